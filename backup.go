@@ -0,0 +1,281 @@
+package pregel
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+// S3API is the part of the S3 client used by backups and snapshots. It's implemented by *s3.S3.
+type S3API interface {
+	PutObjectWithContext(ctx aws.Context, input *s3.PutObjectInput, opts ...request.Option) (*s3.PutObjectOutput, error)
+	GetObjectWithContext(ctx aws.Context, input *s3.GetObjectInput, opts ...request.Option) (*s3.GetObjectOutput, error)
+}
+
+// lazyS3 creates an S3 client from the session the first time it's used, so that Stores which don't
+// use backups or snapshots don't create one.
+type lazyS3 struct {
+	sess   *session.Session
+	once   sync.Once
+	client S3API
+}
+
+func (l *lazyS3) get() S3API {
+	l.once.Do(func() {
+		l.client = s3.New(l.sess)
+	})
+	return l.client
+}
+
+func (l *lazyS3) PutObjectWithContext(ctx aws.Context, input *s3.PutObjectInput, opts ...request.Option) (*s3.PutObjectOutput, error) {
+	return l.get().PutObjectWithContext(ctx, input, opts...)
+}
+
+func (l *lazyS3) GetObjectWithContext(ctx aws.Context, input *s3.GetObjectInput, opts ...request.Option) (*s3.GetObjectOutput, error) {
+	return l.get().GetObjectWithContext(ctx, input, opts...)
+}
+
+// ErrS3NotConfigured is returned when backing up or restoring without setting the Store's S3 client.
+var ErrS3NotConfigured = errors.New("S3 client not configured")
+
+// ErrTableNotEmpty is returned by RestoreFromS3 when the table already contains records.
+var ErrTableNotEmpty = errors.New("cannot restore into a table which isn't empty")
+
+// UnregisteredDataTypesError is returned by RestoreFromS3 when the backup contains data types which
+// haven't been registered with the Store, so the data can't be restored with its original type.
+type UnregisteredDataTypesError struct {
+	DataTypes []string
+}
+
+func (err UnregisteredDataTypesError) Error() string {
+	return fmt.Sprintf("backup contains unregistered data types: %s", strings.Join(err.DataTypes, ", "))
+}
+
+// backupChunkNodes is the maximum number of nodes written to each backup file.
+const backupChunkNodes = 10000
+
+// BackupManifest describes the files in a backup. It's written after all of the files, so a backup
+// without a manifest is incomplete.
+type BackupManifest struct {
+	CreatedAt time.Time `json:"createdAt"`
+	// Nodes is the total number of nodes in the backup.
+	Nodes int `json:"nodes"`
+	// Files are the keys of the gzipped JSON Lines files containing the nodes, relative to the prefix.
+	Files []string `json:"files"`
+	// DataTypes are the names of the data types registered with the Store when the backup was made.
+	DataTypes []string `json:"dataTypes"`
+}
+
+const backupManifestKey = "manifest.json"
+
+// BackupToS3 exports every node in the table to the S3 bucket, under the prefix. The nodes are
+// written as gzipped JSON Lines files of up to 10,000 nodes, then a manifest which lists the files
+// and the data types registered with the Store.
+func (s *Store) BackupToS3(ctx context.Context, bucket, prefix string) (err error) {
+	if s.S3 == nil {
+		return ErrS3NotConfigured
+	}
+	m := BackupManifest{
+		CreatedAt: s.now(),
+	}
+	for name := range s.DataTypes {
+		m.DataTypes = append(m.DataTypes, name)
+	}
+	sort.Strings(m.DataTypes)
+
+	var buf bytes.Buffer
+	var zw *gzip.Writer
+	var enc *json.Encoder
+	var chunkNodes int
+	flush := func() (err error) {
+		if chunkNodes == 0 {
+			return
+		}
+		if err = zw.Close(); err != nil {
+			return
+		}
+		file := fmt.Sprintf("nodes-%05d.jsonl.gz", len(m.Files))
+		if err = s.putObject(ctx, bucket, path.Join(prefix, file), buf.Bytes()); err != nil {
+			return
+		}
+		m.Files = append(m.Files, file)
+		chunkNodes = 0
+		return
+	}
+	err = s.scanNodes(ctx, func(n Node) (err error) {
+		if chunkNodes == 0 {
+			buf.Reset()
+			zw = gzip.NewWriter(&buf)
+			enc = json.NewEncoder(zw)
+		}
+		if err = enc.Encode(n); err != nil {
+			return
+		}
+		chunkNodes++
+		m.Nodes++
+		if chunkNodes == backupChunkNodes {
+			err = flush()
+		}
+		return
+	})
+	if err != nil {
+		return
+	}
+	if err = flush(); err != nil {
+		return
+	}
+	manifest, err := json.Marshal(m)
+	if err != nil {
+		return
+	}
+	return s.putObject(ctx, bucket, path.Join(prefix, backupManifestKey), manifest)
+}
+
+func (s *Store) putObject(ctx context.Context, bucket, key string, body []byte) (err error) {
+	_, err = s.S3.PutObjectWithContext(ctx, &s3.PutObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+		Body:   bytes.NewReader(body),
+	})
+	if err != nil {
+		err = fmt.Errorf("failed to write s3://%s/%s: %v", bucket, key, err)
+	}
+	return
+}
+
+func (s *Store) getObject(ctx context.Context, bucket, key string) (body io.ReadCloser, err error) {
+	return getObject(ctx, s.S3, bucket, key)
+}
+
+func getObject(ctx context.Context, client S3API, bucket, key string) (body io.ReadCloser, err error) {
+	goo, err := client.GetObjectWithContext(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		err = fmt.Errorf("failed to read s3://%s/%s: %v", bucket, key, err)
+		return
+	}
+	body = goo.Body
+	return
+}
+
+// RestoreFromS3 imports a backup made by BackupToS3 into the table, which must be empty. The data
+// types listed in the backup's manifest must be registered with the Store before restoring, so that
+// data is written with its original type.
+func (s *Store) RestoreFromS3(ctx context.Context, bucket, prefix string) (err error) {
+	if s.S3 == nil {
+		return ErrS3NotConfigured
+	}
+	mr, err := s.getObject(ctx, bucket, path.Join(prefix, backupManifestKey))
+	if err != nil {
+		return
+	}
+	defer mr.Close()
+	var m BackupManifest
+	if err = json.NewDecoder(mr).Decode(&m); err != nil {
+		return
+	}
+	var unregistered []string
+	for _, name := range m.DataTypes {
+		if _, ok := s.DataTypes[name]; !ok {
+			unregistered = append(unregistered, name)
+		}
+	}
+	if len(unregistered) > 0 {
+		return UnregisteredDataTypesError{DataTypes: unregistered}
+	}
+	items, _, cc, err := s.Client.ScanSegment(ctx, 0, 1, 1, nil)
+	if err != nil {
+		return
+	}
+	s.updateCapacityStats(cc)
+	if len(items) > 0 {
+		return ErrTableNotEmpty
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	nodes := make(chan Node)
+	readErr := make(chan error, 1)
+	go func() {
+		defer close(nodes)
+		for _, file := range m.Files {
+			if rErr := s.readBackupFile(ctx, bucket, path.Join(prefix, file), nodes); rErr != nil {
+				readErr <- rErr
+				cancel()
+				return
+			}
+		}
+		readErr <- nil
+	}()
+	_, err = s.Import(ctx, nodes, ImportOptions{Snapshot: true})
+	// Import stops reading if it fails, so unblock the reader.
+	cancel()
+	for range nodes {
+	}
+	if rErr := <-readErr; rErr != nil && rErr != context.Canceled {
+		err = rErr
+	}
+	return
+}
+
+// readBackupFile sends each node in the file to the channel.
+func (s *Store) readBackupFile(ctx context.Context, bucket, key string, nodes chan<- Node) (err error) {
+	body, err := s.getObject(ctx, bucket, key)
+	if err != nil {
+		return
+	}
+	defer body.Close()
+	zr, err := gzip.NewReader(body)
+	if err != nil {
+		return
+	}
+	return s.decodeNodes(ctx, zr, nodes)
+}
+
+// convertDecodedNode converts the data of a node decoded from JSON into the registered data types.
+func (s *Store) convertDecodedNode(n *Node) (err error) {
+	if err = s.convertDecodedData(n.Data); err != nil {
+		return
+	}
+	for _, e := range append(n.Children, n.Parents...) {
+		if err = s.convertDecodedData(e.Data); err != nil {
+			return
+		}
+	}
+	return
+}
+
+func (s *Store) convertDecodedData(d Data) (err error) {
+	for name, v := range d {
+		f, ok := s.DataTypes[name]
+		if !ok {
+			continue
+		}
+		var b []byte
+		if b, err = json.Marshal(v); err != nil {
+			return
+		}
+		typed := f()
+		if err = json.Unmarshal(b, typed); err != nil {
+			return
+		}
+		d[name] = typed
+	}
+	return
+}
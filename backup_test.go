@@ -0,0 +1,176 @@
+package pregel
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/a-h/pregel/db"
+	"github.com/a-h/pregel/rangefield"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+type testS3 struct {
+	m       sync.Mutex
+	objects map[string][]byte
+}
+
+func newTestS3() *testS3 {
+	return &testS3{
+		objects: make(map[string][]byte),
+	}
+}
+
+func (ts *testS3) PutObjectWithContext(ctx aws.Context, input *s3.PutObjectInput, opts ...request.Option) (*s3.PutObjectOutput, error) {
+	ts.m.Lock()
+	defer ts.m.Unlock()
+	b, err := ioutil.ReadAll(input.Body)
+	ts.objects[*input.Bucket+"/"+*input.Key] = b
+	return &s3.PutObjectOutput{}, err
+}
+
+func (ts *testS3) GetObjectWithContext(ctx aws.Context, input *s3.GetObjectInput, opts ...request.Option) (*s3.GetObjectOutput, error) {
+	ts.m.Lock()
+	defer ts.m.Unlock()
+	b, ok := ts.objects[*input.Bucket+"/"+*input.Key]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return &s3.GetObjectOutput{Body: ioutil.NopCloser(bytes.NewReader(b))}, nil
+}
+
+func TestStoreBackupAndRestore(t *testing.T) {
+	type location struct {
+		Lat float64 `json:"lat"`
+		Lng float64 `json:"lng"`
+	}
+	dataRecord, err := dynamodbattribute.MarshalMap(location{Lat: 1, Lng: 2})
+	if err != nil {
+		t.Fatalf("failed to marshal data: %v", err)
+	}
+	dataRecord[fieldID] = &dynamodb.AttributeValue{S: aws.String("nodeA")}
+	dataRecord[fieldRange] = &dynamodb.AttributeValue{S: aws.String(rangefield.NodeData{DataType: "location"}.Encode())}
+	dataRecord[fieldRecordDataType] = &dynamodb.AttributeValue{S: aws.String("location")}
+	now := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+	deletedRecord := getID("nodeC", rangefield.Node{})
+	deletedRecord[fieldDeleted] = newTimeAttribute(now)
+	records := []map[string]*dynamodb.AttributeValue{
+		getID("nodeA", rangefield.Child{Child: "nodeB"}),
+		getID("nodeA", rangefield.Node{}),
+		dataRecord,
+		getID("nodeB", rangefield.Node{}),
+		getID("nodeB", rangefield.Parent{Parent: "nodeA"}),
+		deletedRecord,
+	}
+	registerTypes := func(s *Store) {
+		s.RegisterNamedDataType("location", func() interface{} { return &location{} })
+	}
+	objects := newTestS3()
+
+	// Back up the records.
+	source := newdynamoDBClient()
+	source.scanSegmenter = func(segment, totalSegments, limit int64, startKey map[string]*dynamodb.AttributeValue) (items []map[string]*dynamodb.AttributeValue, lastEvaluatedKey map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error) {
+		if segment == 0 {
+			for _, r := range records {
+				// Data records are modified when they're read, so copy them.
+				c := make(map[string]*dynamodb.AttributeValue, len(r))
+				for k, v := range r {
+					c[k] = v
+				}
+				items = append(items, c)
+			}
+		}
+		return
+	}
+	s := NewStoreWithClient(source)
+	s.S3 = objects
+	s.Now = func() time.Time { return now }
+	registerTypes(s)
+	if err := s.BackupToS3(context.Background(), "bucket", "backups/1"); err != nil {
+		t.Fatalf("unexpected error backing up: %v", err)
+	}
+	var m BackupManifest
+	if err := json.Unmarshal(objects.objects["bucket/backups/1/manifest.json"], &m); err != nil {
+		t.Fatalf("failed to read manifest: %v", err)
+	}
+	expectedManifest := BackupManifest{
+		CreatedAt: s.Now(),
+		Nodes:     3,
+		Files:     []string{"nodes-00000.jsonl.gz"},
+		DataTypes: []string{"location"},
+	}
+	if !reflect.DeepEqual(m, expectedManifest) {
+		t.Errorf("expected manifest %+v, got %+v", expectedManifest, m)
+	}
+
+	t.Run("Backups can be restored into an empty table", func(t *testing.T) {
+		var mu sync.Mutex
+		var writes int
+		written := make(map[string]map[string]*dynamodb.AttributeValue)
+		dest := newdynamoDBClient()
+		dest.scanSegmenter = func(segment, totalSegments, limit int64, startKey map[string]*dynamodb.AttributeValue) (items []map[string]*dynamodb.AttributeValue, lastEvaluatedKey map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error) {
+			return
+		}
+		dest.tryBatchPutter = func(items []map[string]*dynamodb.AttributeValue) (unprocessed []map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			for _, itm := range items {
+				written[recordKey(itm)] = itm
+				writes++
+			}
+			return
+		}
+		r := NewStoreWithClient(dest)
+		r.Now = s.Now
+		r.S3 = objects
+		registerTypes(r)
+		if err := r.RestoreFromS3(context.Background(), "bucket", "backups/1"); err != nil {
+			t.Fatalf("unexpected error restoring: %v", err)
+		}
+		for _, expected := range records {
+			actual, ok := written[recordKey(expected)]
+			if !ok {
+				t.Errorf("expected record %s to be restored", recordKey(expected))
+				continue
+			}
+			if !reflect.DeepEqual(actual, expected) {
+				t.Errorf("\nexpected:\n%s\n\ngot:\n%s\n", format([]map[string]*dynamodb.AttributeValue{expected}), format([]map[string]*dynamodb.AttributeValue{actual}))
+			}
+		}
+		if writes != len(records) {
+			t.Errorf("expected each of the %d records to be written once, got %d writes", len(records), writes)
+		}
+	})
+	t.Run("Backups can't be restored into a table which isn't empty", func(t *testing.T) {
+		r := NewStoreWithClient(source)
+		r.S3 = objects
+		registerTypes(r)
+		if err := r.RestoreFromS3(context.Background(), "bucket", "backups/1"); err != ErrTableNotEmpty {
+			t.Errorf("expected err %v, got %v", ErrTableNotEmpty, err)
+		}
+	})
+	t.Run("Data types must be registered before restoring", func(t *testing.T) {
+		r := NewStoreWithClient(newdynamoDBClient())
+		r.S3 = objects
+		expected := UnregisteredDataTypesError{DataTypes: []string{"location"}}
+		if err := r.RestoreFromS3(context.Background(), "bucket", "backups/1"); !reflect.DeepEqual(err, expected) {
+			t.Errorf("expected err %v, got %v", expected, err)
+		}
+	})
+	t.Run("An S3 client is required", func(t *testing.T) {
+		r := NewStoreWithClient(newdynamoDBClient())
+		if err := r.BackupToS3(context.Background(), "bucket", "backups/2"); err != ErrS3NotConfigured {
+			t.Errorf("expected err %v, got %v", ErrS3NotConfigured, err)
+		}
+	})
+}
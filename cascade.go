@@ -0,0 +1,80 @@
+package pregel
+
+// DefaultCascadeMaxDepth is the maximum number of levels of descendants removed by DeleteCascade
+// when CascadeOptions.MaxDepth isn't set.
+const DefaultCascadeMaxDepth = 10
+
+// CascadeOptions control how far DeleteCascade walks down the graph.
+type CascadeOptions struct {
+	// MaxDepth is the maximum number of levels of descendants to delete. Descendants below this depth
+	// are left in place, even if they're orphaned. If zero, DefaultCascadeMaxDepth is used.
+	MaxDepth int
+}
+
+// DeleteCascade deletes a node, then deletes any of its descendants which are left without a parent
+// as a result. Descendants which still have another parent are kept. As with Delete, if SoftDelete
+// is enabled, the nodes are marked as deleted instead of being removed.
+func (s *Store) DeleteCascade(id string, opts CascadeOptions) (err error) {
+	if id == "" {
+		return ErrMissingNodeID
+	}
+	if opts.MaxDepth <= 0 {
+		opts.MaxDepth = DefaultCascadeMaxDepth
+	}
+	return s.deleteCascade(id, 0, opts.MaxDepth, make(map[string]bool))
+}
+
+func (s *Store) deleteCascade(id string, depth, maxDepth int, deleted map[string]bool) (err error) {
+	n, ranges, ok, cc, err := s.getWithRanges(id, allRecords.includingDeleted().keys())
+	s.updateCapacityStats(cc)
+	if err != nil {
+		return
+	}
+	if !ok {
+		return
+	}
+	if s.SoftDelete {
+		err = s.setDeleted(id, true)
+	} else {
+		err = s.deleteNodeRecords(id, ranges)
+	}
+	if err != nil {
+		return
+	}
+	deleted[id] = true
+	if depth >= maxDepth {
+		return
+	}
+	for _, e := range n.Children {
+		if deleted[e.ID] {
+			continue
+		}
+		var orphaned bool
+		orphaned, err = s.isOrphaned(e.ID, deleted)
+		if err != nil {
+			return
+		}
+		if !orphaned {
+			continue
+		}
+		if err = s.deleteCascade(e.ID, depth+1, maxDepth, deleted); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// isOrphaned returns true if all of the node's parents have been deleted.
+func (s *Store) isOrphaned(id string, deleted map[string]bool) (orphaned bool, err error) {
+	n, ok, err := s.GetWithOptions(id, GetOptions{IncludeDeleted: true, Parents: true})
+	if err != nil || !ok {
+		return
+	}
+	for _, p := range n.Parents {
+		if !deleted[p.ID] {
+			return
+		}
+	}
+	orphaned = true
+	return
+}
@@ -0,0 +1,113 @@
+package pregel
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/a-h/pregel/db"
+	"github.com/a-h/pregel/rangefield"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+func TestStoreDeleteCascade(t *testing.T) {
+	// root has children a and b. a has a child a1. b also has another parent, c.
+	graph := map[string][]string{
+		"root": {"a", "b"},
+		"a":    {"a1"},
+		"c":    {"b"},
+	}
+	tests := []struct {
+		name            string
+		id              string
+		opts            CascadeOptions
+		expectedDeleted []string
+		expectedErr     error
+	}{
+		{
+			name:        "Missing node IDs result in an error",
+			expectedErr: ErrMissingNodeID,
+		},
+		{
+			name:            "Orphaned descendants are deleted, but nodes with other parents are kept",
+			id:              "root",
+			expectedDeleted: []string{"a", "a1", "root"},
+		},
+		{
+			name:            "Descendants below the maximum depth are kept",
+			id:              "root",
+			opts:            CascadeOptions{MaxDepth: 1},
+			expectedDeleted: []string{"a", "root"},
+		},
+		{
+			name:            "Leaf nodes are deleted on their own",
+			id:              "a1",
+			expectedDeleted: []string{"a1"},
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			records := make(map[string][]map[string]*dynamodb.AttributeValue)
+			for parent, children := range graph {
+				records[parent] = append(records[parent], getID(parent, rangefield.Node{}))
+				for _, child := range children {
+					records[parent] = append(records[parent], getID(parent, rangefield.Child{Child: child}))
+					records[child] = append(records[child], getID(child, rangefield.Parent{Parent: parent}))
+				}
+			}
+			records["a1"] = append(records["a1"], getID("a1", rangefield.Node{}))
+			records["b"] = append(records["b"], getID("b", rangefield.Node{}))
+
+			client := newdynamoDBClient()
+			client.queryByIDer = func(idField, idValue string) ([]map[string]*dynamodb.AttributeValue, db.ConsumedCapacity, error) {
+				return records[idValue], db.ConsumedCapacity{}, nil
+			}
+			client.getItemer = func(key map[string]*dynamodb.AttributeValue) (map[string]*dynamodb.AttributeValue, db.ConsumedCapacity, error) {
+				for _, r := range records[*key[fieldID].S] {
+					if *r[fieldRange].S == *key[fieldRange].S {
+						return r, db.ConsumedCapacity{}, nil
+					}
+				}
+				return nil, db.ConsumedCapacity{}, nil
+			}
+			client.queryPager = func(idField, idValue, rangeField, rangePrefix string, limit int64, startKey map[string]*dynamodb.AttributeValue) (items []map[string]*dynamodb.AttributeValue, lastEvaluatedKey map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error) {
+				for _, r := range records[idValue] {
+					if strings.HasPrefix(*r[fieldRange].S, rangePrefix) {
+						items = append(items, r)
+					}
+				}
+				return
+			}
+			var actualDeleted []string
+			client.batchDeleter = func(keys []map[string]*dynamodb.AttributeValue) (db.ConsumedCapacity, error) {
+				for _, k := range keys {
+					id, rng := *k[fieldID].S, *k[fieldRange].S
+					if rng == rangefield.NodePrefix {
+						actualDeleted = append(actualDeleted, id)
+					}
+					var remaining []map[string]*dynamodb.AttributeValue
+					for _, r := range records[id] {
+						if *r[fieldRange].S != rng {
+							remaining = append(remaining, r)
+						}
+					}
+					records[id] = remaining
+				}
+				return db.ConsumedCapacity{}, nil
+			}
+			s := NewStoreWithClient(client)
+			err := s.DeleteCascade(test.id, test.opts)
+			if err != test.expectedErr {
+				t.Errorf("expected err %v, got %v", test.expectedErr, err)
+			}
+			sort.Strings(actualDeleted)
+			if !reflect.DeepEqual(actualDeleted, test.expectedDeleted) {
+				t.Errorf("expected %v to be deleted, got %v", test.expectedDeleted, actualDeleted)
+			}
+		})
+	}
+}
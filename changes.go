@@ -0,0 +1,318 @@
+package pregel
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/a-h/pregel/db"
+	"github.com/a-h/pregel/rangefield"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+)
+
+// ChangeType is the kind of change made to the graph.
+type ChangeType string
+
+// Changes reported in ChangeEvents.
+const (
+	NodeCreated  ChangeType = "NodeCreated"
+	NodeUpdated  ChangeType = "NodeUpdated"
+	NodeDeleted  ChangeType = "NodeDeleted"
+	LabelAdded   ChangeType = "LabelAdded"
+	LabelRemoved ChangeType = "LabelRemoved"
+	EdgeCreated  ChangeType = "EdgeCreated"
+	EdgeUpdated  ChangeType = "EdgeUpdated"
+	EdgeDeleted  ChangeType = "EdgeDeleted"
+	DataCreated  ChangeType = "DataCreated"
+	DataUpdated  ChangeType = "DataUpdated"
+	DataDeleted  ChangeType = "DataDeleted"
+)
+
+// ChangeEvent describes a change to a node, one of its labels, an edge, or data. The same events are
+// emitted by a Store's EmitChanges hook and by the stream package, so that consumers don't depend on
+// where the change was observed.
+//
+// Each edge is stored on both of its nodes, but is only reported once, with the parent as the NodeID.
+type ChangeEvent struct {
+	Type ChangeType
+	// NodeID is the node which changed, or the parent node of a changed edge.
+	NodeID string
+	// Child is the child node of a changed edge, or of an edge whose data changed.
+	Child string
+	// Label is the label of the edge, or the node label which was added or removed.
+	Label string
+	// DataType is the name of the data type of changed data.
+	DataType string
+	// Old and New are the data before and after the change. The Store doesn't read records before
+	// writing them, so the events it emits don't include Old data, and report updates of data
+	// attributes with just the updated attributes.
+	Old  map[string]interface{}
+	New  map[string]interface{}
+	Time time.Time
+}
+
+// RecordOperation is the operation which changed a record.
+type RecordOperation int
+
+// Operations which change records.
+const (
+	// RecordInserted is a write of a record which didn't exist.
+	RecordInserted RecordOperation = iota
+	// RecordModified is a write of a record which may already exist.
+	RecordModified
+	// RecordRemoved is the deletion of a record.
+	RecordRemoved
+)
+
+var changeTypes = map[string][3]ChangeType{
+	"node":  {NodeCreated, NodeUpdated, NodeDeleted},
+	"label": {LabelAdded, LabelAdded, LabelRemoved},
+	"edge":  {EdgeCreated, EdgeUpdated, EdgeDeleted},
+	"data":  {DataCreated, DataUpdated, DataDeleted},
+}
+
+// NewChangeEvent decodes a change to a record, identified by its key, into a ChangeEvent. oldImage and
+// newImage are the record before and after the change, and may be nil. If the record isn't a pregel
+// record, or is the parent side of an edge, ok is false. A node record written with a soft delete
+// tombstone is reported as NodeDeleted.
+func NewChangeEvent(op RecordOperation, key, oldImage, newImage map[string]*dynamodb.AttributeValue, t time.Time) (e ChangeEvent, ok bool, err error) {
+	id, rng := key[fieldID], key[fieldRange]
+	if id == nil || id.S == nil || rng == nil || rng.S == nil {
+		return
+	}
+	f, isPregelRecord := rangefield.Decode(*rng.S)
+	if !isPregelRecord {
+		return
+	}
+	e = ChangeEvent{NodeID: *id.S, Time: t}
+	var kind string
+	switch rf := f.(type) {
+	case rangefield.Node:
+		kind = "node"
+		if _, deleted := newImage[fieldDeleted]; deleted {
+			op = RecordRemoved
+		}
+	case rangefield.NodeLabel:
+		kind, e.Label = "label", rf.Label
+	case rangefield.Child:
+		kind, e.Child, e.Label = "edge", rf.Child, rf.Label
+	case rangefield.NodeData:
+		kind, e.DataType = "data", rf.DataType
+	case rangefield.ChildData:
+		kind, e.Child, e.Label, e.DataType = "data", rf.Child, rf.Label, rf.DataType
+	default:
+		return
+	}
+	e.Type = changeTypes[kind][op]
+	if kind == "data" {
+		if e.Old, err = decodeChangedData(oldImage); err != nil {
+			return
+		}
+		if e.New, err = decodeChangedData(newImage); err != nil {
+			return
+		}
+	}
+	ok = true
+	return
+}
+
+// decodeChangedData unmarshals the data attributes of a data record.
+func decodeChangedData(itm map[string]*dynamodb.AttributeValue) (data map[string]interface{}, err error) {
+	if itm == nil {
+		return
+	}
+	attributes := make(map[string]*dynamodb.AttributeValue, len(itm))
+	for k, v := range itm {
+		switch k {
+		case fieldID, fieldRange, fieldRecordDataType, fieldCreatedAt, fieldUpdatedAt, TTLAttributeName:
+			continue
+		}
+		attributes[k] = v
+	}
+	if err = DecompressRecord(attributes); err != nil {
+		return
+	}
+	data = make(map[string]interface{})
+	err = dynamodbattribute.UnmarshalMap(attributes, &data)
+	return
+}
+
+// ChangeHandler receives ChangeEvents.
+type ChangeHandler interface {
+	HandleChange(ctx context.Context, e ChangeEvent) error
+}
+
+// ChangeHandlerFunc allows a function to be used as a ChangeHandler.
+type ChangeHandlerFunc func(ctx context.Context, e ChangeEvent) error
+
+// HandleChange calls the function.
+func (f ChangeHandlerFunc) HandleChange(ctx context.Context, e ChangeEvent) error {
+	return f(ctx, e)
+}
+
+// Dispatcher passes ChangeEvents to its subscribed handlers. It's safe for concurrent use.
+type Dispatcher struct {
+	m        sync.RWMutex
+	handlers []ChangeHandler
+}
+
+// NewDispatcher creates a Dispatcher without any handlers.
+func NewDispatcher() *Dispatcher {
+	return &Dispatcher{}
+}
+
+// Subscribe adds a handler, which receives all of the events dispatched after it's added.
+func (d *Dispatcher) Subscribe(h ChangeHandler) {
+	d.m.Lock()
+	defer d.m.Unlock()
+	d.handlers = append(d.handlers, h)
+}
+
+// Dispatch passes each event to each handler, in order. Dispatch stops at the first error.
+func (d *Dispatcher) Dispatch(ctx context.Context, events ...ChangeEvent) (err error) {
+	d.m.RLock()
+	handlers := d.handlers
+	d.m.RUnlock()
+	for _, e := range events {
+		for _, h := range handlers {
+			if err = h.HandleChange(ctx, e); err != nil {
+				return
+			}
+		}
+	}
+	return
+}
+
+// EmitChanges makes the Store dispatch a ChangeEvent for each record it writes or deletes, once the
+// write has been made. If a handler returns an error, it's returned by the Store method which made
+// the write, even though the write was successful.
+func (s *Store) EmitChanges(d *Dispatcher) {
+	s.Client = &changesDB{
+		DB:         s.Client,
+		dispatcher: d,
+		now:        s.now,
+	}
+}
+
+// changesDB dispatches ChangeEvents for the writes made to the underlying DB. Reads are passed
+// through by the embedded DB.
+type changesDB struct {
+	DB
+	dispatcher *Dispatcher
+	now        func() time.Time
+}
+
+type recordChange struct {
+	op       RecordOperation
+	key, itm map[string]*dynamodb.AttributeValue
+}
+
+func recordChanges(op RecordOperation, items []map[string]*dynamodb.AttributeValue) (changes []recordChange) {
+	for _, itm := range items {
+		changes = append(changes, recordChange{op: op, key: itm, itm: itm})
+	}
+	return
+}
+
+func (cdb *changesDB) dispatch(ctx context.Context, changes []recordChange) (err error) {
+	t := cdb.now()
+	var events []ChangeEvent
+	for _, c := range changes {
+		var newImage map[string]*dynamodb.AttributeValue
+		if c.op != RecordRemoved {
+			newImage = c.itm
+		}
+		e, ok, eErr := NewChangeEvent(c.op, c.key, nil, newImage, t)
+		if eErr != nil {
+			return eErr
+		}
+		if ok {
+			events = append(events, e)
+		}
+	}
+	return cdb.dispatcher.Dispatch(ctx, events...)
+}
+
+func (cdb *changesDB) BatchDelete(ctx context.Context, keys []map[string]*dynamodb.AttributeValue) (cc db.ConsumedCapacity, err error) {
+	if cc, err = cdb.DB.BatchDelete(ctx, keys); err != nil {
+		return
+	}
+	err = cdb.dispatch(ctx, recordChanges(RecordRemoved, keys))
+	return
+}
+
+func (cdb *changesDB) BatchPut(ctx context.Context, items []map[string]*dynamodb.AttributeValue) (cc db.ConsumedCapacity, err error) {
+	if cc, err = cdb.DB.BatchPut(ctx, items); err != nil {
+		return
+	}
+	err = cdb.dispatch(ctx, recordChanges(RecordModified, items))
+	return
+}
+
+func (cdb *changesDB) TryBatchPut(ctx context.Context, items []map[string]*dynamodb.AttributeValue) (unprocessed []map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error) {
+	if unprocessed, cc, err = cdb.DB.TryBatchPut(ctx, items); err != nil {
+		return
+	}
+	isUnprocessed := make(map[string]bool, len(unprocessed))
+	for _, u := range unprocessed {
+		isUnprocessed[recordKey(u)] = true
+	}
+	var processed []map[string]*dynamodb.AttributeValue
+	for _, itm := range items {
+		if !isUnprocessed[recordKey(itm)] {
+			processed = append(processed, itm)
+		}
+	}
+	err = cdb.dispatch(ctx, recordChanges(RecordModified, processed))
+	return
+}
+
+func (cdb *changesDB) PutIfNotExists(ctx context.Context, idField string, item map[string]*dynamodb.AttributeValue) (cc db.ConsumedCapacity, err error) {
+	if cc, err = cdb.DB.PutIfNotExists(ctx, idField, item); err != nil {
+		return
+	}
+	err = cdb.dispatch(ctx, recordChanges(RecordInserted, []map[string]*dynamodb.AttributeValue{item}))
+	return
+}
+
+func (cdb *changesDB) TransactPut(ctx context.Context, items []map[string]*dynamodb.AttributeValue) (cc db.ConsumedCapacity, err error) {
+	if cc, err = cdb.DB.TransactPut(ctx, items); err != nil {
+		return
+	}
+	err = cdb.dispatch(ctx, recordChanges(RecordModified, items))
+	return
+}
+
+func (cdb *changesDB) TransactWrite(ctx context.Context, items []db.TransactWriteItem) (cc db.ConsumedCapacity, err error) {
+	if cc, err = cdb.DB.TransactWrite(ctx, items); err != nil {
+		return
+	}
+	var changes []recordChange
+	for _, item := range items {
+		if item.Put != nil {
+			changes = append(changes, recordChanges(RecordModified, []map[string]*dynamodb.AttributeValue{item.Put})...)
+		}
+		if item.Delete != nil {
+			changes = append(changes, recordChanges(RecordRemoved, []map[string]*dynamodb.AttributeValue{item.Delete})...)
+		}
+	}
+	err = cdb.dispatch(ctx, changes)
+	return
+}
+
+func (cdb *changesDB) TransactPutIfExists(ctx context.Context, key map[string]*dynamodb.AttributeValue, idField, excludedField string, items []map[string]*dynamodb.AttributeValue) (cc db.ConsumedCapacity, err error) {
+	if cc, err = cdb.DB.TransactPutIfExists(ctx, key, idField, excludedField, items); err != nil {
+		return
+	}
+	err = cdb.dispatch(ctx, recordChanges(RecordModified, items))
+	return
+}
+
+func (cdb *changesDB) UpdateItem(ctx context.Context, key map[string]*dynamodb.AttributeValue, set map[string]*dynamodb.AttributeValue) (cc db.ConsumedCapacity, err error) {
+	if cc, err = cdb.DB.UpdateItem(ctx, key, set); err != nil {
+		return
+	}
+	err = cdb.dispatch(ctx, []recordChange{{op: RecordModified, key: key, itm: set}})
+	return
+}
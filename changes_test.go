@@ -0,0 +1,110 @@
+package pregel
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/a-h/pregel/db"
+	"github.com/a-h/pregel/rangefield"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+func TestStoreEmitChanges(t *testing.T) {
+	now := time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		change   func(s *Store) error
+		expected []ChangeEvent
+	}{
+		{
+			name: "Puts are reported as updates, without the parent side of edges",
+			change: func(s *Store) error {
+				return s.Put(NewNode("a").
+					WithData(testNodeData{ExtraAttribute: "value"}).
+					WithChildren(NewEdge("b")))
+			},
+			expected: []ChangeEvent{
+				{Type: NodeUpdated, NodeID: "a", Time: now},
+				{Type: DataUpdated, NodeID: "a", DataType: "testNodeData", New: map[string]interface{}{"extra": "value"}, Time: now},
+				{Type: EdgeUpdated, NodeID: "a", Child: "b", Time: now},
+			},
+		},
+		{
+			name: "Created nodes are reported",
+			change: func(s *Store) error {
+				return s.Create(NewNode("a"))
+			},
+			expected: []ChangeEvent{
+				{Type: NodeCreated, NodeID: "a", Time: now},
+			},
+		},
+		{
+			name: "Deleted records are reported",
+			change: func(s *Store) error {
+				return s.DeleteEdge("a", "b")
+			},
+			expected: []ChangeEvent{
+				{Type: EdgeDeleted, NodeID: "a", Child: "b", Time: now},
+			},
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			client := newdynamoDBClient()
+			client.batchPutter = func(items []map[string]*dynamodb.AttributeValue) (db.ConsumedCapacity, error) {
+				return db.ConsumedCapacity{}, nil
+			}
+			client.putIfNotExister = func(idField string, item map[string]*dynamodb.AttributeValue) (db.ConsumedCapacity, error) {
+				return db.ConsumedCapacity{}, nil
+			}
+			client.queryByIDer = func(idField, idValue string) ([]map[string]*dynamodb.AttributeValue, db.ConsumedCapacity, error) {
+				return []map[string]*dynamodb.AttributeValue{
+					newNodeRecord("a"),
+					newRecord("a", rangefield.Child{Child: "b"}),
+				}, db.ConsumedCapacity{}, nil
+			}
+			client.batchDeleter = func(keys []map[string]*dynamodb.AttributeValue) (db.ConsumedCapacity, error) {
+				return db.ConsumedCapacity{}, nil
+			}
+			s := NewStoreWithClient(client)
+			s.RegisterDataType(func() interface{} { return &testNodeData{} })
+			s.Now = func() time.Time { return now }
+			d := NewDispatcher()
+			var actual []ChangeEvent
+			d.Subscribe(ChangeHandlerFunc(func(ctx context.Context, e ChangeEvent) error {
+				actual = append(actual, e)
+				return nil
+			}))
+			s.EmitChanges(d)
+			if err := test.change(s); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(actual, test.expected) {
+				t.Errorf("expected:\n%+v\ngot:\n%+v", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestDispatcherStopsAtTheFirstError(t *testing.T) {
+	handlerErr := errors.New("handler failed")
+	var calls int
+	d := NewDispatcher()
+	d.Subscribe(ChangeHandlerFunc(func(ctx context.Context, e ChangeEvent) error {
+		calls++
+		return handlerErr
+	}))
+	err := d.Dispatch(context.Background(), ChangeEvent{Type: NodeCreated}, ChangeEvent{Type: NodeDeleted})
+	if err != handlerErr {
+		t.Errorf("expected err %v, got %v", handlerErr, err)
+	}
+	if calls != 1 {
+		t.Errorf("expected 1 call, got %d", calls)
+	}
+}
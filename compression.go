@@ -0,0 +1,208 @@
+package pregel
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sync"
+
+	"github.com/a-h/pregel/db"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+const (
+	// fieldCompressed holds the compressed data attributes of a data record.
+	fieldCompressed = "_z"
+	// fieldCodec holds the name of the codec used to compress the data attributes.
+	fieldCodec = "_zc"
+)
+
+// DefaultCompressionThreshold is the size, in bytes, of a data record's data above which it's
+// compressed, when a Compression doesn't set a Threshold.
+const DefaultCompressionThreshold = 4096
+
+// Codec compresses and decompresses the data of data records. Codecs other than Gzip, e.g. zstd,
+// must be registered with RegisterCodec so that records written with them can be read.
+type Codec interface {
+	// Name is stored alongside the compressed data to identify the codec used to write it.
+	Name() string
+	Compress(data []byte) ([]byte, error)
+	Decompress(data []byte) ([]byte, error)
+}
+
+// Gzip compresses data using gzip. It's always registered.
+var Gzip Codec = gzipCodec{}
+
+type gzipCodec struct{}
+
+func (gzipCodec) Name() string { return "gzip" }
+
+func (gzipCodec) Compress(data []byte) (compressed []byte, err error) {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err = w.Write(data); err != nil {
+		return
+	}
+	if err = w.Close(); err != nil {
+		return
+	}
+	compressed = buf.Bytes()
+	return
+}
+
+func (gzipCodec) Decompress(data []byte) (decompressed []byte, err error) {
+	r, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return
+	}
+	defer r.Close()
+	return ioutil.ReadAll(r)
+}
+
+var codecsMutex sync.RWMutex
+var codecs = map[string]Codec{
+	Gzip.Name(): Gzip,
+}
+
+// RegisterCodec makes a codec available for reading compressed records, by its name.
+func RegisterCodec(c Codec) {
+	codecsMutex.Lock()
+	defer codecsMutex.Unlock()
+	codecs[c.Name()] = c
+}
+
+func getCodec(name string) (c Codec, ok bool) {
+	codecsMutex.RLock()
+	defer codecsMutex.RUnlock()
+	c, ok = codecs[name]
+	return
+}
+
+// UnknownCodecError is returned when reading a record which was compressed with a codec that hasn't
+// been registered.
+type UnknownCodecError struct {
+	Name string
+}
+
+func (err UnknownCodecError) Error() string {
+	return fmt.Sprintf("unknown compression codec %q, codecs must be registered with RegisterCodec", err.Name)
+}
+
+// Compression configures the compression of data records. The data attributes of data records
+// which are larger than the Threshold are compressed into a single binary attribute, which reduces
+// the capacity used to read and write them, and keeps them within DynamoDB's item size limit.
+// Attributes which are registered with RegisterIndex aren't compressed, so that they can be queried.
+type Compression struct {
+	// Codec used to compress records. If nil, Gzip is used.
+	Codec Codec
+	// Threshold is the size in bytes above which data is compressed. If zero,
+	// DefaultCompressionThreshold is used.
+	Threshold int
+}
+
+func (c *Compression) codec() Codec {
+	if c.Codec == nil {
+		return Gzip
+	}
+	return c.Codec
+}
+
+func (c *Compression) threshold() int {
+	if c.Threshold == 0 {
+		return DefaultCompressionThreshold
+	}
+	return c.Threshold
+}
+
+// encodeRecords compresses the data attributes of data records which are larger than the
+// threshold, if compression is enabled, then checks that each record is within DynamoDB's item size
+// limit, so that oversized records are rejected before anything is written.
+func (s *Store) encodeRecords(records []map[string]*dynamodb.AttributeValue) (err error) {
+	for _, r := range records {
+		if s.Compression != nil {
+			if err = s.compressRecord(r); err != nil {
+				return
+			}
+		}
+		if size := db.ItemSize(r); size > db.MaxItemSize {
+			return ItemTooLargeError{ID: *r[fieldID].S, Range: *r[fieldRange].S, Size: size}
+		}
+	}
+	return
+}
+
+func (s *Store) compressRecord(r map[string]*dynamodb.AttributeValue) (err error) {
+	dataType, ok := r[fieldRecordDataType]
+	if !ok || dataType.S == nil {
+		return
+	}
+	data := make(map[string]*dynamodb.AttributeValue, len(r))
+	for k, v := range r {
+		if reservedAttributes[k] {
+			continue
+		}
+		if _, isIndexed := s.Indexes[AttributeIndexKey{DataType: *dataType.S, Field: k}]; isIndexed {
+			continue
+		}
+		data[k] = v
+	}
+	if db.ItemSize(data) <= s.Compression.threshold() {
+		return
+	}
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return
+	}
+	codec := s.Compression.codec()
+	compressed, err := codec.Compress(encoded)
+	if err != nil {
+		err = fmt.Errorf("failed to compress data using %q: %v", codec.Name(), err)
+		return
+	}
+	for k := range data {
+		delete(r, k)
+	}
+	r[fieldCompressed] = &dynamodb.AttributeValue{B: compressed}
+	r[fieldCodec] = &dynamodb.AttributeValue{S: aws.String(codec.Name())}
+	return
+}
+
+// DecompressRecord restores the data attributes of a data record which was compressed by a Store
+// using Compression. Records which aren't compressed are left unchanged. Attributes which were
+// updated after the record was compressed, e.g. by UpdateNodeData, take precedence over the
+// compressed attributes.
+func DecompressRecord(itm map[string]*dynamodb.AttributeValue) (err error) {
+	compressed, ok := itm[fieldCompressed]
+	if !ok {
+		return
+	}
+	var name string
+	if n, ok := itm[fieldCodec]; ok && n.S != nil {
+		name = *n.S
+	}
+	codec, ok := getCodec(name)
+	if !ok {
+		return UnknownCodecError{Name: name}
+	}
+	encoded, err := codec.Decompress(compressed.B)
+	if err != nil {
+		err = fmt.Errorf("failed to decompress data using %q: %v", name, err)
+		return
+	}
+	var data map[string]*dynamodb.AttributeValue
+	if err = json.Unmarshal(encoded, &data); err != nil {
+		return
+	}
+	delete(itm, fieldCompressed)
+	delete(itm, fieldCodec)
+	for k, v := range data {
+		if _, updated := itm[k]; updated {
+			continue
+		}
+		itm[k] = v
+	}
+	return
+}
@@ -0,0 +1,132 @@
+package pregel
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/a-h/pregel/db"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+type largeTestData struct {
+	Body  string `json:"body"`
+	Owner string `json:"owner"`
+}
+
+func TestStoreCompression(t *testing.T) {
+	tests := []struct {
+		name               string
+		data               largeTestData
+		update             map[string]*dynamodb.AttributeValue
+		expectedCompressed bool
+		expectedData       largeTestData
+	}{
+		{
+			name:         "Data below the threshold isn't compressed",
+			data:         largeTestData{Body: "small", Owner: "a"},
+			expectedData: largeTestData{Body: "small", Owner: "a"},
+		},
+		{
+			name:               "Data above the threshold is compressed, and decompressed when it's read",
+			data:               largeTestData{Body: strings.Repeat("large", 100), Owner: "a"},
+			expectedCompressed: true,
+			expectedData:       largeTestData{Body: strings.Repeat("large", 100), Owner: "a"},
+		},
+		{
+			name:               "Attributes updated after compression take precedence",
+			data:               largeTestData{Body: strings.Repeat("large", 100), Owner: "a"},
+			update:             map[string]*dynamodb.AttributeValue{"body": {S: aws.String("updated")}},
+			expectedCompressed: true,
+			expectedData:       largeTestData{Body: "updated", Owner: "a"},
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			var written []map[string]*dynamodb.AttributeValue
+			client := newdynamoDBClient()
+			client.batchPutter = func(items []map[string]*dynamodb.AttributeValue) (db.ConsumedCapacity, error) {
+				written = append(written, items...)
+				return db.ConsumedCapacity{}, nil
+			}
+			client.queryByIDer = func(idField, idValue string) ([]map[string]*dynamodb.AttributeValue, db.ConsumedCapacity, error) {
+				return written, db.ConsumedCapacity{}, nil
+			}
+			s := NewStoreWithClient(client)
+			s.Compression = &Compression{Threshold: 100}
+			s.RegisterDataType(func() interface{} { return &largeTestData{} })
+			s.RegisterIndex("largeTestData", "owner", "owners")
+
+			if err := s.Put(NewNode("a").WithData(test.data)); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var dataRecord map[string]*dynamodb.AttributeValue
+			for _, r := range written {
+				if _, ok := r[fieldRecordDataType]; ok {
+					dataRecord = r
+				}
+			}
+			_, compressed := dataRecord[fieldCompressed]
+			if compressed != test.expectedCompressed {
+				t.Errorf("expected compressed to be %v, got %v", test.expectedCompressed, compressed)
+			}
+			if _, ok := dataRecord["owner"]; !ok {
+				t.Errorf("expected the indexed attribute not to be compressed")
+			}
+			for k, v := range test.update {
+				dataRecord[k] = v
+			}
+
+			n, ok, err := s.Get("a")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !ok {
+				t.Fatalf("expected the node to be found")
+			}
+			if actual := n.Data["largeTestData"]; !reflect.DeepEqual(actual, &test.expectedData) {
+				t.Errorf("expected data %+v, got %+v", test.expectedData, actual)
+			}
+		})
+	}
+}
+
+func TestDecompressRecordWithUnknownCodec(t *testing.T) {
+	itm := map[string]*dynamodb.AttributeValue{
+		fieldCompressed: {B: []byte{}},
+		fieldCodec:      {S: aws.String("unknown")},
+	}
+	err := DecompressRecord(itm)
+	if err != (UnknownCodecError{Name: "unknown"}) {
+		t.Errorf("expected an UnknownCodecError, got %v", err)
+	}
+}
+
+func TestStoreRejectsRecordsLargerThanTheItemSizeLimit(t *testing.T) {
+	client := newdynamoDBClient()
+	client.batchPutter = func(items []map[string]*dynamodb.AttributeValue) (db.ConsumedCapacity, error) {
+		t.Errorf("expected nothing to be written")
+		return db.ConsumedCapacity{}, nil
+	}
+	s := NewStoreWithClient(client)
+	err := s.Put(NewNode("a").WithData(largeTestData{Body: strings.Repeat("x", db.MaxItemSize)}))
+	if !errors.Is(err, ErrItemTooLarge) {
+		t.Fatalf("expected ErrItemTooLarge, got %v", err)
+	}
+	if tooLarge := err.(ItemTooLargeError); tooLarge.ID != "a" || tooLarge.Range != "node/data/largeTestData" {
+		t.Errorf("expected the key of the data record, got %s/%s", tooLarge.ID, tooLarge.Range)
+	}
+
+	s.Compression = &Compression{}
+	client.batchPutter = func(items []map[string]*dynamodb.AttributeValue) (db.ConsumedCapacity, error) {
+		return db.ConsumedCapacity{}, nil
+	}
+	if err = s.Put(NewNode("a").WithData(largeTestData{Body: strings.Repeat("x", db.MaxItemSize)})); err != nil {
+		t.Errorf("expected compressed data to be within the limit, got %v", err)
+	}
+}
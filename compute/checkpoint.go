@@ -0,0 +1,222 @@
+package compute
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"sync"
+
+	"github.com/a-h/pregel"
+)
+
+// Checkpoint is the state of a computation at the start of a superstep.
+type Checkpoint struct {
+	// Superstep is the number of the next superstep to run.
+	Superstep int
+	// Values of each vertex, keyed by node ID.
+	Values map[string]interface{}
+	// Halted is true for each vertex which has voted to halt.
+	Halted map[string]bool
+	// Messages to deliver to each vertex in the superstep.
+	Messages map[string][]interface{}
+}
+
+// converged returns true if every vertex has halted, and there are no messages to deliver. Vertices
+// which haven't run yet aren't halted, so a computation which hasn't started hasn't converged.
+func (c Checkpoint) converged() bool {
+	if c.Superstep == 0 || len(c.Messages) > 0 {
+		return false
+	}
+	for _, halted := range c.Halted {
+		if !halted {
+			return false
+		}
+	}
+	return true
+}
+
+// Checkpointer saves and loads the state of a computation, so that it can be resumed after a failure.
+type Checkpointer interface {
+	// Save the checkpoint, replacing any previous checkpoint.
+	Save(ctx context.Context, c Checkpoint) error
+	// Load the most recently saved checkpoint. If there isn't one, ok is false.
+	Load(ctx context.Context) (c Checkpoint, ok bool, err error)
+}
+
+// MemoryCheckpointer keeps the most recent checkpoint in memory, so checkpoints only survive within
+// the process. Use a StoreCheckpointer to resume a computation in another process.
+type MemoryCheckpointer struct {
+	m          sync.Mutex
+	checkpoint *Checkpoint
+}
+
+// Save the checkpoint.
+func (mc *MemoryCheckpointer) Save(ctx context.Context, c Checkpoint) error {
+	mc.m.Lock()
+	defer mc.m.Unlock()
+	mc.checkpoint = &c
+	return nil
+}
+
+// Load the most recently saved checkpoint.
+func (mc *MemoryCheckpointer) Load(ctx context.Context) (c Checkpoint, ok bool, err error) {
+	mc.m.Lock()
+	defer mc.m.Unlock()
+	if mc.checkpoint == nil {
+		return
+	}
+	return *mc.checkpoint, true, nil
+}
+
+// Codec converts the values or messages of vertices to and from bytes, so that checkpoints can be
+// saved outside of the process.
+type Codec interface {
+	Encode(v interface{}) (data []byte, err error)
+	Decode(data []byte) (v interface{}, err error)
+}
+
+// JSONCodec encodes values as JSON. New returns a pointer to a new value of the type to decode into,
+// e.g. func() interface{} { return new(float64) }, so that decoded values have the same type as the
+// values which were encoded. If New is nil, values are decoded in the same way as json.Unmarshal
+// decodes into an interface{}.
+type JSONCodec struct {
+	New func() interface{}
+}
+
+// Encode the value as JSON.
+func (c JSONCodec) Encode(v interface{}) (data []byte, err error) {
+	return json.Marshal(v)
+}
+
+// Decode the JSON into a value returned by New.
+func (c JSONCodec) Decode(data []byte) (v interface{}, err error) {
+	if c.New == nil {
+		err = json.Unmarshal(data, &v)
+		return
+	}
+	ptr := c.New()
+	if err = json.Unmarshal(data, ptr); err != nil {
+		return
+	}
+	v = reflect.ValueOf(ptr).Elem().Interface()
+	return
+}
+
+// PageRankCodec encodes the values and messages of the PageRank computation.
+var PageRankCodec = JSONCodec{New: func() interface{} { return new(float64) }}
+
+// CheckpointDataType is the name of the node data record which holds the checkpoint saved by a
+// StoreCheckpointer.
+const CheckpointDataType = "computeCheckpoint"
+
+// CheckpointStore is where a StoreCheckpointer saves checkpoints. It's implemented by *pregel.Store.
+type CheckpointStore interface {
+	RegisterNamedDataType(name string, f func() interface{}, aliases ...string)
+	Put(nodes ...pregel.Node) error
+	GetWithOptions(id string, opts pregel.GetOptions) (n pregel.Node, ok bool, err error)
+}
+
+// storedCheckpoint is the node data record which holds a checkpoint, with the values and messages
+// encoded by the StoreCheckpointer's codecs.
+type storedCheckpoint struct {
+	Superstep int                 `json:"superstep"`
+	Values    map[string][]byte   `json:"values"`
+	Halted    map[string]bool     `json:"halted"`
+	Messages  map[string][][]byte `json:"messages"`
+}
+
+// ErrCheckpointNotStored is returned by StoreCheckpointer.Load when the node has data which isn't a
+// checkpoint, e.g. because the node ID is used by another node.
+var ErrCheckpointNotStored = errors.New("compute: the node's data is not a checkpoint")
+
+// StoreCheckpointer saves checkpoints as a data record of a node in a store, so that a computation
+// can be resumed by another process, e.g. after a crash. The values and messages of vertices are
+// encoded by the codecs. The checkpoint is a single record, so it's limited to DynamoDB's maximum
+// item size, unless the Store compresses large data records. Use a different Store to the one the
+// computation runs over, e.g. one returned by WithNamespace, so that the node isn't a vertex.
+type StoreCheckpointer struct {
+	store    CheckpointStore
+	id       string
+	values   Codec
+	messages Codec
+}
+
+// NewStoreCheckpointer creates a StoreCheckpointer which saves checkpoints to the node with the ID,
+// and registers the CheckpointDataType with the store.
+func NewStoreCheckpointer(store CheckpointStore, id string, values, messages Codec) *StoreCheckpointer {
+	store.RegisterNamedDataType(CheckpointDataType, func() interface{} { return &storedCheckpoint{} })
+	return &StoreCheckpointer{
+		store:    store,
+		id:       id,
+		values:   values,
+		messages: messages,
+	}
+}
+
+// Save the checkpoint, replacing any previous checkpoint.
+func (sc *StoreCheckpointer) Save(ctx context.Context, c Checkpoint) (err error) {
+	stored := storedCheckpoint{
+		Superstep: c.Superstep,
+		Values:    make(map[string][]byte, len(c.Values)),
+		Halted:    c.Halted,
+		Messages:  make(map[string][][]byte, len(c.Messages)),
+	}
+	for id, v := range c.Values {
+		if stored.Values[id], err = sc.values.Encode(v); err != nil {
+			return
+		}
+	}
+	for id, messages := range c.Messages {
+		encoded := make([][]byte, len(messages))
+		for i, m := range messages {
+			if encoded[i], err = sc.messages.Encode(m); err != nil {
+				return
+			}
+		}
+		stored.Messages[id] = encoded
+	}
+	return sc.store.Put(pregel.NewNode(sc.id).WithNamedData(CheckpointDataType, stored))
+}
+
+// Load the most recently saved checkpoint.
+func (sc *StoreCheckpointer) Load(ctx context.Context) (c Checkpoint, ok bool, err error) {
+	n, found, err := sc.store.GetWithOptions(sc.id, pregel.GetOptions{Data: true})
+	if err != nil || !found {
+		return
+	}
+	data, found := n.Data[CheckpointDataType]
+	if !found {
+		return
+	}
+	stored, isCheckpoint := data.(*storedCheckpoint)
+	if !isCheckpoint {
+		err = ErrCheckpointNotStored
+		return
+	}
+	c = Checkpoint{
+		Superstep: stored.Superstep,
+		Values:    make(map[string]interface{}, len(stored.Values)),
+		Halted:    stored.Halted,
+		Messages:  make(map[string][]interface{}, len(stored.Messages)),
+	}
+	if c.Halted == nil {
+		c.Halted = make(map[string]bool)
+	}
+	for id, data := range stored.Values {
+		if c.Values[id], err = sc.values.Decode(data); err != nil {
+			return
+		}
+	}
+	for id, encoded := range stored.Messages {
+		messages := make([]interface{}, len(encoded))
+		for i, data := range encoded {
+			if messages[i], err = sc.messages.Decode(data); err != nil {
+				return
+			}
+		}
+		c.Messages[id] = messages
+	}
+	ok = true
+	return
+}
@@ -0,0 +1,217 @@
+// Package compute runs vertex-centric computations over the nodes in a store, using the bulk
+// synchronous parallel model described in the Pregel paper.
+//
+// Each computation is a series of supersteps. In each superstep, the VertexProgram is run for every
+// active vertex, receiving the messages sent to the vertex in the previous superstep. A vertex
+// becomes inactive when it votes to halt, and is woken up again if it receives a message. The
+// computation converges when every vertex has halted and there are no messages left to deliver.
+package compute
+
+import (
+	"context"
+	"sort"
+
+	"github.com/a-h/pregel"
+)
+
+// DefaultMaxSupersteps is the maximum number of supersteps run when Engine.MaxSupersteps isn't set.
+const DefaultMaxSupersteps = 100
+
+// DefaultPageSize is the number of nodes loaded at a time when Engine.PageSize isn't set.
+const DefaultPageSize = 100
+
+// Graph provides the nodes to run the computation over. It's implemented by *pregel.Store.
+type Graph interface {
+	ListNodes(limit int, cursor string) (ids []string, next string, err error)
+	GetMany(ids ...string) (nodes []pregel.Node, err error)
+}
+
+// Message is sent by a vertex, and delivered to the To vertex in the next superstep.
+type Message struct {
+	To    string
+	Value interface{}
+}
+
+// Vertex is a node taking part in a computation.
+type Vertex struct {
+	// Node is the node loaded from the graph, including its edges and data.
+	Node pregel.Node
+	// Value is the state of the vertex, which is kept between supersteps.
+	Value interface{}
+	// Superstep is the number of the current superstep, starting at zero.
+	Superstep int
+}
+
+// VertexProgram computes the new state of a vertex in each superstep.
+type VertexProgram interface {
+	// Compute receives the messages sent to the vertex in the previous superstep, and can update the
+	// vertex's Value. It returns the messages to send to other vertices, and whether the vertex votes
+	// to halt.
+	Compute(ctx context.Context, v *Vertex, messages []interface{}) (outgoing []Message, halt bool, err error)
+}
+
+// VertexProgramFunc allows a function to be used as a VertexProgram.
+type VertexProgramFunc func(ctx context.Context, v *Vertex, messages []interface{}) (outgoing []Message, halt bool, err error)
+
+// Compute calls the function.
+func (f VertexProgramFunc) Compute(ctx context.Context, v *Vertex, messages []interface{}) (outgoing []Message, halt bool, err error) {
+	return f(ctx, v, messages)
+}
+
+// Result of a computation.
+type Result struct {
+	// Supersteps is the number of supersteps which have been run.
+	Supersteps int
+	// Converged is true if every vertex halted, and there were no messages left to deliver.
+	Converged bool
+	// Values are the final values of each vertex, keyed by node ID.
+	Values map[string]interface{}
+}
+
+// Engine runs a VertexProgram over the nodes in a Graph.
+type Engine struct {
+	Graph   Graph
+	Program VertexProgram
+	// MaxSupersteps stops the computation if it hasn't converged. If zero, DefaultMaxSupersteps is used.
+	MaxSupersteps int
+	// PageSize is the number of nodes loaded from the Graph at a time. If zero, DefaultPageSize is used.
+	PageSize int
+	// Checkpointer saves the state of the computation after each superstep, so that it can be resumed.
+	// If nil, no checkpoints are saved.
+	Checkpointer Checkpointer
+}
+
+// New creates an Engine which runs the program over the nodes in the graph.
+func New(graph Graph, program VertexProgram) *Engine {
+	return &Engine{
+		Graph:   graph,
+		Program: program,
+	}
+}
+
+// Run loads every node in the graph, then runs supersteps until the computation converges, or
+// MaxSupersteps is reached. If the Checkpointer has a saved checkpoint, the computation resumes from
+// it. Messages sent to vertices which don't exist are dropped.
+func (e *Engine) Run(ctx context.Context) (r Result, err error) {
+	vertices, err := e.load()
+	if err != nil {
+		return
+	}
+	state := Checkpoint{
+		Values:   make(map[string]interface{}),
+		Halted:   make(map[string]bool),
+		Messages: make(map[string][]interface{}),
+	}
+	if e.Checkpointer != nil {
+		var saved Checkpoint
+		var ok bool
+		saved, ok, err = e.Checkpointer.Load(ctx)
+		if err != nil {
+			return
+		}
+		if ok {
+			state = saved
+		}
+	}
+	maxSupersteps := e.MaxSupersteps
+	if maxSupersteps <= 0 {
+		maxSupersteps = DefaultMaxSupersteps
+	}
+	for ; state.Superstep < maxSupersteps; state.Superstep++ {
+		if err = ctx.Err(); err != nil {
+			return
+		}
+		if state.converged() {
+			r.Converged = true
+			break
+		}
+		if state, err = e.superstep(ctx, vertices, state); err != nil {
+			return
+		}
+		if e.Checkpointer != nil {
+			// The checkpoint is for the start of the next superstep.
+			next := state
+			next.Superstep++
+			if err = e.Checkpointer.Save(ctx, next); err != nil {
+				return
+			}
+		}
+	}
+	if !r.Converged {
+		r.Converged = state.converged()
+	}
+	r.Supersteps = state.Superstep
+	r.Values = state.Values
+	return
+}
+
+// superstep runs the program for each active vertex, in order of ID.
+func (e *Engine) superstep(ctx context.Context, vertices []pregel.Node, state Checkpoint) (next Checkpoint, err error) {
+	next = Checkpoint{
+		Superstep: state.Superstep,
+		Values:    make(map[string]interface{}, len(state.Values)),
+		Halted:    make(map[string]bool, len(state.Halted)),
+		Messages:  make(map[string][]interface{}),
+	}
+	exists := make(map[string]bool, len(vertices))
+	for _, n := range vertices {
+		exists[n.ID] = true
+	}
+	for _, n := range vertices {
+		messages := state.Messages[n.ID]
+		if state.Halted[n.ID] && len(messages) == 0 {
+			next.Values[n.ID] = state.Values[n.ID]
+			next.Halted[n.ID] = true
+			continue
+		}
+		v := &Vertex{
+			Node:      n,
+			Value:     state.Values[n.ID],
+			Superstep: state.Superstep,
+		}
+		var outgoing []Message
+		var halt bool
+		outgoing, halt, err = e.Program.Compute(ctx, v, messages)
+		if err != nil {
+			return
+		}
+		next.Values[n.ID] = v.Value
+		next.Halted[n.ID] = halt
+		for _, m := range outgoing {
+			if !exists[m.To] {
+				continue
+			}
+			next.Messages[m.To] = append(next.Messages[m.To], m.Value)
+		}
+	}
+	return
+}
+
+// load reads all of the nodes from the graph, a page at a time.
+func (e *Engine) load() (vertices []pregel.Node, err error) {
+	pageSize := e.PageSize
+	if pageSize <= 0 {
+		pageSize = DefaultPageSize
+	}
+	var cursor string
+	for {
+		var ids []string
+		ids, cursor, err = e.Graph.ListNodes(pageSize, cursor)
+		if err != nil {
+			return
+		}
+		var nodes []pregel.Node
+		nodes, err = e.Graph.GetMany(ids...)
+		if err != nil {
+			return
+		}
+		vertices = append(vertices, nodes...)
+		if cursor == "" {
+			break
+		}
+	}
+	sort.Slice(vertices, func(i, j int) bool {
+		return vertices[i].ID < vertices[j].ID
+	})
+	return
+}
@@ -0,0 +1,231 @@
+package compute
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/a-h/pregel"
+)
+
+var _ Graph = &pregel.Store{}
+
+type testGraph struct {
+	nodes map[string]pregel.Node
+}
+
+func newTestGraph(nodes ...pregel.Node) testGraph {
+	g := testGraph{
+		nodes: make(map[string]pregel.Node),
+	}
+	for _, n := range nodes {
+		g.nodes[n.ID] = n
+	}
+	return g
+}
+
+// ListNodes returns a page of one node at a time, to check that paging is handled.
+func (g testGraph) ListNodes(limit int, cursor string) (ids []string, next string, err error) {
+	var all []string
+	for id := range g.nodes {
+		all = append(all, id)
+	}
+	sort.Strings(all)
+	for i, id := range all {
+		if id <= cursor {
+			continue
+		}
+		ids = []string{id}
+		if i < len(all)-1 {
+			next = id
+		}
+		return
+	}
+	return
+}
+
+func (g testGraph) GetMany(ids ...string) (nodes []pregel.Node, err error) {
+	for _, id := range ids {
+		nodes = append(nodes, g.nodes[id])
+	}
+	return
+}
+
+// maxValue propagates the largest initial value to every vertex it can reach.
+func maxValue(initial map[string]int) VertexProgramFunc {
+	return func(ctx context.Context, v *Vertex, messages []interface{}) (outgoing []Message, halt bool, err error) {
+		changed := v.Superstep == 0
+		if v.Superstep == 0 {
+			v.Value = initial[v.Node.ID]
+		}
+		for _, m := range messages {
+			if m.(int) > v.Value.(int) {
+				v.Value = m.(int)
+				changed = true
+			}
+		}
+		if changed {
+			for _, c := range v.Node.Children {
+				outgoing = append(outgoing, Message{To: c.ID, Value: v.Value})
+			}
+		}
+		halt = true
+		return
+	}
+}
+
+func TestEngineRun(t *testing.T) {
+	// a -> b -> c -> a, and c -> d. Messages to missing nodes are dropped.
+	graph := newTestGraph(
+		pregel.NewNode("a").WithChildren(pregel.NewEdge("b")),
+		pregel.NewNode("b").WithChildren(pregel.NewEdge("c")),
+		pregel.NewNode("c").WithChildren(pregel.NewEdge("a"), pregel.NewEdge("d"), pregel.NewEdge("missing")),
+		pregel.NewNode("d"),
+	)
+	initial := map[string]int{"a": 3, "b": 6, "c": 2, "d": 1}
+	errCompute := errors.New("compute failed")
+
+	tests := []struct {
+		name              string
+		program           VertexProgram
+		maxSupersteps     int
+		expectedValues    map[string]interface{}
+		expectedConverged bool
+		expectedErr       error
+	}{
+		{
+			name:              "The largest value is propagated to all vertices",
+			program:           maxValue(initial),
+			expectedValues:    map[string]interface{}{"a": 6, "b": 6, "c": 6, "d": 6},
+			expectedConverged: true,
+		},
+		{
+			name:           "Computations stop after the maximum number of supersteps",
+			program:        maxValue(initial),
+			maxSupersteps:  2,
+			expectedValues: map[string]interface{}{"a": 3, "b": 6, "c": 6, "d": 2},
+		},
+		{
+			name: "Program errors are returned",
+			program: VertexProgramFunc(func(ctx context.Context, v *Vertex, messages []interface{}) ([]Message, bool, error) {
+				return nil, false, errCompute
+			}),
+			expectedErr: errCompute,
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			e := New(graph, test.program)
+			e.MaxSupersteps = test.maxSupersteps
+			r, err := e.Run(context.Background())
+			if err != test.expectedErr {
+				t.Fatalf("expected err %v, got %v", test.expectedErr, err)
+			}
+			if r.Converged != test.expectedConverged {
+				t.Errorf("expected converged %v, got %v", test.expectedConverged, r.Converged)
+			}
+			if !reflect.DeepEqual(r.Values, test.expectedValues) {
+				t.Errorf("expected values %v, got %v", test.expectedValues, r.Values)
+			}
+		})
+	}
+}
+
+func TestEngineCheckpoints(t *testing.T) {
+	graph := newTestGraph(
+		pregel.NewNode("a").WithChildren(pregel.NewEdge("b")),
+		pregel.NewNode("b"),
+	)
+	initial := map[string]int{"a": 2, "b": 1}
+	cp := &MemoryCheckpointer{}
+
+	e := New(graph, maxValue(initial))
+	e.MaxSupersteps = 1
+	e.Checkpointer = cp
+	r, err := e.Run(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if r.Converged {
+		t.Fatalf("expected the first run to stop before converging")
+	}
+	saved, ok, err := cp.Load(context.Background())
+	if err != nil || !ok {
+		t.Fatalf("expected a checkpoint to be saved, got %v, %v", ok, err)
+	}
+	if saved.Superstep != 1 || !reflect.DeepEqual(saved.Messages, map[string][]interface{}{"b": {2}}) {
+		t.Errorf("unexpected checkpoint: %+v", saved)
+	}
+
+	e.MaxSupersteps = 0
+	r, err = e.Run(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !r.Converged {
+		t.Errorf("expected the resumed run to converge")
+	}
+	if r.Supersteps != 2 {
+		t.Errorf("expected the resumed run to finish after 2 supersteps, got %d", r.Supersteps)
+	}
+	if expected := map[string]interface{}{"a": 2, "b": 2}; !reflect.DeepEqual(r.Values, expected) {
+		t.Errorf("expected values %v, got %v", expected, r.Values)
+	}
+}
+
+func TestStoreCheckpointer(t *testing.T) {
+	graph := newTestGraph(
+		pregel.NewNode("a").WithChildren(pregel.NewEdge("b")),
+		pregel.NewNode("b"),
+	)
+	initial := map[string]int{"a": 2, "b": 1}
+	store, err := pregel.NewMemoryStore().WithNamespace("checkpoints")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	codec := JSONCodec{New: func() interface{} { return new(int) }}
+
+	cp := NewStoreCheckpointer(store, "maxValue", codec, codec)
+	if _, ok, err := cp.Load(context.Background()); err != nil || ok {
+		t.Fatalf("expected no checkpoint before the first run, got %v, %v", ok, err)
+	}
+	e := New(graph, maxValue(initial))
+	e.MaxSupersteps = 1
+	e.Checkpointer = cp
+	if _, err = e.Run(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Another process resumes the computation from the checkpoint in the store.
+	cp = NewStoreCheckpointer(store, "maxValue", codec, codec)
+	saved, ok, err := cp.Load(context.Background())
+	if err != nil || !ok {
+		t.Fatalf("expected a checkpoint to be saved, got %v, %v", ok, err)
+	}
+	expected := Checkpoint{
+		Superstep: 1,
+		Values:    map[string]interface{}{"a": 2, "b": 1},
+		Halted:    map[string]bool{"a": true, "b": true},
+		Messages:  map[string][]interface{}{"b": {2}},
+	}
+	if !reflect.DeepEqual(saved, expected) {
+		t.Errorf("expected checkpoint %+v, got %+v", expected, saved)
+	}
+	e = New(graph, maxValue(initial))
+	e.Checkpointer = cp
+	r, err := e.Run(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !r.Converged || r.Supersteps != 2 {
+		t.Errorf("expected the resumed run to converge after 2 supersteps, got %v after %d", r.Converged, r.Supersteps)
+	}
+	if expected := map[string]interface{}{"a": 2, "b": 2}; !reflect.DeepEqual(r.Values, expected) {
+		t.Errorf("expected values %v, got %v", expected, r.Values)
+	}
+}
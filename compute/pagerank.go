@@ -0,0 +1,154 @@
+package compute
+
+import (
+	"context"
+	"math"
+	"sync"
+
+	"github.com/a-h/pregel"
+)
+
+// Default PageRank options.
+const (
+	DefaultDampingFactor = 0.85
+	DefaultMaxIterations = 30
+	DefaultTolerance     = 1e-6
+)
+
+// Rank is the node data record written by PageRank. To read it back from a Store, register it with
+// RegisterDataType.
+type Rank struct {
+	Rank float64 `json:"rank"`
+}
+
+// DataWriter writes node data. It's implemented by *pregel.Store.
+type DataWriter interface {
+	PutNodeData(id string, data pregel.Data) error
+}
+
+// PageRankOptions control the PageRank computation.
+type PageRankOptions struct {
+	// DampingFactor is the probability of following an edge, rather than jumping to a random node.
+	// If zero, DefaultDampingFactor is used.
+	DampingFactor float64
+	// MaxIterations is the maximum number of times the ranks are updated. If zero,
+	// DefaultMaxIterations is used.
+	MaxIterations int
+	// Tolerance stops the computation once no rank changes by more than the tolerance in an
+	// iteration. If zero, DefaultTolerance is used.
+	Tolerance float64
+	// Checkpointer saves the state of the computation after each superstep. If nil, no checkpoints
+	// are saved. A StoreCheckpointer should use the PageRankCodec for both values and messages.
+	Checkpointer Checkpointer
+}
+
+// PageRank ranks the nodes in the graph by following child edges, then writes each node's rank to
+// the DataWriter as a Rank data record. The ranks sum to one, except that the rank of nodes without
+// children isn't redistributed.
+func PageRank(ctx context.Context, graph Graph, w DataWriter, opts PageRankOptions) (ranks map[string]float64, err error) {
+	if opts.DampingFactor == 0 {
+		opts.DampingFactor = DefaultDampingFactor
+	}
+	if opts.MaxIterations <= 0 {
+		opts.MaxIterations = DefaultMaxIterations
+	}
+	if opts.Tolerance == 0 {
+		opts.Tolerance = DefaultTolerance
+	}
+	count, err := countNodes(graph)
+	if err != nil || count == 0 {
+		return
+	}
+	e := New(graph, newPageRankProgram(float64(count), opts))
+	// The first superstep sets the initial ranks, and each iteration takes another.
+	e.MaxSupersteps = opts.MaxIterations + 1
+	e.Checkpointer = opts.Checkpointer
+	r, err := e.Run(ctx)
+	if err != nil {
+		return
+	}
+	ranks = make(map[string]float64, len(r.Values))
+	for id, v := range r.Values {
+		rank := v.(float64)
+		ranks[id] = rank
+		if err = w.PutNodeData(id, pregel.NewData(Rank{Rank: rank})); err != nil {
+			return
+		}
+	}
+	return
+}
+
+func countNodes(graph Graph) (count int, err error) {
+	var ids []string
+	var cursor string
+	for {
+		ids, cursor, err = graph.ListNodes(DefaultPageSize, cursor)
+		if err != nil {
+			return
+		}
+		count += len(ids)
+		if cursor == "" {
+			return
+		}
+	}
+}
+
+// pageRankProgram keeps track of the largest change to any rank in each superstep, so that all of
+// the vertices can halt together once the ranks have converged.
+type pageRankProgram struct {
+	n        float64
+	opts     PageRankOptions
+	m        sync.Mutex
+	maxDelta map[int]float64
+}
+
+func newPageRankProgram(n float64, opts PageRankOptions) *pageRankProgram {
+	return &pageRankProgram{
+		n:        n,
+		opts:     opts,
+		maxDelta: make(map[int]float64),
+	}
+}
+
+func (p *pageRankProgram) Compute(ctx context.Context, v *Vertex, messages []interface{}) (outgoing []Message, halt bool, err error) {
+	if v.Superstep == 0 {
+		v.Value = 1 / p.n
+	} else {
+		if p.converged(v.Superstep - 1) {
+			halt = true
+			return
+		}
+		var sum float64
+		for _, m := range messages {
+			sum += m.(float64)
+		}
+		rank := (1-p.opts.DampingFactor)/p.n + p.opts.DampingFactor*sum
+		p.recordDelta(v.Superstep, math.Abs(rank-v.Value.(float64)))
+		v.Value = rank
+	}
+	if len(v.Node.Children) == 0 {
+		return
+	}
+	share := v.Value.(float64) / float64(len(v.Node.Children))
+	for _, c := range v.Node.Children {
+		outgoing = append(outgoing, Message{To: c.ID, Value: share})
+	}
+	return
+}
+
+// converged returns true if no rank changed by more than the tolerance in the superstep. The first
+// superstep only sets the initial ranks, so it never converges.
+func (p *pageRankProgram) converged(superstep int) bool {
+	p.m.Lock()
+	defer p.m.Unlock()
+	delta, ok := p.maxDelta[superstep]
+	return ok && delta < p.opts.Tolerance
+}
+
+func (p *pageRankProgram) recordDelta(superstep int, delta float64) {
+	p.m.Lock()
+	defer p.m.Unlock()
+	if current, ok := p.maxDelta[superstep]; !ok || delta > current {
+		p.maxDelta[superstep] = delta
+	}
+}
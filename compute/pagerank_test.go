@@ -0,0 +1,78 @@
+package compute
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/a-h/pregel"
+)
+
+type testDataWriter struct {
+	data map[string]pregel.Data
+	err  error
+}
+
+func (w *testDataWriter) PutNodeData(id string, data pregel.Data) error {
+	if w.data == nil {
+		w.data = make(map[string]pregel.Data)
+	}
+	w.data[id] = data
+	return w.err
+}
+
+func TestPageRank(t *testing.T) {
+	t.Run("Ranks are written to each node", func(t *testing.T) {
+		// a and b both link to c, and c links back to a.
+		graph := newTestGraph(
+			pregel.NewNode("a").WithChildren(pregel.NewEdge("c")),
+			pregel.NewNode("b").WithChildren(pregel.NewEdge("c")),
+			pregel.NewNode("c").WithChildren(pregel.NewEdge("a")),
+		)
+		w := &testDataWriter{}
+		ranks, err := PageRank(context.Background(), graph, w, PageRankOptions{MaxIterations: 100})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !(ranks["c"] > ranks["a"] && ranks["a"] > ranks["b"]) {
+			t.Errorf("expected c to rank highest and b to rank lowest, got %v", ranks)
+		}
+		// b has no parents, so its rank is just the random jump probability.
+		if expected := (1 - DefaultDampingFactor) / 3; math.Abs(ranks["b"]-expected) > DefaultTolerance {
+			t.Errorf("expected b to have rank %v, got %v", expected, ranks["b"])
+		}
+		var sum float64
+		for id, rank := range ranks {
+			sum += rank
+			written, ok := w.data[id]["Rank"]
+			if !ok || written.(Rank).Rank != rank {
+				t.Errorf("expected rank %v to be written to %s, got %+v", rank, id, w.data[id])
+			}
+		}
+		if math.Abs(sum-1) > 1e-3 {
+			t.Errorf("expected ranks to sum to 1, got %v", sum)
+		}
+	})
+	t.Run("Symmetric graphs have equal ranks", func(t *testing.T) {
+		graph := newTestGraph(
+			pregel.NewNode("a").WithChildren(pregel.NewEdge("b")),
+			pregel.NewNode("b").WithChildren(pregel.NewEdge("a")),
+		)
+		ranks, err := PageRank(context.Background(), graph, &testDataWriter{}, PageRankOptions{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if ranks["a"] != 0.5 || ranks["b"] != 0.5 {
+			t.Errorf("expected equal ranks, got %v", ranks)
+		}
+	})
+	t.Run("Write errors are returned", func(t *testing.T) {
+		errWrite := errors.New("write failed")
+		graph := newTestGraph(pregel.NewNode("a"))
+		_, err := PageRank(context.Background(), graph, &testDataWriter{err: errWrite}, PageRankOptions{})
+		if err != errWrite {
+			t.Errorf("expected err %v, got %v", errWrite, err)
+		}
+	})
+}
@@ -0,0 +1,43 @@
+package pregel
+
+import "reflect"
+
+// DataOf returns the node's data of type T, e.g. DataOf[router](n). The data is found using the
+// type name of T, and ok is false if the node doesn't have the data, or if the data type wasn't
+// registered when the node was read, rather than panicking like a failed type assertion.
+func DataOf[T any](n Node) (v T, ok bool) {
+	return dataOf[T](n.Data)
+}
+
+// EdgeDataOf returns the edge's data of type T, e.g. EdgeDataOf[connection](e). See DataOf.
+func EdgeDataOf[T any](e *Edge) (v T, ok bool) {
+	if e == nil {
+		return
+	}
+	return dataOf[T](e.Data)
+}
+
+func dataOf[T any](d Data) (v T, ok bool) {
+	value, found := d[dataTypeNameOf[T]()]
+	if !found {
+		return
+	}
+	// Data read from the store is a pointer to the registered type, while data added using WithData
+	// is whatever was passed in, so accept either.
+	if v, ok = value.(T); ok {
+		return
+	}
+	if p, isPtr := value.(*T); isPtr && p != nil {
+		return *p, true
+	}
+	return
+}
+
+// dataTypeNameOf returns the name that data of type T is stored under.
+func dataTypeNameOf[T any]() string {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return getTypeName(reflect.New(t).Interface())
+}
@@ -0,0 +1,47 @@
+package pregel
+
+import (
+	"testing"
+)
+
+func TestDataOf(t *testing.T) {
+	n := NewNode("nodeA").
+		WithData(testNodeData{ExtraAttribute: "value"}).
+		WithData(&testEdgeData{EdgeDataField: 1})
+
+	v, ok := DataOf[testNodeData](n)
+	if !ok || v.ExtraAttribute != "value" {
+		t.Errorf("expected value data to be returned, got %+v, %v", v, ok)
+	}
+	p, ok := DataOf[*testNodeData](n)
+	if ok || p != nil {
+		t.Errorf("expected value data not to be returned as a pointer, got %+v, %v", p, ok)
+	}
+	ev, ok := DataOf[testEdgeData](n)
+	if !ok || ev.EdgeDataField != 1 {
+		t.Errorf("expected pointer data to be returned as a value, got %+v, %v", ev, ok)
+	}
+	ep, ok := DataOf[*testEdgeData](n)
+	if !ok || ep.EdgeDataField != 1 {
+		t.Errorf("expected pointer data to be returned, got %+v, %v", ep, ok)
+	}
+	if _, ok := DataOf[namedTestData](n); ok {
+		t.Errorf("expected missing data not to be returned")
+	}
+
+	n.Data["customName"] = &map[string]interface{}{}
+	if _, ok := DataOf[namedTestData](n); ok {
+		t.Errorf("expected unregistered data not to be returned")
+	}
+}
+
+func TestEdgeDataOf(t *testing.T) {
+	e := NewEdge("nodeB").WithData(namedTestData{Value: "a"})
+	v, ok := EdgeDataOf[namedTestData](e)
+	if !ok || v.Value != "a" {
+		t.Errorf("expected data to be found using its TypeName, got %+v, %v", v, ok)
+	}
+	if _, ok := EdgeDataOf[namedTestData](nil); ok {
+		t.Errorf("expected nil edges not to have data")
+	}
+}
@@ -0,0 +1,67 @@
+package pregel
+
+import (
+	"errors"
+
+	"github.com/a-h/pregel/rangefield"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// ErrDataTypeIndexNotConfigured is returned by QueryByDataType when the Store's DataTypeIndex isn't set.
+var ErrDataTypeIndexNotConfigured = errors.New("data type index not configured")
+
+// QueryByDataType returns a page of up to limit IDs of nodes which have data of the type, e.g. all of
+// the nodes with "router" data. Edge data isn't included. Paging works in the same way as
+// QueryByLabel. The data type index is eventually consistent, so recently added data may not be
+// returned immediately, and soft deleted nodes keep their data.
+func (s *Store) QueryByDataType(dataType string, limit int, cursor string) (ids []string, next string, err error) {
+	if dataType == "" {
+		err = ErrMissingDataType
+		return
+	}
+	if s.DataTypeIndex == "" {
+		err = ErrDataTypeIndexNotConfigured
+		return
+	}
+	if name, isAlias := s.DataTypeAliases[dataType]; isAlias {
+		dataType = name
+	}
+	var startKey map[string]*dynamodb.AttributeValue
+	if cursor != "" {
+		// The start key contains the table's key and the index's key.
+		startKey = getID(cursor, rangefield.NodeData{DataType: dataType})
+		startKey[fieldRecordDataType] = &dynamodb.AttributeValue{S: aws.String(dataType)}
+	}
+	for {
+		items, lastEvaluatedKey, cc, qErr := s.Client.QueryIndexPage(s.context(), s.DataTypeIndex, fieldRecordDataType, dataType, int64(limit), startKey)
+		if qErr != nil {
+			err = qErr
+			return
+		}
+		s.updateCapacityStats(cc)
+		for _, itm := range items {
+			rf, ok := decodeRangeField(itm)
+			if !ok || isExpired(itm, s.now()) {
+				continue
+			}
+			if nd, isNodeData := rf.(rangefield.NodeData); !isNodeData || nd.DataType != dataType {
+				continue
+			}
+			ids = append(ids, aws.StringValue(itm[fieldID].S))
+		}
+		if limit > 0 && len(ids) >= limit {
+			// As with ListNodes, the cursor is the ID of the last node returned.
+			truncated := len(ids) > limit
+			ids = ids[:limit]
+			if truncated || lastEvaluatedKey != nil {
+				next = ids[limit-1]
+			}
+			return
+		}
+		if lastEvaluatedKey == nil {
+			return
+		}
+		startKey = lastEvaluatedKey
+	}
+}
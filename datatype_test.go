@@ -0,0 +1,97 @@
+package pregel
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/a-h/pregel/db"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+func TestStoreQueryByDataType(t *testing.T) {
+	dataRecord := func(id, rng string) map[string]*dynamodb.AttributeValue {
+		return map[string]*dynamodb.AttributeValue{
+			"id":  {S: aws.String(id)},
+			"rng": {S: aws.String(rng)},
+			"t":   {S: aws.String("router")},
+		}
+	}
+	records := []map[string]*dynamodb.AttributeValue{
+		dataRecord("routerA", "node/data/router"),
+		dataRecord("routerB", "node/data/router"),
+		dataRecord("routerB", "child/routerC/data/router"),
+		dataRecord("routerC", "node/data/router"),
+	}
+	tests := []struct {
+		name          string
+		dataType      string
+		dataTypeIndex string
+		limit         int
+		cursor        string
+		expectedIDs   []string
+		expectedNext  string
+		expectedErr   error
+	}{
+		{
+			name:          "Missing data types result in an error",
+			dataTypeIndex: "dataTypes",
+			expectedErr:   ErrMissingDataType,
+		},
+		{
+			name:        "An index must be configured",
+			dataType:    "router",
+			expectedErr: ErrDataTypeIndexNotConfigured,
+		},
+		{
+			name:          "Edge data is skipped",
+			dataType:      "router",
+			dataTypeIndex: "dataTypes",
+			expectedIDs:   []string{"routerA", "routerB", "routerC"},
+		},
+		{
+			name:          "Aliases are resolved",
+			dataType:      "wifiRouter",
+			dataTypeIndex: "dataTypes",
+			expectedIDs:   []string{"routerA", "routerB", "routerC"},
+		},
+		{
+			name:          "Limits return a cursor",
+			dataType:      "router",
+			dataTypeIndex: "dataTypes",
+			limit:         2,
+			expectedIDs:   []string{"routerA", "routerB"},
+			expectedNext:  "routerB",
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			client := newdynamoDBClient()
+			client.queryIndexPager = func(indexName, field string, value interface{}, limit int64, startKey map[string]*dynamodb.AttributeValue) ([]map[string]*dynamodb.AttributeValue, map[string]*dynamodb.AttributeValue, db.ConsumedCapacity, error) {
+				if indexName != test.dataTypeIndex || field != "t" || value != "router" {
+					t.Errorf("unexpected query of %s.%s = %v", indexName, field, value)
+				}
+				if limit > 0 && int(limit) < len(records) {
+					return records[:limit], records[limit-1], db.ConsumedCapacity{}, nil
+				}
+				return records, nil, db.ConsumedCapacity{}, nil
+			}
+			s := NewStoreWithClient(client)
+			s.RegisterNamedDataType("router", func() interface{} { return &testNodeData{} }, "wifiRouter")
+			s.DataTypeIndex = test.dataTypeIndex
+			ids, next, err := s.QueryByDataType(test.dataType, test.limit, test.cursor)
+			if err != test.expectedErr {
+				t.Errorf("expected err %v, got %v", test.expectedErr, err)
+			}
+			if !reflect.DeepEqual(ids, test.expectedIDs) {
+				t.Errorf("expected IDs %v, got %v", test.expectedIDs, ids)
+			}
+			if next != test.expectedNext {
+				t.Errorf("expected next cursor %q, got %q", test.expectedNext, next)
+			}
+		})
+	}
+}
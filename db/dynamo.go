@@ -1,7 +1,13 @@
 package db
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
@@ -18,13 +24,23 @@ func New(region, tableName string) (db *DB, err error) {
 	if err != nil {
 		return
 	}
-	db = &DB{
-		Client:    dynamodb.New(sess),
-		TableName: tableName,
-	}
+	db = NewWithSession(sess, tableName)
 	return
 }
 
+// NewWithSession creates a new DynamoDB database tool which uses an existing session, e.g. one
+// configured with assumed role credentials, a custom HTTP client, or a proxy. The AWS SDK's own
+// retries are disabled, so that requests are only retried according to the DB's RetryPolicy, and
+// are always delayed by its Throttle.
+func NewWithSession(sess *session.Session, tableName string) *DB {
+	return &DB{
+		Client:      dynamodb.New(sess, aws.NewConfig().WithMaxRetries(0)),
+		TableName:   tableName,
+		RetryPolicy: DefaultRetryPolicy(),
+		Throttle:    NewThrottle(),
+	}
+}
+
 // ConsumedCapacity from the DB.
 type ConsumedCapacity struct {
 	ConsumedCapacity      float64
@@ -42,6 +58,9 @@ func (c ConsumedCapacity) add(cc ConsumedCapacity) ConsumedCapacity {
 
 func newConsumedCapacity(dcc ...*dynamodb.ConsumedCapacity) (cc ConsumedCapacity) {
 	for _, itm := range dcc {
+		if itm == nil {
+			continue
+		}
 		if itm.CapacityUnits != nil {
 			cc.ConsumedCapacity += *itm.CapacityUnits
 		}
@@ -57,12 +76,34 @@ func newConsumedCapacity(dcc ...*dynamodb.ConsumedCapacity) (cc ConsumedCapacity
 
 // DB client for the store which uses DynamoDB.
 type DB struct {
+	// Client makes the requests. Its own retries should be disabled, as they are by New and
+	// NewWithSession, because the SDK retries throttled requests without waiting for the Throttle.
 	Client    *dynamodb.DynamoDB
 	TableName string
+	// PartitionKeyType is the DynamoDB scalar type of the table's partition key, i.e. "S", "N" or "B".
+	// The ID values passed to QueryByID and QueryPage are converted to the type. If empty, "S" is used.
+	PartitionKeyType string
+	// RetryPolicy decides which failed requests are retried. If nil, requests aren't retried.
+	RetryPolicy RetryPolicy
+	// Throttle slows down requests while DynamoDB is throttling them. If nil, requests aren't slowed down.
+	Throttle *Throttle
+	// Metrics observes each call made to DynamoDB. If nil, calls aren't observed.
+	Metrics Metrics
+	// Logger receives a LogEntry for each operation. If nil, operations aren't logged.
+	Logger Logger
+	// LogAttributes are the attributes whose values are included in log entries. The values of other
+	// attributes are redacted. If nil, DefaultLogAttributes are used.
+	LogAttributes []string
 }
 
-// BatchDelete items in the underlying table.
-func (db *DB) BatchDelete(keys []map[string]*dynamodb.AttributeValue) (cc ConsumedCapacity, err error) {
+// BatchDelete items in the underlying table. The keys are split into requests of up to
+// MaxBatchWriteItems, and keys which DynamoDB doesn't process are retried in the same way as BatchPut.
+func (db *DB) BatchDelete(ctx context.Context, keys []map[string]*dynamodb.AttributeValue) (cc ConsumedCapacity, err error) {
+	if db.Logger != nil {
+		defer func(start time.Time) {
+			db.logOperation("BatchDelete", "", start, logItems(keys...), len(keys), cc, err)
+		}(time.Now())
+	}
 	var deleteRequests []*dynamodb.WriteRequest
 	for _, item := range keys {
 		deleteRequests = append(deleteRequests,
@@ -72,21 +113,97 @@ func (db *DB) BatchDelete(keys []map[string]*dynamodb.AttributeValue) (cc Consum
 				},
 			})
 	}
-	bwo, err := db.Client.BatchWriteItem(&dynamodb.BatchWriteItemInput{
-		RequestItems: map[string][]*dynamodb.WriteRequest{
-			db.TableName: deleteRequests,
-		},
-		ReturnConsumedCapacity: aws.String(dynamodb.ReturnConsumedCapacityIndexes),
-	})
-	if err != nil {
-		return
+	return db.batchWrite(ctx, deleteRequests)
+}
+
+// BatchPut items into the table. The items are split into requests of up to MaxBatchWriteItems.
+// Items which DynamoDB doesn't process, e.g. due to throttling, are retried according to the
+// RetryPolicy, and ErrUnprocessedItems is returned if they still aren't processed.
+func (db *DB) BatchPut(ctx context.Context, items []map[string]*dynamodb.AttributeValue) (cc ConsumedCapacity, err error) {
+	if db.Logger != nil {
+		defer func(start time.Time) {
+			db.logOperation("BatchPut", "", start, logItems(items...), len(items), cc, err)
+		}(time.Now())
+	}
+	var wrs []*dynamodb.WriteRequest
+	for _, item := range items {
+		wrs = append(wrs, &dynamodb.WriteRequest{
+			PutRequest: &dynamodb.PutRequest{
+				Item: item,
+			},
+		})
+	}
+	return db.batchWrite(ctx, wrs)
+}
+
+// ErrUnprocessedItems is returned by BatchPut and BatchDelete when DynamoDB still hasn't processed
+// some of the write requests after retrying.
+var ErrUnprocessedItems = errors.New("DB.BatchWrite: items were not processed after retrying")
+
+// batchWrite makes a BatchWriteItem request for each chunk of up to MaxBatchWriteItems write
+// requests, and returns the total consumed capacity. Write requests which DynamoDB doesn't process,
+// e.g. due to throttling, are resubmitted according to the RetryPolicy.
+func (db *DB) batchWrite(ctx context.Context, wrs []*dynamodb.WriteRequest) (cc ConsumedCapacity, err error) {
+	for len(wrs) > 0 {
+		chunk := wrs
+		if len(chunk) > MaxBatchWriteItems {
+			chunk = chunk[:MaxBatchWriteItems]
+		}
+		wrs = wrs[len(chunk):]
+		for attempt := 0; len(chunk) > 0; attempt++ {
+			if attempt == db.batchAttempts() {
+				err = ErrUnprocessedItems
+				return
+			}
+			if attempt > 0 {
+				if err = sleep(ctx, db.batchBackoff(attempt-1)); err != nil {
+					return
+				}
+			}
+			var bwo *dynamodb.BatchWriteItemOutput
+			callCC, bErr := db.do(ctx, "BatchWriteItem", func() (cc ConsumedCapacity, cErr error) {
+				bwo, cErr = db.Client.BatchWriteItemWithContext(ctx, &dynamodb.BatchWriteItemInput{
+					RequestItems: map[string][]*dynamodb.WriteRequest{
+						db.TableName: chunk,
+					},
+					ReturnConsumedCapacity: aws.String(dynamodb.ReturnConsumedCapacityIndexes),
+				})
+				if cErr == nil {
+					cc = newConsumedCapacity(bwo.ConsumedCapacity...)
+				}
+				return
+			})
+			if bErr != nil {
+				err = requestError("DB.BatchWrite", "failed to write items", bErr)
+				return
+			}
+			cc = cc.add(callCC)
+			chunk = bwo.UnprocessedItems[db.TableName]
+		}
 	}
-	cc = newConsumedCapacity(bwo.ConsumedCapacity...)
 	return
 }
 
-// BatchPut items into the table.
-func (db *DB) BatchPut(items []map[string]*dynamodb.AttributeValue) (cc ConsumedCapacity, err error) {
+// MaxBatchWriteItems is the maximum number of items which can be written in a single DynamoDB
+// BatchWriteItem request.
+const MaxBatchWriteItems = 25
+
+// TryBatchPut makes a single BatchWriteItem request to put the items into the table, and returns the
+// items which DynamoDB didn't process, e.g. due to throttling. The caller is responsible for retrying
+// the unprocessed items. Up to MaxBatchWriteItems items can be put at once.
+func (db *DB) TryBatchPut(ctx context.Context, items []map[string]*dynamodb.AttributeValue) (unprocessed []map[string]*dynamodb.AttributeValue, cc ConsumedCapacity, err error) {
+	if db.Logger != nil {
+		defer func(start time.Time) {
+			db.logOperation("TryBatchPut", "", start, logItems(items...), len(items)-len(unprocessed), cc, err)
+		}(time.Now())
+	}
+	if len(items) == 0 {
+		return
+	}
+	if len(items) > MaxBatchWriteItems {
+		err = fmt.Errorf("DB.TryBatchPut: batch write requests cannot contain more than %d items", MaxBatchWriteItems)
+		return
+	}
 	var wrs []*dynamodb.WriteRequest
 	for _, item := range items {
 		wrs = append(wrs, &dynamodb.WriteRequest{
@@ -95,28 +212,551 @@ func (db *DB) BatchPut(items []map[string]*dynamodb.AttributeValue) (cc Consumed
 			},
 		})
 	}
-	bwo, err := db.Client.BatchWriteItem(&dynamodb.BatchWriteItemInput{
-		RequestItems: map[string][]*dynamodb.WriteRequest{
-			db.TableName: wrs,
-		},
+	var bwo *dynamodb.BatchWriteItemOutput
+	cc, err = db.do(ctx, "BatchWriteItem", func() (cc ConsumedCapacity, cErr error) {
+		bwo, cErr = db.Client.BatchWriteItemWithContext(ctx, &dynamodb.BatchWriteItemInput{
+			RequestItems: map[string][]*dynamodb.WriteRequest{
+				db.TableName: wrs,
+			},
+			ReturnConsumedCapacity: aws.String(dynamodb.ReturnConsumedCapacityIndexes),
+		})
+		if cErr == nil {
+			cc = newConsumedCapacity(bwo.ConsumedCapacity...)
+		}
+		return
+	})
+	if err != nil {
+		err = requestError("DB.TryBatchPut", "failed to put items", err)
+		return
+	}
+	for _, wr := range bwo.UnprocessedItems[db.TableName] {
+		if wr.PutRequest != nil {
+			unprocessed = append(unprocessed, wr.PutRequest.Item)
+		}
+	}
+	return
+}
+
+// MaxTransactionItems is the maximum number of items which can be written in a single DynamoDB
+// transaction.
+const MaxTransactionItems = 100
+
+// ErrTooManyTransactionItems is returned when a transaction contains more than MaxTransactionItems
+// items.
+var ErrTooManyTransactionItems = fmt.Errorf("transactions cannot contain more than %d items", MaxTransactionItems)
+
+// ErrInvalidTransactWriteItem is returned when a TransactWriteItem doesn't have exactly one of Put,
+// Delete or ConditionCheck set, or a ConditionCheck doesn't have a Condition.
+var ErrInvalidTransactWriteItem = errors.New("transaction items must have exactly one of Put, Delete or ConditionCheck")
+
+// TransactWriteItem is a single operation within a transaction. Exactly one of Put, Delete or
+// ConditionCheck must be set.
+type TransactWriteItem struct {
+	// Put writes the item.
+	Put map[string]*dynamodb.AttributeValue
+	// Delete removes the item with the key.
+	Delete map[string]*dynamodb.AttributeValue
+	// ConditionCheck checks the Condition against the item with the key, without writing to it.
+	ConditionCheck map[string]*dynamodb.AttributeValue
+	// Condition must be true for the transaction to be written. It's optional for Put and Delete.
+	Condition *expression.ConditionBuilder
+}
+
+// TransactPut puts the items into the table in a single transaction, so either all of the items are
+// written, or none of them are.
+func (db *DB) TransactPut(ctx context.Context, items []map[string]*dynamodb.AttributeValue) (cc ConsumedCapacity, err error) {
+	twis := make([]TransactWriteItem, len(items))
+	for i, item := range items {
+		twis[i] = TransactWriteItem{Put: item}
+	}
+	return db.TransactWrite(ctx, twis)
+}
+
+// TransactPutIfExists puts the items into the table in a single transaction, if the item with the key
+// exists, and doesn't have the excluded field. Otherwise, ErrConditionalCheckFailed is returned. An
+// empty excluded field only checks that the item exists.
+func (db *DB) TransactPutIfExists(ctx context.Context, key map[string]*dynamodb.AttributeValue, idField, excludedField string, items []map[string]*dynamodb.AttributeValue) (cc ConsumedCapacity, err error) {
+	c := expression.AttributeExists(expression.Name(idField))
+	if excludedField != "" {
+		c = c.And(expression.AttributeNotExists(expression.Name(excludedField)))
+	}
+	twis := []TransactWriteItem{{ConditionCheck: key, Condition: &c}}
+	for _, item := range items {
+		twis = append(twis, TransactWriteItem{Put: item})
+	}
+	return db.TransactWrite(ctx, twis)
+}
+
+// TransactWrite makes the puts, deletes and condition checks in a single transaction, so either all of
+// the writes are made, or none of them are. If any of the conditions aren't met, ErrConditionalCheckFailed
+// is returned.
+func (db *DB) TransactWrite(ctx context.Context, items []TransactWriteItem) (cc ConsumedCapacity, err error) {
+	if db.Logger != nil {
+		defer func(start time.Time) {
+			db.logOperation("TransactWrite", "", start, logTransactWriteItems(items), len(items), cc, err)
+		}(time.Now())
+	}
+	if len(items) == 0 {
+		return
+	}
+	if len(items) > MaxTransactionItems {
+		err = ErrTooManyTransactionItems
+		return
+	}
+	twis := make([]*dynamodb.TransactWriteItem, len(items))
+	for i, item := range items {
+		if twis[i], err = db.newTransactWriteItem(item); err != nil {
+			return
+		}
+	}
+	var two *dynamodb.TransactWriteItemsOutput
+	cc, err = db.do(ctx, "TransactWriteItems", func() (cc ConsumedCapacity, cErr error) {
+		two, cErr = db.Client.TransactWriteItemsWithContext(ctx, &dynamodb.TransactWriteItemsInput{
+			TransactItems:          twis,
+			ReturnConsumedCapacity: aws.String(dynamodb.ReturnConsumedCapacityIndexes),
+		})
+		if cErr == nil {
+			cc = newConsumedCapacity(two.ConsumedCapacity...)
+		}
+		return
+	})
+	if err != nil {
+		if isConditionCheckCancellation(err) {
+			err = ErrConditionalCheckFailed
+			return
+		}
+		err = requestError("DB.TransactWrite", "failed to write items", err)
+		return
+	}
+	return
+}
+
+func (db *DB) newTransactWriteItem(item TransactWriteItem) (twi *dynamodb.TransactWriteItem, err error) {
+	var set int
+	for _, m := range []map[string]*dynamodb.AttributeValue{item.Put, item.Delete, item.ConditionCheck} {
+		if m != nil {
+			set++
+		}
+	}
+	if set != 1 || (item.ConditionCheck != nil && item.Condition == nil) {
+		err = ErrInvalidTransactWriteItem
+		return
+	}
+	var condition *string
+	var names map[string]*string
+	var values map[string]*dynamodb.AttributeValue
+	if item.Condition != nil {
+		expr, bErr := expression.NewBuilder().
+			WithCondition(*item.Condition).
+			Build()
+		if bErr != nil {
+			err = fmt.Errorf("DB.TransactWrite: failed to build condition: %v", bErr)
+			return
+		}
+		condition, names, values = expr.Condition(), expr.Names(), expr.Values()
+	}
+	twi = &dynamodb.TransactWriteItem{}
+	switch {
+	case item.Put != nil:
+		twi.Put = &dynamodb.Put{
+			TableName:                 aws.String(db.TableName),
+			Item:                      item.Put,
+			ConditionExpression:       condition,
+			ExpressionAttributeNames:  names,
+			ExpressionAttributeValues: values,
+		}
+	case item.Delete != nil:
+		twi.Delete = &dynamodb.Delete{
+			TableName:                 aws.String(db.TableName),
+			Key:                       item.Delete,
+			ConditionExpression:       condition,
+			ExpressionAttributeNames:  names,
+			ExpressionAttributeValues: values,
+		}
+	default:
+		twi.ConditionCheck = &dynamodb.ConditionCheck{
+			TableName:                 aws.String(db.TableName),
+			Key:                       item.ConditionCheck,
+			ConditionExpression:       condition,
+			ExpressionAttributeNames:  names,
+			ExpressionAttributeValues: values,
+		}
+	}
+	return
+}
+
+// isConditionCheckCancellation returns true if the transaction was cancelled because a condition
+// wasn't met.
+func isConditionCheckCancellation(err error) bool {
+	tce, ok := err.(*dynamodb.TransactionCanceledException)
+	if !ok {
+		return false
+	}
+	for _, r := range tce.CancellationReasons {
+		if aws.StringValue(r.Code) == "ConditionalCheckFailed" {
+			return true
+		}
+	}
+	return false
+}
+
+// UpdateItem sets the attributes of the item with the key, leaving its other attributes unchanged.
+// If the item doesn't exist, it's created.
+func (db *DB) UpdateItem(ctx context.Context, key map[string]*dynamodb.AttributeValue, set map[string]*dynamodb.AttributeValue) (cc ConsumedCapacity, err error) {
+	if db.Logger != nil {
+		defer func(start time.Time) {
+			db.logOperation("UpdateItem", "", start, logItems(key, set), 1, cc, err)
+		}(time.Now())
+	}
+	if len(set) == 0 {
+		return
+	}
+	var attributes []string
+	for k := range set {
+		attributes = append(attributes, k)
+	}
+	sort.Strings(attributes)
+	names := make(map[string]*string, len(attributes))
+	values := make(map[string]*dynamodb.AttributeValue, len(attributes))
+	assignments := make([]string, len(attributes))
+	for i, attribute := range attributes {
+		name, value := fmt.Sprintf("#n%d", i), fmt.Sprintf(":v%d", i)
+		names[name] = aws.String(attribute)
+		values[value] = set[attribute]
+		assignments[i] = name + " = " + value
+	}
+	var uio *dynamodb.UpdateItemOutput
+	cc, err = db.do(ctx, "UpdateItem", func() (cc ConsumedCapacity, cErr error) {
+		uio, cErr = db.Client.UpdateItemWithContext(ctx, &dynamodb.UpdateItemInput{
+			TableName:                 aws.String(db.TableName),
+			Key:                       key,
+			UpdateExpression:          aws.String("SET " + strings.Join(assignments, ", ")),
+			ExpressionAttributeNames:  names,
+			ExpressionAttributeValues: values,
+			ReturnConsumedCapacity:    aws.String(dynamodb.ReturnConsumedCapacityIndexes),
+		})
+		if cErr == nil {
+			cc = newConsumedCapacity(uio.ConsumedCapacity)
+		}
+		return
+	})
+	if err != nil {
+		err = requestError("DB.UpdateItem", "failed to update item", err)
+		return
+	}
+	return
+}
+
+// ErrConditionalCheckFailed is returned when a conditional write isn't made because its condition
+// wasn't met.
+var ErrConditionalCheckFailed = errors.New("conditional check failed")
+
+// PutIfNotExists puts the item into the table, unless an item with the same key already exists,
+// in which case ErrConditionalCheckFailed is returned.
+func (db *DB) PutIfNotExists(ctx context.Context, idField string, item map[string]*dynamodb.AttributeValue) (cc ConsumedCapacity, err error) {
+	if db.Logger != nil {
+		defer func(start time.Time) {
+			db.logOperation("PutIfNotExists", "", start, logItems(item), 1, cc, err)
+		}(time.Now())
+	}
+	c := expression.AttributeNotExists(expression.Name(idField))
+
+	expr, err := expression.NewBuilder().
+		WithCondition(c).
+		Build()
+	if err != nil {
+		err = fmt.Errorf("DB.PutIfNotExists: failed to build condition: %v", err)
+		return
+	}
+
+	var pio *dynamodb.PutItemOutput
+	cc, err = db.do(ctx, "PutItem", func() (cc ConsumedCapacity, cErr error) {
+		pio, cErr = db.Client.PutItemWithContext(ctx, &dynamodb.PutItemInput{
+			TableName:                 aws.String(db.TableName),
+			Item:                      item,
+			ConditionExpression:       expr.Condition(),
+			ExpressionAttributeNames:  expr.Names(),
+			ExpressionAttributeValues: expr.Values(),
+			ReturnConsumedCapacity:    aws.String(dynamodb.ReturnConsumedCapacityIndexes),
+		})
+		if cErr == nil {
+			cc = newConsumedCapacity(pio.ConsumedCapacity)
+		}
+		return
+	})
+	if err != nil {
+		if isConditionalCheckFailure(err) {
+			err = ErrConditionalCheckFailed
+			return
+		}
+		err = requestError("DB.PutIfNotExists", "failed to put item", err)
+		return
+	}
+	return
+}
+
+func isConditionalCheckFailure(err error) bool {
+	return Classify(err) == ErrConditionalCheckFailed
+}
+
+// GetItem returns the item with the given key, or nil if the item doesn't exist. If attributes are
+// provided, only those attributes are returned.
+func (db *DB) GetItem(ctx context.Context, key map[string]*dynamodb.AttributeValue, attributes ...string) (item map[string]*dynamodb.AttributeValue, cc ConsumedCapacity, err error) {
+	if db.Logger != nil {
+		defer func(start time.Time) {
+			db.logOperation("GetItem", "", start, logItems(key), countItem(item), cc, err)
+		}(time.Now())
+	}
+	gii := &dynamodb.GetItemInput{
+		TableName:              aws.String(db.TableName),
+		Key:                    key,
+		ConsistentRead:         aws.Bool(true),
 		ReturnConsumedCapacity: aws.String(dynamodb.ReturnConsumedCapacityIndexes),
+	}
+	if len(attributes) > 0 {
+		expr, bErr := expression.NewBuilder().WithProjection(projection(attributes)).Build()
+		if bErr != nil {
+			err = fmt.Errorf("DB.GetItem: failed to build projection: %v", bErr)
+			return
+		}
+		gii.ProjectionExpression = expr.Projection()
+		gii.ExpressionAttributeNames = expr.Names()
+	}
+	var gio *dynamodb.GetItemOutput
+	cc, err = db.do(ctx, "GetItem", func() (cc ConsumedCapacity, cErr error) {
+		gio, cErr = db.Client.GetItemWithContext(ctx, gii)
+		if cErr == nil {
+			cc = newConsumedCapacity(gio.ConsumedCapacity)
+		}
+		return
 	})
 	if err != nil {
+		err = requestError("DB.GetItem", "failed to get item", err)
 		return
 	}
-	cc = newConsumedCapacity(bwo.ConsumedCapacity...)
+	item = gio.Item
 	return
 }
 
-// QueryByID returns items with a given ID field name and value.
-func (db *DB) QueryByID(field, value string) (items []map[string]*dynamodb.AttributeValue, cc ConsumedCapacity, err error) {
-	q := expression.Key(field).Equal(expression.Value(value))
+// MaxBatchGetItems is the maximum number of keys which can be read in a single DynamoDB BatchGetItem
+// request.
+const MaxBatchGetItems = 100
+
+// maxBatchAttempts is the number of times batch requests submit keys or items which DynamoDB didn't
+// process, if the DB doesn't have a RetryPolicy.
+const maxBatchAttempts = 5
+
+func (db *DB) batchAttempts() int {
+	if db.RetryPolicy == nil {
+		return maxBatchAttempts
+	}
+	return db.RetryPolicy.MaxAttempts()
+}
+
+func (db *DB) batchBackoff(attempt int) time.Duration {
+	if db.RetryPolicy == nil {
+		return time.Duration(1<<uint(attempt)) * 50 * time.Millisecond
+	}
+	return db.RetryPolicy.Backoff(attempt)
+}
+
+// ErrUnprocessedKeys is returned by BatchGet when DynamoDB still hasn't processed some of the keys
+// after retrying.
+var ErrUnprocessedKeys = errors.New("DB.BatchGet: keys were not processed after retrying")
+
+// BatchGet retrieves the items with the keys, using requests of up to MaxBatchGetItems keys. Keys
+// which DynamoDB doesn't process, e.g. due to throttling, are retried according to the RetryPolicy. The items are
+// returned in no particular order, and keys which don't exist are skipped.
+func (db *DB) BatchGet(ctx context.Context, keys []map[string]*dynamodb.AttributeValue) (items []map[string]*dynamodb.AttributeValue, cc ConsumedCapacity, err error) {
+	if db.Logger != nil {
+		defer func(start time.Time) {
+			db.logOperation("BatchGet", "", start, logItems(keys...), len(items), cc, err)
+		}(time.Now())
+	}
+	for len(keys) > 0 {
+		chunk := keys
+		if len(chunk) > MaxBatchGetItems {
+			chunk = chunk[:MaxBatchGetItems]
+		}
+		keys = keys[len(chunk):]
+		for attempt := 0; len(chunk) > 0; attempt++ {
+			if attempt == db.batchAttempts() {
+				err = ErrUnprocessedKeys
+				return
+			}
+			if attempt > 0 {
+				if err = sleep(ctx, db.batchBackoff(attempt-1)); err != nil {
+					return
+				}
+			}
+			var bgo *dynamodb.BatchGetItemOutput
+			callCC, bErr := db.do(ctx, "BatchGetItem", func() (cc ConsumedCapacity, cErr error) {
+				bgo, cErr = db.Client.BatchGetItemWithContext(ctx, &dynamodb.BatchGetItemInput{
+					RequestItems: map[string]*dynamodb.KeysAndAttributes{
+						db.TableName: {
+							Keys:           chunk,
+							ConsistentRead: aws.Bool(true),
+						},
+					},
+					ReturnConsumedCapacity: aws.String(dynamodb.ReturnConsumedCapacityIndexes),
+				})
+				if cErr == nil {
+					cc = newConsumedCapacity(bgo.ConsumedCapacity...)
+				}
+				return
+			})
+			if bErr != nil {
+				err = requestError("DB.BatchGet", "failed to get items", bErr)
+				return
+			}
+			cc = cc.add(callCC)
+			items = append(items, bgo.Responses[db.TableName]...)
+			chunk = nil
+			if unprocessed, ok := bgo.UnprocessedKeys[db.TableName]; ok && unprocessed != nil {
+				chunk = unprocessed.Keys
+			}
+		}
+	}
+	return
+}
+
+// QueryByID returns items with a given ID field name and value. If attributes are provided, only
+// those attributes of each item are returned, which reduces the size of the response, e.g. when only
+// the keys of the items are required.
+func (db *DB) QueryByID(ctx context.Context, field, value string, attributes ...string) (items []map[string]*dynamodb.AttributeValue, cc ConsumedCapacity, err error) {
+	if db.Logger != nil {
+		defer func(start time.Time) {
+			db.logOperation("QueryByID", "", start, logConditions(field, value), len(items), cc, err)
+		}(time.Now())
+	}
+	q := expression.Key(field).Equal(expression.Value(db.keyValue(value)))
+	return db.queryPages(ctx, "DB.QueryByID", q, attributes)
+}
+
+// QueryByIDAndRangePrefix returns the items with the given ID whose range field begins with the range
+// prefix, e.g. just the child edges of a node. If attributes are provided, only those attributes of
+// each item are returned.
+func (db *DB) QueryByIDAndRangePrefix(ctx context.Context, idField, idValue, rangeField, rangePrefix string, attributes ...string) (items []map[string]*dynamodb.AttributeValue, cc ConsumedCapacity, err error) {
+	if db.Logger != nil {
+		defer func(start time.Time) {
+			db.logOperation("QueryByIDAndRangePrefix", "", start, logConditions(idField, idValue, rangeField, rangePrefix), len(items), cc, err)
+		}(time.Now())
+	}
+	q := expression.Key(idField).Equal(expression.Value(db.keyValue(idValue)))
+	if rangePrefix != "" {
+		q = q.And(expression.Key(rangeField).BeginsWith(rangePrefix))
+	}
+	return db.queryPages(ctx, "DB.QueryByIDAndRangePrefix", q, attributes)
+}
+
+// queryPages returns every item which matches the key condition, reading all of the pages.
+func (db *DB) queryPages(ctx context.Context, op string, q expression.KeyConditionBuilder, attributes []string) (items []map[string]*dynamodb.AttributeValue, cc ConsumedCapacity, err error) {
+	builder := expression.NewBuilder().
+		WithKeyCondition(q)
+	if len(attributes) > 0 {
+		builder = builder.WithProjection(projection(attributes))
+	}
+	expr, err := builder.Build()
+	if err != nil {
+		err = fmt.Errorf("%s: failed to build query: %v", op, err)
+		return
+	}
+
+	qi := &dynamodb.QueryInput{
+		TableName:                 aws.String(db.TableName),
+		KeyConditionExpression:    expr.KeyCondition(),
+		ExpressionAttributeValues: expr.Values(),
+		FilterExpression:          expr.Filter(),
+		ProjectionExpression:      expr.Projection(),
+		ExpressionAttributeNames:  expr.Names(),
+		ConsistentRead:            aws.Bool(true),
+		ReturnConsumedCapacity:    aws.String(dynamodb.ReturnConsumedCapacityIndexes),
+	}
+
+	var pagesCC ConsumedCapacity
+	page := func(page *dynamodb.QueryOutput, lastPage bool) bool {
+		items = append(items, page.Items...)
+		pagesCC = pagesCC.add(newConsumedCapacity(page.ConsumedCapacity))
+		return true
+	}
+
+	cc, err = db.do(ctx, "Query", func() (ConsumedCapacity, error) {
+		// Throttled queries start again from the first page.
+		items, pagesCC = nil, ConsumedCapacity{}
+		qErr := db.Client.QueryPagesWithContext(ctx, qi, page)
+		return pagesCC, qErr
+	})
+	if err != nil {
+		err = requestError(op, "failed to query pages", err)
+		return
+	}
+	return
+}
+
+// keyValue converts a partition key value into the table's PartitionKeyType.
+func (db *DB) keyValue(value string) interface{} {
+	if db.PartitionKeyType == "" || db.PartitionKeyType == dynamodb.ScalarAttributeTypeS {
+		return value
+	}
+	return partitionKeyValue{value: value, attributeType: db.PartitionKeyType}
+}
+
+// partitionKeyValue is marshalled as a number or binary attribute value.
+type partitionKeyValue struct {
+	value         string
+	attributeType string
+}
+
+// MarshalDynamoDBAttributeValue implements dynamodbattribute.Marshaler.
+func (pkv partitionKeyValue) MarshalDynamoDBAttributeValue(av *dynamodb.AttributeValue) error {
+	switch pkv.attributeType {
+	case dynamodb.ScalarAttributeTypeN:
+		av.N = aws.String(pkv.value)
+	case dynamodb.ScalarAttributeTypeB:
+		av.B = []byte(pkv.value)
+	default:
+		av.S = aws.String(pkv.value)
+	}
+	return nil
+}
+
+// projection builds a projection expression of the attributes, which must not be empty.
+func projection(attributes []string) expression.ProjectionBuilder {
+	names := make([]expression.NameBuilder, len(attributes))
+	for i, a := range attributes {
+		names[i] = expression.Name(a)
+	}
+	return expression.NamesList(names[0], names[1:]...)
+}
+
+// QueryByIDPage returns a page of up to limit items with a given ID field name and value, instead of
+// every item like QueryByID. A limit of zero doesn't limit the number of items evaluated. To get the
+// next page, pass the returned lastEvaluatedKey as the startKey of the next call. When there are no
+// more items, lastEvaluatedKey is nil.
+func (db *DB) QueryByIDPage(ctx context.Context, field, value string, limit int64, startKey map[string]*dynamodb.AttributeValue) (items []map[string]*dynamodb.AttributeValue, lastEvaluatedKey map[string]*dynamodb.AttributeValue, cc ConsumedCapacity, err error) {
+	return db.QueryPage(ctx, field, value, "", "", limit, startKey)
+}
+
+// QueryPage returns a page of items with the given ID whose range field begins with the range prefix.
+// An empty range prefix matches all items with the ID, and a limit of zero doesn't limit the
+// number of items evaluated. To get the next page, pass the returned lastEvaluatedKey as the
+// startKey of the next call. When there are no more items, lastEvaluatedKey is nil.
+func (db *DB) QueryPage(ctx context.Context, idField, idValue, rangeField, rangePrefix string, limit int64, startKey map[string]*dynamodb.AttributeValue) (items []map[string]*dynamodb.AttributeValue, lastEvaluatedKey map[string]*dynamodb.AttributeValue, cc ConsumedCapacity, err error) {
+	if db.Logger != nil {
+		defer func(start time.Time) {
+			db.logOperation("QueryPage", "", start, logConditions(idField, idValue, rangeField, rangePrefix), len(items), cc, err)
+		}(time.Now())
+	}
+	q := expression.Key(idField).Equal(expression.Value(db.keyValue(idValue)))
+	if rangePrefix != "" {
+		q = q.And(expression.Key(rangeField).BeginsWith(rangePrefix))
+	}
 
 	expr, err := expression.NewBuilder().
 		WithKeyCondition(q).
 		Build()
 	if err != nil {
-		err = fmt.Errorf("DB.QueryByID: failed to build query: %v", err)
+		err = fmt.Errorf("DB.QueryPage: failed to build query: %v", err)
 		return
 	}
 
@@ -124,27 +764,260 @@ func (db *DB) QueryByID(field, value string) (items []map[string]*dynamodb.Attri
 		TableName:                 aws.String(db.TableName),
 		KeyConditionExpression:    expr.KeyCondition(),
 		ExpressionAttributeValues: expr.Values(),
-		FilterExpression:          expr.Filter(),
 		ExpressionAttributeNames:  expr.Names(),
+		ExclusiveStartKey:         startKey,
 		ConsistentRead:            aws.Bool(true),
 		ReturnConsumedCapacity:    aws.String(dynamodb.ReturnConsumedCapacityIndexes),
 	}
+	if limit > 0 {
+		qi.Limit = aws.Int64(limit)
+	}
 
-	var pageErr error
+	var qo *dynamodb.QueryOutput
+	cc, err = db.do(ctx, "Query", func() (cc ConsumedCapacity, cErr error) {
+		qo, cErr = db.Client.QueryWithContext(ctx, qi)
+		if cErr == nil {
+			cc = newConsumedCapacity(qo.ConsumedCapacity)
+		}
+		return
+	})
+	if err != nil {
+		err = requestError("DB.QueryPage", "failed to query", err)
+		return
+	}
+	items = qo.Items
+	lastEvaluatedKey = qo.LastEvaluatedKey
+	return
+}
+
+// ScanPage returns a page of items from the table where the field is equal to the value.
+// A limit of zero doesn't limit the number of items evaluated. To get the next page, pass the
+// returned lastEvaluatedKey as the startKey of the next call. When there are no more items,
+// lastEvaluatedKey is nil.
+func (db *DB) ScanPage(ctx context.Context, field, value string, limit int64, startKey map[string]*dynamodb.AttributeValue) (items []map[string]*dynamodb.AttributeValue, lastEvaluatedKey map[string]*dynamodb.AttributeValue, cc ConsumedCapacity, err error) {
+	if db.Logger != nil {
+		defer func(start time.Time) {
+			db.logOperation("ScanPage", "", start, logConditions(field, value), len(items), cc, err)
+		}(time.Now())
+	}
+	f := expression.Name(field).Equal(expression.Value(value))
+
+	expr, err := expression.NewBuilder().
+		WithFilter(f).
+		Build()
+	if err != nil {
+		err = fmt.Errorf("DB.ScanPage: failed to build scan: %v", err)
+		return
+	}
+
+	si := &dynamodb.ScanInput{
+		TableName:                 aws.String(db.TableName),
+		FilterExpression:          expr.Filter(),
+		ExpressionAttributeValues: expr.Values(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExclusiveStartKey:         startKey,
+		ReturnConsumedCapacity:    aws.String(dynamodb.ReturnConsumedCapacityIndexes),
+	}
+	if limit > 0 {
+		si.Limit = aws.Int64(limit)
+	}
+
+	var so *dynamodb.ScanOutput
+	cc, err = db.do(ctx, "Scan", func() (cc ConsumedCapacity, cErr error) {
+		so, cErr = db.Client.ScanWithContext(ctx, si)
+		if cErr == nil {
+			cc = newConsumedCapacity(so.ConsumedCapacity)
+		}
+		return
+	})
+	if err != nil {
+		err = requestError("DB.ScanPage", "failed to scan", err)
+		return
+	}
+	items = so.Items
+	lastEvaluatedKey = so.LastEvaluatedKey
+	return
+}
+
+// ScanSegment returns a page of all of the items in one segment of a parallel scan of the table.
+// The table is divided into totalSegments segments, numbered from zero, which can be scanned
+// concurrently. Paging works in the same way as ScanPage.
+func (db *DB) ScanSegment(ctx context.Context, segment, totalSegments, limit int64, startKey map[string]*dynamodb.AttributeValue) (items []map[string]*dynamodb.AttributeValue, lastEvaluatedKey map[string]*dynamodb.AttributeValue, cc ConsumedCapacity, err error) {
+	if db.Logger != nil {
+		defer func(start time.Time) {
+			db.logOperation("ScanSegment", "", start, nil, len(items), cc, err)
+		}(time.Now())
+	}
+	si := &dynamodb.ScanInput{
+		TableName:              aws.String(db.TableName),
+		Segment:                aws.Int64(segment),
+		TotalSegments:          aws.Int64(totalSegments),
+		ExclusiveStartKey:      startKey,
+		ReturnConsumedCapacity: aws.String(dynamodb.ReturnConsumedCapacityIndexes),
+	}
+	if limit > 0 {
+		si.Limit = aws.Int64(limit)
+	}
+	var so *dynamodb.ScanOutput
+	cc, err = db.do(ctx, "Scan", func() (cc ConsumedCapacity, cErr error) {
+		so, cErr = db.Client.ScanWithContext(ctx, si)
+		if cErr == nil {
+			cc = newConsumedCapacity(so.ConsumedCapacity)
+		}
+		return
+	})
+	if err != nil {
+		err = requestError("DB.ScanSegment", "failed to scan", err)
+		return
+	}
+	items = so.Items
+	lastEvaluatedKey = so.LastEvaluatedKey
+	return
+}
+
+// ScanSegments scans the whole table with totalSegments parallel workers, and calls fn with each
+// page of items. fn is called concurrently by the workers, but the pages of each segment are passed
+// to fn in order, by the same worker. If fn returns an error, or the context is cancelled, the scan
+// stops and the error is returned.
+func (db *DB) ScanSegments(ctx context.Context, totalSegments int64, fn func(segment int64, items []map[string]*dynamodb.AttributeValue) error) (cc ConsumedCapacity, err error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var m sync.Mutex
+	var wg sync.WaitGroup
+	for i := int64(0); i < totalSegments; i++ {
+		wg.Add(1)
+		go func(segment int64) {
+			defer wg.Done()
+			segmentCC, sErr := db.scanSegment(ctx, segment, totalSegments, fn)
+			m.Lock()
+			defer m.Unlock()
+			cc = cc.add(segmentCC)
+			if sErr != nil && err == nil {
+				err = sErr
+				cancel()
+			}
+		}(i)
+	}
+	wg.Wait()
+	return
+}
+
+func (db *DB) scanSegment(ctx context.Context, segment, totalSegments int64, fn func(segment int64, items []map[string]*dynamodb.AttributeValue) error) (cc ConsumedCapacity, err error) {
+	var startKey map[string]*dynamodb.AttributeValue
+	for {
+		if err = ctx.Err(); err != nil {
+			return
+		}
+		items, lastEvaluatedKey, pageCC, sErr := db.ScanSegment(ctx, segment, totalSegments, 0, startKey)
+		cc = cc.add(pageCC)
+		if sErr != nil {
+			err = sErr
+			return
+		}
+		if err = fn(segment, items); err != nil {
+			return
+		}
+		if lastEvaluatedKey == nil {
+			return
+		}
+		startKey = lastEvaluatedKey
+	}
+}
+
+// QueryIndex returns all items from the global secondary index whose partition key field is equal
+// to the value. Global secondary indexes don't support consistent reads, so recent writes may not
+// be returned.
+func (db *DB) QueryIndex(ctx context.Context, indexName, field string, value interface{}) (items []map[string]*dynamodb.AttributeValue, cc ConsumedCapacity, err error) {
+	if db.Logger != nil {
+		defer func(start time.Time) {
+			db.logOperation("QueryIndex", indexName, start, logConditions(field, fmt.Sprint(value)), len(items), cc, err)
+		}(time.Now())
+	}
+	q := expression.Key(field).Equal(expression.Value(value))
+
+	expr, err := expression.NewBuilder().
+		WithKeyCondition(q).
+		Build()
+	if err != nil {
+		err = fmt.Errorf("DB.QueryIndex: failed to build query: %v", err)
+		return
+	}
+
+	qi := &dynamodb.QueryInput{
+		TableName:                 aws.String(db.TableName),
+		IndexName:                 aws.String(indexName),
+		KeyConditionExpression:    expr.KeyCondition(),
+		ExpressionAttributeValues: expr.Values(),
+		ExpressionAttributeNames:  expr.Names(),
+		ReturnConsumedCapacity:    aws.String(dynamodb.ReturnConsumedCapacityIndexes),
+	}
+
+	var pagesCC ConsumedCapacity
 	page := func(page *dynamodb.QueryOutput, lastPage bool) bool {
 		items = append(items, page.Items...)
-		cc = cc.add(newConsumedCapacity(page.ConsumedCapacity))
+		pagesCC = pagesCC.add(newConsumedCapacity(page.ConsumedCapacity))
 		return true
 	}
 
-	err = db.Client.QueryPages(qi, page)
+	cc, err = db.do(ctx, "Query", func() (ConsumedCapacity, error) {
+		// Throttled queries start again from the first page.
+		items, pagesCC = nil, ConsumedCapacity{}
+		qErr := db.Client.QueryPagesWithContext(ctx, qi, page)
+		return pagesCC, qErr
+	})
+	if err != nil {
+		err = requestError("DB.QueryIndex", "failed to query pages", err)
+		return
+	}
+	return
+}
+
+// QueryIndexPage returns a page of items from the global secondary index whose partition key field
+// is equal to the value. A limit of zero doesn't limit the number of items evaluated. To get the next
+// page, pass the returned lastEvaluatedKey as the startKey of the next call. When there are no more
+// items, lastEvaluatedKey is nil.
+func (db *DB) QueryIndexPage(ctx context.Context, indexName, field string, value interface{}, limit int64, startKey map[string]*dynamodb.AttributeValue) (items []map[string]*dynamodb.AttributeValue, lastEvaluatedKey map[string]*dynamodb.AttributeValue, cc ConsumedCapacity, err error) {
+	if db.Logger != nil {
+		defer func(start time.Time) {
+			db.logOperation("QueryIndexPage", indexName, start, logConditions(field, fmt.Sprint(value)), len(items), cc, err)
+		}(time.Now())
+	}
+	q := expression.Key(field).Equal(expression.Value(value))
+
+	expr, err := expression.NewBuilder().
+		WithKeyCondition(q).
+		Build()
 	if err != nil {
-		err = fmt.Errorf("DB.QueryByID: failed to query pages: %v", err)
+		err = fmt.Errorf("DB.QueryIndexPage: failed to build query: %v", err)
 		return
 	}
-	if pageErr != nil {
-		err = fmt.Errorf("DB.QueryByID: failed to unmarshal data: %v", pageErr)
+
+	qi := &dynamodb.QueryInput{
+		TableName:                 aws.String(db.TableName),
+		IndexName:                 aws.String(indexName),
+		KeyConditionExpression:    expr.KeyCondition(),
+		ExpressionAttributeValues: expr.Values(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExclusiveStartKey:         startKey,
+		ReturnConsumedCapacity:    aws.String(dynamodb.ReturnConsumedCapacityIndexes),
+	}
+	if limit > 0 {
+		qi.Limit = aws.Int64(limit)
+	}
+
+	var qo *dynamodb.QueryOutput
+	cc, err = db.do(ctx, "Query", func() (cc ConsumedCapacity, cErr error) {
+		qo, cErr = db.Client.QueryWithContext(ctx, qi)
+		if cErr == nil {
+			cc = newConsumedCapacity(qo.ConsumedCapacity)
+		}
+		return
+	})
+	if err != nil {
+		err = requestError("DB.QueryIndexPage", "failed to query", err)
 		return
 	}
+	items = qo.Items
+	lastEvaluatedKey = qo.LastEvaluatedKey
 	return
 }
@@ -0,0 +1,70 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// ErrThrottled is matched by errors returned when DynamoDB rejects a request because the table's
+// capacity, or the account's request rate, was exceeded, and retrying didn't succeed.
+var ErrThrottled = errors.New("request throttled")
+
+// ErrResourceNotFound is matched by errors returned when the table or index doesn't exist, or isn't
+// active.
+var ErrResourceNotFound = errors.New("resource not found")
+
+// ErrValidation is matched by errors returned when DynamoDB rejects a request as invalid, e.g.
+// because an attribute has the wrong type for a key.
+var ErrValidation = errors.New("request failed validation")
+
+// RequestError is returned when a request to DynamoDB fails. It can be matched against
+// ErrConditionalCheckFailed, ErrThrottled, ErrResourceNotFound and ErrValidation using errors.Is,
+// and unwraps to the AWS SDK error.
+type RequestError struct {
+	// Op is the DB method which failed, e.g. "DB.GetItem".
+	Op string
+	// Message describes what the method was doing, e.g. "failed to get item".
+	Message string
+	Err     error
+}
+
+func (err RequestError) Error() string {
+	return fmt.Sprintf("%s: %s: %v", err.Op, err.Message, err.Err)
+}
+
+func (err RequestError) Unwrap() error {
+	return err.Err
+}
+
+// Is returns true if the target is the sentinel error which classifies the AWS error.
+func (err RequestError) Is(target error) bool {
+	c := Classify(err.Err)
+	return c != nil && c == target
+}
+
+// Classify returns the sentinel error which matches the AWS error code of err, or nil if err isn't
+// an AWS error, or the code isn't classified.
+func Classify(err error) error {
+	var aerr awserr.Error
+	if !errors.As(err, &aerr) {
+		return nil
+	}
+	switch aerr.Code() {
+	case dynamodb.ErrCodeConditionalCheckFailedException:
+		return ErrConditionalCheckFailed
+	case dynamodb.ErrCodeProvisionedThroughputExceededException, dynamodb.ErrCodeRequestLimitExceeded, "ThrottlingException":
+		return ErrThrottled
+	case dynamodb.ErrCodeResourceNotFoundException:
+		return ErrResourceNotFound
+	case "ValidationException":
+		return ErrValidation
+	}
+	return nil
+}
+
+func requestError(op, message string, err error) error {
+	return RequestError{Op: op, Message: message, Err: err}
+}
@@ -0,0 +1,291 @@
+// Package file provides an implementation of the database used by pregel.Store which stores each
+// node as a JSON file in a directory, so that the graph can be inspected, diffed and committed, e.g.
+// for examples, documentation and golden file tests.
+//
+//	client, err := file.New("testdata/graph")
+//	s := pregel.NewStoreWithClient(client)
+//
+// Each file contains the records of a node, ordered by their range, in DynamoDB's JSON format. The
+// name of the file is the escaped ID of the node. The graph is held in memory, and the files of the
+// nodes are rewritten after each write, so the directory mustn't be written to by anything else while
+// it's in use.
+package file
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/a-h/pregel/db"
+	"github.com/a-h/pregel/db/memory"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// extension is the extension of the file of each node.
+const extension = ".json"
+
+// DB stores the items of a table in a directory, with a file for the records of each node. Reads are
+// served by an in-memory table. It's safe for concurrent use.
+type DB struct {
+	*memory.DB
+	// Dir is the directory which contains the files.
+	Dir string
+
+	m sync.Mutex
+}
+
+// New creates a DB which stores the items of a table in the directory, creating the directory if it
+// doesn't exist, and reading any files which it contains.
+func New(dir string, indexes ...db.TableIndex) (fdb *DB, err error) {
+	if err = os.MkdirAll(dir, 0755); err != nil {
+		return
+	}
+	fdb = &DB{
+		DB:  memory.New(indexes...),
+		Dir: dir,
+	}
+	names, err := filepath.Glob(filepath.Join(dir, "*"+extension))
+	if err != nil {
+		return
+	}
+	var items []map[string]*dynamodb.AttributeValue
+	for _, name := range names {
+		fileItems, rErr := readFile(name)
+		if rErr != nil {
+			return nil, rErr
+		}
+		items = append(items, fileItems...)
+	}
+	if _, err = fdb.DB.BatchPut(context.Background(), items); err != nil {
+		err = fmt.Errorf("file: failed to load %q: %v", dir, err)
+	}
+	return
+}
+
+func readFile(name string) (items []map[string]*dynamodb.AttributeValue, err error) {
+	b, err := ioutil.ReadFile(name)
+	if err != nil {
+		return
+	}
+	var records []map[string]*value
+	if err = json.Unmarshal(b, &records); err != nil {
+		err = fmt.Errorf("file: failed to read %q: %v", name, err)
+		return
+	}
+	for _, r := range records {
+		itm := make(map[string]*dynamodb.AttributeValue, len(r))
+		for k, v := range r {
+			itm[k] = v.attributeValue()
+		}
+		items = append(items, itm)
+	}
+	return
+}
+
+// fileName returns the name of the file which contains the records of the node.
+func (fdb *DB) fileName(id string) string {
+	return filepath.Join(fdb.Dir, url.PathEscape(id)+extension)
+}
+
+// ids returns the IDs of the nodes of the items.
+func (fdb *DB) ids(items ...map[string]*dynamodb.AttributeValue) (ids []string) {
+	seen := make(map[string]bool)
+	for _, itm := range items {
+		v, ok := itm[fdb.PartitionKey]
+		if !ok || v == nil {
+			continue
+		}
+		var id string
+		switch {
+		case v.S != nil:
+			id = *v.S
+		case v.N != nil:
+			id = *v.N
+		default:
+			id = string(v.B)
+		}
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	return
+}
+
+// save rewrites the files of the nodes, or removes the files of nodes which don't have any records.
+func (fdb *DB) save(ctx context.Context, ids []string) (err error) {
+	for _, id := range ids {
+		items, _, qErr := fdb.DB.QueryByID(ctx, fdb.PartitionKey, id)
+		if qErr != nil {
+			return qErr
+		}
+		name := fdb.fileName(id)
+		if len(items) == 0 {
+			if err = os.Remove(name); err != nil && !os.IsNotExist(err) {
+				return
+			}
+			err = nil
+			continue
+		}
+		records := make([]map[string]*value, len(items))
+		for i, itm := range items {
+			records[i] = make(map[string]*value, len(itm))
+			for k, v := range itm {
+				records[i][k] = newValue(v)
+			}
+		}
+		var buf bytes.Buffer
+		enc := json.NewEncoder(&buf)
+		enc.SetIndent("", "  ")
+		if err = enc.Encode(records); err != nil {
+			return
+		}
+		// Write to a temporary file, so that the file is replaced in a single step.
+		tmp := name + ".tmp"
+		if err = ioutil.WriteFile(tmp, buf.Bytes(), 0644); err != nil {
+			return
+		}
+		if err = os.Rename(tmp, name); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// write makes a write to the in-memory table, then saves the files of the nodes it changed.
+func (fdb *DB) write(ctx context.Context, items []map[string]*dynamodb.AttributeValue, f func() (db.ConsumedCapacity, error)) (cc db.ConsumedCapacity, err error) {
+	fdb.m.Lock()
+	defer fdb.m.Unlock()
+	if cc, err = f(); err != nil {
+		return
+	}
+	err = fdb.save(ctx, fdb.ids(items...))
+	return
+}
+
+// BatchDelete deletes the items with the keys.
+func (fdb *DB) BatchDelete(ctx context.Context, keys []map[string]*dynamodb.AttributeValue) (db.ConsumedCapacity, error) {
+	return fdb.write(ctx, keys, func() (db.ConsumedCapacity, error) {
+		return fdb.DB.BatchDelete(ctx, keys)
+	})
+}
+
+// BatchPut puts the items into the table.
+func (fdb *DB) BatchPut(ctx context.Context, items []map[string]*dynamodb.AttributeValue) (db.ConsumedCapacity, error) {
+	return fdb.write(ctx, items, func() (db.ConsumedCapacity, error) {
+		return fdb.DB.BatchPut(ctx, items)
+	})
+}
+
+// TryBatchPut puts up to db.MaxBatchWriteItems items into the table. Every item is processed.
+func (fdb *DB) TryBatchPut(ctx context.Context, items []map[string]*dynamodb.AttributeValue) (unprocessed []map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error) {
+	cc, err = fdb.write(ctx, items, func() (cc db.ConsumedCapacity, err error) {
+		unprocessed, cc, err = fdb.DB.TryBatchPut(ctx, items)
+		return
+	})
+	return
+}
+
+// PutIfNotExists puts the item into the table, unless an item with the same key already exists, in
+// which case db.ErrConditionalCheckFailed is returned.
+func (fdb *DB) PutIfNotExists(ctx context.Context, idField string, item map[string]*dynamodb.AttributeValue) (db.ConsumedCapacity, error) {
+	return fdb.write(ctx, []map[string]*dynamodb.AttributeValue{item}, func() (db.ConsumedCapacity, error) {
+		return fdb.DB.PutIfNotExists(ctx, idField, item)
+	})
+}
+
+// TransactPut puts all of the items, or none of them.
+func (fdb *DB) TransactPut(ctx context.Context, items []map[string]*dynamodb.AttributeValue) (db.ConsumedCapacity, error) {
+	return fdb.write(ctx, items, func() (db.ConsumedCapacity, error) {
+		return fdb.DB.TransactPut(ctx, items)
+	})
+}
+
+// TransactWrite makes all of the puts and deletes, if all of the conditions are met. Otherwise,
+// db.ErrConditionalCheckFailed is returned.
+func (fdb *DB) TransactWrite(ctx context.Context, items []db.TransactWriteItem) (db.ConsumedCapacity, error) {
+	var written []map[string]*dynamodb.AttributeValue
+	for _, item := range items {
+		written = append(written, item.Put, item.Delete)
+	}
+	return fdb.write(ctx, written, func() (db.ConsumedCapacity, error) {
+		return fdb.DB.TransactWrite(ctx, items)
+	})
+}
+
+// TransactPutIfExists puts the items, if the item with the key exists and doesn't have the excluded
+// field. Otherwise, db.ErrConditionalCheckFailed is returned.
+func (fdb *DB) TransactPutIfExists(ctx context.Context, key map[string]*dynamodb.AttributeValue, idField, excludedField string, items []map[string]*dynamodb.AttributeValue) (db.ConsumedCapacity, error) {
+	return fdb.write(ctx, items, func() (db.ConsumedCapacity, error) {
+		return fdb.DB.TransactPutIfExists(ctx, key, idField, excludedField, items)
+	})
+}
+
+// UpdateItem sets the attributes of the item with the key, creating the item if it doesn't exist.
+func (fdb *DB) UpdateItem(ctx context.Context, key map[string]*dynamodb.AttributeValue, set map[string]*dynamodb.AttributeValue) (db.ConsumedCapacity, error) {
+	return fdb.write(ctx, []map[string]*dynamodb.AttributeValue{key}, func() (db.ConsumedCapacity, error) {
+		return fdb.DB.UpdateItem(ctx, key, set)
+	})
+}
+
+// value is an attribute value in DynamoDB's JSON format, which only includes the value's type.
+type value struct {
+	S    *string            `json:"S,omitempty"`
+	N    *string            `json:"N,omitempty"`
+	B    []byte             `json:"B,omitempty"`
+	BOOL *bool              `json:"BOOL,omitempty"`
+	NULL *bool              `json:"NULL,omitempty"`
+	SS   []*string          `json:"SS,omitempty"`
+	NS   []*string          `json:"NS,omitempty"`
+	BS   [][]byte           `json:"BS,omitempty"`
+	L    *[]*value          `json:"L,omitempty"`
+	M    *map[string]*value `json:"M,omitempty"`
+}
+
+func newValue(av *dynamodb.AttributeValue) (v *value) {
+	if av == nil {
+		return nil
+	}
+	v = &value{S: av.S, N: av.N, B: av.B, BOOL: av.BOOL, NULL: av.NULL, SS: av.SS, NS: av.NS, BS: av.BS}
+	if av.L != nil {
+		l := make([]*value, len(av.L))
+		for i, e := range av.L {
+			l[i] = newValue(e)
+		}
+		v.L = &l
+	}
+	if av.M != nil {
+		m := make(map[string]*value, len(av.M))
+		for k, e := range av.M {
+			m[k] = newValue(e)
+		}
+		v.M = &m
+	}
+	return
+}
+
+func (v *value) attributeValue() (av *dynamodb.AttributeValue) {
+	if v == nil {
+		return nil
+	}
+	av = &dynamodb.AttributeValue{S: v.S, N: v.N, B: v.B, BOOL: v.BOOL, NULL: v.NULL, SS: v.SS, NS: v.NS, BS: v.BS}
+	if v.L != nil {
+		av.L = make([]*dynamodb.AttributeValue, len(*v.L))
+		for i, e := range *v.L {
+			av.L[i] = e.attributeValue()
+		}
+	}
+	if v.M != nil {
+		av.M = make(map[string]*dynamodb.AttributeValue, len(*v.M))
+		for k, e := range *v.M {
+			av.M[k] = e.attributeValue()
+		}
+	}
+	return
+}
@@ -0,0 +1,365 @@
+// Package condition evaluates DynamoDB condition expressions against items, for the implementations
+// of the database which don't use DynamoDB.
+package condition
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/expression"
+)
+
+// UnsupportedError is returned when a condition uses a feature of DynamoDB condition expressions
+// which isn't supported.
+type UnsupportedError struct {
+	Condition string
+	Reason    string
+}
+
+func (err UnsupportedError) Error() string {
+	return fmt.Sprintf("unsupported condition %q: %s", err.Condition, err.Reason)
+}
+
+// Evaluate returns true if the condition is met by the item, which is nil if it doesn't exist.
+// Conditions can use comparisons, BETWEEN, IN, attribute_exists, attribute_not_exists, begins_with,
+// contains, size, AND, OR and NOT. Other functions return an UnsupportedError.
+func Evaluate(c expression.ConditionBuilder, itm map[string]*dynamodb.AttributeValue) (ok bool, err error) {
+	expr, err := expression.NewBuilder().WithCondition(c).Build()
+	if err != nil {
+		err = fmt.Errorf("failed to build condition: %v", err)
+		return
+	}
+	p := &conditionParser{
+		condition: *expr.Condition(),
+		tokens:    tokenize(*expr.Condition()),
+		names:     expr.Names(),
+		values:    expr.Values(),
+		item:      itm,
+	}
+	ok, err = p.or()
+	if err == nil && p.pos < len(p.tokens) {
+		err = p.unsupported("unexpected " + p.tokens[p.pos])
+	}
+	return
+}
+
+// tokenize splits a condition expression into names, values, keywords, functions and punctuation.
+func tokenize(s string) (tokens []string) {
+	for i := 0; i < len(s); {
+		r := rune(s[i])
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case strings.ContainsRune("(),", r):
+			tokens = append(tokens, string(r))
+			i++
+		case strings.ContainsRune("<>=", r):
+			j := i + 1
+			for j < len(s) && strings.ContainsRune("<>=", rune(s[j])) {
+				j++
+			}
+			tokens = append(tokens, s[i:j])
+			i = j
+		default:
+			j := i + 1
+			for j < len(s) && !unicode.IsSpace(rune(s[j])) && !strings.ContainsRune("(),<>=", rune(s[j])) {
+				j++
+			}
+			tokens = append(tokens, s[i:j])
+			i = j
+		}
+	}
+	return
+}
+
+// conditionParser evaluates a condition expression while parsing it, using the grammar of DynamoDB
+// condition expressions.
+type conditionParser struct {
+	condition string
+	tokens    []string
+	pos       int
+	names     map[string]*string
+	values    map[string]*dynamodb.AttributeValue
+	item      map[string]*dynamodb.AttributeValue
+}
+
+func (p *conditionParser) unsupported(reason string) error {
+	return UnsupportedError{Condition: p.condition, Reason: reason}
+}
+
+func (p *conditionParser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+func (p *conditionParser) next() (t string) {
+	t = p.peek()
+	p.pos++
+	return
+}
+
+func (p *conditionParser) expect(t string) error {
+	if actual := p.next(); actual != t {
+		return p.unsupported(fmt.Sprintf("expected %q, got %q", t, actual))
+	}
+	return nil
+}
+
+func (p *conditionParser) or() (ok bool, err error) {
+	if ok, err = p.and(); err != nil {
+		return
+	}
+	for strings.EqualFold(p.peek(), "OR") {
+		p.next()
+		right, rErr := p.and()
+		if rErr != nil {
+			return false, rErr
+		}
+		ok = ok || right
+	}
+	return
+}
+
+func (p *conditionParser) and() (ok bool, err error) {
+	if ok, err = p.not(); err != nil {
+		return
+	}
+	for strings.EqualFold(p.peek(), "AND") {
+		p.next()
+		right, rErr := p.not()
+		if rErr != nil {
+			return false, rErr
+		}
+		ok = ok && right
+	}
+	return
+}
+
+func (p *conditionParser) not() (ok bool, err error) {
+	if strings.EqualFold(p.peek(), "NOT") {
+		p.next()
+		ok, err = p.not()
+		return !ok, err
+	}
+	return p.predicate()
+}
+
+func (p *conditionParser) predicate() (ok bool, err error) {
+	switch t := p.peek(); strings.ToLower(t) {
+	case "(":
+		p.next()
+		if ok, err = p.or(); err != nil {
+			return
+		}
+		err = p.expect(")")
+		return
+	case "attribute_exists", "attribute_not_exists", "begins_with", "contains":
+		p.next()
+		args, aErr := p.arguments()
+		if aErr != nil {
+			return false, aErr
+		}
+		return p.function(strings.ToLower(t), args)
+	}
+	left, err := p.operand()
+	if err != nil {
+		return
+	}
+	switch op := strings.ToUpper(p.next()); op {
+	case "=", "<>", "<", "<=", ">", ">=":
+		right, rErr := p.operand()
+		if rErr != nil {
+			return false, rErr
+		}
+		return comparison(op, left, right), nil
+	case "BETWEEN":
+		lower, lErr := p.operand()
+		if lErr != nil {
+			return false, lErr
+		}
+		if err = p.expect("AND"); err != nil {
+			return
+		}
+		upper, uErr := p.operand()
+		if uErr != nil {
+			return false, uErr
+		}
+		return comparison(">=", left, lower) && comparison("<=", left, upper), nil
+	case "IN":
+		candidates, aErr := p.arguments()
+		if aErr != nil {
+			return false, aErr
+		}
+		for _, c := range candidates {
+			if comparison("=", left, c) {
+				return true, nil
+			}
+		}
+		return false, nil
+	default:
+		return false, p.unsupported("unknown operator " + op)
+	}
+}
+
+// arguments parses a parenthesised list of operands.
+func (p *conditionParser) arguments() (args []*dynamodb.AttributeValue, err error) {
+	if err = p.expect("("); err != nil {
+		return
+	}
+	for {
+		arg, oErr := p.operand()
+		if oErr != nil {
+			return nil, oErr
+		}
+		args = append(args, arg)
+		if p.peek() != "," {
+			break
+		}
+		p.next()
+	}
+	err = p.expect(")")
+	return
+}
+
+func (p *conditionParser) function(name string, args []*dynamodb.AttributeValue) (ok bool, err error) {
+	switch {
+	case name == "attribute_exists" && len(args) == 1:
+		return args[0] != nil, nil
+	case name == "attribute_not_exists" && len(args) == 1:
+		return args[0] == nil, nil
+	case name == "begins_with" && len(args) == 2:
+		return args[0] != nil && args[1] != nil && args[0].S != nil && args[1].S != nil &&
+			strings.HasPrefix(*args[0].S, *args[1].S), nil
+	case name == "contains" && len(args) == 2:
+		return contains(args[0], args[1]), nil
+	}
+	return false, p.unsupported(fmt.Sprintf("%s with %d arguments", name, len(args)))
+}
+
+func contains(v, element *dynamodb.AttributeValue) bool {
+	if v == nil || element == nil {
+		return false
+	}
+	if v.S != nil && element.S != nil {
+		return strings.Contains(*v.S, *element.S)
+	}
+	for _, s := range v.SS {
+		if element.S != nil && *s == *element.S {
+			return true
+		}
+	}
+	for _, n := range v.NS {
+		if element.N != nil && Compare(&dynamodb.AttributeValue{N: n}, element) == 0 {
+			return true
+		}
+	}
+	for _, e := range v.L {
+		if Equal(e, element) {
+			return true
+		}
+	}
+	return false
+}
+
+// operand returns the value of a name, path, value placeholder or size function. Missing attributes
+// are nil.
+func (p *conditionParser) operand() (v *dynamodb.AttributeValue, err error) {
+	t := p.next()
+	switch {
+	case strings.EqualFold(t, "size"):
+		args, aErr := p.arguments()
+		if aErr != nil {
+			return nil, aErr
+		}
+		if len(args) != 1 {
+			return nil, p.unsupported("size requires one argument")
+		}
+		if args[0] == nil {
+			return nil, nil
+		}
+		n := strconv.Itoa(size(args[0]))
+		return &dynamodb.AttributeValue{N: &n}, nil
+	case strings.HasPrefix(t, ":"):
+		var ok bool
+		if v, ok = p.values[t]; !ok {
+			return nil, p.unsupported("unknown value " + t)
+		}
+		return v, nil
+	case strings.HasPrefix(t, "#"):
+		return p.path(t)
+	}
+	return nil, p.unsupported("unexpected " + t)
+}
+
+// path resolves a document path of names, e.g. #0.#1, within the item.
+func (p *conditionParser) path(t string) (v *dynamodb.AttributeValue, err error) {
+	attributes := p.item
+	for i, part := range strings.Split(t, ".") {
+		name, ok := p.names[part]
+		if !ok || name == nil {
+			return nil, p.unsupported("unknown name " + part)
+		}
+		if i > 0 {
+			if v == nil || v.M == nil {
+				return nil, nil
+			}
+			attributes = v.M
+		}
+		v = attributes[*name]
+	}
+	return
+}
+
+// comparison compares values in the same way as DynamoDB, where values of different types, and
+// missing values, are never equal.
+func comparison(op string, a, b *dynamodb.AttributeValue) bool {
+	if a == nil || b == nil {
+		return op == "<>" && (a != nil || b != nil)
+	}
+	if op == "=" {
+		return Equal(a, b)
+	}
+	if op == "<>" {
+		return !Equal(a, b)
+	}
+	sameType := (a.S != nil && b.S != nil) || (a.N != nil && b.N != nil) || (a.B != nil && b.B != nil)
+	if !sameType {
+		return false
+	}
+	c := Compare(a, b)
+	switch op {
+	case "<":
+		return c < 0
+	case "<=":
+		return c <= 0
+	case ">":
+		return c > 0
+	}
+	return c >= 0
+}
+
+// size returns the size of a value, as used by the size function.
+func size(v *dynamodb.AttributeValue) int {
+	switch {
+	case v.S != nil:
+		return len(*v.S)
+	case v.B != nil:
+		return len(v.B)
+	case v.SS != nil:
+		return len(v.SS)
+	case v.NS != nil:
+		return len(v.NS)
+	case v.BS != nil:
+		return len(v.BS)
+	case v.L != nil:
+		return len(v.L)
+	case v.M != nil:
+		return len(v.M)
+	}
+	return 0
+}
@@ -0,0 +1,63 @@
+package condition
+
+import (
+	"bytes"
+	"math/big"
+	"reflect"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// ScalarString returns a string which uniquely identifies a string, number or binary value, including
+// its type.
+func ScalarString(v *dynamodb.AttributeValue) string {
+	switch {
+	case v.S != nil:
+		return "S" + *v.S
+	case v.N != nil:
+		return "N" + *v.N
+	}
+	return "B" + string(v.B)
+}
+
+// KeyText returns the text of a string, number or binary value.
+func KeyText(v *dynamodb.AttributeValue) string {
+	switch {
+	case v.S != nil:
+		return *v.S
+	case v.N != nil:
+		return *v.N
+	}
+	return string(v.B)
+}
+
+// Compare orders scalar values in the same way as DynamoDB. Missing values come first.
+func Compare(a, b *dynamodb.AttributeValue) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return -1
+	case b == nil:
+		return 1
+	case a.N != nil && b.N != nil:
+		x, _ := new(big.Float).SetString(*a.N)
+		y, _ := new(big.Float).SetString(*b.N)
+		if x != nil && y != nil {
+			return x.Cmp(y)
+		}
+	case a.B != nil && b.B != nil:
+		return bytes.Compare(a.B, b.B)
+	}
+	return strings.Compare(ScalarString(a), ScalarString(b))
+}
+
+// Equal returns true if the values are equal. Numbers are equal if they have the same value, even if
+// they're formatted differently.
+func Equal(a, b *dynamodb.AttributeValue) bool {
+	if (a.N != nil && b.N != nil) || (a.B != nil && b.B != nil) || (a.S != nil && b.S != nil) {
+		return Compare(a, b) == 0
+	}
+	return reflect.DeepEqual(a, b)
+}
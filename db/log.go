@@ -0,0 +1,154 @@
+package db
+
+import (
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// Redacted replaces the values of redacted attributes in a LogEntry.
+const Redacted = "REDACTED"
+
+// DefaultLogAttributes are the attributes whose values are logged if the DB's LogAttributes aren't
+// set. They're the table's keys, which are needed to find hot partitions.
+var DefaultLogAttributes = []string{"id", "rng"}
+
+// Logger receives a LogEntry for each operation made by the DB, e.g. to write structured debug logs.
+type Logger interface {
+	Log(entry LogEntry)
+}
+
+// LoggerFunc is a function which implements Logger.
+type LoggerFunc func(entry LogEntry)
+
+// Log calls the function.
+func (f LoggerFunc) Log(entry LogEntry) {
+	f(entry)
+}
+
+// LogEntry describes an operation made by the DB.
+type LogEntry struct {
+	// Operation is the name of the DB method, e.g. "BatchPut" or "QueryPage".
+	Operation string
+	Table     string
+	// Index is the global secondary index used by the operation, if any.
+	Index string
+	// Keys are the keys or items written or read by the operation, or the conditions of queries and
+	// scans. The values of attributes which aren't in the DB's LogAttributes are replaced by Redacted,
+	// so that data isn't written to logs.
+	Keys []map[string]string
+	// Count is the number of items written or read.
+	Count            int
+	ConsumedCapacity ConsumedCapacity
+	Duration         time.Duration
+	Err              error
+}
+
+// logOperation passes an operation which started at the time to the Logger, which must be set.
+func (db *DB) logOperation(op, index string, start time.Time, keys []map[string]string, count int, cc ConsumedCapacity, err error) {
+	db.Logger.Log(LogEntry{
+		Operation:        op,
+		Table:            db.TableName,
+		Index:            index,
+		Keys:             db.redact(keys),
+		Count:            count,
+		ConsumedCapacity: cc,
+		Duration:         time.Since(start),
+		Err:              err,
+	})
+}
+
+// redact replaces the values of attributes which aren't logged.
+func (db *DB) redact(keys []map[string]string) (redacted []map[string]string) {
+	logged := db.LogAttributes
+	if logged == nil {
+		logged = DefaultLogAttributes
+	}
+	isLogged := make(map[string]bool, len(logged))
+	for _, a := range logged {
+		isLogged[a] = true
+	}
+	redacted = make([]map[string]string, len(keys))
+	for i, k := range keys {
+		redacted[i] = make(map[string]string, len(k))
+		for name, value := range k {
+			if !isLogged[name] {
+				value = Redacted
+			}
+			redacted[i][name] = value
+		}
+	}
+	return
+}
+
+// logItems formats the attributes of items for logging.
+func logItems(items ...map[string]*dynamodb.AttributeValue) (keys []map[string]string) {
+	keys = make([]map[string]string, 0, len(items))
+	for _, itm := range items {
+		if itm == nil {
+			continue
+		}
+		k := make(map[string]string, len(itm))
+		for name, av := range itm {
+			k[name] = formatAttributeValue(av)
+		}
+		keys = append(keys, k)
+	}
+	return
+}
+
+// formatAttributeValue formats scalar values. Other types are formatted as their names, since they're
+// not used as keys.
+func formatAttributeValue(av *dynamodb.AttributeValue) string {
+	switch {
+	case av == nil:
+		return ""
+	case av.S != nil:
+		return *av.S
+	case av.N != nil:
+		return *av.N
+	case av.BOOL != nil:
+		if *av.BOOL {
+			return "true"
+		}
+		return "false"
+	case av.NULL != nil:
+		return "null"
+	case av.B != nil:
+		return "(binary)"
+	case av.M != nil:
+		return "(map)"
+	case av.L != nil:
+		return "(list)"
+	}
+	return "(set)"
+}
+
+// countItem returns 1 if the item exists.
+func countItem(item map[string]*dynamodb.AttributeValue) int {
+	if item == nil {
+		return 0
+	}
+	return 1
+}
+
+// logTransactWriteItems formats the items of a transaction for logging.
+func logTransactWriteItems(items []TransactWriteItem) []map[string]string {
+	var records []map[string]*dynamodb.AttributeValue
+	for _, item := range items {
+		records = append(records, item.Put, item.Delete, item.ConditionCheck)
+	}
+	return logItems(records...)
+}
+
+// logConditions formats pairs of attribute names and values used by a query or scan for logging.
+// Attributes without names aren't used, so are skipped.
+func logConditions(namesAndValues ...string) []map[string]string {
+	k := make(map[string]string)
+	for i := 0; i+1 < len(namesAndValues); i += 2 {
+		if namesAndValues[i] != "" {
+			k[namesAndValues[i]] = namesAndValues[i+1]
+		}
+	}
+	return []map[string]string{k}
+}
@@ -0,0 +1,663 @@
+// Package memory provides an in-memory implementation of the database used by pregel.Store, so that
+// code which depends on a Store can be tested without DynamoDB.
+//
+//	s := pregel.NewStoreWithClient(memory.New())
+//
+// Items are ordered by their partition and sort keys, as in DynamoDB, so paging, range prefixes and
+// index queries behave in the same way. The limits of DynamoDB, such as the item size, the number of
+// items in a transaction, and the number of items in a batch write request, are enforced. Reads are
+// always consistent, including index queries.
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/a-h/pregel/db"
+	"github.com/a-h/pregel/db/internal/condition"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+)
+
+// ErrMissingKey is returned when an item or key doesn't have the partition and sort key attributes.
+var ErrMissingKey = errors.New("memory: the item is missing its key attributes")
+
+// ErrItemTooLarge is returned when an item is larger than db.MaxItemSize.
+var ErrItemTooLarge = fmt.Errorf("memory: items cannot be larger than %d bytes", db.MaxItemSize)
+
+// UnsupportedConditionError is returned when a condition uses a feature of DynamoDB condition
+// expressions which isn't supported.
+type UnsupportedConditionError = condition.UnsupportedError
+
+// ErrDuplicateKey is returned when a transaction, or a batch write request, contains more than one
+// operation on the same item.
+var ErrDuplicateKey = errors.New("memory: requests cannot contain multiple operations on the same item")
+
+// DB is an in-memory table, which implements the DB interface used by pregel.Store. It's safe for
+// concurrent use. The zero value isn't usable, use New.
+type DB struct {
+	// PartitionKey and SortKey are the names of the table's key attributes, which are "id" and "rng"
+	// by default.
+	PartitionKey string
+	SortKey      string
+	// PartitionKeyType is the type of the partition key returned by DescribeTable. Partition key
+	// values are matched by QueryByID and QueryPage regardless of their type. If empty, "S" is used.
+	PartitionKeyType string
+	// Indexes are the table's global secondary indexes, which are returned by DescribeTable. Index
+	// queries don't need the index to be defined, but items are ordered by the index's sort key if
+	// it is.
+	Indexes []db.TableIndex
+	// TTLAttribute is returned by DescribeTable. Expired items aren't deleted.
+	TTLAttribute string
+
+	m     sync.Mutex
+	items map[string]map[string]*dynamodb.AttributeValue
+}
+
+// New creates an empty in-memory table with the key schema used by pregel, and the indexes.
+func New(indexes ...db.TableIndex) *DB {
+	return &DB{
+		PartitionKey: "id",
+		SortKey:      "rng",
+		Indexes:      indexes,
+		items:        make(map[string]map[string]*dynamodb.AttributeValue),
+	}
+}
+
+// Items returns a copy of every item in the table, ordered by their keys.
+func (mdb *DB) Items() (items []map[string]*dynamodb.AttributeValue) {
+	mdb.m.Lock()
+	defer mdb.m.Unlock()
+	return mdb.sorted(func(itm map[string]*dynamodb.AttributeValue) bool { return true })
+}
+
+// keyOf returns a string which uniquely identifies the item with the key.
+func (mdb *DB) keyOf(key map[string]*dynamodb.AttributeValue) (k string, err error) {
+	pk, sk := key[mdb.PartitionKey], key[mdb.SortKey]
+	if pk == nil || sk == nil {
+		err = ErrMissingKey
+		return
+	}
+	k = condition.ScalarString(pk) + "\x00" + condition.ScalarString(sk)
+	return
+}
+
+// keyAttributes returns just the key attributes of the item.
+func (mdb *DB) keyAttributes(itm map[string]*dynamodb.AttributeValue, extra ...string) (key map[string]*dynamodb.AttributeValue) {
+	key = make(map[string]*dynamodb.AttributeValue, 2+len(extra))
+	for _, name := range append([]string{mdb.PartitionKey, mdb.SortKey}, extra...) {
+		if v, ok := itm[name]; ok {
+			key[name] = v
+		}
+	}
+	return
+}
+
+// copyItem copies the attributes of an item, so that changes to the returned map don't affect the
+// table. The attribute values themselves aren't copied, and mustn't be modified.
+func copyItem(itm map[string]*dynamodb.AttributeValue) (c map[string]*dynamodb.AttributeValue) {
+	if itm == nil {
+		return nil
+	}
+	c = make(map[string]*dynamodb.AttributeValue, len(itm))
+	for k, v := range itm {
+		c[k] = v
+	}
+	return
+}
+
+func (mdb *DB) put(itm map[string]*dynamodb.AttributeValue) (cc db.ConsumedCapacity, err error) {
+	k, err := mdb.keyOf(itm)
+	if err != nil {
+		return
+	}
+	size := db.ItemSize(itm)
+	if size > db.MaxItemSize {
+		err = ErrItemTooLarge
+		return
+	}
+	mdb.items[k] = copyItem(itm)
+	cc = writeCapacity(size)
+	return
+}
+
+func (mdb *DB) delete(key map[string]*dynamodb.AttributeValue) (cc db.ConsumedCapacity, err error) {
+	k, err := mdb.keyOf(key)
+	if err != nil {
+		return
+	}
+	cc = writeCapacity(db.ItemSize(mdb.items[k]))
+	delete(mdb.items, k)
+	return
+}
+
+// writeCapacity returns the capacity used to write an item, of one unit per KB.
+func writeCapacity(size int) db.ConsumedCapacity {
+	units := float64((size + 1023) / 1024)
+	if units == 0 {
+		units = 1
+	}
+	return db.ConsumedCapacity{ConsumedCapacity: units, ConsumedWriteCapacity: units}
+}
+
+// readCapacity returns the capacity used to consistently read items, of one unit per 4KB.
+func readCapacity(items ...map[string]*dynamodb.AttributeValue) db.ConsumedCapacity {
+	var size int
+	for _, itm := range items {
+		size += db.ItemSize(itm)
+	}
+	units := float64((size + 4095) / 4096)
+	if units == 0 {
+		units = 1
+	}
+	return db.ConsumedCapacity{ConsumedCapacity: units, ConsumedReadCapacity: units}
+}
+
+func addCapacity(a, b db.ConsumedCapacity) db.ConsumedCapacity {
+	a.ConsumedCapacity += b.ConsumedCapacity
+	a.ConsumedReadCapacity += b.ConsumedReadCapacity
+	a.ConsumedWriteCapacity += b.ConsumedWriteCapacity
+	return a
+}
+
+// checkBatch returns an error if a batch write contains an item more than once. As with DB, batches
+// are split into requests of db.MaxBatchWriteItems items.
+func (mdb *DB) checkBatch(items []map[string]*dynamodb.AttributeValue) (err error) {
+	for start := 0; start < len(items); start += db.MaxBatchWriteItems {
+		end := start + db.MaxBatchWriteItems
+		if end > len(items) {
+			end = len(items)
+		}
+		if err = mdb.checkUnique(items[start:end]); err != nil {
+			return
+		}
+	}
+	return
+}
+
+func (mdb *DB) checkUnique(items []map[string]*dynamodb.AttributeValue) (err error) {
+	seen := make(map[string]bool, len(items))
+	for _, itm := range items {
+		k, kErr := mdb.keyOf(itm)
+		if kErr != nil {
+			return kErr
+		}
+		if seen[k] {
+			return ErrDuplicateKey
+		}
+		seen[k] = true
+	}
+	return
+}
+
+// BatchDelete deletes the items with the keys.
+func (mdb *DB) BatchDelete(ctx context.Context, keys []map[string]*dynamodb.AttributeValue) (cc db.ConsumedCapacity, err error) {
+	mdb.m.Lock()
+	defer mdb.m.Unlock()
+	if err = mdb.checkBatch(keys); err != nil {
+		return
+	}
+	for _, key := range keys {
+		deleteCC, dErr := mdb.delete(key)
+		if dErr != nil {
+			err = dErr
+			return
+		}
+		cc = addCapacity(cc, deleteCC)
+	}
+	return
+}
+
+// BatchPut puts the items into the table.
+func (mdb *DB) BatchPut(ctx context.Context, items []map[string]*dynamodb.AttributeValue) (cc db.ConsumedCapacity, err error) {
+	mdb.m.Lock()
+	defer mdb.m.Unlock()
+	if err = mdb.checkBatch(items); err != nil {
+		return
+	}
+	return mdb.putAll(items)
+}
+
+func (mdb *DB) putAll(items []map[string]*dynamodb.AttributeValue) (cc db.ConsumedCapacity, err error) {
+	for _, itm := range items {
+		if _, err = mdb.keyOf(itm); err != nil {
+			return
+		}
+		if db.ItemSize(itm) > db.MaxItemSize {
+			err = ErrItemTooLarge
+			return
+		}
+	}
+	for _, itm := range items {
+		putCC, _ := mdb.put(itm)
+		cc = addCapacity(cc, putCC)
+	}
+	return
+}
+
+// TryBatchPut puts up to db.MaxBatchWriteItems items into the table. Every item is processed.
+func (mdb *DB) TryBatchPut(ctx context.Context, items []map[string]*dynamodb.AttributeValue) (unprocessed []map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error) {
+	if len(items) > db.MaxBatchWriteItems {
+		err = fmt.Errorf("memory: batch write requests cannot contain more than %d items", db.MaxBatchWriteItems)
+		return
+	}
+	cc, err = mdb.BatchPut(ctx, items)
+	return
+}
+
+// PutIfNotExists puts the item into the table, unless an item with the same key already exists, in
+// which case db.ErrConditionalCheckFailed is returned.
+func (mdb *DB) PutIfNotExists(ctx context.Context, idField string, item map[string]*dynamodb.AttributeValue) (cc db.ConsumedCapacity, err error) {
+	mdb.m.Lock()
+	defer mdb.m.Unlock()
+	k, err := mdb.keyOf(item)
+	if err != nil {
+		return
+	}
+	if _, exists := mdb.items[k][idField]; exists {
+		err = db.ErrConditionalCheckFailed
+		return
+	}
+	return mdb.put(item)
+}
+
+// TransactPut puts all of the items, or none of them.
+func (mdb *DB) TransactPut(ctx context.Context, items []map[string]*dynamodb.AttributeValue) (cc db.ConsumedCapacity, err error) {
+	twis := make([]db.TransactWriteItem, len(items))
+	for i, item := range items {
+		twis[i] = db.TransactWriteItem{Put: item}
+	}
+	return mdb.TransactWrite(ctx, twis)
+}
+
+// TransactPutIfExists puts the items, if the item with the key exists and doesn't have the excluded
+// field. Otherwise, db.ErrConditionalCheckFailed is returned.
+func (mdb *DB) TransactPutIfExists(ctx context.Context, key map[string]*dynamodb.AttributeValue, idField, excludedField string, items []map[string]*dynamodb.AttributeValue) (cc db.ConsumedCapacity, err error) {
+	mdb.m.Lock()
+	defer mdb.m.Unlock()
+	if len(items)+1 > db.MaxTransactionItems {
+		err = db.ErrTooManyTransactionItems
+		return
+	}
+	if err = mdb.checkUnique(append([]map[string]*dynamodb.AttributeValue{key}, items...)); err != nil {
+		return
+	}
+	k, _ := mdb.keyOf(key)
+	existing := mdb.items[k]
+	_, hasID := existing[idField]
+	_, hasExcluded := existing[excludedField]
+	if !hasID || (excludedField != "" && hasExcluded) {
+		err = db.ErrConditionalCheckFailed
+		return
+	}
+	cc, err = mdb.putAll(items)
+	cc = addCapacity(cc, readCapacity(existing))
+	return
+}
+
+// TransactWrite makes all of the puts and deletes, if all of the conditions are met. Otherwise,
+// db.ErrConditionalCheckFailed is returned. Conditions can use comparisons, attribute_exists,
+// attribute_not_exists, begins_with, AND, OR and NOT. Other functions return an
+// UnsupportedConditionError.
+func (mdb *DB) TransactWrite(ctx context.Context, items []db.TransactWriteItem) (cc db.ConsumedCapacity, err error) {
+	if len(items) == 0 {
+		return
+	}
+	if len(items) > db.MaxTransactionItems {
+		err = db.ErrTooManyTransactionItems
+		return
+	}
+	mdb.m.Lock()
+	defer mdb.m.Unlock()
+	keys := make([]map[string]*dynamodb.AttributeValue, len(items))
+	for i, item := range items {
+		var set int
+		for _, m := range []map[string]*dynamodb.AttributeValue{item.Put, item.Delete, item.ConditionCheck} {
+			if m != nil {
+				keys[i] = m
+				set++
+			}
+		}
+		if set != 1 || (item.ConditionCheck != nil && item.Condition == nil) {
+			err = db.ErrInvalidTransactWriteItem
+			return
+		}
+	}
+	if err = mdb.checkUnique(keys); err != nil {
+		return
+	}
+	for i, item := range items {
+		if item.Put != nil && db.ItemSize(item.Put) > db.MaxItemSize {
+			err = ErrItemTooLarge
+			return
+		}
+		if item.Condition == nil {
+			continue
+		}
+		k, _ := mdb.keyOf(keys[i])
+		ok, cErr := condition.Evaluate(*item.Condition, mdb.items[k])
+		if cErr != nil {
+			err = cErr
+			return
+		}
+		if !ok {
+			err = db.ErrConditionalCheckFailed
+			return
+		}
+	}
+	for _, item := range items {
+		var itemCC db.ConsumedCapacity
+		switch {
+		case item.Put != nil:
+			itemCC, _ = mdb.put(item.Put)
+		case item.Delete != nil:
+			itemCC, _ = mdb.delete(item.Delete)
+		default:
+			k, _ := mdb.keyOf(item.ConditionCheck)
+			itemCC = readCapacity(mdb.items[k])
+		}
+		// Transactions use twice the capacity of other requests.
+		cc = addCapacity(cc, addCapacity(itemCC, itemCC))
+	}
+	return
+}
+
+// UpdateItem sets the attributes of the item with the key, creating the item if it doesn't exist.
+func (mdb *DB) UpdateItem(ctx context.Context, key map[string]*dynamodb.AttributeValue, set map[string]*dynamodb.AttributeValue) (cc db.ConsumedCapacity, err error) {
+	if len(set) == 0 {
+		return
+	}
+	mdb.m.Lock()
+	defer mdb.m.Unlock()
+	k, err := mdb.keyOf(key)
+	if err != nil {
+		return
+	}
+	itm := copyItem(mdb.items[k])
+	if itm == nil {
+		itm = mdb.keyAttributes(key)
+	}
+	for name, v := range set {
+		if name == mdb.PartitionKey || name == mdb.SortKey {
+			err = fmt.Errorf("memory: cannot update the key attribute %q", name)
+			return
+		}
+		itm[name] = v
+	}
+	return mdb.put(itm)
+}
+
+// GetItem returns the item with the key, or nil if it doesn't exist. If attributes are provided,
+// only those attributes are returned.
+func (mdb *DB) GetItem(ctx context.Context, key map[string]*dynamodb.AttributeValue, attributes ...string) (item map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error) {
+	mdb.m.Lock()
+	defer mdb.m.Unlock()
+	k, err := mdb.keyOf(key)
+	if err != nil {
+		return
+	}
+	item = copyItem(mdb.items[k])
+	cc = readCapacity(item)
+	item = projectItem(item, attributes)
+	return
+}
+
+// BatchGet returns the items with the keys which exist.
+func (mdb *DB) BatchGet(ctx context.Context, keys []map[string]*dynamodb.AttributeValue) (items []map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error) {
+	mdb.m.Lock()
+	defer mdb.m.Unlock()
+	for start := 0; start < len(keys); start += db.MaxBatchGetItems {
+		end := start + db.MaxBatchGetItems
+		if end > len(keys) {
+			end = len(keys)
+		}
+		if err = mdb.checkUnique(keys[start:end]); err != nil {
+			return
+		}
+	}
+	for _, key := range keys {
+		k, _ := mdb.keyOf(key)
+		if itm, ok := mdb.items[k]; ok {
+			items = append(items, copyItem(itm))
+		}
+	}
+	cc = readCapacity(items...)
+	return
+}
+
+// sorted returns copies of the items which match, ordered by their keys.
+func (mdb *DB) sorted(match func(itm map[string]*dynamodb.AttributeValue) bool) (items []map[string]*dynamodb.AttributeValue) {
+	for _, itm := range mdb.items {
+		if match(itm) {
+			items = append(items, copyItem(itm))
+		}
+	}
+	sort.Slice(items, func(i, j int) bool {
+		return mdb.compareKeys(items[i], items[j]) < 0
+	})
+	return
+}
+
+// compareKeys orders items by their partition key, then their sort key.
+func (mdb *DB) compareKeys(a, b map[string]*dynamodb.AttributeValue) int {
+	if c := condition.Compare(a[mdb.PartitionKey], b[mdb.PartitionKey]); c != 0 {
+		return c
+	}
+	return condition.Compare(a[mdb.SortKey], b[mdb.SortKey])
+}
+
+// page returns up to limit of the sorted items which follow the start key, and the key of the last
+// item, if there are more items.
+func page(items []map[string]*dynamodb.AttributeValue, limit int64, after func(itm map[string]*dynamodb.AttributeValue) bool, key func(itm map[string]*dynamodb.AttributeValue) map[string]*dynamodb.AttributeValue) (evaluated []map[string]*dynamodb.AttributeValue, lastEvaluatedKey map[string]*dynamodb.AttributeValue) {
+	for _, itm := range items {
+		if after != nil && !after(itm) {
+			continue
+		}
+		if limit > 0 && int64(len(evaluated)) == limit {
+			lastEvaluatedKey = key(evaluated[len(evaluated)-1])
+			return
+		}
+		evaluated = append(evaluated, itm)
+	}
+	return
+}
+
+// afterKey returns a function which is true for items that follow the start key in the table's order.
+func (mdb *DB) afterKey(startKey map[string]*dynamodb.AttributeValue) func(itm map[string]*dynamodb.AttributeValue) bool {
+	if startKey == nil {
+		return nil
+	}
+	return func(itm map[string]*dynamodb.AttributeValue) bool {
+		return mdb.compareKeys(itm, startKey) > 0
+	}
+}
+
+func (mdb *DB) tableKey(itm map[string]*dynamodb.AttributeValue) map[string]*dynamodb.AttributeValue {
+	return mdb.keyAttributes(itm)
+}
+
+// QueryByID returns the items with the partition key value, ordered by their sort key. If attributes
+// are provided, only those attributes are returned.
+func (mdb *DB) QueryByID(ctx context.Context, field, value string, attributes ...string) (items []map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error) {
+	return mdb.QueryByIDAndRangePrefix(ctx, field, value, mdb.SortKey, "", attributes...)
+}
+
+// QueryByIDAndRangePrefix returns the items with the partition key value whose sort key begins with
+// the range prefix, ordered by their sort key. If attributes are provided, only those attributes are
+// returned.
+func (mdb *DB) QueryByIDAndRangePrefix(ctx context.Context, idField, idValue, rangeField, rangePrefix string, attributes ...string) (items []map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error) {
+	items, _, cc, err = mdb.QueryPage(ctx, idField, idValue, rangeField, rangePrefix, 0, nil)
+	if err != nil || len(attributes) == 0 {
+		return
+	}
+	for i, itm := range items {
+		items[i] = projectItem(itm, attributes)
+	}
+	return
+}
+
+// projectItem returns just the attributes of the item, or the whole item if no attributes are
+// provided.
+func projectItem(itm map[string]*dynamodb.AttributeValue, attributes []string) map[string]*dynamodb.AttributeValue {
+	if itm == nil || len(attributes) == 0 {
+		return itm
+	}
+	projected := make(map[string]*dynamodb.AttributeValue, len(attributes))
+	for _, a := range attributes {
+		if v, ok := itm[a]; ok {
+			projected[a] = v
+		}
+	}
+	return projected
+}
+
+// QueryPage returns a page of the items with the partition key value whose sort key begins with the
+// range prefix.
+func (mdb *DB) QueryPage(ctx context.Context, idField, idValue, rangeField, rangePrefix string, limit int64, startKey map[string]*dynamodb.AttributeValue) (items []map[string]*dynamodb.AttributeValue, lastEvaluatedKey map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error) {
+	if idField != mdb.PartitionKey {
+		err = fmt.Errorf("memory: %q is not the partition key", idField)
+		return
+	}
+	mdb.m.Lock()
+	defer mdb.m.Unlock()
+	matches := mdb.sorted(func(itm map[string]*dynamodb.AttributeValue) bool {
+		id, rng := itm[idField], itm[rangeField]
+		return id != nil && condition.KeyText(id) == idValue &&
+			(rangePrefix == "" || (rng != nil && rng.S != nil && strings.HasPrefix(*rng.S, rangePrefix)))
+	})
+	items, lastEvaluatedKey = page(matches, limit, mdb.afterKey(startKey), mdb.tableKey)
+	cc = readCapacity(items...)
+	return
+}
+
+// ScanPage returns a page of the items in the table where the field is equal to the value. As with
+// DynamoDB, the limit is the number of items evaluated, before the filter is applied.
+func (mdb *DB) ScanPage(ctx context.Context, field, value string, limit int64, startKey map[string]*dynamodb.AttributeValue) (items []map[string]*dynamodb.AttributeValue, lastEvaluatedKey map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error) {
+	mdb.m.Lock()
+	defer mdb.m.Unlock()
+	all := mdb.sorted(func(itm map[string]*dynamodb.AttributeValue) bool { return true })
+	evaluated, lastEvaluatedKey := page(all, limit, mdb.afterKey(startKey), mdb.tableKey)
+	for _, itm := range evaluated {
+		if v := itm[field]; v != nil && v.S != nil && *v.S == value {
+			items = append(items, itm)
+		}
+	}
+	cc = readCapacity(evaluated...)
+	return
+}
+
+// ScanSegment returns a page of the items in a segment of the table. Items are assigned to segments
+// by a hash of their partition key.
+func (mdb *DB) ScanSegment(ctx context.Context, segment, totalSegments, limit int64, startKey map[string]*dynamodb.AttributeValue) (items []map[string]*dynamodb.AttributeValue, lastEvaluatedKey map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error) {
+	if totalSegments < 1 || segment < 0 || segment >= totalSegments {
+		err = fmt.Errorf("memory: invalid segment %d of %d", segment, totalSegments)
+		return
+	}
+	mdb.m.Lock()
+	defer mdb.m.Unlock()
+	inSegment := mdb.sorted(func(itm map[string]*dynamodb.AttributeValue) bool {
+		h := fnv.New32a()
+		h.Write([]byte(condition.ScalarString(itm[mdb.PartitionKey])))
+		return int64(h.Sum32()%uint32(totalSegments)) == segment
+	})
+	items, lastEvaluatedKey = page(inSegment, limit, mdb.afterKey(startKey), mdb.tableKey)
+	cc = readCapacity(items...)
+	return
+}
+
+// indexSortKey returns the name of the sort key of the index, if it's defined.
+func (mdb *DB) indexSortKey(indexName string) string {
+	for _, index := range mdb.Indexes {
+		if index.Name == indexName {
+			return index.SortKey
+		}
+	}
+	return ""
+}
+
+// queryIndex returns the items where the field is equal to the value, ordered by the index's sort
+// key, then the table's keys.
+func (mdb *DB) queryIndex(indexName, field string, value interface{}) (items []map[string]*dynamodb.AttributeValue, compareItems func(a, b map[string]*dynamodb.AttributeValue) int, err error) {
+	v, err := dynamodbattribute.Marshal(value)
+	if err != nil {
+		return
+	}
+	sortKey := mdb.indexSortKey(indexName)
+	compareItems = func(a, b map[string]*dynamodb.AttributeValue) int {
+		if sortKey != "" {
+			if c := condition.Compare(a[sortKey], b[sortKey]); c != 0 {
+				return c
+			}
+		}
+		return mdb.compareKeys(a, b)
+	}
+	items = mdb.sorted(func(itm map[string]*dynamodb.AttributeValue) bool {
+		iv, ok := itm[field]
+		// Items without the index's sort key aren't in the index.
+		_, hasSortKey := itm[sortKey]
+		return ok && condition.Equal(iv, v) && (sortKey == "" || hasSortKey)
+	})
+	sort.SliceStable(items, func(i, j int) bool { return compareItems(items[i], items[j]) < 0 })
+	return
+}
+
+// QueryIndex returns the items where the field, which is the partition key of the index, is equal to
+// the value.
+func (mdb *DB) QueryIndex(ctx context.Context, indexName, field string, value interface{}) (items []map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error) {
+	mdb.m.Lock()
+	defer mdb.m.Unlock()
+	items, _, err = mdb.queryIndex(indexName, field, value)
+	cc = readCapacity(items...)
+	return
+}
+
+// QueryIndexPage returns a page of the items where the field, which is the partition key of the
+// index, is equal to the value. The last evaluated key contains the table's keys and the index's keys.
+func (mdb *DB) QueryIndexPage(ctx context.Context, indexName, field string, value interface{}, limit int64, startKey map[string]*dynamodb.AttributeValue) (items []map[string]*dynamodb.AttributeValue, lastEvaluatedKey map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error) {
+	mdb.m.Lock()
+	defer mdb.m.Unlock()
+	matches, compareItems, err := mdb.queryIndex(indexName, field, value)
+	if err != nil {
+		return
+	}
+	var after func(itm map[string]*dynamodb.AttributeValue) bool
+	if startKey != nil {
+		after = func(itm map[string]*dynamodb.AttributeValue) bool { return compareItems(itm, startKey) > 0 }
+	}
+	extra := []string{field}
+	if sortKey := mdb.indexSortKey(indexName); sortKey != "" {
+		extra = append(extra, sortKey)
+	}
+	items, lastEvaluatedKey = page(matches, limit, after, func(itm map[string]*dynamodb.AttributeValue) map[string]*dynamodb.AttributeValue {
+		return mdb.keyAttributes(itm, extra...)
+	})
+	cc = readCapacity(items...)
+	return
+}
+
+// DescribeTable describes the table's key schema, indexes and Time to Live attribute.
+func (mdb *DB) DescribeTable(ctx context.Context) (td db.TableDescription, err error) {
+	td = db.TableDescription{
+		Status:           dynamodb.TableStatusActive,
+		PartitionKeyName: mdb.PartitionKey,
+		PartitionKeyType: mdb.PartitionKeyType,
+		SortKeyName:      mdb.SortKey,
+		SortKeyType:      dynamodb.ScalarAttributeTypeS,
+		TTLAttribute:     mdb.TTLAttribute,
+	}
+	if td.PartitionKeyType == "" {
+		td.PartitionKeyType = dynamodb.ScalarAttributeTypeS
+	}
+	for _, index := range mdb.Indexes {
+		if index.PartitionKeyType == "" {
+			index.PartitionKeyType = dynamodb.ScalarAttributeTypeS
+		}
+		td.Indexes = append(td.Indexes, index)
+	}
+	return
+}
@@ -0,0 +1,20 @@
+package db
+
+import "time"
+
+// Metrics observes the calls made to DynamoDB, e.g. to record latency histograms and error counts in
+// a monitoring system.
+type Metrics interface {
+	// ObserveCall is called once for each DynamoDB call, after any retries. op is the name of the
+	// DynamoDB operation, e.g. "Query" or "BatchWriteItem", and duration includes the time spent
+	// retrying. cc is the capacity consumed by the last attempt, and err is the error it returned.
+	ObserveCall(op, table string, duration time.Duration, retries int, cc ConsumedCapacity, err error)
+}
+
+// observe passes a call to the Metrics, if there are any.
+func (db *DB) observe(op string, duration time.Duration, retries int, cc ConsumedCapacity, err error) {
+	if db.Metrics == nil {
+		return
+	}
+	db.Metrics.ObserveCall(op, db.TableName, duration, retries, cc, err)
+}
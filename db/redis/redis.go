@@ -0,0 +1,994 @@
+// Package redis provides an implementation of the database used by pregel.Store which is backed by
+// Redis, as a low latency store for graphs which are ephemeral, or used as a cache.
+//
+//	s := pregel.NewStoreWithClient(redis.New(func(ctx context.Context) (redis.Conn, error) {
+//		return pool.GetContext(ctx)
+//	}))
+//
+// The records of each node are stored in a hash, with a field for each range, and a sorted set of
+// the ranges, so that the records of a node, e.g. its edges, can be read in order, and paged. A sorted
+// set of the node IDs is used to scan the table, and each of the Indexes is a sorted set for each of
+// its partition key values. Writes use optimistic locking (WATCH, MULTI and EXEC), so that each write
+// is atomic, and conditions are checked against the items being written.
+//
+// Redis doesn't have capacity units, so the consumed capacity of each operation is zero.
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"strings"
+
+	"github.com/a-h/pregel/db"
+	"github.com/a-h/pregel/db/internal/condition"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+)
+
+// Conn is a connection to Redis, e.g. a redigo redis.Conn.
+type Conn interface {
+	Do(command string, args ...interface{}) (reply interface{}, err error)
+	Close() error
+}
+
+// Dialer returns a connection to Redis, e.g. from a pool. Each operation uses a single connection,
+// which is closed when the operation is complete.
+type Dialer func(ctx context.Context) (Conn, error)
+
+// ErrMissingKey is returned when an item or key doesn't have the partition and sort key attributes.
+var ErrMissingKey = errors.New("redis: the item is missing its key attributes")
+
+// ErrItemTooLarge is returned when an item is larger than db.MaxItemSize.
+var ErrItemTooLarge = fmt.Errorf("redis: items cannot be larger than %d bytes", db.MaxItemSize)
+
+// ErrDuplicateKey is returned when a transaction, or a batch write request, contains more than one
+// operation on the same item.
+var ErrDuplicateKey = errors.New("redis: requests cannot contain multiple operations on the same item")
+
+// ErrConflict is returned when a write is repeatedly prevented by other clients writing to the same
+// nodes.
+var ErrConflict = errors.New("redis: the write conflicted with other writes")
+
+// UnknownIndexError is returned when an index is queried which isn't one of the DB's Indexes.
+type UnknownIndexError struct {
+	Name string
+}
+
+func (err UnknownIndexError) Error() string {
+	return fmt.Sprintf("redis: unknown index %q", err.Name)
+}
+
+// Defaults of the DB created by New.
+const (
+	DefaultPrefix      = "pregel:"
+	DefaultMaxAttempts = 10
+)
+
+// scanBatchSize is the number of node IDs read at a time by scans.
+const scanBatchSize = 100
+
+// DB stores the items of a table in Redis, and implements the DB interface used by pregel.Store. It's
+// safe for concurrent use, if the Dialer is. The zero value isn't usable, use New.
+type DB struct {
+	// Dial returns a connection to Redis.
+	Dial Dialer
+	// Prefix is added to the name of every Redis key, so that multiple tables can share a database.
+	Prefix string
+	// PartitionKey and SortKey are the names of the table's key attributes, which are "id" and "rng"
+	// by default.
+	PartitionKey string
+	SortKey      string
+	// PartitionKeyType is the type of the partition key returned by DescribeTable. If empty, "S" is
+	// used.
+	PartitionKeyType string
+	// Indexes are the global secondary indexes which are maintained when items are written. Items are
+	// ordered within an index by the text of the index's sort key, so numeric sort keys aren't ordered
+	// by their value. Indexes must be defined before any items are written.
+	Indexes []db.TableIndex
+	// TTLAttribute is returned by DescribeTable. Expired items aren't deleted.
+	TTLAttribute string
+	// MaxAttempts is the number of times a write is attempted when other clients write to the same
+	// nodes at the same time, before ErrConflict is returned.
+	MaxAttempts int
+}
+
+// New creates a DB which stores the items of a table in Redis, with the key schema used by pregel,
+// and the indexes.
+func New(dial Dialer, indexes ...db.TableIndex) *DB {
+	return &DB{
+		Dial:         dial,
+		Prefix:       DefaultPrefix,
+		PartitionKey: "id",
+		SortKey:      "rng",
+		Indexes:      indexes,
+		MaxAttempts:  DefaultMaxAttempts,
+	}
+}
+
+// itemKey is the partition and sort key of an item, as text.
+type itemKey struct {
+	pk, sk string
+}
+
+func (rdb *DB) keyOf(key map[string]*dynamodb.AttributeValue) (k itemKey, err error) {
+	pk, sk := key[rdb.PartitionKey], key[rdb.SortKey]
+	if pk == nil || sk == nil {
+		err = ErrMissingKey
+		return
+	}
+	k = itemKey{pk: condition.KeyText(pk), sk: condition.KeyText(sk)}
+	return
+}
+
+func (rdb *DB) itemsKey(pk string) string {
+	return rdb.Prefix + "items:" + pk
+}
+
+func (rdb *DB) rangesKey(pk string) string {
+	return rdb.Prefix + "ranges:" + pk
+}
+
+func (rdb *DB) idsKey() string {
+	return rdb.Prefix + "ids"
+}
+
+func (rdb *DB) indexKey(indexName, value string) string {
+	return rdb.Prefix + "index:" + indexName + ":" + value
+}
+
+// keyAttributes returns just the key attributes of the item.
+func (rdb *DB) keyAttributes(itm map[string]*dynamodb.AttributeValue, extra ...string) (key map[string]*dynamodb.AttributeValue) {
+	key = make(map[string]*dynamodb.AttributeValue, 2+len(extra))
+	for _, name := range append([]string{rdb.PartitionKey, rdb.SortKey}, extra...) {
+		if v, ok := itm[name]; ok {
+			key[name] = v
+		}
+	}
+	return
+}
+
+func (rdb *DB) index(name string) (index db.TableIndex, ok bool) {
+	for _, index = range rdb.Indexes {
+		if index.Name == name {
+			return index, true
+		}
+	}
+	return
+}
+
+func isScalar(v *dynamodb.AttributeValue) bool {
+	return v != nil && (v.S != nil || v.N != nil || v.B != nil)
+}
+
+// indexMember returns the member of the index's sorted set which refers to the item. Items without
+// the index's keys aren't in the index.
+func (rdb *DB) indexMember(index db.TableIndex, itm map[string]*dynamodb.AttributeValue) (member string, ok bool) {
+	var sortText string
+	if index.SortKey != "" {
+		sv := itm[index.SortKey]
+		if !isScalar(sv) {
+			return
+		}
+		sortText = condition.KeyText(sv)
+	}
+	k, err := rdb.keyOf(itm)
+	if err != nil {
+		return
+	}
+	return sortText + "\x00" + k.pk + "\x00" + k.sk, true
+}
+
+// indexEntry is a member of the sorted set of an index.
+type indexEntry struct {
+	key, member string
+}
+
+func (rdb *DB) indexEntries(itm map[string]*dynamodb.AttributeValue) (entries []indexEntry) {
+	for _, index := range rdb.Indexes {
+		pv := itm[index.PartitionKey]
+		if !isScalar(pv) {
+			continue
+		}
+		if member, ok := rdb.indexMember(index, itm); ok {
+			entries = append(entries, indexEntry{key: rdb.indexKey(index.Name, condition.KeyText(pv)), member: member})
+		}
+	}
+	return
+}
+
+func (rdb *DB) conn(ctx context.Context) (c Conn, err error) {
+	c, err = rdb.Dial(ctx)
+	if err != nil {
+		err = fmt.Errorf("redis: failed to connect: %v", err)
+	}
+	return
+}
+
+func (rdb *DB) maxAttempts() int {
+	if rdb.MaxAttempts < 1 {
+		return 1
+	}
+	return rdb.MaxAttempts
+}
+
+func bulk(reply interface{}) (b []byte, ok bool) {
+	switch v := reply.(type) {
+	case []byte:
+		return v, true
+	case string:
+		return []byte(v), true
+	}
+	return nil, false
+}
+
+// strs returns the values of an array reply as strings.
+func strs(reply interface{}, err error) (values []string, rErr error) {
+	if err != nil {
+		return nil, err
+	}
+	arr, ok := reply.([]interface{})
+	if !ok && reply != nil {
+		return nil, fmt.Errorf("redis: unexpected reply of type %T", reply)
+	}
+	values = make([]string, 0, len(arr))
+	for _, v := range arr {
+		b, _ := bulk(v)
+		values = append(values, string(b))
+	}
+	return
+}
+
+func integer(reply interface{}, err error) (n int64, rErr error) {
+	if err != nil {
+		return 0, err
+	}
+	n, ok := reply.(int64)
+	if !ok {
+		return 0, fmt.Errorf("redis: unexpected reply of type %T", reply)
+	}
+	return
+}
+
+func decode(reply interface{}) (itm map[string]*dynamodb.AttributeValue, err error) {
+	b, ok := bulk(reply)
+	if !ok {
+		return
+	}
+	if err = json.Unmarshal(b, &itm); err != nil {
+		err = fmt.Errorf("redis: failed to decode item: %v", err)
+	}
+	return
+}
+
+// getItems returns the items of a node with the sort keys, skipping those which don't exist.
+func (rdb *DB) getItems(c Conn, pk string, sks []string) (items []map[string]*dynamodb.AttributeValue, err error) {
+	if len(sks) == 0 {
+		return
+	}
+	args := []interface{}{rdb.itemsKey(pk)}
+	for _, sk := range sks {
+		args = append(args, sk)
+	}
+	reply, err := c.Do("HMGET", args...)
+	if err != nil {
+		return
+	}
+	values, _ := reply.([]interface{})
+	for _, v := range values {
+		itm, dErr := decode(v)
+		if dErr != nil {
+			return nil, dErr
+		}
+		if itm != nil {
+			items = append(items, itm)
+		}
+	}
+	return
+}
+
+func (rdb *DB) getItem(c Conn, k itemKey) (itm map[string]*dynamodb.AttributeValue, err error) {
+	reply, err := c.Do("HGET", rdb.itemsKey(k.pk), k.sk)
+	if err != nil {
+		return
+	}
+	return decode(reply)
+}
+
+// operation puts or deletes a single item.
+type operation struct {
+	key itemKey
+	// put is the item to put, or nil to delete the item.
+	put map[string]*dynamodb.AttributeValue
+}
+
+// write reads the items with the keys, and makes the operations returned by plan atomically. The
+// operations must only write items with the keys. If another client writes to the same nodes before
+// the operations are made, the items are read again, and plan is called again.
+func (rdb *DB) write(ctx context.Context, keys []itemKey, plan func(existing map[itemKey]map[string]*dynamodb.AttributeValue) ([]operation, error)) (err error) {
+	c, err := rdb.conn(ctx)
+	if err != nil {
+		return
+	}
+	defer c.Close()
+	for attempt := 0; attempt < rdb.maxAttempts(); attempt++ {
+		if err = ctx.Err(); err != nil {
+			return
+		}
+		var ok bool
+		if ok, err = rdb.tryWrite(c, keys, plan); err != nil || ok {
+			return
+		}
+	}
+	return ErrConflict
+}
+
+func (rdb *DB) tryWrite(c Conn, keys []itemKey, plan func(existing map[itemKey]map[string]*dynamodb.AttributeValue) ([]operation, error)) (ok bool, err error) {
+	var pks []string
+	seen := make(map[string]bool)
+	watch := []interface{}{}
+	for _, k := range keys {
+		if !seen[k.pk] {
+			seen[k.pk] = true
+			pks = append(pks, k.pk)
+			watch = append(watch, rdb.itemsKey(k.pk), rdb.rangesKey(k.pk))
+		}
+	}
+	if len(watch) == 0 {
+		return true, nil
+	}
+	if _, err = c.Do("WATCH", watch...); err != nil {
+		return
+	}
+	defer func() {
+		if err != nil {
+			c.Do("UNWATCH")
+		}
+	}()
+	existing := make(map[itemKey]map[string]*dynamodb.AttributeValue, len(keys))
+	for _, k := range keys {
+		if existing[k], err = rdb.getItem(c, k); err != nil {
+			return
+		}
+	}
+	counts := make(map[string]int64, len(pks))
+	for _, pk := range pks {
+		if counts[pk], err = integer(c.Do("ZCARD", rdb.rangesKey(pk))); err != nil {
+			return
+		}
+	}
+	ops, err := plan(existing)
+	if err != nil {
+		return
+	}
+
+	var commands [][]interface{}
+	command := func(args ...interface{}) {
+		commands = append(commands, args)
+	}
+	written := make(map[string]bool)
+	for _, op := range ops {
+		old := existing[op.key]
+		for _, e := range rdb.indexEntries(old) {
+			command("ZREM", e.key, e.member)
+		}
+		if op.put == nil {
+			if old != nil {
+				command("HDEL", rdb.itemsKey(op.key.pk), op.key.sk)
+				command("ZREM", rdb.rangesKey(op.key.pk), op.key.sk)
+				counts[op.key.pk]--
+			}
+		} else {
+			b, mErr := json.Marshal(op.put)
+			if mErr != nil {
+				err = fmt.Errorf("redis: failed to encode item: %v", mErr)
+				return
+			}
+			command("HSET", rdb.itemsKey(op.key.pk), op.key.sk, b)
+			command("ZADD", rdb.rangesKey(op.key.pk), 0, op.key.sk)
+			for _, e := range rdb.indexEntries(op.put) {
+				command("ZADD", e.key, 0, e.member)
+			}
+			if old == nil {
+				counts[op.key.pk]++
+			}
+		}
+		existing[op.key] = op.put
+		written[op.key.pk] = true
+	}
+	for _, pk := range pks {
+		if !written[pk] {
+			continue
+		}
+		if counts[pk] > 0 {
+			command("ZADD", rdb.idsKey(), 0, pk)
+		} else {
+			command("ZREM", rdb.idsKey(), pk)
+		}
+	}
+
+	if _, err = c.Do("MULTI"); err != nil {
+		return
+	}
+	for _, args := range commands {
+		if _, err = c.Do(args[0].(string), args[1:]...); err != nil {
+			c.Do("DISCARD")
+			return
+		}
+	}
+	reply, err := c.Do("EXEC")
+	if err != nil {
+		return
+	}
+	// EXEC returns a nil reply if a watched key was changed.
+	return reply != nil, nil
+}
+
+// checkUnique returns an error if the keys contain an item more than once.
+func (rdb *DB) checkUnique(items []map[string]*dynamodb.AttributeValue) (keys []itemKey, err error) {
+	seen := make(map[itemKey]bool, len(items))
+	for _, itm := range items {
+		k, kErr := rdb.keyOf(itm)
+		if kErr != nil {
+			return nil, kErr
+		}
+		if seen[k] {
+			return nil, ErrDuplicateKey
+		}
+		seen[k] = true
+		keys = append(keys, k)
+	}
+	return
+}
+
+// checkBatch returns an error if a batch write contains an item more than once. As with db.DB,
+// batches are split into requests of db.MaxBatchWriteItems items.
+func (rdb *DB) checkBatch(items []map[string]*dynamodb.AttributeValue) (keys []itemKey, err error) {
+	for start := 0; start < len(items); start += db.MaxBatchWriteItems {
+		end := start + db.MaxBatchWriteItems
+		if end > len(items) {
+			end = len(items)
+		}
+		chunk, cErr := rdb.checkUnique(items[start:end])
+		if cErr != nil {
+			return nil, cErr
+		}
+		keys = append(keys, chunk...)
+	}
+	return
+}
+
+func checkSize(items ...map[string]*dynamodb.AttributeValue) error {
+	for _, itm := range items {
+		if db.ItemSize(itm) > db.MaxItemSize {
+			return ErrItemTooLarge
+		}
+	}
+	return nil
+}
+
+// BatchDelete deletes the items with the keys.
+func (rdb *DB) BatchDelete(ctx context.Context, keys []map[string]*dynamodb.AttributeValue) (cc db.ConsumedCapacity, err error) {
+	ks, err := rdb.checkBatch(keys)
+	if err != nil {
+		return
+	}
+	err = rdb.write(ctx, ks, func(existing map[itemKey]map[string]*dynamodb.AttributeValue) (ops []operation, err error) {
+		for _, k := range ks {
+			ops = append(ops, operation{key: k})
+		}
+		return
+	})
+	return
+}
+
+// BatchPut puts the items into the table.
+func (rdb *DB) BatchPut(ctx context.Context, items []map[string]*dynamodb.AttributeValue) (cc db.ConsumedCapacity, err error) {
+	ks, err := rdb.checkBatch(items)
+	if err != nil {
+		return
+	}
+	if err = checkSize(items...); err != nil {
+		return
+	}
+	err = rdb.write(ctx, ks, func(existing map[itemKey]map[string]*dynamodb.AttributeValue) (ops []operation, err error) {
+		for i, k := range ks {
+			ops = append(ops, operation{key: k, put: items[i]})
+		}
+		return
+	})
+	return
+}
+
+// TryBatchPut puts up to db.MaxBatchWriteItems items into the table. Every item is processed.
+func (rdb *DB) TryBatchPut(ctx context.Context, items []map[string]*dynamodb.AttributeValue) (unprocessed []map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error) {
+	if len(items) > db.MaxBatchWriteItems {
+		err = fmt.Errorf("redis: batch write requests cannot contain more than %d items", db.MaxBatchWriteItems)
+		return
+	}
+	cc, err = rdb.BatchPut(ctx, items)
+	return
+}
+
+// PutIfNotExists puts the item into the table, unless an item with the same key already exists, in
+// which case db.ErrConditionalCheckFailed is returned.
+func (rdb *DB) PutIfNotExists(ctx context.Context, idField string, item map[string]*dynamodb.AttributeValue) (cc db.ConsumedCapacity, err error) {
+	k, err := rdb.keyOf(item)
+	if err != nil {
+		return
+	}
+	if err = checkSize(item); err != nil {
+		return
+	}
+	err = rdb.write(ctx, []itemKey{k}, func(existing map[itemKey]map[string]*dynamodb.AttributeValue) (ops []operation, err error) {
+		if _, exists := existing[k][idField]; exists {
+			return nil, db.ErrConditionalCheckFailed
+		}
+		return []operation{{key: k, put: item}}, nil
+	})
+	return
+}
+
+// TransactPut puts all of the items, or none of them.
+func (rdb *DB) TransactPut(ctx context.Context, items []map[string]*dynamodb.AttributeValue) (cc db.ConsumedCapacity, err error) {
+	twis := make([]db.TransactWriteItem, len(items))
+	for i, item := range items {
+		twis[i] = db.TransactWriteItem{Put: item}
+	}
+	return rdb.TransactWrite(ctx, twis)
+}
+
+// TransactPutIfExists puts the items, if the item with the key exists and doesn't have the excluded
+// field. Otherwise, db.ErrConditionalCheckFailed is returned.
+func (rdb *DB) TransactPutIfExists(ctx context.Context, key map[string]*dynamodb.AttributeValue, idField, excludedField string, items []map[string]*dynamodb.AttributeValue) (cc db.ConsumedCapacity, err error) {
+	if len(items)+1 > db.MaxTransactionItems {
+		err = db.ErrTooManyTransactionItems
+		return
+	}
+	ks, err := rdb.checkUnique(append([]map[string]*dynamodb.AttributeValue{key}, items...))
+	if err != nil {
+		return
+	}
+	if err = checkSize(items...); err != nil {
+		return
+	}
+	err = rdb.write(ctx, ks, func(existing map[itemKey]map[string]*dynamodb.AttributeValue) (ops []operation, err error) {
+		_, hasID := existing[ks[0]][idField]
+		_, hasExcluded := existing[ks[0]][excludedField]
+		if !hasID || (excludedField != "" && hasExcluded) {
+			return nil, db.ErrConditionalCheckFailed
+		}
+		for i, k := range ks[1:] {
+			ops = append(ops, operation{key: k, put: items[i]})
+		}
+		return
+	})
+	return
+}
+
+// TransactWrite makes all of the puts and deletes, if all of the conditions are met. Otherwise,
+// db.ErrConditionalCheckFailed is returned. Conditions which use unsupported features of DynamoDB
+// condition expressions return an error.
+func (rdb *DB) TransactWrite(ctx context.Context, items []db.TransactWriteItem) (cc db.ConsumedCapacity, err error) {
+	if len(items) == 0 {
+		return
+	}
+	if len(items) > db.MaxTransactionItems {
+		err = db.ErrTooManyTransactionItems
+		return
+	}
+	keys := make([]map[string]*dynamodb.AttributeValue, len(items))
+	for i, item := range items {
+		var set int
+		for _, m := range []map[string]*dynamodb.AttributeValue{item.Put, item.Delete, item.ConditionCheck} {
+			if m != nil {
+				keys[i] = m
+				set++
+			}
+		}
+		if set != 1 || (item.ConditionCheck != nil && item.Condition == nil) {
+			err = db.ErrInvalidTransactWriteItem
+			return
+		}
+		if item.Put != nil {
+			if err = checkSize(item.Put); err != nil {
+				return
+			}
+		}
+	}
+	ks, err := rdb.checkUnique(keys)
+	if err != nil {
+		return
+	}
+	err = rdb.write(ctx, ks, func(existing map[itemKey]map[string]*dynamodb.AttributeValue) (ops []operation, err error) {
+		for i, item := range items {
+			if item.Condition == nil {
+				continue
+			}
+			ok, cErr := condition.Evaluate(*item.Condition, existing[ks[i]])
+			if cErr != nil {
+				return nil, cErr
+			}
+			if !ok {
+				return nil, db.ErrConditionalCheckFailed
+			}
+		}
+		for i, item := range items {
+			switch {
+			case item.Put != nil:
+				ops = append(ops, operation{key: ks[i], put: item.Put})
+			case item.Delete != nil:
+				ops = append(ops, operation{key: ks[i]})
+			}
+		}
+		return
+	})
+	return
+}
+
+// UpdateItem sets the attributes of the item with the key, creating the item if it doesn't exist.
+func (rdb *DB) UpdateItem(ctx context.Context, key map[string]*dynamodb.AttributeValue, set map[string]*dynamodb.AttributeValue) (cc db.ConsumedCapacity, err error) {
+	if len(set) == 0 {
+		return
+	}
+	k, err := rdb.keyOf(key)
+	if err != nil {
+		return
+	}
+	for name := range set {
+		if name == rdb.PartitionKey || name == rdb.SortKey {
+			err = fmt.Errorf("redis: cannot update the key attribute %q", name)
+			return
+		}
+	}
+	err = rdb.write(ctx, []itemKey{k}, func(existing map[itemKey]map[string]*dynamodb.AttributeValue) (ops []operation, err error) {
+		itm := rdb.keyAttributes(key)
+		for name, v := range existing[k] {
+			itm[name] = v
+		}
+		for name, v := range set {
+			itm[name] = v
+		}
+		if err = checkSize(itm); err != nil {
+			return
+		}
+		return []operation{{key: k, put: itm}}, nil
+	})
+	return
+}
+
+// GetItem returns the item with the key, or nil if it doesn't exist. If attributes are provided,
+// only those attributes are returned.
+func (rdb *DB) GetItem(ctx context.Context, key map[string]*dynamodb.AttributeValue, attributes ...string) (item map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error) {
+	k, err := rdb.keyOf(key)
+	if err != nil {
+		return
+	}
+	c, err := rdb.conn(ctx)
+	if err != nil {
+		return
+	}
+	defer c.Close()
+	item, err = rdb.getItem(c, k)
+	item = projectItem(item, attributes)
+	return
+}
+
+// BatchGet returns the items with the keys which exist.
+func (rdb *DB) BatchGet(ctx context.Context, keys []map[string]*dynamodb.AttributeValue) (items []map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error) {
+	var ks []itemKey
+	for start := 0; start < len(keys); start += db.MaxBatchGetItems {
+		end := start + db.MaxBatchGetItems
+		if end > len(keys) {
+			end = len(keys)
+		}
+		chunk, cErr := rdb.checkUnique(keys[start:end])
+		if cErr != nil {
+			err = cErr
+			return
+		}
+		ks = append(ks, chunk...)
+	}
+	if len(ks) == 0 {
+		return
+	}
+	c, err := rdb.conn(ctx)
+	if err != nil {
+		return
+	}
+	defer c.Close()
+	for _, k := range ks {
+		itm, gErr := rdb.getItem(c, k)
+		if gErr != nil {
+			err = gErr
+			return
+		}
+		if itm != nil {
+			items = append(items, itm)
+		}
+	}
+	return
+}
+
+// QueryByID returns the items with the partition key value, ordered by their sort key. If attributes
+// are provided, only those attributes are returned.
+func (rdb *DB) QueryByID(ctx context.Context, field, value string, attributes ...string) (items []map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error) {
+	return rdb.QueryByIDAndRangePrefix(ctx, field, value, rdb.SortKey, "", attributes...)
+}
+
+// QueryByIDAndRangePrefix returns the items with the partition key value whose sort key begins with
+// the range prefix, ordered by their sort key. If attributes are provided, only those attributes are
+// returned.
+func (rdb *DB) QueryByIDAndRangePrefix(ctx context.Context, idField, idValue, rangeField, rangePrefix string, attributes ...string) (items []map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error) {
+	items, _, cc, err = rdb.QueryPage(ctx, idField, idValue, rangeField, rangePrefix, 0, nil)
+	if err != nil || len(attributes) == 0 {
+		return
+	}
+	for i, itm := range items {
+		items[i] = projectItem(itm, attributes)
+	}
+	return
+}
+
+// projectItem returns just the attributes of the item, or the whole item if no attributes are
+// provided.
+func projectItem(itm map[string]*dynamodb.AttributeValue, attributes []string) map[string]*dynamodb.AttributeValue {
+	if itm == nil || len(attributes) == 0 {
+		return itm
+	}
+	projected := make(map[string]*dynamodb.AttributeValue, len(attributes))
+	for _, a := range attributes {
+		if v, ok := itm[a]; ok {
+			projected[a] = v
+		}
+	}
+	return projected
+}
+
+// QueryPage returns a page of the items with the partition key value whose sort key begins with the
+// range prefix.
+func (rdb *DB) QueryPage(ctx context.Context, idField, idValue, rangeField, rangePrefix string, limit int64, startKey map[string]*dynamodb.AttributeValue) (items []map[string]*dynamodb.AttributeValue, lastEvaluatedKey map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error) {
+	if idField != rdb.PartitionKey {
+		err = fmt.Errorf("redis: %q is not the partition key", idField)
+		return
+	}
+	if rangePrefix != "" && rangeField != rdb.SortKey {
+		err = fmt.Errorf("redis: %q is not the sort key", rangeField)
+		return
+	}
+	min, max := "-", "+"
+	if rangePrefix != "" {
+		// UTF-8 strings never contain 0xff, so it follows every string with the prefix.
+		min, max = "["+rangePrefix, "["+rangePrefix+"\xff"
+	}
+	if startKey != nil {
+		k, kErr := rdb.keyOf(startKey)
+		if kErr != nil {
+			err = kErr
+			return
+		}
+		if k.sk >= rangePrefix {
+			min = "(" + k.sk
+		}
+	}
+	c, err := rdb.conn(ctx)
+	if err != nil {
+		return
+	}
+	defer c.Close()
+	args := []interface{}{rdb.rangesKey(idValue), min, max}
+	if limit > 0 {
+		args = append(args, "LIMIT", 0, limit+1)
+	}
+	sks, err := strs(c.Do("ZRANGEBYLEX", args...))
+	if err != nil {
+		return
+	}
+	more := limit > 0 && int64(len(sks)) > limit
+	if more {
+		sks = sks[:limit]
+	}
+	if items, err = rdb.getItems(c, idValue, sks); err != nil {
+		return
+	}
+	if more && len(items) > 0 {
+		lastEvaluatedKey = rdb.keyAttributes(items[len(items)-1])
+	}
+	return
+}
+
+// scan returns up to limit of the items which follow the start key, ordered by the text of their
+// partition key, then their sort key. Only the nodes which are included are read.
+func (rdb *DB) scan(ctx context.Context, limit int64, startKey map[string]*dynamodb.AttributeValue, include func(pk string) bool) (evaluated []map[string]*dynamodb.AttributeValue, lastEvaluatedKey map[string]*dynamodb.AttributeValue, err error) {
+	min := "-"
+	var start itemKey
+	if startKey != nil {
+		if start, err = rdb.keyOf(startKey); err != nil {
+			return
+		}
+		min = "[" + start.pk
+	}
+	c, err := rdb.conn(ctx)
+	if err != nil {
+		return
+	}
+	defer c.Close()
+	for {
+		pks, rErr := strs(c.Do("ZRANGEBYLEX", rdb.idsKey(), min, "+", "LIMIT", 0, scanBatchSize))
+		if rErr != nil {
+			err = rErr
+			return
+		}
+		if len(pks) == 0 {
+			return
+		}
+		for _, pk := range pks {
+			if include != nil && !include(pk) {
+				continue
+			}
+			rangeMin := "-"
+			if startKey != nil && pk == start.pk {
+				rangeMin = "(" + start.sk
+			}
+			args := []interface{}{rdb.rangesKey(pk), rangeMin, "+"}
+			if limit > 0 {
+				args = append(args, "LIMIT", 0, limit-int64(len(evaluated))+1)
+			}
+			sks, rErr := strs(c.Do("ZRANGEBYLEX", args...))
+			if rErr != nil {
+				err = rErr
+				return
+			}
+			more := limit > 0 && int64(len(evaluated)+len(sks)) > limit
+			if more {
+				sks = sks[:limit-int64(len(evaluated))]
+			}
+			items, gErr := rdb.getItems(c, pk, sks)
+			if gErr != nil {
+				err = gErr
+				return
+			}
+			evaluated = append(evaluated, items...)
+			if more && len(evaluated) > 0 {
+				lastEvaluatedKey = rdb.keyAttributes(evaluated[len(evaluated)-1])
+				return
+			}
+		}
+		min = "(" + pks[len(pks)-1]
+	}
+}
+
+// ScanPage returns a page of the items in the table where the field is equal to the value. As with
+// DynamoDB, the limit is the number of items evaluated, before the filter is applied.
+func (rdb *DB) ScanPage(ctx context.Context, field, value string, limit int64, startKey map[string]*dynamodb.AttributeValue) (items []map[string]*dynamodb.AttributeValue, lastEvaluatedKey map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error) {
+	evaluated, lastEvaluatedKey, err := rdb.scan(ctx, limit, startKey, nil)
+	for _, itm := range evaluated {
+		if v := itm[field]; v != nil && v.S != nil && *v.S == value {
+			items = append(items, itm)
+		}
+	}
+	return
+}
+
+// ScanSegment returns a page of the items in a segment of the table. Items are assigned to segments
+// by a hash of their partition key.
+func (rdb *DB) ScanSegment(ctx context.Context, segment, totalSegments, limit int64, startKey map[string]*dynamodb.AttributeValue) (items []map[string]*dynamodb.AttributeValue, lastEvaluatedKey map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error) {
+	if totalSegments < 1 || segment < 0 || segment >= totalSegments {
+		err = fmt.Errorf("redis: invalid segment %d of %d", segment, totalSegments)
+		return
+	}
+	items, lastEvaluatedKey, err = rdb.scan(ctx, limit, startKey, func(pk string) bool {
+		h := fnv.New32a()
+		h.Write([]byte(pk))
+		return int64(h.Sum32()%uint32(totalSegments)) == segment
+	})
+	return
+}
+
+// queryIndex returns up to limit of the items where the field, which is the partition key of the
+// index, is equal to the value, ordered by the text of the index's sort key, then the table's keys.
+func (rdb *DB) queryIndex(ctx context.Context, indexName, field string, value interface{}, limit int64, startKey map[string]*dynamodb.AttributeValue) (items []map[string]*dynamodb.AttributeValue, lastEvaluatedKey map[string]*dynamodb.AttributeValue, err error) {
+	index, ok := rdb.index(indexName)
+	if !ok {
+		err = UnknownIndexError{Name: indexName}
+		return
+	}
+	if field != index.PartitionKey {
+		err = fmt.Errorf("redis: %q is not the partition key of index %q", field, indexName)
+		return
+	}
+	v, err := dynamodbattribute.Marshal(value)
+	if err != nil {
+		return
+	}
+	if !isScalar(v) {
+		err = fmt.Errorf("redis: index %q can only be queried with a string, number or binary value", indexName)
+		return
+	}
+	min := "-"
+	if startKey != nil {
+		member, ok := rdb.indexMember(index, startKey)
+		if !ok {
+			err = ErrMissingKey
+			return
+		}
+		min = "(" + member
+	}
+	c, err := rdb.conn(ctx)
+	if err != nil {
+		return
+	}
+	defer c.Close()
+	args := []interface{}{rdb.indexKey(indexName, condition.KeyText(v)), min, "+"}
+	if limit > 0 {
+		args = append(args, "LIMIT", 0, limit+1)
+	}
+	members, err := strs(c.Do("ZRANGEBYLEX", args...))
+	if err != nil {
+		return
+	}
+	more := limit > 0 && int64(len(members)) > limit
+	if more {
+		members = members[:limit]
+	}
+	for _, member := range members {
+		parts := strings.Split(member, "\x00")
+		if len(parts) < 3 {
+			continue
+		}
+		itm, gErr := rdb.getItem(c, itemKey{pk: strings.Join(parts[1:len(parts)-1], "\x00"), sk: parts[len(parts)-1]})
+		if gErr != nil {
+			err = gErr
+			return
+		}
+		if itm != nil {
+			items = append(items, itm)
+		}
+	}
+	if more && len(items) > 0 {
+		extra := []string{field}
+		if index.SortKey != "" {
+			extra = append(extra, index.SortKey)
+		}
+		lastEvaluatedKey = rdb.keyAttributes(items[len(items)-1], extra...)
+	}
+	return
+}
+
+// QueryIndex returns the items where the field, which is the partition key of the index, is equal to
+// the value.
+func (rdb *DB) QueryIndex(ctx context.Context, indexName, field string, value interface{}) (items []map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error) {
+	items, _, err = rdb.queryIndex(ctx, indexName, field, value, 0, nil)
+	return
+}
+
+// QueryIndexPage returns a page of the items where the field, which is the partition key of the
+// index, is equal to the value. The last evaluated key contains the table's keys and the index's keys.
+func (rdb *DB) QueryIndexPage(ctx context.Context, indexName, field string, value interface{}, limit int64, startKey map[string]*dynamodb.AttributeValue) (items []map[string]*dynamodb.AttributeValue, lastEvaluatedKey map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error) {
+	items, lastEvaluatedKey, err = rdb.queryIndex(ctx, indexName, field, value, limit, startKey)
+	return
+}
+
+// DescribeTable describes the table's key schema, indexes and Time to Live attribute.
+func (rdb *DB) DescribeTable(ctx context.Context) (td db.TableDescription, err error) {
+	td = db.TableDescription{
+		Status:           dynamodb.TableStatusActive,
+		PartitionKeyName: rdb.PartitionKey,
+		PartitionKeyType: rdb.PartitionKeyType,
+		SortKeyName:      rdb.SortKey,
+		SortKeyType:      dynamodb.ScalarAttributeTypeS,
+		TTLAttribute:     rdb.TTLAttribute,
+	}
+	if td.PartitionKeyType == "" {
+		td.PartitionKeyType = dynamodb.ScalarAttributeTypeS
+	}
+	for _, index := range rdb.Indexes {
+		if index.PartitionKeyType == "" {
+			index.PartitionKeyType = dynamodb.ScalarAttributeTypeS
+		}
+		td.Indexes = append(td.Indexes, index)
+	}
+	return
+}
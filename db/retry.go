@@ -0,0 +1,127 @@
+package db
+
+import (
+	"context"
+	"math/rand"
+	"time"
+)
+
+// RetryPolicy decides whether failed requests are retried, and how long to wait between attempts,
+// e.g. to retry aggressively in batch jobs, and to fail fast in interactive APIs.
+type RetryPolicy interface {
+	// MaxAttempts is the maximum number of attempts at a request, including the first.
+	MaxAttempts() int
+	// Backoff returns the time to wait after the attempt, numbered from zero, before the next one.
+	Backoff(attempt int) time.Duration
+	// Retryable returns true if a request which failed with the error should be retried.
+	Retryable(err error) bool
+}
+
+// Default settings of the RetryPolicy returned by DefaultRetryPolicy.
+const (
+	DefaultRetryMaxAttempts = 6
+	DefaultRetryBaseDelay   = 25 * time.Millisecond
+	DefaultRetryMaxDelay    = 5 * time.Second
+)
+
+// DefaultRetryPolicy returns the RetryPolicy used by New, which retries throttled requests with
+// exponential backoff.
+func DefaultRetryPolicy() RetryPolicy {
+	return ExponentialBackoff{
+		Attempts:  DefaultRetryMaxAttempts,
+		BaseDelay: DefaultRetryBaseDelay,
+		MaxDelay:  DefaultRetryMaxDelay,
+	}
+}
+
+// ExponentialBackoff is a RetryPolicy which doubles the delay after each attempt, with jitter.
+type ExponentialBackoff struct {
+	// Attempts is the maximum number of attempts, including the first.
+	Attempts int
+	// BaseDelay is the delay after the first attempt.
+	BaseDelay time.Duration
+	// MaxDelay is the longest delay between attempts. If zero, the delay isn't limited.
+	MaxDelay time.Duration
+	// IsRetryable classifies errors. If nil, only throttling errors are retried.
+	IsRetryable func(err error) bool
+}
+
+// MaxAttempts is the maximum number of attempts at a request, including the first.
+func (eb ExponentialBackoff) MaxAttempts() int {
+	return eb.Attempts
+}
+
+// Backoff returns a random delay between half of, and all of, BaseDelay doubled for each attempt.
+func (eb ExponentialBackoff) Backoff(attempt int) time.Duration {
+	d := eb.BaseDelay << uint(attempt)
+	if d <= 0 || (eb.MaxDelay > 0 && d > eb.MaxDelay) {
+		d = eb.MaxDelay
+	}
+	return jitter(d)
+}
+
+// Retryable returns true if the error should be retried.
+func (eb ExponentialBackoff) Retryable(err error) bool {
+	if eb.IsRetryable != nil {
+		return eb.IsRetryable(err)
+	}
+	return IsThrottlingError(err)
+}
+
+// NoRetry is a RetryPolicy which never retries, so that errors are returned immediately.
+var NoRetry RetryPolicy = noRetry{}
+
+type noRetry struct{}
+
+func (noRetry) MaxAttempts() int                  { return 1 }
+func (noRetry) Backoff(attempt int) time.Duration { return 0 }
+func (noRetry) Retryable(err error) bool          { return false }
+
+// jitter returns a random duration between half of d and d.
+func jitter(d time.Duration) time.Duration {
+	if d <= 0 {
+		return 0
+	}
+	half := int64(d / 2)
+	return time.Duration(half + rand.Int63n(half+1))
+}
+
+// sleep waits for the duration, or until the context is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// do makes a DynamoDB call with f, which returns the capacity consumed by the call, retrying it
+// according to the RetryPolicy, and slowing down while the table is throttled. Retries stop when the
+// context is done. The call, including its retries, is observed by the DB's Metrics.
+func (db *DB) do(ctx context.Context, op string, f func() (ConsumedCapacity, error)) (cc ConsumedCapacity, err error) {
+	start := time.Now()
+	var retries int
+	defer func() {
+		db.observe(op, time.Since(start), retries, cc, err)
+	}()
+	for {
+		if err = db.Throttle.wait(ctx); err != nil {
+			return
+		}
+		cc, err = f()
+		db.Throttle.observe(err)
+		if err == nil || db.RetryPolicy == nil || !db.RetryPolicy.Retryable(err) || retries+1 >= db.RetryPolicy.MaxAttempts() {
+			return
+		}
+		if sErr := sleep(ctx, db.RetryPolicy.Backoff(retries)); sErr != nil {
+			return
+		}
+		retries++
+	}
+}
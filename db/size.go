@@ -0,0 +1,51 @@
+package db
+
+import "github.com/aws/aws-sdk-go/service/dynamodb"
+
+// MaxItemSize is the maximum size, in bytes, of a DynamoDB item, including its attribute names.
+const MaxItemSize = 400 * 1024
+
+// ItemSize estimates the number of bytes DynamoDB uses to store an item, using the sizes documented
+// by DynamoDB for each attribute type.
+func ItemSize(item map[string]*dynamodb.AttributeValue) (size int) {
+	for k, v := range item {
+		size += len(k) + attributeValueSize(v)
+	}
+	return
+}
+
+func attributeValueSize(v *dynamodb.AttributeValue) (size int) {
+	if v == nil {
+		return
+	}
+	switch {
+	case v.S != nil:
+		return len(*v.S)
+	case v.N != nil:
+		return len(*v.N)/2 + 1
+	case v.B != nil:
+		return len(v.B)
+	case v.BOOL != nil, v.NULL != nil:
+		return 1
+	}
+	for _, s := range v.SS {
+		size += len(*s)
+	}
+	for _, n := range v.NS {
+		size += len(*n)/2 + 1
+	}
+	for _, b := range v.BS {
+		size += len(b)
+	}
+	// Lists and maps use 3 bytes, and 1 byte per element.
+	if v.L != nil || v.M != nil {
+		size += 3
+	}
+	for _, e := range v.L {
+		size += 1 + attributeValueSize(e)
+	}
+	for k, e := range v.M {
+		size += 1 + len(k) + attributeValueSize(e)
+	}
+	return
+}
@@ -0,0 +1,255 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// TableOptions configure the table created by CreateTableIfNotExists.
+type TableOptions struct {
+	// ReadCapacityUnits and WriteCapacityUnits set the provisioned throughput of the table and its
+	// indexes. If both are zero, the table uses on-demand (PAY_PER_REQUEST) billing.
+	ReadCapacityUnits  int64
+	WriteCapacityUnits int64
+	// TTLAttribute enables Time to Live on the attribute, e.g. pregel.TTLAttributeName. If empty,
+	// Time to Live isn't enabled.
+	TTLAttribute string
+	// Indexes are the global secondary indexes to create, e.g. for the Store's LabelIndex, or
+	// indexes registered with RegisterIndex.
+	Indexes []TableIndex
+}
+
+// TableIndex is a global secondary index which projects all attributes.
+type TableIndex struct {
+	Name string
+	// PartitionKey is the name of the index's partition key attribute, e.g. pregel.LabelAttributeName.
+	PartitionKey string
+	// PartitionKeyType is the DynamoDB scalar type of the partition key, e.g. "S" or "N". If empty,
+	// "S" is used.
+	PartitionKeyType string
+	// SortKey is the name of the index's sort key attribute, which must be a string. pregel's label
+	// index uses the "id" attribute. If empty, the index has no sort key.
+	SortKey string
+}
+
+// CreateTableIfNotExists creates a table with the key schema used by pregel, i.e. an "id" partition
+// key and a "rng" sort key, then waits for the table to become active. If the table already exists,
+// it's left unchanged, but it's still waited for if it's being created by another process. created
+// is true if the table was created.
+func CreateTableIfNotExists(ctx context.Context, region, tableName string, opts TableOptions) (created bool, err error) {
+	sess, err := session.NewSession(&aws.Config{
+		Region: aws.String(region),
+	})
+	if err != nil {
+		return
+	}
+	client := dynamodb.New(sess)
+	dto, err := client.DescribeTableWithContext(ctx, &dynamodb.DescribeTableInput{
+		TableName: aws.String(tableName),
+	})
+	if err == nil {
+		if aws.StringValue(dto.Table.TableStatus) == dynamodb.TableStatusCreating {
+			err = waitUntilTableExists(ctx, client, tableName)
+		}
+		return
+	}
+	if !isAWSError(err, dynamodb.ErrCodeResourceNotFoundException) {
+		err = requestError("DB.CreateTableIfNotExists", "failed to describe table", err)
+		return
+	}
+	_, err = client.CreateTableWithContext(ctx, newCreateTableInput(tableName, opts))
+	if err != nil {
+		// Another process created the table at the same time.
+		if isAWSError(err, dynamodb.ErrCodeResourceInUseException) {
+			err = waitUntilTableExists(ctx, client, tableName)
+			return
+		}
+		err = requestError("DB.CreateTableIfNotExists", "failed to create table", err)
+		return
+	}
+	created = true
+	if err = waitUntilTableExists(ctx, client, tableName); err != nil {
+		return
+	}
+	if opts.TTLAttribute == "" {
+		return
+	}
+	_, err = client.UpdateTimeToLiveWithContext(ctx, &dynamodb.UpdateTimeToLiveInput{
+		TableName: aws.String(tableName),
+		TimeToLiveSpecification: &dynamodb.TimeToLiveSpecification{
+			AttributeName: aws.String(opts.TTLAttribute),
+			Enabled:       aws.Bool(true),
+		},
+	})
+	if err != nil {
+		err = requestError("DB.CreateTableIfNotExists", "failed to enable time to live", err)
+	}
+	return
+}
+
+func waitUntilTableExists(ctx context.Context, client *dynamodb.DynamoDB, tableName string) (err error) {
+	err = client.WaitUntilTableExistsWithContext(ctx, &dynamodb.DescribeTableInput{
+		TableName: aws.String(tableName),
+	})
+	if err != nil {
+		err = requestError("DB.CreateTableIfNotExists", "failed waiting for table to become active", err)
+	}
+	return
+}
+
+func newCreateTableInput(tableName string, opts TableOptions) (cti *dynamodb.CreateTableInput) {
+	cti = &dynamodb.CreateTableInput{
+		TableName: aws.String(tableName),
+		KeySchema: []*dynamodb.KeySchemaElement{
+			{AttributeName: aws.String("id"), KeyType: aws.String(dynamodb.KeyTypeHash)},
+			{AttributeName: aws.String("rng"), KeyType: aws.String(dynamodb.KeyTypeRange)},
+		},
+	}
+	attributeTypes := map[string]string{
+		"id":  dynamodb.ScalarAttributeTypeS,
+		"rng": dynamodb.ScalarAttributeTypeS,
+	}
+	attributes := []string{"id", "rng"}
+	addAttribute := func(name, attributeType string) {
+		if _, ok := attributeTypes[name]; ok {
+			return
+		}
+		attributeTypes[name] = attributeType
+		attributes = append(attributes, name)
+	}
+	var throughput *dynamodb.ProvisionedThroughput
+	if opts.ReadCapacityUnits > 0 || opts.WriteCapacityUnits > 0 {
+		throughput = &dynamodb.ProvisionedThroughput{
+			ReadCapacityUnits:  aws.Int64(opts.ReadCapacityUnits),
+			WriteCapacityUnits: aws.Int64(opts.WriteCapacityUnits),
+		}
+		cti.BillingMode = aws.String(dynamodb.BillingModeProvisioned)
+		cti.ProvisionedThroughput = throughput
+	} else {
+		cti.BillingMode = aws.String(dynamodb.BillingModePayPerRequest)
+	}
+	for _, index := range opts.Indexes {
+		partitionKeyType := index.PartitionKeyType
+		if partitionKeyType == "" {
+			partitionKeyType = dynamodb.ScalarAttributeTypeS
+		}
+		addAttribute(index.PartitionKey, partitionKeyType)
+		gsi := &dynamodb.GlobalSecondaryIndex{
+			IndexName: aws.String(index.Name),
+			KeySchema: []*dynamodb.KeySchemaElement{
+				{AttributeName: aws.String(index.PartitionKey), KeyType: aws.String(dynamodb.KeyTypeHash)},
+			},
+			Projection: &dynamodb.Projection{
+				ProjectionType: aws.String(dynamodb.ProjectionTypeAll),
+			},
+			ProvisionedThroughput: throughput,
+		}
+		if index.SortKey != "" {
+			addAttribute(index.SortKey, dynamodb.ScalarAttributeTypeS)
+			gsi.KeySchema = append(gsi.KeySchema, &dynamodb.KeySchemaElement{
+				AttributeName: aws.String(index.SortKey),
+				KeyType:       aws.String(dynamodb.KeyTypeRange),
+			})
+		}
+		cti.GlobalSecondaryIndexes = append(cti.GlobalSecondaryIndexes, gsi)
+	}
+	for _, name := range attributes {
+		cti.AttributeDefinitions = append(cti.AttributeDefinitions, &dynamodb.AttributeDefinition{
+			AttributeName: aws.String(name),
+			AttributeType: aws.String(attributeTypes[name]),
+		})
+	}
+	return
+}
+
+func isAWSError(err error, code string) bool {
+	var aerr awserr.Error
+	return errors.As(err, &aerr) && aerr.Code() == code
+}
+
+// TableDescription describes the schema of a table.
+type TableDescription struct {
+	// Status of the table, e.g. "ACTIVE".
+	Status string
+	// PartitionKeyName and PartitionKeyType are the name and DynamoDB scalar type of the table's
+	// partition key.
+	PartitionKeyName string
+	PartitionKeyType string
+	// SortKeyName and SortKeyType are the name and DynamoDB scalar type of the table's sort key, or
+	// empty if the table doesn't have a sort key.
+	SortKeyName string
+	SortKeyType string
+	// Indexes are the table's global secondary indexes.
+	Indexes []TableIndex
+	// TTLAttribute is the attribute used for Time to Live, or empty if Time to Live isn't enabled.
+	TTLAttribute string
+}
+
+// DescribeTable returns the schema of the table.
+func (db *DB) DescribeTable(ctx context.Context) (td TableDescription, err error) {
+	start := time.Now()
+	dto, err := db.Client.DescribeTableWithContext(ctx, &dynamodb.DescribeTableInput{
+		TableName: aws.String(db.TableName),
+	})
+	db.observe("DescribeTable", time.Since(start), 0, ConsumedCapacity{}, err)
+	if err != nil {
+		err = requestError("DB.DescribeTable", "failed to describe table", err)
+		return
+	}
+	if dto.Table == nil {
+		err = fmt.Errorf("DB.DescribeTable: table %q has no description", db.TableName)
+		return
+	}
+	attributeTypes := make(map[string]string)
+	for _, ad := range dto.Table.AttributeDefinitions {
+		attributeTypes[aws.StringValue(ad.AttributeName)] = aws.StringValue(ad.AttributeType)
+	}
+	td.Status = aws.StringValue(dto.Table.TableStatus)
+	for _, kse := range dto.Table.KeySchema {
+		name := aws.StringValue(kse.AttributeName)
+		switch aws.StringValue(kse.KeyType) {
+		case dynamodb.KeyTypeHash:
+			td.PartitionKeyName, td.PartitionKeyType = name, attributeTypes[name]
+		case dynamodb.KeyTypeRange:
+			td.SortKeyName, td.SortKeyType = name, attributeTypes[name]
+		}
+	}
+	for _, gsi := range dto.Table.GlobalSecondaryIndexes {
+		index := TableIndex{
+			Name: aws.StringValue(gsi.IndexName),
+		}
+		for _, kse := range gsi.KeySchema {
+			name := aws.StringValue(kse.AttributeName)
+			switch aws.StringValue(kse.KeyType) {
+			case dynamodb.KeyTypeHash:
+				index.PartitionKey, index.PartitionKeyType = name, attributeTypes[name]
+			case dynamodb.KeyTypeRange:
+				index.SortKey = name
+			}
+		}
+		td.Indexes = append(td.Indexes, index)
+	}
+	start = time.Now()
+	ttlo, err := db.Client.DescribeTimeToLiveWithContext(ctx, &dynamodb.DescribeTimeToLiveInput{
+		TableName: aws.String(db.TableName),
+	})
+	db.observe("DescribeTimeToLive", time.Since(start), 0, ConsumedCapacity{}, err)
+	if err != nil {
+		err = requestError("DB.DescribeTable", "failed to describe time to live", err)
+		return
+	}
+	if ttl := ttlo.TimeToLiveDescription; ttl != nil {
+		switch aws.StringValue(ttl.TimeToLiveStatus) {
+		case dynamodb.TimeToLiveStatusEnabled, dynamodb.TimeToLiveStatusEnabling:
+			td.TTLAttribute = aws.StringValue(ttl.AttributeName)
+		}
+	}
+	return
+}
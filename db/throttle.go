@@ -0,0 +1,76 @@
+package db
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Default settings of the Throttle created by New.
+const (
+	DefaultThrottleBaseDelay = 25 * time.Millisecond
+	DefaultThrottleMaxDelay  = 5 * time.Second
+)
+
+// Throttle slows down all of the requests made to a table while DynamoDB is throttling them. Each
+// throttled request doubles a delay which is applied before every request, and each successful
+// request reduces it, so that the request rate adapts to the capacity available. Throttled requests
+// are retried according to the DB's RetryPolicy. The delay is applied before each attempt made by
+// the DB, so the DB's Client mustn't retry requests itself, or its retries would ignore the delay.
+type Throttle struct {
+	// BaseDelay is the smallest delay applied to requests while the table is throttled.
+	BaseDelay time.Duration
+	// MaxDelay is the longest delay applied to requests.
+	MaxDelay time.Duration
+
+	m     sync.Mutex
+	delay time.Duration
+}
+
+// NewThrottle creates a Throttle with the default settings.
+func NewThrottle() *Throttle {
+	return &Throttle{
+		BaseDelay: DefaultThrottleBaseDelay,
+		MaxDelay:  DefaultThrottleMaxDelay,
+	}
+}
+
+// wait sleeps for the current delay, with jitter, or until the context is done.
+func (t *Throttle) wait(ctx context.Context) error {
+	if t == nil {
+		return ctx.Err()
+	}
+	t.m.Lock()
+	d := t.delay
+	t.m.Unlock()
+	return sleep(ctx, jitter(d))
+}
+
+// observe adjusts the delay based on the result of a request.
+func (t *Throttle) observe(err error) {
+	if t == nil {
+		return
+	}
+	t.m.Lock()
+	defer t.m.Unlock()
+	if IsThrottlingError(err) {
+		t.delay *= 2
+		if t.delay < t.BaseDelay {
+			t.delay = t.BaseDelay
+		}
+		if t.delay > t.MaxDelay {
+			t.delay = t.MaxDelay
+		}
+		return
+	}
+	t.delay -= t.delay / 4
+	if t.delay < t.BaseDelay/2 {
+		t.delay = 0
+	}
+}
+
+// IsThrottlingError returns true if DynamoDB rejected a request because the table's capacity, or the
+// account's request rate, was exceeded.
+func IsThrottlingError(err error) bool {
+	return Classify(err) == ErrThrottled
+}
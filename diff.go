@@ -0,0 +1,223 @@
+package pregel
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"reflect"
+	"sort"
+)
+
+// Snapshot is a copy of the nodes in a graph, keyed by ID.
+type Snapshot map[string]Node
+
+// Snapshot reads every node in the table into memory, for comparison with Diff.
+func (s *Store) Snapshot(ctx context.Context) (snapshot Snapshot, err error) {
+	snapshot = make(Snapshot)
+	err = s.scanNodes(ctx, func(n Node) error {
+		snapshot[n.ID] = n
+		return nil
+	})
+	return
+}
+
+// ReadSnapshot reads the nodes written by Export.
+func ReadSnapshot(r io.Reader) (snapshot Snapshot, err error) {
+	snapshot = make(Snapshot)
+	dec := json.NewDecoder(r)
+	for {
+		var n Node
+		if err = dec.Decode(&n); err == io.EOF {
+			return snapshot, nil
+		}
+		if err != nil {
+			return
+		}
+		snapshot[n.ID] = n
+	}
+}
+
+// GraphDiff is the difference between two snapshots of a graph.
+type GraphDiff struct {
+	// AddedNodes are the IDs of nodes which are only in the second snapshot.
+	AddedNodes []string
+	// RemovedNodes are the IDs of nodes which are only in the first snapshot.
+	RemovedNodes []string
+	// ChangedNodes are the nodes which are in both snapshots, but are different.
+	ChangedNodes []NodeDiff
+}
+
+// Empty returns true if the snapshots are the same.
+func (d GraphDiff) Empty() bool {
+	return len(d.AddedNodes) == 0 && len(d.RemovedNodes) == 0 && len(d.ChangedNodes) == 0
+}
+
+// NodeDiff is the difference between two versions of a node.
+type NodeDiff struct {
+	ID            string
+	Data          DataDiff
+	AddedLabels   []string
+	RemovedLabels []string
+	Children      EdgesDiff
+	Parents       EdgesDiff
+	// Deleted is true if the node's soft deleted status is different.
+	Deleted bool
+}
+
+func (d NodeDiff) empty() bool {
+	return d.Data.empty() && len(d.AddedLabels) == 0 && len(d.RemovedLabels) == 0 &&
+		d.Children.empty() && d.Parents.empty() && !d.Deleted
+}
+
+// DataDiff lists the names of the data types which are different between two versions of a node
+// or edge.
+type DataDiff struct {
+	Added   []string
+	Removed []string
+	Changed []string
+}
+
+func (d DataDiff) empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// EdgesDiff is the difference between two versions of a node's child or parent edges.
+type EdgesDiff struct {
+	Added   []*Edge
+	Removed []*Edge
+	// Changed are the edges whose data is different.
+	Changed []EdgeDiff
+}
+
+func (d EdgesDiff) empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// EdgeDiff is the difference between the data of two versions of an edge.
+type EdgeDiff struct {
+	ID    string
+	Label string
+	Data  DataDiff
+}
+
+// Diff compares two snapshots, e.g. to check that a migration or replication copied everything.
+// Data values are compared by their JSON representation, so a snapshot read from a Store can be
+// compared with one read from an export. Timestamps aren't compared.
+func Diff(from, to Snapshot) (d GraphDiff) {
+	for _, id := range sortedIDs(from) {
+		if _, ok := to[id]; !ok {
+			d.RemovedNodes = append(d.RemovedNodes, id)
+			continue
+		}
+		if nd := diffNode(from[id], to[id]); !nd.empty() {
+			d.ChangedNodes = append(d.ChangedNodes, nd)
+		}
+	}
+	for _, id := range sortedIDs(to) {
+		if _, ok := from[id]; !ok {
+			d.AddedNodes = append(d.AddedNodes, id)
+		}
+	}
+	return
+}
+
+func sortedIDs(s Snapshot) (ids []string) {
+	for id := range s {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return
+}
+
+func diffNode(from, to Node) (d NodeDiff) {
+	d.ID = from.ID
+	d.Data = diffData(from.Data, to.Data)
+	d.AddedLabels, d.RemovedLabels = diffStrings(from.Labels, to.Labels)
+	d.Children = diffEdges(from.Children, to.Children)
+	d.Parents = diffEdges(from.Parents, to.Parents)
+	d.Deleted = from.Deleted != to.Deleted
+	return
+}
+
+func diffStrings(from, to []string) (added, removed []string) {
+	inFrom, inTo := make(map[string]bool), make(map[string]bool)
+	for _, s := range from {
+		inFrom[s] = true
+	}
+	for _, s := range to {
+		inTo[s] = true
+		if !inFrom[s] {
+			added = append(added, s)
+		}
+	}
+	for _, s := range from {
+		if !inTo[s] {
+			removed = append(removed, s)
+		}
+	}
+	sort.Strings(added)
+	sort.Strings(removed)
+	return
+}
+
+func diffEdges(from, to []*Edge) (d EdgesDiff) {
+	type edgeKey struct{ id, label string }
+	toEdges := make(map[edgeKey]*Edge, len(to))
+	for _, e := range to {
+		toEdges[edgeKey{e.ID, e.Label}] = e
+	}
+	fromEdges := make(map[edgeKey]*Edge, len(from))
+	for _, e := range from {
+		k := edgeKey{e.ID, e.Label}
+		fromEdges[k] = e
+		te, ok := toEdges[k]
+		if !ok {
+			d.Removed = append(d.Removed, e)
+			continue
+		}
+		if dd := diffData(e.Data, te.Data); !dd.empty() {
+			d.Changed = append(d.Changed, EdgeDiff{ID: e.ID, Label: e.Label, Data: dd})
+		}
+	}
+	for _, e := range to {
+		if _, ok := fromEdges[edgeKey{e.ID, e.Label}]; !ok {
+			d.Added = append(d.Added, e)
+		}
+	}
+	return
+}
+
+func diffData(from, to Data) (d DataDiff) {
+	for name, v := range from {
+		tv, ok := to[name]
+		if !ok {
+			d.Removed = append(d.Removed, name)
+			continue
+		}
+		if !reflect.DeepEqual(normalizeData(v), normalizeData(tv)) {
+			d.Changed = append(d.Changed, name)
+		}
+	}
+	for name := range to {
+		if _, ok := from[name]; !ok {
+			d.Added = append(d.Added, name)
+		}
+	}
+	sort.Strings(d.Added)
+	sort.Strings(d.Removed)
+	sort.Strings(d.Changed)
+	return
+}
+
+// normalizeData converts a data value to the generic form it would have after being written to JSON
+// and read back, so that typed values can be compared with values read from an export.
+func normalizeData(v interface{}) (normalized interface{}) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+	if err = json.Unmarshal(b, &normalized); err != nil {
+		return v
+	}
+	return
+}
@@ -0,0 +1,121 @@
+package pregel
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	type location struct {
+		Lat float64 `json:"lat"`
+		Lng float64 `json:"lng"`
+	}
+	tests := []struct {
+		name     string
+		from     Snapshot
+		to       Snapshot
+		expected GraphDiff
+	}{
+		{
+			name: "Identical snapshots have no differences",
+			from: Snapshot{"a": NewNode("a").WithData(&location{Lat: 1})},
+			to:   Snapshot{"a": NewNode("a").WithData(&location{Lat: 1})},
+		},
+		{
+			name: "Added and removed nodes are reported",
+			from: Snapshot{"a": NewNode("a"), "b": NewNode("b")},
+			to:   Snapshot{"b": NewNode("b"), "c": NewNode("c")},
+			expected: GraphDiff{
+				AddedNodes:   []string{"c"},
+				RemovedNodes: []string{"a"},
+			},
+		},
+		{
+			name: "Typed data is the same as data read from an export",
+			from: Snapshot{"a": NewNode("a").WithData(&location{Lat: 1, Lng: 2})},
+			to:   Snapshot{"a": NewNode("a").WithNamedData("location", map[string]interface{}{"lat": 1.0, "lng": 2.0})},
+		},
+		{
+			name: "Changes to data are reported",
+			from: Snapshot{"a": NewNode("a").WithData(&location{Lat: 1}).WithNamedData("old", 1)},
+			to:   Snapshot{"a": NewNode("a").WithData(&location{Lat: 2}).WithNamedData("new", 1)},
+			expected: GraphDiff{
+				ChangedNodes: []NodeDiff{
+					{
+						ID: "a",
+						Data: DataDiff{
+							Added:   []string{"new"},
+							Removed: []string{"old"},
+							Changed: []string{"location"},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "Changes to labels, edges and edge data are reported",
+			from: Snapshot{"a": NewNode("a").WithLabels("x").WithChildren(
+				NewEdge("b"),
+				NewEdge("c").WithData(&location{Lat: 1}),
+			)},
+			to: Snapshot{"a": NewNode("a").WithLabels("y").WithChildren(
+				NewEdge("c").WithData(&location{Lat: 2}),
+				NewEdge("b").WithLabel("owns"),
+			)},
+			expected: GraphDiff{
+				ChangedNodes: []NodeDiff{
+					{
+						ID:            "a",
+						AddedLabels:   []string{"y"},
+						RemovedLabels: []string{"x"},
+						Children: EdgesDiff{
+							Added:   []*Edge{NewEdge("b").WithLabel("owns")},
+							Removed: []*Edge{NewEdge("b")},
+							Changed: []EdgeDiff{
+								{ID: "c", Data: DataDiff{Changed: []string{"location"}}},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			actual := Diff(test.from, test.to)
+			if !reflect.DeepEqual(actual, test.expected) {
+				t.Errorf("\nexpected:\n%+v\n\ngot:\n%+v\n", test.expected, actual)
+			}
+			if actual.Empty() != reflect.DeepEqual(test.expected, GraphDiff{}) {
+				t.Errorf("expected Empty to be %v", !actual.Empty())
+			}
+		})
+	}
+}
+
+func TestReadSnapshot(t *testing.T) {
+	export := `{"id":"a","data":{"location":{"lat":1}},"children":[{"id":"b"}],"parents":null}
+{"id":"b","data":{},"children":null,"parents":[{"id":"a"}]}
+`
+	snapshot, err := ReadSnapshot(strings.NewReader(export))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(snapshot) != 2 {
+		t.Fatalf("expected 2 nodes, got %d", len(snapshot))
+	}
+	if len(snapshot["a"].Children) != 1 || snapshot["a"].Children[0].ID != "b" {
+		t.Errorf("expected a to have child b, got %+v", snapshot["a"].Children)
+	}
+	d := Diff(snapshot, Snapshot{
+		"a": NewNode("a").WithNamedData("location", map[string]interface{}{"lat": 1}).WithChildren(NewEdge("b")),
+		"b": NewNode("b").WithParents(NewEdge("a")),
+	})
+	if !d.Empty() {
+		t.Errorf("expected no differences, got %+v", d)
+	}
+}
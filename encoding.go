@@ -0,0 +1,87 @@
+package pregel
+
+import (
+	"errors"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+)
+
+// Encoding converts the data attached to nodes and edges to and from the attributes of a data
+// record, excluding the attributes used by the Store. Encodings other than DynamoDBEncoding, e.g.
+// protobuf, msgpack, or encryption, can be used for a data type by registering them with
+// RegisterEncoding.
+type Encoding interface {
+	Marshal(v interface{}) (attributes map[string]*dynamodb.AttributeValue, err error)
+	Unmarshal(attributes map[string]*dynamodb.AttributeValue, into interface{}) error
+}
+
+// DynamoDBEncoding stores each field of the data in its own attribute, using dynamodbattribute, so
+// that fields can be indexed with RegisterIndex, and updated with UpdateNodeData. It's used for data
+// types which don't have a registered Encoding.
+var DynamoDBEncoding Encoding = dynamoDBEncoding{}
+
+type dynamoDBEncoding struct{}
+
+func (dynamoDBEncoding) Marshal(v interface{}) (map[string]*dynamodb.AttributeValue, error) {
+	return dynamodbattribute.MarshalMap(v)
+}
+
+func (dynamoDBEncoding) Unmarshal(attributes map[string]*dynamodb.AttributeValue, into interface{}) error {
+	return dynamodbattribute.UnmarshalMap(attributes, into)
+}
+
+// EncodedAttributeName is the name of the binary attribute which holds data encoded by a
+// BinaryEncoding.
+const EncodedAttributeName = "data"
+
+// BinaryEncoding stores data in a single binary attribute, named EncodedAttributeName, using
+// functions which convert it to and from bytes, e.g. proto.Marshal and proto.Unmarshal.
+type BinaryEncoding struct {
+	MarshalBinary   func(v interface{}) ([]byte, error)
+	UnmarshalBinary func(data []byte, into interface{}) error
+}
+
+// Marshal stores the bytes returned by MarshalBinary in the EncodedAttributeName attribute.
+func (e BinaryEncoding) Marshal(v interface{}) (attributes map[string]*dynamodb.AttributeValue, err error) {
+	data, err := e.MarshalBinary(v)
+	if err != nil {
+		return
+	}
+	attributes = map[string]*dynamodb.AttributeValue{
+		EncodedAttributeName: {B: data},
+	}
+	return
+}
+
+// Unmarshal reads the EncodedAttributeName attribute using UnmarshalBinary.
+func (e BinaryEncoding) Unmarshal(attributes map[string]*dynamodb.AttributeValue, into interface{}) error {
+	data, ok := attributes[EncodedAttributeName]
+	if !ok {
+		return ErrMissingEncodedData
+	}
+	return e.UnmarshalBinary(data.B, into)
+}
+
+// ErrMissingEncodedData is returned when reading a data record written by a BinaryEncoding, which
+// doesn't have the EncodedAttributeName attribute.
+var ErrMissingEncodedData = errors.New("data record is missing its encoded data")
+
+// ErrEncodedDataUpdate is returned by UpdateNodeData when the data type has a registered Encoding,
+// since the fields of the data might not be stored in their own attributes.
+var ErrEncodedDataUpdate = errors.New("data types with a registered encoding can't be updated, use PutNodeData instead")
+
+// RegisterEncoding sets the Encoding used to write and read data of the data type. The encoding
+// must be registered before any data of the data type is written, since existing data records can
+// only be read using the encoding which wrote them.
+func (s *Store) RegisterEncoding(dataType string, e Encoding) {
+	s.Encodings[dataType] = e
+}
+
+// encoding returns the Encoding of the data type.
+func (s *Store) encoding(dataType string) Encoding {
+	if e, ok := s.Encodings[dataType]; ok {
+		return e
+	}
+	return DynamoDBEncoding
+}
@@ -0,0 +1,52 @@
+package pregel
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/a-h/pregel/db/memory"
+)
+
+func TestStoreEncoding(t *testing.T) {
+	client := memory.New()
+	s := NewStoreWithClient(client)
+	s.RegisterDataType(func() interface{} { return &testNodeData{} })
+	s.RegisterDataType(func() interface{} { return &testEdgeData{} })
+	s.RegisterEncoding("testNodeData", BinaryEncoding{MarshalBinary: json.Marshal, UnmarshalBinary: json.Unmarshal})
+
+	if err := s.Put(NewNode("a").WithData(testNodeData{ExtraAttribute: "a"}), NewNode("b")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := s.PutEdges("a", NewEdge("b").WithData(testEdgeData{EdgeDataField: 1})); err != nil {
+		t.Fatalf("unexpected error putting edges: %v", err)
+	}
+	for _, itm := range client.Items() {
+		switch *itm[fieldRange].S {
+		case "node/data/testNodeData":
+			if _, ok := itm["extra"]; ok {
+				t.Errorf("expected the node data's fields to be encoded, got %v", itm)
+			}
+			if v, ok := itm[EncodedAttributeName]; !ok || string(v.B) != `{"extra":"a"}` {
+				t.Errorf("expected the node data to be stored in the %q attribute, got %v", EncodedAttributeName, itm)
+			}
+		case "child/b/data/testEdgeData":
+			if _, ok := itm["edgeDataField"]; !ok {
+				t.Errorf("expected the edge data to use the default encoding, got %v", itm)
+			}
+		}
+	}
+
+	n, ok, err := s.Get("a")
+	if err != nil || !ok {
+		t.Fatalf("expected a to be found, got %v, %v", ok, err)
+	}
+	if d, ok := n.Data["testNodeData"].(*testNodeData); !ok || d.ExtraAttribute != "a" {
+		t.Errorf("expected the encoded data to be read, got %+v", n.Data["testNodeData"])
+	}
+	if e := n.GetChild("b"); e == nil || e.Data["testEdgeData"].(*testEdgeData).EdgeDataField != 1 {
+		t.Errorf("expected the edge data to be read, got %+v", n.Children)
+	}
+	if err = s.UpdateNodeData("a", "testNodeData", map[string]interface{}{"extra": "b"}); err != ErrEncodedDataUpdate {
+		t.Errorf("expected ErrEncodedDataUpdate, got %v", err)
+	}
+}
@@ -183,7 +183,8 @@ func main() {
 	bytes, _ = json.Marshal(router)
 	fmt.Println(string(bytes))
 
-	fmt.Printf("Capacity units consumed - total: %v, read: %v, write: %v\n", s.ConsumedCapacity, s.ConsumedReadCapacity, s.ConsumedWriteCapacity)
+	cc := s.CapacitySnapshot()
+	fmt.Printf("Capacity units consumed - total: %v, read: %v, write: %v\n", cc.ConsumedCapacity, cc.ConsumedReadCapacity, cc.ConsumedWriteCapacity)
 }
 
 type computer struct {
@@ -0,0 +1,113 @@
+package pregel
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// ExportFormat is the format of the data written by Export.
+type ExportFormat int
+
+const (
+	// ExportJSONLines writes each node, including its data and edges, as a JSON object on its own line.
+	ExportJSONLines ExportFormat = iota
+)
+
+// ErrUnsupportedExportFormat is returned when the export format isn't known.
+var ErrUnsupportedExportFormat = errors.New("unsupported export format")
+
+// exportSegments is the number of segments of the table that Export scans in parallel.
+const exportSegments = 4
+
+// Export writes every node in the table to w, for backups, analysis, or migrating to another table.
+// The table is scanned in parallel segments, so the nodes are written in no particular order. Soft
+// deleted nodes are included, and expired nodes aren't. Data types which aren't registered are
+// written as maps.
+func (s *Store) Export(ctx context.Context, w io.Writer, format ExportFormat) (err error) {
+	if format != ExportJSONLines {
+		return ErrUnsupportedExportFormat
+	}
+	enc := json.NewEncoder(w)
+	return s.scanNodes(ctx, func(n Node) error {
+		return enc.Encode(n)
+	})
+}
+
+// scanNodes scans the table in parallel segments, and calls emit with each node. Calls to emit
+// aren't concurrent.
+func (s *Store) scanNodes(ctx context.Context, emit func(n Node) error) (err error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var m sync.Mutex
+	emitLocked := func(n Node) error {
+		m.Lock()
+		defer m.Unlock()
+		return emit(n)
+	}
+	var wg sync.WaitGroup
+	wg.Add(exportSegments)
+	for i := 0; i < exportSegments; i++ {
+		go func(segment int64) {
+			defer wg.Done()
+			if sErr := s.exportSegment(ctx, segment, emitLocked); sErr != nil {
+				m.Lock()
+				if err == nil {
+					err = sErr
+				}
+				m.Unlock()
+				cancel()
+			}
+		}(int64(i))
+	}
+	wg.Wait()
+	return
+}
+
+// exportSegment scans a segment of the table, and emits each node. DynamoDB returns the records of
+// each node together, in a single segment, so a node is complete once a record from another node is
+// read.
+func (s *Store) exportSegment(ctx context.Context, segment int64, emit func(n Node) error) (err error) {
+	var startKey map[string]*dynamodb.AttributeValue
+	var currentID string
+	n := NewNode("")
+	flush := func() error {
+		// Nodes whose node record is missing or expired aren't emitted.
+		if n.ID == "" {
+			return nil
+		}
+		return emit(n)
+	}
+	for {
+		if err = ctx.Err(); err != nil {
+			return
+		}
+		items, lastEvaluatedKey, cc, sErr := s.Client.ScanSegment(ctx, segment, exportSegments, 0, startKey)
+		if sErr != nil {
+			err = sErr
+			return
+		}
+		s.updateCapacityStats(cc)
+		for _, itm := range items {
+			if id := aws.StringValue(itm[fieldID].S); id != currentID {
+				if err = flush(); err != nil {
+					return
+				}
+				n = NewNode("")
+				currentID = id
+			}
+			if err = s.populateNodeFromRecord(itm, &n); err != nil {
+				return
+			}
+		}
+		if lastEvaluatedKey == nil {
+			return flush()
+		}
+		startKey = lastEvaluatedKey
+	}
+}
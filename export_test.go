@@ -0,0 +1,141 @@
+package pregel
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/a-h/pregel/db"
+	"github.com/a-h/pregel/rangefield"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+func TestStoreExport(t *testing.T) {
+	// Segment 0 contains two nodes, with a page boundary in the middle of nodeA's records. Segment 1
+	// contains an edge record without a node record, which isn't exported.
+	segments := map[int64][][]map[string]*dynamodb.AttributeValue{
+		0: {
+			{
+				getID("nodeA", rangefield.Child{Child: "nodeB"}),
+			},
+			{
+				getID("nodeA", rangefield.Node{}),
+				getID("nodeA", rangefield.NodeLabel{Label: "device"}),
+				getID("nodeB", rangefield.Node{}),
+				getID("nodeB", rangefield.Parent{Parent: "nodeA"}),
+			},
+		},
+		1: {
+			{
+				getID("orphan", rangefield.Child{Child: "nodeA"}),
+			},
+		},
+	}
+	t.Run("Each node is written as a line of JSON", func(t *testing.T) {
+		client := newdynamoDBClient()
+		client.scanSegmenter = func(segment, totalSegments, limit int64, startKey map[string]*dynamodb.AttributeValue) (items []map[string]*dynamodb.AttributeValue, lastEvaluatedKey map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error) {
+			if totalSegments != exportSegments {
+				t.Errorf("expected %d segments, got %d", exportSegments, totalSegments)
+			}
+			pages := segments[segment]
+			page := 0
+			if startKey != nil {
+				page = 1
+			}
+			if page < len(pages) {
+				items = pages[page]
+			}
+			if page < len(pages)-1 {
+				lastEvaluatedKey = items[len(items)-1]
+			}
+			return
+		}
+		s := NewStoreWithClient(client)
+		var buf bytes.Buffer
+		if err := s.Export(context.Background(), &buf, ExportJSONLines); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var actual []Node
+		for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+			var n Node
+			if err := json.Unmarshal([]byte(line), &n); err != nil {
+				t.Fatalf("failed to unmarshal line %q: %v", line, err)
+			}
+			actual = append(actual, n)
+		}
+		sort.Slice(actual, func(i, j int) bool { return actual[i].ID < actual[j].ID })
+		expected := []Node{
+			NewNode("nodeA").WithLabels("device").WithChildren(NewEdge("nodeB")),
+			NewNode("nodeB").WithParents(NewEdge("nodeA")),
+		}
+		if !reflect.DeepEqual(actual, expected) {
+			t.Errorf("\nexpected:\n%+v\n\ngot:\n%+v\n", expected, actual)
+		}
+	})
+	t.Run("Database errors are returned", func(t *testing.T) {
+		client := newdynamoDBClient()
+		client.scanSegmenter = func(segment, totalSegments, limit int64, startKey map[string]*dynamodb.AttributeValue) (items []map[string]*dynamodb.AttributeValue, lastEvaluatedKey map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error) {
+			err = errTestDatabaseFailure
+			return
+		}
+		s := NewStoreWithClient(client)
+		if err := s.Export(context.Background(), &bytes.Buffer{}, ExportJSONLines); err != errTestDatabaseFailure {
+			t.Errorf("expected err %v, got %v", errTestDatabaseFailure, err)
+		}
+	})
+	t.Run("Unknown formats result in an error", func(t *testing.T) {
+		s := NewStoreWithClient(newdynamoDBClient())
+		if err := s.Export(context.Background(), &bytes.Buffer{}, ExportFormat(-1)); err != ErrUnsupportedExportFormat {
+			t.Errorf("expected err %v, got %v", ErrUnsupportedExportFormat, err)
+		}
+	})
+}
+
+func TestNodeJSONTimes(t *testing.T) {
+	created := time.Date(2019, time.January, 1, 0, 0, 0, 0, time.UTC)
+	withTimes := NewNode("a").WithChildren(NewEdge("b"))
+	withTimes.CreatedAt = created
+	withTimes.Children[0].TTL = created
+
+	tests := []struct {
+		name     string
+		node     Node
+		expected string
+	}{
+		{
+			name:     "times which aren't set are left out",
+			node:     NewNode("a").WithChildren(NewEdge("b")),
+			expected: `{"id":"a","data":{},"children":[{"id":"b","data":{}}],"parents":null}`,
+		},
+		{
+			name:     "times which are set are included",
+			node:     withTimes,
+			expected: `{"id":"a","data":{},"children":[{"id":"b","data":{},"ttl":"2019-01-01T00:00:00Z"}],"parents":null,"createdAt":"2019-01-01T00:00:00Z"}`,
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			actual, err := json.Marshal(test.node)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(actual) != test.expected {
+				t.Errorf("expected %s, got %s", test.expected, actual)
+			}
+			var n Node
+			if err = json.Unmarshal(actual, &n); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !n.CreatedAt.Equal(test.node.CreatedAt) || !n.Children[0].TTL.Equal(test.node.Children[0].TTL) {
+				t.Errorf("expected the times to round trip, got %+v", n)
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package pregel
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestNewFileStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "pregel")
+	if err != nil {
+		t.Fatalf("failed to create directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	s, err := NewFileStore(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	s.Now = func() time.Time { return time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC) }
+	if err = s.Put(NewNode("a/1").WithLabels("letter")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err = s.Put(NewNode("b")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err = s.PutEdges("a/1", NewEdge("b")); err != nil {
+		t.Fatalf("unexpected error putting edges: %v", err)
+	}
+
+	actual, err := ioutil.ReadFile(filepath.Join(dir, "a%2F1.json"))
+	if err != nil {
+		t.Fatalf("failed to read the node's file: %v", err)
+	}
+	expected := `[
+  {
+    "id": {
+      "S": "a/1"
+    },
+    "rng": {
+      "S": "child/b"
+    }
+  },
+  {
+    "id": {
+      "S": "a/1"
+    },
+    "rng": {
+      "S": "node"
+    }
+  },
+  {
+    "_l": {
+      "S": "letter"
+    },
+    "id": {
+      "S": "a/1"
+    },
+    "rng": {
+      "S": "node/label/letter"
+    }
+  }
+]
+`
+	if string(actual) != expected {
+		t.Errorf("expected file:\n%s\ngot:\n%s", expected, actual)
+	}
+
+	t.Run("The graph is read from the directory", func(t *testing.T) {
+		s, err := NewFileStore(dir)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		n, ok, err := s.Get("a/1")
+		if err != nil || !ok {
+			t.Fatalf("expected node a/1 to be found, got %v, %v", ok, err)
+		}
+		if len(n.Children) != 1 || n.Children[0].ID != "b" {
+			t.Errorf("expected a/1 to have child b, got %+v", n.Children)
+		}
+		ids, _, err := s.QueryByLabel("letter", 0, "")
+		if err != nil || len(ids) != 1 || ids[0] != "a/1" {
+			t.Errorf("expected a/1 to be labelled, got %v, %v", ids, err)
+		}
+	})
+	t.Run("Deleting a node removes its file", func(t *testing.T) {
+		if err := s.Delete("b"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, err := os.Stat(filepath.Join(dir, "b.json")); !os.IsNotExist(err) {
+			t.Errorf("expected b.json to be removed, got %v", err)
+		}
+	})
+}
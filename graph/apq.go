@@ -0,0 +1,238 @@
+package graph
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"sync"
+
+	"github.com/a-h/pregel"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// PersistedQueryCache stores the documents of queries by their SHA-256 hash, so that clients can
+// send the hash of a query instead of the whole document.
+type PersistedQueryCache interface {
+	Get(ctx context.Context, hash string) (query string, ok bool, err error)
+	Add(ctx context.Context, hash, query string) error
+}
+
+// MemoryQueryCache is a PersistedQueryCache which holds up to Size queries in memory. When it's
+// full, the oldest query is removed.
+type MemoryQueryCache struct {
+	Size    int
+	m       sync.Mutex
+	queries map[string]string
+	hashes  []string
+}
+
+// NewMemoryQueryCache creates a cache which holds up to size queries in memory.
+func NewMemoryQueryCache(size int) *MemoryQueryCache {
+	return &MemoryQueryCache{
+		Size:    size,
+		queries: make(map[string]string),
+	}
+}
+
+// Get a query by its hash.
+func (c *MemoryQueryCache) Get(ctx context.Context, hash string) (query string, ok bool, err error) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	query, ok = c.queries[hash]
+	return
+}
+
+// Add a query to the cache.
+func (c *MemoryQueryCache) Add(ctx context.Context, hash, query string) error {
+	c.m.Lock()
+	defer c.m.Unlock()
+	if _, ok := c.queries[hash]; ok {
+		return nil
+	}
+	if c.Size > 0 && len(c.hashes) >= c.Size {
+		delete(c.queries, c.hashes[0])
+		c.hashes = c.hashes[1:]
+	}
+	c.queries[hash] = query
+	c.hashes = append(c.hashes, hash)
+	return nil
+}
+
+// DBQueryCache is a PersistedQueryCache which stores queries in a table with the same keys as the
+// pregel table, so that the queries are shared by every instance of the server, e.g. each Lambda
+// function. It should be a separate table to the graph, since the queries aren't nodes.
+type DBQueryCache struct {
+	Client pregel.DB
+}
+
+// NewDBQueryCache creates a cache which stores queries using the client.
+func NewDBQueryCache(client pregel.DB) *DBQueryCache {
+	return &DBQueryCache{
+		Client: client,
+	}
+}
+
+const persistedQueryRange = "query"
+
+func persistedQueryKey(hash string) map[string]*dynamodb.AttributeValue {
+	return map[string]*dynamodb.AttributeValue{
+		"id":  {S: aws.String(hash)},
+		"rng": {S: aws.String(persistedQueryRange)},
+	}
+}
+
+// Get a query by its hash.
+func (c *DBQueryCache) Get(ctx context.Context, hash string) (query string, ok bool, err error) {
+	itm, _, err := c.Client.GetItem(ctx, persistedQueryKey(hash))
+	if err != nil || itm == nil {
+		return
+	}
+	if v, hasQuery := itm[persistedQueryRange]; hasQuery && v.S != nil {
+		return *v.S, true, nil
+	}
+	return
+}
+
+// Add a query to the cache.
+func (c *DBQueryCache) Add(ctx context.Context, hash, query string) (err error) {
+	itm := persistedQueryKey(hash)
+	itm[persistedQueryRange] = &dynamodb.AttributeValue{S: aws.String(query)}
+	_, err = c.Client.BatchPut(ctx, []map[string]*dynamodb.AttributeValue{itm})
+	return
+}
+
+// PersistedQueryNotFound is the error returned to clients when the hash of a query isn't in the
+// cache, so that they send the hash again with the query.
+const PersistedQueryNotFound = "PersistedQueryNotFound"
+
+// PersistedQueryMiddleware implements automatic persisted queries, as used by Apollo clients. If a
+// request has the hash of a query in its persistedQuery extension, but no query, the query is read
+// from the Cache. If the hash isn't in the Cache, the PersistedQueryNotFound error is returned, and
+// the client sends the hash again with the query, which is added to the Cache.
+//
+// Only POST requests with an application/json body are read, up to MaxBodySize bytes. Other
+// requests, such as multipart file uploads, are passed to the Next handler untouched.
+type PersistedQueryMiddleware struct {
+	Next        http.Handler
+	Cache       PersistedQueryCache
+	MaxBodySize int64
+}
+
+// DefaultMaxBodySize is the largest JSON request body that the PersistedQueryMiddleware reads.
+const DefaultMaxBodySize = 1 << 20
+
+type persistedQueryExtensions struct {
+	PersistedQuery *struct {
+		Version    int    `json:"version"`
+		SHA256Hash string `json:"sha256Hash"`
+	} `json:"persistedQuery"`
+}
+
+func (pqm *PersistedQueryMiddleware) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		params := r.URL.Query()
+		query, ok := pqm.query(w, r, params.Get("query"), []byte(params.Get("extensions")))
+		if !ok {
+			return
+		}
+		params.Set("query", query)
+		r.URL.RawQuery = params.Encode()
+	case http.MethodPost:
+		if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType != "application/json" {
+			break
+		}
+		maxBodySize := pqm.MaxBodySize
+		if maxBodySize <= 0 {
+			maxBodySize = DefaultMaxBodySize
+		}
+		body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, maxBodySize))
+		if err != nil {
+			if int64(len(body)) >= maxBodySize {
+				writeGraphQLError(w, http.StatusRequestEntityTooLarge, "request body too large")
+				return
+			}
+			writeGraphQLError(w, http.StatusBadRequest, "failed to read request body")
+			return
+		}
+		r.Body = ioutil.NopCloser(bytes.NewReader(body))
+		var params map[string]json.RawMessage
+		if json.Unmarshal(body, &params) != nil {
+			// Leave batches of queries and invalid requests to the next handler.
+			break
+		}
+		var query string
+		if q, hasQuery := params["query"]; hasQuery && json.Unmarshal(q, &query) != nil {
+			break
+		}
+		query, ok := pqm.query(w, r, query, params["extensions"])
+		if !ok {
+			return
+		}
+		if params["query"], err = json.Marshal(query); err != nil {
+			writeGraphQLError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if body, err = json.Marshal(params); err != nil {
+			writeGraphQLError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		r.Body = ioutil.NopCloser(bytes.NewReader(body))
+		r.ContentLength = int64(len(body))
+	}
+	pqm.Next.ServeHTTP(w, r)
+}
+
+// query returns the query of the request, reading it from the cache if the request only contains
+// its hash. If ok is false, an error has been written to the response.
+func (pqm *PersistedQueryMiddleware) query(w http.ResponseWriter, r *http.Request, query string, extensions []byte) (q string, ok bool) {
+	var ext persistedQueryExtensions
+	if len(extensions) == 0 || json.Unmarshal(extensions, &ext) != nil || ext.PersistedQuery == nil {
+		return query, true
+	}
+	hash := ext.PersistedQuery.SHA256Hash
+	if query == "" {
+		cached, found, err := pqm.Cache.Get(r.Context(), hash)
+		if err != nil {
+			writeGraphQLError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if !found {
+			writeGraphQLError(w, http.StatusOK, PersistedQueryNotFound)
+			return
+		}
+		return cached, true
+	}
+	sum := sha256.Sum256([]byte(query))
+	if hex.EncodeToString(sum[:]) != hash {
+		writeGraphQLError(w, http.StatusBadRequest, "provided sha does not match query")
+		return
+	}
+	// If the query can't be cached, it's still run, and the client will send it again next time.
+	pqm.Cache.Add(r.Context(), hash, query)
+	return query, true
+}
+
+func writeGraphQLError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"errors": []map[string]string{{"message": message}},
+	})
+}
+
+// WithPersistedQueryMiddleware reads the queries of requests which only contain the hash of the query
+// from the cache.
+func WithPersistedQueryMiddleware(cache PersistedQueryCache, next http.Handler) *PersistedQueryMiddleware {
+	return &PersistedQueryMiddleware{
+		Next:        next,
+		Cache:       cache,
+		MaxBodySize: DefaultMaxBodySize,
+	}
+}
@@ -0,0 +1,175 @@
+package graph
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/a-h/pregel/db/memory"
+)
+
+func TestPersistedQueryMiddleware(t *testing.T) {
+	query := `{ get(id: "a") { nodeId } }`
+	sum := sha256.Sum256([]byte(query))
+	hash := hex.EncodeToString(sum[:])
+	extensions := `{"persistedQuery":{"version":1,"sha256Hash":"` + hash + `"}}`
+
+	var actualQuery string
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			actualQuery = r.URL.Query().Get("query")
+			return
+		}
+		var params struct {
+			Query string `json:"query"`
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		json.Unmarshal(body, &params)
+		actualQuery = params.Query
+	})
+	h := WithPersistedQueryMiddleware(NewMemoryQueryCache(10), next)
+	serve := func(r *http.Request) *httptest.ResponseRecorder {
+		actualQuery = ""
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w
+	}
+	post := func(body string) *http.Request {
+		r := httptest.NewRequest(http.MethodPost, "/query", strings.NewReader(body))
+		r.Header.Set("Content-Type", "application/json")
+		return r
+	}
+
+	// The steps depend on each other, since they share the cache.
+	w := serve(post(`{"extensions":` + extensions + `}`))
+	if !strings.Contains(w.Body.String(), PersistedQueryNotFound) {
+		t.Errorf("expected %s for an unknown hash, got %d %s", PersistedQueryNotFound, w.Code, w.Body.String())
+	}
+	if actualQuery != "" {
+		t.Errorf("expected the next handler not to be called, got query %q", actualQuery)
+	}
+
+	body, _ := json.Marshal(query)
+	serve(post(`{"query":` + string(body) + `,"extensions":` + extensions + `}`))
+	if actualQuery != query {
+		t.Errorf("expected the query to be passed on, got %q", actualQuery)
+	}
+
+	serve(post(`{"extensions":` + extensions + `}`))
+	if actualQuery != query {
+		t.Errorf("expected the cached query to be used, got %q", actualQuery)
+	}
+
+	serve(httptest.NewRequest(http.MethodGet, "/query?extensions="+url.QueryEscape(extensions), nil))
+	if actualQuery != query {
+		t.Errorf("expected the cached query to be used for GET requests, got %q", actualQuery)
+	}
+
+	w = serve(post(`{"query":"{ other }","extensions":` + extensions + `}`))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected queries which don't match the hash to be rejected, got %d", w.Code)
+	}
+
+	serve(post(`{"query":"{ other }"}`))
+	if actualQuery != "{ other }" {
+		t.Errorf("expected requests without a hash to be passed on, got %q", actualQuery)
+	}
+}
+
+func TestPersistedQueryMiddlewareBody(t *testing.T) {
+	var tests = []struct {
+		name               string
+		contentType        string
+		body               string
+		expectedStatusCode int
+		expectedNextBody   string
+	}{
+		{
+			name:               "multipart requests are passed on untouched",
+			contentType:        "multipart/form-data; boundary=xyz",
+			body:               strings.Repeat("a", 200),
+			expectedStatusCode: http.StatusOK,
+			expectedNextBody:   strings.Repeat("a", 200),
+		},
+		{
+			name:               "JSON requests within the limit are read",
+			contentType:        "application/json; charset=utf-8",
+			body:               `{"query":"{ a }"}`,
+			expectedStatusCode: http.StatusOK,
+			expectedNextBody:   `{"query":"{ a }"}`,
+		},
+		{
+			name:               "JSON requests over the limit are rejected",
+			contentType:        "application/json",
+			body:               `{"query":"` + strings.Repeat("a", 200) + `"}`,
+			expectedStatusCode: http.StatusRequestEntityTooLarge,
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			var actualNextBody string
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := ioutil.ReadAll(r.Body)
+				actualNextBody = string(body)
+			})
+			h := WithPersistedQueryMiddleware(NewMemoryQueryCache(10), next)
+			h.MaxBodySize = 100
+			r := httptest.NewRequest(http.MethodPost, "/query", strings.NewReader(test.body))
+			r.Header.Set("Content-Type", test.contentType)
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+			if w.Code != test.expectedStatusCode {
+				t.Errorf("expected status %d, got %d", test.expectedStatusCode, w.Code)
+			}
+			if actualNextBody != test.expectedNextBody {
+				t.Errorf("expected the next handler to receive %q, got %q", test.expectedNextBody, actualNextBody)
+			}
+		})
+	}
+}
+
+func TestQueryCaches(t *testing.T) {
+	caches := map[string]PersistedQueryCache{
+		"memory": NewMemoryQueryCache(2),
+		"db":     NewDBQueryCache(memory.New()),
+	}
+	for name, c := range caches {
+		c := c
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			if _, ok, err := c.Get(ctx, "a"); ok || err != nil {
+				t.Errorf("expected a not to be found, got %v, %v", ok, err)
+			}
+			if err := c.Add(ctx, "a", "query a"); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if q, ok, err := c.Get(ctx, "a"); !ok || err != nil || q != "query a" {
+				t.Errorf("expected query a, got %q, %v, %v", q, ok, err)
+			}
+		})
+	}
+	t.Run("The oldest queries are removed from full memory caches", func(t *testing.T) {
+		ctx := context.Background()
+		c := NewMemoryQueryCache(2)
+		for _, h := range []string{"a", "b", "c"} {
+			c.Add(ctx, h, "query "+h)
+		}
+		if _, ok, _ := c.Get(ctx, "a"); ok {
+			t.Errorf("expected a to be removed")
+		}
+		if _, ok, _ := c.Get(ctx, "c"); !ok {
+			t.Errorf("expected c to be found")
+		}
+	})
+}
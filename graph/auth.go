@@ -0,0 +1,422 @@
+package graph
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/a-h/pregel"
+	"github.com/a-h/pregel/graph/gqlid"
+)
+
+// ErrMissingCredentials is returned by an Authenticator when the request doesn't contain
+// credentials.
+var ErrMissingCredentials = errors.New("graph: missing credentials")
+
+// ErrInvalidCredentials is returned by an Authenticator when the request's credentials aren't valid,
+// e.g. an unknown API key, or a JWT with an invalid signature or which has expired.
+var ErrInvalidCredentials = errors.New("graph: invalid credentials")
+
+// ErrForbidden is returned by an Authorizer when the caller isn't allowed to run the operation.
+var ErrForbidden = errors.New("graph: forbidden")
+
+// Principal is an authenticated caller.
+type Principal struct {
+	// ID of the caller, e.g. the subject of a JWT.
+	ID string
+	// Scopes granted to the caller, e.g. "pregel:write".
+	Scopes []string
+	// Tenant of the caller, e.g. the tenant claim of a JWT, used by TenantFromPrincipal.
+	Tenant string
+}
+
+// HasScope returns true if the principal has been granted the scope.
+func (p Principal) HasScope(scope string) bool {
+	for _, s := range p.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+type principalKey struct{}
+
+// WithPrincipal returns a context containing the principal.
+func WithPrincipal(ctx context.Context, p Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// PrincipalFromContext returns the principal added to the context by the AuthMiddleware.
+func PrincipalFromContext(ctx context.Context) (p Principal, ok bool) {
+	p, ok = ctx.Value(principalKey{}).(Principal)
+	return
+}
+
+// Authenticator returns the caller of a HTTP request, or ErrMissingCredentials or
+// ErrInvalidCredentials if the caller can't be authenticated.
+type Authenticator func(r *http.Request) (p Principal, err error)
+
+// AuthMiddleware authenticates each request, adding the Principal to the request's context before
+// calling the next handler. Requests which can't be authenticated are rejected with a 401 status.
+type AuthMiddleware struct {
+	Next         http.Handler
+	Authenticate Authenticator
+}
+
+func (am *AuthMiddleware) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	p, err := am.Authenticate(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	am.Next.ServeHTTP(w, r.WithContext(WithPrincipal(r.Context(), p)))
+}
+
+// WithAuthMiddleware authenticates requests before they're passed to the next handler.
+func WithAuthMiddleware(authenticate Authenticator, next http.Handler) *AuthMiddleware {
+	return &AuthMiddleware{
+		Next:         next,
+		Authenticate: authenticate,
+	}
+}
+
+// AuthenticateWithAny tries each of the authenticators in turn, returning the principal of the
+// first one which finds credentials in the request.
+func AuthenticateWithAny(authenticators ...Authenticator) Authenticator {
+	return func(r *http.Request) (p Principal, err error) {
+		err = ErrMissingCredentials
+		for _, a := range authenticators {
+			p, err = a(r)
+			if err != ErrMissingCredentials {
+				return
+			}
+		}
+		return
+	}
+}
+
+// APIKeyHeader is the HTTP header which contains the API key.
+const APIKeyHeader = "X-API-Key"
+
+// APIKeyAuthenticator authenticates requests using the API key in the X-API-Key header, returning
+// the principal of the key.
+func APIKeyAuthenticator(keys map[string]Principal) Authenticator {
+	return func(r *http.Request) (p Principal, err error) {
+		key := r.Header.Get(APIKeyHeader)
+		if key == "" {
+			err = ErrMissingCredentials
+			return
+		}
+		// Compare every key, so that the time taken doesn't depend on which key matches.
+		var ok bool
+		for k, kp := range keys {
+			if subtle.ConstantTimeCompare([]byte(k), []byte(key)) == 1 {
+				p, ok = kp, true
+			}
+		}
+		if !ok {
+			err = ErrInvalidCredentials
+		}
+		return
+	}
+}
+
+// JWTAuthenticator authenticates requests using a JWT in the Authorization header, e.g.
+// "Authorization: Bearer <token>". The token must be signed using HS256 with the secret, and
+// mustn't have expired. The principal's ID is the token's "sub" claim, and its scopes are the
+// space separated values of the "scope" claim.
+func JWTAuthenticator(secret []byte) Authenticator {
+	return func(r *http.Request) (p Principal, err error) {
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if token == "" || token == r.Header.Get("Authorization") {
+			err = ErrMissingCredentials
+			return
+		}
+		return parseJWT(token, secret, time.Now())
+	}
+}
+
+type jwtHeader struct {
+	Algorithm string `json:"alg"`
+}
+
+type jwtClaims struct {
+	Subject   string `json:"sub"`
+	Scope     string `json:"scope"`
+	Tenant    string `json:"tenant"`
+	ExpiresAt *int64 `json:"exp"`
+	NotBefore *int64 `json:"nbf"`
+}
+
+func parseJWT(token string, secret []byte, now time.Time) (p Principal, err error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		err = ErrInvalidCredentials
+		return
+	}
+	var h jwtHeader
+	if err = decodeJWTPart(parts[0], &h); err != nil || h.Algorithm != "HS256" {
+		err = ErrInvalidCredentials
+		return
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		err = ErrInvalidCredentials
+		return
+	}
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(parts[0] + "." + parts[1]))
+	if !hmac.Equal(sig, mac.Sum(nil)) {
+		err = ErrInvalidCredentials
+		return
+	}
+	var c jwtClaims
+	if err = decodeJWTPart(parts[1], &c); err != nil {
+		err = ErrInvalidCredentials
+		return
+	}
+	if (c.ExpiresAt != nil && now.Unix() >= *c.ExpiresAt) || (c.NotBefore != nil && now.Unix() < *c.NotBefore) {
+		err = ErrInvalidCredentials
+		return
+	}
+	p = Principal{
+		ID:     c.Subject,
+		Scopes: strings.Fields(c.Scope),
+		Tenant: c.Tenant,
+	}
+	return
+}
+
+func decodeJWTPart(s string, v interface{}) error {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+// Operation is a query or mutation which is being authorized.
+type Operation struct {
+	// Field is the name of the query or mutation, e.g. "get" or "saveNode".
+	Field string
+	// Mutation is true if the operation writes to the store.
+	Mutation bool
+	// NodeID is the ID of the node which is being read or written, or empty if the operation
+	// doesn't target a node, e.g. the nodes query.
+	NodeID string
+	// Admin is true if the operation can change any part of the graph, e.g. importGraph, so it
+	// should only be run by administrators.
+	Admin bool
+}
+
+// Authorizer is called before each query and mutation is resolved, with the principal in the
+// context, if there is one. If it returns an error, the operation isn't run and the error is
+// returned to the caller. Mutations which write to more than one node, e.g. saveEdge, are
+// authorized once for each node.
+type Authorizer func(ctx context.Context, op Operation) error
+
+// RequireScopes is an Authorizer which requires the principal to have the read scope to run queries,
+// and the write scope to run mutations.
+func RequireScopes(read, write string) Authorizer {
+	return func(ctx context.Context, op Operation) error {
+		p, _ := PrincipalFromContext(ctx)
+		scope := read
+		if op.Mutation {
+			scope = write
+		}
+		if !p.HasScope(scope) {
+			return ErrForbidden
+		}
+		return nil
+	}
+}
+
+// RequireAdminScope wraps an Authorizer, so that admin operations, e.g. importGraph, also require the
+// principal to have the admin scope.
+func RequireAdminScope(admin string, next Authorizer) Authorizer {
+	return func(ctx context.Context, op Operation) error {
+		if op.Admin {
+			if p, _ := PrincipalFromContext(ctx); !p.HasScope(admin) {
+				return ErrForbidden
+			}
+		}
+		return next(ctx, op)
+	}
+}
+
+// NewAuthorizedResolver wraps the queries and mutations of the resolver, so that authorize is called
+// with the target node ID of each before it's resolved. The fields of the nodes that are returned
+// aren't authorized, so a caller which can get a node can also read its parents and children.
+// Subscriptions, and the nodes returned by mutations, are authorized as queries.
+func NewAuthorizedResolver(r *Resolver, authorize Authorizer) *Resolver {
+	ar := &Resolver{
+		MutationResolver: &authorizedMutationResolver{next: r.MutationResolver, authorize: authorize},
+		NodeResolver:     r.NodeResolver,
+		QueryResolver:    &authorizedQueryResolver{next: r.QueryResolver, authorize: authorize},
+		authorize:        authorize,
+	}
+	if r.SubscriptionResolver != nil {
+		ar.SubscriptionResolver = &authorizedSubscriptionResolver{next: r.SubscriptionResolver, authorize: authorize}
+	}
+	return ar
+}
+
+type authorizedMutationResolver struct {
+	next      MutationResolver
+	authorize Authorizer
+}
+
+func (r *authorizedMutationResolver) check(ctx context.Context, field string, ids ...string) error {
+	for _, id := range ids {
+		if err := r.authorize(ctx, Operation{Field: field, Mutation: true, NodeID: id}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *authorizedMutationResolver) SaveNode(ctx context.Context, node SaveNodeInput) (*SaveNodeOutput, error) {
+	ids := append(append([]string{node.ID}, node.Parents...), node.Children...)
+	if err := r.check(ctx, "saveNode", ids...); err != nil {
+		return nil, err
+	}
+	return r.next.SaveNode(ctx, node)
+}
+
+func (r *authorizedMutationResolver) SaveEdge(ctx context.Context, edge SaveEdgeInput) (*SaveEdgeOutput, error) {
+	if err := r.check(ctx, "saveEdge", edge.Parent, edge.Child); err != nil {
+		return nil, err
+	}
+	return r.next.SaveEdge(ctx, edge)
+}
+
+func (r *authorizedMutationResolver) RemoveNode(ctx context.Context, input RemoveNodeInput) (*RemoveNodeOutput, error) {
+	if err := r.check(ctx, "removeNode", input.ID); err != nil {
+		return nil, err
+	}
+	return r.next.RemoveNode(ctx, input)
+}
+
+func (r *authorizedMutationResolver) RemoveEdge(ctx context.Context, input RemoveEdgeInput) (*RemoveEdgeOutput, error) {
+	if err := r.check(ctx, "removeEdge", input.Parent, input.Child); err != nil {
+		return nil, err
+	}
+	return r.next.RemoveEdge(ctx, input)
+}
+
+func (r *authorizedMutationResolver) SetNodeFields(ctx context.Context, input SetNodeFieldsInput) (*SetNodeFieldsOutput, error) {
+	if err := r.check(ctx, "setNodeFields", input.ID); err != nil {
+		return nil, err
+	}
+	return r.next.SetNodeFields(ctx, input)
+}
+
+func (r *authorizedMutationResolver) SetEdgeFields(ctx context.Context, input SetEdgeFieldsInput) (*SetEdgeFieldsOutput, error) {
+	if err := r.check(ctx, "setEdgeFields", input.Parent, input.Child); err != nil {
+		return nil, err
+	}
+	return r.next.SetEdgeFields(ctx, input)
+}
+
+func (r *authorizedMutationResolver) SetNodeData(ctx context.Context, input SetNodeDataInput) (*SetNodeDataOutput, error) {
+	if err := r.check(ctx, "setNodeData", input.ID); err != nil {
+		return nil, err
+	}
+	return r.next.SetNodeData(ctx, input)
+}
+
+func (r *authorizedMutationResolver) SetEdgeData(ctx context.Context, input SetEdgeDataInput) (*SetEdgeDataOutput, error) {
+	if err := r.check(ctx, "setEdgeData", input.Parent, input.Child); err != nil {
+		return nil, err
+	}
+	return r.next.SetEdgeData(ctx, input)
+}
+
+func (r *authorizedMutationResolver) RemoveNodeData(ctx context.Context, input RemoveNodeDataInput) (*RemoveNodeDataOutput, error) {
+	if err := r.check(ctx, "removeNodeData", input.ID); err != nil {
+		return nil, err
+	}
+	return r.next.RemoveNodeData(ctx, input)
+}
+
+func (r *authorizedMutationResolver) RemoveEdgeData(ctx context.Context, input RemoveEdgeDataInput) (*RemoveEdgeDataOutput, error) {
+	if err := r.check(ctx, "removeEdgeData", input.Parent, input.Child); err != nil {
+		return nil, err
+	}
+	return r.next.RemoveEdgeData(ctx, input)
+}
+
+func (r *authorizedMutationResolver) ImportGraph(ctx context.Context, file graphql.Upload) (*ImportGraphOutput, error) {
+	if err := r.authorize(ctx, Operation{Field: "importGraph", Mutation: true, Admin: true}); err != nil {
+		return nil, err
+	}
+	return r.next.ImportGraph(ctx, file)
+}
+
+type authorizedQueryResolver struct {
+	next      QueryResolver
+	authorize Authorizer
+}
+
+func (r *authorizedQueryResolver) Get(ctx context.Context, id string) (*pregel.Node, error) {
+	if err := r.authorize(ctx, Operation{Field: "get", NodeID: id}); err != nil {
+		return nil, err
+	}
+	return r.next.Get(ctx, id)
+}
+
+func (r *authorizedQueryResolver) Node(ctx context.Context, id string) (RelayNode, error) {
+	// Authorize the ID of the node in pregel, rather than the global ID.
+	_, nodeID, err := gqlid.DecodeGlobal(id)
+	if err != nil {
+		return nil, err
+	}
+	if err = r.authorize(ctx, Operation{Field: "node", NodeID: nodeID}); err != nil {
+		return nil, err
+	}
+	return r.next.Node(ctx, id)
+}
+
+func (r *authorizedQueryResolver) Nodes(ctx context.Context, filter *NodeFilter, first int, after *string) (*Connection, error) {
+	if err := r.authorize(ctx, Operation{Field: "nodes"}); err != nil {
+		return nil, err
+	}
+	return r.next.Nodes(ctx, filter, first, after)
+}
+
+func (r *authorizedQueryResolver) Path(ctx context.Context, from string, to string, maxDepth *int) (*Path, error) {
+	for _, id := range []string{from, to} {
+		if err := r.authorize(ctx, Operation{Field: "path", NodeID: id}); err != nil {
+			return nil, err
+		}
+	}
+	return r.next.Path(ctx, from, to, maxDepth)
+}
+
+func (r *authorizedQueryResolver) Neighborhood(ctx context.Context, id string, depth int, direction *Direction) (*Subgraph, error) {
+	if err := r.authorize(ctx, Operation{Field: "neighborhood", NodeID: id}); err != nil {
+		return nil, err
+	}
+	return r.next.Neighborhood(ctx, id, depth, direction)
+}
+
+type authorizedSubscriptionResolver struct {
+	next      SubscriptionResolver
+	authorize Authorizer
+}
+
+func (r *authorizedSubscriptionResolver) NodeChanged(ctx context.Context, id *string) (<-chan *ChangeEvent, error) {
+	if err := r.authorize(ctx, Operation{Field: "nodeChanged", NodeID: stringValue(id)}); err != nil {
+		return nil, err
+	}
+	return r.next.NodeChanged(ctx, id)
+}
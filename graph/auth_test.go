@@ -0,0 +1,223 @@
+package graph
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/a-h/pregel"
+)
+
+func newTestJWT(header, claims string, secret []byte) string {
+	enc := base64.RawURLEncoding
+	unsigned := enc.EncodeToString([]byte(header)) + "." + enc.EncodeToString([]byte(claims))
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(unsigned))
+	return unsigned + "." + enc.EncodeToString(mac.Sum(nil))
+}
+
+func TestAuthMiddleware(t *testing.T) {
+	secret := []byte("secret")
+	hs256 := `{"alg":"HS256","typ":"JWT"}`
+	future, past := time.Now().Add(time.Hour).Unix(), time.Now().Add(-time.Hour).Unix()
+	authenticate := AuthenticateWithAny(
+		JWTAuthenticator(secret),
+		APIKeyAuthenticator(map[string]Principal{"key": {ID: "apikey"}}),
+	)
+
+	tests := []struct {
+		name              string
+		headers           map[string]string
+		expectedStatus    int
+		expectedPrincipal Principal
+	}{
+		{
+			name:           "Requests without credentials are rejected",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:              "API keys are accepted",
+			headers:           map[string]string{APIKeyHeader: "key"},
+			expectedStatus:    http.StatusOK,
+			expectedPrincipal: Principal{ID: "apikey"},
+		},
+		{
+			name:           "Unknown API keys are rejected",
+			headers:        map[string]string{APIKeyHeader: "other"},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name: "JWTs are accepted",
+			headers: map[string]string{
+				"Authorization": "Bearer " + newTestJWT(hs256, `{"sub":"ada","scope":"pregel:read pregel:write","exp":`+itoa(future)+`}`, secret),
+			},
+			expectedStatus:    http.StatusOK,
+			expectedPrincipal: Principal{ID: "ada", Scopes: []string{"pregel:read", "pregel:write"}},
+		},
+		{
+			name: "The tenant of a JWT is returned",
+			headers: map[string]string{
+				"Authorization": "Bearer " + newTestJWT(hs256, `{"sub":"ada","tenant":"acme"}`, secret),
+			},
+			expectedStatus:    http.StatusOK,
+			expectedPrincipal: Principal{ID: "ada", Scopes: []string{}, Tenant: "acme"},
+		},
+		{
+			name: "Expired JWTs are rejected",
+			headers: map[string]string{
+				"Authorization": "Bearer " + newTestJWT(hs256, `{"sub":"ada","exp":`+itoa(past)+`}`, secret),
+			},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name: "JWTs signed with another secret are rejected",
+			headers: map[string]string{
+				"Authorization": "Bearer " + newTestJWT(hs256, `{"sub":"ada"}`, []byte("other")),
+			},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name: "JWTs which aren't signed are rejected",
+			headers: map[string]string{
+				"Authorization": "Bearer " + newTestJWT(`{"alg":"none"}`, `{"sub":"ada"}`, secret),
+			},
+			expectedStatus: http.StatusUnauthorized,
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			var actualPrincipal Principal
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				actualPrincipal, _ = PrincipalFromContext(r.Context())
+			})
+			r := httptest.NewRequest("POST", "/query", nil)
+			for k, v := range test.headers {
+				r.Header.Set(k, v)
+			}
+			w := httptest.NewRecorder()
+			WithAuthMiddleware(authenticate, next).ServeHTTP(w, r)
+			if w.Code != test.expectedStatus {
+				t.Errorf("expected status %d, got %d", test.expectedStatus, w.Code)
+			}
+			if !reflect.DeepEqual(actualPrincipal, test.expectedPrincipal) {
+				t.Errorf("expected principal %+v, got %+v", test.expectedPrincipal, actualPrincipal)
+			}
+		})
+	}
+}
+
+func TestNewAuthorizedResolver(t *testing.T) {
+	var authorized []Operation
+	var removed bool
+	r := NewAuthorizedResolver(&Resolver{
+		MutationResolver: &PregelMutationResolver{
+			Store: &mockStorer{
+				edgePutter: func(parent string, edges ...*pregel.Edge) error { return nil },
+				deleter: func(id string) error {
+					removed = true
+					return nil
+				},
+			},
+		},
+		SubscriptionResolver: &PregelSubscriptionResolver{Changes: ChangeList{}},
+	}, func(ctx context.Context, op Operation) error {
+		authorized = append(authorized, op)
+		p, _ := PrincipalFromContext(ctx)
+		if op.Mutation && !p.HasScope("write") || op.NodeID == "secret" {
+			return ErrForbidden
+		}
+		return nil
+	})
+	ctx := WithPrincipal(context.Background(), Principal{ID: "reader", Scopes: []string{"read"}})
+
+	if _, err := r.Mutation().RemoveNode(ctx, RemoveNodeInput{ID: "a"}); err != ErrForbidden {
+		t.Errorf("expected ErrForbidden, got %v", err)
+	}
+	if removed {
+		t.Errorf("expected the node not to be removed")
+	}
+	ctx = WithPrincipal(context.Background(), Principal{ID: "writer", Scopes: []string{"write"}})
+	if _, err := r.Mutation().SaveEdge(ctx, SaveEdgeInput{Parent: "a", Child: "b"}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	saved := &SaveEdgeOutput{Parent: "a", Child: "b"}
+	if _, err := r.SaveEdgeOutput().ParentNode(withTestNodeLoader(ctx), saved); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	id := "c"
+	if _, err := r.Subscription().NodeChanged(ctx, &id); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if _, err := r.Query().Path(ctx, "a", "secret", nil); err != ErrForbidden {
+		t.Errorf("expected ErrForbidden for a path to a forbidden node, got %v", err)
+	}
+	expected := []Operation{
+		{Field: "removeNode", Mutation: true, NodeID: "a"},
+		{Field: "saveEdge", Mutation: true, NodeID: "a"},
+		{Field: "saveEdge", Mutation: true, NodeID: "b"},
+		{Field: "saveEdge", NodeID: "a"},
+		{Field: "nodeChanged", NodeID: "c"},
+		{Field: "path", NodeID: "a"},
+		{Field: "path", NodeID: "secret"},
+	}
+	if !reflect.DeepEqual(authorized, expected) {
+		t.Errorf("expected operations %+v, got %+v", expected, authorized)
+	}
+}
+
+func itoa(i int64) string {
+	return strconv.FormatInt(i, 10)
+}
+
+func TestRequireAdminScope(t *testing.T) {
+	authorize := RequireAdminScope("admin", RequireScopes("read", "write"))
+	tests := []struct {
+		name     string
+		scopes   []string
+		op       Operation
+		expected error
+	}{
+		{
+			name:   "admin operations are allowed with the admin scope",
+			scopes: []string{"write", "admin"},
+			op:     Operation{Field: "importGraph", Mutation: true, Admin: true},
+		},
+		{
+			name:     "admin operations require the admin scope",
+			scopes:   []string{"read", "write"},
+			op:       Operation{Field: "importGraph", Mutation: true, Admin: true},
+			expected: ErrForbidden,
+		},
+		{
+			name:     "admin operations also require the scopes of the next authorizer",
+			scopes:   []string{"admin"},
+			op:       Operation{Field: "importGraph", Mutation: true, Admin: true},
+			expected: ErrForbidden,
+		},
+		{
+			name:   "other operations don't require the admin scope",
+			scopes: []string{"write"},
+			op:     Operation{Field: "saveNode", Mutation: true, NodeID: "a"},
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			ctx := WithPrincipal(context.Background(), Principal{ID: "user", Scopes: test.scopes})
+			if err := authorize(ctx, test.op); err != test.expected {
+				t.Errorf("expected %v, got %v", test.expected, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package graph
+
+// DefaultComplexityLimit is the maximum complexity of a query, which can be used with gqlgen's
+// handler.ComplexityLimit. A query which reads 100 children of each of 100 children of a node has a
+// complexity of over 10000, so it's rejected.
+const DefaultComplexityLimit = 5000
+
+// unboundedConnectionSize is the number of edges assumed to be returned by a connection when first
+// is zero, which returns all of the edges.
+const unboundedConnectionSize = 1000
+
+// NewConfig creates the configuration of the schema, using complexity functions which multiply the
+// complexity of each edge of a connection by the number of edges requested, so that the complexity
+// of nested connections, e.g. children { children { children } }, reflects the number of nodes that
+// are read.
+func NewConfig(resolvers ResolverRoot) (c Config) {
+	c.Resolvers = resolvers
+	c.Complexity.GraphNode.Children = edgeConnectionComplexity
+	c.Complexity.GraphNode.Parents = edgeConnectionComplexity
+	c.Complexity.Query.Nodes = func(childComplexity int, filter *NodeFilter, first int, after *string) int {
+		return connectionComplexity(childComplexity, first, after)
+	}
+	c.Complexity.Query.Path = pathComplexity
+	c.Complexity.Query.Neighborhood = func(childComplexity int, id string, depth int, direction *Direction) int {
+		return 1 + MaxNeighborhoodNodes*childComplexity
+	}
+	return
+}
+
+// pathComplexity multiplies the complexity of the nodes and edges by the length of the longest path
+// which can be returned, which has one more node than it has edges.
+func pathComplexity(childComplexity int, from string, to string, maxDepth *int) int {
+	depth := DefaultPathDepth
+	if maxDepth != nil && *maxDepth >= 0 {
+		depth = *maxDepth
+	}
+	return 1 + (depth+1)*childComplexity
+}
+
+func edgeConnectionComplexity(childComplexity int, first int, after *string, orderBy *EdgeOrder) int {
+	return connectionComplexity(childComplexity, first, after)
+}
+
+func connectionComplexity(childComplexity int, first int, after *string) int {
+	if first < 1 || first > unboundedConnectionSize {
+		first = unboundedConnectionSize
+	}
+	return 1 + first*childComplexity
+}
@@ -0,0 +1,54 @@
+package graph
+
+import "testing"
+
+func TestConnectionComplexity(t *testing.T) {
+	c := NewConfig(&Resolver{})
+	tests := []struct {
+		name     string
+		actual   int
+		expected int
+	}{
+		{
+			name:     "The complexity of the edges is multiplied by the number requested",
+			actual:   c.Complexity.GraphNode.Children(3, 10, nil, nil),
+			expected: 31,
+		},
+		{
+			name:     "Requests for all of the edges are limited",
+			actual:   c.Complexity.GraphNode.Parents(3, 0, nil, nil),
+			expected: 1 + unboundedConnectionSize*3,
+		},
+		{
+			name:     "The nodes query is limited",
+			actual:   c.Complexity.Query.Nodes(3, nil, 10, nil),
+			expected: 31,
+		},
+		{
+			name:     "The path query is multiplied by the default depth",
+			actual:   c.Complexity.Query.Path(3, "a", "b", nil),
+			expected: 1 + (DefaultPathDepth+1)*3,
+		},
+		{
+			name:     "The neighborhood query is multiplied by the maximum number of nodes",
+			actual:   c.Complexity.Query.Neighborhood(3, "a", 1, nil),
+			expected: 1 + MaxNeighborhoodNodes*3,
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			if test.actual != test.expected {
+				t.Errorf("expected %d, got %d", test.expected, test.actual)
+			}
+		})
+	}
+	t.Run("Nested connections exceed the default limit", func(t *testing.T) {
+		children := c.Complexity.GraphNode.Children
+		if actual := children(children(3, 100, nil, nil), 100, nil, nil); actual <= DefaultComplexityLimit {
+			t.Errorf("expected complexity over %d, got %d", DefaultComplexityLimit, actual)
+		}
+	})
+}
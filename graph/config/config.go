@@ -0,0 +1,307 @@
+// Package config reads the settings of the GraphQL server and Lambda handlers from environment
+// variables, which can be overridden by command line flags, so that each handler reads them the
+// same way.
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/a-h/pregel/graph"
+)
+
+// The environment variables which contain the settings.
+const (
+	Region                = "PREGEL_DYNAMO_REGION"
+	TableName             = "PREGEL_DYNAMO_TABLE_NAME"
+	Store                 = "PREGEL_STORE"
+	Port                  = "PORT"
+	Playground            = "PREGEL_PLAYGROUND"
+	Debug                 = "PREGEL_DEBUG"
+	QueryTableName        = "PREGEL_QUERY_TABLE_NAME"
+	SubscriptionTableName = "PREGEL_SUBSCRIPTION_TABLE_NAME"
+	WebSocketEndpoint     = "PREGEL_WEBSOCKET_ENDPOINT"
+	LoaderMaxBatch        = "PREGEL_LOADER_MAX_BATCH"
+	LoaderWait            = "PREGEL_LOADER_WAIT"
+	LoaderConcurrency     = "PREGEL_LOADER_CONCURRENCY"
+	JWTSecret             = "PREGEL_JWT_SECRET"
+	APIKey                = "PREGEL_API_KEY"
+	CORSOrigins           = "PREGEL_CORS_ORIGINS"
+	CORSHeaders           = "PREGEL_CORS_HEADERS"
+	CORSCredentials       = "PREGEL_CORS_CREDENTIALS"
+	Tenants               = "PREGEL_TENANTS"
+)
+
+// The ways that the tenant of each request can be selected, set by PREGEL_TENANTS.
+const (
+	// TenantsFromHeader reads the tenant from the graph.TenantHeader.
+	TenantsFromHeader = "header"
+	// TenantsFromToken reads the tenant from the tenant claim of the caller's JWT.
+	TenantsFromToken = "token"
+)
+
+// DefaultPort is the port used by the local server if PORT isn't set.
+const DefaultPort = "8080"
+
+// ErrNoCredentials is returned by Authenticator when neither the JWT secret or the API key is set.
+var ErrNoCredentials = errors.New("config: " + JWTSecret + " or " + APIKey + " must be set")
+
+// ErrUnknownTenants is returned by TenantSelector when PREGEL_TENANTS isn't "header" or "token".
+var ErrUnknownTenants = errors.New("config: " + Tenants + " must be " + TenantsFromHeader + " or " + TenantsFromToken)
+
+// MissingError is returned by Require, listing the settings which aren't set.
+type MissingError []string
+
+func (me MissingError) Error() string {
+	return "config: " + strings.Join(me, ", ") + " not set"
+}
+
+// Config contains the settings of the handlers. Each handler only uses some of them.
+type Config struct {
+	Region    string
+	TableName string
+	// Store is set to "memory" to use an in-memory Store instead of DynamoDB.
+	Store string
+	Port  string
+	// Playground serves the GraphQL playground at "/".
+	Playground bool
+	// Debug adds the backend cost of each query to the extensions of its response.
+	Debug                 bool
+	QueryTableName        string
+	SubscriptionTableName string
+	WebSocketEndpoint     string
+	Loader                LoaderConfig
+	Auth                  AuthConfig
+	CORS                  CORSConfig
+	// Tenants selects the tenant of each request, so that each tenant has its own namespace of the
+	// table. If empty, every request uses the whole table.
+	Tenants string
+}
+
+// LoaderConfig contains the settings of the graph.NodeDataLoaderMiddlware.
+type LoaderConfig struct {
+	MaxBatch    int
+	Wait        time.Duration
+	Concurrency int
+}
+
+// AuthConfig contains the credentials that callers can use.
+type AuthConfig struct {
+	JWTSecret string
+	APIKey    string
+}
+
+// CORSConfig contains the settings of the graph.CORSMiddleware.
+type CORSConfig struct {
+	Origins     []string
+	Headers     []string
+	Credentials bool
+}
+
+// Default returns the settings used when they're not set, which can be changed before they're
+// passed to FromEnvironment, e.g. to set the default table of a local server.
+func Default() Config {
+	return Config{
+		Port:       DefaultPort,
+		Playground: true,
+		Loader: LoaderConfig{
+			MaxBatch: graph.DefaultMaxBatch,
+			Wait:     graph.DefaultWait,
+		},
+		CORS: CORSConfig{
+			Headers: graph.DefaultAllowedHeaders,
+		},
+	}
+}
+
+// FromEnvironment reads the settings from the environment variables and the command line flags of
+// the process, using the defaults for settings which aren't set.
+func FromEnvironment(defaults Config) (c Config, err error) {
+	return Parse(defaults, os.Args[1:], os.Getenv)
+}
+
+// Parse reads the settings using getenv, then overrides them with the flags in args, e.g.
+// "-table=graph".
+func Parse(defaults Config, args []string, getenv func(string) string) (c Config, err error) {
+	c = defaults
+	p := parser{getenv: getenv}
+	p.string(&c.Region, Region)
+	p.string(&c.TableName, TableName)
+	p.string(&c.Store, Store)
+	p.string(&c.Port, Port)
+	p.bool(&c.Playground, Playground)
+	p.bool(&c.Debug, Debug)
+	p.string(&c.QueryTableName, QueryTableName)
+	p.string(&c.SubscriptionTableName, SubscriptionTableName)
+	p.string(&c.WebSocketEndpoint, WebSocketEndpoint)
+	p.int(&c.Loader.MaxBatch, LoaderMaxBatch)
+	p.duration(&c.Loader.Wait, LoaderWait)
+	p.int(&c.Loader.Concurrency, LoaderConcurrency)
+	p.string(&c.Auth.JWTSecret, JWTSecret)
+	p.string(&c.Auth.APIKey, APIKey)
+	p.list(&c.CORS.Origins, CORSOrigins)
+	p.list(&c.CORS.Headers, CORSHeaders)
+	p.bool(&c.CORS.Credentials, CORSCredentials)
+	p.string(&c.Tenants, Tenants)
+	if p.err != nil {
+		return c, p.err
+	}
+
+	fs := flag.NewFlagSet("pregel", flag.ContinueOnError)
+	fs.SetOutput(ioutil.Discard)
+	fs.StringVar(&c.Region, "region", c.Region, "The AWS region of the DynamoDB tables.")
+	fs.StringVar(&c.TableName, "table", c.TableName, "The name of the pregel table.")
+	fs.StringVar(&c.Store, "store", c.Store, `Set to "memory" to use an in-memory store.`)
+	fs.StringVar(&c.Port, "port", c.Port, "The port of the local server.")
+	fs.BoolVar(&c.Playground, "playground", c.Playground, "Serve the GraphQL playground.")
+	fs.BoolVar(&c.Debug, "debug", c.Debug, "Add the cost of each query to its response.")
+	fs.IntVar(&c.Loader.MaxBatch, "loader-max-batch", c.Loader.MaxBatch, "The maximum number of nodes loaded in a batch.")
+	fs.DurationVar(&c.Loader.Wait, "loader-wait", c.Loader.Wait, "The time to wait for more nodes to be requested before loading a batch.")
+	fs.StringVar(&c.Tenants, "tenants", c.Tenants, `Set to "header" to select a namespace of the table using the `+graph.TenantHeader+` header.`)
+	fs.IntVar(&c.Loader.Concurrency, "loader-concurrency", c.Loader.Concurrency, "The maximum number of nodes loaded at once.")
+	err = fs.Parse(args)
+	return
+}
+
+// Require returns a MissingError if any of the settings, e.g. Region, aren't set.
+func (c Config) Require(settings ...string) error {
+	values := map[string]string{
+		Region:                c.Region,
+		TableName:             c.TableName,
+		Store:                 c.Store,
+		Port:                  c.Port,
+		QueryTableName:        c.QueryTableName,
+		SubscriptionTableName: c.SubscriptionTableName,
+		WebSocketEndpoint:     c.WebSocketEndpoint,
+		JWTSecret:             c.Auth.JWTSecret,
+		APIKey:                c.Auth.APIKey,
+	}
+	var missing MissingError
+	for _, s := range settings {
+		if values[s] == "" {
+			missing = append(missing, s)
+		}
+	}
+	if len(missing) > 0 {
+		return missing
+	}
+	return nil
+}
+
+// Authenticator returns an Authenticator which accepts a JWT signed with the JWT secret, or the
+// API key, which has the pregel:read, pregel:write and pregel:admin scopes. If neither is set,
+// ErrNoCredentials is returned.
+func (c Config) Authenticator() (a graph.Authenticator, err error) {
+	var authenticators []graph.Authenticator
+	if c.Auth.JWTSecret != "" {
+		authenticators = append(authenticators, graph.JWTAuthenticator([]byte(c.Auth.JWTSecret)))
+	}
+	if c.Auth.APIKey != "" {
+		authenticators = append(authenticators, graph.APIKeyAuthenticator(map[string]graph.Principal{
+			c.Auth.APIKey: {ID: "apikey", Scopes: []string{"pregel:read", "pregel:write", "pregel:admin"}},
+		}))
+	}
+	if len(authenticators) == 0 {
+		err = ErrNoCredentials
+		return
+	}
+	a = graph.AuthenticateWithAny(authenticators...)
+	return
+}
+
+// TenantSelector returns the TenantSelector of the Tenants setting. If it isn't set, ok is false.
+func (c Config) TenantSelector() (ts graph.TenantSelector, ok bool, err error) {
+	switch c.Tenants {
+	case "":
+		return
+	case TenantsFromHeader:
+		return graph.TenantFromHeader(graph.TenantHeader), true, nil
+	case TenantsFromToken:
+		return graph.TenantFromPrincipal(), true, nil
+	}
+	err = ErrUnknownTenants
+	return
+}
+
+// ConfigureLoader applies the loader settings, and the debug setting, to the middleware.
+func (c Config) ConfigureLoader(ndlm *graph.NodeDataLoaderMiddlware) {
+	ndlm.MaxBatch = c.Loader.MaxBatch
+	ndlm.Wait = c.Loader.Wait
+	ndlm.Concurrency = c.Loader.Concurrency
+	ndlm.Debug = c.Debug
+}
+
+// ConfigureCORS applies the CORS settings to the middleware. If credentials are allowed from any
+// origin, graph.ErrWildcardCredentials is returned.
+func (c Config) ConfigureCORS(cm *graph.CORSMiddleware) error {
+	cm.AllowedOrigins = c.CORS.Origins
+	cm.AllowedHeaders = c.CORS.Headers
+	cm.AllowCredentials = c.CORS.Credentials
+	return cm.Validate()
+}
+
+// parser reads environment variables, keeping the first error.
+type parser struct {
+	getenv func(string) string
+	err    error
+}
+
+func (p *parser) string(v *string, name string) {
+	if s := p.getenv(name); s != "" {
+		*v = s
+	}
+}
+
+func (p *parser) list(v *[]string, name string) {
+	var values []string
+	for _, s := range strings.Split(p.getenv(name), ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			values = append(values, s)
+		}
+	}
+	if len(values) > 0 {
+		*v = values
+	}
+}
+
+func (p *parser) bool(v *bool, name string) {
+	s := p.getenv(name)
+	if s == "" {
+		return
+	}
+	b, err := strconv.ParseBool(s)
+	p.fail(name, err)
+	*v = b
+}
+
+func (p *parser) int(v *int, name string) {
+	s := p.getenv(name)
+	if s == "" {
+		return
+	}
+	i, err := strconv.Atoi(s)
+	p.fail(name, err)
+	*v = i
+}
+
+func (p *parser) duration(v *time.Duration, name string) {
+	s := p.getenv(name)
+	if s == "" {
+		return
+	}
+	d, err := time.ParseDuration(s)
+	p.fail(name, err)
+	*v = d
+}
+
+func (p *parser) fail(name string, err error) {
+	if err != nil && p.err == nil {
+		p.err = fmt.Errorf("config: invalid %s: %v", name, err)
+	}
+}
@@ -0,0 +1,159 @@
+package config
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/a-h/pregel/graph"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name          string
+		env           map[string]string
+		args          []string
+		expected      func(c *Config)
+		expectedError bool
+	}{
+		{
+			name:     "defaults are used if nothing is set",
+			expected: func(c *Config) {},
+		},
+		{
+			name: "settings are read from the environment",
+			env: map[string]string{
+				Region:          "eu-west-1",
+				TableName:       "graph",
+				Playground:      "false",
+				Debug:           "1",
+				LoaderMaxBatch:  "25",
+				LoaderWait:      "5ms",
+				JWTSecret:       "secret",
+				CORSOrigins:     "https://a.com, https://b.com",
+				CORSCredentials: "true",
+			},
+			expected: func(c *Config) {
+				c.Region = "eu-west-1"
+				c.TableName = "graph"
+				c.Playground = false
+				c.Debug = true
+				c.Loader.MaxBatch = 25
+				c.Loader.Wait = 5 * time.Millisecond
+				c.Auth.JWTSecret = "secret"
+				c.CORS.Origins = []string{"https://a.com", "https://b.com"}
+				c.CORS.Credentials = true
+			},
+		},
+		{
+			name: "flags override the environment",
+			env: map[string]string{
+				TableName: "graph",
+				Port:      "9000",
+			},
+			args: []string{"-table=other", "-loader-concurrency", "4", "-playground=false"},
+			expected: func(c *Config) {
+				c.TableName = "other"
+				c.Port = "9000"
+				c.Loader.Concurrency = 4
+				c.Playground = false
+			},
+		},
+		{
+			name:          "invalid numbers are rejected",
+			env:           map[string]string{LoaderMaxBatch: "ten"},
+			expectedError: true,
+		},
+		{
+			name:          "invalid durations are rejected",
+			env:           map[string]string{LoaderWait: "10"},
+			expectedError: true,
+		},
+		{
+			name:          "unknown flags are rejected",
+			args:          []string{"-unknown"},
+			expectedError: true,
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			actual, err := Parse(Default(), test.args, func(name string) string {
+				return test.env[name]
+			})
+			if test.expectedError {
+				if err == nil {
+					t.Errorf("expected an error, got %+v", actual)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			expected := Default()
+			test.expected(&expected)
+			if !reflect.DeepEqual(actual, expected) {
+				t.Errorf("expected %+v, got %+v", expected, actual)
+			}
+		})
+	}
+}
+
+func TestRequire(t *testing.T) {
+	c := Default()
+	c.Region = "eu-west-2"
+	err := c.Require(Region, TableName, SubscriptionTableName)
+	missing, ok := err.(MissingError)
+	if !ok || !reflect.DeepEqual(missing, MissingError{TableName, SubscriptionTableName}) {
+		t.Errorf("expected the table names to be missing, got %v", err)
+	}
+	if err := c.Require(Region, Port); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestAuthenticator(t *testing.T) {
+	c := Default()
+	if _, err := c.Authenticator(); err != ErrNoCredentials {
+		t.Errorf("expected %v, got %v", ErrNoCredentials, err)
+	}
+	c.Auth.APIKey = "key"
+	authenticate, err := c.Authenticator()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	r := httptest.NewRequest(http.MethodPost, "/query", nil)
+	r.Header.Set(graph.APIKeyHeader, "key")
+	p, err := authenticate(r)
+	if err != nil || p.ID != "apikey" {
+		t.Errorf("expected the API key to be accepted, got %+v, %v", p, err)
+	}
+}
+
+func TestTenantSelector(t *testing.T) {
+	tests := []struct {
+		tenants     string
+		expectedOK  bool
+		expectedErr error
+	}{
+		{tenants: "", expectedOK: false},
+		{tenants: TenantsFromHeader, expectedOK: true},
+		{tenants: TenantsFromToken, expectedOK: true},
+		{tenants: "cookie", expectedErr: ErrUnknownTenants},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.tenants, func(t *testing.T) {
+			t.Parallel()
+			c := Default()
+			c.Tenants = test.tenants
+			ts, ok, err := c.TenantSelector()
+			if ok != test.expectedOK || err != test.expectedErr || (ok && ts == nil) {
+				t.Errorf("expected ok %v and error %v, got %v, %v", test.expectedOK, test.expectedErr, ok, err)
+			}
+		})
+	}
+}
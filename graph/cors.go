@@ -0,0 +1,148 @@
+package graph
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultAllowedHeaders are the request headers allowed by the CORSMiddleware if its AllowedHeaders
+// aren't set. They include the headers used to authenticate.
+var DefaultAllowedHeaders = []string{"Content-Type", "Authorization", APIKeyHeader}
+
+// ErrWildcardCredentials is returned by CORSMiddleware.Validate when credentials are allowed from
+// any origin, which would let any website make requests as the browser's user.
+var ErrWildcardCredentials = errors.New("graph: credentials can't be allowed from any origin")
+
+// CORSMiddleware allows browsers to call the API from the AllowedOrigins. Preflight requests are
+// answered without calling the next handler, so they must be handled before authentication, since
+// browsers don't send credentials with them.
+type CORSMiddleware struct {
+	Next http.Handler
+	// AllowedOrigins are the origins which can call the API, e.g. "https://example.com". If it
+	// contains "*", every origin is allowed.
+	AllowedOrigins []string
+	// AllowedHeaders are the request headers which browsers can send. If empty,
+	// DefaultAllowedHeaders is used.
+	AllowedHeaders []string
+	// AllowCredentials allows browsers to send cookies and authorization headers. It can't be used
+	// when AllowedOrigins contains "*".
+	AllowCredentials bool
+	// MaxAge is the time that browsers can cache the result of a preflight request. If zero, the
+	// browser's default is used.
+	MaxAge time.Duration
+}
+
+func (cm *CORSMiddleware) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		cm.Next.ServeHTTP(w, r)
+		return
+	}
+	h := w.Header()
+	h.Add("Vary", "Origin")
+	preflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+	if !cm.allowed(origin) {
+		if preflight {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		cm.Next.ServeHTTP(w, r)
+		return
+	}
+	// Credentials are never allowed from a wildcard origin, even if Validate wasn't called.
+	if cm.allowsAny() {
+		h.Set("Access-Control-Allow-Origin", "*")
+	} else {
+		h.Set("Access-Control-Allow-Origin", origin)
+		if cm.AllowCredentials {
+			h.Set("Access-Control-Allow-Credentials", "true")
+		}
+	}
+	if !preflight {
+		cm.Next.ServeHTTP(w, r)
+		return
+	}
+	headers := cm.AllowedHeaders
+	if len(headers) == 0 {
+		headers = DefaultAllowedHeaders
+	}
+	h.Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
+	h.Set("Access-Control-Allow-Headers", strings.Join(headers, ", "))
+	if cm.MaxAge > 0 {
+		h.Set("Access-Control-Max-Age", strconv.Itoa(int(cm.MaxAge/time.Second)))
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Validate returns ErrWildcardCredentials if AllowCredentials is set and AllowedOrigins contains "*".
+func (cm *CORSMiddleware) Validate() error {
+	if cm.AllowCredentials && cm.allowsAny() {
+		return ErrWildcardCredentials
+	}
+	return nil
+}
+
+func (cm *CORSMiddleware) allowed(origin string) bool {
+	for _, o := range cm.AllowedOrigins {
+		if o == "*" || strings.EqualFold(o, origin) {
+			return true
+		}
+	}
+	return false
+}
+
+func (cm *CORSMiddleware) allowsAny() bool {
+	for _, o := range cm.AllowedOrigins {
+		if o == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// WithCORSMiddleware allows browsers to call the next handler from the origins. The headers,
+// credentials and preflight cache can be configured by setting the fields of the middleware.
+func WithCORSMiddleware(origins []string, next http.Handler) *CORSMiddleware {
+	return &CORSMiddleware{
+		Next:           next,
+		AllowedOrigins: origins,
+		AllowedHeaders: DefaultAllowedHeaders,
+	}
+}
+
+// DefaultSecurityHeaders are the headers added to each response by the SecurityHeadersMiddleware,
+// which stop browsers from sniffing the content type of responses, or displaying them in frames.
+var DefaultSecurityHeaders = map[string]string{
+	"X-Content-Type-Options":    "nosniff",
+	"X-Frame-Options":           "DENY",
+	"Referrer-Policy":           "no-referrer",
+	"Strict-Transport-Security": "max-age=31536000; includeSubDomains",
+}
+
+// SecurityHeadersMiddleware adds the Headers to each response.
+type SecurityHeadersMiddleware struct {
+	Next    http.Handler
+	Headers map[string]string
+}
+
+func (shm *SecurityHeadersMiddleware) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	for k, v := range shm.Headers {
+		w.Header().Set(k, v)
+	}
+	shm.Next.ServeHTTP(w, r)
+}
+
+// WithSecurityHeadersMiddleware adds the DefaultSecurityHeaders to the responses of the next handler.
+func WithSecurityHeadersMiddleware(next http.Handler) *SecurityHeadersMiddleware {
+	headers := make(map[string]string, len(DefaultSecurityHeaders))
+	for k, v := range DefaultSecurityHeaders {
+		headers[k] = v
+	}
+	return &SecurityHeadersMiddleware{
+		Next:    next,
+		Headers: headers,
+	}
+}
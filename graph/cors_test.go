@@ -0,0 +1,214 @@
+package graph
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCORSMiddleware(t *testing.T) {
+	tests := []struct {
+		name               string
+		origins            []string
+		credentials        bool
+		method             string
+		origin             string
+		requestMethod      string
+		expectedStatus     int
+		expectedNextCalled bool
+		expectedHeaders    map[string]string
+	}{
+		{
+			name:               "requests without an origin are passed on",
+			origins:            []string{"https://example.com"},
+			method:             http.MethodPost,
+			expectedStatus:     http.StatusOK,
+			expectedNextCalled: true,
+			expectedHeaders: map[string]string{
+				"Access-Control-Allow-Origin": "",
+			},
+		},
+		{
+			name:               "allowed origins are returned",
+			origins:            []string{"https://example.com"},
+			method:             http.MethodPost,
+			origin:             "https://example.com",
+			expectedStatus:     http.StatusOK,
+			expectedNextCalled: true,
+			expectedHeaders: map[string]string{
+				"Access-Control-Allow-Origin":      "https://example.com",
+				"Access-Control-Allow-Credentials": "",
+				"Vary":                             "Origin",
+			},
+		},
+		{
+			name:               "other origins aren't allowed",
+			origins:            []string{"https://example.com"},
+			method:             http.MethodPost,
+			origin:             "https://other.com",
+			expectedStatus:     http.StatusOK,
+			expectedNextCalled: true,
+			expectedHeaders: map[string]string{
+				"Access-Control-Allow-Origin": "",
+			},
+		},
+		{
+			name:               "a wildcard allows every origin",
+			origins:            []string{"*"},
+			method:             http.MethodGet,
+			origin:             "https://other.com",
+			expectedStatus:     http.StatusOK,
+			expectedNextCalled: true,
+			expectedHeaders: map[string]string{
+				"Access-Control-Allow-Origin": "*",
+			},
+		},
+		{
+			name:               "credentials are allowed from listed origins",
+			origins:            []string{"https://example.com"},
+			credentials:        true,
+			method:             http.MethodGet,
+			origin:             "https://example.com",
+			expectedStatus:     http.StatusOK,
+			expectedNextCalled: true,
+			expectedHeaders: map[string]string{
+				"Access-Control-Allow-Origin":      "https://example.com",
+				"Access-Control-Allow-Credentials": "true",
+			},
+		},
+		{
+			name:               "credentials are never allowed from a wildcard",
+			origins:            []string{"*"},
+			credentials:        true,
+			method:             http.MethodGet,
+			origin:             "https://other.com",
+			expectedStatus:     http.StatusOK,
+			expectedNextCalled: true,
+			expectedHeaders: map[string]string{
+				"Access-Control-Allow-Origin":      "*",
+				"Access-Control-Allow-Credentials": "",
+			},
+		},
+		{
+			name:           "preflight requests are answered without calling the next handler",
+			origins:        []string{"https://example.com"},
+			method:         http.MethodOptions,
+			origin:         "https://example.com",
+			requestMethod:  http.MethodPost,
+			expectedStatus: http.StatusNoContent,
+			expectedHeaders: map[string]string{
+				"Access-Control-Allow-Origin":  "https://example.com",
+				"Access-Control-Allow-Methods": "GET, POST, OPTIONS",
+				"Access-Control-Allow-Headers": "Content-Type, Authorization, X-API-Key",
+				"Access-Control-Max-Age":       "600",
+			},
+		},
+		{
+			name:           "preflight requests from other origins aren't allowed",
+			origins:        []string{"https://example.com"},
+			method:         http.MethodOptions,
+			origin:         "https://other.com",
+			requestMethod:  http.MethodPost,
+			expectedStatus: http.StatusNoContent,
+			expectedHeaders: map[string]string{
+				"Access-Control-Allow-Origin":  "",
+				"Access-Control-Allow-Methods": "",
+			},
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			var nextCalled bool
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				nextCalled = true
+			})
+			h := WithCORSMiddleware(test.origins, next)
+			h.AllowCredentials = test.credentials
+			h.MaxAge = 10 * time.Minute
+
+			r := httptest.NewRequest(test.method, "/query", nil)
+			if test.origin != "" {
+				r.Header.Set("Origin", test.origin)
+			}
+			if test.requestMethod != "" {
+				r.Header.Set("Access-Control-Request-Method", test.requestMethod)
+			}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			if w.Code != test.expectedStatus {
+				t.Errorf("expected status %d, got %d", test.expectedStatus, w.Code)
+			}
+			if nextCalled != test.expectedNextCalled {
+				t.Errorf("expected next called %v, got %v", test.expectedNextCalled, nextCalled)
+			}
+			for k, v := range test.expectedHeaders {
+				if actual := w.Header().Get(k); actual != v {
+					t.Errorf("expected header %s to be %q, got %q", k, v, actual)
+				}
+			}
+		})
+	}
+}
+
+func TestCORSMiddlewareValidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		origins     []string
+		credentials bool
+		expected    error
+	}{
+		{
+			name:        "credentials can be allowed from listed origins",
+			origins:     []string{"https://example.com"},
+			credentials: true,
+		},
+		{
+			name:    "any origin can be allowed without credentials",
+			origins: []string{"*"},
+		},
+		{
+			name:        "credentials can't be allowed from any origin",
+			origins:     []string{"https://example.com", "*"},
+			credentials: true,
+			expected:    ErrWildcardCredentials,
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			h := WithCORSMiddleware(test.origins, http.NotFoundHandler())
+			h.AllowCredentials = test.credentials
+			if err := h.Validate(); err != test.expected {
+				t.Errorf("expected error %v, got %v", test.expected, err)
+			}
+		})
+	}
+}
+
+func TestSecurityHeadersMiddleware(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Frame-Options", "SAMEORIGIN")
+	})
+	h := WithSecurityHeadersMiddleware(next)
+	h.Headers["Content-Security-Policy"] = "default-src 'none'"
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/query", nil))
+
+	for k, v := range map[string]string{
+		"X-Content-Type-Options":  "nosniff",
+		"X-Frame-Options":         "SAMEORIGIN",
+		"Content-Security-Policy": "default-src 'none'",
+	} {
+		if actual := w.Header().Get(k); actual != v {
+			t.Errorf("expected header %s to be %q, got %q", k, v, actual)
+		}
+	}
+	if _, ok := DefaultSecurityHeaders["Content-Security-Policy"]; ok {
+		t.Errorf("expected the default headers not to be modified")
+	}
+}
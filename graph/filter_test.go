@@ -1,7 +1,9 @@
 package graph
 
 import (
+	"reflect"
 	"testing"
+	"time"
 
 	"github.com/a-h/pregel/graph/gqlid"
 
@@ -157,3 +159,68 @@ func TestFilterEdges(t *testing.T) {
 		})
 	}
 }
+
+func TestSortEdges(t *testing.T) {
+	t0 := time.Date(2019, time.May, 1, 0, 0, 0, 0, time.UTC)
+	edge := func(id string, created, updated int) *pregel.Edge {
+		e := pregel.NewEdge(id)
+		e.CreatedAt = t0.Add(time.Duration(created) * time.Hour)
+		e.UpdatedAt = t0.Add(time.Duration(updated) * time.Hour)
+		return e
+	}
+	edges := []*pregel.Edge{
+		edge("a", 2, 3),
+		edge("b", 1, 5),
+		edge("c", 3, 4),
+		pregel.NewEdge("d"),
+	}
+	asc, desc := OrderDirectionAsc, OrderDirectionDesc
+
+	tests := []struct {
+		name        string
+		orderBy     *EdgeOrder
+		expectedIDs []string
+	}{
+		{
+			name:        "no order",
+			expectedIDs: []string{"a", "b", "c", "d"},
+		},
+		{
+			name:        "ID descending",
+			orderBy:     &EdgeOrder{Field: EdgeOrderFieldID, Direction: &desc},
+			expectedIDs: []string{"d", "c", "b", "a"},
+		},
+		{
+			name:        "created, without a direction",
+			orderBy:     &EdgeOrder{Field: EdgeOrderFieldCreatedAt},
+			expectedIDs: []string{"d", "b", "a", "c"},
+		},
+		{
+			name:        "updated ascending",
+			orderBy:     &EdgeOrder{Field: EdgeOrderFieldUpdatedAt, Direction: &asc},
+			expectedIDs: []string{"d", "a", "c", "b"},
+		},
+		{
+			name:        "updated descending",
+			orderBy:     &EdgeOrder{Field: EdgeOrderFieldUpdatedAt, Direction: &desc},
+			expectedIDs: []string{"b", "c", "a", "d"},
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			sorted := sortEdges(edges, test.orderBy)
+			var ids []string
+			for _, e := range sorted {
+				ids = append(ids, e.ID)
+			}
+			if !reflect.DeepEqual(ids, test.expectedIDs) {
+				t.Errorf("expected %v, got %v", test.expectedIDs, ids)
+			}
+		})
+	}
+	if edges[0].ID != "a" || edges[3].ID != "d" {
+		t.Errorf("expected the edges not to be reordered")
+	}
+}
@@ -7,8 +7,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"strconv"
 	"sync"
+	"time"
 
 	"github.com/99designs/gqlgen/graphql"
 	"github.com/99designs/gqlgen/graphql/introspection"
@@ -36,14 +38,28 @@ type Config struct {
 
 type ResolverRoot interface {
 	Mutation() MutationResolver
-	Node() NodeResolver
+	GraphNode() GraphNodeResolver
 	Query() QueryResolver
+	SaveEdgeOutput() SaveEdgeOutputResolver
+	SaveNodeOutput() SaveNodeOutputResolver
+	SetNodeFieldsOutput() SetNodeFieldsOutputResolver
+	Subscription() SubscriptionResolver
 }
 
 type DirectiveRoot struct {
 }
 
 type ComplexityRoot struct {
+	ChangeEvent struct {
+		Child    func(childComplexity int) int
+		DataType func(childComplexity int) int
+		Label    func(childComplexity int) int
+		New      func(childComplexity int) int
+		NodeID   func(childComplexity int) int
+		Time     func(childComplexity int) int
+		Type     func(childComplexity int) int
+	}
+
 	Computer struct {
 		Brand         func(childComplexity int) int
 		YearPurchased func(childComplexity int) int
@@ -56,9 +72,29 @@ type ComplexityRoot struct {
 	}
 
 	Edge struct {
-		Cursor func(childComplexity int) int
-		Data   func(childComplexity int) int
-		Node   func(childComplexity int) int
+		CreatedAt func(childComplexity int) int
+		Cursor    func(childComplexity int) int
+		Data      func(childComplexity int) int
+		JSON      func(childComplexity int) int
+		Node      func(childComplexity int) int
+		UpdatedAt func(childComplexity int) int
+	}
+
+	GraphNode struct {
+		Children  func(childComplexity int, first int, after *string, orderBy *EdgeOrder) int
+		CreatedAt func(childComplexity int) int
+		Data      func(childComplexity int) int
+		ID        func(childComplexity int) int
+		JSON      func(childComplexity int) int
+		NodeID    func(childComplexity int) int
+		Parents   func(childComplexity int, first int, after *string, orderBy *EdgeOrder) int
+		Typed     func(childComplexity int) int
+		UpdatedAt func(childComplexity int) int
+	}
+
+	ImportGraphOutput struct {
+		NodesRead      func(childComplexity int) int
+		RecordsWritten func(childComplexity int) int
 	}
 
 	Location struct {
@@ -66,20 +102,25 @@ type ComplexityRoot struct {
 		Lng func(childComplexity int) int
 	}
 
-	Mutation struct {
-		RemoveEdge    func(childComplexity int, input RemoveEdgeInput) int
-		RemoveNode    func(childComplexity int, input RemoveNodeInput) int
-		SaveEdge      func(childComplexity int, edge SaveEdgeInput) int
-		SaveNode      func(childComplexity int, node SaveNodeInput) int
-		SetEdgeFields func(childComplexity int, input SetEdgeFieldsInput) int
-		SetNodeFields func(childComplexity int, input SetNodeFieldsInput) int
+	LocationNode struct {
+		ID       func(childComplexity int) int
+		Location func(childComplexity int) int
+		Node     func(childComplexity int) int
+		NodeID   func(childComplexity int) int
 	}
 
-	Node struct {
-		Children func(childComplexity int, first int, after *string) int
-		Data     func(childComplexity int) int
-		ID       func(childComplexity int) int
-		Parents  func(childComplexity int, first int, after *string) int
+	Mutation struct {
+		ImportGraph    func(childComplexity int, file graphql.Upload) int
+		RemoveEdge     func(childComplexity int, input RemoveEdgeInput) int
+		RemoveEdgeData func(childComplexity int, input RemoveEdgeDataInput) int
+		RemoveNode     func(childComplexity int, input RemoveNodeInput) int
+		RemoveNodeData func(childComplexity int, input RemoveNodeDataInput) int
+		SaveEdge       func(childComplexity int, edge SaveEdgeInput) int
+		SaveNode       func(childComplexity int, node SaveNodeInput) int
+		SetEdgeData    func(childComplexity int, input SetEdgeDataInput) int
+		SetEdgeFields  func(childComplexity int, input SetEdgeFieldsInput) int
+		SetNodeData    func(childComplexity int, input SetNodeDataInput) int
+		SetNodeFields  func(childComplexity int, input SetNodeFieldsInput) int
 	}
 
 	PageInfo struct {
@@ -89,36 +130,94 @@ type ComplexityRoot struct {
 		StartCursor     func(childComplexity int) int
 	}
 
+	Path struct {
+		Edges func(childComplexity int) int
+		Nodes func(childComplexity int) int
+	}
+
 	Query struct {
-		Get func(childComplexity int, id string) int
+		Get          func(childComplexity int, id string) int
+		Neighborhood func(childComplexity int, id string, depth int, direction *Direction) int
+		Node         func(childComplexity int, id string) int
+		Nodes        func(childComplexity int, filter *NodeFilter, first int, after *string) int
+		Path         func(childComplexity int, from string, to string, maxDepth *int) int
+	}
+
+	RemoveEdgeDataOutput struct {
+		Removed func(childComplexity int) int
 	}
 
 	RemoveEdgeOutput struct {
 		Removed func(childComplexity int) int
 	}
 
+	RemoveNodeDataOutput struct {
+		Removed func(childComplexity int) int
+	}
+
 	RemoveNodeOutput struct {
 		Removed func(childComplexity int) int
 	}
 
 	SaveEdgeOutput struct {
-		Child  func(childComplexity int) int
-		Parent func(childComplexity int) int
+		Child      func(childComplexity int) int
+		ChildNode  func(childComplexity int) int
+		Parent     func(childComplexity int) int
+		ParentNode func(childComplexity int) int
 	}
 
 	SaveNodeOutput struct {
-		ID func(childComplexity int) int
+		ID   func(childComplexity int) int
+		Node func(childComplexity int) int
+	}
+
+	SetEdgeDataOutput struct {
+		Set func(childComplexity int) int
 	}
 
 	SetEdgeFieldsOutput struct {
 		Set func(childComplexity int) int
 	}
 
-	SetNodeFieldsOutput struct {
+	SetNodeDataOutput struct {
 		Set func(childComplexity int) int
 	}
+
+	SetNodeFieldsOutput struct {
+		ID   func(childComplexity int) int
+		Node func(childComplexity int) int
+		Set  func(childComplexity int) int
+	}
+
+	Subgraph struct {
+		Edges func(childComplexity int) int
+		Nodes func(childComplexity int) int
+		Root  func(childComplexity int) int
+	}
+
+	SubgraphEdge struct {
+		Child  func(childComplexity int) int
+		JSON   func(childComplexity int) int
+		Label  func(childComplexity int) int
+		Parent func(childComplexity int) int
+	}
+
+	Subscription struct {
+		NodeChanged func(childComplexity int, id *string) int
+	}
 }
 
+type GraphNodeResolver interface {
+	ID(ctx context.Context, obj *pregel.Node) (string, error)
+	NodeID(ctx context.Context, obj *pregel.Node) (string, error)
+	Parents(ctx context.Context, obj *pregel.Node, first int, after *string, orderBy *EdgeOrder) (*Connection, error)
+	Children(ctx context.Context, obj *pregel.Node, first int, after *string, orderBy *EdgeOrder) (*Connection, error)
+	Data(ctx context.Context, obj *pregel.Node) ([]NodeDataItem, error)
+	JSON(ctx context.Context, obj *pregel.Node) (map[string]interface{}, error)
+	CreatedAt(ctx context.Context, obj *pregel.Node) (*time.Time, error)
+	UpdatedAt(ctx context.Context, obj *pregel.Node) (*time.Time, error)
+	Typed(ctx context.Context, obj *pregel.Node) (RelayNode, error)
+}
 type MutationResolver interface {
 	SaveNode(ctx context.Context, node SaveNodeInput) (*SaveNodeOutput, error)
 	SaveEdge(ctx context.Context, edge SaveEdgeInput) (*SaveEdgeOutput, error)
@@ -126,14 +225,31 @@ type MutationResolver interface {
 	RemoveEdge(ctx context.Context, input RemoveEdgeInput) (*RemoveEdgeOutput, error)
 	SetNodeFields(ctx context.Context, input SetNodeFieldsInput) (*SetNodeFieldsOutput, error)
 	SetEdgeFields(ctx context.Context, input SetEdgeFieldsInput) (*SetEdgeFieldsOutput, error)
-}
-type NodeResolver interface {
-	Parents(ctx context.Context, obj *pregel.Node, first int, after *string) (*Connection, error)
-	Children(ctx context.Context, obj *pregel.Node, first int, after *string) (*Connection, error)
-	Data(ctx context.Context, obj *pregel.Node) ([]NodeDataItem, error)
+	SetNodeData(ctx context.Context, input SetNodeDataInput) (*SetNodeDataOutput, error)
+	SetEdgeData(ctx context.Context, input SetEdgeDataInput) (*SetEdgeDataOutput, error)
+	RemoveNodeData(ctx context.Context, input RemoveNodeDataInput) (*RemoveNodeDataOutput, error)
+	RemoveEdgeData(ctx context.Context, input RemoveEdgeDataInput) (*RemoveEdgeDataOutput, error)
+	ImportGraph(ctx context.Context, file graphql.Upload) (*ImportGraphOutput, error)
 }
 type QueryResolver interface {
 	Get(ctx context.Context, id string) (*pregel.Node, error)
+	Node(ctx context.Context, id string) (RelayNode, error)
+	Nodes(ctx context.Context, filter *NodeFilter, first int, after *string) (*Connection, error)
+	Path(ctx context.Context, from string, to string, maxDepth *int) (*Path, error)
+	Neighborhood(ctx context.Context, id string, depth int, direction *Direction) (*Subgraph, error)
+}
+type SaveEdgeOutputResolver interface {
+	ParentNode(ctx context.Context, obj *SaveEdgeOutput) (*pregel.Node, error)
+	ChildNode(ctx context.Context, obj *SaveEdgeOutput) (*pregel.Node, error)
+}
+type SaveNodeOutputResolver interface {
+	Node(ctx context.Context, obj *SaveNodeOutput) (*pregel.Node, error)
+}
+type SetNodeFieldsOutputResolver interface {
+	Node(ctx context.Context, obj *SetNodeFieldsOutput) (*pregel.Node, error)
+}
+type SubscriptionResolver interface {
+	NodeChanged(ctx context.Context, id *string) (<-chan *ChangeEvent, error)
 }
 
 type executableSchema struct {
@@ -151,6 +267,55 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 	_ = ec
 	switch typeName + "." + field {
 
+	case "ChangeEvent.child":
+		if e.complexity.ChangeEvent.Child == nil {
+			break
+		}
+
+		return e.complexity.ChangeEvent.Child(childComplexity), true
+
+	case "ChangeEvent.dataType":
+		if e.complexity.ChangeEvent.DataType == nil {
+			break
+		}
+
+		return e.complexity.ChangeEvent.DataType(childComplexity), true
+
+	case "ChangeEvent.label":
+		if e.complexity.ChangeEvent.Label == nil {
+			break
+		}
+
+		return e.complexity.ChangeEvent.Label(childComplexity), true
+
+	case "ChangeEvent.new":
+		if e.complexity.ChangeEvent.New == nil {
+			break
+		}
+
+		return e.complexity.ChangeEvent.New(childComplexity), true
+
+	case "ChangeEvent.nodeId":
+		if e.complexity.ChangeEvent.NodeID == nil {
+			break
+		}
+
+		return e.complexity.ChangeEvent.NodeID(childComplexity), true
+
+	case "ChangeEvent.time":
+		if e.complexity.ChangeEvent.Time == nil {
+			break
+		}
+
+		return e.complexity.ChangeEvent.Time(childComplexity), true
+
+	case "ChangeEvent.type":
+		if e.complexity.ChangeEvent.Type == nil {
+			break
+		}
+
+		return e.complexity.ChangeEvent.Type(childComplexity), true
+
 	case "Computer.brand":
 		if e.complexity.Computer.Brand == nil {
 			break
@@ -186,6 +351,13 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 		return e.complexity.Connection.TotalCount(childComplexity), true
 
+	case "Edge.createdAt":
+		if e.complexity.Edge.CreatedAt == nil {
+			break
+		}
+
+		return e.complexity.Edge.CreatedAt(childComplexity), true
+
 	case "Edge.cursor":
 		if e.complexity.Edge.Cursor == nil {
 			break
@@ -200,6 +372,13 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 		return e.complexity.Edge.Data(childComplexity), true
 
+	case "Edge.json":
+		if e.complexity.Edge.JSON == nil {
+			break
+		}
+
+		return e.complexity.Edge.JSON(childComplexity), true
+
 	case "Edge.node":
 		if e.complexity.Edge.Node == nil {
 			break
@@ -207,6 +386,100 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 		return e.complexity.Edge.Node(childComplexity), true
 
+	case "Edge.updatedAt":
+		if e.complexity.Edge.UpdatedAt == nil {
+			break
+		}
+
+		return e.complexity.Edge.UpdatedAt(childComplexity), true
+
+	case "GraphNode.children":
+		if e.complexity.GraphNode.Children == nil {
+			break
+		}
+
+		args, err := ec.field_GraphNode_children_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.GraphNode.Children(childComplexity, args["first"].(int), args["after"].(*string), args["orderBy"].(*EdgeOrder)), true
+
+	case "GraphNode.createdAt":
+		if e.complexity.GraphNode.CreatedAt == nil {
+			break
+		}
+
+		return e.complexity.GraphNode.CreatedAt(childComplexity), true
+
+	case "GraphNode.data":
+		if e.complexity.GraphNode.Data == nil {
+			break
+		}
+
+		return e.complexity.GraphNode.Data(childComplexity), true
+
+	case "GraphNode.id":
+		if e.complexity.GraphNode.ID == nil {
+			break
+		}
+
+		return e.complexity.GraphNode.ID(childComplexity), true
+
+	case "GraphNode.json":
+		if e.complexity.GraphNode.JSON == nil {
+			break
+		}
+
+		return e.complexity.GraphNode.JSON(childComplexity), true
+
+	case "GraphNode.nodeId":
+		if e.complexity.GraphNode.NodeID == nil {
+			break
+		}
+
+		return e.complexity.GraphNode.NodeID(childComplexity), true
+
+	case "GraphNode.parents":
+		if e.complexity.GraphNode.Parents == nil {
+			break
+		}
+
+		args, err := ec.field_GraphNode_parents_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.GraphNode.Parents(childComplexity, args["first"].(int), args["after"].(*string), args["orderBy"].(*EdgeOrder)), true
+
+	case "GraphNode.typed":
+		if e.complexity.GraphNode.Typed == nil {
+			break
+		}
+
+		return e.complexity.GraphNode.Typed(childComplexity), true
+
+	case "GraphNode.updatedAt":
+		if e.complexity.GraphNode.UpdatedAt == nil {
+			break
+		}
+
+		return e.complexity.GraphNode.UpdatedAt(childComplexity), true
+
+	case "ImportGraphOutput.nodesRead":
+		if e.complexity.ImportGraphOutput.NodesRead == nil {
+			break
+		}
+
+		return e.complexity.ImportGraphOutput.NodesRead(childComplexity), true
+
+	case "ImportGraphOutput.recordsWritten":
+		if e.complexity.ImportGraphOutput.RecordsWritten == nil {
+			break
+		}
+
+		return e.complexity.ImportGraphOutput.RecordsWritten(childComplexity), true
+
 	case "Location.lat":
 		if e.complexity.Location.Lat == nil {
 			break
@@ -221,6 +494,46 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 		return e.complexity.Location.Lng(childComplexity), true
 
+	case "LocationNode.id":
+		if e.complexity.LocationNode.ID == nil {
+			break
+		}
+
+		return e.complexity.LocationNode.ID(childComplexity), true
+
+	case "LocationNode.location":
+		if e.complexity.LocationNode.Location == nil {
+			break
+		}
+
+		return e.complexity.LocationNode.Location(childComplexity), true
+
+	case "LocationNode.node":
+		if e.complexity.LocationNode.Node == nil {
+			break
+		}
+
+		return e.complexity.LocationNode.Node(childComplexity), true
+
+	case "LocationNode.nodeId":
+		if e.complexity.LocationNode.NodeID == nil {
+			break
+		}
+
+		return e.complexity.LocationNode.NodeID(childComplexity), true
+
+	case "Mutation.importGraph":
+		if e.complexity.Mutation.ImportGraph == nil {
+			break
+		}
+
+		args, err := ec.field_Mutation_importGraph_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Mutation.ImportGraph(childComplexity, args["file"].(graphql.Upload)), true
+
 	case "Mutation.removeEdge":
 		if e.complexity.Mutation.RemoveEdge == nil {
 			break
@@ -233,6 +546,18 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 		return e.complexity.Mutation.RemoveEdge(childComplexity, args["input"].(RemoveEdgeInput)), true
 
+	case "Mutation.removeEdgeData":
+		if e.complexity.Mutation.RemoveEdgeData == nil {
+			break
+		}
+
+		args, err := ec.field_Mutation_removeEdgeData_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Mutation.RemoveEdgeData(childComplexity, args["input"].(RemoveEdgeDataInput)), true
+
 	case "Mutation.removeNode":
 		if e.complexity.Mutation.RemoveNode == nil {
 			break
@@ -245,6 +570,18 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 		return e.complexity.Mutation.RemoveNode(childComplexity, args["input"].(RemoveNodeInput)), true
 
+	case "Mutation.removeNodeData":
+		if e.complexity.Mutation.RemoveNodeData == nil {
+			break
+		}
+
+		args, err := ec.field_Mutation_removeNodeData_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Mutation.RemoveNodeData(childComplexity, args["input"].(RemoveNodeDataInput)), true
+
 	case "Mutation.saveEdge":
 		if e.complexity.Mutation.SaveEdge == nil {
 			break
@@ -269,67 +606,53 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 		return e.complexity.Mutation.SaveNode(childComplexity, args["node"].(SaveNodeInput)), true
 
-	case "Mutation.setEdgeFields":
-		if e.complexity.Mutation.SetEdgeFields == nil {
+	case "Mutation.setEdgeData":
+		if e.complexity.Mutation.SetEdgeData == nil {
 			break
 		}
 
-		args, err := ec.field_Mutation_setEdgeFields_args(context.TODO(), rawArgs)
+		args, err := ec.field_Mutation_setEdgeData_args(context.TODO(), rawArgs)
 		if err != nil {
 			return 0, false
 		}
 
-		return e.complexity.Mutation.SetEdgeFields(childComplexity, args["input"].(SetEdgeFieldsInput)), true
+		return e.complexity.Mutation.SetEdgeData(childComplexity, args["input"].(SetEdgeDataInput)), true
 
-	case "Mutation.setNodeFields":
-		if e.complexity.Mutation.SetNodeFields == nil {
+	case "Mutation.setEdgeFields":
+		if e.complexity.Mutation.SetEdgeFields == nil {
 			break
 		}
 
-		args, err := ec.field_Mutation_setNodeFields_args(context.TODO(), rawArgs)
+		args, err := ec.field_Mutation_setEdgeFields_args(context.TODO(), rawArgs)
 		if err != nil {
 			return 0, false
 		}
 
-		return e.complexity.Mutation.SetNodeFields(childComplexity, args["input"].(SetNodeFieldsInput)), true
+		return e.complexity.Mutation.SetEdgeFields(childComplexity, args["input"].(SetEdgeFieldsInput)), true
 
-	case "Node.children":
-		if e.complexity.Node.Children == nil {
+	case "Mutation.setNodeData":
+		if e.complexity.Mutation.SetNodeData == nil {
 			break
 		}
 
-		args, err := ec.field_Node_children_args(context.TODO(), rawArgs)
+		args, err := ec.field_Mutation_setNodeData_args(context.TODO(), rawArgs)
 		if err != nil {
 			return 0, false
 		}
 
-		return e.complexity.Node.Children(childComplexity, args["first"].(int), args["after"].(*string)), true
-
-	case "Node.data":
-		if e.complexity.Node.Data == nil {
-			break
-		}
-
-		return e.complexity.Node.Data(childComplexity), true
-
-	case "Node.id":
-		if e.complexity.Node.ID == nil {
-			break
-		}
-
-		return e.complexity.Node.ID(childComplexity), true
+		return e.complexity.Mutation.SetNodeData(childComplexity, args["input"].(SetNodeDataInput)), true
 
-	case "Node.parents":
-		if e.complexity.Node.Parents == nil {
+	case "Mutation.setNodeFields":
+		if e.complexity.Mutation.SetNodeFields == nil {
 			break
 		}
 
-		args, err := ec.field_Node_parents_args(context.TODO(), rawArgs)
+		args, err := ec.field_Mutation_setNodeFields_args(context.TODO(), rawArgs)
 		if err != nil {
 			return 0, false
 		}
 
-		return e.complexity.Node.Parents(childComplexity, args["first"].(int), args["after"].(*string)), true
+		return e.complexity.Mutation.SetNodeFields(childComplexity, args["input"].(SetNodeFieldsInput)), true
 
 	case "PageInfo.endCursor":
 		if e.complexity.PageInfo.EndCursor == nil {
@@ -359,6 +682,20 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 		return e.complexity.PageInfo.StartCursor(childComplexity), true
 
+	case "Path.edges":
+		if e.complexity.Path.Edges == nil {
+			break
+		}
+
+		return e.complexity.Path.Edges(childComplexity), true
+
+	case "Path.nodes":
+		if e.complexity.Path.Nodes == nil {
+			break
+		}
+
+		return e.complexity.Path.Nodes(childComplexity), true
+
 	case "Query.get":
 		if e.complexity.Query.Get == nil {
 			break
@@ -371,6 +708,61 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 		return e.complexity.Query.Get(childComplexity, args["id"].(string)), true
 
+	case "Query.neighborhood":
+		if e.complexity.Query.Neighborhood == nil {
+			break
+		}
+
+		args, err := ec.field_Query_neighborhood_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Query.Neighborhood(childComplexity, args["id"].(string), args["depth"].(int), args["direction"].(*Direction)), true
+
+	case "Query.node":
+		if e.complexity.Query.Node == nil {
+			break
+		}
+
+		args, err := ec.field_Query_node_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Query.Node(childComplexity, args["id"].(string)), true
+
+	case "Query.nodes":
+		if e.complexity.Query.Nodes == nil {
+			break
+		}
+
+		args, err := ec.field_Query_nodes_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Query.Nodes(childComplexity, args["filter"].(*NodeFilter), args["first"].(int), args["after"].(*string)), true
+
+	case "Query.path":
+		if e.complexity.Query.Path == nil {
+			break
+		}
+
+		args, err := ec.field_Query_path_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Query.Path(childComplexity, args["from"].(string), args["to"].(string), args["maxDepth"].(*int)), true
+
+	case "RemoveEdgeDataOutput.removed":
+		if e.complexity.RemoveEdgeDataOutput.Removed == nil {
+			break
+		}
+
+		return e.complexity.RemoveEdgeDataOutput.Removed(childComplexity), true
+
 	case "RemoveEdgeOutput.removed":
 		if e.complexity.RemoveEdgeOutput.Removed == nil {
 			break
@@ -378,6 +770,13 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 		return e.complexity.RemoveEdgeOutput.Removed(childComplexity), true
 
+	case "RemoveNodeDataOutput.removed":
+		if e.complexity.RemoveNodeDataOutput.Removed == nil {
+			break
+		}
+
+		return e.complexity.RemoveNodeDataOutput.Removed(childComplexity), true
+
 	case "RemoveNodeOutput.removed":
 		if e.complexity.RemoveNodeOutput.Removed == nil {
 			break
@@ -392,6 +791,13 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 		return e.complexity.SaveEdgeOutput.Child(childComplexity), true
 
+	case "SaveEdgeOutput.childNode":
+		if e.complexity.SaveEdgeOutput.ChildNode == nil {
+			break
+		}
+
+		return e.complexity.SaveEdgeOutput.ChildNode(childComplexity), true
+
 	case "SaveEdgeOutput.parent":
 		if e.complexity.SaveEdgeOutput.Parent == nil {
 			break
@@ -399,6 +805,13 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 		return e.complexity.SaveEdgeOutput.Parent(childComplexity), true
 
+	case "SaveEdgeOutput.parentNode":
+		if e.complexity.SaveEdgeOutput.ParentNode == nil {
+			break
+		}
+
+		return e.complexity.SaveEdgeOutput.ParentNode(childComplexity), true
+
 	case "SaveNodeOutput.id":
 		if e.complexity.SaveNodeOutput.ID == nil {
 			break
@@ -406,13 +819,48 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 		return e.complexity.SaveNodeOutput.ID(childComplexity), true
 
-	case "SetEdgeFieldsOutput.set":
+	case "SaveNodeOutput.node":
+		if e.complexity.SaveNodeOutput.Node == nil {
+			break
+		}
+
+		return e.complexity.SaveNodeOutput.Node(childComplexity), true
+
+	case "SetEdgeDataOutput.set":
+		if e.complexity.SetEdgeDataOutput.Set == nil {
+			break
+		}
+
+		return e.complexity.SetEdgeDataOutput.Set(childComplexity), true
+
+	case "SetEdgeFieldsOutput.set":
 		if e.complexity.SetEdgeFieldsOutput.Set == nil {
 			break
 		}
 
 		return e.complexity.SetEdgeFieldsOutput.Set(childComplexity), true
 
+	case "SetNodeDataOutput.set":
+		if e.complexity.SetNodeDataOutput.Set == nil {
+			break
+		}
+
+		return e.complexity.SetNodeDataOutput.Set(childComplexity), true
+
+	case "SetNodeFieldsOutput.id":
+		if e.complexity.SetNodeFieldsOutput.ID == nil {
+			break
+		}
+
+		return e.complexity.SetNodeFieldsOutput.ID(childComplexity), true
+
+	case "SetNodeFieldsOutput.node":
+		if e.complexity.SetNodeFieldsOutput.Node == nil {
+			break
+		}
+
+		return e.complexity.SetNodeFieldsOutput.Node(childComplexity), true
+
 	case "SetNodeFieldsOutput.set":
 		if e.complexity.SetNodeFieldsOutput.Set == nil {
 			break
@@ -420,6 +868,67 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 		return e.complexity.SetNodeFieldsOutput.Set(childComplexity), true
 
+	case "Subgraph.edges":
+		if e.complexity.Subgraph.Edges == nil {
+			break
+		}
+
+		return e.complexity.Subgraph.Edges(childComplexity), true
+
+	case "Subgraph.nodes":
+		if e.complexity.Subgraph.Nodes == nil {
+			break
+		}
+
+		return e.complexity.Subgraph.Nodes(childComplexity), true
+
+	case "Subgraph.root":
+		if e.complexity.Subgraph.Root == nil {
+			break
+		}
+
+		return e.complexity.Subgraph.Root(childComplexity), true
+
+	case "SubgraphEdge.child":
+		if e.complexity.SubgraphEdge.Child == nil {
+			break
+		}
+
+		return e.complexity.SubgraphEdge.Child(childComplexity), true
+
+	case "SubgraphEdge.json":
+		if e.complexity.SubgraphEdge.JSON == nil {
+			break
+		}
+
+		return e.complexity.SubgraphEdge.JSON(childComplexity), true
+
+	case "SubgraphEdge.label":
+		if e.complexity.SubgraphEdge.Label == nil {
+			break
+		}
+
+		return e.complexity.SubgraphEdge.Label(childComplexity), true
+
+	case "SubgraphEdge.parent":
+		if e.complexity.SubgraphEdge.Parent == nil {
+			break
+		}
+
+		return e.complexity.SubgraphEdge.Parent(childComplexity), true
+
+	case "Subscription.nodeChanged":
+		if e.complexity.Subscription.NodeChanged == nil {
+			break
+		}
+
+		args, err := ec.field_Subscription_nodeChanged_args(context.TODO(), rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Subscription.NodeChanged(childComplexity, args["id"].(*string)), true
+
 	}
 	return 0, false
 }
@@ -459,7 +968,36 @@ func (e *executableSchema) Mutation(ctx context.Context, op *ast.OperationDefini
 }
 
 func (e *executableSchema) Subscription(ctx context.Context, op *ast.OperationDefinition) func() *graphql.Response {
-	return graphql.OneShot(graphql.ErrorResponse(ctx, "subscriptions are not supported"))
+	ec := executionContext{graphql.GetRequestContext(ctx), e}
+
+	next := ec._Subscription(ctx, op.SelectionSet)
+	if ec.Errors != nil {
+		return graphql.OneShot(&graphql.Response{Data: []byte("null"), Errors: ec.Errors})
+	}
+
+	var buf bytes.Buffer
+	return func() *graphql.Response {
+		buf := ec.RequestMiddleware(ctx, func(ctx context.Context) []byte {
+			buf.Reset()
+			data := next()
+
+			if data == nil {
+				return nil
+			}
+			data.MarshalGQL(&buf)
+			return buf.Bytes()
+		})
+
+		if buf == nil {
+			return nil
+		}
+
+		return &graphql.Response{
+			Data:       buf,
+			Errors:     ec.Errors,
+			Extensions: ec.Extensions,
+		}
+	}
 }
 
 type executionContext struct {
@@ -517,11 +1055,74 @@ type Computer {
 
 union NodeDataItem = Location | Computer
 
-type Node {
+# JSON is a JSON object, used for data types which aren't part of the schema.
+scalar JSON
+
+# DateTime is a time in RFC 3339 format, e.g. 2019-05-01T12:00:00Z.
+scalar DateTime
+
+# Upload is a file sent in a multipart request, as described by
+# https://github.com/jaydenseric/graphql-multipart-request-spec.
+scalar Upload
+
+# EdgeOrderField is the field used to sort the edges of a connection.
+enum EdgeOrderField {
+  ID
+  CREATED_AT
+  UPDATED_AT
+}
+
+enum OrderDirection {
+  ASC
+  DESC
+}
+
+# Direction is the direction of the edges followed when traversing the graph.
+enum Direction {
+  CHILDREN
+  PARENTS
+  BOTH
+}
+
+# EdgeOrder sorts the edges of a connection. Edges are sorted by ID if it's not set. Edges without
+# timestamps, e.g. if the Store's Timestamps aren't enabled, are sorted as the earliest.
+input EdgeOrder {
+  field: EdgeOrderField!
+  # direction is ASC if it's not set.
+  direction: OrderDirection
+}
+
+# Node is an object with a global ID, which can be fetched using the node query.
+interface Node {
+  id: ID!
+}
+
+type GraphNode implements Node {
+  # id is the global ID of the node.
   id: ID!
-  parents(first: Int!, after: String): Connection
-  children(first: Int!, after: String): Connection
+  # nodeId is the ID of the node in pregel, which is used by get and the mutations.
+  nodeId: ID!
+  parents(first: Int!, after: String, orderBy: EdgeOrder): Connection
+  children(first: Int!, after: String, orderBy: EdgeOrder): Connection
   data: [NodeDataItem]!
+  # json is all of the node's data, keyed by the name of each data type.
+  json: JSON!
+  # createdAt and updatedAt are only set if the Store's Timestamps are enabled.
+  createdAt: DateTime
+  updatedAt: DateTime
+  # typed is the node as its most specific type, e.g. LocationNode if it has location data, so that
+  # clients can use a fragment for each kind of node. Nodes without a more specific type are
+  # GraphNodes.
+  typed: Node!
+}
+
+# LocationNode is a node which has location data.
+type LocationNode implements Node {
+  id: ID!
+  nodeId: ID!
+  location: Location!
+  # node has the fields of every node, e.g. its children.
+  node: GraphNode!
 }
 
 type Connection {
@@ -534,13 +1135,65 @@ union EdgeDataItem = Location
 
 type Edge {
   cursor: String!
-  node: Node
+  node: GraphNode
   data: [EdgeDataItem]!
+  # json is all of the edge's data, keyed by the name of each data type.
+  json: JSON!
+  # createdAt and updatedAt are the times of the edge, rather than its node.
+  createdAt: DateTime
+  updatedAt: DateTime
+}
+
+# Path is a route through the graph. Each edge leads from the node at the same position in nodes to
+# the next node, so there's one less edge than there are nodes.
+type Path {
+  nodes: [GraphNode!]!
+  edges: [Edge!]!
+}
+
+# Subgraph is a set of nodes, and the edges between them.
+type Subgraph {
+  # root is the ID of the node that the subgraph was expanded from.
+  root: ID!
+  nodes: [GraphNode!]!
+  # edges are the child edges between the nodes of the subgraph. Edges to nodes outside the subgraph
+  # aren't included.
+  edges: [SubgraphEdge!]!
+}
+
+type SubgraphEdge {
+  parent: ID!
+  child: ID!
+  label: String
+  # json is all of the edge's data, keyed by the name of each data type.
+  json: JSON!
+}
+
+# NodeFilter filters the nodes returned by the nodes query. The label or dataType is used to query
+# an index if it's set, otherwise every node is listed.
+input NodeFilter {
+  # idPrefix returns nodes with IDs which start with the prefix.
+  idPrefix: String
+  # label returns nodes which have the label.
+  label: String
+  # dataType returns nodes which have data of the type.
+  dataType: String
 }
 
 # Define queries and mutations.
 type Query {
-  get(id: ID!): Node
+  get(id: ID!): GraphNode
+  # node returns the object with the global ID, which may be a GraphNode, or a more specific type,
+  # e.g. LocationNode.
+  node(id: ID!): Node
+  nodes(filter: NodeFilter, first: Int!, after: String): Connection
+  # path returns the path with the fewest child edges from one node to another, or null if there
+  # isn't a path within maxDepth edges, which defaults to 6.
+  path(from: ID!, to: ID!, maxDepth: Int): Path
+  # neighborhood returns the nodes up to depth edges away from the node, following child edges
+  # unless the direction is set, or null if the node doesn't exist. The depth can't be more than 3,
+  # and at most 500 nodes are returned.
+  neighborhood(id: ID!, depth: Int!, direction: Direction): Subgraph
 }
 
 input SaveNodeInput {
@@ -557,6 +1210,8 @@ input LocationInput {
 
 type SaveNodeOutput {
   id: ID!
+  # node is the saved node, read after it was saved, so that clients can update their caches.
+  node: GraphNode
 }
 
 input SaveEdgeInput {
@@ -569,6 +1224,9 @@ input SaveEdgeInput {
 type SaveEdgeOutput {
   parent: ID! 
   child: ID!
+  # parentNode and childNode are the nodes of the saved edge, read after it was saved.
+  parentNode: GraphNode
+  childNode: GraphNode
 }
 
 input RemoveNodeInput {
@@ -595,6 +1253,9 @@ input SetNodeFieldsInput {
 
 type SetNodeFieldsOutput {
   set: Boolean!
+  id: ID!
+  # node is the node, read after its fields were set.
+  node: GraphNode
 }
 
 input SetEdgeFieldsInput {
@@ -607,6 +1268,55 @@ type SetEdgeFieldsOutput {
   set: Boolean!
 }
 
+input SetNodeDataInput {
+  id: ID!
+  type: String!
+  data: JSON!
+}
+
+type SetNodeDataOutput {
+  set: Boolean!
+}
+
+input SetEdgeDataInput {
+  parent: ID!
+  child: ID!
+  type: String!
+  data: JSON!
+}
+
+type SetEdgeDataOutput {
+  set: Boolean!
+}
+
+input RemoveNodeDataInput {
+  id: ID!
+  type: String!
+}
+
+type RemoveNodeDataOutput {
+  removed: Boolean!
+}
+
+input RemoveEdgeDataInput {
+  parent: ID!
+  child: ID!
+  # label is the label of the edge. If it isn't set, the data is removed from the unlabelled edge.
+  label: String
+  type: String!
+}
+
+type RemoveEdgeDataOutput {
+  removed: Boolean!
+}
+
+type ImportGraphOutput {
+  # nodesRead is the number of nodes read from the file.
+  nodesRead: Int!
+  # recordsWritten is the number of records written to the table.
+  recordsWritten: Int!
+}
+
 type Mutation {
   saveNode(node: SaveNodeInput!): SaveNodeOutput!
   saveEdge(edge: SaveEdgeInput!): SaveEdgeOutput!
@@ -614,6 +1324,34 @@ type Mutation {
   removeEdge(input: RemoveEdgeInput!): RemoveEdgeOutput!
   setNodeFields(input: SetNodeFieldsInput!): SetNodeFieldsOutput!
   setEdgeFields(input: SetEdgeFieldsInput!): SetEdgeFieldsOutput!
+  setNodeData(input: SetNodeDataInput!): SetNodeDataOutput!
+  setEdgeData(input: SetEdgeDataInput!): SetEdgeDataOutput!
+  removeNodeData(input: RemoveNodeDataInput!): RemoveNodeDataOutput!
+  removeEdgeData(input: RemoveEdgeDataInput!): RemoveEdgeDataOutput!
+  # importGraph loads a file of nodes written by Export, with a JSON object on each line.
+  importGraph(file: Upload!): ImportGraphOutput!
+}
+
+# ChangeEvent describes a change to a node, one of its labels, an edge, or data.
+type ChangeEvent {
+  # type is the kind of change, e.g. NodeCreated or EdgeDeleted.
+  type: String!
+  # nodeId is the node which changed, or the parent node of a changed edge.
+  nodeId: ID!
+  # child is the child node of a changed edge, or of an edge whose data changed.
+  child: ID
+  # label is the label of the edge, or the node label which was added or removed.
+  label: String
+  # dataType is the name of the data type of changed data.
+  dataType: String
+  # new is the data after the change.
+  new: JSON
+  time: String!
+}
+
+type Subscription {
+  # nodeChanged returns the changes to the node and its edges, or every change if the id isn't set.
+  nodeChanged(id: ID): ChangeEvent!
 }
 `},
 )
@@ -622,6 +1360,80 @@ type Mutation {
 
 // region    ***************************** args.gotpl *****************************
 
+func (ec *executionContext) field_GraphNode_children_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
+	var err error
+	args := map[string]interface{}{}
+	var arg0 int
+	if tmp, ok := rawArgs["first"]; ok {
+		arg0, err = ec.unmarshalNInt2int(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["first"] = arg0
+	var arg1 *string
+	if tmp, ok := rawArgs["after"]; ok {
+		arg1, err = ec.unmarshalOString2ᚖstring(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["after"] = arg1
+	var arg2 *EdgeOrder
+	if tmp, ok := rawArgs["orderBy"]; ok {
+		arg2, err = ec.unmarshalOEdgeOrder2ᚖgithubᚗcomᚋaᚑhᚋpregelᚋgraphᚐEdgeOrder(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["orderBy"] = arg2
+	return args, nil
+}
+
+func (ec *executionContext) field_GraphNode_parents_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
+	var err error
+	args := map[string]interface{}{}
+	var arg0 int
+	if tmp, ok := rawArgs["first"]; ok {
+		arg0, err = ec.unmarshalNInt2int(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["first"] = arg0
+	var arg1 *string
+	if tmp, ok := rawArgs["after"]; ok {
+		arg1, err = ec.unmarshalOString2ᚖstring(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["after"] = arg1
+	var arg2 *EdgeOrder
+	if tmp, ok := rawArgs["orderBy"]; ok {
+		arg2, err = ec.unmarshalOEdgeOrder2ᚖgithubᚗcomᚋaᚑhᚋpregelᚋgraphᚐEdgeOrder(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["orderBy"] = arg2
+	return args, nil
+}
+
+func (ec *executionContext) field_Mutation_importGraph_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
+	var err error
+	args := map[string]interface{}{}
+	var arg0 graphql.Upload
+	if tmp, ok := rawArgs["file"]; ok {
+		arg0, err = ec.unmarshalNUpload2githubᚗcomᚋ99designsᚋgqlgenᚋgraphqlᚐUpload(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["file"] = arg0
+	return args, nil
+}
+
 func (ec *executionContext) field_Mutation_removeEdge_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
 	var err error
 	args := map[string]interface{}{}
@@ -636,6 +1448,20 @@ func (ec *executionContext) field_Mutation_removeEdge_args(ctx context.Context,
 	return args, nil
 }
 
+func (ec *executionContext) field_Mutation_removeEdgeData_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
+	var err error
+	args := map[string]interface{}{}
+	var arg0 RemoveEdgeDataInput
+	if tmp, ok := rawArgs["input"]; ok {
+		arg0, err = ec.unmarshalNRemoveEdgeDataInput2githubᚗcomᚋaᚑhᚋpregelᚋgraphᚐRemoveEdgeDataInput(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["input"] = arg0
+	return args, nil
+}
+
 func (ec *executionContext) field_Mutation_removeNode_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
 	var err error
 	args := map[string]interface{}{}
@@ -650,6 +1476,20 @@ func (ec *executionContext) field_Mutation_removeNode_args(ctx context.Context,
 	return args, nil
 }
 
+func (ec *executionContext) field_Mutation_removeNodeData_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
+	var err error
+	args := map[string]interface{}{}
+	var arg0 RemoveNodeDataInput
+	if tmp, ok := rawArgs["input"]; ok {
+		arg0, err = ec.unmarshalNRemoveNodeDataInput2githubᚗcomᚋaᚑhᚋpregelᚋgraphᚐRemoveNodeDataInput(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["input"] = arg0
+	return args, nil
+}
+
 func (ec *executionContext) field_Mutation_saveEdge_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
 	var err error
 	args := map[string]interface{}{}
@@ -678,12 +1518,12 @@ func (ec *executionContext) field_Mutation_saveNode_args(ctx context.Context, ra
 	return args, nil
 }
 
-func (ec *executionContext) field_Mutation_setEdgeFields_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
+func (ec *executionContext) field_Mutation_setEdgeData_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
 	var err error
 	args := map[string]interface{}{}
-	var arg0 SetEdgeFieldsInput
+	var arg0 SetEdgeDataInput
 	if tmp, ok := rawArgs["input"]; ok {
-		arg0, err = ec.unmarshalNSetEdgeFieldsInput2githubᚗcomᚋaᚑhᚋpregelᚋgraphᚐSetEdgeFieldsInput(ctx, tmp)
+		arg0, err = ec.unmarshalNSetEdgeDataInput2githubᚗcomᚋaᚑhᚋpregelᚋgraphᚐSetEdgeDataInput(ctx, tmp)
 		if err != nil {
 			return nil, err
 		}
@@ -692,12 +1532,12 @@ func (ec *executionContext) field_Mutation_setEdgeFields_args(ctx context.Contex
 	return args, nil
 }
 
-func (ec *executionContext) field_Mutation_setNodeFields_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
+func (ec *executionContext) field_Mutation_setEdgeFields_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
 	var err error
 	args := map[string]interface{}{}
-	var arg0 SetNodeFieldsInput
+	var arg0 SetEdgeFieldsInput
 	if tmp, ok := rawArgs["input"]; ok {
-		arg0, err = ec.unmarshalNSetNodeFieldsInput2githubᚗcomᚋaᚑhᚋpregelᚋgraphᚐSetNodeFieldsInput(ctx, tmp)
+		arg0, err = ec.unmarshalNSetEdgeFieldsInput2githubᚗcomᚋaᚑhᚋpregelᚋgraphᚐSetEdgeFieldsInput(ctx, tmp)
 		if err != nil {
 			return nil, err
 		}
@@ -706,47 +1546,31 @@ func (ec *executionContext) field_Mutation_setNodeFields_args(ctx context.Contex
 	return args, nil
 }
 
-func (ec *executionContext) field_Node_children_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
+func (ec *executionContext) field_Mutation_setNodeData_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
 	var err error
 	args := map[string]interface{}{}
-	var arg0 int
-	if tmp, ok := rawArgs["first"]; ok {
-		arg0, err = ec.unmarshalNInt2int(ctx, tmp)
-		if err != nil {
-			return nil, err
-		}
-	}
-	args["first"] = arg0
-	var arg1 *string
-	if tmp, ok := rawArgs["after"]; ok {
-		arg1, err = ec.unmarshalOString2ᚖstring(ctx, tmp)
+	var arg0 SetNodeDataInput
+	if tmp, ok := rawArgs["input"]; ok {
+		arg0, err = ec.unmarshalNSetNodeDataInput2githubᚗcomᚋaᚑhᚋpregelᚋgraphᚐSetNodeDataInput(ctx, tmp)
 		if err != nil {
 			return nil, err
 		}
 	}
-	args["after"] = arg1
+	args["input"] = arg0
 	return args, nil
 }
 
-func (ec *executionContext) field_Node_parents_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
+func (ec *executionContext) field_Mutation_setNodeFields_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
 	var err error
 	args := map[string]interface{}{}
-	var arg0 int
-	if tmp, ok := rawArgs["first"]; ok {
-		arg0, err = ec.unmarshalNInt2int(ctx, tmp)
-		if err != nil {
-			return nil, err
-		}
-	}
-	args["first"] = arg0
-	var arg1 *string
-	if tmp, ok := rawArgs["after"]; ok {
-		arg1, err = ec.unmarshalOString2ᚖstring(ctx, tmp)
+	var arg0 SetNodeFieldsInput
+	if tmp, ok := rawArgs["input"]; ok {
+		arg0, err = ec.unmarshalNSetNodeFieldsInput2githubᚗcomᚋaᚑhᚋpregelᚋgraphᚐSetNodeFieldsInput(ctx, tmp)
 		if err != nil {
 			return nil, err
 		}
 	}
-	args["after"] = arg1
+	args["input"] = arg0
 	return args, nil
 }
 
@@ -778,22 +1602,140 @@ func (ec *executionContext) field_Query_get_args(ctx context.Context, rawArgs ma
 	return args, nil
 }
 
-func (ec *executionContext) field___Type_enumValues_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
+func (ec *executionContext) field_Query_neighborhood_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
 	var err error
 	args := map[string]interface{}{}
-	var arg0 bool
-	if tmp, ok := rawArgs["includeDeprecated"]; ok {
-		arg0, err = ec.unmarshalOBoolean2bool(ctx, tmp)
+	var arg0 string
+	if tmp, ok := rawArgs["id"]; ok {
+		arg0, err = ec.unmarshalNID2string(ctx, tmp)
 		if err != nil {
 			return nil, err
 		}
 	}
-	args["includeDeprecated"] = arg0
-	return args, nil
-}
-
-func (ec *executionContext) field___Type_fields_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
-	var err error
+	args["id"] = arg0
+	var arg1 int
+	if tmp, ok := rawArgs["depth"]; ok {
+		arg1, err = ec.unmarshalNInt2int(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["depth"] = arg1
+	var arg2 *Direction
+	if tmp, ok := rawArgs["direction"]; ok {
+		arg2, err = ec.unmarshalODirection2ᚖgithubᚗcomᚋaᚑhᚋpregelᚋgraphᚐDirection(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["direction"] = arg2
+	return args, nil
+}
+
+func (ec *executionContext) field_Query_node_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
+	var err error
+	args := map[string]interface{}{}
+	var arg0 string
+	if tmp, ok := rawArgs["id"]; ok {
+		arg0, err = ec.unmarshalNID2string(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["id"] = arg0
+	return args, nil
+}
+
+func (ec *executionContext) field_Query_nodes_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
+	var err error
+	args := map[string]interface{}{}
+	var arg0 *NodeFilter
+	if tmp, ok := rawArgs["filter"]; ok {
+		arg0, err = ec.unmarshalONodeFilter2ᚖgithubᚗcomᚋaᚑhᚋpregelᚋgraphᚐNodeFilter(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["filter"] = arg0
+	var arg1 int
+	if tmp, ok := rawArgs["first"]; ok {
+		arg1, err = ec.unmarshalNInt2int(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["first"] = arg1
+	var arg2 *string
+	if tmp, ok := rawArgs["after"]; ok {
+		arg2, err = ec.unmarshalOString2ᚖstring(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["after"] = arg2
+	return args, nil
+}
+
+func (ec *executionContext) field_Query_path_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
+	var err error
+	args := map[string]interface{}{}
+	var arg0 string
+	if tmp, ok := rawArgs["from"]; ok {
+		arg0, err = ec.unmarshalNID2string(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["from"] = arg0
+	var arg1 string
+	if tmp, ok := rawArgs["to"]; ok {
+		arg1, err = ec.unmarshalNID2string(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["to"] = arg1
+	var arg2 *int
+	if tmp, ok := rawArgs["maxDepth"]; ok {
+		arg2, err = ec.unmarshalOInt2ᚖint(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["maxDepth"] = arg2
+	return args, nil
+}
+
+func (ec *executionContext) field_Subscription_nodeChanged_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
+	var err error
+	args := map[string]interface{}{}
+	var arg0 *string
+	if tmp, ok := rawArgs["id"]; ok {
+		arg0, err = ec.unmarshalOID2ᚖstring(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["id"] = arg0
+	return args, nil
+}
+
+func (ec *executionContext) field___Type_enumValues_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
+	var err error
+	args := map[string]interface{}{}
+	var arg0 bool
+	if tmp, ok := rawArgs["includeDeprecated"]; ok {
+		arg0, err = ec.unmarshalOBoolean2bool(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["includeDeprecated"] = arg0
+	return args, nil
+}
+
+func (ec *executionContext) field___Type_fields_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
+	var err error
 	args := map[string]interface{}{}
 	var arg0 bool
 	if tmp, ok := rawArgs["includeDeprecated"]; ok {
@@ -810,11 +1752,11 @@ func (ec *executionContext) field___Type_fields_args(ctx context.Context, rawArg
 
 // region    **************************** field.gotpl *****************************
 
-func (ec *executionContext) _Computer_brand(ctx context.Context, field graphql.CollectedField, obj *Computer) graphql.Marshaler {
+func (ec *executionContext) _ChangeEvent_type(ctx context.Context, field graphql.CollectedField, obj *ChangeEvent) graphql.Marshaler {
 	ctx = ec.Tracer.StartFieldExecution(ctx, field)
 	defer func() { ec.Tracer.EndFieldExecution(ctx) }()
 	rctx := &graphql.ResolverContext{
-		Object:   "Computer",
+		Object:   "ChangeEvent",
 		Field:    field,
 		Args:     nil,
 		IsMethod: false,
@@ -823,7 +1765,7 @@ func (ec *executionContext) _Computer_brand(ctx context.Context, field graphql.C
 	ctx = ec.Tracer.StartFieldResolverExecution(ctx, rctx)
 	resTmp := ec.FieldMiddleware(ctx, obj, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Brand, nil
+		return obj.Type, nil
 	})
 	if resTmp == nil {
 		if !ec.HasError(rctx) {
@@ -837,11 +1779,11 @@ func (ec *executionContext) _Computer_brand(ctx context.Context, field graphql.C
 	return ec.marshalNString2string(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) _Computer_yearPurchased(ctx context.Context, field graphql.CollectedField, obj *Computer) graphql.Marshaler {
+func (ec *executionContext) _ChangeEvent_nodeId(ctx context.Context, field graphql.CollectedField, obj *ChangeEvent) graphql.Marshaler {
 	ctx = ec.Tracer.StartFieldExecution(ctx, field)
 	defer func() { ec.Tracer.EndFieldExecution(ctx) }()
 	rctx := &graphql.ResolverContext{
-		Object:   "Computer",
+		Object:   "ChangeEvent",
 		Field:    field,
 		Args:     nil,
 		IsMethod: false,
@@ -850,7 +1792,7 @@ func (ec *executionContext) _Computer_yearPurchased(ctx context.Context, field g
 	ctx = ec.Tracer.StartFieldResolverExecution(ctx, rctx)
 	resTmp := ec.FieldMiddleware(ctx, obj, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.YearPurchased, nil
+		return obj.NodeID, nil
 	})
 	if resTmp == nil {
 		if !ec.HasError(rctx) {
@@ -858,17 +1800,17 @@ func (ec *executionContext) _Computer_yearPurchased(ctx context.Context, field g
 		}
 		return graphql.Null
 	}
-	res := resTmp.(int)
+	res := resTmp.(string)
 	rctx.Result = res
 	ctx = ec.Tracer.StartFieldChildExecution(ctx)
-	return ec.marshalNInt2int(ctx, field.Selections, res)
+	return ec.marshalNID2string(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) _Connection_edges(ctx context.Context, field graphql.CollectedField, obj *Connection) graphql.Marshaler {
+func (ec *executionContext) _ChangeEvent_child(ctx context.Context, field graphql.CollectedField, obj *ChangeEvent) graphql.Marshaler {
 	ctx = ec.Tracer.StartFieldExecution(ctx, field)
 	defer func() { ec.Tracer.EndFieldExecution(ctx) }()
 	rctx := &graphql.ResolverContext{
-		Object:   "Connection",
+		Object:   "ChangeEvent",
 		Field:    field,
 		Args:     nil,
 		IsMethod: false,
@@ -877,22 +1819,22 @@ func (ec *executionContext) _Connection_edges(ctx context.Context, field graphql
 	ctx = ec.Tracer.StartFieldResolverExecution(ctx, rctx)
 	resTmp := ec.FieldMiddleware(ctx, obj, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Edges, nil
+		return obj.Child, nil
 	})
 	if resTmp == nil {
 		return graphql.Null
 	}
-	res := resTmp.([]Edge)
+	res := resTmp.(*string)
 	rctx.Result = res
 	ctx = ec.Tracer.StartFieldChildExecution(ctx)
-	return ec.marshalOEdge2ᚕgithubᚗcomᚋaᚑhᚋpregelᚋgraphᚐEdge(ctx, field.Selections, res)
+	return ec.marshalOID2ᚖstring(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) _Connection_pageInfo(ctx context.Context, field graphql.CollectedField, obj *Connection) graphql.Marshaler {
+func (ec *executionContext) _ChangeEvent_label(ctx context.Context, field graphql.CollectedField, obj *ChangeEvent) graphql.Marshaler {
 	ctx = ec.Tracer.StartFieldExecution(ctx, field)
 	defer func() { ec.Tracer.EndFieldExecution(ctx) }()
 	rctx := &graphql.ResolverContext{
-		Object:   "Connection",
+		Object:   "ChangeEvent",
 		Field:    field,
 		Args:     nil,
 		IsMethod: false,
@@ -901,25 +1843,22 @@ func (ec *executionContext) _Connection_pageInfo(ctx context.Context, field grap
 	ctx = ec.Tracer.StartFieldResolverExecution(ctx, rctx)
 	resTmp := ec.FieldMiddleware(ctx, obj, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.PageInfo, nil
+		return obj.Label, nil
 	})
 	if resTmp == nil {
-		if !ec.HasError(rctx) {
-			ec.Errorf(ctx, "must not be null")
-		}
 		return graphql.Null
 	}
-	res := resTmp.(PageInfo)
+	res := resTmp.(*string)
 	rctx.Result = res
 	ctx = ec.Tracer.StartFieldChildExecution(ctx)
-	return ec.marshalNPageInfo2githubᚗcomᚋaᚑhᚋpregelᚋgraphᚐPageInfo(ctx, field.Selections, res)
+	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) _Connection_totalCount(ctx context.Context, field graphql.CollectedField, obj *Connection) graphql.Marshaler {
+func (ec *executionContext) _ChangeEvent_dataType(ctx context.Context, field graphql.CollectedField, obj *ChangeEvent) graphql.Marshaler {
 	ctx = ec.Tracer.StartFieldExecution(ctx, field)
 	defer func() { ec.Tracer.EndFieldExecution(ctx) }()
 	rctx := &graphql.ResolverContext{
-		Object:   "Connection",
+		Object:   "ChangeEvent",
 		Field:    field,
 		Args:     nil,
 		IsMethod: false,
@@ -928,25 +1867,22 @@ func (ec *executionContext) _Connection_totalCount(ctx context.Context, field gr
 	ctx = ec.Tracer.StartFieldResolverExecution(ctx, rctx)
 	resTmp := ec.FieldMiddleware(ctx, obj, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.TotalCount, nil
+		return obj.DataType, nil
 	})
 	if resTmp == nil {
-		if !ec.HasError(rctx) {
-			ec.Errorf(ctx, "must not be null")
-		}
 		return graphql.Null
 	}
-	res := resTmp.(int)
+	res := resTmp.(*string)
 	rctx.Result = res
 	ctx = ec.Tracer.StartFieldChildExecution(ctx)
-	return ec.marshalNInt2int(ctx, field.Selections, res)
+	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) _Edge_cursor(ctx context.Context, field graphql.CollectedField, obj *Edge) graphql.Marshaler {
+func (ec *executionContext) _ChangeEvent_new(ctx context.Context, field graphql.CollectedField, obj *ChangeEvent) graphql.Marshaler {
 	ctx = ec.Tracer.StartFieldExecution(ctx, field)
 	defer func() { ec.Tracer.EndFieldExecution(ctx) }()
 	rctx := &graphql.ResolverContext{
-		Object:   "Edge",
+		Object:   "ChangeEvent",
 		Field:    field,
 		Args:     nil,
 		IsMethod: false,
@@ -955,25 +1891,22 @@ func (ec *executionContext) _Edge_cursor(ctx context.Context, field graphql.Coll
 	ctx = ec.Tracer.StartFieldResolverExecution(ctx, rctx)
 	resTmp := ec.FieldMiddleware(ctx, obj, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Cursor, nil
+		return obj.New, nil
 	})
 	if resTmp == nil {
-		if !ec.HasError(rctx) {
-			ec.Errorf(ctx, "must not be null")
-		}
 		return graphql.Null
 	}
-	res := resTmp.(string)
+	res := resTmp.(map[string]interface{})
 	rctx.Result = res
 	ctx = ec.Tracer.StartFieldChildExecution(ctx)
-	return ec.marshalNString2string(ctx, field.Selections, res)
+	return ec.marshalOJSON2map(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) _Edge_node(ctx context.Context, field graphql.CollectedField, obj *Edge) graphql.Marshaler {
+func (ec *executionContext) _ChangeEvent_time(ctx context.Context, field graphql.CollectedField, obj *ChangeEvent) graphql.Marshaler {
 	ctx = ec.Tracer.StartFieldExecution(ctx, field)
 	defer func() { ec.Tracer.EndFieldExecution(ctx) }()
 	rctx := &graphql.ResolverContext{
-		Object:   "Edge",
+		Object:   "ChangeEvent",
 		Field:    field,
 		Args:     nil,
 		IsMethod: false,
@@ -982,22 +1915,25 @@ func (ec *executionContext) _Edge_node(ctx context.Context, field graphql.Collec
 	ctx = ec.Tracer.StartFieldResolverExecution(ctx, rctx)
 	resTmp := ec.FieldMiddleware(ctx, obj, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Node, nil
+		return obj.Time, nil
 	})
 	if resTmp == nil {
+		if !ec.HasError(rctx) {
+			ec.Errorf(ctx, "must not be null")
+		}
 		return graphql.Null
 	}
-	res := resTmp.(*pregel.Node)
+	res := resTmp.(string)
 	rctx.Result = res
 	ctx = ec.Tracer.StartFieldChildExecution(ctx)
-	return ec.marshalONode2ᚖgithubᚗcomᚋaᚑhᚋpregelᚐNode(ctx, field.Selections, res)
+	return ec.marshalNString2string(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) _Edge_data(ctx context.Context, field graphql.CollectedField, obj *Edge) graphql.Marshaler {
+func (ec *executionContext) _Computer_brand(ctx context.Context, field graphql.CollectedField, obj *Computer) graphql.Marshaler {
 	ctx = ec.Tracer.StartFieldExecution(ctx, field)
 	defer func() { ec.Tracer.EndFieldExecution(ctx) }()
 	rctx := &graphql.ResolverContext{
-		Object:   "Edge",
+		Object:   "Computer",
 		Field:    field,
 		Args:     nil,
 		IsMethod: false,
@@ -1006,7 +1942,7 @@ func (ec *executionContext) _Edge_data(ctx context.Context, field graphql.Collec
 	ctx = ec.Tracer.StartFieldResolverExecution(ctx, rctx)
 	resTmp := ec.FieldMiddleware(ctx, obj, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Data, nil
+		return obj.Brand, nil
 	})
 	if resTmp == nil {
 		if !ec.HasError(rctx) {
@@ -1014,17 +1950,17 @@ func (ec *executionContext) _Edge_data(ctx context.Context, field graphql.Collec
 		}
 		return graphql.Null
 	}
-	res := resTmp.([]EdgeDataItem)
+	res := resTmp.(string)
 	rctx.Result = res
 	ctx = ec.Tracer.StartFieldChildExecution(ctx)
-	return ec.marshalNEdgeDataItem2ᚕgithubᚗcomᚋaᚑhᚋpregelᚋgraphᚐEdgeDataItem(ctx, field.Selections, res)
+	return ec.marshalNString2string(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) _Location_lng(ctx context.Context, field graphql.CollectedField, obj *Location) graphql.Marshaler {
+func (ec *executionContext) _Computer_yearPurchased(ctx context.Context, field graphql.CollectedField, obj *Computer) graphql.Marshaler {
 	ctx = ec.Tracer.StartFieldExecution(ctx, field)
 	defer func() { ec.Tracer.EndFieldExecution(ctx) }()
 	rctx := &graphql.ResolverContext{
-		Object:   "Location",
+		Object:   "Computer",
 		Field:    field,
 		Args:     nil,
 		IsMethod: false,
@@ -1033,7 +1969,7 @@ func (ec *executionContext) _Location_lng(ctx context.Context, field graphql.Col
 	ctx = ec.Tracer.StartFieldResolverExecution(ctx, rctx)
 	resTmp := ec.FieldMiddleware(ctx, obj, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Lng, nil
+		return obj.YearPurchased, nil
 	})
 	if resTmp == nil {
 		if !ec.HasError(rctx) {
@@ -1041,17 +1977,17 @@ func (ec *executionContext) _Location_lng(ctx context.Context, field graphql.Col
 		}
 		return graphql.Null
 	}
-	res := resTmp.(float64)
+	res := resTmp.(int)
 	rctx.Result = res
 	ctx = ec.Tracer.StartFieldChildExecution(ctx)
-	return ec.marshalNFloat2float64(ctx, field.Selections, res)
+	return ec.marshalNInt2int(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) _Location_lat(ctx context.Context, field graphql.CollectedField, obj *Location) graphql.Marshaler {
+func (ec *executionContext) _Connection_edges(ctx context.Context, field graphql.CollectedField, obj *Connection) graphql.Marshaler {
 	ctx = ec.Tracer.StartFieldExecution(ctx, field)
 	defer func() { ec.Tracer.EndFieldExecution(ctx) }()
 	rctx := &graphql.ResolverContext{
-		Object:   "Location",
+		Object:   "Connection",
 		Field:    field,
 		Args:     nil,
 		IsMethod: false,
@@ -1060,41 +1996,31 @@ func (ec *executionContext) _Location_lat(ctx context.Context, field graphql.Col
 	ctx = ec.Tracer.StartFieldResolverExecution(ctx, rctx)
 	resTmp := ec.FieldMiddleware(ctx, obj, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Lat, nil
+		return obj.Edges, nil
 	})
 	if resTmp == nil {
-		if !ec.HasError(rctx) {
-			ec.Errorf(ctx, "must not be null")
-		}
 		return graphql.Null
 	}
-	res := resTmp.(float64)
+	res := resTmp.([]Edge)
 	rctx.Result = res
 	ctx = ec.Tracer.StartFieldChildExecution(ctx)
-	return ec.marshalNFloat2float64(ctx, field.Selections, res)
+	return ec.marshalOEdge2ᚕgithubᚗcomᚋaᚑhᚋpregelᚋgraphᚐEdge(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) _Mutation_saveNode(ctx context.Context, field graphql.CollectedField) graphql.Marshaler {
+func (ec *executionContext) _Connection_pageInfo(ctx context.Context, field graphql.CollectedField, obj *Connection) graphql.Marshaler {
 	ctx = ec.Tracer.StartFieldExecution(ctx, field)
 	defer func() { ec.Tracer.EndFieldExecution(ctx) }()
 	rctx := &graphql.ResolverContext{
-		Object:   "Mutation",
+		Object:   "Connection",
 		Field:    field,
 		Args:     nil,
-		IsMethod: true,
+		IsMethod: false,
 	}
 	ctx = graphql.WithResolverContext(ctx, rctx)
-	rawArgs := field.ArgumentMap(ec.Variables)
-	args, err := ec.field_Mutation_saveNode_args(ctx, rawArgs)
-	if err != nil {
-		ec.Error(ctx, err)
-		return graphql.Null
-	}
-	rctx.Args = args
 	ctx = ec.Tracer.StartFieldResolverExecution(ctx, rctx)
-	resTmp := ec.FieldMiddleware(ctx, nil, func(rctx context.Context) (interface{}, error) {
+	resTmp := ec.FieldMiddleware(ctx, obj, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return ec.resolvers.Mutation().SaveNode(rctx, args["node"].(SaveNodeInput))
+		return obj.PageInfo, nil
 	})
 	if resTmp == nil {
 		if !ec.HasError(rctx) {
@@ -1102,33 +2028,26 @@ func (ec *executionContext) _Mutation_saveNode(ctx context.Context, field graphq
 		}
 		return graphql.Null
 	}
-	res := resTmp.(*SaveNodeOutput)
+	res := resTmp.(PageInfo)
 	rctx.Result = res
 	ctx = ec.Tracer.StartFieldChildExecution(ctx)
-	return ec.marshalNSaveNodeOutput2ᚖgithubᚗcomᚋaᚑhᚋpregelᚋgraphᚐSaveNodeOutput(ctx, field.Selections, res)
+	return ec.marshalNPageInfo2githubᚗcomᚋaᚑhᚋpregelᚋgraphᚐPageInfo(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) _Mutation_saveEdge(ctx context.Context, field graphql.CollectedField) graphql.Marshaler {
+func (ec *executionContext) _Connection_totalCount(ctx context.Context, field graphql.CollectedField, obj *Connection) graphql.Marshaler {
 	ctx = ec.Tracer.StartFieldExecution(ctx, field)
 	defer func() { ec.Tracer.EndFieldExecution(ctx) }()
 	rctx := &graphql.ResolverContext{
-		Object:   "Mutation",
+		Object:   "Connection",
 		Field:    field,
 		Args:     nil,
-		IsMethod: true,
+		IsMethod: false,
 	}
 	ctx = graphql.WithResolverContext(ctx, rctx)
-	rawArgs := field.ArgumentMap(ec.Variables)
-	args, err := ec.field_Mutation_saveEdge_args(ctx, rawArgs)
-	if err != nil {
-		ec.Error(ctx, err)
-		return graphql.Null
-	}
-	rctx.Args = args
 	ctx = ec.Tracer.StartFieldResolverExecution(ctx, rctx)
-	resTmp := ec.FieldMiddleware(ctx, nil, func(rctx context.Context) (interface{}, error) {
+	resTmp := ec.FieldMiddleware(ctx, obj, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return ec.resolvers.Mutation().SaveEdge(rctx, args["edge"].(SaveEdgeInput))
+		return obj.TotalCount, nil
 	})
 	if resTmp == nil {
 		if !ec.HasError(rctx) {
@@ -1136,33 +2055,26 @@ func (ec *executionContext) _Mutation_saveEdge(ctx context.Context, field graphq
 		}
 		return graphql.Null
 	}
-	res := resTmp.(*SaveEdgeOutput)
+	res := resTmp.(int)
 	rctx.Result = res
 	ctx = ec.Tracer.StartFieldChildExecution(ctx)
-	return ec.marshalNSaveEdgeOutput2ᚖgithubᚗcomᚋaᚑhᚋpregelᚋgraphᚐSaveEdgeOutput(ctx, field.Selections, res)
+	return ec.marshalNInt2int(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) _Mutation_removeNode(ctx context.Context, field graphql.CollectedField) graphql.Marshaler {
+func (ec *executionContext) _Edge_cursor(ctx context.Context, field graphql.CollectedField, obj *Edge) graphql.Marshaler {
 	ctx = ec.Tracer.StartFieldExecution(ctx, field)
 	defer func() { ec.Tracer.EndFieldExecution(ctx) }()
 	rctx := &graphql.ResolverContext{
-		Object:   "Mutation",
+		Object:   "Edge",
 		Field:    field,
 		Args:     nil,
-		IsMethod: true,
+		IsMethod: false,
 	}
 	ctx = graphql.WithResolverContext(ctx, rctx)
-	rawArgs := field.ArgumentMap(ec.Variables)
-	args, err := ec.field_Mutation_removeNode_args(ctx, rawArgs)
-	if err != nil {
-		ec.Error(ctx, err)
-		return graphql.Null
-	}
-	rctx.Args = args
 	ctx = ec.Tracer.StartFieldResolverExecution(ctx, rctx)
-	resTmp := ec.FieldMiddleware(ctx, nil, func(rctx context.Context) (interface{}, error) {
+	resTmp := ec.FieldMiddleware(ctx, obj, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return ec.resolvers.Mutation().RemoveNode(rctx, args["input"].(RemoveNodeInput))
+		return obj.Cursor, nil
 	})
 	if resTmp == nil {
 		if !ec.HasError(rctx) {
@@ -1170,67 +2082,50 @@ func (ec *executionContext) _Mutation_removeNode(ctx context.Context, field grap
 		}
 		return graphql.Null
 	}
-	res := resTmp.(*RemoveNodeOutput)
+	res := resTmp.(string)
 	rctx.Result = res
 	ctx = ec.Tracer.StartFieldChildExecution(ctx)
-	return ec.marshalNRemoveNodeOutput2ᚖgithubᚗcomᚋaᚑhᚋpregelᚋgraphᚐRemoveNodeOutput(ctx, field.Selections, res)
+	return ec.marshalNString2string(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) _Mutation_removeEdge(ctx context.Context, field graphql.CollectedField) graphql.Marshaler {
+func (ec *executionContext) _Edge_node(ctx context.Context, field graphql.CollectedField, obj *Edge) graphql.Marshaler {
 	ctx = ec.Tracer.StartFieldExecution(ctx, field)
 	defer func() { ec.Tracer.EndFieldExecution(ctx) }()
 	rctx := &graphql.ResolverContext{
-		Object:   "Mutation",
+		Object:   "Edge",
 		Field:    field,
 		Args:     nil,
-		IsMethod: true,
+		IsMethod: false,
 	}
 	ctx = graphql.WithResolverContext(ctx, rctx)
-	rawArgs := field.ArgumentMap(ec.Variables)
-	args, err := ec.field_Mutation_removeEdge_args(ctx, rawArgs)
-	if err != nil {
-		ec.Error(ctx, err)
-		return graphql.Null
-	}
-	rctx.Args = args
 	ctx = ec.Tracer.StartFieldResolverExecution(ctx, rctx)
-	resTmp := ec.FieldMiddleware(ctx, nil, func(rctx context.Context) (interface{}, error) {
+	resTmp := ec.FieldMiddleware(ctx, obj, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return ec.resolvers.Mutation().RemoveEdge(rctx, args["input"].(RemoveEdgeInput))
+		return obj.Node, nil
 	})
 	if resTmp == nil {
-		if !ec.HasError(rctx) {
-			ec.Errorf(ctx, "must not be null")
-		}
 		return graphql.Null
 	}
-	res := resTmp.(*RemoveEdgeOutput)
+	res := resTmp.(*pregel.Node)
 	rctx.Result = res
 	ctx = ec.Tracer.StartFieldChildExecution(ctx)
-	return ec.marshalNRemoveEdgeOutput2ᚖgithubᚗcomᚋaᚑhᚋpregelᚋgraphᚐRemoveEdgeOutput(ctx, field.Selections, res)
+	return ec.marshalOGraphNode2ᚖgithubᚗcomᚋaᚑhᚋpregelᚐNode(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) _Mutation_setNodeFields(ctx context.Context, field graphql.CollectedField) graphql.Marshaler {
+func (ec *executionContext) _Edge_data(ctx context.Context, field graphql.CollectedField, obj *Edge) graphql.Marshaler {
 	ctx = ec.Tracer.StartFieldExecution(ctx, field)
 	defer func() { ec.Tracer.EndFieldExecution(ctx) }()
 	rctx := &graphql.ResolverContext{
-		Object:   "Mutation",
+		Object:   "Edge",
 		Field:    field,
 		Args:     nil,
-		IsMethod: true,
+		IsMethod: false,
 	}
 	ctx = graphql.WithResolverContext(ctx, rctx)
-	rawArgs := field.ArgumentMap(ec.Variables)
-	args, err := ec.field_Mutation_setNodeFields_args(ctx, rawArgs)
-	if err != nil {
-		ec.Error(ctx, err)
-		return graphql.Null
-	}
-	rctx.Args = args
 	ctx = ec.Tracer.StartFieldResolverExecution(ctx, rctx)
-	resTmp := ec.FieldMiddleware(ctx, nil, func(rctx context.Context) (interface{}, error) {
+	resTmp := ec.FieldMiddleware(ctx, obj, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return ec.resolvers.Mutation().SetNodeFields(rctx, args["input"].(SetNodeFieldsInput))
+		return obj.Data, nil
 	})
 	if resTmp == nil {
 		if !ec.HasError(rctx) {
@@ -1238,33 +2133,26 @@ func (ec *executionContext) _Mutation_setNodeFields(ctx context.Context, field g
 		}
 		return graphql.Null
 	}
-	res := resTmp.(*SetNodeFieldsOutput)
+	res := resTmp.([]EdgeDataItem)
 	rctx.Result = res
 	ctx = ec.Tracer.StartFieldChildExecution(ctx)
-	return ec.marshalNSetNodeFieldsOutput2ᚖgithubᚗcomᚋaᚑhᚋpregelᚋgraphᚐSetNodeFieldsOutput(ctx, field.Selections, res)
+	return ec.marshalNEdgeDataItem2ᚕgithubᚗcomᚋaᚑhᚋpregelᚋgraphᚐEdgeDataItem(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) _Mutation_setEdgeFields(ctx context.Context, field graphql.CollectedField) graphql.Marshaler {
+func (ec *executionContext) _Edge_json(ctx context.Context, field graphql.CollectedField, obj *Edge) graphql.Marshaler {
 	ctx = ec.Tracer.StartFieldExecution(ctx, field)
 	defer func() { ec.Tracer.EndFieldExecution(ctx) }()
 	rctx := &graphql.ResolverContext{
-		Object:   "Mutation",
+		Object:   "Edge",
 		Field:    field,
 		Args:     nil,
-		IsMethod: true,
+		IsMethod: false,
 	}
 	ctx = graphql.WithResolverContext(ctx, rctx)
-	rawArgs := field.ArgumentMap(ec.Variables)
-	args, err := ec.field_Mutation_setEdgeFields_args(ctx, rawArgs)
-	if err != nil {
-		ec.Error(ctx, err)
-		return graphql.Null
-	}
-	rctx.Args = args
 	ctx = ec.Tracer.StartFieldResolverExecution(ctx, rctx)
-	resTmp := ec.FieldMiddleware(ctx, nil, func(rctx context.Context) (interface{}, error) {
+	resTmp := ec.FieldMiddleware(ctx, obj, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return ec.resolvers.Mutation().SetEdgeFields(rctx, args["input"].(SetEdgeFieldsInput))
+		return obj.JSON, nil
 	})
 	if resTmp == nil {
 		if !ec.HasError(rctx) {
@@ -1272,17 +2160,17 @@ func (ec *executionContext) _Mutation_setEdgeFields(ctx context.Context, field g
 		}
 		return graphql.Null
 	}
-	res := resTmp.(*SetEdgeFieldsOutput)
+	res := resTmp.(map[string]interface{})
 	rctx.Result = res
 	ctx = ec.Tracer.StartFieldChildExecution(ctx)
-	return ec.marshalNSetEdgeFieldsOutput2ᚖgithubᚗcomᚋaᚑhᚋpregelᚋgraphᚐSetEdgeFieldsOutput(ctx, field.Selections, res)
+	return ec.marshalNJSON2map(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) _Node_id(ctx context.Context, field graphql.CollectedField, obj *pregel.Node) graphql.Marshaler {
+func (ec *executionContext) _Edge_createdAt(ctx context.Context, field graphql.CollectedField, obj *Edge) graphql.Marshaler {
 	ctx = ec.Tracer.StartFieldExecution(ctx, field)
 	defer func() { ec.Tracer.EndFieldExecution(ctx) }()
 	rctx := &graphql.ResolverContext{
-		Object:   "Node",
+		Object:   "Edge",
 		Field:    field,
 		Args:     nil,
 		IsMethod: false,
@@ -1291,87 +2179,73 @@ func (ec *executionContext) _Node_id(ctx context.Context, field graphql.Collecte
 	ctx = ec.Tracer.StartFieldResolverExecution(ctx, rctx)
 	resTmp := ec.FieldMiddleware(ctx, obj, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.ID, nil
+		return obj.CreatedAt, nil
 	})
 	if resTmp == nil {
-		if !ec.HasError(rctx) {
-			ec.Errorf(ctx, "must not be null")
-		}
 		return graphql.Null
 	}
-	res := resTmp.(string)
+	res := resTmp.(*time.Time)
 	rctx.Result = res
 	ctx = ec.Tracer.StartFieldChildExecution(ctx)
-	return ec.marshalNID2string(ctx, field.Selections, res)
+	return ec.marshalODateTime2ᚖtimeᚐTime(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) _Node_parents(ctx context.Context, field graphql.CollectedField, obj *pregel.Node) graphql.Marshaler {
+func (ec *executionContext) _Edge_updatedAt(ctx context.Context, field graphql.CollectedField, obj *Edge) graphql.Marshaler {
 	ctx = ec.Tracer.StartFieldExecution(ctx, field)
 	defer func() { ec.Tracer.EndFieldExecution(ctx) }()
 	rctx := &graphql.ResolverContext{
-		Object:   "Node",
+		Object:   "Edge",
 		Field:    field,
 		Args:     nil,
-		IsMethod: true,
+		IsMethod: false,
 	}
 	ctx = graphql.WithResolverContext(ctx, rctx)
-	rawArgs := field.ArgumentMap(ec.Variables)
-	args, err := ec.field_Node_parents_args(ctx, rawArgs)
-	if err != nil {
-		ec.Error(ctx, err)
-		return graphql.Null
-	}
-	rctx.Args = args
 	ctx = ec.Tracer.StartFieldResolverExecution(ctx, rctx)
 	resTmp := ec.FieldMiddleware(ctx, obj, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return ec.resolvers.Node().Parents(rctx, obj, args["first"].(int), args["after"].(*string))
+		return obj.UpdatedAt, nil
 	})
 	if resTmp == nil {
 		return graphql.Null
 	}
-	res := resTmp.(*Connection)
+	res := resTmp.(*time.Time)
 	rctx.Result = res
 	ctx = ec.Tracer.StartFieldChildExecution(ctx)
-	return ec.marshalOConnection2ᚖgithubᚗcomᚋaᚑhᚋpregelᚋgraphᚐConnection(ctx, field.Selections, res)
+	return ec.marshalODateTime2ᚖtimeᚐTime(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) _Node_children(ctx context.Context, field graphql.CollectedField, obj *pregel.Node) graphql.Marshaler {
+func (ec *executionContext) _GraphNode_id(ctx context.Context, field graphql.CollectedField, obj *pregel.Node) graphql.Marshaler {
 	ctx = ec.Tracer.StartFieldExecution(ctx, field)
 	defer func() { ec.Tracer.EndFieldExecution(ctx) }()
 	rctx := &graphql.ResolverContext{
-		Object:   "Node",
+		Object:   "GraphNode",
 		Field:    field,
 		Args:     nil,
 		IsMethod: true,
 	}
 	ctx = graphql.WithResolverContext(ctx, rctx)
-	rawArgs := field.ArgumentMap(ec.Variables)
-	args, err := ec.field_Node_children_args(ctx, rawArgs)
-	if err != nil {
-		ec.Error(ctx, err)
-		return graphql.Null
-	}
-	rctx.Args = args
 	ctx = ec.Tracer.StartFieldResolverExecution(ctx, rctx)
 	resTmp := ec.FieldMiddleware(ctx, obj, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return ec.resolvers.Node().Children(rctx, obj, args["first"].(int), args["after"].(*string))
+		return ec.resolvers.GraphNode().ID(rctx, obj)
 	})
 	if resTmp == nil {
+		if !ec.HasError(rctx) {
+			ec.Errorf(ctx, "must not be null")
+		}
 		return graphql.Null
 	}
-	res := resTmp.(*Connection)
+	res := resTmp.(string)
 	rctx.Result = res
 	ctx = ec.Tracer.StartFieldChildExecution(ctx)
-	return ec.marshalOConnection2ᚖgithubᚗcomᚋaᚑhᚋpregelᚋgraphᚐConnection(ctx, field.Selections, res)
+	return ec.marshalNID2string(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) _Node_data(ctx context.Context, field graphql.CollectedField, obj *pregel.Node) graphql.Marshaler {
+func (ec *executionContext) _GraphNode_nodeId(ctx context.Context, field graphql.CollectedField, obj *pregel.Node) graphql.Marshaler {
 	ctx = ec.Tracer.StartFieldExecution(ctx, field)
 	defer func() { ec.Tracer.EndFieldExecution(ctx) }()
 	rctx := &graphql.ResolverContext{
-		Object:   "Node",
+		Object:   "GraphNode",
 		Field:    field,
 		Args:     nil,
 		IsMethod: true,
@@ -1380,7 +2254,7 @@ func (ec *executionContext) _Node_data(ctx context.Context, field graphql.Collec
 	ctx = ec.Tracer.StartFieldResolverExecution(ctx, rctx)
 	resTmp := ec.FieldMiddleware(ctx, obj, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return ec.resolvers.Node().Data(rctx, obj)
+		return ec.resolvers.GraphNode().NodeID(rctx, obj)
 	})
 	if resTmp == nil {
 		if !ec.HasError(rctx) {
@@ -1388,77 +2262,88 @@ func (ec *executionContext) _Node_data(ctx context.Context, field graphql.Collec
 		}
 		return graphql.Null
 	}
-	res := resTmp.([]NodeDataItem)
+	res := resTmp.(string)
 	rctx.Result = res
 	ctx = ec.Tracer.StartFieldChildExecution(ctx)
-	return ec.marshalNNodeDataItem2ᚕgithubᚗcomᚋaᚑhᚋpregelᚋgraphᚐNodeDataItem(ctx, field.Selections, res)
+	return ec.marshalNID2string(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) _PageInfo_endCursor(ctx context.Context, field graphql.CollectedField, obj *PageInfo) graphql.Marshaler {
+func (ec *executionContext) _GraphNode_parents(ctx context.Context, field graphql.CollectedField, obj *pregel.Node) graphql.Marshaler {
 	ctx = ec.Tracer.StartFieldExecution(ctx, field)
 	defer func() { ec.Tracer.EndFieldExecution(ctx) }()
 	rctx := &graphql.ResolverContext{
-		Object:   "PageInfo",
+		Object:   "GraphNode",
 		Field:    field,
 		Args:     nil,
-		IsMethod: false,
+		IsMethod: true,
 	}
 	ctx = graphql.WithResolverContext(ctx, rctx)
+	rawArgs := field.ArgumentMap(ec.Variables)
+	args, err := ec.field_GraphNode_parents_args(ctx, rawArgs)
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	rctx.Args = args
 	ctx = ec.Tracer.StartFieldResolverExecution(ctx, rctx)
 	resTmp := ec.FieldMiddleware(ctx, obj, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.EndCursor, nil
+		return ec.resolvers.GraphNode().Parents(rctx, obj, args["first"].(int), args["after"].(*string), args["orderBy"].(*EdgeOrder))
 	})
 	if resTmp == nil {
 		return graphql.Null
 	}
-	res := resTmp.(*string)
+	res := resTmp.(*Connection)
 	rctx.Result = res
 	ctx = ec.Tracer.StartFieldChildExecution(ctx)
-	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
+	return ec.marshalOConnection2ᚖgithubᚗcomᚋaᚑhᚋpregelᚋgraphᚐConnection(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) _PageInfo_hasNextPage(ctx context.Context, field graphql.CollectedField, obj *PageInfo) graphql.Marshaler {
+func (ec *executionContext) _GraphNode_children(ctx context.Context, field graphql.CollectedField, obj *pregel.Node) graphql.Marshaler {
 	ctx = ec.Tracer.StartFieldExecution(ctx, field)
 	defer func() { ec.Tracer.EndFieldExecution(ctx) }()
 	rctx := &graphql.ResolverContext{
-		Object:   "PageInfo",
+		Object:   "GraphNode",
 		Field:    field,
 		Args:     nil,
-		IsMethod: false,
+		IsMethod: true,
 	}
 	ctx = graphql.WithResolverContext(ctx, rctx)
+	rawArgs := field.ArgumentMap(ec.Variables)
+	args, err := ec.field_GraphNode_children_args(ctx, rawArgs)
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	rctx.Args = args
 	ctx = ec.Tracer.StartFieldResolverExecution(ctx, rctx)
 	resTmp := ec.FieldMiddleware(ctx, obj, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.HasNextPage, nil
+		return ec.resolvers.GraphNode().Children(rctx, obj, args["first"].(int), args["after"].(*string), args["orderBy"].(*EdgeOrder))
 	})
 	if resTmp == nil {
-		if !ec.HasError(rctx) {
-			ec.Errorf(ctx, "must not be null")
-		}
 		return graphql.Null
 	}
-	res := resTmp.(bool)
+	res := resTmp.(*Connection)
 	rctx.Result = res
 	ctx = ec.Tracer.StartFieldChildExecution(ctx)
-	return ec.marshalNBoolean2bool(ctx, field.Selections, res)
+	return ec.marshalOConnection2ᚖgithubᚗcomᚋaᚑhᚋpregelᚋgraphᚐConnection(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) _PageInfo_hasPreviousPage(ctx context.Context, field graphql.CollectedField, obj *PageInfo) graphql.Marshaler {
+func (ec *executionContext) _GraphNode_data(ctx context.Context, field graphql.CollectedField, obj *pregel.Node) graphql.Marshaler {
 	ctx = ec.Tracer.StartFieldExecution(ctx, field)
 	defer func() { ec.Tracer.EndFieldExecution(ctx) }()
 	rctx := &graphql.ResolverContext{
-		Object:   "PageInfo",
+		Object:   "GraphNode",
 		Field:    field,
 		Args:     nil,
-		IsMethod: false,
+		IsMethod: true,
 	}
 	ctx = graphql.WithResolverContext(ctx, rctx)
 	ctx = ec.Tracer.StartFieldResolverExecution(ctx, rctx)
 	resTmp := ec.FieldMiddleware(ctx, obj, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.HasPreviousPage, nil
+		return ec.resolvers.GraphNode().Data(rctx, obj)
 	})
 	if resTmp == nil {
 		if !ec.HasError(rctx) {
@@ -1466,127 +2351,119 @@ func (ec *executionContext) _PageInfo_hasPreviousPage(ctx context.Context, field
 		}
 		return graphql.Null
 	}
-	res := resTmp.(bool)
+	res := resTmp.([]NodeDataItem)
 	rctx.Result = res
 	ctx = ec.Tracer.StartFieldChildExecution(ctx)
-	return ec.marshalNBoolean2bool(ctx, field.Selections, res)
+	return ec.marshalNNodeDataItem2ᚕgithubᚗcomᚋaᚑhᚋpregelᚋgraphᚐNodeDataItem(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) _PageInfo_startCursor(ctx context.Context, field graphql.CollectedField, obj *PageInfo) graphql.Marshaler {
+func (ec *executionContext) _GraphNode_json(ctx context.Context, field graphql.CollectedField, obj *pregel.Node) graphql.Marshaler {
 	ctx = ec.Tracer.StartFieldExecution(ctx, field)
 	defer func() { ec.Tracer.EndFieldExecution(ctx) }()
 	rctx := &graphql.ResolverContext{
-		Object:   "PageInfo",
+		Object:   "GraphNode",
 		Field:    field,
 		Args:     nil,
-		IsMethod: false,
+		IsMethod: true,
 	}
 	ctx = graphql.WithResolverContext(ctx, rctx)
 	ctx = ec.Tracer.StartFieldResolverExecution(ctx, rctx)
 	resTmp := ec.FieldMiddleware(ctx, obj, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.StartCursor, nil
+		return ec.resolvers.GraphNode().JSON(rctx, obj)
 	})
 	if resTmp == nil {
+		if !ec.HasError(rctx) {
+			ec.Errorf(ctx, "must not be null")
+		}
 		return graphql.Null
 	}
-	res := resTmp.(*string)
+	res := resTmp.(map[string]interface{})
 	rctx.Result = res
 	ctx = ec.Tracer.StartFieldChildExecution(ctx)
-	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
+	return ec.marshalNJSON2map(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) _Query_get(ctx context.Context, field graphql.CollectedField) graphql.Marshaler {
+func (ec *executionContext) _GraphNode_createdAt(ctx context.Context, field graphql.CollectedField, obj *pregel.Node) graphql.Marshaler {
 	ctx = ec.Tracer.StartFieldExecution(ctx, field)
 	defer func() { ec.Tracer.EndFieldExecution(ctx) }()
 	rctx := &graphql.ResolverContext{
-		Object:   "Query",
+		Object:   "GraphNode",
 		Field:    field,
 		Args:     nil,
 		IsMethod: true,
 	}
 	ctx = graphql.WithResolverContext(ctx, rctx)
-	rawArgs := field.ArgumentMap(ec.Variables)
-	args, err := ec.field_Query_get_args(ctx, rawArgs)
-	if err != nil {
-		ec.Error(ctx, err)
-		return graphql.Null
-	}
-	rctx.Args = args
 	ctx = ec.Tracer.StartFieldResolverExecution(ctx, rctx)
-	resTmp := ec.FieldMiddleware(ctx, nil, func(rctx context.Context) (interface{}, error) {
+	resTmp := ec.FieldMiddleware(ctx, obj, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return ec.resolvers.Query().Get(rctx, args["id"].(string))
+		return ec.resolvers.GraphNode().CreatedAt(rctx, obj)
 	})
 	if resTmp == nil {
 		return graphql.Null
 	}
-	res := resTmp.(*pregel.Node)
+	res := resTmp.(*time.Time)
 	rctx.Result = res
 	ctx = ec.Tracer.StartFieldChildExecution(ctx)
-	return ec.marshalONode2ᚖgithubᚗcomᚋaᚑhᚋpregelᚐNode(ctx, field.Selections, res)
+	return ec.marshalODateTime2ᚖtimeᚐTime(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) _Query___type(ctx context.Context, field graphql.CollectedField) graphql.Marshaler {
+func (ec *executionContext) _GraphNode_updatedAt(ctx context.Context, field graphql.CollectedField, obj *pregel.Node) graphql.Marshaler {
 	ctx = ec.Tracer.StartFieldExecution(ctx, field)
 	defer func() { ec.Tracer.EndFieldExecution(ctx) }()
 	rctx := &graphql.ResolverContext{
-		Object:   "Query",
+		Object:   "GraphNode",
 		Field:    field,
 		Args:     nil,
 		IsMethod: true,
 	}
 	ctx = graphql.WithResolverContext(ctx, rctx)
-	rawArgs := field.ArgumentMap(ec.Variables)
-	args, err := ec.field_Query___type_args(ctx, rawArgs)
-	if err != nil {
-		ec.Error(ctx, err)
-		return graphql.Null
-	}
-	rctx.Args = args
 	ctx = ec.Tracer.StartFieldResolverExecution(ctx, rctx)
-	resTmp := ec.FieldMiddleware(ctx, nil, func(rctx context.Context) (interface{}, error) {
+	resTmp := ec.FieldMiddleware(ctx, obj, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return ec.introspectType(args["name"].(string))
+		return ec.resolvers.GraphNode().UpdatedAt(rctx, obj)
 	})
 	if resTmp == nil {
 		return graphql.Null
 	}
-	res := resTmp.(*introspection.Type)
+	res := resTmp.(*time.Time)
 	rctx.Result = res
 	ctx = ec.Tracer.StartFieldChildExecution(ctx)
-	return ec.marshalO__Type2ᚖgithubᚗcomᚋ99designsᚋgqlgenᚋgraphqlᚋintrospectionᚐType(ctx, field.Selections, res)
+	return ec.marshalODateTime2ᚖtimeᚐTime(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) _Query___schema(ctx context.Context, field graphql.CollectedField) graphql.Marshaler {
+func (ec *executionContext) _GraphNode_typed(ctx context.Context, field graphql.CollectedField, obj *pregel.Node) graphql.Marshaler {
 	ctx = ec.Tracer.StartFieldExecution(ctx, field)
 	defer func() { ec.Tracer.EndFieldExecution(ctx) }()
 	rctx := &graphql.ResolverContext{
-		Object:   "Query",
+		Object:   "GraphNode",
 		Field:    field,
 		Args:     nil,
 		IsMethod: true,
 	}
 	ctx = graphql.WithResolverContext(ctx, rctx)
 	ctx = ec.Tracer.StartFieldResolverExecution(ctx, rctx)
-	resTmp := ec.FieldMiddleware(ctx, nil, func(rctx context.Context) (interface{}, error) {
+	resTmp := ec.FieldMiddleware(ctx, obj, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return ec.introspectSchema()
+		return ec.resolvers.GraphNode().Typed(rctx, obj)
 	})
 	if resTmp == nil {
+		if !ec.HasError(rctx) {
+			ec.Errorf(ctx, "must not be null")
+		}
 		return graphql.Null
 	}
-	res := resTmp.(*introspection.Schema)
+	res := resTmp.(RelayNode)
 	rctx.Result = res
 	ctx = ec.Tracer.StartFieldChildExecution(ctx)
-	return ec.marshalO__Schema2ᚖgithubᚗcomᚋ99designsᚋgqlgenᚋgraphqlᚋintrospectionᚐSchema(ctx, field.Selections, res)
+	return ec.marshalNNode2githubᚗcomᚋaᚑhᚋpregelᚋgraphᚐRelayNode(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) _RemoveEdgeOutput_removed(ctx context.Context, field graphql.CollectedField, obj *RemoveEdgeOutput) graphql.Marshaler {
+func (ec *executionContext) _ImportGraphOutput_nodesRead(ctx context.Context, field graphql.CollectedField, obj *ImportGraphOutput) graphql.Marshaler {
 	ctx = ec.Tracer.StartFieldExecution(ctx, field)
 	defer func() { ec.Tracer.EndFieldExecution(ctx) }()
 	rctx := &graphql.ResolverContext{
-		Object:   "RemoveEdgeOutput",
+		Object:   "ImportGraphOutput",
 		Field:    field,
 		Args:     nil,
 		IsMethod: false,
@@ -1595,7 +2472,7 @@ func (ec *executionContext) _RemoveEdgeOutput_removed(ctx context.Context, field
 	ctx = ec.Tracer.StartFieldResolverExecution(ctx, rctx)
 	resTmp := ec.FieldMiddleware(ctx, obj, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Removed, nil
+		return obj.NodesRead, nil
 	})
 	if resTmp == nil {
 		if !ec.HasError(rctx) {
@@ -1603,17 +2480,17 @@ func (ec *executionContext) _RemoveEdgeOutput_removed(ctx context.Context, field
 		}
 		return graphql.Null
 	}
-	res := resTmp.(bool)
+	res := resTmp.(int)
 	rctx.Result = res
 	ctx = ec.Tracer.StartFieldChildExecution(ctx)
-	return ec.marshalNBoolean2bool(ctx, field.Selections, res)
+	return ec.marshalNInt2int(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) _RemoveNodeOutput_removed(ctx context.Context, field graphql.CollectedField, obj *RemoveNodeOutput) graphql.Marshaler {
+func (ec *executionContext) _ImportGraphOutput_recordsWritten(ctx context.Context, field graphql.CollectedField, obj *ImportGraphOutput) graphql.Marshaler {
 	ctx = ec.Tracer.StartFieldExecution(ctx, field)
 	defer func() { ec.Tracer.EndFieldExecution(ctx) }()
 	rctx := &graphql.ResolverContext{
-		Object:   "RemoveNodeOutput",
+		Object:   "ImportGraphOutput",
 		Field:    field,
 		Args:     nil,
 		IsMethod: false,
@@ -1622,7 +2499,7 @@ func (ec *executionContext) _RemoveNodeOutput_removed(ctx context.Context, field
 	ctx = ec.Tracer.StartFieldResolverExecution(ctx, rctx)
 	resTmp := ec.FieldMiddleware(ctx, obj, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Removed, nil
+		return obj.RecordsWritten, nil
 	})
 	if resTmp == nil {
 		if !ec.HasError(rctx) {
@@ -1630,17 +2507,17 @@ func (ec *executionContext) _RemoveNodeOutput_removed(ctx context.Context, field
 		}
 		return graphql.Null
 	}
-	res := resTmp.(bool)
+	res := resTmp.(int)
 	rctx.Result = res
 	ctx = ec.Tracer.StartFieldChildExecution(ctx)
-	return ec.marshalNBoolean2bool(ctx, field.Selections, res)
+	return ec.marshalNInt2int(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) _SaveEdgeOutput_parent(ctx context.Context, field graphql.CollectedField, obj *SaveEdgeOutput) graphql.Marshaler {
+func (ec *executionContext) _Location_lng(ctx context.Context, field graphql.CollectedField, obj *Location) graphql.Marshaler {
 	ctx = ec.Tracer.StartFieldExecution(ctx, field)
 	defer func() { ec.Tracer.EndFieldExecution(ctx) }()
 	rctx := &graphql.ResolverContext{
-		Object:   "SaveEdgeOutput",
+		Object:   "Location",
 		Field:    field,
 		Args:     nil,
 		IsMethod: false,
@@ -1649,7 +2526,7 @@ func (ec *executionContext) _SaveEdgeOutput_parent(ctx context.Context, field gr
 	ctx = ec.Tracer.StartFieldResolverExecution(ctx, rctx)
 	resTmp := ec.FieldMiddleware(ctx, obj, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Parent, nil
+		return obj.Lng, nil
 	})
 	if resTmp == nil {
 		if !ec.HasError(rctx) {
@@ -1657,17 +2534,17 @@ func (ec *executionContext) _SaveEdgeOutput_parent(ctx context.Context, field gr
 		}
 		return graphql.Null
 	}
-	res := resTmp.(string)
+	res := resTmp.(float64)
 	rctx.Result = res
 	ctx = ec.Tracer.StartFieldChildExecution(ctx)
-	return ec.marshalNID2string(ctx, field.Selections, res)
+	return ec.marshalNFloat2float64(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) _SaveEdgeOutput_child(ctx context.Context, field graphql.CollectedField, obj *SaveEdgeOutput) graphql.Marshaler {
+func (ec *executionContext) _Location_lat(ctx context.Context, field graphql.CollectedField, obj *Location) graphql.Marshaler {
 	ctx = ec.Tracer.StartFieldExecution(ctx, field)
 	defer func() { ec.Tracer.EndFieldExecution(ctx) }()
 	rctx := &graphql.ResolverContext{
-		Object:   "SaveEdgeOutput",
+		Object:   "Location",
 		Field:    field,
 		Args:     nil,
 		IsMethod: false,
@@ -1676,7 +2553,7 @@ func (ec *executionContext) _SaveEdgeOutput_child(ctx context.Context, field gra
 	ctx = ec.Tracer.StartFieldResolverExecution(ctx, rctx)
 	resTmp := ec.FieldMiddleware(ctx, obj, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Child, nil
+		return obj.Lat, nil
 	})
 	if resTmp == nil {
 		if !ec.HasError(rctx) {
@@ -1684,17 +2561,17 @@ func (ec *executionContext) _SaveEdgeOutput_child(ctx context.Context, field gra
 		}
 		return graphql.Null
 	}
-	res := resTmp.(string)
+	res := resTmp.(float64)
 	rctx.Result = res
 	ctx = ec.Tracer.StartFieldChildExecution(ctx)
-	return ec.marshalNID2string(ctx, field.Selections, res)
+	return ec.marshalNFloat2float64(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) _SaveNodeOutput_id(ctx context.Context, field graphql.CollectedField, obj *SaveNodeOutput) graphql.Marshaler {
+func (ec *executionContext) _LocationNode_id(ctx context.Context, field graphql.CollectedField, obj *LocationNode) graphql.Marshaler {
 	ctx = ec.Tracer.StartFieldExecution(ctx, field)
 	defer func() { ec.Tracer.EndFieldExecution(ctx) }()
 	rctx := &graphql.ResolverContext{
-		Object:   "SaveNodeOutput",
+		Object:   "LocationNode",
 		Field:    field,
 		Args:     nil,
 		IsMethod: false,
@@ -1717,11 +2594,11 @@ func (ec *executionContext) _SaveNodeOutput_id(ctx context.Context, field graphq
 	return ec.marshalNID2string(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) _SetEdgeFieldsOutput_set(ctx context.Context, field graphql.CollectedField, obj *SetEdgeFieldsOutput) graphql.Marshaler {
+func (ec *executionContext) _LocationNode_nodeId(ctx context.Context, field graphql.CollectedField, obj *LocationNode) graphql.Marshaler {
 	ctx = ec.Tracer.StartFieldExecution(ctx, field)
 	defer func() { ec.Tracer.EndFieldExecution(ctx) }()
 	rctx := &graphql.ResolverContext{
-		Object:   "SetEdgeFieldsOutput",
+		Object:   "LocationNode",
 		Field:    field,
 		Args:     nil,
 		IsMethod: false,
@@ -1730,7 +2607,7 @@ func (ec *executionContext) _SetEdgeFieldsOutput_set(ctx context.Context, field
 	ctx = ec.Tracer.StartFieldResolverExecution(ctx, rctx)
 	resTmp := ec.FieldMiddleware(ctx, obj, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Set, nil
+		return obj.NodeID, nil
 	})
 	if resTmp == nil {
 		if !ec.HasError(rctx) {
@@ -1738,17 +2615,17 @@ func (ec *executionContext) _SetEdgeFieldsOutput_set(ctx context.Context, field
 		}
 		return graphql.Null
 	}
-	res := resTmp.(bool)
+	res := resTmp.(string)
 	rctx.Result = res
 	ctx = ec.Tracer.StartFieldChildExecution(ctx)
-	return ec.marshalNBoolean2bool(ctx, field.Selections, res)
+	return ec.marshalNID2string(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) _SetNodeFieldsOutput_set(ctx context.Context, field graphql.CollectedField, obj *SetNodeFieldsOutput) graphql.Marshaler {
+func (ec *executionContext) _LocationNode_location(ctx context.Context, field graphql.CollectedField, obj *LocationNode) graphql.Marshaler {
 	ctx = ec.Tracer.StartFieldExecution(ctx, field)
 	defer func() { ec.Tracer.EndFieldExecution(ctx) }()
 	rctx := &graphql.ResolverContext{
-		Object:   "SetNodeFieldsOutput",
+		Object:   "LocationNode",
 		Field:    field,
 		Args:     nil,
 		IsMethod: false,
@@ -1757,7 +2634,7 @@ func (ec *executionContext) _SetNodeFieldsOutput_set(ctx context.Context, field
 	ctx = ec.Tracer.StartFieldResolverExecution(ctx, rctx)
 	resTmp := ec.FieldMiddleware(ctx, obj, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Set, nil
+		return obj.Location, nil
 	})
 	if resTmp == nil {
 		if !ec.HasError(rctx) {
@@ -1765,17 +2642,17 @@ func (ec *executionContext) _SetNodeFieldsOutput_set(ctx context.Context, field
 		}
 		return graphql.Null
 	}
-	res := resTmp.(bool)
+	res := resTmp.(*Location)
 	rctx.Result = res
 	ctx = ec.Tracer.StartFieldChildExecution(ctx)
-	return ec.marshalNBoolean2bool(ctx, field.Selections, res)
+	return ec.marshalNLocation2ᚖgithubᚗcomᚋaᚑhᚋpregelᚋgraphᚐLocation(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) ___Directive_name(ctx context.Context, field graphql.CollectedField, obj *introspection.Directive) graphql.Marshaler {
+func (ec *executionContext) _LocationNode_node(ctx context.Context, field graphql.CollectedField, obj *LocationNode) graphql.Marshaler {
 	ctx = ec.Tracer.StartFieldExecution(ctx, field)
 	defer func() { ec.Tracer.EndFieldExecution(ctx) }()
 	rctx := &graphql.ResolverContext{
-		Object:   "__Directive",
+		Object:   "LocationNode",
 		Field:    field,
 		Args:     nil,
 		IsMethod: false,
@@ -1784,7 +2661,7 @@ func (ec *executionContext) ___Directive_name(ctx context.Context, field graphql
 	ctx = ec.Tracer.StartFieldResolverExecution(ctx, rctx)
 	resTmp := ec.FieldMiddleware(ctx, obj, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Name, nil
+		return obj.Node, nil
 	})
 	if resTmp == nil {
 		if !ec.HasError(rctx) {
@@ -1792,50 +2669,67 @@ func (ec *executionContext) ___Directive_name(ctx context.Context, field graphql
 		}
 		return graphql.Null
 	}
-	res := resTmp.(string)
+	res := resTmp.(*pregel.Node)
 	rctx.Result = res
 	ctx = ec.Tracer.StartFieldChildExecution(ctx)
-	return ec.marshalNString2string(ctx, field.Selections, res)
+	return ec.marshalNGraphNode2ᚖgithubᚗcomᚋaᚑhᚋpregelᚐNode(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) ___Directive_description(ctx context.Context, field graphql.CollectedField, obj *introspection.Directive) graphql.Marshaler {
+func (ec *executionContext) _Mutation_saveNode(ctx context.Context, field graphql.CollectedField) graphql.Marshaler {
 	ctx = ec.Tracer.StartFieldExecution(ctx, field)
 	defer func() { ec.Tracer.EndFieldExecution(ctx) }()
 	rctx := &graphql.ResolverContext{
-		Object:   "__Directive",
+		Object:   "Mutation",
 		Field:    field,
 		Args:     nil,
-		IsMethod: false,
+		IsMethod: true,
 	}
 	ctx = graphql.WithResolverContext(ctx, rctx)
+	rawArgs := field.ArgumentMap(ec.Variables)
+	args, err := ec.field_Mutation_saveNode_args(ctx, rawArgs)
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	rctx.Args = args
 	ctx = ec.Tracer.StartFieldResolverExecution(ctx, rctx)
-	resTmp := ec.FieldMiddleware(ctx, obj, func(rctx context.Context) (interface{}, error) {
+	resTmp := ec.FieldMiddleware(ctx, nil, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Description, nil
+		return ec.resolvers.Mutation().SaveNode(rctx, args["node"].(SaveNodeInput))
 	})
 	if resTmp == nil {
+		if !ec.HasError(rctx) {
+			ec.Errorf(ctx, "must not be null")
+		}
 		return graphql.Null
 	}
-	res := resTmp.(string)
+	res := resTmp.(*SaveNodeOutput)
 	rctx.Result = res
 	ctx = ec.Tracer.StartFieldChildExecution(ctx)
-	return ec.marshalOString2string(ctx, field.Selections, res)
+	return ec.marshalNSaveNodeOutput2ᚖgithubᚗcomᚋaᚑhᚋpregelᚋgraphᚐSaveNodeOutput(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) ___Directive_locations(ctx context.Context, field graphql.CollectedField, obj *introspection.Directive) graphql.Marshaler {
+func (ec *executionContext) _Mutation_saveEdge(ctx context.Context, field graphql.CollectedField) graphql.Marshaler {
 	ctx = ec.Tracer.StartFieldExecution(ctx, field)
 	defer func() { ec.Tracer.EndFieldExecution(ctx) }()
 	rctx := &graphql.ResolverContext{
-		Object:   "__Directive",
+		Object:   "Mutation",
 		Field:    field,
 		Args:     nil,
-		IsMethod: false,
+		IsMethod: true,
 	}
 	ctx = graphql.WithResolverContext(ctx, rctx)
-	ctx = ec.Tracer.StartFieldResolverExecution(ctx, rctx)
-	resTmp := ec.FieldMiddleware(ctx, obj, func(rctx context.Context) (interface{}, error) {
+	rawArgs := field.ArgumentMap(ec.Variables)
+	args, err := ec.field_Mutation_saveEdge_args(ctx, rawArgs)
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	rctx.Args = args
+	ctx = ec.Tracer.StartFieldResolverExecution(ctx, rctx)
+	resTmp := ec.FieldMiddleware(ctx, nil, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Locations, nil
+		return ec.resolvers.Mutation().SaveEdge(rctx, args["edge"].(SaveEdgeInput))
 	})
 	if resTmp == nil {
 		if !ec.HasError(rctx) {
@@ -1843,26 +2737,33 @@ func (ec *executionContext) ___Directive_locations(ctx context.Context, field gr
 		}
 		return graphql.Null
 	}
-	res := resTmp.([]string)
+	res := resTmp.(*SaveEdgeOutput)
 	rctx.Result = res
 	ctx = ec.Tracer.StartFieldChildExecution(ctx)
-	return ec.marshalN__DirectiveLocation2ᚕstring(ctx, field.Selections, res)
+	return ec.marshalNSaveEdgeOutput2ᚖgithubᚗcomᚋaᚑhᚋpregelᚋgraphᚐSaveEdgeOutput(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) ___Directive_args(ctx context.Context, field graphql.CollectedField, obj *introspection.Directive) graphql.Marshaler {
+func (ec *executionContext) _Mutation_removeNode(ctx context.Context, field graphql.CollectedField) graphql.Marshaler {
 	ctx = ec.Tracer.StartFieldExecution(ctx, field)
 	defer func() { ec.Tracer.EndFieldExecution(ctx) }()
 	rctx := &graphql.ResolverContext{
-		Object:   "__Directive",
+		Object:   "Mutation",
 		Field:    field,
 		Args:     nil,
-		IsMethod: false,
+		IsMethod: true,
 	}
 	ctx = graphql.WithResolverContext(ctx, rctx)
+	rawArgs := field.ArgumentMap(ec.Variables)
+	args, err := ec.field_Mutation_removeNode_args(ctx, rawArgs)
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	rctx.Args = args
 	ctx = ec.Tracer.StartFieldResolverExecution(ctx, rctx)
-	resTmp := ec.FieldMiddleware(ctx, obj, func(rctx context.Context) (interface{}, error) {
+	resTmp := ec.FieldMiddleware(ctx, nil, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Args, nil
+		return ec.resolvers.Mutation().RemoveNode(rctx, args["input"].(RemoveNodeInput))
 	})
 	if resTmp == nil {
 		if !ec.HasError(rctx) {
@@ -1870,26 +2771,33 @@ func (ec *executionContext) ___Directive_args(ctx context.Context, field graphql
 		}
 		return graphql.Null
 	}
-	res := resTmp.([]introspection.InputValue)
+	res := resTmp.(*RemoveNodeOutput)
 	rctx.Result = res
 	ctx = ec.Tracer.StartFieldChildExecution(ctx)
-	return ec.marshalN__InputValue2ᚕgithubᚗcomᚋ99designsᚋgqlgenᚋgraphqlᚋintrospectionᚐInputValue(ctx, field.Selections, res)
+	return ec.marshalNRemoveNodeOutput2ᚖgithubᚗcomᚋaᚑhᚋpregelᚋgraphᚐRemoveNodeOutput(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) ___EnumValue_name(ctx context.Context, field graphql.CollectedField, obj *introspection.EnumValue) graphql.Marshaler {
+func (ec *executionContext) _Mutation_removeEdge(ctx context.Context, field graphql.CollectedField) graphql.Marshaler {
 	ctx = ec.Tracer.StartFieldExecution(ctx, field)
 	defer func() { ec.Tracer.EndFieldExecution(ctx) }()
 	rctx := &graphql.ResolverContext{
-		Object:   "__EnumValue",
+		Object:   "Mutation",
 		Field:    field,
 		Args:     nil,
-		IsMethod: false,
+		IsMethod: true,
 	}
 	ctx = graphql.WithResolverContext(ctx, rctx)
+	rawArgs := field.ArgumentMap(ec.Variables)
+	args, err := ec.field_Mutation_removeEdge_args(ctx, rawArgs)
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	rctx.Args = args
 	ctx = ec.Tracer.StartFieldResolverExecution(ctx, rctx)
-	resTmp := ec.FieldMiddleware(ctx, obj, func(rctx context.Context) (interface{}, error) {
+	resTmp := ec.FieldMiddleware(ctx, nil, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Name, nil
+		return ec.resolvers.Mutation().RemoveEdge(rctx, args["input"].(RemoveEdgeInput))
 	})
 	if resTmp == nil {
 		if !ec.HasError(rctx) {
@@ -1897,50 +2805,67 @@ func (ec *executionContext) ___EnumValue_name(ctx context.Context, field graphql
 		}
 		return graphql.Null
 	}
-	res := resTmp.(string)
+	res := resTmp.(*RemoveEdgeOutput)
 	rctx.Result = res
 	ctx = ec.Tracer.StartFieldChildExecution(ctx)
-	return ec.marshalNString2string(ctx, field.Selections, res)
+	return ec.marshalNRemoveEdgeOutput2ᚖgithubᚗcomᚋaᚑhᚋpregelᚋgraphᚐRemoveEdgeOutput(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) ___EnumValue_description(ctx context.Context, field graphql.CollectedField, obj *introspection.EnumValue) graphql.Marshaler {
+func (ec *executionContext) _Mutation_setNodeFields(ctx context.Context, field graphql.CollectedField) graphql.Marshaler {
 	ctx = ec.Tracer.StartFieldExecution(ctx, field)
 	defer func() { ec.Tracer.EndFieldExecution(ctx) }()
 	rctx := &graphql.ResolverContext{
-		Object:   "__EnumValue",
+		Object:   "Mutation",
 		Field:    field,
 		Args:     nil,
-		IsMethod: false,
+		IsMethod: true,
 	}
 	ctx = graphql.WithResolverContext(ctx, rctx)
+	rawArgs := field.ArgumentMap(ec.Variables)
+	args, err := ec.field_Mutation_setNodeFields_args(ctx, rawArgs)
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	rctx.Args = args
 	ctx = ec.Tracer.StartFieldResolverExecution(ctx, rctx)
-	resTmp := ec.FieldMiddleware(ctx, obj, func(rctx context.Context) (interface{}, error) {
+	resTmp := ec.FieldMiddleware(ctx, nil, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Description, nil
+		return ec.resolvers.Mutation().SetNodeFields(rctx, args["input"].(SetNodeFieldsInput))
 	})
 	if resTmp == nil {
+		if !ec.HasError(rctx) {
+			ec.Errorf(ctx, "must not be null")
+		}
 		return graphql.Null
 	}
-	res := resTmp.(string)
+	res := resTmp.(*SetNodeFieldsOutput)
 	rctx.Result = res
 	ctx = ec.Tracer.StartFieldChildExecution(ctx)
-	return ec.marshalOString2string(ctx, field.Selections, res)
+	return ec.marshalNSetNodeFieldsOutput2ᚖgithubᚗcomᚋaᚑhᚋpregelᚋgraphᚐSetNodeFieldsOutput(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) ___EnumValue_isDeprecated(ctx context.Context, field graphql.CollectedField, obj *introspection.EnumValue) graphql.Marshaler {
+func (ec *executionContext) _Mutation_setEdgeFields(ctx context.Context, field graphql.CollectedField) graphql.Marshaler {
 	ctx = ec.Tracer.StartFieldExecution(ctx, field)
 	defer func() { ec.Tracer.EndFieldExecution(ctx) }()
 	rctx := &graphql.ResolverContext{
-		Object:   "__EnumValue",
+		Object:   "Mutation",
 		Field:    field,
 		Args:     nil,
 		IsMethod: true,
 	}
 	ctx = graphql.WithResolverContext(ctx, rctx)
+	rawArgs := field.ArgumentMap(ec.Variables)
+	args, err := ec.field_Mutation_setEdgeFields_args(ctx, rawArgs)
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	rctx.Args = args
 	ctx = ec.Tracer.StartFieldResolverExecution(ctx, rctx)
-	resTmp := ec.FieldMiddleware(ctx, obj, func(rctx context.Context) (interface{}, error) {
+	resTmp := ec.FieldMiddleware(ctx, nil, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.IsDeprecated(), nil
+		return ec.resolvers.Mutation().SetEdgeFields(rctx, args["input"].(SetEdgeFieldsInput))
 	})
 	if resTmp == nil {
 		if !ec.HasError(rctx) {
@@ -1948,50 +2873,67 @@ func (ec *executionContext) ___EnumValue_isDeprecated(ctx context.Context, field
 		}
 		return graphql.Null
 	}
-	res := resTmp.(bool)
+	res := resTmp.(*SetEdgeFieldsOutput)
 	rctx.Result = res
 	ctx = ec.Tracer.StartFieldChildExecution(ctx)
-	return ec.marshalNBoolean2bool(ctx, field.Selections, res)
+	return ec.marshalNSetEdgeFieldsOutput2ᚖgithubᚗcomᚋaᚑhᚋpregelᚋgraphᚐSetEdgeFieldsOutput(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) ___EnumValue_deprecationReason(ctx context.Context, field graphql.CollectedField, obj *introspection.EnumValue) graphql.Marshaler {
+func (ec *executionContext) _Mutation_setNodeData(ctx context.Context, field graphql.CollectedField) graphql.Marshaler {
 	ctx = ec.Tracer.StartFieldExecution(ctx, field)
 	defer func() { ec.Tracer.EndFieldExecution(ctx) }()
 	rctx := &graphql.ResolverContext{
-		Object:   "__EnumValue",
+		Object:   "Mutation",
 		Field:    field,
 		Args:     nil,
 		IsMethod: true,
 	}
 	ctx = graphql.WithResolverContext(ctx, rctx)
+	rawArgs := field.ArgumentMap(ec.Variables)
+	args, err := ec.field_Mutation_setNodeData_args(ctx, rawArgs)
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	rctx.Args = args
 	ctx = ec.Tracer.StartFieldResolverExecution(ctx, rctx)
-	resTmp := ec.FieldMiddleware(ctx, obj, func(rctx context.Context) (interface{}, error) {
+	resTmp := ec.FieldMiddleware(ctx, nil, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.DeprecationReason(), nil
+		return ec.resolvers.Mutation().SetNodeData(rctx, args["input"].(SetNodeDataInput))
 	})
 	if resTmp == nil {
+		if !ec.HasError(rctx) {
+			ec.Errorf(ctx, "must not be null")
+		}
 		return graphql.Null
 	}
-	res := resTmp.(*string)
+	res := resTmp.(*SetNodeDataOutput)
 	rctx.Result = res
 	ctx = ec.Tracer.StartFieldChildExecution(ctx)
-	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
+	return ec.marshalNSetNodeDataOutput2ᚖgithubᚗcomᚋaᚑhᚋpregelᚋgraphᚐSetNodeDataOutput(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) ___Field_name(ctx context.Context, field graphql.CollectedField, obj *introspection.Field) graphql.Marshaler {
+func (ec *executionContext) _Mutation_setEdgeData(ctx context.Context, field graphql.CollectedField) graphql.Marshaler {
 	ctx = ec.Tracer.StartFieldExecution(ctx, field)
 	defer func() { ec.Tracer.EndFieldExecution(ctx) }()
 	rctx := &graphql.ResolverContext{
-		Object:   "__Field",
+		Object:   "Mutation",
 		Field:    field,
 		Args:     nil,
-		IsMethod: false,
+		IsMethod: true,
 	}
 	ctx = graphql.WithResolverContext(ctx, rctx)
+	rawArgs := field.ArgumentMap(ec.Variables)
+	args, err := ec.field_Mutation_setEdgeData_args(ctx, rawArgs)
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	rctx.Args = args
 	ctx = ec.Tracer.StartFieldResolverExecution(ctx, rctx)
-	resTmp := ec.FieldMiddleware(ctx, obj, func(rctx context.Context) (interface{}, error) {
+	resTmp := ec.FieldMiddleware(ctx, nil, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Name, nil
+		return ec.resolvers.Mutation().SetEdgeData(rctx, args["input"].(SetEdgeDataInput))
 	})
 	if resTmp == nil {
 		if !ec.HasError(rctx) {
@@ -1999,50 +2941,67 @@ func (ec *executionContext) ___Field_name(ctx context.Context, field graphql.Col
 		}
 		return graphql.Null
 	}
-	res := resTmp.(string)
+	res := resTmp.(*SetEdgeDataOutput)
 	rctx.Result = res
 	ctx = ec.Tracer.StartFieldChildExecution(ctx)
-	return ec.marshalNString2string(ctx, field.Selections, res)
+	return ec.marshalNSetEdgeDataOutput2ᚖgithubᚗcomᚋaᚑhᚋpregelᚋgraphᚐSetEdgeDataOutput(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) ___Field_description(ctx context.Context, field graphql.CollectedField, obj *introspection.Field) graphql.Marshaler {
+func (ec *executionContext) _Mutation_removeNodeData(ctx context.Context, field graphql.CollectedField) graphql.Marshaler {
 	ctx = ec.Tracer.StartFieldExecution(ctx, field)
 	defer func() { ec.Tracer.EndFieldExecution(ctx) }()
 	rctx := &graphql.ResolverContext{
-		Object:   "__Field",
+		Object:   "Mutation",
 		Field:    field,
 		Args:     nil,
-		IsMethod: false,
+		IsMethod: true,
 	}
 	ctx = graphql.WithResolverContext(ctx, rctx)
+	rawArgs := field.ArgumentMap(ec.Variables)
+	args, err := ec.field_Mutation_removeNodeData_args(ctx, rawArgs)
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	rctx.Args = args
 	ctx = ec.Tracer.StartFieldResolverExecution(ctx, rctx)
-	resTmp := ec.FieldMiddleware(ctx, obj, func(rctx context.Context) (interface{}, error) {
+	resTmp := ec.FieldMiddleware(ctx, nil, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Description, nil
+		return ec.resolvers.Mutation().RemoveNodeData(rctx, args["input"].(RemoveNodeDataInput))
 	})
 	if resTmp == nil {
+		if !ec.HasError(rctx) {
+			ec.Errorf(ctx, "must not be null")
+		}
 		return graphql.Null
 	}
-	res := resTmp.(string)
+	res := resTmp.(*RemoveNodeDataOutput)
 	rctx.Result = res
 	ctx = ec.Tracer.StartFieldChildExecution(ctx)
-	return ec.marshalOString2string(ctx, field.Selections, res)
+	return ec.marshalNRemoveNodeDataOutput2ᚖgithubᚗcomᚋaᚑhᚋpregelᚋgraphᚐRemoveNodeDataOutput(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) ___Field_args(ctx context.Context, field graphql.CollectedField, obj *introspection.Field) graphql.Marshaler {
+func (ec *executionContext) _Mutation_removeEdgeData(ctx context.Context, field graphql.CollectedField) graphql.Marshaler {
 	ctx = ec.Tracer.StartFieldExecution(ctx, field)
 	defer func() { ec.Tracer.EndFieldExecution(ctx) }()
 	rctx := &graphql.ResolverContext{
-		Object:   "__Field",
+		Object:   "Mutation",
 		Field:    field,
 		Args:     nil,
-		IsMethod: false,
+		IsMethod: true,
 	}
 	ctx = graphql.WithResolverContext(ctx, rctx)
+	rawArgs := field.ArgumentMap(ec.Variables)
+	args, err := ec.field_Mutation_removeEdgeData_args(ctx, rawArgs)
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	rctx.Args = args
 	ctx = ec.Tracer.StartFieldResolverExecution(ctx, rctx)
-	resTmp := ec.FieldMiddleware(ctx, obj, func(rctx context.Context) (interface{}, error) {
+	resTmp := ec.FieldMiddleware(ctx, nil, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Args, nil
+		return ec.resolvers.Mutation().RemoveEdgeData(rctx, args["input"].(RemoveEdgeDataInput))
 	})
 	if resTmp == nil {
 		if !ec.HasError(rctx) {
@@ -2050,26 +3009,33 @@ func (ec *executionContext) ___Field_args(ctx context.Context, field graphql.Col
 		}
 		return graphql.Null
 	}
-	res := resTmp.([]introspection.InputValue)
+	res := resTmp.(*RemoveEdgeDataOutput)
 	rctx.Result = res
 	ctx = ec.Tracer.StartFieldChildExecution(ctx)
-	return ec.marshalN__InputValue2ᚕgithubᚗcomᚋ99designsᚋgqlgenᚋgraphqlᚋintrospectionᚐInputValue(ctx, field.Selections, res)
+	return ec.marshalNRemoveEdgeDataOutput2ᚖgithubᚗcomᚋaᚑhᚋpregelᚋgraphᚐRemoveEdgeDataOutput(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) ___Field_type(ctx context.Context, field graphql.CollectedField, obj *introspection.Field) graphql.Marshaler {
+func (ec *executionContext) _Mutation_importGraph(ctx context.Context, field graphql.CollectedField) graphql.Marshaler {
 	ctx = ec.Tracer.StartFieldExecution(ctx, field)
 	defer func() { ec.Tracer.EndFieldExecution(ctx) }()
 	rctx := &graphql.ResolverContext{
-		Object:   "__Field",
+		Object:   "Mutation",
 		Field:    field,
 		Args:     nil,
-		IsMethod: false,
+		IsMethod: true,
 	}
 	ctx = graphql.WithResolverContext(ctx, rctx)
+	rawArgs := field.ArgumentMap(ec.Variables)
+	args, err := ec.field_Mutation_importGraph_args(ctx, rawArgs)
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	rctx.Args = args
 	ctx = ec.Tracer.StartFieldResolverExecution(ctx, rctx)
-	resTmp := ec.FieldMiddleware(ctx, obj, func(rctx context.Context) (interface{}, error) {
+	resTmp := ec.FieldMiddleware(ctx, nil, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Type, nil
+		return ec.resolvers.Mutation().ImportGraph(rctx, args["file"].(graphql.Upload))
 	})
 	if resTmp == nil {
 		if !ec.HasError(rctx) {
@@ -2077,68 +3043,68 @@ func (ec *executionContext) ___Field_type(ctx context.Context, field graphql.Col
 		}
 		return graphql.Null
 	}
-	res := resTmp.(*introspection.Type)
+	res := resTmp.(*ImportGraphOutput)
 	rctx.Result = res
 	ctx = ec.Tracer.StartFieldChildExecution(ctx)
-	return ec.marshalN__Type2ᚖgithubᚗcomᚋ99designsᚋgqlgenᚋgraphqlᚋintrospectionᚐType(ctx, field.Selections, res)
+	return ec.marshalNImportGraphOutput2ᚖgithubᚗcomᚋaᚑhᚋpregelᚋgraphᚐImportGraphOutput(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) ___Field_isDeprecated(ctx context.Context, field graphql.CollectedField, obj *introspection.Field) graphql.Marshaler {
+func (ec *executionContext) _PageInfo_endCursor(ctx context.Context, field graphql.CollectedField, obj *PageInfo) graphql.Marshaler {
 	ctx = ec.Tracer.StartFieldExecution(ctx, field)
 	defer func() { ec.Tracer.EndFieldExecution(ctx) }()
 	rctx := &graphql.ResolverContext{
-		Object:   "__Field",
+		Object:   "PageInfo",
 		Field:    field,
 		Args:     nil,
-		IsMethod: true,
+		IsMethod: false,
 	}
 	ctx = graphql.WithResolverContext(ctx, rctx)
 	ctx = ec.Tracer.StartFieldResolverExecution(ctx, rctx)
 	resTmp := ec.FieldMiddleware(ctx, obj, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.IsDeprecated(), nil
+		return obj.EndCursor, nil
 	})
 	if resTmp == nil {
-		if !ec.HasError(rctx) {
-			ec.Errorf(ctx, "must not be null")
-		}
 		return graphql.Null
 	}
-	res := resTmp.(bool)
+	res := resTmp.(*string)
 	rctx.Result = res
 	ctx = ec.Tracer.StartFieldChildExecution(ctx)
-	return ec.marshalNBoolean2bool(ctx, field.Selections, res)
+	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) ___Field_deprecationReason(ctx context.Context, field graphql.CollectedField, obj *introspection.Field) graphql.Marshaler {
+func (ec *executionContext) _PageInfo_hasNextPage(ctx context.Context, field graphql.CollectedField, obj *PageInfo) graphql.Marshaler {
 	ctx = ec.Tracer.StartFieldExecution(ctx, field)
 	defer func() { ec.Tracer.EndFieldExecution(ctx) }()
 	rctx := &graphql.ResolverContext{
-		Object:   "__Field",
+		Object:   "PageInfo",
 		Field:    field,
 		Args:     nil,
-		IsMethod: true,
+		IsMethod: false,
 	}
 	ctx = graphql.WithResolverContext(ctx, rctx)
 	ctx = ec.Tracer.StartFieldResolverExecution(ctx, rctx)
 	resTmp := ec.FieldMiddleware(ctx, obj, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.DeprecationReason(), nil
+		return obj.HasNextPage, nil
 	})
 	if resTmp == nil {
+		if !ec.HasError(rctx) {
+			ec.Errorf(ctx, "must not be null")
+		}
 		return graphql.Null
 	}
-	res := resTmp.(*string)
+	res := resTmp.(bool)
 	rctx.Result = res
 	ctx = ec.Tracer.StartFieldChildExecution(ctx)
-	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
+	return ec.marshalNBoolean2bool(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) ___InputValue_name(ctx context.Context, field graphql.CollectedField, obj *introspection.InputValue) graphql.Marshaler {
+func (ec *executionContext) _PageInfo_hasPreviousPage(ctx context.Context, field graphql.CollectedField, obj *PageInfo) graphql.Marshaler {
 	ctx = ec.Tracer.StartFieldExecution(ctx, field)
 	defer func() { ec.Tracer.EndFieldExecution(ctx) }()
 	rctx := &graphql.ResolverContext{
-		Object:   "__InputValue",
+		Object:   "PageInfo",
 		Field:    field,
 		Args:     nil,
 		IsMethod: false,
@@ -2147,7 +3113,7 @@ func (ec *executionContext) ___InputValue_name(ctx context.Context, field graphq
 	ctx = ec.Tracer.StartFieldResolverExecution(ctx, rctx)
 	resTmp := ec.FieldMiddleware(ctx, obj, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Name, nil
+		return obj.HasPreviousPage, nil
 	})
 	if resTmp == nil {
 		if !ec.HasError(rctx) {
@@ -2155,17 +3121,17 @@ func (ec *executionContext) ___InputValue_name(ctx context.Context, field graphq
 		}
 		return graphql.Null
 	}
-	res := resTmp.(string)
+	res := resTmp.(bool)
 	rctx.Result = res
 	ctx = ec.Tracer.StartFieldChildExecution(ctx)
-	return ec.marshalNString2string(ctx, field.Selections, res)
+	return ec.marshalNBoolean2bool(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) ___InputValue_description(ctx context.Context, field graphql.CollectedField, obj *introspection.InputValue) graphql.Marshaler {
+func (ec *executionContext) _PageInfo_startCursor(ctx context.Context, field graphql.CollectedField, obj *PageInfo) graphql.Marshaler {
 	ctx = ec.Tracer.StartFieldExecution(ctx, field)
 	defer func() { ec.Tracer.EndFieldExecution(ctx) }()
 	rctx := &graphql.ResolverContext{
-		Object:   "__InputValue",
+		Object:   "PageInfo",
 		Field:    field,
 		Args:     nil,
 		IsMethod: false,
@@ -2174,22 +3140,22 @@ func (ec *executionContext) ___InputValue_description(ctx context.Context, field
 	ctx = ec.Tracer.StartFieldResolverExecution(ctx, rctx)
 	resTmp := ec.FieldMiddleware(ctx, obj, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Description, nil
+		return obj.StartCursor, nil
 	})
 	if resTmp == nil {
 		return graphql.Null
 	}
-	res := resTmp.(string)
+	res := resTmp.(*string)
 	rctx.Result = res
 	ctx = ec.Tracer.StartFieldChildExecution(ctx)
-	return ec.marshalOString2string(ctx, field.Selections, res)
+	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) ___InputValue_type(ctx context.Context, field graphql.CollectedField, obj *introspection.InputValue) graphql.Marshaler {
+func (ec *executionContext) _Path_nodes(ctx context.Context, field graphql.CollectedField, obj *Path) graphql.Marshaler {
 	ctx = ec.Tracer.StartFieldExecution(ctx, field)
 	defer func() { ec.Tracer.EndFieldExecution(ctx) }()
 	rctx := &graphql.ResolverContext{
-		Object:   "__InputValue",
+		Object:   "Path",
 		Field:    field,
 		Args:     nil,
 		IsMethod: false,
@@ -2198,7 +3164,7 @@ func (ec *executionContext) ___InputValue_type(ctx context.Context, field graphq
 	ctx = ec.Tracer.StartFieldResolverExecution(ctx, rctx)
 	resTmp := ec.FieldMiddleware(ctx, obj, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Type, nil
+		return obj.Nodes, nil
 	})
 	if resTmp == nil {
 		if !ec.HasError(rctx) {
@@ -2206,17 +3172,17 @@ func (ec *executionContext) ___InputValue_type(ctx context.Context, field graphq
 		}
 		return graphql.Null
 	}
-	res := resTmp.(*introspection.Type)
+	res := resTmp.([]*pregel.Node)
 	rctx.Result = res
 	ctx = ec.Tracer.StartFieldChildExecution(ctx)
-	return ec.marshalN__Type2ᚖgithubᚗcomᚋ99designsᚋgqlgenᚋgraphqlᚋintrospectionᚐType(ctx, field.Selections, res)
+	return ec.marshalNGraphNode2ᚕᚖgithubᚗcomᚋaᚑhᚋpregelᚐNode(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) ___InputValue_defaultValue(ctx context.Context, field graphql.CollectedField, obj *introspection.InputValue) graphql.Marshaler {
+func (ec *executionContext) _Path_edges(ctx context.Context, field graphql.CollectedField, obj *Path) graphql.Marshaler {
 	ctx = ec.Tracer.StartFieldExecution(ctx, field)
 	defer func() { ec.Tracer.EndFieldExecution(ctx) }()
 	rctx := &graphql.ResolverContext{
-		Object:   "__InputValue",
+		Object:   "Path",
 		Field:    field,
 		Args:     nil,
 		IsMethod: false,
@@ -2225,109 +3191,196 @@ func (ec *executionContext) ___InputValue_defaultValue(ctx context.Context, fiel
 	ctx = ec.Tracer.StartFieldResolverExecution(ctx, rctx)
 	resTmp := ec.FieldMiddleware(ctx, obj, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.DefaultValue, nil
+		return obj.Edges, nil
 	})
 	if resTmp == nil {
+		if !ec.HasError(rctx) {
+			ec.Errorf(ctx, "must not be null")
+		}
 		return graphql.Null
 	}
-	res := resTmp.(*string)
+	res := resTmp.([]Edge)
 	rctx.Result = res
 	ctx = ec.Tracer.StartFieldChildExecution(ctx)
-	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
+	return ec.marshalNEdge2ᚕgithubᚗcomᚋaᚑhᚋpregelᚋgraphᚐEdge(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) ___Schema_types(ctx context.Context, field graphql.CollectedField, obj *introspection.Schema) graphql.Marshaler {
+func (ec *executionContext) _Query_get(ctx context.Context, field graphql.CollectedField) graphql.Marshaler {
 	ctx = ec.Tracer.StartFieldExecution(ctx, field)
 	defer func() { ec.Tracer.EndFieldExecution(ctx) }()
 	rctx := &graphql.ResolverContext{
-		Object:   "__Schema",
+		Object:   "Query",
 		Field:    field,
 		Args:     nil,
 		IsMethod: true,
 	}
 	ctx = graphql.WithResolverContext(ctx, rctx)
-	ctx = ec.Tracer.StartFieldResolverExecution(ctx, rctx)
-	resTmp := ec.FieldMiddleware(ctx, obj, func(rctx context.Context) (interface{}, error) {
+	rawArgs := field.ArgumentMap(ec.Variables)
+	args, err := ec.field_Query_get_args(ctx, rawArgs)
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	rctx.Args = args
+	ctx = ec.Tracer.StartFieldResolverExecution(ctx, rctx)
+	resTmp := ec.FieldMiddleware(ctx, nil, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Types(), nil
+		return ec.resolvers.Query().Get(rctx, args["id"].(string))
 	})
 	if resTmp == nil {
-		if !ec.HasError(rctx) {
-			ec.Errorf(ctx, "must not be null")
-		}
 		return graphql.Null
 	}
-	res := resTmp.([]introspection.Type)
+	res := resTmp.(*pregel.Node)
 	rctx.Result = res
 	ctx = ec.Tracer.StartFieldChildExecution(ctx)
-	return ec.marshalN__Type2ᚕgithubᚗcomᚋ99designsᚋgqlgenᚋgraphqlᚋintrospectionᚐType(ctx, field.Selections, res)
+	return ec.marshalOGraphNode2ᚖgithubᚗcomᚋaᚑhᚋpregelᚐNode(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) ___Schema_queryType(ctx context.Context, field graphql.CollectedField, obj *introspection.Schema) graphql.Marshaler {
+func (ec *executionContext) _Query_node(ctx context.Context, field graphql.CollectedField) graphql.Marshaler {
 	ctx = ec.Tracer.StartFieldExecution(ctx, field)
 	defer func() { ec.Tracer.EndFieldExecution(ctx) }()
 	rctx := &graphql.ResolverContext{
-		Object:   "__Schema",
+		Object:   "Query",
 		Field:    field,
 		Args:     nil,
 		IsMethod: true,
 	}
 	ctx = graphql.WithResolverContext(ctx, rctx)
+	rawArgs := field.ArgumentMap(ec.Variables)
+	args, err := ec.field_Query_node_args(ctx, rawArgs)
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	rctx.Args = args
 	ctx = ec.Tracer.StartFieldResolverExecution(ctx, rctx)
-	resTmp := ec.FieldMiddleware(ctx, obj, func(rctx context.Context) (interface{}, error) {
+	resTmp := ec.FieldMiddleware(ctx, nil, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.QueryType(), nil
+		return ec.resolvers.Query().Node(rctx, args["id"].(string))
 	})
 	if resTmp == nil {
-		if !ec.HasError(rctx) {
-			ec.Errorf(ctx, "must not be null")
-		}
 		return graphql.Null
 	}
-	res := resTmp.(*introspection.Type)
+	res := resTmp.(RelayNode)
 	rctx.Result = res
 	ctx = ec.Tracer.StartFieldChildExecution(ctx)
-	return ec.marshalN__Type2ᚖgithubᚗcomᚋ99designsᚋgqlgenᚋgraphqlᚋintrospectionᚐType(ctx, field.Selections, res)
+	return ec.marshalONode2githubᚗcomᚋaᚑhᚋpregelᚋgraphᚐRelayNode(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) ___Schema_mutationType(ctx context.Context, field graphql.CollectedField, obj *introspection.Schema) graphql.Marshaler {
+func (ec *executionContext) _Query_nodes(ctx context.Context, field graphql.CollectedField) graphql.Marshaler {
 	ctx = ec.Tracer.StartFieldExecution(ctx, field)
 	defer func() { ec.Tracer.EndFieldExecution(ctx) }()
 	rctx := &graphql.ResolverContext{
-		Object:   "__Schema",
+		Object:   "Query",
 		Field:    field,
 		Args:     nil,
 		IsMethod: true,
 	}
 	ctx = graphql.WithResolverContext(ctx, rctx)
+	rawArgs := field.ArgumentMap(ec.Variables)
+	args, err := ec.field_Query_nodes_args(ctx, rawArgs)
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	rctx.Args = args
 	ctx = ec.Tracer.StartFieldResolverExecution(ctx, rctx)
-	resTmp := ec.FieldMiddleware(ctx, obj, func(rctx context.Context) (interface{}, error) {
+	resTmp := ec.FieldMiddleware(ctx, nil, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.MutationType(), nil
+		return ec.resolvers.Query().Nodes(rctx, args["filter"].(*NodeFilter), args["first"].(int), args["after"].(*string))
 	})
 	if resTmp == nil {
 		return graphql.Null
 	}
-	res := resTmp.(*introspection.Type)
+	res := resTmp.(*Connection)
 	rctx.Result = res
 	ctx = ec.Tracer.StartFieldChildExecution(ctx)
-	return ec.marshalO__Type2ᚖgithubᚗcomᚋ99designsᚋgqlgenᚋgraphqlᚋintrospectionᚐType(ctx, field.Selections, res)
+	return ec.marshalOConnection2ᚖgithubᚗcomᚋaᚑhᚋpregelᚋgraphᚐConnection(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) ___Schema_subscriptionType(ctx context.Context, field graphql.CollectedField, obj *introspection.Schema) graphql.Marshaler {
+func (ec *executionContext) _Query_path(ctx context.Context, field graphql.CollectedField) graphql.Marshaler {
 	ctx = ec.Tracer.StartFieldExecution(ctx, field)
 	defer func() { ec.Tracer.EndFieldExecution(ctx) }()
 	rctx := &graphql.ResolverContext{
-		Object:   "__Schema",
+		Object:   "Query",
 		Field:    field,
 		Args:     nil,
 		IsMethod: true,
 	}
 	ctx = graphql.WithResolverContext(ctx, rctx)
+	rawArgs := field.ArgumentMap(ec.Variables)
+	args, err := ec.field_Query_path_args(ctx, rawArgs)
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	rctx.Args = args
 	ctx = ec.Tracer.StartFieldResolverExecution(ctx, rctx)
-	resTmp := ec.FieldMiddleware(ctx, obj, func(rctx context.Context) (interface{}, error) {
+	resTmp := ec.FieldMiddleware(ctx, nil, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.SubscriptionType(), nil
+		return ec.resolvers.Query().Path(rctx, args["from"].(string), args["to"].(string), args["maxDepth"].(*int))
+	})
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(*Path)
+	rctx.Result = res
+	ctx = ec.Tracer.StartFieldChildExecution(ctx)
+	return ec.marshalOPath2ᚖgithubᚗcomᚋaᚑhᚋpregelᚋgraphᚐPath(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) _Query_neighborhood(ctx context.Context, field graphql.CollectedField) graphql.Marshaler {
+	ctx = ec.Tracer.StartFieldExecution(ctx, field)
+	defer func() { ec.Tracer.EndFieldExecution(ctx) }()
+	rctx := &graphql.ResolverContext{
+		Object:   "Query",
+		Field:    field,
+		Args:     nil,
+		IsMethod: true,
+	}
+	ctx = graphql.WithResolverContext(ctx, rctx)
+	rawArgs := field.ArgumentMap(ec.Variables)
+	args, err := ec.field_Query_neighborhood_args(ctx, rawArgs)
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	rctx.Args = args
+	ctx = ec.Tracer.StartFieldResolverExecution(ctx, rctx)
+	resTmp := ec.FieldMiddleware(ctx, nil, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return ec.resolvers.Query().Neighborhood(rctx, args["id"].(string), args["depth"].(int), args["direction"].(*Direction))
+	})
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(*Subgraph)
+	rctx.Result = res
+	ctx = ec.Tracer.StartFieldChildExecution(ctx)
+	return ec.marshalOSubgraph2ᚖgithubᚗcomᚋaᚑhᚋpregelᚋgraphᚐSubgraph(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) _Query___type(ctx context.Context, field graphql.CollectedField) graphql.Marshaler {
+	ctx = ec.Tracer.StartFieldExecution(ctx, field)
+	defer func() { ec.Tracer.EndFieldExecution(ctx) }()
+	rctx := &graphql.ResolverContext{
+		Object:   "Query",
+		Field:    field,
+		Args:     nil,
+		IsMethod: true,
+	}
+	ctx = graphql.WithResolverContext(ctx, rctx)
+	rawArgs := field.ArgumentMap(ec.Variables)
+	args, err := ec.field_Query___type_args(ctx, rawArgs)
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	rctx.Args = args
+	ctx = ec.Tracer.StartFieldResolverExecution(ctx, rctx)
+	resTmp := ec.FieldMiddleware(ctx, nil, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return ec.introspectType(args["name"].(string))
 	})
 	if resTmp == nil {
 		return graphql.Null
@@ -2338,47 +3391,44 @@ func (ec *executionContext) ___Schema_subscriptionType(ctx context.Context, fiel
 	return ec.marshalO__Type2ᚖgithubᚗcomᚋ99designsᚋgqlgenᚋgraphqlᚋintrospectionᚐType(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) ___Schema_directives(ctx context.Context, field graphql.CollectedField, obj *introspection.Schema) graphql.Marshaler {
+func (ec *executionContext) _Query___schema(ctx context.Context, field graphql.CollectedField) graphql.Marshaler {
 	ctx = ec.Tracer.StartFieldExecution(ctx, field)
 	defer func() { ec.Tracer.EndFieldExecution(ctx) }()
 	rctx := &graphql.ResolverContext{
-		Object:   "__Schema",
+		Object:   "Query",
 		Field:    field,
 		Args:     nil,
 		IsMethod: true,
 	}
 	ctx = graphql.WithResolverContext(ctx, rctx)
 	ctx = ec.Tracer.StartFieldResolverExecution(ctx, rctx)
-	resTmp := ec.FieldMiddleware(ctx, obj, func(rctx context.Context) (interface{}, error) {
+	resTmp := ec.FieldMiddleware(ctx, nil, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Directives(), nil
+		return ec.introspectSchema()
 	})
 	if resTmp == nil {
-		if !ec.HasError(rctx) {
-			ec.Errorf(ctx, "must not be null")
-		}
 		return graphql.Null
 	}
-	res := resTmp.([]introspection.Directive)
+	res := resTmp.(*introspection.Schema)
 	rctx.Result = res
 	ctx = ec.Tracer.StartFieldChildExecution(ctx)
-	return ec.marshalN__Directive2ᚕgithubᚗcomᚋ99designsᚋgqlgenᚋgraphqlᚋintrospectionᚐDirective(ctx, field.Selections, res)
+	return ec.marshalO__Schema2ᚖgithubᚗcomᚋ99designsᚋgqlgenᚋgraphqlᚋintrospectionᚐSchema(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) ___Type_kind(ctx context.Context, field graphql.CollectedField, obj *introspection.Type) graphql.Marshaler {
+func (ec *executionContext) _RemoveEdgeDataOutput_removed(ctx context.Context, field graphql.CollectedField, obj *RemoveEdgeDataOutput) graphql.Marshaler {
 	ctx = ec.Tracer.StartFieldExecution(ctx, field)
 	defer func() { ec.Tracer.EndFieldExecution(ctx) }()
 	rctx := &graphql.ResolverContext{
-		Object:   "__Type",
+		Object:   "RemoveEdgeDataOutput",
 		Field:    field,
 		Args:     nil,
-		IsMethod: true,
+		IsMethod: false,
 	}
 	ctx = graphql.WithResolverContext(ctx, rctx)
 	ctx = ec.Tracer.StartFieldResolverExecution(ctx, rctx)
 	resTmp := ec.FieldMiddleware(ctx, obj, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Kind(), nil
+		return obj.Removed, nil
 	})
 	if resTmp == nil {
 		if !ec.HasError(rctx) {
@@ -2386,175 +3436,176 @@ func (ec *executionContext) ___Type_kind(ctx context.Context, field graphql.Coll
 		}
 		return graphql.Null
 	}
-	res := resTmp.(string)
+	res := resTmp.(bool)
 	rctx.Result = res
 	ctx = ec.Tracer.StartFieldChildExecution(ctx)
-	return ec.marshalN__TypeKind2string(ctx, field.Selections, res)
+	return ec.marshalNBoolean2bool(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) ___Type_name(ctx context.Context, field graphql.CollectedField, obj *introspection.Type) graphql.Marshaler {
+func (ec *executionContext) _RemoveEdgeOutput_removed(ctx context.Context, field graphql.CollectedField, obj *RemoveEdgeOutput) graphql.Marshaler {
 	ctx = ec.Tracer.StartFieldExecution(ctx, field)
 	defer func() { ec.Tracer.EndFieldExecution(ctx) }()
 	rctx := &graphql.ResolverContext{
-		Object:   "__Type",
+		Object:   "RemoveEdgeOutput",
 		Field:    field,
 		Args:     nil,
-		IsMethod: true,
+		IsMethod: false,
 	}
 	ctx = graphql.WithResolverContext(ctx, rctx)
 	ctx = ec.Tracer.StartFieldResolverExecution(ctx, rctx)
 	resTmp := ec.FieldMiddleware(ctx, obj, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Name(), nil
+		return obj.Removed, nil
 	})
 	if resTmp == nil {
+		if !ec.HasError(rctx) {
+			ec.Errorf(ctx, "must not be null")
+		}
 		return graphql.Null
 	}
-	res := resTmp.(*string)
+	res := resTmp.(bool)
 	rctx.Result = res
 	ctx = ec.Tracer.StartFieldChildExecution(ctx)
-	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
+	return ec.marshalNBoolean2bool(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) ___Type_description(ctx context.Context, field graphql.CollectedField, obj *introspection.Type) graphql.Marshaler {
+func (ec *executionContext) _RemoveNodeDataOutput_removed(ctx context.Context, field graphql.CollectedField, obj *RemoveNodeDataOutput) graphql.Marshaler {
 	ctx = ec.Tracer.StartFieldExecution(ctx, field)
 	defer func() { ec.Tracer.EndFieldExecution(ctx) }()
 	rctx := &graphql.ResolverContext{
-		Object:   "__Type",
+		Object:   "RemoveNodeDataOutput",
 		Field:    field,
 		Args:     nil,
-		IsMethod: true,
+		IsMethod: false,
 	}
 	ctx = graphql.WithResolverContext(ctx, rctx)
 	ctx = ec.Tracer.StartFieldResolverExecution(ctx, rctx)
 	resTmp := ec.FieldMiddleware(ctx, obj, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Description(), nil
+		return obj.Removed, nil
 	})
 	if resTmp == nil {
+		if !ec.HasError(rctx) {
+			ec.Errorf(ctx, "must not be null")
+		}
 		return graphql.Null
 	}
-	res := resTmp.(string)
+	res := resTmp.(bool)
 	rctx.Result = res
 	ctx = ec.Tracer.StartFieldChildExecution(ctx)
-	return ec.marshalOString2string(ctx, field.Selections, res)
+	return ec.marshalNBoolean2bool(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) ___Type_fields(ctx context.Context, field graphql.CollectedField, obj *introspection.Type) graphql.Marshaler {
+func (ec *executionContext) _RemoveNodeOutput_removed(ctx context.Context, field graphql.CollectedField, obj *RemoveNodeOutput) graphql.Marshaler {
 	ctx = ec.Tracer.StartFieldExecution(ctx, field)
 	defer func() { ec.Tracer.EndFieldExecution(ctx) }()
 	rctx := &graphql.ResolverContext{
-		Object:   "__Type",
+		Object:   "RemoveNodeOutput",
 		Field:    field,
 		Args:     nil,
-		IsMethod: true,
+		IsMethod: false,
 	}
 	ctx = graphql.WithResolverContext(ctx, rctx)
-	rawArgs := field.ArgumentMap(ec.Variables)
-	args, err := ec.field___Type_fields_args(ctx, rawArgs)
-	if err != nil {
-		ec.Error(ctx, err)
-		return graphql.Null
-	}
-	rctx.Args = args
 	ctx = ec.Tracer.StartFieldResolverExecution(ctx, rctx)
 	resTmp := ec.FieldMiddleware(ctx, obj, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Fields(args["includeDeprecated"].(bool)), nil
+		return obj.Removed, nil
 	})
 	if resTmp == nil {
+		if !ec.HasError(rctx) {
+			ec.Errorf(ctx, "must not be null")
+		}
 		return graphql.Null
 	}
-	res := resTmp.([]introspection.Field)
+	res := resTmp.(bool)
 	rctx.Result = res
 	ctx = ec.Tracer.StartFieldChildExecution(ctx)
-	return ec.marshalO__Field2ᚕgithubᚗcomᚋ99designsᚋgqlgenᚋgraphqlᚋintrospectionᚐField(ctx, field.Selections, res)
+	return ec.marshalNBoolean2bool(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) ___Type_interfaces(ctx context.Context, field graphql.CollectedField, obj *introspection.Type) graphql.Marshaler {
+func (ec *executionContext) _SaveEdgeOutput_parent(ctx context.Context, field graphql.CollectedField, obj *SaveEdgeOutput) graphql.Marshaler {
 	ctx = ec.Tracer.StartFieldExecution(ctx, field)
 	defer func() { ec.Tracer.EndFieldExecution(ctx) }()
 	rctx := &graphql.ResolverContext{
-		Object:   "__Type",
+		Object:   "SaveEdgeOutput",
 		Field:    field,
 		Args:     nil,
-		IsMethod: true,
+		IsMethod: false,
 	}
 	ctx = graphql.WithResolverContext(ctx, rctx)
 	ctx = ec.Tracer.StartFieldResolverExecution(ctx, rctx)
 	resTmp := ec.FieldMiddleware(ctx, obj, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.Interfaces(), nil
+		return obj.Parent, nil
 	})
 	if resTmp == nil {
+		if !ec.HasError(rctx) {
+			ec.Errorf(ctx, "must not be null")
+		}
 		return graphql.Null
 	}
-	res := resTmp.([]introspection.Type)
+	res := resTmp.(string)
 	rctx.Result = res
 	ctx = ec.Tracer.StartFieldChildExecution(ctx)
-	return ec.marshalO__Type2ᚕgithubᚗcomᚋ99designsᚋgqlgenᚋgraphqlᚋintrospectionᚐType(ctx, field.Selections, res)
+	return ec.marshalNID2string(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) ___Type_possibleTypes(ctx context.Context, field graphql.CollectedField, obj *introspection.Type) graphql.Marshaler {
+func (ec *executionContext) _SaveEdgeOutput_child(ctx context.Context, field graphql.CollectedField, obj *SaveEdgeOutput) graphql.Marshaler {
 	ctx = ec.Tracer.StartFieldExecution(ctx, field)
 	defer func() { ec.Tracer.EndFieldExecution(ctx) }()
 	rctx := &graphql.ResolverContext{
-		Object:   "__Type",
+		Object:   "SaveEdgeOutput",
 		Field:    field,
 		Args:     nil,
-		IsMethod: true,
+		IsMethod: false,
 	}
 	ctx = graphql.WithResolverContext(ctx, rctx)
 	ctx = ec.Tracer.StartFieldResolverExecution(ctx, rctx)
 	resTmp := ec.FieldMiddleware(ctx, obj, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.PossibleTypes(), nil
+		return obj.Child, nil
 	})
 	if resTmp == nil {
+		if !ec.HasError(rctx) {
+			ec.Errorf(ctx, "must not be null")
+		}
 		return graphql.Null
 	}
-	res := resTmp.([]introspection.Type)
+	res := resTmp.(string)
 	rctx.Result = res
 	ctx = ec.Tracer.StartFieldChildExecution(ctx)
-	return ec.marshalO__Type2ᚕgithubᚗcomᚋ99designsᚋgqlgenᚋgraphqlᚋintrospectionᚐType(ctx, field.Selections, res)
+	return ec.marshalNID2string(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) ___Type_enumValues(ctx context.Context, field graphql.CollectedField, obj *introspection.Type) graphql.Marshaler {
+func (ec *executionContext) _SaveEdgeOutput_parentNode(ctx context.Context, field graphql.CollectedField, obj *SaveEdgeOutput) graphql.Marshaler {
 	ctx = ec.Tracer.StartFieldExecution(ctx, field)
 	defer func() { ec.Tracer.EndFieldExecution(ctx) }()
 	rctx := &graphql.ResolverContext{
-		Object:   "__Type",
+		Object:   "SaveEdgeOutput",
 		Field:    field,
 		Args:     nil,
 		IsMethod: true,
 	}
 	ctx = graphql.WithResolverContext(ctx, rctx)
-	rawArgs := field.ArgumentMap(ec.Variables)
-	args, err := ec.field___Type_enumValues_args(ctx, rawArgs)
-	if err != nil {
-		ec.Error(ctx, err)
-		return graphql.Null
-	}
-	rctx.Args = args
 	ctx = ec.Tracer.StartFieldResolverExecution(ctx, rctx)
 	resTmp := ec.FieldMiddleware(ctx, obj, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.EnumValues(args["includeDeprecated"].(bool)), nil
+		return ec.resolvers.SaveEdgeOutput().ParentNode(rctx, obj)
 	})
 	if resTmp == nil {
 		return graphql.Null
 	}
-	res := resTmp.([]introspection.EnumValue)
+	res := resTmp.(*pregel.Node)
 	rctx.Result = res
 	ctx = ec.Tracer.StartFieldChildExecution(ctx)
-	return ec.marshalO__EnumValue2ᚕgithubᚗcomᚋ99designsᚋgqlgenᚋgraphqlᚋintrospectionᚐEnumValue(ctx, field.Selections, res)
+	return ec.marshalOGraphNode2ᚖgithubᚗcomᚋaᚑhᚋpregelᚐNode(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) ___Type_inputFields(ctx context.Context, field graphql.CollectedField, obj *introspection.Type) graphql.Marshaler {
+func (ec *executionContext) _SaveEdgeOutput_childNode(ctx context.Context, field graphql.CollectedField, obj *SaveEdgeOutput) graphql.Marshaler {
 	ctx = ec.Tracer.StartFieldExecution(ctx, field)
 	defer func() { ec.Tracer.EndFieldExecution(ctx) }()
 	rctx := &graphql.ResolverContext{
-		Object:   "__Type",
+		Object:   "SaveEdgeOutput",
 		Field:    field,
 		Args:     nil,
 		IsMethod: true,
@@ -2563,290 +3614,2205 @@ func (ec *executionContext) ___Type_inputFields(ctx context.Context, field graph
 	ctx = ec.Tracer.StartFieldResolverExecution(ctx, rctx)
 	resTmp := ec.FieldMiddleware(ctx, obj, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.InputFields(), nil
+		return ec.resolvers.SaveEdgeOutput().ChildNode(rctx, obj)
 	})
 	if resTmp == nil {
 		return graphql.Null
 	}
-	res := resTmp.([]introspection.InputValue)
+	res := resTmp.(*pregel.Node)
 	rctx.Result = res
 	ctx = ec.Tracer.StartFieldChildExecution(ctx)
-	return ec.marshalO__InputValue2ᚕgithubᚗcomᚋ99designsᚋgqlgenᚋgraphqlᚋintrospectionᚐInputValue(ctx, field.Selections, res)
+	return ec.marshalOGraphNode2ᚖgithubᚗcomᚋaᚑhᚋpregelᚐNode(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) ___Type_ofType(ctx context.Context, field graphql.CollectedField, obj *introspection.Type) graphql.Marshaler {
+func (ec *executionContext) _SaveNodeOutput_id(ctx context.Context, field graphql.CollectedField, obj *SaveNodeOutput) graphql.Marshaler {
 	ctx = ec.Tracer.StartFieldExecution(ctx, field)
 	defer func() { ec.Tracer.EndFieldExecution(ctx) }()
 	rctx := &graphql.ResolverContext{
-		Object:   "__Type",
+		Object:   "SaveNodeOutput",
 		Field:    field,
 		Args:     nil,
-		IsMethod: true,
+		IsMethod: false,
 	}
 	ctx = graphql.WithResolverContext(ctx, rctx)
 	ctx = ec.Tracer.StartFieldResolverExecution(ctx, rctx)
 	resTmp := ec.FieldMiddleware(ctx, obj, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.OfType(), nil
+		return obj.ID, nil
 	})
 	if resTmp == nil {
+		if !ec.HasError(rctx) {
+			ec.Errorf(ctx, "must not be null")
+		}
 		return graphql.Null
 	}
-	res := resTmp.(*introspection.Type)
+	res := resTmp.(string)
+	rctx.Result = res
+	ctx = ec.Tracer.StartFieldChildExecution(ctx)
+	return ec.marshalNID2string(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) _SaveNodeOutput_node(ctx context.Context, field graphql.CollectedField, obj *SaveNodeOutput) graphql.Marshaler {
+	ctx = ec.Tracer.StartFieldExecution(ctx, field)
+	defer func() { ec.Tracer.EndFieldExecution(ctx) }()
+	rctx := &graphql.ResolverContext{
+		Object:   "SaveNodeOutput",
+		Field:    field,
+		Args:     nil,
+		IsMethod: true,
+	}
+	ctx = graphql.WithResolverContext(ctx, rctx)
+	ctx = ec.Tracer.StartFieldResolverExecution(ctx, rctx)
+	resTmp := ec.FieldMiddleware(ctx, obj, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return ec.resolvers.SaveNodeOutput().Node(rctx, obj)
+	})
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(*pregel.Node)
+	rctx.Result = res
+	ctx = ec.Tracer.StartFieldChildExecution(ctx)
+	return ec.marshalOGraphNode2ᚖgithubᚗcomᚋaᚑhᚋpregelᚐNode(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) _SetEdgeDataOutput_set(ctx context.Context, field graphql.CollectedField, obj *SetEdgeDataOutput) graphql.Marshaler {
+	ctx = ec.Tracer.StartFieldExecution(ctx, field)
+	defer func() { ec.Tracer.EndFieldExecution(ctx) }()
+	rctx := &graphql.ResolverContext{
+		Object:   "SetEdgeDataOutput",
+		Field:    field,
+		Args:     nil,
+		IsMethod: false,
+	}
+	ctx = graphql.WithResolverContext(ctx, rctx)
+	ctx = ec.Tracer.StartFieldResolverExecution(ctx, rctx)
+	resTmp := ec.FieldMiddleware(ctx, obj, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Set, nil
+	})
+	if resTmp == nil {
+		if !ec.HasError(rctx) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(bool)
+	rctx.Result = res
+	ctx = ec.Tracer.StartFieldChildExecution(ctx)
+	return ec.marshalNBoolean2bool(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) _SetEdgeFieldsOutput_set(ctx context.Context, field graphql.CollectedField, obj *SetEdgeFieldsOutput) graphql.Marshaler {
+	ctx = ec.Tracer.StartFieldExecution(ctx, field)
+	defer func() { ec.Tracer.EndFieldExecution(ctx) }()
+	rctx := &graphql.ResolverContext{
+		Object:   "SetEdgeFieldsOutput",
+		Field:    field,
+		Args:     nil,
+		IsMethod: false,
+	}
+	ctx = graphql.WithResolverContext(ctx, rctx)
+	ctx = ec.Tracer.StartFieldResolverExecution(ctx, rctx)
+	resTmp := ec.FieldMiddleware(ctx, obj, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Set, nil
+	})
+	if resTmp == nil {
+		if !ec.HasError(rctx) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(bool)
+	rctx.Result = res
+	ctx = ec.Tracer.StartFieldChildExecution(ctx)
+	return ec.marshalNBoolean2bool(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) _SetNodeDataOutput_set(ctx context.Context, field graphql.CollectedField, obj *SetNodeDataOutput) graphql.Marshaler {
+	ctx = ec.Tracer.StartFieldExecution(ctx, field)
+	defer func() { ec.Tracer.EndFieldExecution(ctx) }()
+	rctx := &graphql.ResolverContext{
+		Object:   "SetNodeDataOutput",
+		Field:    field,
+		Args:     nil,
+		IsMethod: false,
+	}
+	ctx = graphql.WithResolverContext(ctx, rctx)
+	ctx = ec.Tracer.StartFieldResolverExecution(ctx, rctx)
+	resTmp := ec.FieldMiddleware(ctx, obj, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Set, nil
+	})
+	if resTmp == nil {
+		if !ec.HasError(rctx) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(bool)
+	rctx.Result = res
+	ctx = ec.Tracer.StartFieldChildExecution(ctx)
+	return ec.marshalNBoolean2bool(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) _SetNodeFieldsOutput_set(ctx context.Context, field graphql.CollectedField, obj *SetNodeFieldsOutput) graphql.Marshaler {
+	ctx = ec.Tracer.StartFieldExecution(ctx, field)
+	defer func() { ec.Tracer.EndFieldExecution(ctx) }()
+	rctx := &graphql.ResolverContext{
+		Object:   "SetNodeFieldsOutput",
+		Field:    field,
+		Args:     nil,
+		IsMethod: false,
+	}
+	ctx = graphql.WithResolverContext(ctx, rctx)
+	ctx = ec.Tracer.StartFieldResolverExecution(ctx, rctx)
+	resTmp := ec.FieldMiddleware(ctx, obj, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Set, nil
+	})
+	if resTmp == nil {
+		if !ec.HasError(rctx) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(bool)
+	rctx.Result = res
+	ctx = ec.Tracer.StartFieldChildExecution(ctx)
+	return ec.marshalNBoolean2bool(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) _SetNodeFieldsOutput_id(ctx context.Context, field graphql.CollectedField, obj *SetNodeFieldsOutput) graphql.Marshaler {
+	ctx = ec.Tracer.StartFieldExecution(ctx, field)
+	defer func() { ec.Tracer.EndFieldExecution(ctx) }()
+	rctx := &graphql.ResolverContext{
+		Object:   "SetNodeFieldsOutput",
+		Field:    field,
+		Args:     nil,
+		IsMethod: false,
+	}
+	ctx = graphql.WithResolverContext(ctx, rctx)
+	ctx = ec.Tracer.StartFieldResolverExecution(ctx, rctx)
+	resTmp := ec.FieldMiddleware(ctx, obj, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.ID, nil
+	})
+	if resTmp == nil {
+		if !ec.HasError(rctx) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(string)
+	rctx.Result = res
+	ctx = ec.Tracer.StartFieldChildExecution(ctx)
+	return ec.marshalNID2string(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) _SetNodeFieldsOutput_node(ctx context.Context, field graphql.CollectedField, obj *SetNodeFieldsOutput) graphql.Marshaler {
+	ctx = ec.Tracer.StartFieldExecution(ctx, field)
+	defer func() { ec.Tracer.EndFieldExecution(ctx) }()
+	rctx := &graphql.ResolverContext{
+		Object:   "SetNodeFieldsOutput",
+		Field:    field,
+		Args:     nil,
+		IsMethod: true,
+	}
+	ctx = graphql.WithResolverContext(ctx, rctx)
+	ctx = ec.Tracer.StartFieldResolverExecution(ctx, rctx)
+	resTmp := ec.FieldMiddleware(ctx, obj, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return ec.resolvers.SetNodeFieldsOutput().Node(rctx, obj)
+	})
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(*pregel.Node)
+	rctx.Result = res
+	ctx = ec.Tracer.StartFieldChildExecution(ctx)
+	return ec.marshalOGraphNode2ᚖgithubᚗcomᚋaᚑhᚋpregelᚐNode(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) _Subgraph_root(ctx context.Context, field graphql.CollectedField, obj *Subgraph) graphql.Marshaler {
+	ctx = ec.Tracer.StartFieldExecution(ctx, field)
+	defer func() { ec.Tracer.EndFieldExecution(ctx) }()
+	rctx := &graphql.ResolverContext{
+		Object:   "Subgraph",
+		Field:    field,
+		Args:     nil,
+		IsMethod: false,
+	}
+	ctx = graphql.WithResolverContext(ctx, rctx)
+	ctx = ec.Tracer.StartFieldResolverExecution(ctx, rctx)
+	resTmp := ec.FieldMiddleware(ctx, obj, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Root, nil
+	})
+	if resTmp == nil {
+		if !ec.HasError(rctx) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(string)
+	rctx.Result = res
+	ctx = ec.Tracer.StartFieldChildExecution(ctx)
+	return ec.marshalNID2string(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) _Subgraph_nodes(ctx context.Context, field graphql.CollectedField, obj *Subgraph) graphql.Marshaler {
+	ctx = ec.Tracer.StartFieldExecution(ctx, field)
+	defer func() { ec.Tracer.EndFieldExecution(ctx) }()
+	rctx := &graphql.ResolverContext{
+		Object:   "Subgraph",
+		Field:    field,
+		Args:     nil,
+		IsMethod: false,
+	}
+	ctx = graphql.WithResolverContext(ctx, rctx)
+	ctx = ec.Tracer.StartFieldResolverExecution(ctx, rctx)
+	resTmp := ec.FieldMiddleware(ctx, obj, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Nodes, nil
+	})
+	if resTmp == nil {
+		if !ec.HasError(rctx) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.([]*pregel.Node)
+	rctx.Result = res
+	ctx = ec.Tracer.StartFieldChildExecution(ctx)
+	return ec.marshalNGraphNode2ᚕᚖgithubᚗcomᚋaᚑhᚋpregelᚐNode(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) _Subgraph_edges(ctx context.Context, field graphql.CollectedField, obj *Subgraph) graphql.Marshaler {
+	ctx = ec.Tracer.StartFieldExecution(ctx, field)
+	defer func() { ec.Tracer.EndFieldExecution(ctx) }()
+	rctx := &graphql.ResolverContext{
+		Object:   "Subgraph",
+		Field:    field,
+		Args:     nil,
+		IsMethod: false,
+	}
+	ctx = graphql.WithResolverContext(ctx, rctx)
+	ctx = ec.Tracer.StartFieldResolverExecution(ctx, rctx)
+	resTmp := ec.FieldMiddleware(ctx, obj, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Edges, nil
+	})
+	if resTmp == nil {
+		if !ec.HasError(rctx) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.([]SubgraphEdge)
+	rctx.Result = res
+	ctx = ec.Tracer.StartFieldChildExecution(ctx)
+	return ec.marshalNSubgraphEdge2ᚕgithubᚗcomᚋaᚑhᚋpregelᚋgraphᚐSubgraphEdge(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) _SubgraphEdge_parent(ctx context.Context, field graphql.CollectedField, obj *SubgraphEdge) graphql.Marshaler {
+	ctx = ec.Tracer.StartFieldExecution(ctx, field)
+	defer func() { ec.Tracer.EndFieldExecution(ctx) }()
+	rctx := &graphql.ResolverContext{
+		Object:   "SubgraphEdge",
+		Field:    field,
+		Args:     nil,
+		IsMethod: false,
+	}
+	ctx = graphql.WithResolverContext(ctx, rctx)
+	ctx = ec.Tracer.StartFieldResolverExecution(ctx, rctx)
+	resTmp := ec.FieldMiddleware(ctx, obj, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Parent, nil
+	})
+	if resTmp == nil {
+		if !ec.HasError(rctx) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(string)
+	rctx.Result = res
+	ctx = ec.Tracer.StartFieldChildExecution(ctx)
+	return ec.marshalNID2string(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) _SubgraphEdge_child(ctx context.Context, field graphql.CollectedField, obj *SubgraphEdge) graphql.Marshaler {
+	ctx = ec.Tracer.StartFieldExecution(ctx, field)
+	defer func() { ec.Tracer.EndFieldExecution(ctx) }()
+	rctx := &graphql.ResolverContext{
+		Object:   "SubgraphEdge",
+		Field:    field,
+		Args:     nil,
+		IsMethod: false,
+	}
+	ctx = graphql.WithResolverContext(ctx, rctx)
+	ctx = ec.Tracer.StartFieldResolverExecution(ctx, rctx)
+	resTmp := ec.FieldMiddleware(ctx, obj, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Child, nil
+	})
+	if resTmp == nil {
+		if !ec.HasError(rctx) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(string)
+	rctx.Result = res
+	ctx = ec.Tracer.StartFieldChildExecution(ctx)
+	return ec.marshalNID2string(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) _SubgraphEdge_label(ctx context.Context, field graphql.CollectedField, obj *SubgraphEdge) graphql.Marshaler {
+	ctx = ec.Tracer.StartFieldExecution(ctx, field)
+	defer func() { ec.Tracer.EndFieldExecution(ctx) }()
+	rctx := &graphql.ResolverContext{
+		Object:   "SubgraphEdge",
+		Field:    field,
+		Args:     nil,
+		IsMethod: false,
+	}
+	ctx = graphql.WithResolverContext(ctx, rctx)
+	ctx = ec.Tracer.StartFieldResolverExecution(ctx, rctx)
+	resTmp := ec.FieldMiddleware(ctx, obj, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Label, nil
+	})
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(*string)
+	rctx.Result = res
+	ctx = ec.Tracer.StartFieldChildExecution(ctx)
+	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) _SubgraphEdge_json(ctx context.Context, field graphql.CollectedField, obj *SubgraphEdge) graphql.Marshaler {
+	ctx = ec.Tracer.StartFieldExecution(ctx, field)
+	defer func() { ec.Tracer.EndFieldExecution(ctx) }()
+	rctx := &graphql.ResolverContext{
+		Object:   "SubgraphEdge",
+		Field:    field,
+		Args:     nil,
+		IsMethod: false,
+	}
+	ctx = graphql.WithResolverContext(ctx, rctx)
+	ctx = ec.Tracer.StartFieldResolverExecution(ctx, rctx)
+	resTmp := ec.FieldMiddleware(ctx, obj, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.JSON, nil
+	})
+	if resTmp == nil {
+		if !ec.HasError(rctx) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(map[string]interface{})
+	rctx.Result = res
+	ctx = ec.Tracer.StartFieldChildExecution(ctx)
+	return ec.marshalNJSON2map(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) _Subscription_nodeChanged(ctx context.Context, field graphql.CollectedField) func() graphql.Marshaler {
+	ctx = ec.Tracer.StartFieldExecution(ctx, field)
+	defer func() { ec.Tracer.EndFieldExecution(ctx) }()
+	rctx := &graphql.ResolverContext{
+		Object:   "Subscription",
+		Field:    field,
+		Args:     nil,
+		IsMethod: true,
+	}
+	ctx = graphql.WithResolverContext(ctx, rctx)
+	rawArgs := field.ArgumentMap(ec.Variables)
+	args, err := ec.field_Subscription_nodeChanged_args(ctx, rawArgs)
+	if err != nil {
+		ec.Error(ctx, err)
+		return nil
+	}
+	rctx.Args = args
+	ctx = ec.Tracer.StartFieldResolverExecution(ctx, rctx)
+	results, err := ec.resolvers.Subscription().NodeChanged(rctx, args["id"].(*string))
+	if err != nil {
+		ec.Error(ctx, err)
+		return nil
+	}
+	return func() graphql.Marshaler {
+		res, ok := <-results
+		if !ok {
+			return nil
+		}
+		return graphql.WriterFunc(func(w io.Writer) {
+			w.Write([]byte{'{'})
+			graphql.MarshalString(field.Alias).MarshalGQL(w)
+			w.Write([]byte{':'})
+			ec.marshalNChangeEvent2ᚖgithubᚗcomᚋaᚑhᚋpregelᚋgraphᚐChangeEvent(ctx, field.Selections, res).MarshalGQL(w)
+			w.Write([]byte{'}'})
+		})
+	}
+}
+
+func (ec *executionContext) ___Directive_name(ctx context.Context, field graphql.CollectedField, obj *introspection.Directive) graphql.Marshaler {
+	ctx = ec.Tracer.StartFieldExecution(ctx, field)
+	defer func() { ec.Tracer.EndFieldExecution(ctx) }()
+	rctx := &graphql.ResolverContext{
+		Object:   "__Directive",
+		Field:    field,
+		Args:     nil,
+		IsMethod: false,
+	}
+	ctx = graphql.WithResolverContext(ctx, rctx)
+	ctx = ec.Tracer.StartFieldResolverExecution(ctx, rctx)
+	resTmp := ec.FieldMiddleware(ctx, obj, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Name, nil
+	})
+	if resTmp == nil {
+		if !ec.HasError(rctx) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(string)
+	rctx.Result = res
+	ctx = ec.Tracer.StartFieldChildExecution(ctx)
+	return ec.marshalNString2string(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) ___Directive_description(ctx context.Context, field graphql.CollectedField, obj *introspection.Directive) graphql.Marshaler {
+	ctx = ec.Tracer.StartFieldExecution(ctx, field)
+	defer func() { ec.Tracer.EndFieldExecution(ctx) }()
+	rctx := &graphql.ResolverContext{
+		Object:   "__Directive",
+		Field:    field,
+		Args:     nil,
+		IsMethod: false,
+	}
+	ctx = graphql.WithResolverContext(ctx, rctx)
+	ctx = ec.Tracer.StartFieldResolverExecution(ctx, rctx)
+	resTmp := ec.FieldMiddleware(ctx, obj, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Description, nil
+	})
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(string)
+	rctx.Result = res
+	ctx = ec.Tracer.StartFieldChildExecution(ctx)
+	return ec.marshalOString2string(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) ___Directive_locations(ctx context.Context, field graphql.CollectedField, obj *introspection.Directive) graphql.Marshaler {
+	ctx = ec.Tracer.StartFieldExecution(ctx, field)
+	defer func() { ec.Tracer.EndFieldExecution(ctx) }()
+	rctx := &graphql.ResolverContext{
+		Object:   "__Directive",
+		Field:    field,
+		Args:     nil,
+		IsMethod: false,
+	}
+	ctx = graphql.WithResolverContext(ctx, rctx)
+	ctx = ec.Tracer.StartFieldResolverExecution(ctx, rctx)
+	resTmp := ec.FieldMiddleware(ctx, obj, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Locations, nil
+	})
+	if resTmp == nil {
+		if !ec.HasError(rctx) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.([]string)
+	rctx.Result = res
+	ctx = ec.Tracer.StartFieldChildExecution(ctx)
+	return ec.marshalN__DirectiveLocation2ᚕstring(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) ___Directive_args(ctx context.Context, field graphql.CollectedField, obj *introspection.Directive) graphql.Marshaler {
+	ctx = ec.Tracer.StartFieldExecution(ctx, field)
+	defer func() { ec.Tracer.EndFieldExecution(ctx) }()
+	rctx := &graphql.ResolverContext{
+		Object:   "__Directive",
+		Field:    field,
+		Args:     nil,
+		IsMethod: false,
+	}
+	ctx = graphql.WithResolverContext(ctx, rctx)
+	ctx = ec.Tracer.StartFieldResolverExecution(ctx, rctx)
+	resTmp := ec.FieldMiddleware(ctx, obj, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Args, nil
+	})
+	if resTmp == nil {
+		if !ec.HasError(rctx) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.([]introspection.InputValue)
+	rctx.Result = res
+	ctx = ec.Tracer.StartFieldChildExecution(ctx)
+	return ec.marshalN__InputValue2ᚕgithubᚗcomᚋ99designsᚋgqlgenᚋgraphqlᚋintrospectionᚐInputValue(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) ___EnumValue_name(ctx context.Context, field graphql.CollectedField, obj *introspection.EnumValue) graphql.Marshaler {
+	ctx = ec.Tracer.StartFieldExecution(ctx, field)
+	defer func() { ec.Tracer.EndFieldExecution(ctx) }()
+	rctx := &graphql.ResolverContext{
+		Object:   "__EnumValue",
+		Field:    field,
+		Args:     nil,
+		IsMethod: false,
+	}
+	ctx = graphql.WithResolverContext(ctx, rctx)
+	ctx = ec.Tracer.StartFieldResolverExecution(ctx, rctx)
+	resTmp := ec.FieldMiddleware(ctx, obj, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Name, nil
+	})
+	if resTmp == nil {
+		if !ec.HasError(rctx) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(string)
+	rctx.Result = res
+	ctx = ec.Tracer.StartFieldChildExecution(ctx)
+	return ec.marshalNString2string(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) ___EnumValue_description(ctx context.Context, field graphql.CollectedField, obj *introspection.EnumValue) graphql.Marshaler {
+	ctx = ec.Tracer.StartFieldExecution(ctx, field)
+	defer func() { ec.Tracer.EndFieldExecution(ctx) }()
+	rctx := &graphql.ResolverContext{
+		Object:   "__EnumValue",
+		Field:    field,
+		Args:     nil,
+		IsMethod: false,
+	}
+	ctx = graphql.WithResolverContext(ctx, rctx)
+	ctx = ec.Tracer.StartFieldResolverExecution(ctx, rctx)
+	resTmp := ec.FieldMiddleware(ctx, obj, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Description, nil
+	})
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(string)
+	rctx.Result = res
+	ctx = ec.Tracer.StartFieldChildExecution(ctx)
+	return ec.marshalOString2string(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) ___EnumValue_isDeprecated(ctx context.Context, field graphql.CollectedField, obj *introspection.EnumValue) graphql.Marshaler {
+	ctx = ec.Tracer.StartFieldExecution(ctx, field)
+	defer func() { ec.Tracer.EndFieldExecution(ctx) }()
+	rctx := &graphql.ResolverContext{
+		Object:   "__EnumValue",
+		Field:    field,
+		Args:     nil,
+		IsMethod: true,
+	}
+	ctx = graphql.WithResolverContext(ctx, rctx)
+	ctx = ec.Tracer.StartFieldResolverExecution(ctx, rctx)
+	resTmp := ec.FieldMiddleware(ctx, obj, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.IsDeprecated(), nil
+	})
+	if resTmp == nil {
+		if !ec.HasError(rctx) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(bool)
+	rctx.Result = res
+	ctx = ec.Tracer.StartFieldChildExecution(ctx)
+	return ec.marshalNBoolean2bool(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) ___EnumValue_deprecationReason(ctx context.Context, field graphql.CollectedField, obj *introspection.EnumValue) graphql.Marshaler {
+	ctx = ec.Tracer.StartFieldExecution(ctx, field)
+	defer func() { ec.Tracer.EndFieldExecution(ctx) }()
+	rctx := &graphql.ResolverContext{
+		Object:   "__EnumValue",
+		Field:    field,
+		Args:     nil,
+		IsMethod: true,
+	}
+	ctx = graphql.WithResolverContext(ctx, rctx)
+	ctx = ec.Tracer.StartFieldResolverExecution(ctx, rctx)
+	resTmp := ec.FieldMiddleware(ctx, obj, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.DeprecationReason(), nil
+	})
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(*string)
+	rctx.Result = res
+	ctx = ec.Tracer.StartFieldChildExecution(ctx)
+	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) ___Field_name(ctx context.Context, field graphql.CollectedField, obj *introspection.Field) graphql.Marshaler {
+	ctx = ec.Tracer.StartFieldExecution(ctx, field)
+	defer func() { ec.Tracer.EndFieldExecution(ctx) }()
+	rctx := &graphql.ResolverContext{
+		Object:   "__Field",
+		Field:    field,
+		Args:     nil,
+		IsMethod: false,
+	}
+	ctx = graphql.WithResolverContext(ctx, rctx)
+	ctx = ec.Tracer.StartFieldResolverExecution(ctx, rctx)
+	resTmp := ec.FieldMiddleware(ctx, obj, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Name, nil
+	})
+	if resTmp == nil {
+		if !ec.HasError(rctx) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(string)
+	rctx.Result = res
+	ctx = ec.Tracer.StartFieldChildExecution(ctx)
+	return ec.marshalNString2string(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) ___Field_description(ctx context.Context, field graphql.CollectedField, obj *introspection.Field) graphql.Marshaler {
+	ctx = ec.Tracer.StartFieldExecution(ctx, field)
+	defer func() { ec.Tracer.EndFieldExecution(ctx) }()
+	rctx := &graphql.ResolverContext{
+		Object:   "__Field",
+		Field:    field,
+		Args:     nil,
+		IsMethod: false,
+	}
+	ctx = graphql.WithResolverContext(ctx, rctx)
+	ctx = ec.Tracer.StartFieldResolverExecution(ctx, rctx)
+	resTmp := ec.FieldMiddleware(ctx, obj, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Description, nil
+	})
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(string)
+	rctx.Result = res
+	ctx = ec.Tracer.StartFieldChildExecution(ctx)
+	return ec.marshalOString2string(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) ___Field_args(ctx context.Context, field graphql.CollectedField, obj *introspection.Field) graphql.Marshaler {
+	ctx = ec.Tracer.StartFieldExecution(ctx, field)
+	defer func() { ec.Tracer.EndFieldExecution(ctx) }()
+	rctx := &graphql.ResolverContext{
+		Object:   "__Field",
+		Field:    field,
+		Args:     nil,
+		IsMethod: false,
+	}
+	ctx = graphql.WithResolverContext(ctx, rctx)
+	ctx = ec.Tracer.StartFieldResolverExecution(ctx, rctx)
+	resTmp := ec.FieldMiddleware(ctx, obj, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Args, nil
+	})
+	if resTmp == nil {
+		if !ec.HasError(rctx) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.([]introspection.InputValue)
+	rctx.Result = res
+	ctx = ec.Tracer.StartFieldChildExecution(ctx)
+	return ec.marshalN__InputValue2ᚕgithubᚗcomᚋ99designsᚋgqlgenᚋgraphqlᚋintrospectionᚐInputValue(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) ___Field_type(ctx context.Context, field graphql.CollectedField, obj *introspection.Field) graphql.Marshaler {
+	ctx = ec.Tracer.StartFieldExecution(ctx, field)
+	defer func() { ec.Tracer.EndFieldExecution(ctx) }()
+	rctx := &graphql.ResolverContext{
+		Object:   "__Field",
+		Field:    field,
+		Args:     nil,
+		IsMethod: false,
+	}
+	ctx = graphql.WithResolverContext(ctx, rctx)
+	ctx = ec.Tracer.StartFieldResolverExecution(ctx, rctx)
+	resTmp := ec.FieldMiddleware(ctx, obj, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Type, nil
+	})
+	if resTmp == nil {
+		if !ec.HasError(rctx) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(*introspection.Type)
+	rctx.Result = res
+	ctx = ec.Tracer.StartFieldChildExecution(ctx)
+	return ec.marshalN__Type2ᚖgithubᚗcomᚋ99designsᚋgqlgenᚋgraphqlᚋintrospectionᚐType(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) ___Field_isDeprecated(ctx context.Context, field graphql.CollectedField, obj *introspection.Field) graphql.Marshaler {
+	ctx = ec.Tracer.StartFieldExecution(ctx, field)
+	defer func() { ec.Tracer.EndFieldExecution(ctx) }()
+	rctx := &graphql.ResolverContext{
+		Object:   "__Field",
+		Field:    field,
+		Args:     nil,
+		IsMethod: true,
+	}
+	ctx = graphql.WithResolverContext(ctx, rctx)
+	ctx = ec.Tracer.StartFieldResolverExecution(ctx, rctx)
+	resTmp := ec.FieldMiddleware(ctx, obj, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.IsDeprecated(), nil
+	})
+	if resTmp == nil {
+		if !ec.HasError(rctx) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(bool)
+	rctx.Result = res
+	ctx = ec.Tracer.StartFieldChildExecution(ctx)
+	return ec.marshalNBoolean2bool(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) ___Field_deprecationReason(ctx context.Context, field graphql.CollectedField, obj *introspection.Field) graphql.Marshaler {
+	ctx = ec.Tracer.StartFieldExecution(ctx, field)
+	defer func() { ec.Tracer.EndFieldExecution(ctx) }()
+	rctx := &graphql.ResolverContext{
+		Object:   "__Field",
+		Field:    field,
+		Args:     nil,
+		IsMethod: true,
+	}
+	ctx = graphql.WithResolverContext(ctx, rctx)
+	ctx = ec.Tracer.StartFieldResolverExecution(ctx, rctx)
+	resTmp := ec.FieldMiddleware(ctx, obj, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.DeprecationReason(), nil
+	})
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(*string)
+	rctx.Result = res
+	ctx = ec.Tracer.StartFieldChildExecution(ctx)
+	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) ___InputValue_name(ctx context.Context, field graphql.CollectedField, obj *introspection.InputValue) graphql.Marshaler {
+	ctx = ec.Tracer.StartFieldExecution(ctx, field)
+	defer func() { ec.Tracer.EndFieldExecution(ctx) }()
+	rctx := &graphql.ResolverContext{
+		Object:   "__InputValue",
+		Field:    field,
+		Args:     nil,
+		IsMethod: false,
+	}
+	ctx = graphql.WithResolverContext(ctx, rctx)
+	ctx = ec.Tracer.StartFieldResolverExecution(ctx, rctx)
+	resTmp := ec.FieldMiddleware(ctx, obj, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Name, nil
+	})
+	if resTmp == nil {
+		if !ec.HasError(rctx) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(string)
+	rctx.Result = res
+	ctx = ec.Tracer.StartFieldChildExecution(ctx)
+	return ec.marshalNString2string(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) ___InputValue_description(ctx context.Context, field graphql.CollectedField, obj *introspection.InputValue) graphql.Marshaler {
+	ctx = ec.Tracer.StartFieldExecution(ctx, field)
+	defer func() { ec.Tracer.EndFieldExecution(ctx) }()
+	rctx := &graphql.ResolverContext{
+		Object:   "__InputValue",
+		Field:    field,
+		Args:     nil,
+		IsMethod: false,
+	}
+	ctx = graphql.WithResolverContext(ctx, rctx)
+	ctx = ec.Tracer.StartFieldResolverExecution(ctx, rctx)
+	resTmp := ec.FieldMiddleware(ctx, obj, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Description, nil
+	})
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(string)
+	rctx.Result = res
+	ctx = ec.Tracer.StartFieldChildExecution(ctx)
+	return ec.marshalOString2string(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) ___InputValue_type(ctx context.Context, field graphql.CollectedField, obj *introspection.InputValue) graphql.Marshaler {
+	ctx = ec.Tracer.StartFieldExecution(ctx, field)
+	defer func() { ec.Tracer.EndFieldExecution(ctx) }()
+	rctx := &graphql.ResolverContext{
+		Object:   "__InputValue",
+		Field:    field,
+		Args:     nil,
+		IsMethod: false,
+	}
+	ctx = graphql.WithResolverContext(ctx, rctx)
+	ctx = ec.Tracer.StartFieldResolverExecution(ctx, rctx)
+	resTmp := ec.FieldMiddleware(ctx, obj, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Type, nil
+	})
+	if resTmp == nil {
+		if !ec.HasError(rctx) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(*introspection.Type)
+	rctx.Result = res
+	ctx = ec.Tracer.StartFieldChildExecution(ctx)
+	return ec.marshalN__Type2ᚖgithubᚗcomᚋ99designsᚋgqlgenᚋgraphqlᚋintrospectionᚐType(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) ___InputValue_defaultValue(ctx context.Context, field graphql.CollectedField, obj *introspection.InputValue) graphql.Marshaler {
+	ctx = ec.Tracer.StartFieldExecution(ctx, field)
+	defer func() { ec.Tracer.EndFieldExecution(ctx) }()
+	rctx := &graphql.ResolverContext{
+		Object:   "__InputValue",
+		Field:    field,
+		Args:     nil,
+		IsMethod: false,
+	}
+	ctx = graphql.WithResolverContext(ctx, rctx)
+	ctx = ec.Tracer.StartFieldResolverExecution(ctx, rctx)
+	resTmp := ec.FieldMiddleware(ctx, obj, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.DefaultValue, nil
+	})
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(*string)
+	rctx.Result = res
+	ctx = ec.Tracer.StartFieldChildExecution(ctx)
+	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) ___Schema_types(ctx context.Context, field graphql.CollectedField, obj *introspection.Schema) graphql.Marshaler {
+	ctx = ec.Tracer.StartFieldExecution(ctx, field)
+	defer func() { ec.Tracer.EndFieldExecution(ctx) }()
+	rctx := &graphql.ResolverContext{
+		Object:   "__Schema",
+		Field:    field,
+		Args:     nil,
+		IsMethod: true,
+	}
+	ctx = graphql.WithResolverContext(ctx, rctx)
+	ctx = ec.Tracer.StartFieldResolverExecution(ctx, rctx)
+	resTmp := ec.FieldMiddleware(ctx, obj, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Types(), nil
+	})
+	if resTmp == nil {
+		if !ec.HasError(rctx) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.([]introspection.Type)
+	rctx.Result = res
+	ctx = ec.Tracer.StartFieldChildExecution(ctx)
+	return ec.marshalN__Type2ᚕgithubᚗcomᚋ99designsᚋgqlgenᚋgraphqlᚋintrospectionᚐType(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) ___Schema_queryType(ctx context.Context, field graphql.CollectedField, obj *introspection.Schema) graphql.Marshaler {
+	ctx = ec.Tracer.StartFieldExecution(ctx, field)
+	defer func() { ec.Tracer.EndFieldExecution(ctx) }()
+	rctx := &graphql.ResolverContext{
+		Object:   "__Schema",
+		Field:    field,
+		Args:     nil,
+		IsMethod: true,
+	}
+	ctx = graphql.WithResolverContext(ctx, rctx)
+	ctx = ec.Tracer.StartFieldResolverExecution(ctx, rctx)
+	resTmp := ec.FieldMiddleware(ctx, obj, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.QueryType(), nil
+	})
+	if resTmp == nil {
+		if !ec.HasError(rctx) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(*introspection.Type)
+	rctx.Result = res
+	ctx = ec.Tracer.StartFieldChildExecution(ctx)
+	return ec.marshalN__Type2ᚖgithubᚗcomᚋ99designsᚋgqlgenᚋgraphqlᚋintrospectionᚐType(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) ___Schema_mutationType(ctx context.Context, field graphql.CollectedField, obj *introspection.Schema) graphql.Marshaler {
+	ctx = ec.Tracer.StartFieldExecution(ctx, field)
+	defer func() { ec.Tracer.EndFieldExecution(ctx) }()
+	rctx := &graphql.ResolverContext{
+		Object:   "__Schema",
+		Field:    field,
+		Args:     nil,
+		IsMethod: true,
+	}
+	ctx = graphql.WithResolverContext(ctx, rctx)
+	ctx = ec.Tracer.StartFieldResolverExecution(ctx, rctx)
+	resTmp := ec.FieldMiddleware(ctx, obj, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.MutationType(), nil
+	})
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(*introspection.Type)
+	rctx.Result = res
+	ctx = ec.Tracer.StartFieldChildExecution(ctx)
+	return ec.marshalO__Type2ᚖgithubᚗcomᚋ99designsᚋgqlgenᚋgraphqlᚋintrospectionᚐType(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) ___Schema_subscriptionType(ctx context.Context, field graphql.CollectedField, obj *introspection.Schema) graphql.Marshaler {
+	ctx = ec.Tracer.StartFieldExecution(ctx, field)
+	defer func() { ec.Tracer.EndFieldExecution(ctx) }()
+	rctx := &graphql.ResolverContext{
+		Object:   "__Schema",
+		Field:    field,
+		Args:     nil,
+		IsMethod: true,
+	}
+	ctx = graphql.WithResolverContext(ctx, rctx)
+	ctx = ec.Tracer.StartFieldResolverExecution(ctx, rctx)
+	resTmp := ec.FieldMiddleware(ctx, obj, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.SubscriptionType(), nil
+	})
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(*introspection.Type)
+	rctx.Result = res
+	ctx = ec.Tracer.StartFieldChildExecution(ctx)
+	return ec.marshalO__Type2ᚖgithubᚗcomᚋ99designsᚋgqlgenᚋgraphqlᚋintrospectionᚐType(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) ___Schema_directives(ctx context.Context, field graphql.CollectedField, obj *introspection.Schema) graphql.Marshaler {
+	ctx = ec.Tracer.StartFieldExecution(ctx, field)
+	defer func() { ec.Tracer.EndFieldExecution(ctx) }()
+	rctx := &graphql.ResolverContext{
+		Object:   "__Schema",
+		Field:    field,
+		Args:     nil,
+		IsMethod: true,
+	}
+	ctx = graphql.WithResolverContext(ctx, rctx)
+	ctx = ec.Tracer.StartFieldResolverExecution(ctx, rctx)
+	resTmp := ec.FieldMiddleware(ctx, obj, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Directives(), nil
+	})
+	if resTmp == nil {
+		if !ec.HasError(rctx) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.([]introspection.Directive)
+	rctx.Result = res
+	ctx = ec.Tracer.StartFieldChildExecution(ctx)
+	return ec.marshalN__Directive2ᚕgithubᚗcomᚋ99designsᚋgqlgenᚋgraphqlᚋintrospectionᚐDirective(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) ___Type_kind(ctx context.Context, field graphql.CollectedField, obj *introspection.Type) graphql.Marshaler {
+	ctx = ec.Tracer.StartFieldExecution(ctx, field)
+	defer func() { ec.Tracer.EndFieldExecution(ctx) }()
+	rctx := &graphql.ResolverContext{
+		Object:   "__Type",
+		Field:    field,
+		Args:     nil,
+		IsMethod: true,
+	}
+	ctx = graphql.WithResolverContext(ctx, rctx)
+	ctx = ec.Tracer.StartFieldResolverExecution(ctx, rctx)
+	resTmp := ec.FieldMiddleware(ctx, obj, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Kind(), nil
+	})
+	if resTmp == nil {
+		if !ec.HasError(rctx) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(string)
+	rctx.Result = res
+	ctx = ec.Tracer.StartFieldChildExecution(ctx)
+	return ec.marshalN__TypeKind2string(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) ___Type_name(ctx context.Context, field graphql.CollectedField, obj *introspection.Type) graphql.Marshaler {
+	ctx = ec.Tracer.StartFieldExecution(ctx, field)
+	defer func() { ec.Tracer.EndFieldExecution(ctx) }()
+	rctx := &graphql.ResolverContext{
+		Object:   "__Type",
+		Field:    field,
+		Args:     nil,
+		IsMethod: true,
+	}
+	ctx = graphql.WithResolverContext(ctx, rctx)
+	ctx = ec.Tracer.StartFieldResolverExecution(ctx, rctx)
+	resTmp := ec.FieldMiddleware(ctx, obj, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Name(), nil
+	})
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(*string)
+	rctx.Result = res
+	ctx = ec.Tracer.StartFieldChildExecution(ctx)
+	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) ___Type_description(ctx context.Context, field graphql.CollectedField, obj *introspection.Type) graphql.Marshaler {
+	ctx = ec.Tracer.StartFieldExecution(ctx, field)
+	defer func() { ec.Tracer.EndFieldExecution(ctx) }()
+	rctx := &graphql.ResolverContext{
+		Object:   "__Type",
+		Field:    field,
+		Args:     nil,
+		IsMethod: true,
+	}
+	ctx = graphql.WithResolverContext(ctx, rctx)
+	ctx = ec.Tracer.StartFieldResolverExecution(ctx, rctx)
+	resTmp := ec.FieldMiddleware(ctx, obj, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Description(), nil
+	})
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(string)
+	rctx.Result = res
+	ctx = ec.Tracer.StartFieldChildExecution(ctx)
+	return ec.marshalOString2string(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) ___Type_fields(ctx context.Context, field graphql.CollectedField, obj *introspection.Type) graphql.Marshaler {
+	ctx = ec.Tracer.StartFieldExecution(ctx, field)
+	defer func() { ec.Tracer.EndFieldExecution(ctx) }()
+	rctx := &graphql.ResolverContext{
+		Object:   "__Type",
+		Field:    field,
+		Args:     nil,
+		IsMethod: true,
+	}
+	ctx = graphql.WithResolverContext(ctx, rctx)
+	rawArgs := field.ArgumentMap(ec.Variables)
+	args, err := ec.field___Type_fields_args(ctx, rawArgs)
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	rctx.Args = args
+	ctx = ec.Tracer.StartFieldResolverExecution(ctx, rctx)
+	resTmp := ec.FieldMiddleware(ctx, obj, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Fields(args["includeDeprecated"].(bool)), nil
+	})
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.([]introspection.Field)
+	rctx.Result = res
+	ctx = ec.Tracer.StartFieldChildExecution(ctx)
+	return ec.marshalO__Field2ᚕgithubᚗcomᚋ99designsᚋgqlgenᚋgraphqlᚋintrospectionᚐField(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) ___Type_interfaces(ctx context.Context, field graphql.CollectedField, obj *introspection.Type) graphql.Marshaler {
+	ctx = ec.Tracer.StartFieldExecution(ctx, field)
+	defer func() { ec.Tracer.EndFieldExecution(ctx) }()
+	rctx := &graphql.ResolverContext{
+		Object:   "__Type",
+		Field:    field,
+		Args:     nil,
+		IsMethod: true,
+	}
+	ctx = graphql.WithResolverContext(ctx, rctx)
+	ctx = ec.Tracer.StartFieldResolverExecution(ctx, rctx)
+	resTmp := ec.FieldMiddleware(ctx, obj, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Interfaces(), nil
+	})
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.([]introspection.Type)
+	rctx.Result = res
+	ctx = ec.Tracer.StartFieldChildExecution(ctx)
+	return ec.marshalO__Type2ᚕgithubᚗcomᚋ99designsᚋgqlgenᚋgraphqlᚋintrospectionᚐType(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) ___Type_possibleTypes(ctx context.Context, field graphql.CollectedField, obj *introspection.Type) graphql.Marshaler {
+	ctx = ec.Tracer.StartFieldExecution(ctx, field)
+	defer func() { ec.Tracer.EndFieldExecution(ctx) }()
+	rctx := &graphql.ResolverContext{
+		Object:   "__Type",
+		Field:    field,
+		Args:     nil,
+		IsMethod: true,
+	}
+	ctx = graphql.WithResolverContext(ctx, rctx)
+	ctx = ec.Tracer.StartFieldResolverExecution(ctx, rctx)
+	resTmp := ec.FieldMiddleware(ctx, obj, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.PossibleTypes(), nil
+	})
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.([]introspection.Type)
+	rctx.Result = res
+	ctx = ec.Tracer.StartFieldChildExecution(ctx)
+	return ec.marshalO__Type2ᚕgithubᚗcomᚋ99designsᚋgqlgenᚋgraphqlᚋintrospectionᚐType(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) ___Type_enumValues(ctx context.Context, field graphql.CollectedField, obj *introspection.Type) graphql.Marshaler {
+	ctx = ec.Tracer.StartFieldExecution(ctx, field)
+	defer func() { ec.Tracer.EndFieldExecution(ctx) }()
+	rctx := &graphql.ResolverContext{
+		Object:   "__Type",
+		Field:    field,
+		Args:     nil,
+		IsMethod: true,
+	}
+	ctx = graphql.WithResolverContext(ctx, rctx)
+	rawArgs := field.ArgumentMap(ec.Variables)
+	args, err := ec.field___Type_enumValues_args(ctx, rawArgs)
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	rctx.Args = args
+	ctx = ec.Tracer.StartFieldResolverExecution(ctx, rctx)
+	resTmp := ec.FieldMiddleware(ctx, obj, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.EnumValues(args["includeDeprecated"].(bool)), nil
+	})
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.([]introspection.EnumValue)
+	rctx.Result = res
+	ctx = ec.Tracer.StartFieldChildExecution(ctx)
+	return ec.marshalO__EnumValue2ᚕgithubᚗcomᚋ99designsᚋgqlgenᚋgraphqlᚋintrospectionᚐEnumValue(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) ___Type_inputFields(ctx context.Context, field graphql.CollectedField, obj *introspection.Type) graphql.Marshaler {
+	ctx = ec.Tracer.StartFieldExecution(ctx, field)
+	defer func() { ec.Tracer.EndFieldExecution(ctx) }()
+	rctx := &graphql.ResolverContext{
+		Object:   "__Type",
+		Field:    field,
+		Args:     nil,
+		IsMethod: true,
+	}
+	ctx = graphql.WithResolverContext(ctx, rctx)
+	ctx = ec.Tracer.StartFieldResolverExecution(ctx, rctx)
+	resTmp := ec.FieldMiddleware(ctx, obj, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.InputFields(), nil
+	})
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.([]introspection.InputValue)
+	rctx.Result = res
+	ctx = ec.Tracer.StartFieldChildExecution(ctx)
+	return ec.marshalO__InputValue2ᚕgithubᚗcomᚋ99designsᚋgqlgenᚋgraphqlᚋintrospectionᚐInputValue(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) ___Type_ofType(ctx context.Context, field graphql.CollectedField, obj *introspection.Type) graphql.Marshaler {
+	ctx = ec.Tracer.StartFieldExecution(ctx, field)
+	defer func() { ec.Tracer.EndFieldExecution(ctx) }()
+	rctx := &graphql.ResolverContext{
+		Object:   "__Type",
+		Field:    field,
+		Args:     nil,
+		IsMethod: true,
+	}
+	ctx = graphql.WithResolverContext(ctx, rctx)
+	ctx = ec.Tracer.StartFieldResolverExecution(ctx, rctx)
+	resTmp := ec.FieldMiddleware(ctx, obj, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.OfType(), nil
+	})
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(*introspection.Type)
 	rctx.Result = res
 	ctx = ec.Tracer.StartFieldChildExecution(ctx)
 	return ec.marshalO__Type2ᚖgithubᚗcomᚋ99designsᚋgqlgenᚋgraphqlᚋintrospectionᚐType(ctx, field.Selections, res)
 }
 
-// endregion **************************** field.gotpl *****************************
-
-// region    **************************** input.gotpl *****************************
+// endregion **************************** field.gotpl *****************************
+
+// region    **************************** input.gotpl *****************************
+
+func (ec *executionContext) unmarshalInputEdgeOrder(ctx context.Context, v interface{}) (EdgeOrder, error) {
+	var it EdgeOrder
+	var asMap = v.(map[string]interface{})
+
+	for k, v := range asMap {
+		switch k {
+		case "field":
+			var err error
+			it.Field, err = ec.unmarshalNEdgeOrderField2githubᚗcomᚋaᚑhᚋpregelᚋgraphᚐEdgeOrderField(ctx, v)
+			if err != nil {
+				return it, err
+			}
+		case "direction":
+			var err error
+			it.Direction, err = ec.unmarshalOOrderDirection2ᚖgithubᚗcomᚋaᚑhᚋpregelᚋgraphᚐOrderDirection(ctx, v)
+			if err != nil {
+				return it, err
+			}
+		}
+	}
+
+	return it, nil
+}
+
+func (ec *executionContext) unmarshalInputLocationInput(ctx context.Context, v interface{}) (LocationInput, error) {
+	var it LocationInput
+	var asMap = v.(map[string]interface{})
+
+	for k, v := range asMap {
+		switch k {
+		case "lng":
+			var err error
+			it.Lng, err = ec.unmarshalNFloat2float64(ctx, v)
+			if err != nil {
+				return it, err
+			}
+		case "lat":
+			var err error
+			it.Lat, err = ec.unmarshalNFloat2float64(ctx, v)
+			if err != nil {
+				return it, err
+			}
+		}
+	}
+
+	return it, nil
+}
+
+func (ec *executionContext) unmarshalInputNodeFilter(ctx context.Context, v interface{}) (NodeFilter, error) {
+	var it NodeFilter
+	var asMap = v.(map[string]interface{})
+
+	for k, v := range asMap {
+		switch k {
+		case "idPrefix":
+			var err error
+			it.IDPrefix, err = ec.unmarshalOString2ᚖstring(ctx, v)
+			if err != nil {
+				return it, err
+			}
+		case "label":
+			var err error
+			it.Label, err = ec.unmarshalOString2ᚖstring(ctx, v)
+			if err != nil {
+				return it, err
+			}
+		case "dataType":
+			var err error
+			it.DataType, err = ec.unmarshalOString2ᚖstring(ctx, v)
+			if err != nil {
+				return it, err
+			}
+		}
+	}
+
+	return it, nil
+}
+
+func (ec *executionContext) unmarshalInputRemoveEdgeDataInput(ctx context.Context, v interface{}) (RemoveEdgeDataInput, error) {
+	var it RemoveEdgeDataInput
+	var asMap = v.(map[string]interface{})
+
+	for k, v := range asMap {
+		switch k {
+		case "parent":
+			var err error
+			it.Parent, err = ec.unmarshalNID2string(ctx, v)
+			if err != nil {
+				return it, err
+			}
+		case "child":
+			var err error
+			it.Child, err = ec.unmarshalNID2string(ctx, v)
+			if err != nil {
+				return it, err
+			}
+		case "label":
+			var err error
+			it.Label, err = ec.unmarshalOString2ᚖstring(ctx, v)
+			if err != nil {
+				return it, err
+			}
+		case "type":
+			var err error
+			it.Type, err = ec.unmarshalNString2string(ctx, v)
+			if err != nil {
+				return it, err
+			}
+		}
+	}
+
+	return it, nil
+}
+
+func (ec *executionContext) unmarshalInputRemoveEdgeInput(ctx context.Context, v interface{}) (RemoveEdgeInput, error) {
+	var it RemoveEdgeInput
+	var asMap = v.(map[string]interface{})
+
+	for k, v := range asMap {
+		switch k {
+		case "parent":
+			var err error
+			it.Parent, err = ec.unmarshalNID2string(ctx, v)
+			if err != nil {
+				return it, err
+			}
+		case "child":
+			var err error
+			it.Child, err = ec.unmarshalNID2string(ctx, v)
+			if err != nil {
+				return it, err
+			}
+		}
+	}
+
+	return it, nil
+}
+
+func (ec *executionContext) unmarshalInputRemoveNodeDataInput(ctx context.Context, v interface{}) (RemoveNodeDataInput, error) {
+	var it RemoveNodeDataInput
+	var asMap = v.(map[string]interface{})
+
+	for k, v := range asMap {
+		switch k {
+		case "id":
+			var err error
+			it.ID, err = ec.unmarshalNID2string(ctx, v)
+			if err != nil {
+				return it, err
+			}
+		case "type":
+			var err error
+			it.Type, err = ec.unmarshalNString2string(ctx, v)
+			if err != nil {
+				return it, err
+			}
+		}
+	}
+
+	return it, nil
+}
+
+func (ec *executionContext) unmarshalInputRemoveNodeInput(ctx context.Context, v interface{}) (RemoveNodeInput, error) {
+	var it RemoveNodeInput
+	var asMap = v.(map[string]interface{})
+
+	for k, v := range asMap {
+		switch k {
+		case "id":
+			var err error
+			it.ID, err = ec.unmarshalNID2string(ctx, v)
+			if err != nil {
+				return it, err
+			}
+		}
+	}
+
+	return it, nil
+}
+
+func (ec *executionContext) unmarshalInputSaveEdgeInput(ctx context.Context, v interface{}) (SaveEdgeInput, error) {
+	var it SaveEdgeInput
+	var asMap = v.(map[string]interface{})
+
+	for k, v := range asMap {
+		switch k {
+		case "parent":
+			var err error
+			it.Parent, err = ec.unmarshalNID2string(ctx, v)
+			if err != nil {
+				return it, err
+			}
+		case "child":
+			var err error
+			it.Child, err = ec.unmarshalNID2string(ctx, v)
+			if err != nil {
+				return it, err
+			}
+		case "location":
+			var err error
+			it.Location, err = ec.unmarshalOLocationInput2ᚖgithubᚗcomᚋaᚑhᚋpregelᚋgraphᚐLocationInput(ctx, v)
+			if err != nil {
+				return it, err
+			}
+		}
+	}
+
+	return it, nil
+}
+
+func (ec *executionContext) unmarshalInputSaveNodeInput(ctx context.Context, v interface{}) (SaveNodeInput, error) {
+	var it SaveNodeInput
+	var asMap = v.(map[string]interface{})
+
+	for k, v := range asMap {
+		switch k {
+		case "id":
+			var err error
+			it.ID, err = ec.unmarshalNID2string(ctx, v)
+			if err != nil {
+				return it, err
+			}
+		case "parents":
+			var err error
+			it.Parents, err = ec.unmarshalOID2ᚕstring(ctx, v)
+			if err != nil {
+				return it, err
+			}
+		case "children":
+			var err error
+			it.Children, err = ec.unmarshalOID2ᚕstring(ctx, v)
+			if err != nil {
+				return it, err
+			}
+		case "location":
+			var err error
+			it.Location, err = ec.unmarshalOLocationInput2ᚖgithubᚗcomᚋaᚑhᚋpregelᚋgraphᚐLocationInput(ctx, v)
+			if err != nil {
+				return it, err
+			}
+		}
+	}
+
+	return it, nil
+}
+
+func (ec *executionContext) unmarshalInputSetEdgeDataInput(ctx context.Context, v interface{}) (SetEdgeDataInput, error) {
+	var it SetEdgeDataInput
+	var asMap = v.(map[string]interface{})
+
+	for k, v := range asMap {
+		switch k {
+		case "parent":
+			var err error
+			it.Parent, err = ec.unmarshalNID2string(ctx, v)
+			if err != nil {
+				return it, err
+			}
+		case "child":
+			var err error
+			it.Child, err = ec.unmarshalNID2string(ctx, v)
+			if err != nil {
+				return it, err
+			}
+		case "type":
+			var err error
+			it.Type, err = ec.unmarshalNString2string(ctx, v)
+			if err != nil {
+				return it, err
+			}
+		case "data":
+			var err error
+			it.Data, err = ec.unmarshalNJSON2map(ctx, v)
+			if err != nil {
+				return it, err
+			}
+		}
+	}
+
+	return it, nil
+}
+
+func (ec *executionContext) unmarshalInputSetEdgeFieldsInput(ctx context.Context, v interface{}) (SetEdgeFieldsInput, error) {
+	var it SetEdgeFieldsInput
+	var asMap = v.(map[string]interface{})
+
+	for k, v := range asMap {
+		switch k {
+		case "parent":
+			var err error
+			it.Parent, err = ec.unmarshalNID2string(ctx, v)
+			if err != nil {
+				return it, err
+			}
+		case "child":
+			var err error
+			it.Child, err = ec.unmarshalNID2string(ctx, v)
+			if err != nil {
+				return it, err
+			}
+		case "location":
+			var err error
+			it.Location, err = ec.unmarshalOLocationInput2ᚖgithubᚗcomᚋaᚑhᚋpregelᚋgraphᚐLocationInput(ctx, v)
+			if err != nil {
+				return it, err
+			}
+		}
+	}
+
+	return it, nil
+}
+
+func (ec *executionContext) unmarshalInputSetNodeDataInput(ctx context.Context, v interface{}) (SetNodeDataInput, error) {
+	var it SetNodeDataInput
+	var asMap = v.(map[string]interface{})
+
+	for k, v := range asMap {
+		switch k {
+		case "id":
+			var err error
+			it.ID, err = ec.unmarshalNID2string(ctx, v)
+			if err != nil {
+				return it, err
+			}
+		case "type":
+			var err error
+			it.Type, err = ec.unmarshalNString2string(ctx, v)
+			if err != nil {
+				return it, err
+			}
+		case "data":
+			var err error
+			it.Data, err = ec.unmarshalNJSON2map(ctx, v)
+			if err != nil {
+				return it, err
+			}
+		}
+	}
+
+	return it, nil
+}
+
+func (ec *executionContext) unmarshalInputSetNodeFieldsInput(ctx context.Context, v interface{}) (SetNodeFieldsInput, error) {
+	var it SetNodeFieldsInput
+	var asMap = v.(map[string]interface{})
+
+	for k, v := range asMap {
+		switch k {
+		case "id":
+			var err error
+			it.ID, err = ec.unmarshalNID2string(ctx, v)
+			if err != nil {
+				return it, err
+			}
+		case "location":
+			var err error
+			it.Location, err = ec.unmarshalOLocationInput2ᚖgithubᚗcomᚋaᚑhᚋpregelᚋgraphᚐLocationInput(ctx, v)
+			if err != nil {
+				return it, err
+			}
+		}
+	}
+
+	return it, nil
+}
+
+// endregion **************************** input.gotpl *****************************
+
+// region    ************************** interface.gotpl ***************************
+
+func (ec *executionContext) _EdgeDataItem(ctx context.Context, sel ast.SelectionSet, obj *EdgeDataItem) graphql.Marshaler {
+	switch obj := (*obj).(type) {
+	case nil:
+		return graphql.Null
+	case Location:
+		return ec._Location(ctx, sel, &obj)
+	case *Location:
+		return ec._Location(ctx, sel, obj)
+	default:
+		panic(fmt.Errorf("unexpected type %T", obj))
+	}
+}
+
+func (ec *executionContext) _Node(ctx context.Context, sel ast.SelectionSet, obj *RelayNode) graphql.Marshaler {
+	switch obj := (*obj).(type) {
+	case nil:
+		return graphql.Null
+	case pregel.Node:
+		return ec._GraphNode(ctx, sel, &obj)
+	case *pregel.Node:
+		return ec._GraphNode(ctx, sel, obj)
+	case LocationNode:
+		return ec._LocationNode(ctx, sel, &obj)
+	case *LocationNode:
+		return ec._LocationNode(ctx, sel, obj)
+	default:
+		panic(fmt.Errorf("unexpected type %T", obj))
+	}
+}
+
+func (ec *executionContext) _NodeDataItem(ctx context.Context, sel ast.SelectionSet, obj *NodeDataItem) graphql.Marshaler {
+	switch obj := (*obj).(type) {
+	case nil:
+		return graphql.Null
+	case Location:
+		return ec._Location(ctx, sel, &obj)
+	case *Location:
+		return ec._Location(ctx, sel, obj)
+	case Computer:
+		return ec._Computer(ctx, sel, &obj)
+	case *Computer:
+		return ec._Computer(ctx, sel, obj)
+	default:
+		panic(fmt.Errorf("unexpected type %T", obj))
+	}
+}
+
+// endregion ************************** interface.gotpl ***************************
+
+// region    **************************** object.gotpl ****************************
+
+var changeEventImplementors = []string{"ChangeEvent"}
+
+func (ec *executionContext) _ChangeEvent(ctx context.Context, sel ast.SelectionSet, obj *ChangeEvent) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.RequestContext, sel, changeEventImplementors)
+
+	out := graphql.NewFieldSet(fields)
+	invalid := false
+	for i, field := range fields {
+		switch field.Name {
+		case "__typename":
+			out.Values[i] = graphql.MarshalString("ChangeEvent")
+		case "type":
+			out.Values[i] = ec._ChangeEvent_type(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				invalid = true
+			}
+		case "nodeId":
+			out.Values[i] = ec._ChangeEvent_nodeId(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				invalid = true
+			}
+		case "child":
+			out.Values[i] = ec._ChangeEvent_child(ctx, field, obj)
+		case "label":
+			out.Values[i] = ec._ChangeEvent_label(ctx, field, obj)
+		case "dataType":
+			out.Values[i] = ec._ChangeEvent_dataType(ctx, field, obj)
+		case "new":
+			out.Values[i] = ec._ChangeEvent_new(ctx, field, obj)
+		case "time":
+			out.Values[i] = ec._ChangeEvent_time(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				invalid = true
+			}
+		default:
+			panic("unknown field " + strconv.Quote(field.Name))
+		}
+	}
+	out.Dispatch()
+	if invalid {
+		return graphql.Null
+	}
+	return out
+}
+
+var computerImplementors = []string{"Computer", "NodeDataItem"}
 
-func (ec *executionContext) unmarshalInputLocationInput(ctx context.Context, v interface{}) (LocationInput, error) {
-	var it LocationInput
-	var asMap = v.(map[string]interface{})
+func (ec *executionContext) _Computer(ctx context.Context, sel ast.SelectionSet, obj *Computer) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.RequestContext, sel, computerImplementors)
 
-	for k, v := range asMap {
-		switch k {
-		case "lng":
-			var err error
-			it.Lng, err = ec.unmarshalNFloat2float64(ctx, v)
-			if err != nil {
-				return it, err
+	out := graphql.NewFieldSet(fields)
+	invalid := false
+	for i, field := range fields {
+		switch field.Name {
+		case "__typename":
+			out.Values[i] = graphql.MarshalString("Computer")
+		case "brand":
+			out.Values[i] = ec._Computer_brand(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				invalid = true
 			}
-		case "lat":
-			var err error
-			it.Lat, err = ec.unmarshalNFloat2float64(ctx, v)
-			if err != nil {
-				return it, err
+		case "yearPurchased":
+			out.Values[i] = ec._Computer_yearPurchased(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				invalid = true
 			}
+		default:
+			panic("unknown field " + strconv.Quote(field.Name))
 		}
 	}
-
-	return it, nil
+	out.Dispatch()
+	if invalid {
+		return graphql.Null
+	}
+	return out
 }
 
-func (ec *executionContext) unmarshalInputRemoveEdgeInput(ctx context.Context, v interface{}) (RemoveEdgeInput, error) {
-	var it RemoveEdgeInput
-	var asMap = v.(map[string]interface{})
+var connectionImplementors = []string{"Connection"}
 
-	for k, v := range asMap {
-		switch k {
-		case "parent":
-			var err error
-			it.Parent, err = ec.unmarshalNID2string(ctx, v)
-			if err != nil {
-				return it, err
+func (ec *executionContext) _Connection(ctx context.Context, sel ast.SelectionSet, obj *Connection) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.RequestContext, sel, connectionImplementors)
+
+	out := graphql.NewFieldSet(fields)
+	invalid := false
+	for i, field := range fields {
+		switch field.Name {
+		case "__typename":
+			out.Values[i] = graphql.MarshalString("Connection")
+		case "edges":
+			out.Values[i] = ec._Connection_edges(ctx, field, obj)
+		case "pageInfo":
+			out.Values[i] = ec._Connection_pageInfo(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				invalid = true
 			}
-		case "child":
-			var err error
-			it.Child, err = ec.unmarshalNID2string(ctx, v)
-			if err != nil {
-				return it, err
+		case "totalCount":
+			out.Values[i] = ec._Connection_totalCount(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				invalid = true
 			}
+		default:
+			panic("unknown field " + strconv.Quote(field.Name))
 		}
 	}
+	out.Dispatch()
+	if invalid {
+		return graphql.Null
+	}
+	return out
+}
 
-	return it, nil
+var edgeImplementors = []string{"Edge"}
+
+func (ec *executionContext) _Edge(ctx context.Context, sel ast.SelectionSet, obj *Edge) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.RequestContext, sel, edgeImplementors)
+
+	out := graphql.NewFieldSet(fields)
+	invalid := false
+	for i, field := range fields {
+		switch field.Name {
+		case "__typename":
+			out.Values[i] = graphql.MarshalString("Edge")
+		case "cursor":
+			out.Values[i] = ec._Edge_cursor(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				invalid = true
+			}
+		case "node":
+			out.Values[i] = ec._Edge_node(ctx, field, obj)
+		case "data":
+			out.Values[i] = ec._Edge_data(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				invalid = true
+			}
+		case "json":
+			out.Values[i] = ec._Edge_json(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				invalid = true
+			}
+		case "createdAt":
+			out.Values[i] = ec._Edge_createdAt(ctx, field, obj)
+		case "updatedAt":
+			out.Values[i] = ec._Edge_updatedAt(ctx, field, obj)
+		default:
+			panic("unknown field " + strconv.Quote(field.Name))
+		}
+	}
+	out.Dispatch()
+	if invalid {
+		return graphql.Null
+	}
+	return out
 }
 
-func (ec *executionContext) unmarshalInputRemoveNodeInput(ctx context.Context, v interface{}) (RemoveNodeInput, error) {
-	var it RemoveNodeInput
-	var asMap = v.(map[string]interface{})
+var graphNodeImplementors = []string{"GraphNode", "Node"}
 
-	for k, v := range asMap {
-		switch k {
+func (ec *executionContext) _GraphNode(ctx context.Context, sel ast.SelectionSet, obj *pregel.Node) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.RequestContext, sel, graphNodeImplementors)
+
+	out := graphql.NewFieldSet(fields)
+	invalid := false
+	for i, field := range fields {
+		switch field.Name {
+		case "__typename":
+			out.Values[i] = graphql.MarshalString("GraphNode")
 		case "id":
-			var err error
-			it.ID, err = ec.unmarshalNID2string(ctx, v)
-			if err != nil {
-				return it, err
-			}
+			field := field
+			out.Concurrently(i, func() (res graphql.Marshaler) {
+				defer func() {
+					if r := recover(); r != nil {
+						ec.Error(ctx, ec.Recover(ctx, r))
+					}
+				}()
+				res = ec._GraphNode_id(ctx, field, obj)
+				if res == graphql.Null {
+					invalid = true
+				}
+				return res
+			})
+		case "nodeId":
+			field := field
+			out.Concurrently(i, func() (res graphql.Marshaler) {
+				defer func() {
+					if r := recover(); r != nil {
+						ec.Error(ctx, ec.Recover(ctx, r))
+					}
+				}()
+				res = ec._GraphNode_nodeId(ctx, field, obj)
+				if res == graphql.Null {
+					invalid = true
+				}
+				return res
+			})
+		case "parents":
+			field := field
+			out.Concurrently(i, func() (res graphql.Marshaler) {
+				defer func() {
+					if r := recover(); r != nil {
+						ec.Error(ctx, ec.Recover(ctx, r))
+					}
+				}()
+				res = ec._GraphNode_parents(ctx, field, obj)
+				return res
+			})
+		case "children":
+			field := field
+			out.Concurrently(i, func() (res graphql.Marshaler) {
+				defer func() {
+					if r := recover(); r != nil {
+						ec.Error(ctx, ec.Recover(ctx, r))
+					}
+				}()
+				res = ec._GraphNode_children(ctx, field, obj)
+				return res
+			})
+		case "data":
+			field := field
+			out.Concurrently(i, func() (res graphql.Marshaler) {
+				defer func() {
+					if r := recover(); r != nil {
+						ec.Error(ctx, ec.Recover(ctx, r))
+					}
+				}()
+				res = ec._GraphNode_data(ctx, field, obj)
+				if res == graphql.Null {
+					invalid = true
+				}
+				return res
+			})
+		case "json":
+			field := field
+			out.Concurrently(i, func() (res graphql.Marshaler) {
+				defer func() {
+					if r := recover(); r != nil {
+						ec.Error(ctx, ec.Recover(ctx, r))
+					}
+				}()
+				res = ec._GraphNode_json(ctx, field, obj)
+				if res == graphql.Null {
+					invalid = true
+				}
+				return res
+			})
+		case "createdAt":
+			field := field
+			out.Concurrently(i, func() (res graphql.Marshaler) {
+				defer func() {
+					if r := recover(); r != nil {
+						ec.Error(ctx, ec.Recover(ctx, r))
+					}
+				}()
+				res = ec._GraphNode_createdAt(ctx, field, obj)
+				return res
+			})
+		case "updatedAt":
+			field := field
+			out.Concurrently(i, func() (res graphql.Marshaler) {
+				defer func() {
+					if r := recover(); r != nil {
+						ec.Error(ctx, ec.Recover(ctx, r))
+					}
+				}()
+				res = ec._GraphNode_updatedAt(ctx, field, obj)
+				return res
+			})
+		case "typed":
+			field := field
+			out.Concurrently(i, func() (res graphql.Marshaler) {
+				defer func() {
+					if r := recover(); r != nil {
+						ec.Error(ctx, ec.Recover(ctx, r))
+					}
+				}()
+				res = ec._GraphNode_typed(ctx, field, obj)
+				if res == graphql.Null {
+					invalid = true
+				}
+				return res
+			})
+		default:
+			panic("unknown field " + strconv.Quote(field.Name))
 		}
 	}
-
-	return it, nil
-}
-
-func (ec *executionContext) unmarshalInputSaveEdgeInput(ctx context.Context, v interface{}) (SaveEdgeInput, error) {
-	var it SaveEdgeInput
-	var asMap = v.(map[string]interface{})
-
-	for k, v := range asMap {
-		switch k {
-		case "parent":
-			var err error
-			it.Parent, err = ec.unmarshalNID2string(ctx, v)
-			if err != nil {
-				return it, err
-			}
-		case "child":
-			var err error
-			it.Child, err = ec.unmarshalNID2string(ctx, v)
-			if err != nil {
-				return it, err
-			}
-		case "location":
-			var err error
-			it.Location, err = ec.unmarshalOLocationInput2ᚖgithubᚗcomᚋaᚑhᚋpregelᚋgraphᚐLocationInput(ctx, v)
-			if err != nil {
-				return it, err
-			}
-		}
+	out.Dispatch()
+	if invalid {
+		return graphql.Null
 	}
-
-	return it, nil
+	return out
 }
 
-func (ec *executionContext) unmarshalInputSaveNodeInput(ctx context.Context, v interface{}) (SaveNodeInput, error) {
-	var it SaveNodeInput
-	var asMap = v.(map[string]interface{})
+var importGraphOutputImplementors = []string{"ImportGraphOutput"}
 
-	for k, v := range asMap {
-		switch k {
-		case "id":
-			var err error
-			it.ID, err = ec.unmarshalNID2string(ctx, v)
-			if err != nil {
-				return it, err
-			}
-		case "parents":
-			var err error
-			it.Parents, err = ec.unmarshalOID2ᚕstring(ctx, v)
-			if err != nil {
-				return it, err
-			}
-		case "children":
-			var err error
-			it.Children, err = ec.unmarshalOID2ᚕstring(ctx, v)
-			if err != nil {
-				return it, err
+func (ec *executionContext) _ImportGraphOutput(ctx context.Context, sel ast.SelectionSet, obj *ImportGraphOutput) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.RequestContext, sel, importGraphOutputImplementors)
+
+	out := graphql.NewFieldSet(fields)
+	invalid := false
+	for i, field := range fields {
+		switch field.Name {
+		case "__typename":
+			out.Values[i] = graphql.MarshalString("ImportGraphOutput")
+		case "nodesRead":
+			out.Values[i] = ec._ImportGraphOutput_nodesRead(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				invalid = true
 			}
-		case "location":
-			var err error
-			it.Location, err = ec.unmarshalOLocationInput2ᚖgithubᚗcomᚋaᚑhᚋpregelᚋgraphᚐLocationInput(ctx, v)
-			if err != nil {
-				return it, err
+		case "recordsWritten":
+			out.Values[i] = ec._ImportGraphOutput_recordsWritten(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				invalid = true
 			}
+		default:
+			panic("unknown field " + strconv.Quote(field.Name))
 		}
 	}
-
-	return it, nil
+	out.Dispatch()
+	if invalid {
+		return graphql.Null
+	}
+	return out
 }
 
-func (ec *executionContext) unmarshalInputSetEdgeFieldsInput(ctx context.Context, v interface{}) (SetEdgeFieldsInput, error) {
-	var it SetEdgeFieldsInput
-	var asMap = v.(map[string]interface{})
+var locationImplementors = []string{"Location", "NodeDataItem", "EdgeDataItem"}
 
-	for k, v := range asMap {
-		switch k {
-		case "parent":
-			var err error
-			it.Parent, err = ec.unmarshalNID2string(ctx, v)
-			if err != nil {
-				return it, err
-			}
-		case "child":
-			var err error
-			it.Child, err = ec.unmarshalNID2string(ctx, v)
-			if err != nil {
-				return it, err
+func (ec *executionContext) _Location(ctx context.Context, sel ast.SelectionSet, obj *Location) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.RequestContext, sel, locationImplementors)
+
+	out := graphql.NewFieldSet(fields)
+	invalid := false
+	for i, field := range fields {
+		switch field.Name {
+		case "__typename":
+			out.Values[i] = graphql.MarshalString("Location")
+		case "lng":
+			out.Values[i] = ec._Location_lng(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				invalid = true
 			}
-		case "location":
-			var err error
-			it.Location, err = ec.unmarshalOLocationInput2ᚖgithubᚗcomᚋaᚑhᚋpregelᚋgraphᚐLocationInput(ctx, v)
-			if err != nil {
-				return it, err
+		case "lat":
+			out.Values[i] = ec._Location_lat(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				invalid = true
 			}
+		default:
+			panic("unknown field " + strconv.Quote(field.Name))
 		}
 	}
-
-	return it, nil
+	out.Dispatch()
+	if invalid {
+		return graphql.Null
+	}
+	return out
 }
 
-func (ec *executionContext) unmarshalInputSetNodeFieldsInput(ctx context.Context, v interface{}) (SetNodeFieldsInput, error) {
-	var it SetNodeFieldsInput
-	var asMap = v.(map[string]interface{})
+var locationNodeImplementors = []string{"LocationNode", "Node"}
 
-	for k, v := range asMap {
-		switch k {
+func (ec *executionContext) _LocationNode(ctx context.Context, sel ast.SelectionSet, obj *LocationNode) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.RequestContext, sel, locationNodeImplementors)
+
+	out := graphql.NewFieldSet(fields)
+	invalid := false
+	for i, field := range fields {
+		switch field.Name {
+		case "__typename":
+			out.Values[i] = graphql.MarshalString("LocationNode")
 		case "id":
-			var err error
-			it.ID, err = ec.unmarshalNID2string(ctx, v)
-			if err != nil {
-				return it, err
+			out.Values[i] = ec._LocationNode_id(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				invalid = true
+			}
+		case "nodeId":
+			out.Values[i] = ec._LocationNode_nodeId(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				invalid = true
 			}
 		case "location":
-			var err error
-			it.Location, err = ec.unmarshalOLocationInput2ᚖgithubᚗcomᚋaᚑhᚋpregelᚋgraphᚐLocationInput(ctx, v)
-			if err != nil {
-				return it, err
+			out.Values[i] = ec._LocationNode_location(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				invalid = true
+			}
+		case "node":
+			out.Values[i] = ec._LocationNode_node(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				invalid = true
 			}
+		default:
+			panic("unknown field " + strconv.Quote(field.Name))
 		}
 	}
-
-	return it, nil
+	out.Dispatch()
+	if invalid {
+		return graphql.Null
+	}
+	return out
 }
 
-// endregion **************************** input.gotpl *****************************
+var mutationImplementors = []string{"Mutation"}
 
-// region    ************************** interface.gotpl ***************************
+func (ec *executionContext) _Mutation(ctx context.Context, sel ast.SelectionSet) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.RequestContext, sel, mutationImplementors)
 
-func (ec *executionContext) _EdgeDataItem(ctx context.Context, sel ast.SelectionSet, obj *EdgeDataItem) graphql.Marshaler {
-	switch obj := (*obj).(type) {
-	case nil:
-		return graphql.Null
-	case Location:
-		return ec._Location(ctx, sel, &obj)
-	case *Location:
-		return ec._Location(ctx, sel, obj)
-	default:
-		panic(fmt.Errorf("unexpected type %T", obj))
-	}
-}
+	ctx = graphql.WithResolverContext(ctx, &graphql.ResolverContext{
+		Object: "Mutation",
+	})
 
-func (ec *executionContext) _NodeDataItem(ctx context.Context, sel ast.SelectionSet, obj *NodeDataItem) graphql.Marshaler {
-	switch obj := (*obj).(type) {
-	case nil:
-		return graphql.Null
-	case Location:
-		return ec._Location(ctx, sel, &obj)
-	case *Location:
-		return ec._Location(ctx, sel, obj)
-	case Computer:
-		return ec._Computer(ctx, sel, &obj)
-	case *Computer:
-		return ec._Computer(ctx, sel, obj)
-	default:
-		panic(fmt.Errorf("unexpected type %T", obj))
+	out := graphql.NewFieldSet(fields)
+	invalid := false
+	for i, field := range fields {
+		switch field.Name {
+		case "__typename":
+			out.Values[i] = graphql.MarshalString("Mutation")
+		case "saveNode":
+			out.Values[i] = ec._Mutation_saveNode(ctx, field)
+			if out.Values[i] == graphql.Null {
+				invalid = true
+			}
+		case "saveEdge":
+			out.Values[i] = ec._Mutation_saveEdge(ctx, field)
+			if out.Values[i] == graphql.Null {
+				invalid = true
+			}
+		case "removeNode":
+			out.Values[i] = ec._Mutation_removeNode(ctx, field)
+			if out.Values[i] == graphql.Null {
+				invalid = true
+			}
+		case "removeEdge":
+			out.Values[i] = ec._Mutation_removeEdge(ctx, field)
+			if out.Values[i] == graphql.Null {
+				invalid = true
+			}
+		case "setNodeFields":
+			out.Values[i] = ec._Mutation_setNodeFields(ctx, field)
+			if out.Values[i] == graphql.Null {
+				invalid = true
+			}
+		case "setEdgeFields":
+			out.Values[i] = ec._Mutation_setEdgeFields(ctx, field)
+			if out.Values[i] == graphql.Null {
+				invalid = true
+			}
+		case "setNodeData":
+			out.Values[i] = ec._Mutation_setNodeData(ctx, field)
+			if out.Values[i] == graphql.Null {
+				invalid = true
+			}
+		case "setEdgeData":
+			out.Values[i] = ec._Mutation_setEdgeData(ctx, field)
+			if out.Values[i] == graphql.Null {
+				invalid = true
+			}
+		case "removeNodeData":
+			out.Values[i] = ec._Mutation_removeNodeData(ctx, field)
+			if out.Values[i] == graphql.Null {
+				invalid = true
+			}
+		case "removeEdgeData":
+			out.Values[i] = ec._Mutation_removeEdgeData(ctx, field)
+			if out.Values[i] == graphql.Null {
+				invalid = true
+			}
+		case "importGraph":
+			out.Values[i] = ec._Mutation_importGraph(ctx, field)
+			if out.Values[i] == graphql.Null {
+				invalid = true
+			}
+		default:
+			panic("unknown field " + strconv.Quote(field.Name))
+		}
+	}
+	out.Dispatch()
+	if invalid {
+		return graphql.Null
 	}
+	return out
 }
 
-// endregion ************************** interface.gotpl ***************************
-
-// region    **************************** object.gotpl ****************************
-
-var computerImplementors = []string{"Computer", "NodeDataItem"}
+var pageInfoImplementors = []string{"PageInfo"}
 
-func (ec *executionContext) _Computer(ctx context.Context, sel ast.SelectionSet, obj *Computer) graphql.Marshaler {
-	fields := graphql.CollectFields(ec.RequestContext, sel, computerImplementors)
+func (ec *executionContext) _PageInfo(ctx context.Context, sel ast.SelectionSet, obj *PageInfo) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.RequestContext, sel, pageInfoImplementors)
 
 	out := graphql.NewFieldSet(fields)
 	invalid := false
 	for i, field := range fields {
 		switch field.Name {
 		case "__typename":
-			out.Values[i] = graphql.MarshalString("Computer")
-		case "brand":
-			out.Values[i] = ec._Computer_brand(ctx, field, obj)
+			out.Values[i] = graphql.MarshalString("PageInfo")
+		case "endCursor":
+			out.Values[i] = ec._PageInfo_endCursor(ctx, field, obj)
+		case "hasNextPage":
+			out.Values[i] = ec._PageInfo_hasNextPage(ctx, field, obj)
 			if out.Values[i] == graphql.Null {
 				invalid = true
 			}
-		case "yearPurchased":
-			out.Values[i] = ec._Computer_yearPurchased(ctx, field, obj)
+		case "hasPreviousPage":
+			out.Values[i] = ec._PageInfo_hasPreviousPage(ctx, field, obj)
 			if out.Values[i] == graphql.Null {
 				invalid = true
 			}
+		case "startCursor":
+			out.Values[i] = ec._PageInfo_startCursor(ctx, field, obj)
 		default:
 			panic("unknown field " + strconv.Quote(field.Name))
 		}
@@ -2858,26 +5824,24 @@ func (ec *executionContext) _Computer(ctx context.Context, sel ast.SelectionSet,
 	return out
 }
 
-var connectionImplementors = []string{"Connection"}
+var pathImplementors = []string{"Path"}
 
-func (ec *executionContext) _Connection(ctx context.Context, sel ast.SelectionSet, obj *Connection) graphql.Marshaler {
-	fields := graphql.CollectFields(ec.RequestContext, sel, connectionImplementors)
+func (ec *executionContext) _Path(ctx context.Context, sel ast.SelectionSet, obj *Path) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.RequestContext, sel, pathImplementors)
 
 	out := graphql.NewFieldSet(fields)
 	invalid := false
 	for i, field := range fields {
 		switch field.Name {
 		case "__typename":
-			out.Values[i] = graphql.MarshalString("Connection")
-		case "edges":
-			out.Values[i] = ec._Connection_edges(ctx, field, obj)
-		case "pageInfo":
-			out.Values[i] = ec._Connection_pageInfo(ctx, field, obj)
+			out.Values[i] = graphql.MarshalString("Path")
+		case "nodes":
+			out.Values[i] = ec._Path_nodes(ctx, field, obj)
 			if out.Values[i] == graphql.Null {
 				invalid = true
 			}
-		case "totalCount":
-			out.Values[i] = ec._Connection_totalCount(ctx, field, obj)
+		case "edges":
+			out.Values[i] = ec._Path_edges(ctx, field, obj)
 			if out.Values[i] == graphql.Null {
 				invalid = true
 			}
@@ -2892,29 +5856,80 @@ func (ec *executionContext) _Connection(ctx context.Context, sel ast.SelectionSe
 	return out
 }
 
-var edgeImplementors = []string{"Edge"}
+var queryImplementors = []string{"Query"}
 
-func (ec *executionContext) _Edge(ctx context.Context, sel ast.SelectionSet, obj *Edge) graphql.Marshaler {
-	fields := graphql.CollectFields(ec.RequestContext, sel, edgeImplementors)
+func (ec *executionContext) _Query(ctx context.Context, sel ast.SelectionSet) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.RequestContext, sel, queryImplementors)
+
+	ctx = graphql.WithResolverContext(ctx, &graphql.ResolverContext{
+		Object: "Query",
+	})
 
 	out := graphql.NewFieldSet(fields)
 	invalid := false
 	for i, field := range fields {
 		switch field.Name {
 		case "__typename":
-			out.Values[i] = graphql.MarshalString("Edge")
-		case "cursor":
-			out.Values[i] = ec._Edge_cursor(ctx, field, obj)
-			if out.Values[i] == graphql.Null {
-				invalid = true
-			}
+			out.Values[i] = graphql.MarshalString("Query")
+		case "get":
+			field := field
+			out.Concurrently(i, func() (res graphql.Marshaler) {
+				defer func() {
+					if r := recover(); r != nil {
+						ec.Error(ctx, ec.Recover(ctx, r))
+					}
+				}()
+				res = ec._Query_get(ctx, field)
+				return res
+			})
 		case "node":
-			out.Values[i] = ec._Edge_node(ctx, field, obj)
-		case "data":
-			out.Values[i] = ec._Edge_data(ctx, field, obj)
-			if out.Values[i] == graphql.Null {
-				invalid = true
-			}
+			field := field
+			out.Concurrently(i, func() (res graphql.Marshaler) {
+				defer func() {
+					if r := recover(); r != nil {
+						ec.Error(ctx, ec.Recover(ctx, r))
+					}
+				}()
+				res = ec._Query_node(ctx, field)
+				return res
+			})
+		case "nodes":
+			field := field
+			out.Concurrently(i, func() (res graphql.Marshaler) {
+				defer func() {
+					if r := recover(); r != nil {
+						ec.Error(ctx, ec.Recover(ctx, r))
+					}
+				}()
+				res = ec._Query_nodes(ctx, field)
+				return res
+			})
+		case "path":
+			field := field
+			out.Concurrently(i, func() (res graphql.Marshaler) {
+				defer func() {
+					if r := recover(); r != nil {
+						ec.Error(ctx, ec.Recover(ctx, r))
+					}
+				}()
+				res = ec._Query_path(ctx, field)
+				return res
+			})
+		case "neighborhood":
+			field := field
+			out.Concurrently(i, func() (res graphql.Marshaler) {
+				defer func() {
+					if r := recover(); r != nil {
+						ec.Error(ctx, ec.Recover(ctx, r))
+					}
+				}()
+				res = ec._Query_neighborhood(ctx, field)
+				return res
+			})
+		case "__type":
+			out.Values[i] = ec._Query___type(ctx, field)
+		case "__schema":
+			out.Values[i] = ec._Query___schema(ctx, field)
 		default:
 			panic("unknown field " + strconv.Quote(field.Name))
 		}
@@ -2926,24 +5941,19 @@ func (ec *executionContext) _Edge(ctx context.Context, sel ast.SelectionSet, obj
 	return out
 }
 
-var locationImplementors = []string{"Location", "NodeDataItem", "EdgeDataItem"}
+var removeEdgeDataOutputImplementors = []string{"RemoveEdgeDataOutput"}
 
-func (ec *executionContext) _Location(ctx context.Context, sel ast.SelectionSet, obj *Location) graphql.Marshaler {
-	fields := graphql.CollectFields(ec.RequestContext, sel, locationImplementors)
+func (ec *executionContext) _RemoveEdgeDataOutput(ctx context.Context, sel ast.SelectionSet, obj *RemoveEdgeDataOutput) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.RequestContext, sel, removeEdgeDataOutputImplementors)
 
 	out := graphql.NewFieldSet(fields)
 	invalid := false
 	for i, field := range fields {
 		switch field.Name {
 		case "__typename":
-			out.Values[i] = graphql.MarshalString("Location")
-		case "lng":
-			out.Values[i] = ec._Location_lng(ctx, field, obj)
-			if out.Values[i] == graphql.Null {
-				invalid = true
-			}
-		case "lat":
-			out.Values[i] = ec._Location_lat(ctx, field, obj)
+			out.Values[i] = graphql.MarshalString("RemoveEdgeDataOutput")
+		case "removed":
+			out.Values[i] = ec._RemoveEdgeDataOutput_removed(ctx, field, obj)
 			if out.Values[i] == graphql.Null {
 				invalid = true
 			}
@@ -2958,48 +5968,46 @@ func (ec *executionContext) _Location(ctx context.Context, sel ast.SelectionSet,
 	return out
 }
 
-var mutationImplementors = []string{"Mutation"}
-
-func (ec *executionContext) _Mutation(ctx context.Context, sel ast.SelectionSet) graphql.Marshaler {
-	fields := graphql.CollectFields(ec.RequestContext, sel, mutationImplementors)
+var removeEdgeOutputImplementors = []string{"RemoveEdgeOutput"}
 
-	ctx = graphql.WithResolverContext(ctx, &graphql.ResolverContext{
-		Object: "Mutation",
-	})
+func (ec *executionContext) _RemoveEdgeOutput(ctx context.Context, sel ast.SelectionSet, obj *RemoveEdgeOutput) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.RequestContext, sel, removeEdgeOutputImplementors)
 
 	out := graphql.NewFieldSet(fields)
 	invalid := false
 	for i, field := range fields {
 		switch field.Name {
 		case "__typename":
-			out.Values[i] = graphql.MarshalString("Mutation")
-		case "saveNode":
-			out.Values[i] = ec._Mutation_saveNode(ctx, field)
-			if out.Values[i] == graphql.Null {
-				invalid = true
-			}
-		case "saveEdge":
-			out.Values[i] = ec._Mutation_saveEdge(ctx, field)
-			if out.Values[i] == graphql.Null {
-				invalid = true
-			}
-		case "removeNode":
-			out.Values[i] = ec._Mutation_removeNode(ctx, field)
-			if out.Values[i] == graphql.Null {
-				invalid = true
-			}
-		case "removeEdge":
-			out.Values[i] = ec._Mutation_removeEdge(ctx, field)
-			if out.Values[i] == graphql.Null {
-				invalid = true
-			}
-		case "setNodeFields":
-			out.Values[i] = ec._Mutation_setNodeFields(ctx, field)
+			out.Values[i] = graphql.MarshalString("RemoveEdgeOutput")
+		case "removed":
+			out.Values[i] = ec._RemoveEdgeOutput_removed(ctx, field, obj)
 			if out.Values[i] == graphql.Null {
 				invalid = true
 			}
-		case "setEdgeFields":
-			out.Values[i] = ec._Mutation_setEdgeFields(ctx, field)
+		default:
+			panic("unknown field " + strconv.Quote(field.Name))
+		}
+	}
+	out.Dispatch()
+	if invalid {
+		return graphql.Null
+	}
+	return out
+}
+
+var removeNodeDataOutputImplementors = []string{"RemoveNodeDataOutput"}
+
+func (ec *executionContext) _RemoveNodeDataOutput(ctx context.Context, sel ast.SelectionSet, obj *RemoveNodeDataOutput) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.RequestContext, sel, removeNodeDataOutputImplementors)
+
+	out := graphql.NewFieldSet(fields)
+	invalid := false
+	for i, field := range fields {
+		switch field.Name {
+		case "__typename":
+			out.Values[i] = graphql.MarshalString("RemoveNodeDataOutput")
+		case "removed":
+			out.Values[i] = ec._RemoveNodeDataOutput_removed(ctx, field, obj)
 			if out.Values[i] == graphql.Null {
 				invalid = true
 			}
@@ -3014,58 +6022,22 @@ func (ec *executionContext) _Mutation(ctx context.Context, sel ast.SelectionSet)
 	return out
 }
 
-var nodeImplementors = []string{"Node"}
+var removeNodeOutputImplementors = []string{"RemoveNodeOutput"}
 
-func (ec *executionContext) _Node(ctx context.Context, sel ast.SelectionSet, obj *pregel.Node) graphql.Marshaler {
-	fields := graphql.CollectFields(ec.RequestContext, sel, nodeImplementors)
+func (ec *executionContext) _RemoveNodeOutput(ctx context.Context, sel ast.SelectionSet, obj *RemoveNodeOutput) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.RequestContext, sel, removeNodeOutputImplementors)
 
 	out := graphql.NewFieldSet(fields)
 	invalid := false
 	for i, field := range fields {
 		switch field.Name {
 		case "__typename":
-			out.Values[i] = graphql.MarshalString("Node")
-		case "id":
-			out.Values[i] = ec._Node_id(ctx, field, obj)
+			out.Values[i] = graphql.MarshalString("RemoveNodeOutput")
+		case "removed":
+			out.Values[i] = ec._RemoveNodeOutput_removed(ctx, field, obj)
 			if out.Values[i] == graphql.Null {
 				invalid = true
 			}
-		case "parents":
-			field := field
-			out.Concurrently(i, func() (res graphql.Marshaler) {
-				defer func() {
-					if r := recover(); r != nil {
-						ec.Error(ctx, ec.Recover(ctx, r))
-					}
-				}()
-				res = ec._Node_parents(ctx, field, obj)
-				return res
-			})
-		case "children":
-			field := field
-			out.Concurrently(i, func() (res graphql.Marshaler) {
-				defer func() {
-					if r := recover(); r != nil {
-						ec.Error(ctx, ec.Recover(ctx, r))
-					}
-				}()
-				res = ec._Node_children(ctx, field, obj)
-				return res
-			})
-		case "data":
-			field := field
-			out.Concurrently(i, func() (res graphql.Marshaler) {
-				defer func() {
-					if r := recover(); r != nil {
-						ec.Error(ctx, ec.Recover(ctx, r))
-					}
-				}()
-				res = ec._Node_data(ctx, field, obj)
-				if res == graphql.Null {
-					invalid = true
-				}
-				return res
-			})
 		default:
 			panic("unknown field " + strconv.Quote(field.Name))
 		}
@@ -3077,31 +6049,49 @@ func (ec *executionContext) _Node(ctx context.Context, sel ast.SelectionSet, obj
 	return out
 }
 
-var pageInfoImplementors = []string{"PageInfo"}
+var saveEdgeOutputImplementors = []string{"SaveEdgeOutput"}
 
-func (ec *executionContext) _PageInfo(ctx context.Context, sel ast.SelectionSet, obj *PageInfo) graphql.Marshaler {
-	fields := graphql.CollectFields(ec.RequestContext, sel, pageInfoImplementors)
+func (ec *executionContext) _SaveEdgeOutput(ctx context.Context, sel ast.SelectionSet, obj *SaveEdgeOutput) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.RequestContext, sel, saveEdgeOutputImplementors)
 
 	out := graphql.NewFieldSet(fields)
 	invalid := false
 	for i, field := range fields {
 		switch field.Name {
 		case "__typename":
-			out.Values[i] = graphql.MarshalString("PageInfo")
-		case "endCursor":
-			out.Values[i] = ec._PageInfo_endCursor(ctx, field, obj)
-		case "hasNextPage":
-			out.Values[i] = ec._PageInfo_hasNextPage(ctx, field, obj)
+			out.Values[i] = graphql.MarshalString("SaveEdgeOutput")
+		case "parent":
+			out.Values[i] = ec._SaveEdgeOutput_parent(ctx, field, obj)
 			if out.Values[i] == graphql.Null {
 				invalid = true
 			}
-		case "hasPreviousPage":
-			out.Values[i] = ec._PageInfo_hasPreviousPage(ctx, field, obj)
+		case "child":
+			out.Values[i] = ec._SaveEdgeOutput_child(ctx, field, obj)
 			if out.Values[i] == graphql.Null {
 				invalid = true
 			}
-		case "startCursor":
-			out.Values[i] = ec._PageInfo_startCursor(ctx, field, obj)
+		case "parentNode":
+			field := field
+			out.Concurrently(i, func() (res graphql.Marshaler) {
+				defer func() {
+					if r := recover(); r != nil {
+						ec.Error(ctx, ec.Recover(ctx, r))
+					}
+				}()
+				res = ec._SaveEdgeOutput_parentNode(ctx, field, obj)
+				return res
+			})
+		case "childNode":
+			field := field
+			out.Concurrently(i, func() (res graphql.Marshaler) {
+				defer func() {
+					if r := recover(); r != nil {
+						ec.Error(ctx, ec.Recover(ctx, r))
+					}
+				}()
+				res = ec._SaveEdgeOutput_childNode(ctx, field, obj)
+				return res
+			})
 		default:
 			panic("unknown field " + strconv.Quote(field.Name))
 		}
@@ -3113,22 +6103,23 @@ func (ec *executionContext) _PageInfo(ctx context.Context, sel ast.SelectionSet,
 	return out
 }
 
-var queryImplementors = []string{"Query"}
-
-func (ec *executionContext) _Query(ctx context.Context, sel ast.SelectionSet) graphql.Marshaler {
-	fields := graphql.CollectFields(ec.RequestContext, sel, queryImplementors)
+var saveNodeOutputImplementors = []string{"SaveNodeOutput"}
 
-	ctx = graphql.WithResolverContext(ctx, &graphql.ResolverContext{
-		Object: "Query",
-	})
+func (ec *executionContext) _SaveNodeOutput(ctx context.Context, sel ast.SelectionSet, obj *SaveNodeOutput) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.RequestContext, sel, saveNodeOutputImplementors)
 
 	out := graphql.NewFieldSet(fields)
 	invalid := false
 	for i, field := range fields {
 		switch field.Name {
 		case "__typename":
-			out.Values[i] = graphql.MarshalString("Query")
-		case "get":
+			out.Values[i] = graphql.MarshalString("SaveNodeOutput")
+		case "id":
+			out.Values[i] = ec._SaveNodeOutput_id(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				invalid = true
+			}
+		case "node":
 			field := field
 			out.Concurrently(i, func() (res graphql.Marshaler) {
 				defer func() {
@@ -3136,13 +6127,9 @@ func (ec *executionContext) _Query(ctx context.Context, sel ast.SelectionSet) gr
 						ec.Error(ctx, ec.Recover(ctx, r))
 					}
 				}()
-				res = ec._Query_get(ctx, field)
+				res = ec._SaveNodeOutput_node(ctx, field, obj)
 				return res
 			})
-		case "__type":
-			out.Values[i] = ec._Query___type(ctx, field)
-		case "__schema":
-			out.Values[i] = ec._Query___schema(ctx, field)
 		default:
 			panic("unknown field " + strconv.Quote(field.Name))
 		}
@@ -3154,19 +6141,19 @@ func (ec *executionContext) _Query(ctx context.Context, sel ast.SelectionSet) gr
 	return out
 }
 
-var removeEdgeOutputImplementors = []string{"RemoveEdgeOutput"}
+var setEdgeDataOutputImplementors = []string{"SetEdgeDataOutput"}
 
-func (ec *executionContext) _RemoveEdgeOutput(ctx context.Context, sel ast.SelectionSet, obj *RemoveEdgeOutput) graphql.Marshaler {
-	fields := graphql.CollectFields(ec.RequestContext, sel, removeEdgeOutputImplementors)
+func (ec *executionContext) _SetEdgeDataOutput(ctx context.Context, sel ast.SelectionSet, obj *SetEdgeDataOutput) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.RequestContext, sel, setEdgeDataOutputImplementors)
 
 	out := graphql.NewFieldSet(fields)
 	invalid := false
 	for i, field := range fields {
 		switch field.Name {
 		case "__typename":
-			out.Values[i] = graphql.MarshalString("RemoveEdgeOutput")
-		case "removed":
-			out.Values[i] = ec._RemoveEdgeOutput_removed(ctx, field, obj)
+			out.Values[i] = graphql.MarshalString("SetEdgeDataOutput")
+		case "set":
+			out.Values[i] = ec._SetEdgeDataOutput_set(ctx, field, obj)
 			if out.Values[i] == graphql.Null {
 				invalid = true
 			}
@@ -3181,19 +6168,19 @@ func (ec *executionContext) _RemoveEdgeOutput(ctx context.Context, sel ast.Selec
 	return out
 }
 
-var removeNodeOutputImplementors = []string{"RemoveNodeOutput"}
+var setEdgeFieldsOutputImplementors = []string{"SetEdgeFieldsOutput"}
 
-func (ec *executionContext) _RemoveNodeOutput(ctx context.Context, sel ast.SelectionSet, obj *RemoveNodeOutput) graphql.Marshaler {
-	fields := graphql.CollectFields(ec.RequestContext, sel, removeNodeOutputImplementors)
+func (ec *executionContext) _SetEdgeFieldsOutput(ctx context.Context, sel ast.SelectionSet, obj *SetEdgeFieldsOutput) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.RequestContext, sel, setEdgeFieldsOutputImplementors)
 
 	out := graphql.NewFieldSet(fields)
 	invalid := false
 	for i, field := range fields {
 		switch field.Name {
 		case "__typename":
-			out.Values[i] = graphql.MarshalString("RemoveNodeOutput")
-		case "removed":
-			out.Values[i] = ec._RemoveNodeOutput_removed(ctx, field, obj)
+			out.Values[i] = graphql.MarshalString("SetEdgeFieldsOutput")
+		case "set":
+			out.Values[i] = ec._SetEdgeFieldsOutput_set(ctx, field, obj)
 			if out.Values[i] == graphql.Null {
 				invalid = true
 			}
@@ -3208,24 +6195,19 @@ func (ec *executionContext) _RemoveNodeOutput(ctx context.Context, sel ast.Selec
 	return out
 }
 
-var saveEdgeOutputImplementors = []string{"SaveEdgeOutput"}
+var setNodeDataOutputImplementors = []string{"SetNodeDataOutput"}
 
-func (ec *executionContext) _SaveEdgeOutput(ctx context.Context, sel ast.SelectionSet, obj *SaveEdgeOutput) graphql.Marshaler {
-	fields := graphql.CollectFields(ec.RequestContext, sel, saveEdgeOutputImplementors)
+func (ec *executionContext) _SetNodeDataOutput(ctx context.Context, sel ast.SelectionSet, obj *SetNodeDataOutput) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.RequestContext, sel, setNodeDataOutputImplementors)
 
 	out := graphql.NewFieldSet(fields)
 	invalid := false
 	for i, field := range fields {
 		switch field.Name {
 		case "__typename":
-			out.Values[i] = graphql.MarshalString("SaveEdgeOutput")
-		case "parent":
-			out.Values[i] = ec._SaveEdgeOutput_parent(ctx, field, obj)
-			if out.Values[i] == graphql.Null {
-				invalid = true
-			}
-		case "child":
-			out.Values[i] = ec._SaveEdgeOutput_child(ctx, field, obj)
+			out.Values[i] = graphql.MarshalString("SetNodeDataOutput")
+		case "set":
+			out.Values[i] = ec._SetNodeDataOutput_set(ctx, field, obj)
 			if out.Values[i] == graphql.Null {
 				invalid = true
 			}
@@ -3240,22 +6222,38 @@ func (ec *executionContext) _SaveEdgeOutput(ctx context.Context, sel ast.Selecti
 	return out
 }
 
-var saveNodeOutputImplementors = []string{"SaveNodeOutput"}
+var setNodeFieldsOutputImplementors = []string{"SetNodeFieldsOutput"}
 
-func (ec *executionContext) _SaveNodeOutput(ctx context.Context, sel ast.SelectionSet, obj *SaveNodeOutput) graphql.Marshaler {
-	fields := graphql.CollectFields(ec.RequestContext, sel, saveNodeOutputImplementors)
+func (ec *executionContext) _SetNodeFieldsOutput(ctx context.Context, sel ast.SelectionSet, obj *SetNodeFieldsOutput) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.RequestContext, sel, setNodeFieldsOutputImplementors)
 
 	out := graphql.NewFieldSet(fields)
 	invalid := false
 	for i, field := range fields {
 		switch field.Name {
 		case "__typename":
-			out.Values[i] = graphql.MarshalString("SaveNodeOutput")
+			out.Values[i] = graphql.MarshalString("SetNodeFieldsOutput")
+		case "set":
+			out.Values[i] = ec._SetNodeFieldsOutput_set(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				invalid = true
+			}
 		case "id":
-			out.Values[i] = ec._SaveNodeOutput_id(ctx, field, obj)
+			out.Values[i] = ec._SetNodeFieldsOutput_id(ctx, field, obj)
 			if out.Values[i] == graphql.Null {
 				invalid = true
 			}
+		case "node":
+			field := field
+			out.Concurrently(i, func() (res graphql.Marshaler) {
+				defer func() {
+					if r := recover(); r != nil {
+						ec.Error(ctx, ec.Recover(ctx, r))
+					}
+				}()
+				res = ec._SetNodeFieldsOutput_node(ctx, field, obj)
+				return res
+			})
 		default:
 			panic("unknown field " + strconv.Quote(field.Name))
 		}
@@ -3267,19 +6265,29 @@ func (ec *executionContext) _SaveNodeOutput(ctx context.Context, sel ast.Selecti
 	return out
 }
 
-var setEdgeFieldsOutputImplementors = []string{"SetEdgeFieldsOutput"}
+var subgraphImplementors = []string{"Subgraph"}
 
-func (ec *executionContext) _SetEdgeFieldsOutput(ctx context.Context, sel ast.SelectionSet, obj *SetEdgeFieldsOutput) graphql.Marshaler {
-	fields := graphql.CollectFields(ec.RequestContext, sel, setEdgeFieldsOutputImplementors)
+func (ec *executionContext) _Subgraph(ctx context.Context, sel ast.SelectionSet, obj *Subgraph) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.RequestContext, sel, subgraphImplementors)
 
 	out := graphql.NewFieldSet(fields)
 	invalid := false
 	for i, field := range fields {
 		switch field.Name {
 		case "__typename":
-			out.Values[i] = graphql.MarshalString("SetEdgeFieldsOutput")
-		case "set":
-			out.Values[i] = ec._SetEdgeFieldsOutput_set(ctx, field, obj)
+			out.Values[i] = graphql.MarshalString("Subgraph")
+		case "root":
+			out.Values[i] = ec._Subgraph_root(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				invalid = true
+			}
+		case "nodes":
+			out.Values[i] = ec._Subgraph_nodes(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				invalid = true
+			}
+		case "edges":
+			out.Values[i] = ec._Subgraph_edges(ctx, field, obj)
 			if out.Values[i] == graphql.Null {
 				invalid = true
 			}
@@ -3294,19 +6302,31 @@ func (ec *executionContext) _SetEdgeFieldsOutput(ctx context.Context, sel ast.Se
 	return out
 }
 
-var setNodeFieldsOutputImplementors = []string{"SetNodeFieldsOutput"}
+var subgraphEdgeImplementors = []string{"SubgraphEdge"}
 
-func (ec *executionContext) _SetNodeFieldsOutput(ctx context.Context, sel ast.SelectionSet, obj *SetNodeFieldsOutput) graphql.Marshaler {
-	fields := graphql.CollectFields(ec.RequestContext, sel, setNodeFieldsOutputImplementors)
+func (ec *executionContext) _SubgraphEdge(ctx context.Context, sel ast.SelectionSet, obj *SubgraphEdge) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.RequestContext, sel, subgraphEdgeImplementors)
 
 	out := graphql.NewFieldSet(fields)
 	invalid := false
 	for i, field := range fields {
 		switch field.Name {
 		case "__typename":
-			out.Values[i] = graphql.MarshalString("SetNodeFieldsOutput")
-		case "set":
-			out.Values[i] = ec._SetNodeFieldsOutput_set(ctx, field, obj)
+			out.Values[i] = graphql.MarshalString("SubgraphEdge")
+		case "parent":
+			out.Values[i] = ec._SubgraphEdge_parent(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				invalid = true
+			}
+		case "child":
+			out.Values[i] = ec._SubgraphEdge_child(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				invalid = true
+			}
+		case "label":
+			out.Values[i] = ec._SubgraphEdge_label(ctx, field, obj)
+		case "json":
+			out.Values[i] = ec._SubgraphEdge_json(ctx, field, obj)
 			if out.Values[i] == graphql.Null {
 				invalid = true
 			}
@@ -3321,6 +6341,26 @@ func (ec *executionContext) _SetNodeFieldsOutput(ctx context.Context, sel ast.Se
 	return out
 }
 
+var subscriptionImplementors = []string{"Subscription"}
+
+func (ec *executionContext) _Subscription(ctx context.Context, sel ast.SelectionSet) func() graphql.Marshaler {
+	fields := graphql.CollectFields(ec.RequestContext, sel, subscriptionImplementors)
+	ctx = graphql.WithResolverContext(ctx, &graphql.ResolverContext{
+		Object: "Subscription",
+	})
+	if len(fields) != 1 {
+		ec.Errorf(ctx, "must subscribe to exactly one stream")
+		return nil
+	}
+
+	switch fields[0].Name {
+	case "nodeChanged":
+		return ec._Subscription_nodeChanged(ctx, fields[0])
+	default:
+		panic("unknown field " + strconv.Quote(fields[0].Name))
+	}
+}
+
 var __DirectiveImplementors = []string{"__Directive"}
 
 func (ec *executionContext) ___Directive(ctx context.Context, sel ast.SelectionSet, obj *introspection.Directive) graphql.Marshaler {
@@ -3580,10 +6620,61 @@ func (ec *executionContext) marshalNBoolean2bool(ctx context.Context, sel ast.Se
 	return res
 }
 
+func (ec *executionContext) marshalNChangeEvent2githubᚗcomᚋaᚑhᚋpregelᚋgraphᚐChangeEvent(ctx context.Context, sel ast.SelectionSet, v ChangeEvent) graphql.Marshaler {
+	return ec._ChangeEvent(ctx, sel, &v)
+}
+
+func (ec *executionContext) marshalNChangeEvent2ᚖgithubᚗcomᚋaᚑhᚋpregelᚋgraphᚐChangeEvent(ctx context.Context, sel ast.SelectionSet, v *ChangeEvent) graphql.Marshaler {
+	if v == nil {
+		if !ec.HasError(graphql.GetResolverContext(ctx)) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	return ec._ChangeEvent(ctx, sel, v)
+}
+
 func (ec *executionContext) marshalNEdge2githubᚗcomᚋaᚑhᚋpregelᚋgraphᚐEdge(ctx context.Context, sel ast.SelectionSet, v Edge) graphql.Marshaler {
 	return ec._Edge(ctx, sel, &v)
 }
 
+func (ec *executionContext) marshalNEdge2ᚕgithubᚗcomᚋaᚑhᚋpregelᚋgraphᚐEdge(ctx context.Context, sel ast.SelectionSet, v []Edge) graphql.Marshaler {
+	ret := make(graphql.Array, len(v))
+	var wg sync.WaitGroup
+	isLen1 := len(v) == 1
+	if !isLen1 {
+		wg.Add(len(v))
+	}
+	for i := range v {
+		i := i
+		rctx := &graphql.ResolverContext{
+			Index:  &i,
+			Result: &v[i],
+		}
+		ctx := graphql.WithResolverContext(ctx, rctx)
+		f := func(i int) {
+			defer func() {
+				if r := recover(); r != nil {
+					ec.Error(ctx, ec.Recover(ctx, r))
+					ret = nil
+				}
+			}()
+			if !isLen1 {
+				defer wg.Done()
+			}
+			ret[i] = ec.marshalNEdge2githubᚗcomᚋaᚑhᚋpregelᚋgraphᚐEdge(ctx, sel, v[i])
+		}
+		if isLen1 {
+			f(i)
+		} else {
+			go f(i)
+		}
+
+	}
+	wg.Wait()
+	return ret
+}
+
 func (ec *executionContext) marshalNEdgeDataItem2ᚕgithubᚗcomᚋaᚑhᚋpregelᚋgraphᚐEdgeDataItem(ctx context.Context, sel ast.SelectionSet, v []EdgeDataItem) graphql.Marshaler {
 	ret := make(graphql.Array, len(v))
 	var wg sync.WaitGroup
@@ -3617,16 +6708,118 @@ func (ec *executionContext) marshalNEdgeDataItem2ᚕgithubᚗcomᚋaᚑhᚋprege
 		}
 
 	}
-	wg.Wait()
-	return ret
+	wg.Wait()
+	return ret
+}
+
+func (ec *executionContext) unmarshalNEdgeOrderField2githubᚗcomᚋaᚑhᚋpregelᚋgraphᚐEdgeOrderField(ctx context.Context, v interface{}) (EdgeOrderField, error) {
+	var res EdgeOrderField
+	return res, res.UnmarshalGQL(v)
+}
+
+func (ec *executionContext) marshalNEdgeOrderField2githubᚗcomᚋaᚑhᚋpregelᚋgraphᚐEdgeOrderField(ctx context.Context, sel ast.SelectionSet, v EdgeOrderField) graphql.Marshaler {
+	return v
+}
+
+func (ec *executionContext) unmarshalNFloat2float64(ctx context.Context, v interface{}) (float64, error) {
+	return graphql.UnmarshalFloat(v)
+}
+
+func (ec *executionContext) marshalNFloat2float64(ctx context.Context, sel ast.SelectionSet, v float64) graphql.Marshaler {
+	res := graphql.MarshalFloat(v)
+	if res == graphql.Null {
+		if !ec.HasError(graphql.GetResolverContext(ctx)) {
+			ec.Errorf(ctx, "must not be null")
+		}
+	}
+	return res
+}
+
+func (ec *executionContext) marshalNGraphNode2githubᚗcomᚋaᚑhᚋpregelᚐNode(ctx context.Context, sel ast.SelectionSet, v pregel.Node) graphql.Marshaler {
+	return ec._GraphNode(ctx, sel, &v)
+}
+
+func (ec *executionContext) marshalNGraphNode2ᚕᚖgithubᚗcomᚋaᚑhᚋpregelᚐNode(ctx context.Context, sel ast.SelectionSet, v []*pregel.Node) graphql.Marshaler {
+	ret := make(graphql.Array, len(v))
+	var wg sync.WaitGroup
+	isLen1 := len(v) == 1
+	if !isLen1 {
+		wg.Add(len(v))
+	}
+	for i := range v {
+		i := i
+		rctx := &graphql.ResolverContext{
+			Index:  &i,
+			Result: v[i],
+		}
+		ctx := graphql.WithResolverContext(ctx, rctx)
+		f := func(i int) {
+			defer func() {
+				if r := recover(); r != nil {
+					ec.Error(ctx, ec.Recover(ctx, r))
+					ret = nil
+				}
+			}()
+			if !isLen1 {
+				defer wg.Done()
+			}
+			ret[i] = ec.marshalNGraphNode2ᚖgithubᚗcomᚋaᚑhᚋpregelᚐNode(ctx, sel, v[i])
+		}
+		if isLen1 {
+			f(i)
+		} else {
+			go f(i)
+		}
+
+	}
+	wg.Wait()
+	return ret
+}
+
+func (ec *executionContext) marshalNGraphNode2ᚖgithubᚗcomᚋaᚑhᚋpregelᚐNode(ctx context.Context, sel ast.SelectionSet, v *pregel.Node) graphql.Marshaler {
+	if v == nil {
+		if !ec.HasError(graphql.GetResolverContext(ctx)) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	return ec._GraphNode(ctx, sel, v)
+}
+
+func (ec *executionContext) unmarshalNID2string(ctx context.Context, v interface{}) (string, error) {
+	return graphql.UnmarshalID(v)
+}
+
+func (ec *executionContext) marshalNID2string(ctx context.Context, sel ast.SelectionSet, v string) graphql.Marshaler {
+	res := graphql.MarshalID(v)
+	if res == graphql.Null {
+		if !ec.HasError(graphql.GetResolverContext(ctx)) {
+			ec.Errorf(ctx, "must not be null")
+		}
+	}
+	return res
+}
+
+func (ec *executionContext) marshalNImportGraphOutput2githubᚗcomᚋaᚑhᚋpregelᚋgraphᚐImportGraphOutput(ctx context.Context, sel ast.SelectionSet, v ImportGraphOutput) graphql.Marshaler {
+	return ec._ImportGraphOutput(ctx, sel, &v)
+}
+
+func (ec *executionContext) marshalNImportGraphOutput2ᚖgithubᚗcomᚋaᚑhᚋpregelᚋgraphᚐImportGraphOutput(ctx context.Context, sel ast.SelectionSet, v *ImportGraphOutput) graphql.Marshaler {
+	if v == nil {
+		if !ec.HasError(graphql.GetResolverContext(ctx)) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	return ec._ImportGraphOutput(ctx, sel, v)
 }
 
-func (ec *executionContext) unmarshalNFloat2float64(ctx context.Context, v interface{}) (float64, error) {
-	return graphql.UnmarshalFloat(v)
+func (ec *executionContext) unmarshalNInt2int(ctx context.Context, v interface{}) (int, error) {
+	return graphql.UnmarshalInt(v)
 }
 
-func (ec *executionContext) marshalNFloat2float64(ctx context.Context, sel ast.SelectionSet, v float64) graphql.Marshaler {
-	res := graphql.MarshalFloat(v)
+func (ec *executionContext) marshalNInt2int(ctx context.Context, sel ast.SelectionSet, v int) graphql.Marshaler {
+	res := graphql.MarshalInt(v)
 	if res == graphql.Null {
 		if !ec.HasError(graphql.GetResolverContext(ctx)) {
 			ec.Errorf(ctx, "must not be null")
@@ -3635,32 +6828,45 @@ func (ec *executionContext) marshalNFloat2float64(ctx context.Context, sel ast.S
 	return res
 }
 
-func (ec *executionContext) unmarshalNID2string(ctx context.Context, v interface{}) (string, error) {
-	return graphql.UnmarshalID(v)
+func (ec *executionContext) unmarshalNJSON2map(ctx context.Context, v interface{}) (map[string]interface{}, error) {
+	if v == nil {
+		return nil, nil
+	}
+	return graphql.UnmarshalMap(v)
 }
 
-func (ec *executionContext) marshalNID2string(ctx context.Context, sel ast.SelectionSet, v string) graphql.Marshaler {
-	res := graphql.MarshalID(v)
-	if res == graphql.Null {
+func (ec *executionContext) marshalNJSON2map(ctx context.Context, sel ast.SelectionSet, v map[string]interface{}) graphql.Marshaler {
+	if v == nil {
 		if !ec.HasError(graphql.GetResolverContext(ctx)) {
 			ec.Errorf(ctx, "must not be null")
 		}
+		return graphql.Null
 	}
-	return res
+	return graphql.MarshalMap(v)
 }
 
-func (ec *executionContext) unmarshalNInt2int(ctx context.Context, v interface{}) (int, error) {
-	return graphql.UnmarshalInt(v)
+func (ec *executionContext) marshalNLocation2githubᚗcomᚋaᚑhᚋpregelᚋgraphᚐLocation(ctx context.Context, sel ast.SelectionSet, v Location) graphql.Marshaler {
+	return ec._Location(ctx, sel, &v)
 }
 
-func (ec *executionContext) marshalNInt2int(ctx context.Context, sel ast.SelectionSet, v int) graphql.Marshaler {
-	res := graphql.MarshalInt(v)
-	if res == graphql.Null {
+func (ec *executionContext) marshalNLocation2ᚖgithubᚗcomᚋaᚑhᚋpregelᚋgraphᚐLocation(ctx context.Context, sel ast.SelectionSet, v *Location) graphql.Marshaler {
+	if v == nil {
 		if !ec.HasError(graphql.GetResolverContext(ctx)) {
 			ec.Errorf(ctx, "must not be null")
 		}
+		return graphql.Null
 	}
-	return res
+	return ec._Location(ctx, sel, v)
+}
+
+func (ec *executionContext) marshalNNode2githubᚗcomᚋaᚑhᚋpregelᚋgraphᚐRelayNode(ctx context.Context, sel ast.SelectionSet, v RelayNode) graphql.Marshaler {
+	if v == nil {
+		if !ec.HasError(graphql.GetResolverContext(ctx)) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	return ec._Node(ctx, sel, &v)
 }
 
 func (ec *executionContext) marshalNNodeDataItem2ᚕgithubᚗcomᚋaᚑhᚋpregelᚋgraphᚐNodeDataItem(ctx context.Context, sel ast.SelectionSet, v []NodeDataItem) graphql.Marshaler {
@@ -3704,6 +6910,24 @@ func (ec *executionContext) marshalNPageInfo2githubᚗcomᚋaᚑhᚋpregelᚋgra
 	return ec._PageInfo(ctx, sel, &v)
 }
 
+func (ec *executionContext) unmarshalNRemoveEdgeDataInput2githubᚗcomᚋaᚑhᚋpregelᚋgraphᚐRemoveEdgeDataInput(ctx context.Context, v interface{}) (RemoveEdgeDataInput, error) {
+	return ec.unmarshalInputRemoveEdgeDataInput(ctx, v)
+}
+
+func (ec *executionContext) marshalNRemoveEdgeDataOutput2githubᚗcomᚋaᚑhᚋpregelᚋgraphᚐRemoveEdgeDataOutput(ctx context.Context, sel ast.SelectionSet, v RemoveEdgeDataOutput) graphql.Marshaler {
+	return ec._RemoveEdgeDataOutput(ctx, sel, &v)
+}
+
+func (ec *executionContext) marshalNRemoveEdgeDataOutput2ᚖgithubᚗcomᚋaᚑhᚋpregelᚋgraphᚐRemoveEdgeDataOutput(ctx context.Context, sel ast.SelectionSet, v *RemoveEdgeDataOutput) graphql.Marshaler {
+	if v == nil {
+		if !ec.HasError(graphql.GetResolverContext(ctx)) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	return ec._RemoveEdgeDataOutput(ctx, sel, v)
+}
+
 func (ec *executionContext) unmarshalNRemoveEdgeInput2githubᚗcomᚋaᚑhᚋpregelᚋgraphᚐRemoveEdgeInput(ctx context.Context, v interface{}) (RemoveEdgeInput, error) {
 	return ec.unmarshalInputRemoveEdgeInput(ctx, v)
 }
@@ -3722,6 +6946,24 @@ func (ec *executionContext) marshalNRemoveEdgeOutput2ᚖgithubᚗcomᚋaᚑhᚋp
 	return ec._RemoveEdgeOutput(ctx, sel, v)
 }
 
+func (ec *executionContext) unmarshalNRemoveNodeDataInput2githubᚗcomᚋaᚑhᚋpregelᚋgraphᚐRemoveNodeDataInput(ctx context.Context, v interface{}) (RemoveNodeDataInput, error) {
+	return ec.unmarshalInputRemoveNodeDataInput(ctx, v)
+}
+
+func (ec *executionContext) marshalNRemoveNodeDataOutput2githubᚗcomᚋaᚑhᚋpregelᚋgraphᚐRemoveNodeDataOutput(ctx context.Context, sel ast.SelectionSet, v RemoveNodeDataOutput) graphql.Marshaler {
+	return ec._RemoveNodeDataOutput(ctx, sel, &v)
+}
+
+func (ec *executionContext) marshalNRemoveNodeDataOutput2ᚖgithubᚗcomᚋaᚑhᚋpregelᚋgraphᚐRemoveNodeDataOutput(ctx context.Context, sel ast.SelectionSet, v *RemoveNodeDataOutput) graphql.Marshaler {
+	if v == nil {
+		if !ec.HasError(graphql.GetResolverContext(ctx)) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	return ec._RemoveNodeDataOutput(ctx, sel, v)
+}
+
 func (ec *executionContext) unmarshalNRemoveNodeInput2githubᚗcomᚋaᚑhᚋpregelᚋgraphᚐRemoveNodeInput(ctx context.Context, v interface{}) (RemoveNodeInput, error) {
 	return ec.unmarshalInputRemoveNodeInput(ctx, v)
 }
@@ -3776,6 +7018,24 @@ func (ec *executionContext) marshalNSaveNodeOutput2ᚖgithubᚗcomᚋaᚑhᚋpre
 	return ec._SaveNodeOutput(ctx, sel, v)
 }
 
+func (ec *executionContext) unmarshalNSetEdgeDataInput2githubᚗcomᚋaᚑhᚋpregelᚋgraphᚐSetEdgeDataInput(ctx context.Context, v interface{}) (SetEdgeDataInput, error) {
+	return ec.unmarshalInputSetEdgeDataInput(ctx, v)
+}
+
+func (ec *executionContext) marshalNSetEdgeDataOutput2githubᚗcomᚋaᚑhᚋpregelᚋgraphᚐSetEdgeDataOutput(ctx context.Context, sel ast.SelectionSet, v SetEdgeDataOutput) graphql.Marshaler {
+	return ec._SetEdgeDataOutput(ctx, sel, &v)
+}
+
+func (ec *executionContext) marshalNSetEdgeDataOutput2ᚖgithubᚗcomᚋaᚑhᚋpregelᚋgraphᚐSetEdgeDataOutput(ctx context.Context, sel ast.SelectionSet, v *SetEdgeDataOutput) graphql.Marshaler {
+	if v == nil {
+		if !ec.HasError(graphql.GetResolverContext(ctx)) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	return ec._SetEdgeDataOutput(ctx, sel, v)
+}
+
 func (ec *executionContext) unmarshalNSetEdgeFieldsInput2githubᚗcomᚋaᚑhᚋpregelᚋgraphᚐSetEdgeFieldsInput(ctx context.Context, v interface{}) (SetEdgeFieldsInput, error) {
 	return ec.unmarshalInputSetEdgeFieldsInput(ctx, v)
 }
@@ -3794,6 +7054,24 @@ func (ec *executionContext) marshalNSetEdgeFieldsOutput2ᚖgithubᚗcomᚋaᚑh
 	return ec._SetEdgeFieldsOutput(ctx, sel, v)
 }
 
+func (ec *executionContext) unmarshalNSetNodeDataInput2githubᚗcomᚋaᚑhᚋpregelᚋgraphᚐSetNodeDataInput(ctx context.Context, v interface{}) (SetNodeDataInput, error) {
+	return ec.unmarshalInputSetNodeDataInput(ctx, v)
+}
+
+func (ec *executionContext) marshalNSetNodeDataOutput2githubᚗcomᚋaᚑhᚋpregelᚋgraphᚐSetNodeDataOutput(ctx context.Context, sel ast.SelectionSet, v SetNodeDataOutput) graphql.Marshaler {
+	return ec._SetNodeDataOutput(ctx, sel, &v)
+}
+
+func (ec *executionContext) marshalNSetNodeDataOutput2ᚖgithubᚗcomᚋaᚑhᚋpregelᚋgraphᚐSetNodeDataOutput(ctx context.Context, sel ast.SelectionSet, v *SetNodeDataOutput) graphql.Marshaler {
+	if v == nil {
+		if !ec.HasError(graphql.GetResolverContext(ctx)) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	return ec._SetNodeDataOutput(ctx, sel, v)
+}
+
 func (ec *executionContext) unmarshalNSetNodeFieldsInput2githubᚗcomᚋaᚑhᚋpregelᚋgraphᚐSetNodeFieldsInput(ctx context.Context, v interface{}) (SetNodeFieldsInput, error) {
 	return ec.unmarshalInputSetNodeFieldsInput(ctx, v)
 }
@@ -3826,6 +7104,61 @@ func (ec *executionContext) marshalNString2string(ctx context.Context, sel ast.S
 	return res
 }
 
+func (ec *executionContext) marshalNSubgraphEdge2githubᚗcomᚋaᚑhᚋpregelᚋgraphᚐSubgraphEdge(ctx context.Context, sel ast.SelectionSet, v SubgraphEdge) graphql.Marshaler {
+	return ec._SubgraphEdge(ctx, sel, &v)
+}
+
+func (ec *executionContext) marshalNSubgraphEdge2ᚕgithubᚗcomᚋaᚑhᚋpregelᚋgraphᚐSubgraphEdge(ctx context.Context, sel ast.SelectionSet, v []SubgraphEdge) graphql.Marshaler {
+	ret := make(graphql.Array, len(v))
+	var wg sync.WaitGroup
+	isLen1 := len(v) == 1
+	if !isLen1 {
+		wg.Add(len(v))
+	}
+	for i := range v {
+		i := i
+		rctx := &graphql.ResolverContext{
+			Index:  &i,
+			Result: &v[i],
+		}
+		ctx := graphql.WithResolverContext(ctx, rctx)
+		f := func(i int) {
+			defer func() {
+				if r := recover(); r != nil {
+					ec.Error(ctx, ec.Recover(ctx, r))
+					ret = nil
+				}
+			}()
+			if !isLen1 {
+				defer wg.Done()
+			}
+			ret[i] = ec.marshalNSubgraphEdge2githubᚗcomᚋaᚑhᚋpregelᚋgraphᚐSubgraphEdge(ctx, sel, v[i])
+		}
+		if isLen1 {
+			f(i)
+		} else {
+			go f(i)
+		}
+
+	}
+	wg.Wait()
+	return ret
+}
+
+func (ec *executionContext) unmarshalNUpload2githubᚗcomᚋ99designsᚋgqlgenᚋgraphqlᚐUpload(ctx context.Context, v interface{}) (graphql.Upload, error) {
+	return graphql.UnmarshalUpload(v)
+}
+
+func (ec *executionContext) marshalNUpload2githubᚗcomᚋ99designsᚋgqlgenᚋgraphqlᚐUpload(ctx context.Context, sel ast.SelectionSet, v graphql.Upload) graphql.Marshaler {
+	res := graphql.MarshalUpload(v)
+	if res == graphql.Null {
+		if !ec.HasError(graphql.GetResolverContext(ctx)) {
+			ec.Errorf(ctx, "must not be null")
+		}
+	}
+	return res
+}
+
 func (ec *executionContext) marshalN__Directive2githubᚗcomᚋ99designsᚋgqlgenᚋgraphqlᚋintrospectionᚐDirective(ctx context.Context, sel ast.SelectionSet, v introspection.Directive) graphql.Marshaler {
 	return ec.___Directive(ctx, sel, &v)
 }
@@ -4086,6 +7419,53 @@ func (ec *executionContext) marshalOConnection2ᚖgithubᚗcomᚋaᚑhᚋpregel
 	return ec._Connection(ctx, sel, v)
 }
 
+func (ec *executionContext) unmarshalODateTime2timeᚐTime(ctx context.Context, v interface{}) (time.Time, error) {
+	return graphql.UnmarshalTime(v)
+}
+
+func (ec *executionContext) marshalODateTime2timeᚐTime(ctx context.Context, sel ast.SelectionSet, v time.Time) graphql.Marshaler {
+	return graphql.MarshalTime(v)
+}
+
+func (ec *executionContext) unmarshalODateTime2ᚖtimeᚐTime(ctx context.Context, v interface{}) (*time.Time, error) {
+	if v == nil {
+		return nil, nil
+	}
+	res, err := ec.unmarshalODateTime2timeᚐTime(ctx, v)
+	return &res, err
+}
+
+func (ec *executionContext) marshalODateTime2ᚖtimeᚐTime(ctx context.Context, sel ast.SelectionSet, v *time.Time) graphql.Marshaler {
+	if v == nil {
+		return graphql.Null
+	}
+	return ec.marshalODateTime2timeᚐTime(ctx, sel, *v)
+}
+
+func (ec *executionContext) unmarshalODirection2githubᚗcomᚋaᚑhᚋpregelᚋgraphᚐDirection(ctx context.Context, v interface{}) (Direction, error) {
+	var res Direction
+	return res, res.UnmarshalGQL(v)
+}
+
+func (ec *executionContext) marshalODirection2githubᚗcomᚋaᚑhᚋpregelᚋgraphᚐDirection(ctx context.Context, sel ast.SelectionSet, v Direction) graphql.Marshaler {
+	return v
+}
+
+func (ec *executionContext) unmarshalODirection2ᚖgithubᚗcomᚋaᚑhᚋpregelᚋgraphᚐDirection(ctx context.Context, v interface{}) (*Direction, error) {
+	if v == nil {
+		return nil, nil
+	}
+	res, err := ec.unmarshalODirection2githubᚗcomᚋaᚑhᚋpregelᚋgraphᚐDirection(ctx, v)
+	return &res, err
+}
+
+func (ec *executionContext) marshalODirection2ᚖgithubᚗcomᚋaᚑhᚋpregelᚋgraphᚐDirection(ctx context.Context, sel ast.SelectionSet, v *Direction) graphql.Marshaler {
+	if v == nil {
+		return graphql.Null
+	}
+	return v
+}
+
 func (ec *executionContext) marshalOEdge2ᚕgithubᚗcomᚋaᚑhᚋpregelᚋgraphᚐEdge(ctx context.Context, sel ast.SelectionSet, v []Edge) graphql.Marshaler {
 	if v == nil {
 		return graphql.Null
@@ -4130,6 +7510,37 @@ func (ec *executionContext) marshalOEdgeDataItem2githubᚗcomᚋaᚑhᚋpregel
 	return ec._EdgeDataItem(ctx, sel, &v)
 }
 
+func (ec *executionContext) unmarshalOEdgeOrder2githubᚗcomᚋaᚑhᚋpregelᚋgraphᚐEdgeOrder(ctx context.Context, v interface{}) (EdgeOrder, error) {
+	return ec.unmarshalInputEdgeOrder(ctx, v)
+}
+
+func (ec *executionContext) unmarshalOEdgeOrder2ᚖgithubᚗcomᚋaᚑhᚋpregelᚋgraphᚐEdgeOrder(ctx context.Context, v interface{}) (*EdgeOrder, error) {
+	if v == nil {
+		return nil, nil
+	}
+	res, err := ec.unmarshalOEdgeOrder2githubᚗcomᚋaᚑhᚋpregelᚋgraphᚐEdgeOrder(ctx, v)
+	return &res, err
+}
+
+func (ec *executionContext) marshalOGraphNode2githubᚗcomᚋaᚑhᚋpregelᚐNode(ctx context.Context, sel ast.SelectionSet, v pregel.Node) graphql.Marshaler {
+	return ec._GraphNode(ctx, sel, &v)
+}
+
+func (ec *executionContext) marshalOGraphNode2ᚖgithubᚗcomᚋaᚑhᚋpregelᚐNode(ctx context.Context, sel ast.SelectionSet, v *pregel.Node) graphql.Marshaler {
+	if v == nil {
+		return graphql.Null
+	}
+	return ec._GraphNode(ctx, sel, v)
+}
+
+func (ec *executionContext) unmarshalOID2string(ctx context.Context, v interface{}) (string, error) {
+	return graphql.UnmarshalID(v)
+}
+
+func (ec *executionContext) marshalOID2string(ctx context.Context, sel ast.SelectionSet, v string) graphql.Marshaler {
+	return graphql.MarshalID(v)
+}
+
 func (ec *executionContext) unmarshalOID2ᚕstring(ctx context.Context, v interface{}) ([]string, error) {
 	var vSlice []interface{}
 	if v != nil {
@@ -4162,6 +7573,58 @@ func (ec *executionContext) marshalOID2ᚕstring(ctx context.Context, sel ast.Se
 	return ret
 }
 
+func (ec *executionContext) unmarshalOID2ᚖstring(ctx context.Context, v interface{}) (*string, error) {
+	if v == nil {
+		return nil, nil
+	}
+	res, err := ec.unmarshalOID2string(ctx, v)
+	return &res, err
+}
+
+func (ec *executionContext) marshalOID2ᚖstring(ctx context.Context, sel ast.SelectionSet, v *string) graphql.Marshaler {
+	if v == nil {
+		return graphql.Null
+	}
+	return ec.marshalOID2string(ctx, sel, *v)
+}
+
+func (ec *executionContext) unmarshalOInt2int(ctx context.Context, v interface{}) (int, error) {
+	return graphql.UnmarshalInt(v)
+}
+
+func (ec *executionContext) marshalOInt2int(ctx context.Context, sel ast.SelectionSet, v int) graphql.Marshaler {
+	return graphql.MarshalInt(v)
+}
+
+func (ec *executionContext) unmarshalOInt2ᚖint(ctx context.Context, v interface{}) (*int, error) {
+	if v == nil {
+		return nil, nil
+	}
+	res, err := ec.unmarshalOInt2int(ctx, v)
+	return &res, err
+}
+
+func (ec *executionContext) marshalOInt2ᚖint(ctx context.Context, sel ast.SelectionSet, v *int) graphql.Marshaler {
+	if v == nil {
+		return graphql.Null
+	}
+	return ec.marshalOInt2int(ctx, sel, *v)
+}
+
+func (ec *executionContext) unmarshalOJSON2map(ctx context.Context, v interface{}) (map[string]interface{}, error) {
+	if v == nil {
+		return nil, nil
+	}
+	return graphql.UnmarshalMap(v)
+}
+
+func (ec *executionContext) marshalOJSON2map(ctx context.Context, sel ast.SelectionSet, v map[string]interface{}) graphql.Marshaler {
+	if v == nil {
+		return graphql.Null
+	}
+	return graphql.MarshalMap(v)
+}
+
 func (ec *executionContext) unmarshalOLocationInput2githubᚗcomᚋaᚑhᚋpregelᚋgraphᚐLocationInput(ctx context.Context, v interface{}) (LocationInput, error) {
 	return ec.unmarshalInputLocationInput(ctx, v)
 }
@@ -4174,19 +7637,59 @@ func (ec *executionContext) unmarshalOLocationInput2ᚖgithubᚗcomᚋaᚑhᚋpr
 	return &res, err
 }
 
-func (ec *executionContext) marshalONode2githubᚗcomᚋaᚑhᚋpregelᚐNode(ctx context.Context, sel ast.SelectionSet, v pregel.Node) graphql.Marshaler {
+func (ec *executionContext) marshalONode2githubᚗcomᚋaᚑhᚋpregelᚋgraphᚐRelayNode(ctx context.Context, sel ast.SelectionSet, v RelayNode) graphql.Marshaler {
 	return ec._Node(ctx, sel, &v)
 }
 
-func (ec *executionContext) marshalONode2ᚖgithubᚗcomᚋaᚑhᚋpregelᚐNode(ctx context.Context, sel ast.SelectionSet, v *pregel.Node) graphql.Marshaler {
+func (ec *executionContext) marshalONodeDataItem2githubᚗcomᚋaᚑhᚋpregelᚋgraphᚐNodeDataItem(ctx context.Context, sel ast.SelectionSet, v NodeDataItem) graphql.Marshaler {
+	return ec._NodeDataItem(ctx, sel, &v)
+}
+
+func (ec *executionContext) unmarshalONodeFilter2githubᚗcomᚋaᚑhᚋpregelᚋgraphᚐNodeFilter(ctx context.Context, v interface{}) (NodeFilter, error) {
+	return ec.unmarshalInputNodeFilter(ctx, v)
+}
+
+func (ec *executionContext) unmarshalONodeFilter2ᚖgithubᚗcomᚋaᚑhᚋpregelᚋgraphᚐNodeFilter(ctx context.Context, v interface{}) (*NodeFilter, error) {
+	if v == nil {
+		return nil, nil
+	}
+	res, err := ec.unmarshalONodeFilter2githubᚗcomᚋaᚑhᚋpregelᚋgraphᚐNodeFilter(ctx, v)
+	return &res, err
+}
+
+func (ec *executionContext) unmarshalOOrderDirection2githubᚗcomᚋaᚑhᚋpregelᚋgraphᚐOrderDirection(ctx context.Context, v interface{}) (OrderDirection, error) {
+	var res OrderDirection
+	return res, res.UnmarshalGQL(v)
+}
+
+func (ec *executionContext) marshalOOrderDirection2githubᚗcomᚋaᚑhᚋpregelᚋgraphᚐOrderDirection(ctx context.Context, sel ast.SelectionSet, v OrderDirection) graphql.Marshaler {
+	return v
+}
+
+func (ec *executionContext) unmarshalOOrderDirection2ᚖgithubᚗcomᚋaᚑhᚋpregelᚋgraphᚐOrderDirection(ctx context.Context, v interface{}) (*OrderDirection, error) {
+	if v == nil {
+		return nil, nil
+	}
+	res, err := ec.unmarshalOOrderDirection2githubᚗcomᚋaᚑhᚋpregelᚋgraphᚐOrderDirection(ctx, v)
+	return &res, err
+}
+
+func (ec *executionContext) marshalOOrderDirection2ᚖgithubᚗcomᚋaᚑhᚋpregelᚋgraphᚐOrderDirection(ctx context.Context, sel ast.SelectionSet, v *OrderDirection) graphql.Marshaler {
 	if v == nil {
 		return graphql.Null
 	}
-	return ec._Node(ctx, sel, v)
+	return v
 }
 
-func (ec *executionContext) marshalONodeDataItem2githubᚗcomᚋaᚑhᚋpregelᚋgraphᚐNodeDataItem(ctx context.Context, sel ast.SelectionSet, v NodeDataItem) graphql.Marshaler {
-	return ec._NodeDataItem(ctx, sel, &v)
+func (ec *executionContext) marshalOPath2githubᚗcomᚋaᚑhᚋpregelᚋgraphᚐPath(ctx context.Context, sel ast.SelectionSet, v Path) graphql.Marshaler {
+	return ec._Path(ctx, sel, &v)
+}
+
+func (ec *executionContext) marshalOPath2ᚖgithubᚗcomᚋaᚑhᚋpregelᚋgraphᚐPath(ctx context.Context, sel ast.SelectionSet, v *Path) graphql.Marshaler {
+	if v == nil {
+		return graphql.Null
+	}
+	return ec._Path(ctx, sel, v)
 }
 
 func (ec *executionContext) unmarshalOString2string(ctx context.Context, v interface{}) (string, error) {
@@ -4212,6 +7715,17 @@ func (ec *executionContext) marshalOString2ᚖstring(ctx context.Context, sel as
 	return ec.marshalOString2string(ctx, sel, *v)
 }
 
+func (ec *executionContext) marshalOSubgraph2githubᚗcomᚋaᚑhᚋpregelᚋgraphᚐSubgraph(ctx context.Context, sel ast.SelectionSet, v Subgraph) graphql.Marshaler {
+	return ec._Subgraph(ctx, sel, &v)
+}
+
+func (ec *executionContext) marshalOSubgraph2ᚖgithubᚗcomᚋaᚑhᚋpregelᚋgraphᚐSubgraph(ctx context.Context, sel ast.SelectionSet, v *Subgraph) graphql.Marshaler {
+	if v == nil {
+		return graphql.Null
+	}
+	return ec._Subgraph(ctx, sel, v)
+}
+
 func (ec *executionContext) marshalO__EnumValue2ᚕgithubᚗcomᚋ99designsᚋgqlgenᚋgraphqlᚋintrospectionᚐEnumValue(ctx context.Context, sel ast.SelectionSet, v []introspection.EnumValue) graphql.Marshaler {
 	if v == nil {
 		return graphql.Null
@@ -1,6 +1,10 @@
 package gqlid
 
-import "encoding/base64"
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+)
 
 // Encode an input string to a GraphQL ID.
 func Encode(s string) string {
@@ -14,3 +18,27 @@ func Decode(s string) (id string, err error) {
 	id = string(b)
 	return
 }
+
+// ErrInvalidGlobalID is returned when a global ID isn't an encoded "type:id" pair.
+var ErrInvalidGlobalID = errors.New("gqlid: invalid global ID")
+
+// EncodeGlobal encodes the name of a GraphQL type and an ID into a global ID, which is unique
+// across all types, as used by the Relay node query.
+func EncodeGlobal(typeName, id string) string {
+	return Encode(typeName + ":" + id)
+}
+
+// DecodeGlobal decodes a global ID created by EncodeGlobal into the name of the type and the ID.
+func DecodeGlobal(s string) (typeName, id string, err error) {
+	v, err := Decode(s)
+	if err != nil {
+		err = ErrInvalidGlobalID
+		return
+	}
+	i := strings.Index(v, ":")
+	if i < 1 {
+		err = ErrInvalidGlobalID
+		return
+	}
+	return v[:i], v[i+1:], nil
+}
@@ -1,34 +1,28 @@
 package main
 
 import (
-	"fmt"
 	"log"
 	"net/http"
-	"os"
+	"time"
 
 	"github.com/99designs/gqlgen/handler"
 	"github.com/a-h/pregel"
+	"github.com/a-h/pregel/db"
 	"github.com/a-h/pregel/graph"
+	"github.com/a-h/pregel/graph/config"
 	"github.com/akrylysov/algnhsa"
 )
 
 func main() {
-	region := os.Getenv("PREGEL_DYNAMO_REGION")
-	shouldQuit := false
-	if region == "" {
-		fmt.Println("PREGEL_DYNAMO_REGION not set")
-		shouldQuit = true
-	}
-	tableName := os.Getenv("PREGEL_DYNAMO_TABLE_NAME")
-	if tableName == "" {
-		fmt.Println("PREGEL_DYNAMO_TABLE_NAME is not set")
-		shouldQuit = true
+	c, err := config.FromEnvironment(config.Default())
+	if err != nil {
+		log.Fatal(err)
 	}
-	if shouldQuit {
-		os.Exit(1)
+	if err = c.Require(config.Region, config.TableName); err != nil {
+		log.Fatal(err)
 	}
 
-	store, err := pregel.NewStore(region, tableName)
+	store, err := pregel.NewStore(c.Region, c.TableName)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -36,20 +30,65 @@ func main() {
 		return &graph.Location{}
 	})
 
-	http.Handle("/", handler.Playground("GraphQL playground", "/query"))
+	if c.Playground {
+		http.Handle("/", handler.Playground("GraphQL playground", "/query"))
+	}
 	root := &graph.Resolver{
 		MutationResolver: &graph.PregelMutationResolver{
-			Store: store,
+			Store:     store,
+			DataTypes: store.DataTypes,
 		},
 		NodeResolver:  &graph.PregelNodeResolver{},
-		QueryResolver: &graph.PregelQueryResolver{},
+		QueryResolver: &graph.PregelQueryResolver{Store: store},
+	}
+
+	// Callers need a JWT signed with the PREGEL_JWT_SECRET, with the pregel:read scope to run queries,
+	// the pregel:write scope to run mutations, and the pregel:admin scope to import graphs, or the
+	// PREGEL_API_KEY, which can do all three.
+	authenticate, err := c.Authenticator()
+	if err != nil {
+		log.Fatal(err)
 	}
+	root = graph.NewAuthorizedResolver(root, graph.RequireAdminScope("pregel:admin", graph.RequireScopes("pregel:read", "pregel:write")))
 
-	h := handler.GraphQL(graph.NewExecutableSchema(graph.Config{Resolvers: root}))
+	h := handler.GraphQL(graph.NewExecutableSchema(graph.NewConfig(root)),
+		handler.ComplexityLimit(graph.DefaultComplexityLimit))
 	statsLogger := func(stats graph.NodeDataLoaderStats) {
 		log.Printf("stats: %+v\n", stats)
 	}
-	http.Handle("/query", graph.WithNodeDataloaderMiddleware(store, statsLogger, h))
+	// Persisted queries are shared by every instance of the function if PREGEL_QUERY_TABLE_NAME is
+	// set. The table has the same keys as the pregel table.
+	var cache graph.PersistedQueryCache = graph.NewMemoryQueryCache(1000)
+	if c.QueryTableName != "" {
+		client, err := db.New(c.Region, c.QueryTableName)
+		if err != nil {
+			log.Fatal(err)
+		}
+		cache = graph.NewDBQueryCache(client)
+	}
+	apq := graph.WithPersistedQueryMiddleware(cache, h)
+	loader := graph.WithNodeDataloaderMiddleware(store, statsLogger, apq)
+	c.ConfigureLoader(loader)
+	// Set PREGEL_TENANTS to give each tenant its own namespace of the table. The tenant is selected
+	// after authentication, so that it can be read from the caller's JWT.
+	var next http.Handler = loader
+	selectTenant, ok, err := c.TenantSelector()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if ok {
+		next = graph.WithTenantMiddleware(selectTenant, graph.NamespaceTenants(store), loader)
+	}
+	auth := graph.WithAuthMiddleware(authenticate, next)
+	cors := graph.WithCORSMiddleware(c.CORS.Origins, auth)
+	if err = c.ConfigureCORS(cors); err != nil {
+		log.Fatal(err)
+	}
+	cors.MaxAge = 10 * time.Minute
+	http.Handle("/query", graph.WithSecurityHeadersMiddleware(cors))
+	// The health checks don't require authentication.
+	http.Handle("/healthz", graph.HealthHandler())
+	http.Handle("/readyz", graph.ReadinessHandler(store))
 
 	algnhsa.ListenAndServe(http.DefaultServeMux, nil)
 }
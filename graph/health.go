@@ -0,0 +1,37 @@
+package graph
+
+import (
+	"context"
+	"log"
+	"net/http"
+
+	"github.com/a-h/pregel"
+)
+
+// Pinger checks that a dependency is available, e.g. the pregel.Store.
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+var _ Pinger = &pregel.Store{}
+
+// HealthHandler responds to liveness probes. It doesn't check any dependencies, so that the
+// service isn't restarted when the database is unavailable.
+func HealthHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	})
+}
+
+// ReadinessHandler responds to readiness probes, returning a 503 status if the Pinger fails, so that
+// load balancers stop sending requests to the service.
+func ReadinessHandler(p Pinger) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := p.Ping(r.Context()); err != nil {
+			log.Printf("readiness check failed: %v", err)
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("ok"))
+	})
+}
@@ -0,0 +1,52 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type testPinger struct {
+	err error
+}
+
+func (tp testPinger) Ping(ctx context.Context) error {
+	return tp.err
+}
+
+func TestHealthHandlers(t *testing.T) {
+	tests := []struct {
+		name           string
+		handler        http.Handler
+		expectedStatus int
+	}{
+		{
+			name:           "health checks don't check dependencies",
+			handler:        HealthHandler(),
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "ready if the ping succeeds",
+			handler:        ReadinessHandler(testPinger{}),
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "not ready if the ping fails",
+			handler:        ReadinessHandler(testPinger{err: errors.New("table not found")}),
+			expectedStatus: http.StatusServiceUnavailable,
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			w := httptest.NewRecorder()
+			test.handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+			if w.Code != test.expectedStatus {
+				t.Errorf("expected status %d, got %d", test.expectedStatus, w.Code)
+			}
+		})
+	}
+}
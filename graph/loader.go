@@ -1,12 +1,16 @@
 package graph
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"net/http"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/a-h/pregel"
+	"github.com/a-h/pregel/db"
 )
 
 // FromContext returns the node loader from the context.
@@ -23,12 +27,30 @@ type NodeGetter interface {
 	Get(id string) (n pregel.Node, ok bool, err error)
 }
 
+// NodeBatchGetter can retrieve many nodes at once, e.g. the pregel.Store. The nodes which exist are
+// returned in the order of the IDs.
+type NodeBatchGetter interface {
+	GetMany(ids ...string) (nodes []pregel.Node, err error)
+}
+
+var _ NodeBatchGetter = &pregel.Store{}
+
+// NodeBatchCapacityGetter can retrieve many nodes at once, and returns the capacity consumed, e.g.
+// the pregel.Store. It's used in preference to a NodeBatchGetter, so that the capacity consumed by
+// each request is included in the NodeDataLoaderStats.
+type NodeBatchCapacityGetter interface {
+	GetManyWithCapacity(ids ...string) (nodes []pregel.Node, cc db.ConsumedCapacity, err error)
+}
+
+var _ NodeBatchCapacityGetter = &pregel.Store{}
+
 // NodeDataLoaderStats contains stats about the operation.
 type NodeDataLoaderStats struct {
-	FetchesMade int64
-	NodesLoaded int64
-	StartTime   time.Time
-	TimeTaken   time.Duration
+	FetchesMade      int64
+	NodesLoaded      int64
+	StartTime        time.Time
+	TimeTaken        time.Duration
+	ConsumedCapacity db.ConsumedCapacity
 }
 
 // NewNodeDataLoaderStats creates a new data loader.
@@ -38,65 +60,260 @@ func NewNodeDataLoaderStats(startTime time.Time) NodeDataLoaderStats {
 	}
 }
 
-// NodeDataLoaderMiddlware is middleware which loads nodes using the NodeGetter.
+// DefaultMaxBatch is the default maximum number of nodes fetched in a batch by the
+// NodeDataLoaderMiddlware.
+const DefaultMaxBatch = 10
+
+// DefaultWait is the default time that the NodeDataLoaderMiddlware waits for more nodes to be
+// requested before fetching a batch.
+const DefaultWait = time.Millisecond
+
+// NodeDataLoaderMiddlware is middleware which loads nodes using the NodeGetter, or the Store added
+// to the request's context by the TenantMiddleware.
 type NodeDataLoaderMiddlware struct {
 	Next       http.Handler
 	NodeGetter NodeGetter
 	Now        func() time.Time
 	Stats      func(s NodeDataLoaderStats)
+	// MaxBatch is the maximum number of nodes fetched in a batch. If zero, DefaultMaxBatch is used.
+	MaxBatch int
+	// Wait is the time to wait for more nodes to be requested before fetching a batch. If zero,
+	// DefaultWait is used.
+	Wait time.Duration
+	// Concurrency is the maximum number of nodes of a batch which are fetched at once, if the
+	// NodeGetter isn't a NodeBatchGetter. If zero, every node of the batch is fetched at once.
+	Concurrency int
+	// Debug adds the stats to the "loader" field of the extensions of each response, so that clients
+	// can see the cost of their queries.
+	Debug bool
 }
 
 func (ndlm *NodeDataLoaderMiddlware) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	stats := NewNodeDataLoaderStats(ndlm.Now().UTC())
+	maxBatch, wait := ndlm.MaxBatch, ndlm.Wait
+	if maxBatch < 1 {
+		maxBatch = DefaultMaxBatch
+	}
+	if wait <= 0 {
+		wait = DefaultWait
+	}
+	var ng NodeGetter = ndlm.NodeGetter
+	if s, ok := StoreFromContext(r.Context()); ok {
+		ng = s
+	}
+	// Batches can be fetched concurrently, so the stats are protected by a mutex.
+	var m sync.Mutex
 	l := NewNodeLoader(NodeLoaderConfig{
 		Fetch: func(ids []string) (nodes []*pregel.Node, errs []error) {
-			stats.FetchesMade++
-
-			nodes = make([]*pregel.Node, len(ids))
-			errs = make([]error, len(ids))
-
-			var wg sync.WaitGroup
-			wg.Add(len(ids))
-			for i, id := range ids {
-				go func(index int, nodeID string) {
-					defer func() {
-						stats.NodesLoaded++
-						wg.Done()
-					}()
-					n, ok, err := ndlm.NodeGetter.Get(nodeID)
-					if err != nil {
-						errs[index] = err
-						return
-					}
-					if !ok {
-						return
-					}
-					nodes[index] = &n
-					return
-				}(i, id)
+			var cc db.ConsumedCapacity
+			switch ng := ng.(type) {
+			case NodeBatchCapacityGetter:
+				nodes, errs, cc = getManyWithCapacity(ng, ids)
+			case NodeBatchGetter:
+				nodes, errs = getMany(ng, ids)
+			default:
+				nodes, errs = ndlm.getEach(ng, ids)
 			}
-
-			wg.Wait()
+			m.Lock()
+			defer m.Unlock()
+			stats.FetchesMade++
+			stats.NodesLoaded += int64(len(ids))
+			stats.ConsumedCapacity.ConsumedCapacity += cc.ConsumedCapacity
+			stats.ConsumedCapacity.ConsumedReadCapacity += cc.ConsumedReadCapacity
+			stats.ConsumedCapacity.ConsumedWriteCapacity += cc.ConsumedWriteCapacity
 			return
 		},
-		MaxBatch: 10,
-		Wait:     time.Millisecond,
+		MaxBatch: maxBatch,
+		Wait:     wait,
 	})
 	ctx := context.WithValue(r.Context(), nodeLoaderKey, l)
 	r = r.WithContext(ctx)
-	ndlm.Next.ServeHTTP(w, r)
+	// WebSocket connections aren't buffered, since the connection is hijacked by the handler.
+	var bw *bufferedResponseWriter
+	if ndlm.Debug && !strings.EqualFold(r.Header.Get("Upgrade"), "websocket") {
+		bw = newBufferedResponseWriter(w)
+		ndlm.Next.ServeHTTP(bw, r)
+	} else {
+		ndlm.Next.ServeHTTP(w, r)
+	}
+	m.Lock()
 	stats.TimeTaken = ndlm.Now().Sub(stats.StartTime)
+	m.Unlock()
 	if ndlm.Stats != nil {
 		ndlm.Stats(stats)
 	}
+	if bw != nil {
+		bw.flush(addLoaderExtension(bw.body.Bytes(), stats))
+	}
+}
+
+// bufferedResponseWriter holds the response, so that it can be modified before it's written.
+type bufferedResponseWriter struct {
+	w      http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func newBufferedResponseWriter(w http.ResponseWriter) *bufferedResponseWriter {
+	return &bufferedResponseWriter{
+		w:      w,
+		status: http.StatusOK,
+	}
+}
+
+func (bw *bufferedResponseWriter) Header() http.Header {
+	return bw.w.Header()
+}
+
+func (bw *bufferedResponseWriter) Write(p []byte) (int, error) {
+	return bw.body.Write(p)
+}
+
+func (bw *bufferedResponseWriter) WriteHeader(status int) {
+	bw.status = status
+}
+
+// flush writes the status and body to the underlying ResponseWriter.
+func (bw *bufferedResponseWriter) flush(body []byte) {
+	bw.w.Header().Del("Content-Length")
+	bw.w.WriteHeader(bw.status)
+	bw.w.Write(body)
+}
+
+// loaderExtension is the JSON representation of the NodeDataLoaderStats in the extensions of a
+// response.
+type loaderExtension struct {
+	FetchesMade      int64             `json:"fetchesMade"`
+	NodesLoaded      int64             `json:"nodesLoaded"`
+	StartTime        time.Time         `json:"startTime"`
+	TimeTaken        string            `json:"timeTaken"`
+	ConsumedCapacity capacityExtension `json:"consumedCapacity"`
+}
+
+type capacityExtension struct {
+	Total float64 `json:"total"`
+	Read  float64 `json:"read"`
+	Write float64 `json:"write"`
+}
+
+// addLoaderExtension adds the stats to the "loader" field of the extensions of a GraphQL response.
+// Responses which aren't a single JSON object, e.g. the responses of batched queries, are returned
+// unchanged.
+func addLoaderExtension(body []byte, stats NodeDataLoaderStats) []byte {
+	var response map[string]json.RawMessage
+	if err := json.Unmarshal(body, &response); err != nil || response == nil {
+		return body
+	}
+	extensions := map[string]json.RawMessage{}
+	if ext, ok := response["extensions"]; ok {
+		if err := json.Unmarshal(ext, &extensions); err != nil || extensions == nil {
+			return body
+		}
+	}
+	loader, err := json.Marshal(loaderExtension{
+		FetchesMade: stats.FetchesMade,
+		NodesLoaded: stats.NodesLoaded,
+		StartTime:   stats.StartTime,
+		TimeTaken:   stats.TimeTaken.String(),
+		ConsumedCapacity: capacityExtension{
+			Total: stats.ConsumedCapacity.ConsumedCapacity,
+			Read:  stats.ConsumedCapacity.ConsumedReadCapacity,
+			Write: stats.ConsumedCapacity.ConsumedWriteCapacity,
+		},
+	})
+	if err != nil {
+		return body
+	}
+	extensions["loader"] = loader
+	if response["extensions"], err = json.Marshal(extensions); err != nil {
+		return body
+	}
+	updated, err := json.Marshal(response)
+	if err != nil {
+		return body
+	}
+	return updated
+}
+
+// getMany gets a batch of nodes using a single call to the NodeBatchGetter.
+func getMany(bg NodeBatchGetter, ids []string) (nodes []*pregel.Node, errs []error) {
+	found, err := bg.GetMany(ids...)
+	return orderNodes(ids, found, err)
+}
+
+// getManyWithCapacity gets a batch of nodes using a single call to the NodeBatchCapacityGetter.
+func getManyWithCapacity(bcg NodeBatchCapacityGetter, ids []string) (nodes []*pregel.Node, errs []error, cc db.ConsumedCapacity) {
+	found, cc, err := bcg.GetManyWithCapacity(ids...)
+	nodes, errs = orderNodes(ids, found, err)
+	return
+}
+
+// orderNodes returns the nodes found in the order of the IDs, with a nil entry for each node which
+// wasn't found. If err is set, it's returned for every node.
+func orderNodes(ids []string, found []pregel.Node, err error) (nodes []*pregel.Node, errs []error) {
+	nodes = make([]*pregel.Node, len(ids))
+	errs = make([]error, len(ids))
+	if err != nil {
+		for i := range errs {
+			errs[i] = err
+		}
+		return
+	}
+	byID := make(map[string]*pregel.Node, len(found))
+	for i := range found {
+		byID[found[i].ID] = &found[i]
+	}
+	for i, id := range ids {
+		nodes[i] = byID[id]
+	}
+	return
+}
+
+// getEach gets each node of a batch in parallel, for NodeGetters which can't get many nodes at once.
+func (ndlm *NodeDataLoaderMiddlware) getEach(ng NodeGetter, ids []string) (nodes []*pregel.Node, errs []error) {
+	nodes = make([]*pregel.Node, len(ids))
+	errs = make([]error, len(ids))
+
+	concurrency := ndlm.Concurrency
+	if concurrency < 1 {
+		concurrency = len(ids)
+	}
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	wg.Add(len(ids))
+	for i, id := range ids {
+		sem <- struct{}{}
+		go func(index int, nodeID string) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			n, ok, err := ng.Get(nodeID)
+			if err != nil {
+				errs[index] = err
+				return
+			}
+			if !ok {
+				return
+			}
+			nodes[index] = &n
+			return
+		}(i, id)
+	}
+
+	wg.Wait()
+	return
 }
 
-// WithNodeDataloaderMiddleware populates the Data Loader middleware for loading nodes.
+// WithNodeDataloaderMiddleware populates the Data Loader middleware for loading nodes. The batching
+// can be tuned by setting the MaxBatch, Wait and Concurrency fields of the middleware.
 func WithNodeDataloaderMiddleware(nodeGetter NodeGetter, statsLogger func(NodeDataLoaderStats), next http.Handler) *NodeDataLoaderMiddlware {
 	return &NodeDataLoaderMiddlware{
 		NodeGetter: nodeGetter,
 		Next:       next,
 		Now:        time.Now,
 		Stats:      statsLogger,
+		MaxBatch:   DefaultMaxBatch,
+		Wait:       DefaultWait,
 	}
 }
@@ -5,8 +5,10 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/a-h/pregel"
+	"github.com/a-h/pregel/db"
 )
 
 type inMemoryNodeGetter struct {
@@ -43,6 +45,8 @@ func Test(t *testing.T) {
 		inputs           []string
 		expectedNodes    []*pregel.Node
 		expectedErrors   []error
+		maxBatch         int
+		concurrency      int
 		expectedNodeGets int64
 		expectedFetches  int64
 	}{
@@ -140,6 +144,50 @@ func Test(t *testing.T) {
 			expectedNodeGets: 12,
 			expectedFetches:  2,
 		},
+		{
+			name:   "the batch size can be configured",
+			inputs: []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j", "k", "l"},
+			expectedNodes: []*pregel.Node{
+				&nodeA,
+				&nodeB,
+				&nodeC,
+				&nodeD,
+				&nodeE,
+				&nodeF,
+				&nodeG,
+				&nodeH,
+				&nodeI,
+				&nodeJ,
+				&nodeK,
+				&nodeL,
+			},
+			expectedErrors:   make([]error, 12),
+			maxBatch:         5,
+			expectedNodeGets: 12,
+			expectedFetches:  3,
+		},
+		{
+			name:   "the number of nodes fetched at once can be limited",
+			inputs: []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j", "k", "l"},
+			expectedNodes: []*pregel.Node{
+				&nodeA,
+				&nodeB,
+				&nodeC,
+				&nodeD,
+				&nodeE,
+				&nodeF,
+				&nodeG,
+				&nodeH,
+				&nodeI,
+				&nodeJ,
+				&nodeK,
+				&nodeL,
+			},
+			expectedErrors:   make([]error, 12),
+			concurrency:      2,
+			expectedNodeGets: 12,
+			expectedFetches:  2,
+		},
 	}
 	for _, test := range tests {
 		test := test
@@ -201,6 +249,10 @@ func Test(t *testing.T) {
 				stats = s
 			}
 			h := WithNodeDataloaderMiddleware(ng, statsLogger, th)
+			if test.maxBatch > 0 {
+				h.MaxBatch = test.maxBatch
+			}
+			h.Concurrency = test.concurrency
 
 			w := httptest.NewRecorder()
 			r := httptest.NewRequest(http.MethodPost, "/query", nil)
@@ -215,3 +267,149 @@ func Test(t *testing.T) {
 		})
 	}
 }
+
+type inMemoryNodeBatchGetter struct {
+	inMemoryNodeGetter
+	calls [][]string
+}
+
+func (imnbg *inMemoryNodeBatchGetter) GetMany(ids ...string) (nodes []pregel.Node, err error) {
+	imnbg.calls = append(imnbg.calls, ids)
+	for _, id := range ids {
+		if id == "error" {
+			return nil, errNodeGetFailure
+		}
+		if n, ok := imnbg.nodes[id]; ok {
+			nodes = append(nodes, n)
+		}
+	}
+	return
+}
+
+func TestNodeBatchGetter(t *testing.T) {
+	ng := &inMemoryNodeBatchGetter{
+		inMemoryNodeGetter: inMemoryNodeGetter{
+			nodes: map[string]pregel.Node{
+				"a": pregel.NewNode("a"),
+				"c": pregel.NewNode("c"),
+			},
+		},
+	}
+	var actualNodes []*pregel.Node
+	var actualErrs []error
+	th := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		actualNodes, actualErrs = FromContext(r.Context()).LoadAll([]string{"a", "b", "c"})
+	})
+	h := WithNodeDataloaderMiddleware(ng, nil, th)
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/query", nil))
+
+	if len(ng.calls) != 1 || len(ng.calls[0]) != 3 {
+		t.Errorf("expected a single call to get 3 nodes, got %v", ng.calls)
+	}
+	if len(actualNodes) != 3 || actualNodes[0].ID != "a" || actualNodes[1] != nil || actualNodes[2].ID != "c" {
+		t.Errorf("expected nodes a, nil and c, got %v", actualNodes)
+	}
+	for i, err := range actualErrs {
+		if err != nil {
+			t.Errorf("unexpected error %d: %v", i, err)
+		}
+	}
+
+	t.Run("errors are returned for each node of the batch", func(t *testing.T) {
+		th := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, actualErrs = FromContext(r.Context()).LoadAll([]string{"a", "error"})
+		})
+		h := WithNodeDataloaderMiddleware(ng, nil, th)
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/query", nil))
+		if len(actualErrs) != 2 || actualErrs[0] != errNodeGetFailure || actualErrs[1] != errNodeGetFailure {
+			t.Errorf("expected both nodes to return the error, got %v", actualErrs)
+		}
+	})
+}
+
+type inMemoryNodeBatchCapacityGetter struct {
+	inMemoryNodeBatchGetter
+}
+
+func (imnbcg *inMemoryNodeBatchCapacityGetter) GetManyWithCapacity(ids ...string) (nodes []pregel.Node, cc db.ConsumedCapacity, err error) {
+	nodes, err = imnbcg.GetMany(ids...)
+	cc = db.ConsumedCapacity{ConsumedCapacity: float64(len(ids)), ConsumedReadCapacity: float64(len(ids))}
+	return
+}
+
+func TestNodeDataLoaderDebug(t *testing.T) {
+	tests := []struct {
+		name     string
+		debug    bool
+		response string
+		expected string
+	}{
+		{
+			name:     "responses aren't modified if debug isn't set",
+			response: `{"data":{"a":"b"}}`,
+			expected: `{"data":{"a":"b"}}`,
+		},
+		{
+			name:     "the stats are added to the extensions",
+			debug:    true,
+			response: `{"data":{"a":"b"}}`,
+			expected: `{"data":{"a":"b"},"extensions":{"loader":{"fetchesMade":1,"nodesLoaded":2,"startTime":"2019-01-01T00:00:00Z","timeTaken":"1s","consumedCapacity":{"total":2,"read":2,"write":0}}}}`,
+		},
+		{
+			name:     "existing extensions are kept",
+			debug:    true,
+			response: `{"data":null,"extensions":{"a":1}}`,
+			expected: `{"data":null,"extensions":{"a":1,"loader":{"fetchesMade":1,"nodesLoaded":2,"startTime":"2019-01-01T00:00:00Z","timeTaken":"1s","consumedCapacity":{"total":2,"read":2,"write":0}}}}`,
+		},
+		{
+			name:     "batched responses aren't modified",
+			debug:    true,
+			response: `[{"data":null}]`,
+			expected: `[{"data":null}]`,
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			ng := &inMemoryNodeBatchCapacityGetter{
+				inMemoryNodeBatchGetter: inMemoryNodeBatchGetter{
+					inMemoryNodeGetter: inMemoryNodeGetter{
+						nodes: map[string]pregel.Node{"a": pregel.NewNode("a")},
+					},
+				},
+			}
+			th := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				FromContext(r.Context()).LoadAll([]string{"a", "b"})
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusTeapot)
+				w.Write([]byte(test.response))
+			})
+			var stats NodeDataLoaderStats
+			h := WithNodeDataloaderMiddleware(ng, func(s NodeDataLoaderStats) { stats = s }, th)
+			h.Debug = test.debug
+			start := time.Date(2019, time.January, 1, 0, 0, 0, 0, time.UTC)
+			var calls int
+			h.Now = func() time.Time {
+				calls++
+				return start.Add(time.Duration(calls-1) * time.Second)
+			}
+
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/query", nil))
+
+			if w.Code != http.StatusTeapot {
+				t.Errorf("expected the status to be kept, got %d", w.Code)
+			}
+			if w.Header().Get("Content-Type") != "application/json" {
+				t.Errorf("expected the headers to be kept, got %v", w.Header())
+			}
+			if actual := w.Body.String(); actual != test.expected {
+				t.Errorf("expected response:\n%s\ngot:\n%s", test.expected, actual)
+			}
+			if expected := (db.ConsumedCapacity{ConsumedCapacity: 2, ConsumedReadCapacity: 2}); stats.ConsumedCapacity != expected {
+				t.Errorf("expected capacity %+v, got %+v", expected, stats.ConsumedCapacity)
+			}
+		})
+	}
+}
@@ -3,6 +3,11 @@
 package graph
 
 import (
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
 	"github.com/a-h/pregel"
 )
 
@@ -14,6 +19,16 @@ type NodeDataItem interface {
 	IsNodeDataItem()
 }
 
+type ChangeEvent struct {
+	Type     string                 `json:"type"`
+	NodeID   string                 `json:"nodeId"`
+	Child    *string                `json:"child"`
+	Label    *string                `json:"label"`
+	DataType *string                `json:"dataType"`
+	New      map[string]interface{} `json:"new"`
+	Time     string                 `json:"time"`
+}
+
 type Computer struct {
 	Brand         string `json:"brand"`
 	YearPurchased int    `json:"yearPurchased"`
@@ -28,9 +43,22 @@ type Connection struct {
 }
 
 type Edge struct {
-	Cursor string         `json:"cursor"`
-	Node   *pregel.Node   `json:"node"`
-	Data   []EdgeDataItem `json:"data"`
+	Cursor    string                 `json:"cursor"`
+	Node      *pregel.Node           `json:"node"`
+	Data      []EdgeDataItem         `json:"data"`
+	JSON      map[string]interface{} `json:"json"`
+	CreatedAt *time.Time             `json:"createdAt"`
+	UpdatedAt *time.Time             `json:"updatedAt"`
+}
+
+type EdgeOrder struct {
+	Field     EdgeOrderField  `json:"field"`
+	Direction *OrderDirection `json:"direction"`
+}
+
+type ImportGraphOutput struct {
+	NodesRead      int `json:"nodesRead"`
+	RecordsWritten int `json:"recordsWritten"`
 }
 
 type Location struct {
@@ -46,6 +74,19 @@ type LocationInput struct {
 	Lat float64 `json:"lat"`
 }
 
+type LocationNode struct {
+	ID       string       `json:"id"`
+	NodeID   string       `json:"nodeId"`
+	Location *Location    `json:"location"`
+	Node     *pregel.Node `json:"node"`
+}
+
+type NodeFilter struct {
+	IDPrefix *string `json:"idPrefix"`
+	Label    *string `json:"label"`
+	DataType *string `json:"dataType"`
+}
+
 type PageInfo struct {
 	EndCursor       *string `json:"endCursor"`
 	HasNextPage     bool    `json:"hasNextPage"`
@@ -53,6 +94,22 @@ type PageInfo struct {
 	StartCursor     *string `json:"startCursor"`
 }
 
+type Path struct {
+	Nodes []*pregel.Node `json:"nodes"`
+	Edges []Edge         `json:"edges"`
+}
+
+type RemoveEdgeDataInput struct {
+	Parent string  `json:"parent"`
+	Child  string  `json:"child"`
+	Label  *string `json:"label"`
+	Type   string  `json:"type"`
+}
+
+type RemoveEdgeDataOutput struct {
+	Removed bool `json:"removed"`
+}
+
 type RemoveEdgeInput struct {
 	Parent string `json:"parent"`
 	Child  string `json:"child"`
@@ -62,6 +119,15 @@ type RemoveEdgeOutput struct {
 	Removed bool `json:"removed"`
 }
 
+type RemoveNodeDataInput struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
+}
+
+type RemoveNodeDataOutput struct {
+	Removed bool `json:"removed"`
+}
+
 type RemoveNodeInput struct {
 	ID string `json:"id"`
 }
@@ -77,8 +143,10 @@ type SaveEdgeInput struct {
 }
 
 type SaveEdgeOutput struct {
-	Parent string `json:"parent"`
-	Child  string `json:"child"`
+	Parent     string       `json:"parent"`
+	Child      string       `json:"child"`
+	ParentNode *pregel.Node `json:"parentNode"`
+	ChildNode  *pregel.Node `json:"childNode"`
 }
 
 type SaveNodeInput struct {
@@ -89,7 +157,19 @@ type SaveNodeInput struct {
 }
 
 type SaveNodeOutput struct {
-	ID string `json:"id"`
+	ID   string       `json:"id"`
+	Node *pregel.Node `json:"node"`
+}
+
+type SetEdgeDataInput struct {
+	Parent string                 `json:"parent"`
+	Child  string                 `json:"child"`
+	Type   string                 `json:"type"`
+	Data   map[string]interface{} `json:"data"`
+}
+
+type SetEdgeDataOutput struct {
+	Set bool `json:"set"`
 }
 
 type SetEdgeFieldsInput struct {
@@ -102,11 +182,163 @@ type SetEdgeFieldsOutput struct {
 	Set bool `json:"set"`
 }
 
+type SetNodeDataInput struct {
+	ID   string                 `json:"id"`
+	Type string                 `json:"type"`
+	Data map[string]interface{} `json:"data"`
+}
+
+type SetNodeDataOutput struct {
+	Set bool `json:"set"`
+}
+
 type SetNodeFieldsInput struct {
 	ID       string         `json:"id"`
 	Location *LocationInput `json:"location"`
 }
 
 type SetNodeFieldsOutput struct {
-	Set bool `json:"set"`
+	Set  bool         `json:"set"`
+	ID   string       `json:"id"`
+	Node *pregel.Node `json:"node"`
+}
+
+type Subgraph struct {
+	Root  string         `json:"root"`
+	Nodes []*pregel.Node `json:"nodes"`
+	Edges []SubgraphEdge `json:"edges"`
+}
+
+type SubgraphEdge struct {
+	Parent string                 `json:"parent"`
+	Child  string                 `json:"child"`
+	Label  *string                `json:"label"`
+	JSON   map[string]interface{} `json:"json"`
+}
+
+type Direction string
+
+const (
+	DirectionChildren Direction = "CHILDREN"
+	DirectionParents  Direction = "PARENTS"
+	DirectionBoth     Direction = "BOTH"
+)
+
+var AllDirection = []Direction{
+	DirectionChildren,
+	DirectionParents,
+	DirectionBoth,
+}
+
+func (e Direction) IsValid() bool {
+	switch e {
+	case DirectionChildren, DirectionParents, DirectionBoth:
+		return true
+	}
+	return false
+}
+
+func (e Direction) String() string {
+	return string(e)
+}
+
+func (e *Direction) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = Direction(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid Direction", str)
+	}
+	return nil
+}
+
+func (e Direction) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type EdgeOrderField string
+
+const (
+	EdgeOrderFieldID        EdgeOrderField = "ID"
+	EdgeOrderFieldCreatedAt EdgeOrderField = "CREATED_AT"
+	EdgeOrderFieldUpdatedAt EdgeOrderField = "UPDATED_AT"
+)
+
+var AllEdgeOrderField = []EdgeOrderField{
+	EdgeOrderFieldID,
+	EdgeOrderFieldCreatedAt,
+	EdgeOrderFieldUpdatedAt,
+}
+
+func (e EdgeOrderField) IsValid() bool {
+	switch e {
+	case EdgeOrderFieldID, EdgeOrderFieldCreatedAt, EdgeOrderFieldUpdatedAt:
+		return true
+	}
+	return false
+}
+
+func (e EdgeOrderField) String() string {
+	return string(e)
+}
+
+func (e *EdgeOrderField) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = EdgeOrderField(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid EdgeOrderField", str)
+	}
+	return nil
+}
+
+func (e EdgeOrderField) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type OrderDirection string
+
+const (
+	OrderDirectionAsc  OrderDirection = "ASC"
+	OrderDirectionDesc OrderDirection = "DESC"
+)
+
+var AllOrderDirection = []OrderDirection{
+	OrderDirectionAsc,
+	OrderDirectionDesc,
+}
+
+func (e OrderDirection) IsValid() bool {
+	switch e {
+	case OrderDirectionAsc, OrderDirectionDesc:
+		return true
+	}
+	return false
+}
+
+func (e OrderDirection) String() string {
+	return string(e)
+}
+
+func (e *OrderDirection) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = OrderDirection(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid OrderDirection", str)
+	}
+	return nil
+}
+
+func (e OrderDirection) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
 }
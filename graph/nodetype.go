@@ -0,0 +1,91 @@
+package graph
+
+import (
+	"github.com/a-h/pregel"
+	"github.com/a-h/pregel/graph/gqlid"
+)
+
+// locationNodeType is the name of the GraphQL type of nodes which have location data.
+const locationNodeType = "LocationNode"
+
+// NodeType is a GraphQL type of the pregel nodes which have a kind of data, e.g. the LocationNode
+// type of nodes which have location data.
+type NodeType struct {
+	// Name of the GraphQL type, which is used in the global IDs of its objects.
+	Name string
+	// New returns the object of the node, or false if the node isn't of the type. The Go type of the
+	// object must be bound to the GraphQL type, and handled by the Node interface in generated.go.
+	New func(n *pregel.Node) (obj RelayNode, ok bool)
+}
+
+// NodeTypes map pregel nodes to their most specific GraphQL type. The first type which accepts a
+// node is used, so more specific types must be listed first. Nodes which aren't accepted by any of
+// the types are GraphNodes. To add a type, add it to the schema, regenerate the code, and add it to
+// the NodeTypes of the resolvers.
+type NodeTypes []NodeType
+
+// DefaultNodeTypes returns the types used by the resolvers if their NodeTypes aren't set.
+func DefaultNodeTypes() NodeTypes {
+	return NodeTypes{
+		{Name: locationNodeType, New: newLocationNode},
+	}
+}
+
+// Map returns the object of the first type which accepts the node, or the node itself, which is a
+// GraphNode.
+func (nt NodeTypes) Map(n *pregel.Node) RelayNode {
+	for _, t := range nt {
+		if obj, ok := t.New(n); ok {
+			return obj
+		}
+	}
+	return n
+}
+
+// Get returns the type with the name. GraphNode is a type of every node, so it's always found.
+func (nt NodeTypes) Get(typeName string) (t NodeType, ok bool) {
+	if typeName == graphNodeType {
+		return graphNode, true
+	}
+	for _, t = range nt {
+		if t.Name == typeName {
+			return t, true
+		}
+	}
+	return NodeType{}, false
+}
+
+// graphNode is the GraphNode type, which accepts every node.
+var graphNode = NodeType{
+	Name: graphNodeType,
+	New: func(n *pregel.Node) (obj RelayNode, ok bool) {
+		return n, true
+	},
+}
+
+// nodeTypesOrDefault returns the node types, or the DefaultNodeTypes if they're not set.
+func nodeTypesOrDefault(nt NodeTypes) NodeTypes {
+	if nt == nil {
+		return DefaultNodeTypes()
+	}
+	return nt
+}
+
+func newLocationNode(n *pregel.Node) (obj RelayNode, ok bool) {
+	var l *Location
+	switch v := n.Data["Location"].(type) {
+	case *Location:
+		l = v
+	case Location:
+		l = &v
+	default:
+		return
+	}
+	obj = &LocationNode{
+		ID:       gqlid.EncodeGlobal(locationNodeType, n.ID),
+		NodeID:   n.ID,
+		Location: l,
+		Node:     n,
+	}
+	return obj, true
+}
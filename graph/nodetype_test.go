@@ -0,0 +1,76 @@
+package graph
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/a-h/pregel"
+	"github.com/a-h/pregel/graph/gqlid"
+)
+
+func TestNodeTypesMap(t *testing.T) {
+	plain := pregel.NewNode("plain")
+	located := pregel.NewNode("located").WithData(Location{Lat: 1, Lng: 2})
+	tests := []struct {
+		name     string
+		types    NodeTypes
+		node     *pregel.Node
+		expected RelayNode
+	}{
+		{
+			name:     "Nodes without a more specific type are GraphNodes",
+			types:    DefaultNodeTypes(),
+			node:     &plain,
+			expected: &plain,
+		},
+		{
+			name:  "Nodes with location data are LocationNodes",
+			types: DefaultNodeTypes(),
+			node:  &located,
+			expected: &LocationNode{
+				ID:       gqlid.EncodeGlobal("LocationNode", "located"),
+				NodeID:   "located",
+				Location: &Location{Lat: 1, Lng: 2},
+				Node:     &located,
+			},
+		},
+		{
+			name:     "Nodes are GraphNodes if there aren't any types",
+			types:    NodeTypes{},
+			node:     &located,
+			expected: &located,
+		},
+		{
+			name: "The first type which accepts the node is used",
+			types: NodeTypes{
+				{Name: "Named", New: func(n *pregel.Node) (RelayNode, bool) { return n.ID, n.ID == "plain" }},
+				{Name: "Any", New: func(n *pregel.Node) (RelayNode, bool) { return "any", true }},
+			},
+			node:     &plain,
+			expected: "plain",
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			actual := test.types.Map(test.node)
+			if !reflect.DeepEqual(actual, test.expected) {
+				t.Errorf("expected %#v, got %#v", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestNodeTypesGet(t *testing.T) {
+	types := DefaultNodeTypes()
+	for _, name := range []string{"GraphNode", "LocationNode"} {
+		if nt, ok := types.Get(name); !ok || nt.Name != name {
+			t.Errorf("expected to find %s, got %q, %v", name, nt.Name, ok)
+		}
+	}
+	if _, ok := types.Get("Edge"); ok {
+		t.Error("expected Edge not to be a node type")
+	}
+}
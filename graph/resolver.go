@@ -2,9 +2,15 @@ package graph
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
+	"sort"
 	"strings"
+	"time"
 
+	"github.com/99designs/gqlgen/graphql"
 	"github.com/a-h/pregel/graph/gqlid"
 
 	"github.com/a-h/pregel"
@@ -13,8 +19,14 @@ import (
 // Resolver of GraphQL queries.
 type Resolver struct {
 	MutationResolver MutationResolver
-	NodeResolver     NodeResolver
+	NodeResolver     GraphNodeResolver
 	QueryResolver    QueryResolver
+	// SubscriptionResolver is optional, since subscriptions need a source of changes, e.g. a
+	// ChangeBroker.
+	SubscriptionResolver SubscriptionResolver
+	// authorize is set by NewAuthorizedResolver, to authorize reading the nodes returned by
+	// mutations.
+	authorize Authorizer
 }
 
 // Mutation provides the available mutations.
@@ -22,8 +34,8 @@ func (r *Resolver) Mutation() MutationResolver {
 	return r.MutationResolver
 }
 
-// Node provides the Node resolver, used to resolve the subfields of a Node.
-func (r *Resolver) Node() NodeResolver {
+// GraphNode provides the GraphNode resolver, used to resolve the subfields of a node.
+func (r *Resolver) GraphNode() GraphNodeResolver {
 	return r.NodeResolver
 }
 
@@ -32,9 +44,80 @@ func (r *Resolver) Query() QueryResolver {
 	return r.QueryResolver
 }
 
+// Subscription provides the available subscriptions.
+func (r *Resolver) Subscription() SubscriptionResolver {
+	return r.SubscriptionResolver
+}
+
+// SaveEdgeOutput provides the resolver of the nodes of an edge saved by the saveEdge mutation.
+func (r *Resolver) SaveEdgeOutput() SaveEdgeOutputResolver {
+	return &saveEdgeOutputResolver{r: r}
+}
+
+// SaveNodeOutput provides the resolver of the node saved by the saveNode mutation.
+func (r *Resolver) SaveNodeOutput() SaveNodeOutputResolver {
+	return &saveNodeOutputResolver{r: r}
+}
+
+// SetNodeFieldsOutput provides the resolver of the node updated by the setNodeFields mutation.
+func (r *Resolver) SetNodeFieldsOutput() SetNodeFieldsOutputResolver {
+	return &setNodeFieldsOutputResolver{r: r}
+}
+
+// loadMutated loads a node written by a mutation, so that clients can update their caches without
+// another query. The node is cleared from the loader first, since it may have been loaded earlier
+// in the request, before it was written. Reading the node is authorized as a query.
+func (r *Resolver) loadMutated(ctx context.Context, field, id string) (n *pregel.Node, err error) {
+	if r.authorize != nil {
+		if err = r.authorize(ctx, Operation{Field: field, NodeID: id}); err != nil {
+			return
+		}
+	}
+	l := FromContext(ctx)
+	l.Clear(id)
+	return l.Load(id)
+}
+
+type saveEdgeOutputResolver struct {
+	r *Resolver
+}
+
+func (sr *saveEdgeOutputResolver) ParentNode(ctx context.Context, obj *SaveEdgeOutput) (*pregel.Node, error) {
+	return sr.r.loadMutated(ctx, "saveEdge", obj.Parent)
+}
+
+func (sr *saveEdgeOutputResolver) ChildNode(ctx context.Context, obj *SaveEdgeOutput) (*pregel.Node, error) {
+	return sr.r.loadMutated(ctx, "saveEdge", obj.Child)
+}
+
+type saveNodeOutputResolver struct {
+	r *Resolver
+}
+
+func (sr *saveNodeOutputResolver) Node(ctx context.Context, obj *SaveNodeOutput) (*pregel.Node, error) {
+	return sr.r.loadMutated(ctx, "saveNode", obj.ID)
+}
+
+type setNodeFieldsOutputResolver struct {
+	r *Resolver
+}
+
+func (sr *setNodeFieldsOutputResolver) Node(ctx context.Context, obj *SetNodeFieldsOutput) (*pregel.Node, error) {
+	return sr.r.loadMutated(ctx, "setNodeFields", obj.ID)
+}
+
 // PregelMutationResolver resolves mutations.
 type PregelMutationResolver struct {
-	Store *pregel.Store
+	Store pregel.Storer
+	// DataTypes are used to convert the JSON data passed to setNodeData and setEdgeData into the
+	// registered data type, e.g. the Store's DataTypes, so that the data is validated and stored in
+	// the same way as data written in Go. Data of other types is stored as a map.
+	DataTypes map[string]func() interface{}
+}
+
+// store returns the Store of the request's tenant, or the resolver's Store.
+func (pr *PregelMutationResolver) store(ctx context.Context) pregel.Storer {
+	return storeFor(ctx, pr.Store)
 }
 
 // SaveNode saves Nodes.
@@ -52,7 +135,7 @@ func (pr *PregelMutationResolver) SaveNode(ctx context.Context, input SaveNodeIn
 			Lng: input.Location.Lng,
 		})
 	}
-	err = pr.Store.Put(n)
+	err = pr.store(ctx).Put(n)
 	if err != nil {
 		return
 	}
@@ -67,9 +150,12 @@ func (pr *PregelMutationResolver) SaveEdge(ctx context.Context, input SaveEdgeIn
 	// Copy GraphQL edge data to pregel edge.
 	e := pregel.NewEdge(input.Child)
 	if input.Location != nil {
-		e = e.WithData(input.Location)
+		e = e.WithData(Location{
+			Lat: input.Location.Lat,
+			Lng: input.Location.Lng,
+		})
 	}
-	err = pr.Store.PutEdges(input.Parent, e)
+	err = pr.store(ctx).PutEdges(input.Parent, e)
 	if err != nil {
 		return
 	}
@@ -82,7 +168,7 @@ func (pr *PregelMutationResolver) SaveEdge(ctx context.Context, input SaveEdgeIn
 
 // RemoveNode from the database.
 func (pr *PregelMutationResolver) RemoveNode(ctx context.Context, input RemoveNodeInput) (output *RemoveNodeOutput, err error) {
-	err = pr.Store.Delete(input.ID)
+	err = pr.store(ctx).Delete(input.ID)
 	output = &RemoveNodeOutput{}
 	if err == nil {
 		output.Removed = true
@@ -92,7 +178,7 @@ func (pr *PregelMutationResolver) RemoveNode(ctx context.Context, input RemoveNo
 
 // RemoveEdge from the database.
 func (pr *PregelMutationResolver) RemoveEdge(ctx context.Context, input RemoveEdgeInput) (output *RemoveEdgeOutput, err error) {
-	err = pr.Store.DeleteEdge(input.Parent, input.Child)
+	err = pr.store(ctx).DeleteEdge(input.Parent, input.Child)
 	output = &RemoveEdgeOutput{}
 	if err == nil {
 		output.Removed = true
@@ -102,7 +188,9 @@ func (pr *PregelMutationResolver) RemoveEdge(ctx context.Context, input RemoveEd
 
 // SetNodeFields sets data on a node.
 func (pr *PregelMutationResolver) SetNodeFields(ctx context.Context, input SetNodeFieldsInput) (output *SetNodeFieldsOutput, err error) {
-	output = &SetNodeFieldsOutput{}
+	output = &SetNodeFieldsOutput{
+		ID: input.ID,
+	}
 	if input.Location == nil {
 		return
 	}
@@ -111,7 +199,7 @@ func (pr *PregelMutationResolver) SetNodeFields(ctx context.Context, input SetNo
 		Lat: input.Location.Lat,
 		Lng: input.Location.Lng,
 	}
-	err = pr.Store.PutNodeData(input.ID, pregel.NewData(location))
+	err = pr.store(ctx).PutNodeData(input.ID, pregel.NewData(location))
 	if err == nil {
 		output.Set = true
 	}
@@ -129,24 +217,135 @@ func (pr *PregelMutationResolver) SetEdgeFields(ctx context.Context, input SetEd
 		Lat: input.Location.Lat,
 		Lng: input.Location.Lng,
 	}
-	err = pr.Store.PutEdgeData(input.Parent, input.Child, pregel.NewData(location))
+	err = pr.store(ctx).PutEdgeData(input.Parent, input.Child, pregel.NewData(location))
 	if err == nil {
 		output.Set = true
 	}
 	return
 }
 
+// SetNodeData sets data of any type on a node.
+func (pr *PregelMutationResolver) SetNodeData(ctx context.Context, input SetNodeDataInput) (output *SetNodeDataOutput, err error) {
+	output = &SetNodeDataOutput{}
+	v, err := pr.decodeData(input.Type, input.Data)
+	if err != nil {
+		return
+	}
+	err = pr.store(ctx).PutNodeData(input.ID, pregel.Data{input.Type: v})
+	if err == nil {
+		output.Set = true
+	}
+	return
+}
+
+// SetEdgeData sets data of any type on an edge.
+func (pr *PregelMutationResolver) SetEdgeData(ctx context.Context, input SetEdgeDataInput) (output *SetEdgeDataOutput, err error) {
+	output = &SetEdgeDataOutput{}
+	v, err := pr.decodeData(input.Type, input.Data)
+	if err != nil {
+		return
+	}
+	err = pr.store(ctx).PutEdgeData(input.Parent, input.Child, pregel.Data{input.Type: v})
+	if err == nil {
+		output.Set = true
+	}
+	return
+}
+
+// RemoveNodeData removes data of a type from a node.
+func (pr *PregelMutationResolver) RemoveNodeData(ctx context.Context, input RemoveNodeDataInput) (output *RemoveNodeDataOutput, err error) {
+	err = pr.store(ctx).DeleteNodeData(input.ID, input.Type)
+	output = &RemoveNodeDataOutput{}
+	if err == nil {
+		output.Removed = true
+	}
+	return
+}
+
+// RemoveEdgeData removes data of a type from an edge, which is the unlabelled edge if the input
+// doesn't have a label.
+func (pr *PregelMutationResolver) RemoveEdgeData(ctx context.Context, input RemoveEdgeDataInput) (output *RemoveEdgeDataOutput, err error) {
+	err = pr.store(ctx).DeleteLabelledEdgeData(input.Parent, input.Child, stringValue(input.Label), input.Type)
+	output = &RemoveEdgeDataOutput{}
+	if err == nil {
+		output.Removed = true
+	}
+	return
+}
+
+// ErrImportNotSupported is returned by the importGraph mutation when the Store isn't an Importer.
+var ErrImportNotSupported = errors.New("graph: the store does not support importing nodes")
+
+// Importer loads the nodes written by Export. It's implemented by *pregel.Store, but not by every
+// Storer, e.g. a mock.
+type Importer interface {
+	ImportJSONLines(ctx context.Context, r io.Reader, opts pregel.ImportOptions) (p pregel.ImportProgress, err error)
+}
+
+// ImportGraph streams the nodes in the uploaded file into the Store, without reading the whole file
+// into memory. Nodes are written as they're read, so if the import fails, the nodes before the error
+// have been written.
+func (pr *PregelMutationResolver) ImportGraph(ctx context.Context, file graphql.Upload) (output *ImportGraphOutput, err error) {
+	imp, ok := pr.store(ctx).(Importer)
+	if !ok {
+		err = ErrImportNotSupported
+		return
+	}
+	p, err := imp.ImportJSONLines(ctx, file.File, pregel.ImportOptions{})
+	output = &ImportGraphOutput{
+		NodesRead:      int(p.NodesRead),
+		RecordsWritten: int(p.RecordsWritten),
+	}
+	return
+}
+
+// decodeData converts JSON data into a value of the data type, if it's registered.
+func (pr *PregelMutationResolver) decodeData(dataType string, data map[string]interface{}) (v interface{}, err error) {
+	f, ok := pr.DataTypes[dataType]
+	if !ok {
+		return data, nil
+	}
+	b, err := json.Marshal(data)
+	if err != nil {
+		return
+	}
+	v = f()
+	err = json.Unmarshal(b, v)
+	return
+}
+
+// RelayNode is an object with a global ID, as returned by the Relay node query. It's implemented
+// by *pregel.Node, exposed as the GraphNode type, and the objects of the NodeTypes, e.g.
+// *LocationNode.
+type RelayNode interface{}
+
+// graphNodeType is the name of the GraphQL type of pregel nodes, used in their global IDs.
+const graphNodeType = "GraphNode"
+
 // PregelNodeResolver uses pregel to get the node's parents and children.
-type PregelNodeResolver struct{}
+type PregelNodeResolver struct {
+	// Types are used to resolve the typed field of nodes. If nil, the DefaultNodeTypes are used.
+	Types NodeTypes
+}
+
+// ID of the Node, which is unique across all GraphQL types.
+func (r *PregelNodeResolver) ID(ctx context.Context, obj *pregel.Node) (string, error) {
+	return gqlid.EncodeGlobal(graphNodeType, obj.ID), nil
+}
+
+// NodeID is the ID of the Node in pregel.
+func (r *PregelNodeResolver) NodeID(ctx context.Context, obj *pregel.Node) (string, error) {
+	return obj.ID, nil
+}
 
 // Parents of the Node.
-func (r *PregelNodeResolver) Parents(ctx context.Context, obj *pregel.Node, first int, after *string) (c *Connection, err error) {
-	return createConnectionFrom(ctx, obj.Parents, first, after)
+func (r *PregelNodeResolver) Parents(ctx context.Context, obj *pregel.Node, first int, after *string, orderBy *EdgeOrder) (c *Connection, err error) {
+	return createConnectionFrom(ctx, sortEdges(obj.Parents, orderBy), first, after)
 }
 
 // Children of the Node.
-func (r *PregelNodeResolver) Children(ctx context.Context, obj *pregel.Node, first int, after *string) (*Connection, error) {
-	return createConnectionFrom(ctx, obj.Children, first, after)
+func (r *PregelNodeResolver) Children(ctx context.Context, obj *pregel.Node, first int, after *string, orderBy *EdgeOrder) (*Connection, error) {
+	return createConnectionFrom(ctx, sortEdges(obj.Children, orderBy), first, after)
 }
 
 // Data converts the underlying pregel.Node's data into the GraphQL data.
@@ -160,6 +359,61 @@ func (r *PregelNodeResolver) Data(ctx context.Context, obj *pregel.Node) (items
 	return
 }
 
+// JSON returns all of the node's data, keyed by the name of each data type.
+func (r *PregelNodeResolver) JSON(ctx context.Context, obj *pregel.Node) (data map[string]interface{}, err error) {
+	return jsonData(obj.Data), nil
+}
+
+// CreatedAt is the time that the node was created, or nil if the Store's Timestamps aren't enabled.
+func (r *PregelNodeResolver) CreatedAt(ctx context.Context, obj *pregel.Node) (*time.Time, error) {
+	return optionalTime(obj.CreatedAt), nil
+}
+
+// UpdatedAt is the time that the node or its data was last written, or nil if the Store's
+// Timestamps aren't enabled.
+func (r *PregelNodeResolver) UpdatedAt(ctx context.Context, obj *pregel.Node) (*time.Time, error) {
+	return optionalTime(obj.UpdatedAt), nil
+}
+
+// Typed returns the node as its most specific type, e.g. a LocationNode.
+func (r *PregelNodeResolver) Typed(ctx context.Context, obj *pregel.Node) (RelayNode, error) {
+	return nodeTypesOrDefault(r.Types).Map(obj), nil
+}
+
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+// sortEdges returns a sorted copy of the edges, so that the node's edges aren't reordered. The
+// edges of a node are sorted by ID, so they're returned as they are if the order is by ID.
+func sortEdges(edges []*pregel.Edge, orderBy *EdgeOrder) []*pregel.Edge {
+	if orderBy == nil || (orderBy.Field == EdgeOrderFieldID && (orderBy.Direction == nil || *orderBy.Direction == OrderDirectionAsc)) {
+		return edges
+	}
+	sorted := make([]*pregel.Edge, len(edges))
+	copy(sorted, edges)
+	less := func(a, b *pregel.Edge) bool {
+		switch orderBy.Field {
+		case EdgeOrderFieldCreatedAt:
+			return a.CreatedAt.Before(b.CreatedAt)
+		case EdgeOrderFieldUpdatedAt:
+			return a.UpdatedAt.Before(b.UpdatedAt)
+		}
+		return a.ID < b.ID
+	}
+	desc := orderBy.Direction != nil && *orderBy.Direction == OrderDirectionDesc
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if desc {
+			return less(sorted[j], sorted[i])
+		}
+		return less(sorted[i], sorted[j])
+	})
+	return sorted
+}
+
 func filterEdges(edges []*pregel.Edge, first int, after *string) (filtered []*pregel.Edge, pi PageInfo) {
 	start, end := 0, len(edges)
 	if after != nil {
@@ -215,16 +469,39 @@ func createConnectionFrom(ctx context.Context, edges []*pregel.Edge, first int,
 	if err != nil {
 		return
 	}
-	for _, n := range nodes {
+	for i, n := range nodes {
 		if n == nil {
 			//TODO: Log the fact that we received an unexpected null record for one of the keys.
 			continue
 		}
-		ee := Edge{
-			Cursor: gqlid.Encode(n.ID),
-			Node:   n,
+		c.Edges = append(c.Edges, newEdge(edges[i], n))
+	}
+	return
+}
+
+// newEdge creates the GraphQL Edge of a pregel edge which leads to the node.
+func newEdge(e *pregel.Edge, n *pregel.Node) (ee Edge) {
+	ee = Edge{
+		Cursor:    gqlid.Encode(n.ID),
+		Node:      n,
+		Data:      []EdgeDataItem{},
+		JSON:      jsonData(e.Data),
+		CreatedAt: optionalTime(e.CreatedAt),
+		UpdatedAt: optionalTime(e.UpdatedAt),
+	}
+	for _, v := range e.Data {
+		if itm, ok := v.(EdgeDataItem); ok {
+			ee.Data = append(ee.Data, itm)
 		}
-		c.Edges = append(c.Edges, ee)
+	}
+	return
+}
+
+// jsonData copies pregel data into a map which can be returned as a JSON scalar.
+func jsonData(d pregel.Data) (data map[string]interface{}) {
+	data = make(map[string]interface{}, len(d))
+	for k, v := range d {
+		data[k] = v
 	}
 	return
 }
@@ -243,9 +520,234 @@ func joinErrs(errs []error) error {
 }
 
 // PregelQueryResolver resolves queries using pregel.
-type PregelQueryResolver struct{}
+type PregelQueryResolver struct {
+	// Store is used to list and search for nodes.
+	Store pregel.Storer
+	// Types are the types of the objects returned by the node query, in addition to GraphNode. If
+	// nil, the DefaultNodeTypes are used.
+	Types NodeTypes
+}
+
+// store returns the Store of the request's tenant, or the resolver's Store.
+func (pr *PregelQueryResolver) store(ctx context.Context) pregel.Storer {
+	return storeFor(ctx, pr.Store)
+}
 
 // Get a node by its ID.
 func (pr *PregelQueryResolver) Get(ctx context.Context, id string) (n *pregel.Node, err error) {
 	return FromContext(ctx).Load(id)
 }
+
+// Node gets an object by its global ID, so that Relay clients can refetch it. The type in the ID
+// is the type of the object, e.g. LocationNode, which must be GraphNode or one of the Types.
+func (pr *PregelQueryResolver) Node(ctx context.Context, id string) (n RelayNode, err error) {
+	typeName, nodeID, err := gqlid.DecodeGlobal(id)
+	if err != nil {
+		return
+	}
+	t, ok := nodeTypesOrDefault(pr.Types).Get(typeName)
+	if !ok {
+		err = fmt.Errorf("graph: unknown type %q in global ID", typeName)
+		return
+	}
+	pn, err := FromContext(ctx).Load(nodeID)
+	if err != nil || pn == nil {
+		return
+	}
+	// Nodes which aren't of the type are null, e.g. if the node's location has been removed.
+	if obj, ok := t.New(pn); ok {
+		n = obj
+	}
+	return
+}
+
+// Nodes returns a page of nodes which match the filter. If the filter has a label, the nodes are
+// queried by label, otherwise if it has a data type, they're queried by data type, otherwise every
+// node is listed. The rest of the filter is applied to each node, so a filter with only an ID
+// prefix reads the whole table. The cursors are the encoded IDs of the nodes.
+func (pr *PregelQueryResolver) Nodes(ctx context.Context, filter *NodeFilter, first int, after *string) (c *Connection, err error) {
+	var f NodeFilter
+	if filter != nil {
+		f = *filter
+	}
+	label, dataType, prefix := stringValue(f.Label), stringValue(f.DataType), stringValue(f.IDPrefix)
+	store := pr.store(ctx)
+	list := store.ListNodes
+	switch {
+	case label != "":
+		list = func(limit int, cursor string) ([]string, string, error) {
+			return store.QueryByLabel(label, limit, cursor)
+		}
+	case dataType != "":
+		list = func(limit int, cursor string) ([]string, string, error) {
+			return store.QueryByDataType(dataType, limit, cursor)
+		}
+	}
+	var cursor string
+	if after != nil {
+		if cursor, err = gqlid.Decode(*after); err != nil {
+			return
+		}
+	}
+	c = &Connection{
+		Edges: []Edge{},
+		PageInfo: PageInfo{
+			HasPreviousPage: cursor != "",
+		},
+	}
+	for {
+		ids, next, lErr := list(first, cursor)
+		if lErr != nil {
+			err = lErr
+			return
+		}
+		var matched []string
+		for _, id := range ids {
+			if strings.HasPrefix(id, prefix) {
+				matched = append(matched, id)
+			}
+		}
+		nodes, errs := FromContext(ctx).LoadAll(matched)
+		if err = joinErrs(errs); err != nil {
+			return
+		}
+		for _, n := range nodes {
+			if n == nil || (label != "" && dataType != "" && n.Data[dataType] == nil) {
+				continue
+			}
+			if first > 0 && len(c.Edges) == first {
+				c.PageInfo.HasNextPage = true
+				break
+			}
+			c.Edges = append(c.Edges, Edge{
+				Cursor: gqlid.Encode(n.ID),
+				Node:   n,
+				Data:   []EdgeDataItem{},
+				JSON:   map[string]interface{}{},
+			})
+		}
+		if next == "" || c.PageInfo.HasNextPage {
+			break
+		}
+		if first > 0 && len(c.Edges) == first {
+			// There may not be any more matching nodes, but finding out could read the whole table.
+			c.PageInfo.HasNextPage = true
+			break
+		}
+		cursor = next
+	}
+	c.TotalCount = len(c.Edges)
+	if len(c.Edges) > 0 {
+		sc, ec := c.Edges[0].Cursor, c.Edges[len(c.Edges)-1].Cursor
+		c.PageInfo.StartCursor, c.PageInfo.EndCursor = &sc, &ec
+	}
+	return
+}
+
+// DefaultPathDepth is the maximum number of edges in a path returned by the path query, if the
+// query doesn't set maxDepth.
+const DefaultPathDepth = 6
+
+// MaxPathDepth is the largest maxDepth accepted by the path query. Each level of the search reads
+// every node found at the previous level, so deep searches of well connected graphs are expensive.
+const MaxPathDepth = 10
+
+// Path returns the path with the fewest child edges between two nodes, or nil if there isn't a path
+// within maxDepth edges.
+func (pr *PregelQueryResolver) Path(ctx context.Context, from string, to string, maxDepth *int) (p *Path, err error) {
+	depth := DefaultPathDepth
+	if maxDepth != nil {
+		depth = *maxDepth
+	}
+	if depth > MaxPathDepth {
+		err = fmt.Errorf("graph: maxDepth must not be greater than %d", MaxPathDepth)
+		return
+	}
+	sp, ok, err := pr.store(ctx).ShortestPath(from, to, depth, pregel.TraversalOptions{})
+	if err != nil || !ok {
+		return
+	}
+	p = &Path{
+		Nodes: make([]*pregel.Node, len(sp.Nodes)),
+		Edges: make([]Edge, len(sp.Edges)),
+	}
+	for i := range sp.Nodes {
+		p.Nodes[i] = &sp.Nodes[i]
+	}
+	for i, e := range sp.Edges {
+		p.Edges[i] = newEdge(e, p.Nodes[i+1])
+	}
+	return
+}
+
+// MaxNeighborhoodDepth is the largest depth accepted by the neighborhood query.
+const MaxNeighborhoodDepth = 3
+
+// MaxNeighborhoodNodes is the maximum number of nodes returned by the neighborhood query, so that the
+// neighborhood of a well connected node doesn't read the whole graph.
+const MaxNeighborhoodNodes = 500
+
+// traversalDirections maps the GraphQL directions to pregel's.
+var traversalDirections = map[Direction]pregel.Direction{
+	DirectionChildren: pregel.DirectionChildren,
+	DirectionParents:  pregel.DirectionParents,
+	DirectionBoth:     pregel.DirectionBoth,
+}
+
+// Neighborhood returns the nodes up to depth edges away from the node, and the child edges between
+// them, so that a visualisation can be drawn from a single query. If the node doesn't exist, nil is
+// returned. The nodes are sorted by ID, and the edges by their parent and child.
+func (pr *PregelQueryResolver) Neighborhood(ctx context.Context, id string, depth int, direction *Direction) (sg *Subgraph, err error) {
+	if depth > MaxNeighborhoodDepth {
+		err = fmt.Errorf("graph: depth must not be greater than %d", MaxNeighborhoodDepth)
+		return
+	}
+	opts := pregel.TraversalOptions{MaxNodes: MaxNeighborhoodNodes}
+	if direction != nil {
+		opts.Direction = traversalDirections[*direction]
+	}
+	g, err := pr.store(ctx).GetSubgraph(id, depth, opts)
+	if err == pregel.ErrNodeNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return
+	}
+	ids := make([]string, 0, len(g.Nodes))
+	for id := range g.Nodes {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	sg = &Subgraph{
+		Root:  g.Root,
+		Nodes: make([]*pregel.Node, len(ids)),
+		Edges: []SubgraphEdge{},
+	}
+	for i, id := range ids {
+		n := g.Nodes[id]
+		sg.Nodes[i] = &n
+		for _, e := range n.Children {
+			if _, ok := g.Get(e.ID); !ok {
+				continue
+			}
+			sg.Edges = append(sg.Edges, SubgraphEdge{
+				Parent: n.ID,
+				Child:  e.ID,
+				Label:  optionalString(e.Label),
+				JSON:   jsonData(e.Data),
+			})
+		}
+	}
+	sort.SliceStable(sg.Edges, func(i, j int) bool {
+		a, b := sg.Edges[i], sg.Edges[j]
+		return a.Parent < b.Parent || (a.Parent == b.Parent && a.Child < b.Child)
+	})
+	return
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
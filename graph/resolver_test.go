@@ -0,0 +1,670 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/a-h/pregel"
+	"github.com/a-h/pregel/graph/gqlid"
+)
+
+// mockStorer implements the methods of pregel.Storer used by the mutation resolver. Calling any
+// other method panics.
+type mockStorer struct {
+	pregel.Storer
+	deleter    func(id string) error
+	edgePutter func(parent string, edges ...*pregel.Edge) error
+	dataPutter func(id string, data pregel.Data) error
+}
+
+func (ms *mockStorer) PutNodeData(id string, data pregel.Data) error {
+	return ms.dataPutter(id, data)
+}
+
+func (ms *mockStorer) Delete(id string) error {
+	return ms.deleter(id)
+}
+
+func (ms *mockStorer) PutEdges(parent string, edges ...*pregel.Edge) error {
+	return ms.edgePutter(parent, edges...)
+}
+
+var errStoreFailure = errors.New("store failure")
+
+func TestPregelMutationResolverRemoveNode(t *testing.T) {
+	tests := []struct {
+		name            string
+		deleteErr       error
+		expectedRemoved bool
+	}{
+		{
+			name:            "Deleted nodes are reported as removed",
+			expectedRemoved: true,
+		},
+		{
+			name:      "Store errors are returned",
+			deleteErr: errStoreFailure,
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			var actualID string
+			store := &mockStorer{
+				deleter: func(id string) error {
+					actualID = id
+					return test.deleteErr
+				},
+			}
+			r := &PregelMutationResolver{Store: store}
+			output, err := r.RemoveNode(context.Background(), RemoveNodeInput{ID: "a"})
+			if err != test.deleteErr {
+				t.Errorf("expected err %v, got %v", test.deleteErr, err)
+			}
+			if actualID != "a" {
+				t.Errorf("expected node 'a' to be deleted, got %q", actualID)
+			}
+			if output.Removed != test.expectedRemoved {
+				t.Errorf("expected removed %v, got %v", test.expectedRemoved, output.Removed)
+			}
+		})
+	}
+}
+
+func TestPregelMutationResolverSaveEdge(t *testing.T) {
+	var actualParent string
+	var actualEdges []*pregel.Edge
+	store := &mockStorer{
+		edgePutter: func(parent string, edges ...*pregel.Edge) error {
+			actualParent, actualEdges = parent, edges
+			return nil
+		},
+	}
+	r := &PregelMutationResolver{Store: store}
+	output, err := r.SaveEdge(context.Background(), SaveEdgeInput{
+		Parent:   "a",
+		Child:    "b",
+		Location: &LocationInput{Lat: 1, Lng: 2},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if output.Parent != "a" || output.Child != "b" {
+		t.Errorf("expected output for edge a -> b, got %+v", output)
+	}
+	if actualParent != "a" || len(actualEdges) != 1 || actualEdges[0].ID != "b" {
+		t.Fatalf("expected edge a -> b to be put, got %s -> %+v", actualParent, actualEdges)
+	}
+	if expected := (pregel.Data{"Location": Location{Lat: 1, Lng: 2}}); !reflect.DeepEqual(actualEdges[0].Data, expected) {
+		t.Errorf("expected edge data %v, got %v", expected, actualEdges[0].Data)
+	}
+}
+
+func TestMutationOutputNodes(t *testing.T) {
+	a := pregel.NewNode("a").WithChildren(pregel.NewEdge("b"))
+	b := pregel.NewNode("b").WithParents(pregel.NewEdge("a"))
+	ctx := withTestNodeLoader(context.Background(), a, b)
+	// The nodes were loaded before the mutation changed them.
+	stale := pregel.NewNode("a")
+	FromContext(ctx).Prime("a", &stale)
+
+	r := &Resolver{}
+	tests := []struct {
+		name     string
+		resolve  func() (*pregel.Node, error)
+		expected *pregel.Node
+	}{
+		{
+			name: "saveNode returns the saved node",
+			resolve: func() (*pregel.Node, error) {
+				return r.SaveNodeOutput().Node(ctx, &SaveNodeOutput{ID: "a"})
+			},
+			expected: &a,
+		},
+		{
+			name: "saveEdge returns the parent node",
+			resolve: func() (*pregel.Node, error) {
+				return r.SaveEdgeOutput().ParentNode(ctx, &SaveEdgeOutput{Parent: "a", Child: "b"})
+			},
+			expected: &a,
+		},
+		{
+			name: "saveEdge returns the child node",
+			resolve: func() (*pregel.Node, error) {
+				return r.SaveEdgeOutput().ChildNode(ctx, &SaveEdgeOutput{Parent: "a", Child: "b"})
+			},
+			expected: &b,
+		},
+		{
+			name: "setNodeFields returns the updated node",
+			resolve: func() (*pregel.Node, error) {
+				return r.SetNodeFieldsOutput().Node(ctx, &SetNodeFieldsOutput{ID: "a"})
+			},
+			expected: &a,
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			actual, err := test.resolve()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(actual, test.expected) {
+				t.Errorf("expected %+v, got %+v", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestPregelMutationResolverSetNodeData(t *testing.T) {
+	tests := []struct {
+		name         string
+		dataType     string
+		data         map[string]interface{}
+		expectedData pregel.Data
+	}{
+		{
+			name:         "Registered data types are converted",
+			dataType:     "Location",
+			data:         map[string]interface{}{"lat": 1.0, "lng": 2.0},
+			expectedData: pregel.Data{"Location": &Location{Lat: 1, Lng: 2}},
+		},
+		{
+			name:         "Other data types are stored as maps",
+			dataType:     "Other",
+			data:         map[string]interface{}{"name": "a"},
+			expectedData: pregel.Data{"Other": map[string]interface{}{"name": "a"}},
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			var actualData pregel.Data
+			store := &mockStorer{
+				dataPutter: func(id string, data pregel.Data) error {
+					actualData = data
+					return nil
+				},
+			}
+			r := &PregelMutationResolver{
+				Store: store,
+				DataTypes: map[string]func() interface{}{
+					"Location": func() interface{} { return &Location{} },
+				},
+			}
+			output, err := r.SetNodeData(context.Background(), SetNodeDataInput{ID: "a", Type: test.dataType, Data: test.data})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !output.Set {
+				t.Errorf("expected the data to be set")
+			}
+			if !reflect.DeepEqual(actualData, test.expectedData) {
+				t.Errorf("expected data %+v, got %+v", test.expectedData, actualData)
+			}
+		})
+	}
+}
+
+// withTestNodeLoader returns a context containing a node loader which loads the nodes.
+func withTestNodeLoader(ctx context.Context, nodes ...pregel.Node) context.Context {
+	l := NewNodeLoader(NodeLoaderConfig{
+		Fetch: func(ids []string) (loaded []*pregel.Node, errs []error) {
+			loaded, errs = make([]*pregel.Node, len(ids)), make([]error, len(ids))
+			for i, id := range ids {
+				for j := range nodes {
+					if nodes[j].ID == id {
+						loaded[i] = &nodes[j]
+					}
+				}
+			}
+			return
+		},
+		MaxBatch: 10,
+		Wait:     time.Millisecond,
+	})
+	return context.WithValue(ctx, nodeLoaderKey, l)
+}
+
+func TestPregelQueryResolverNode(t *testing.T) {
+	a := pregel.NewNode("a")
+	b := pregel.NewNode("b").WithData(&Location{Lat: 1, Lng: 2})
+	ctx := withTestNodeLoader(context.Background(), a, b)
+
+	tests := []struct {
+		name          string
+		id            string
+		expected      RelayNode
+		expectedError bool
+	}{
+		{
+			name:     "Nodes are found by their global ID",
+			id:       gqlid.EncodeGlobal("GraphNode", "a"),
+			expected: &a,
+		},
+		{
+			name: "Missing nodes are null",
+			id:   gqlid.EncodeGlobal("GraphNode", "c"),
+		},
+		{
+			name: "Nodes can be found by the ID of a more specific type",
+			id:   gqlid.EncodeGlobal("LocationNode", "b"),
+			expected: &LocationNode{
+				ID:       gqlid.EncodeGlobal("LocationNode", "b"),
+				NodeID:   "b",
+				Location: &Location{Lat: 1, Lng: 2},
+				Node:     &b,
+			},
+		},
+		{
+			name: "Nodes which aren't of the type are null",
+			id:   gqlid.EncodeGlobal("LocationNode", "a"),
+		},
+		{
+			name:          "IDs of other types are rejected",
+			id:            gqlid.EncodeGlobal("Edge", "a"),
+			expectedError: true,
+		},
+		{
+			name:          "IDs which aren't global IDs are rejected",
+			id:            "a",
+			expectedError: true,
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := &PregelQueryResolver{}
+			actual, err := r.Node(ctx, test.id)
+			if (err != nil) != test.expectedError {
+				t.Fatalf("expected error %v, got %v", test.expectedError, err)
+			}
+			if !reflect.DeepEqual(actual, test.expected) {
+				t.Errorf("expected %v, got %v", test.expected, actual)
+			}
+		})
+	}
+	t.Run("Node IDs are global IDs", func(t *testing.T) {
+		id, _ := (&PregelNodeResolver{}).ID(ctx, &a)
+		typeName, nodeID, err := gqlid.DecodeGlobal(id)
+		if err != nil || typeName != "GraphNode" || nodeID != "a" {
+			t.Errorf("expected GraphNode a, got %q %q %v", typeName, nodeID, err)
+		}
+	})
+}
+
+func TestCreateConnectionFromEdgeData(t *testing.T) {
+	ctx := withTestNodeLoader(context.Background(), pregel.NewNode("b"), pregel.NewNode("c"))
+	owner := map[string]interface{}{"name": "Ada"}
+	edges := []*pregel.Edge{
+		pregel.NewEdge("b").WithData(&Location{Lat: 1, Lng: 2}).WithNamedData("Owner", owner),
+		pregel.NewEdge("c"),
+	}
+	c, err := createConnectionFrom(ctx, edges, 10, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(c.Edges) != 2 {
+		t.Fatalf("expected 2 edges, got %d", len(c.Edges))
+	}
+	if expected := []EdgeDataItem{&Location{Lat: 1, Lng: 2}}; !reflect.DeepEqual(c.Edges[0].Data, expected) {
+		t.Errorf("expected data %v, got %v", expected, c.Edges[0].Data)
+	}
+	expectedJSON := map[string]interface{}{"Location": &Location{Lat: 1, Lng: 2}, "Owner": owner}
+	if !reflect.DeepEqual(c.Edges[0].JSON, expectedJSON) {
+		t.Errorf("expected JSON %v, got %v", expectedJSON, c.Edges[0].JSON)
+	}
+	if len(c.Edges[1].Data) != 0 || c.Edges[1].JSON == nil || len(c.Edges[1].JSON) != 0 {
+		t.Errorf("expected empty, non-nil data for edges without data, got %v and %v", c.Edges[1].Data, c.Edges[1].JSON)
+	}
+}
+
+func TestPregelQueryResolverNodes(t *testing.T) {
+	store := pregel.NewMemoryStore()
+	nodes := []pregel.Node{
+		pregel.NewNode("a1").WithLabels("x"),
+		pregel.NewNode("a2").WithData(Location{Lat: 1, Lng: 2}),
+		pregel.NewNode("b1").WithLabels("x").WithData(Location{Lat: 3, Lng: 4}),
+		pregel.NewNode("c"),
+	}
+	if err := store.Put(nodes...); err != nil {
+		t.Fatalf("unexpected error putting nodes: %v", err)
+	}
+	ctx := withTestNodeLoader(context.Background(), nodes...)
+	str := func(s string) *string { return &s }
+
+	tests := []struct {
+		name            string
+		filter          *NodeFilter
+		first           int
+		after           *string
+		expectedIDs     []string
+		expectedHasNext bool
+	}{
+		{
+			name:        "All nodes are listed without a filter",
+			expectedIDs: []string{"a1", "a2", "b1", "c"},
+		},
+		{
+			name:            "Nodes are listed in pages",
+			first:           2,
+			expectedIDs:     []string{"a1", "a2"},
+			expectedHasNext: true,
+		},
+		{
+			name:        "The next page starts after the cursor",
+			first:       2,
+			after:       str(gqlid.Encode("a2")),
+			expectedIDs: []string{"b1", "c"},
+		},
+		{
+			name:        "Nodes can be filtered by ID prefix",
+			filter:      &NodeFilter{IDPrefix: str("a")},
+			first:       10,
+			expectedIDs: []string{"a1", "a2"},
+		},
+		{
+			name:        "Nodes can be filtered by label",
+			filter:      &NodeFilter{Label: str("x")},
+			first:       10,
+			expectedIDs: []string{"a1", "b1"},
+		},
+		{
+			name:        "Nodes can be filtered by data type",
+			filter:      &NodeFilter{DataType: str("Location")},
+			first:       10,
+			expectedIDs: []string{"a2", "b1"},
+		},
+		{
+			name:        "Filters are combined",
+			filter:      &NodeFilter{IDPrefix: str("b"), Label: str("x"), DataType: str("Location")},
+			first:       10,
+			expectedIDs: []string{"b1"},
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := &PregelQueryResolver{Store: store}
+			c, err := r.Nodes(ctx, test.filter, test.first, test.after)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var actualIDs []string
+			for _, e := range c.Edges {
+				actualIDs = append(actualIDs, e.Node.ID)
+			}
+			if !reflect.DeepEqual(actualIDs, test.expectedIDs) {
+				t.Errorf("expected %v, got %v", test.expectedIDs, actualIDs)
+			}
+			if c.PageInfo.HasNextPage != test.expectedHasNext {
+				t.Errorf("expected hasNextPage %v, got %v", test.expectedHasNext, c.PageInfo.HasNextPage)
+			}
+		})
+	}
+}
+
+func TestPregelQueryResolverPath(t *testing.T) {
+	store := pregel.NewMemoryStore()
+	store.RegisterDataType(func() interface{} {
+		return &Location{}
+	})
+	nodes := []pregel.Node{
+		pregel.NewNode("a").WithChildren(pregel.NewEdge("b").WithData(&Location{Lat: 1, Lng: 2})),
+		pregel.NewNode("b").WithChildren(pregel.NewEdge("c")),
+		pregel.NewNode("c"),
+		pregel.NewNode("d"),
+	}
+	if err := store.Put(nodes...); err != nil {
+		t.Fatalf("unexpected error putting nodes: %v", err)
+	}
+	depth := func(d int) *int { return &d }
+
+	tests := []struct {
+		name          string
+		from, to      string
+		maxDepth      *int
+		expectedIDs   []string
+		expectedError bool
+	}{
+		{
+			name:        "The nodes of the path are returned in order",
+			from:        "a",
+			to:          "c",
+			expectedIDs: []string{"a", "b", "c"},
+		},
+		{
+			name:     "Paths longer than the maximum depth aren't returned",
+			from:     "a",
+			to:       "c",
+			maxDepth: depth(1),
+		},
+		{
+			name: "Unconnected nodes don't have a path",
+			from: "a",
+			to:   "d",
+		},
+		{
+			name: "Child edges are followed",
+			from: "c",
+			to:   "a",
+		},
+		{
+			name:          "The maximum depth is limited",
+			from:          "a",
+			to:            "c",
+			maxDepth:      depth(MaxPathDepth + 1),
+			expectedError: true,
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := &PregelQueryResolver{Store: store}
+			p, err := r.Path(context.Background(), test.from, test.to, test.maxDepth)
+			if (err != nil) != test.expectedError {
+				t.Fatalf("expected error %v, got %v", test.expectedError, err)
+			}
+			if test.expectedIDs == nil {
+				if p != nil {
+					t.Errorf("expected no path, got %+v", p)
+				}
+				return
+			}
+			var ids []string
+			for _, n := range p.Nodes {
+				ids = append(ids, n.ID)
+			}
+			if !reflect.DeepEqual(ids, test.expectedIDs) {
+				t.Errorf("expected nodes %v, got %v", test.expectedIDs, ids)
+			}
+			if len(p.Edges) != len(p.Nodes)-1 {
+				t.Fatalf("expected %d edges, got %d", len(p.Nodes)-1, len(p.Edges))
+			}
+			for i, e := range p.Edges {
+				if e.Node != p.Nodes[i+1] {
+					t.Errorf("expected edge %d to lead to %s, got %v", i, p.Nodes[i+1].ID, e.Node)
+				}
+			}
+			if expected := []EdgeDataItem{&Location{Lat: 1, Lng: 2}}; !reflect.DeepEqual(p.Edges[0].Data, expected) {
+				t.Errorf("expected data %v, got %v", expected, p.Edges[0].Data)
+			}
+		})
+	}
+}
+
+func TestPregelQueryResolverNeighborhood(t *testing.T) {
+	store := pregel.NewMemoryStore()
+	nodes := []pregel.Node{
+		pregel.NewNode("a").WithChildren(pregel.NewEdge("b").WithLabel("uplink"), pregel.NewEdge("c")),
+		pregel.NewNode("b").WithChildren(pregel.NewEdge("d")),
+		pregel.NewNode("c"),
+		pregel.NewNode("d"),
+	}
+	if err := store.Put(nodes...); err != nil {
+		t.Fatalf("unexpected error putting nodes: %v", err)
+	}
+	parents := DirectionParents
+
+	tests := []struct {
+		name          string
+		id            string
+		depth         int
+		direction     *Direction
+		expectedIDs   []string
+		expectedEdges []string
+		expectedNil   bool
+		expectedError bool
+	}{
+		{
+			name:          "Only edges between the nodes of the neighborhood are returned",
+			id:            "a",
+			depth:         1,
+			expectedIDs:   []string{"a", "b", "c"},
+			expectedEdges: []string{"a-b", "a-c"},
+		},
+		{
+			name:          "Deeper neighborhoods include more nodes",
+			id:            "a",
+			depth:         2,
+			expectedIDs:   []string{"a", "b", "c", "d"},
+			expectedEdges: []string{"a-b", "a-c", "b-d"},
+		},
+		{
+			name:          "The direction of the edges can be set",
+			id:            "d",
+			depth:         2,
+			direction:     &parents,
+			expectedIDs:   []string{"a", "b", "d"},
+			expectedEdges: []string{"a-b", "b-d"},
+		},
+		{
+			name:        "Missing nodes are null",
+			id:          "e",
+			depth:       1,
+			expectedNil: true,
+		},
+		{
+			name:          "The depth is limited",
+			id:            "a",
+			depth:         MaxNeighborhoodDepth + 1,
+			expectedNil:   true,
+			expectedError: true,
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := &PregelQueryResolver{Store: store}
+			sg, err := r.Neighborhood(context.Background(), test.id, test.depth, test.direction)
+			if (err != nil) != test.expectedError {
+				t.Fatalf("expected error %v, got %v", test.expectedError, err)
+			}
+			if test.expectedNil {
+				if sg != nil {
+					t.Errorf("expected nil, got %+v", sg)
+				}
+				return
+			}
+			var ids, edges []string
+			for _, n := range sg.Nodes {
+				ids = append(ids, n.ID)
+			}
+			for _, e := range sg.Edges {
+				edges = append(edges, e.Parent+"-"+e.Child)
+			}
+			if !reflect.DeepEqual(ids, test.expectedIDs) {
+				t.Errorf("expected nodes %v, got %v", test.expectedIDs, ids)
+			}
+			if !reflect.DeepEqual(edges, test.expectedEdges) {
+				t.Errorf("expected edges %v, got %v", test.expectedEdges, edges)
+			}
+			if sg.Root != test.id {
+				t.Errorf("expected root %q, got %q", test.id, sg.Root)
+			}
+		})
+	}
+}
+
+func TestPregelMutationResolverRemoveData(t *testing.T) {
+	store := pregel.NewMemoryStore()
+	err := store.Put(pregel.NewNode("a").
+		WithData(Location{Lat: 1, Lng: 2}).
+		WithChildren(pregel.NewEdge("b").WithData(Location{Lat: 3, Lng: 4}),
+			pregel.NewEdge("c").WithData(Location{Lat: 5, Lng: 6}).WithLabel("link")))
+	if err != nil {
+		t.Fatalf("unexpected error putting nodes: %v", err)
+	}
+	r := &PregelMutationResolver{Store: store}
+
+	nodeOutput, err := r.RemoveNodeData(context.Background(), RemoveNodeDataInput{ID: "a", Type: "Location"})
+	if err != nil || !nodeOutput.Removed {
+		t.Fatalf("expected node data to be removed, got %v, %v", nodeOutput.Removed, err)
+	}
+	edgeOutput, err := r.RemoveEdgeData(context.Background(), RemoveEdgeDataInput{Parent: "a", Child: "b", Type: "Location"})
+	if err != nil || !edgeOutput.Removed {
+		t.Fatalf("expected edge data to be removed, got %v, %v", edgeOutput.Removed, err)
+	}
+	label := "link"
+	edgeOutput, err = r.RemoveEdgeData(context.Background(), RemoveEdgeDataInput{Parent: "a", Child: "c", Label: &label, Type: "Location"})
+	if err != nil || !edgeOutput.Removed {
+		t.Fatalf("expected labelled edge data to be removed, got %v, %v", edgeOutput.Removed, err)
+	}
+
+	n, ok, err := store.Get("a")
+	if err != nil || !ok {
+		t.Fatalf("expected node a to be found, got %v, %v", ok, err)
+	}
+	if len(n.Data) != 0 {
+		t.Errorf("expected the node data to be removed, got %v", n.Data)
+	}
+	if len(n.Children) != 2 || len(n.Children[0].Data) != 0 || len(n.Children[1].Data) != 0 {
+		t.Errorf("expected the edges to b and c to remain without data, got %+v", n.Children)
+	}
+}
+
+func TestPregelMutationResolverImportGraph(t *testing.T) {
+	store := pregel.NewMemoryStore()
+	store.RegisterDataType(func() interface{} { return &Location{} })
+	r := &PregelMutationResolver{Store: store}
+	file := `{"id":"a","data":{"Location":{"lng":1,"lat":2}},"children":[{"id":"b"}]}` + "\n" +
+		`{"id":"b","parents":[{"id":"a"}]}` + "\n"
+
+	output, err := r.ImportGraph(context.Background(), graphql.Upload{File: strings.NewReader(file)})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if output.NodesRead != 2 {
+		t.Errorf("expected 2 nodes to be read, got %d", output.NodesRead)
+	}
+	n, ok, err := store.Get("a")
+	if err != nil || !ok {
+		t.Fatalf("expected node a to be imported, got %v, %v", ok, err)
+	}
+	if l, ok := n.Data["Location"].(*Location); !ok || *l != (Location{Lng: 1, Lat: 2}) {
+		t.Errorf("expected the location data to be imported, got %#v", n.Data["Location"])
+	}
+
+	r = &PregelMutationResolver{Store: &mockStorer{}}
+	if _, err := r.ImportGraph(context.Background(), graphql.Upload{File: strings.NewReader(file)}); err != ErrImportNotSupported {
+		t.Errorf("expected ErrImportNotSupported, got %v", err)
+	}
+}
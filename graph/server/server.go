@@ -1,46 +1,115 @@
 package main
 
 import (
+	"context"
 	"log"
 	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/99designs/gqlgen/handler"
 	"github.com/a-h/pregel"
 	"github.com/a-h/pregel/graph"
+	"github.com/a-h/pregel/graph/config"
 )
 
-const defaultPort = "8080"
+const (
+	readTimeout  = 10 * time.Second
+	writeTimeout = 30 * time.Second
+	idleTimeout  = 2 * time.Minute
+	// shutdownTimeout is the time that requests in flight have to complete when the server stops.
+	shutdownTimeout = 30 * time.Second
+)
 
 func main() {
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = defaultPort
-	}
-
-	store, err := pregel.NewStore("eu-west-2", "pregelStoreLocal")
+	defaults := config.Default()
+	defaults.Region = "eu-west-2"
+	defaults.TableName = "pregelStoreLocal"
+	c, err := config.FromEnvironment(defaults)
 	if err != nil {
 		log.Fatal(err)
 	}
+
+	// Set PREGEL_STORE=memory to run the server without AWS.
+	var store *pregel.Store
+	if c.Store == "memory" {
+		store = pregel.NewMemoryStore()
+	} else {
+		store, err = pregel.NewStore(c.Region, c.TableName)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
 	store.RegisterDataType(func() interface{} {
 		return &graph.Location{}
 	})
+	// Subscriptions receive the changes made through this server over WebSockets.
+	changes := pregel.NewDispatcher()
+	store.EmitChanges(changes)
 
-	http.Handle("/", handler.Playground("GraphQL playground", "/query"))
+	if c.Playground {
+		http.Handle("/", handler.Playground("GraphQL playground", "/query"))
+	}
 	root := &graph.Resolver{
 		MutationResolver: &graph.PregelMutationResolver{
-			Store: store,
+			Store:     store,
+			DataTypes: store.DataTypes,
 		},
-		NodeResolver:  &graph.PregelNodeResolver{},
-		QueryResolver: &graph.PregelQueryResolver{},
+		NodeResolver:         &graph.PregelNodeResolver{},
+		QueryResolver:        &graph.PregelQueryResolver{Store: store},
+		SubscriptionResolver: &graph.PregelSubscriptionResolver{Changes: graph.NewChangeBroker(changes)},
 	}
 
-	h := handler.GraphQL(graph.NewExecutableSchema(graph.Config{Resolvers: root}))
+	h := handler.GraphQL(graph.NewExecutableSchema(graph.NewConfig(root)),
+		handler.ComplexityLimit(graph.DefaultComplexityLimit))
 	statsLogger := func(stats graph.NodeDataLoaderStats) {
 		log.Printf("stats: %+v\n", stats)
 	}
-	http.Handle("/query", graph.WithNodeDataloaderMiddleware(store, statsLogger, h))
+	apq := graph.WithPersistedQueryMiddleware(graph.NewMemoryQueryCache(1000), h)
+	loader := graph.WithNodeDataloaderMiddleware(store, statsLogger, apq)
+	c.ConfigureLoader(loader)
+	// Set PREGEL_TENANTS=header to give each tenant its own namespace of the table.
+	var next http.Handler = loader
+	selectTenant, ok, err := c.TenantSelector()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if ok {
+		next = graph.WithTenantMiddleware(selectTenant, graph.NamespaceTenants(store), loader)
+	}
+	cors := graph.WithCORSMiddleware(c.CORS.Origins, next)
+	if err = c.ConfigureCORS(cors); err != nil {
+		log.Fatal(err)
+	}
+	cors.MaxAge = 10 * time.Minute
+	http.Handle("/query", graph.WithSecurityHeadersMiddleware(cors))
+	http.Handle("/healthz", graph.HealthHandler())
+	http.Handle("/readyz", graph.ReadinessHandler(store))
 
-	log.Printf("connect to http://localhost:%s/ for GraphQL playground", port)
-	log.Fatal(http.ListenAndServe(":"+port, nil))
+	srv := &http.Server{
+		Addr:         ":" + c.Port,
+		ReadTimeout:  readTimeout,
+		WriteTimeout: writeTimeout,
+		IdleTimeout:  idleTimeout,
+	}
+	go func() {
+		log.Printf("connect to http://localhost:%s/ for GraphQL playground", c.Port)
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Fatal(err)
+		}
+	}()
+
+	// Stop accepting connections on SIGTERM, and wait for the requests in flight to complete, so
+	// that mutations aren't interrupted during deploys.
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, syscall.SIGTERM, os.Interrupt)
+	<-stop
+	log.Printf("shutting down")
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(ctx); err != nil {
+		log.Printf("failed to shut down gracefully: %v", err)
+	}
 }
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/a-h/pregel"
+	"github.com/a-h/pregel/db"
+	"github.com/a-h/pregel/graph"
+	"github.com/a-h/pregel/graph/config"
+	"github.com/a-h/pregel/stream"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/apigatewaymanagementapi"
+	"github.com/aws/aws-sdk-go/service/dynamodbstreams"
+	"github.com/vektah/gqlparser"
+	"github.com/vektah/gqlparser/ast"
+	"github.com/vektah/gqlparser/validator"
+)
+
+// streamEvent is the event passed to a Lambda function by a DynamoDB stream. The records are
+// decoded into the types used by the stream package, apart from the creation time, which is sent
+// as seconds since the epoch.
+type streamEvent struct {
+	Records []struct {
+		EventName string `json:"eventName"`
+		Dynamodb  struct {
+			ApproximateCreationDateTime float64                                    `json:"ApproximateCreationDateTime"`
+			Keys                        map[string]*dynamodbstreams.AttributeValue `json:"Keys"`
+			NewImage                    map[string]*dynamodbstreams.AttributeValue `json:"NewImage"`
+			OldImage                    map[string]*dynamodbstreams.AttributeValue `json:"OldImage"`
+		} `json:"dynamodb"`
+	} `json:"Records"`
+}
+
+func (e streamEvent) records() (records []*dynamodbstreams.Record) {
+	for _, r := range e.Records {
+		sec := int64(r.Dynamodb.ApproximateCreationDateTime)
+		records = append(records, &dynamodbstreams.Record{
+			EventName: aws.String(r.EventName),
+			Dynamodb: &dynamodbstreams.StreamRecord{
+				ApproximateCreationDateTime: aws.Time(time.Unix(sec, 0)),
+				Keys:                        r.Dynamodb.Keys,
+				NewImage:                    r.Dynamodb.NewImage,
+				OldImage:                    r.Dynamodb.OldImage,
+			},
+		})
+	}
+	return
+}
+
+// execute runs a subscription against the changes, using the same schema and authorization rules
+// as the handler.
+func execute(ctx context.Context, sub graph.Subscription, changes graph.ChangeSubscriber) (responses []json.RawMessage, err error) {
+	root := &graph.Resolver{
+		NodeResolver:         &graph.PregelNodeResolver{},
+		SubscriptionResolver: &graph.PregelSubscriptionResolver{Changes: changes},
+	}
+	root = graph.NewAuthorizedResolver(root, graph.RequireScopes("pregel:read", "pregel:write"))
+	es := graph.NewExecutableSchema(graph.NewConfig(root))
+
+	doc, errs := gqlparser.LoadQuery(es.Schema(), sub.Query)
+	if errs != nil {
+		return nil, errs
+	}
+	op := doc.Operations.ForName(sub.OperationName)
+	if op == nil || op.Operation != ast.Subscription {
+		return nil, fmt.Errorf("operation %q is not a subscription", sub.OperationName)
+	}
+	vars, varErr := validator.VariableValues(es.Schema(), op, sub.Variables)
+	if varErr != nil {
+		return nil, varErr
+	}
+	ctx = graphql.WithRequestContext(ctx, graphql.NewRequestContext(doc, sub.Query, vars))
+	next := es.Subscription(ctx, op)
+	for {
+		resp := next()
+		if resp == nil {
+			return
+		}
+		var r []byte
+		if r, err = json.Marshal(resp); err != nil {
+			return
+		}
+		responses = append(responses, r)
+	}
+}
+
+// The consumer of the pregel table's stream, which publishes the changes to the subscriptions
+// stored by the wshandler.
+func main() {
+	c, err := config.FromEnvironment(config.Default())
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err = c.Require(config.Region, config.SubscriptionTableName, config.WebSocketEndpoint); err != nil {
+		log.Fatal(err)
+	}
+
+	client, err := db.New(c.Region, c.SubscriptionTableName)
+	if err != nil {
+		log.Fatal(err)
+	}
+	sess, err := session.NewSession(&aws.Config{Region: aws.String(c.Region)})
+	if err != nil {
+		log.Fatal(err)
+	}
+	publisher := &graph.SubscriptionPublisher{
+		Subscriptions: graph.NewSubscriptionStore(client),
+		Execute:       execute,
+		Post:          graph.NewAPIGatewayConnectionPoster(apigatewaymanagementapi.New(sess, aws.NewConfig().WithEndpoint(c.WebSocketEndpoint))),
+		// Set PREGEL_TENANTS to the same value as the wshandler's, so that each subscription only
+		// receives the changes in its tenant's namespace.
+		Tenants: c.Tenants != "",
+	}
+
+	lambda.Start(func(ctx context.Context, e streamEvent) (err error) {
+		// Collect the changes, so that each subscription is run once for the whole batch.
+		var changes []pregel.ChangeEvent
+		d := pregel.NewDispatcher()
+		d.Subscribe(pregel.ChangeHandlerFunc(func(ctx context.Context, ce pregel.ChangeEvent) error {
+			changes = append(changes, ce)
+			return nil
+		}))
+		if err = stream.Dispatch(ctx, e.records(), d); err != nil {
+			return
+		}
+		return publisher.Publish(ctx, changes...)
+	})
+}
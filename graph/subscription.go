@@ -0,0 +1,141 @@
+package graph
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/a-h/pregel"
+)
+
+// ChangeSubscriber provides the ChangeEvents which match a filter. The channel is closed when the
+// context is done, or when there are no more events.
+type ChangeSubscriber interface {
+	Subscribe(ctx context.Context, match func(e pregel.ChangeEvent) bool) <-chan pregel.ChangeEvent
+}
+
+// DefaultChangeBufferSize is the number of events buffered for each subscriber of a ChangeBroker.
+const DefaultChangeBufferSize = 64
+
+// ChangeBroker passes the ChangeEvents dispatched by a pregel.Dispatcher to subscriptions, e.g. the
+// events of a Store's EmitChanges hook, so that a server can serve subscriptions over WebSockets.
+// Events aren't sent to subscribers which have BufferSize events waiting, so that a slow subscriber
+// doesn't block the writes which dispatch the events.
+type ChangeBroker struct {
+	BufferSize  int
+	m           sync.Mutex
+	subscribers map[chan pregel.ChangeEvent]func(e pregel.ChangeEvent) bool
+}
+
+// NewChangeBroker creates a ChangeBroker which subscribes to the dispatcher.
+func NewChangeBroker(d *pregel.Dispatcher) *ChangeBroker {
+	b := &ChangeBroker{
+		BufferSize:  DefaultChangeBufferSize,
+		subscribers: make(map[chan pregel.ChangeEvent]func(e pregel.ChangeEvent) bool),
+	}
+	d.Subscribe(b)
+	return b
+}
+
+// HandleChange sends the event to each subscriber which it matches.
+func (b *ChangeBroker) HandleChange(ctx context.Context, e pregel.ChangeEvent) error {
+	b.m.Lock()
+	defer b.m.Unlock()
+	for c, match := range b.subscribers {
+		if !match(e) {
+			continue
+		}
+		select {
+		case c <- e:
+		default:
+		}
+	}
+	return nil
+}
+
+// Subscribe to the events which match, until the context is done.
+func (b *ChangeBroker) Subscribe(ctx context.Context, match func(e pregel.ChangeEvent) bool) <-chan pregel.ChangeEvent {
+	c := make(chan pregel.ChangeEvent, b.BufferSize)
+	b.m.Lock()
+	b.subscribers[c] = match
+	b.m.Unlock()
+	go func() {
+		<-ctx.Done()
+		b.m.Lock()
+		defer b.m.Unlock()
+		delete(b.subscribers, c)
+		close(c)
+	}()
+	return c
+}
+
+// ChangeList is a ChangeSubscriber which provides a fixed list of events, e.g. the events decoded
+// from a batch of DynamoDB stream records.
+type ChangeList []pregel.ChangeEvent
+
+// Subscribe to the events in the list which match.
+func (cl ChangeList) Subscribe(ctx context.Context, match func(e pregel.ChangeEvent) bool) <-chan pregel.ChangeEvent {
+	c := make(chan pregel.ChangeEvent, len(cl))
+	for _, e := range cl {
+		if match(e) {
+			c <- e
+		}
+	}
+	close(c)
+	return c
+}
+
+// PregelSubscriptionResolver resolves subscriptions.
+type PregelSubscriptionResolver struct {
+	Changes ChangeSubscriber
+}
+
+// NodeChanged returns the changes to the node, including changes to the edges to and from it. If
+// the id isn't set, every change is returned. If there's a tenant in the context, only the changes in
+// the tenant's namespace are returned, with the namespace removed from their IDs.
+func (sr *PregelSubscriptionResolver) NodeChanged(ctx context.Context, id *string) (<-chan *ChangeEvent, error) {
+	inTenant := func(e pregel.ChangeEvent) (pregel.ChangeEvent, bool) {
+		return e, true
+	}
+	if tenant, ok := TenantFromContext(ctx); ok {
+		inTenant = func(e pregel.ChangeEvent) (pregel.ChangeEvent, bool) {
+			return e.InNamespace(tenant)
+		}
+	}
+	events := sr.Changes.Subscribe(ctx, func(e pregel.ChangeEvent) bool {
+		e, ok := inTenant(e)
+		return ok && (id == nil || e.NodeID == *id || e.Child == *id)
+	})
+	results := make(chan *ChangeEvent)
+	go func() {
+		defer close(results)
+		for e := range events {
+			e, _ = inTenant(e)
+			select {
+			case results <- newChangeEvent(e):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return results, nil
+}
+
+func newChangeEvent(e pregel.ChangeEvent) *ChangeEvent {
+	return &ChangeEvent{
+		Type:     string(e.Type),
+		NodeID:   e.NodeID,
+		Child:    optionalString(e.Child),
+		Label:    optionalString(e.Label),
+		DataType: optionalString(e.DataType),
+		New:      e.New,
+		Time:     e.Time.UTC().Format(time.RFC3339Nano),
+	}
+}
+
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
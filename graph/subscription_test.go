@@ -0,0 +1,111 @@
+package graph
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/a-h/pregel"
+)
+
+func TestChangeBroker(t *testing.T) {
+	d := pregel.NewDispatcher()
+	b := NewChangeBroker(d)
+	sr := &PregelSubscriptionResolver{Changes: b}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	id := "a"
+	results, err := sr.NodeChanged(ctx, &id)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	all, err := sr.NodeChanged(ctx, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	events := []pregel.ChangeEvent{
+		{Type: pregel.NodeCreated, NodeID: "b"},
+		{Type: pregel.EdgeCreated, NodeID: "b", Child: "a", Label: "knows"},
+		{Type: pregel.DataCreated, NodeID: "a", DataType: "location", New: map[string]interface{}{"lat": 1.0}},
+	}
+	if err := d.Dispatch(ctx, events...); err != nil {
+		t.Fatalf("unexpected error dispatching: %v", err)
+	}
+
+	receive := func(c <-chan *ChangeEvent) *ChangeEvent {
+		select {
+		case e := <-c:
+			return e
+		case <-time.After(time.Second):
+			t.Fatalf("timed out waiting for an event")
+		}
+		return nil
+	}
+	if e := receive(results); e.Type != string(pregel.EdgeCreated) || e.Child == nil || *e.Child != "a" || *e.Label != "knows" {
+		t.Errorf("expected the edge to a, got %+v", e)
+	}
+	if e := receive(results); e.Type != string(pregel.DataCreated) || *e.DataType != "location" || e.New["lat"] != 1.0 {
+		t.Errorf("expected the data of a, got %+v", e)
+	}
+	for i := range events {
+		if e := receive(all); e.NodeID != events[i].NodeID || e.Type != string(events[i].Type) {
+			t.Errorf("%d: expected %+v, got %+v", i, events[i], e)
+		}
+	}
+
+	cancel()
+	select {
+	case _, ok := <-results:
+		if ok {
+			t.Errorf("expected no more events after the subscription is cancelled")
+		}
+	case <-time.After(time.Second):
+		t.Errorf("expected the results to be closed when the subscription is cancelled")
+	}
+}
+
+func TestChangeList(t *testing.T) {
+	sr := &PregelSubscriptionResolver{
+		Changes: ChangeList{
+			{Type: pregel.NodeCreated, NodeID: "a"},
+			{Type: pregel.NodeCreated, NodeID: "b"},
+			{Type: pregel.NodeDeleted, NodeID: "a"},
+		},
+	}
+	id := "a"
+	results, err := sr.NodeChanged(context.Background(), &id)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var types []string
+	for e := range results {
+		types = append(types, e.Type)
+	}
+	if len(types) != 2 || types[0] != string(pregel.NodeCreated) || types[1] != string(pregel.NodeDeleted) {
+		t.Errorf("expected the changes to a, got %v", types)
+	}
+}
+
+func TestPregelSubscriptionResolverTenant(t *testing.T) {
+	sr := &PregelSubscriptionResolver{
+		Changes: ChangeList{
+			{Type: pregel.NodeCreated, NodeID: "acme/a"},
+			{Type: pregel.NodeCreated, NodeID: "other/a"},
+			{Type: pregel.EdgeCreated, NodeID: "acme/b", Child: "a"},
+		},
+	}
+	id := "a"
+	results, err := sr.NodeChanged(WithTenant(context.Background(), "acme"), &id)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var nodeIDs []string
+	for e := range results {
+		nodeIDs = append(nodeIDs, e.NodeID)
+	}
+	if len(nodeIDs) != 2 || nodeIDs[0] != "a" || nodeIDs[1] != "b" {
+		t.Errorf("expected only the tenant's changes, without the namespace, got %v", nodeIDs)
+	}
+}
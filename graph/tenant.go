@@ -0,0 +1,133 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/a-h/pregel"
+)
+
+// ErrMissingTenant is returned by a TenantSelector when the request doesn't identify a tenant.
+var ErrMissingTenant = errors.New("graph: missing tenant")
+
+// TenantHeader is the HTTP header read by TenantFromHeader.
+const TenantHeader = "X-Pregel-Tenant"
+
+// TenantSelector returns the tenant of a HTTP request, or ErrMissingTenant.
+type TenantSelector func(r *http.Request) (tenant string, err error)
+
+// TenantFromHeader selects the tenant named by the header. Callers can choose any tenant, so it
+// should only be used when every caller is trusted, or the API is behind a gateway which sets the
+// header.
+func TenantFromHeader(header string) TenantSelector {
+	return func(r *http.Request) (tenant string, err error) {
+		if tenant = r.Header.Get(header); tenant == "" {
+			err = ErrMissingTenant
+		}
+		return
+	}
+}
+
+// TenantFromPrincipal selects the tenant of the Principal added to the request's context by the
+// AuthMiddleware, e.g. the tenant claim of a JWT.
+func TenantFromPrincipal() TenantSelector {
+	return func(r *http.Request) (tenant string, err error) {
+		if p, ok := PrincipalFromContext(r.Context()); ok {
+			tenant = p.Tenant
+		}
+		if tenant == "" {
+			err = ErrMissingTenant
+		}
+		return
+	}
+}
+
+// NamespaceTenants returns a function which gives each tenant a namespace of the Store, so that
+// tenants can share a table without seeing each other's nodes. Tenants which aren't valid
+// namespaces, e.g. "a/b", are rejected with pregel.ErrInvalidNamespace.
+func NamespaceTenants(s *pregel.Store) func(tenant string) (pregel.Storer, error) {
+	return func(tenant string) (pregel.Storer, error) {
+		ns, err := s.WithNamespace(tenant)
+		if err != nil {
+			return nil, err
+		}
+		return ns, nil
+	}
+}
+
+type storeKey struct{}
+
+type tenantKey struct{}
+
+// WithTenant returns a context containing the tenant of the request, which restricts subscriptions
+// to the changes in the tenant's namespace.
+func WithTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenant)
+}
+
+// TenantFromContext returns the tenant added to the context by the TenantMiddleware.
+func TenantFromContext(ctx context.Context) (tenant string, ok bool) {
+	tenant, ok = ctx.Value(tenantKey{}).(string)
+	return
+}
+
+// WithStore returns a context containing the Store used by the resolvers and the node loader
+// instead of their own Store.
+func WithStore(ctx context.Context, s pregel.Storer) context.Context {
+	return context.WithValue(ctx, storeKey{}, s)
+}
+
+// StoreFromContext returns the Store added to the context by the TenantMiddleware.
+func StoreFromContext(ctx context.Context) (s pregel.Storer, ok bool) {
+	s, ok = ctx.Value(storeKey{}).(pregel.Storer)
+	return
+}
+
+// storeFor returns the Store in the context, or the default Store if there isn't one.
+func storeFor(ctx context.Context, defaultStore pregel.Storer) pregel.Storer {
+	if s, ok := StoreFromContext(ctx); ok {
+		return s
+	}
+	return defaultStore
+}
+
+// TenantMiddleware selects the Store of each request's tenant, so that a single endpoint can serve
+// many tenants with isolated graphs, and adds the tenant to the context, so that subscriptions only
+// receive the changes in the tenant's namespace. Requests without a tenant, or with a tenant which isn't a valid
+// namespace, are rejected with a 400 status. If
+// the tenant is selected using the Principal, the middleware must be run after the AuthMiddleware,
+// and before the NodeDataLoaderMiddlware, so that nodes are loaded from the tenant's Store.
+type TenantMiddleware struct {
+	Next   http.Handler
+	Select TenantSelector
+	Store  func(tenant string) (pregel.Storer, error)
+}
+
+func (tm *TenantMiddleware) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	tenant, err := tm.Select(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	s, err := tm.Store(tenant)
+	if err == pregel.ErrInvalidNamespace {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	ctx := WithTenant(WithStore(r.Context(), s), tenant)
+	tm.Next.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// WithTenantMiddleware passes each request to the next handler with the Store of its tenant.
+func WithTenantMiddleware(selectTenant TenantSelector, store func(tenant string) (pregel.Storer, error), next http.Handler) *TenantMiddleware {
+	return &TenantMiddleware{
+		Next:   next,
+		Select: selectTenant,
+		Store:  store,
+	}
+}
@@ -0,0 +1,103 @@
+package graph
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/a-h/pregel"
+)
+
+func TestTenantMiddleware(t *testing.T) {
+	store := pregel.NewMemoryStore()
+	mr := &PregelMutationResolver{Store: store}
+	qr := &PregelQueryResolver{Store: store}
+
+	var loaded *pregel.Node
+	var err error
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		if r.Method == http.MethodPost {
+			_, err = mr.SaveNode(ctx, SaveNodeInput{ID: "a"})
+			return
+		}
+		loaded, err = qr.Get(ctx, "a")
+	})
+	h := WithTenantMiddleware(TenantFromHeader(TenantHeader), NamespaceTenants(store),
+		WithNodeDataloaderMiddleware(store, nil, next))
+	serve := func(method, tenant string) int {
+		r := httptest.NewRequest(method, "/query", nil)
+		if tenant != "" {
+			r.Header.Set(TenantHeader, tenant)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w.Code
+	}
+
+	// The steps depend on each other, since they share the store.
+	if status := serve(http.MethodPost, ""); status != http.StatusBadRequest {
+		t.Errorf("expected requests without a tenant to be rejected, got %d", status)
+	}
+	if serve(http.MethodPost, "acme"); err != nil {
+		t.Fatalf("unexpected error saving the node: %v", err)
+	}
+	if _, ok, _ := store.Get("a"); ok {
+		t.Errorf("expected the node not to be saved outside the tenant's namespace")
+	}
+	acme, err := store.WithNamespace("acme")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok, _ := acme.Get("a"); !ok {
+		t.Errorf("expected the node to be saved in the tenant's namespace")
+	}
+	if serve(http.MethodGet, "acme"); err != nil || loaded == nil || loaded.ID != "a" {
+		t.Errorf("expected the tenant's node to be loaded, got %v, %v", loaded, err)
+	}
+	if serve(http.MethodGet, "other"); err != nil || loaded != nil {
+		t.Errorf("expected another tenant not to see the node, got %v, %v", loaded, err)
+	}
+	if status := serve(http.MethodGet, "acme/b"); status != http.StatusBadRequest {
+		t.Errorf("expected tenants containing a / to be rejected, got %d", status)
+	}
+}
+
+func TestTenantFromPrincipal(t *testing.T) {
+	tests := []struct {
+		name           string
+		principal      *Principal
+		expectedTenant string
+		expectedErr    error
+	}{
+		{
+			name:        "requests without a principal have no tenant",
+			expectedErr: ErrMissingTenant,
+		},
+		{
+			name:        "principals without a tenant are rejected",
+			principal:   &Principal{ID: "ada"},
+			expectedErr: ErrMissingTenant,
+		},
+		{
+			name:           "the principal's tenant is returned",
+			principal:      &Principal{ID: "ada", Tenant: "acme"},
+			expectedTenant: "acme",
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			r := httptest.NewRequest(http.MethodPost, "/query", nil)
+			if test.principal != nil {
+				r = r.WithContext(WithPrincipal(context.Background(), *test.principal))
+			}
+			tenant, err := TenantFromPrincipal()(r)
+			if tenant != test.expectedTenant || err != test.expectedErr {
+				t.Errorf("expected %q, %v, got %q, %v", test.expectedTenant, test.expectedErr, tenant, err)
+			}
+		})
+	}
+}
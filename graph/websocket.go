@@ -0,0 +1,385 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/a-h/pregel"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/apigatewaymanagementapi"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// ErrConnectionGone is returned by a ConnectionPoster when the client has disconnected.
+var ErrConnectionGone = errors.New("graph: the websocket connection is gone")
+
+// ErrConnectionNotInitialised is sent to clients which start a subscription before they've sent a
+// connection_init message.
+var ErrConnectionNotInitialised = errors.New("graph: the websocket connection hasn't been initialised")
+
+// ConnectionPoster sends a message to a WebSocket connection.
+type ConnectionPoster func(ctx context.Context, connectionID string, message []byte) error
+
+// NewAPIGatewayConnectionPoster sends messages to the WebSocket connections of an API Gateway
+// WebSocket API. The client's endpoint must be the API's callback URL, e.g.
+// https://{api-id}.execute-api.{region}.amazonaws.com/{stage}.
+func NewAPIGatewayConnectionPoster(client *apigatewaymanagementapi.ApiGatewayManagementApi) ConnectionPoster {
+	return func(ctx context.Context, connectionID string, message []byte) (err error) {
+		_, err = client.PostToConnectionWithContext(ctx, &apigatewaymanagementapi.PostToConnectionInput{
+			ConnectionId: aws.String(connectionID),
+			Data:         message,
+		})
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == apigatewaymanagementapi.ErrCodeGoneException {
+			err = ErrConnectionGone
+		}
+		return
+	}
+}
+
+// Subscription is a GraphQL subscription started by a WebSocket connection.
+type Subscription struct {
+	ConnectionID  string                 `json:"connectionId"`
+	OperationID   string                 `json:"operationId"`
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName,omitempty"`
+	Variables     map[string]interface{} `json:"variables,omitempty"`
+	// Principal is the caller which initialised the connection.
+	Principal Principal `json:"principal"`
+}
+
+// SubscriptionStore stores the WebSocket connections and subscriptions of clients in a table with
+// the same keys as the pregel table, so that they can be read by the Lambda function which consumes
+// the pregel table's stream. It should be a separate table to the graph, since the connections
+// aren't nodes.
+type SubscriptionStore struct {
+	Client pregel.DB
+}
+
+// NewSubscriptionStore creates a store which stores subscriptions using the client.
+func NewSubscriptionStore(client pregel.DB) *SubscriptionStore {
+	return &SubscriptionStore{
+		Client: client,
+	}
+}
+
+const (
+	connectionRange       = "connection"
+	subscriptionsID       = "subscriptions"
+	subscriptionAttribute = "subscription"
+	principalAttribute    = "principal"
+)
+
+func connectionKey(connectionID string) map[string]*dynamodb.AttributeValue {
+	return map[string]*dynamodb.AttributeValue{
+		"id":  {S: aws.String(connectionID)},
+		"rng": {S: aws.String(connectionRange)},
+	}
+}
+
+// All of the subscriptions are in a single partition, so that they can be read by a single query,
+// with the connection ID as the prefix of the range key, so that a connection's subscriptions can be
+// deleted when it disconnects.
+func subscriptionKey(connectionID, operationID string) map[string]*dynamodb.AttributeValue {
+	return map[string]*dynamodb.AttributeValue{
+		"id":  {S: aws.String(subscriptionsID)},
+		"rng": {S: aws.String(connectionID + "/" + operationID)},
+	}
+}
+
+// PutConnection stores the principal of an initialised connection.
+func (s *SubscriptionStore) PutConnection(ctx context.Context, connectionID string, p Principal) (err error) {
+	v, err := json.Marshal(p)
+	if err != nil {
+		return
+	}
+	itm := connectionKey(connectionID)
+	itm[principalAttribute] = &dynamodb.AttributeValue{S: aws.String(string(v))}
+	_, err = s.Client.BatchPut(ctx, []map[string]*dynamodb.AttributeValue{itm})
+	return
+}
+
+// GetConnection returns the principal of an initialised connection.
+func (s *SubscriptionStore) GetConnection(ctx context.Context, connectionID string) (p Principal, ok bool, err error) {
+	itm, _, err := s.Client.GetItem(ctx, connectionKey(connectionID))
+	if err != nil || itm == nil {
+		return
+	}
+	v, hasPrincipal := itm[principalAttribute]
+	if !hasPrincipal || v.S == nil {
+		return
+	}
+	err = json.Unmarshal([]byte(*v.S), &p)
+	ok = err == nil
+	return
+}
+
+// Put a subscription.
+func (s *SubscriptionStore) Put(ctx context.Context, sub Subscription) (err error) {
+	v, err := json.Marshal(sub)
+	if err != nil {
+		return
+	}
+	itm := subscriptionKey(sub.ConnectionID, sub.OperationID)
+	itm[subscriptionAttribute] = &dynamodb.AttributeValue{S: aws.String(string(v))}
+	_, err = s.Client.BatchPut(ctx, []map[string]*dynamodb.AttributeValue{itm})
+	return
+}
+
+// Delete a subscription.
+func (s *SubscriptionStore) Delete(ctx context.Context, connectionID, operationID string) (err error) {
+	_, err = s.Client.BatchDelete(ctx, []map[string]*dynamodb.AttributeValue{subscriptionKey(connectionID, operationID)})
+	return
+}
+
+// DeleteConnection deletes a connection and all of its subscriptions.
+func (s *SubscriptionStore) DeleteConnection(ctx context.Context, connectionID string) (err error) {
+	items, _, err := s.Client.QueryByIDAndRangePrefix(ctx, "id", subscriptionsID, "rng", connectionID+"/")
+	if err != nil {
+		return
+	}
+	keys := []map[string]*dynamodb.AttributeValue{connectionKey(connectionID)}
+	for _, itm := range items {
+		keys = append(keys, map[string]*dynamodb.AttributeValue{
+			"id":  itm["id"],
+			"rng": itm["rng"],
+		})
+	}
+	_, err = s.Client.BatchDelete(ctx, keys)
+	return
+}
+
+// List all of the subscriptions.
+func (s *SubscriptionStore) List(ctx context.Context) (subs []Subscription, err error) {
+	items, _, err := s.Client.QueryByID(ctx, "id", subscriptionsID)
+	if err != nil {
+		return
+	}
+	for _, itm := range items {
+		v, ok := itm[subscriptionAttribute]
+		if !ok || v.S == nil {
+			continue
+		}
+		var sub Subscription
+		if err = json.Unmarshal([]byte(*v.S), &sub); err != nil {
+			return
+		}
+		subs = append(subs, sub)
+	}
+	return
+}
+
+// Messages of the graphql-ws protocol, used by Apollo's subscriptions-transport-ws.
+const (
+	messageConnectionInit      = "connection_init"
+	messageConnectionAck       = "connection_ack"
+	messageConnectionError     = "connection_error"
+	messageConnectionTerminate = "connection_terminate"
+	messageStart               = "start"
+	messageStop                = "stop"
+	messageData                = "data"
+	messageError               = "error"
+	messageComplete            = "complete"
+)
+
+type operationMessage struct {
+	ID      string          `json:"id,omitempty"`
+	Type    string          `json:"type"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+type startPayload struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+func postMessage(ctx context.Context, post ConnectionPoster, connectionID, id, messageType string, payload interface{}) (err error) {
+	msg := operationMessage{ID: id, Type: messageType}
+	if payload != nil {
+		if msg.Payload, err = json.Marshal(payload); err != nil {
+			return
+		}
+	}
+	v, err := json.Marshal(msg)
+	if err != nil {
+		return
+	}
+	return post(ctx, connectionID, v)
+}
+
+func errorPayload(err error) map[string]string {
+	return map[string]string{"message": err.Error()}
+}
+
+// WebSocketHandler handles the messages of the graphql-ws protocol, for WebSocket connections which
+// are managed by API Gateway, where each message is passed to a Lambda function. Rather than running
+// the subscriptions, the handler stores them, so that they can be run by a SubscriptionPublisher
+// when the changes are read from the table's stream.
+type WebSocketHandler struct {
+	Subscriptions *SubscriptionStore
+	// Authenticate is passed a request containing the payload of the connection_init message as its
+	// headers, e.g. {"Authorization": "Bearer <token>"}. If it's nil, every connection is accepted.
+	Authenticate Authenticator
+	// SelectTenant selects the tenant of each connection from the same request, with the principal
+	// in its context, and stores it as the Tenant of the connection's principal. If it's nil,
+	// connections don't have a tenant.
+	SelectTenant TenantSelector
+	Post         ConnectionPoster
+}
+
+// HandleMessage handles a message received from the connection.
+func (h *WebSocketHandler) HandleMessage(ctx context.Context, connectionID string, message []byte) (err error) {
+	var msg operationMessage
+	if err = json.Unmarshal(message, &msg); err != nil {
+		return postMessage(ctx, h.Post, connectionID, "", messageConnectionError, errorPayload(err))
+	}
+	switch msg.Type {
+	case messageConnectionInit:
+		var p Principal
+		if p, err = h.connect(msg.Payload); err != nil {
+			return postMessage(ctx, h.Post, connectionID, "", messageConnectionError, errorPayload(err))
+		}
+		if err = h.Subscriptions.PutConnection(ctx, connectionID, p); err != nil {
+			return
+		}
+		return postMessage(ctx, h.Post, connectionID, "", messageConnectionAck, nil)
+	case messageStart:
+		p, ok, pErr := h.Subscriptions.GetConnection(ctx, connectionID)
+		if pErr != nil {
+			return pErr
+		}
+		if !ok {
+			return postMessage(ctx, h.Post, connectionID, msg.ID, messageError, errorPayload(ErrConnectionNotInitialised))
+		}
+		var payload startPayload
+		if err = json.Unmarshal(msg.Payload, &payload); err != nil {
+			return postMessage(ctx, h.Post, connectionID, msg.ID, messageError, errorPayload(err))
+		}
+		return h.Subscriptions.Put(ctx, Subscription{
+			ConnectionID:  connectionID,
+			OperationID:   msg.ID,
+			Query:         payload.Query,
+			OperationName: payload.OperationName,
+			Variables:     payload.Variables,
+			Principal:     p,
+		})
+	case messageStop:
+		if err = h.Subscriptions.Delete(ctx, connectionID, msg.ID); err != nil {
+			return
+		}
+		return postMessage(ctx, h.Post, connectionID, msg.ID, messageComplete, nil)
+	case messageConnectionTerminate:
+		return h.Subscriptions.DeleteConnection(ctx, connectionID)
+	}
+	return postMessage(ctx, h.Post, connectionID, msg.ID, messageError, errorPayload(fmt.Errorf("unknown message type %q", msg.Type)))
+}
+
+// connect authenticates the caller, and selects its tenant, using the payload of the connection_init
+// message as the headers of a request.
+func (h *WebSocketHandler) connect(payload json.RawMessage) (p Principal, err error) {
+	if h.Authenticate == nil && h.SelectTenant == nil {
+		return
+	}
+	r, err := http.NewRequest(http.MethodGet, "/", nil)
+	if err != nil {
+		return
+	}
+	var headers map[string]interface{}
+	if len(payload) > 0 && json.Unmarshal(payload, &headers) == nil {
+		for k, v := range headers {
+			if s, ok := v.(string); ok {
+				r.Header.Set(k, s)
+			}
+		}
+	}
+	if h.Authenticate != nil {
+		if p, err = h.Authenticate(r); err != nil {
+			return
+		}
+	}
+	if h.SelectTenant != nil {
+		p.Tenant, err = h.SelectTenant(r.WithContext(WithPrincipal(r.Context(), p)))
+	}
+	return
+}
+
+// Disconnect deletes the subscriptions of a connection which has been closed.
+func (h *WebSocketHandler) Disconnect(ctx context.Context, connectionID string) error {
+	return h.Subscriptions.DeleteConnection(ctx, connectionID)
+}
+
+// SubscriptionExecutor runs the query of a subscription, using the changes as the source of its
+// events, and returns the GraphQL response to each event, e.g. by passing a
+// PregelSubscriptionResolver to the schema created by NewExecutableSchema.
+type SubscriptionExecutor func(ctx context.Context, sub Subscription, changes ChangeSubscriber) (responses []json.RawMessage, err error)
+
+// SubscriptionPublisher publishes changes to the subscriptions stored by a WebSocketHandler, e.g.
+// the changes read from the table's stream by the stream package's Dispatch function. Each
+// subscription is run against the changes by Execute, with the subscription's principal in the
+// context, and the responses are posted to its connection. Subscriptions which can't be run are
+// sent an error and deleted, and connections which have gone are deleted.
+type SubscriptionPublisher struct {
+	Subscriptions *SubscriptionStore
+	Execute       SubscriptionExecutor
+	Post          ConnectionPoster
+	// Tenants is set if each tenant has a namespace of the table, so that subscriptions are run with
+	// the Tenant of their principal in the context, and only receive the changes in its namespace.
+	Tenants bool
+}
+
+// Publish the changes to every subscription.
+func (sp *SubscriptionPublisher) Publish(ctx context.Context, events ...pregel.ChangeEvent) (err error) {
+	if len(events) == 0 {
+		return
+	}
+	subs, err := sp.Subscriptions.List(ctx)
+	if err != nil {
+		return
+	}
+	changes := ChangeList(events)
+	gone := make(map[string]bool)
+	for _, sub := range subs {
+		if gone[sub.ConnectionID] {
+			continue
+		}
+		subCtx := WithPrincipal(ctx, sub.Principal)
+		if sp.Tenants {
+			subCtx = WithTenant(subCtx, sub.Principal.Tenant)
+		}
+		responses, exErr := sp.Execute(subCtx, sub, changes)
+		if exErr != nil {
+			// The subscription can't be run, e.g. because its query isn't valid, so it's removed.
+			if pErr := postMessage(ctx, sp.Post, sub.ConnectionID, sub.OperationID, messageError, errorPayload(exErr)); pErr != nil && pErr != ErrConnectionGone {
+				return pErr
+			}
+			if err = sp.Subscriptions.Delete(ctx, sub.ConnectionID, sub.OperationID); err != nil {
+				return
+			}
+			continue
+		}
+		for _, r := range responses {
+			pErr := postMessage(ctx, sp.Post, sub.ConnectionID, sub.OperationID, messageData, r)
+			if pErr == ErrConnectionGone {
+				gone[sub.ConnectionID] = true
+				if err = sp.Subscriptions.DeleteConnection(ctx, sub.ConnectionID); err != nil {
+					return
+				}
+				break
+			}
+			if pErr != nil {
+				return pErr
+			}
+		}
+	}
+	return
+}
+
+// HandleChange publishes a single change, so that the publisher can be subscribed to a
+// pregel.Dispatcher.
+func (sp *SubscriptionPublisher) HandleChange(ctx context.Context, e pregel.ChangeEvent) error {
+	return sp.Publish(ctx, e)
+}
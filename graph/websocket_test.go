@@ -0,0 +1,185 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/a-h/pregel"
+	"github.com/a-h/pregel/db/memory"
+)
+
+type postedMessage struct {
+	ConnectionID string
+	Message      operationMessage
+}
+
+type testPoster struct {
+	posted []postedMessage
+	gone   map[string]bool
+}
+
+func (tp *testPoster) post(ctx context.Context, connectionID string, message []byte) error {
+	if tp.gone[connectionID] {
+		return ErrConnectionGone
+	}
+	var msg operationMessage
+	if err := json.Unmarshal(message, &msg); err != nil {
+		return err
+	}
+	tp.posted = append(tp.posted, postedMessage{ConnectionID: connectionID, Message: msg})
+	return nil
+}
+
+func (tp *testPoster) types() (types []string) {
+	for _, p := range tp.posted {
+		types = append(types, p.Message.Type)
+	}
+	tp.posted = nil
+	return
+}
+
+func TestWebSocketHandler(t *testing.T) {
+	ctx := context.Background()
+	tp := &testPoster{}
+	h := &WebSocketHandler{
+		Subscriptions: NewSubscriptionStore(memory.New()),
+		Authenticate: func(r *http.Request) (Principal, error) {
+			if r.Header.Get("Authorization") != "secret" {
+				return Principal{}, ErrInvalidCredentials
+			}
+			return Principal{ID: "user", Scopes: []string{"pregel:read"}}, nil
+		},
+		Post: tp.post,
+	}
+	start := []byte(`{"id":"1","type":"start","payload":{"query":"subscription { nodeChanged { type } }"}}`)
+
+	// The steps depend on each other, since they share the store.
+	if err := h.HandleMessage(ctx, "conn", start); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if types := tp.types(); !reflect.DeepEqual(types, []string{messageError}) {
+		t.Errorf("expected subscriptions to be rejected before the connection is initialised, got %v", types)
+	}
+	if err := h.HandleMessage(ctx, "conn", []byte(`{"type":"connection_init","payload":{"Authorization":"wrong"}}`)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if types := tp.types(); !reflect.DeepEqual(types, []string{messageConnectionError}) {
+		t.Errorf("expected invalid credentials to be rejected, got %v", types)
+	}
+	if err := h.HandleMessage(ctx, "conn", []byte(`{"type":"connection_init","payload":{"Authorization":"secret"}}`)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if types := tp.types(); !reflect.DeepEqual(types, []string{messageConnectionAck}) {
+		t.Errorf("expected the connection to be acknowledged, got %v", types)
+	}
+	for _, msg := range [][]byte{start, []byte(`{"id":"2","type":"start","payload":{"query":"subscription { nodeChanged { nodeId } }"}}`)} {
+		if err := h.HandleMessage(ctx, "conn", msg); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	subs, err := h.Subscriptions.List(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error listing subscriptions: %v", err)
+	}
+	if len(subs) != 2 || subs[0].Query != "subscription { nodeChanged { type } }" || subs[0].Principal.ID != "user" {
+		t.Errorf("expected 2 subscriptions with the connection's principal, got %+v", subs)
+	}
+	if err := h.HandleMessage(ctx, "conn", []byte(`{"id":"2","type":"stop"}`)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if types := tp.types(); !reflect.DeepEqual(types, []string{messageComplete}) {
+		t.Errorf("expected the stopped subscription to be completed, got %v", types)
+	}
+	if subs, _ = h.Subscriptions.List(ctx); len(subs) != 1 || subs[0].OperationID != "1" {
+		t.Errorf("expected subscription 1 to remain, got %+v", subs)
+	}
+	if err := h.Disconnect(ctx, "conn"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if subs, _ = h.Subscriptions.List(ctx); len(subs) != 0 {
+		t.Errorf("expected the subscriptions to be deleted when the connection closes, got %+v", subs)
+	}
+	if _, ok, _ := h.Subscriptions.GetConnection(ctx, "conn"); ok {
+		t.Errorf("expected the connection to be deleted")
+	}
+}
+
+func TestWebSocketHandlerTenant(t *testing.T) {
+	ctx := context.Background()
+	tp := &testPoster{}
+	h := &WebSocketHandler{
+		Subscriptions: NewSubscriptionStore(memory.New()),
+		SelectTenant:  TenantFromHeader(TenantHeader),
+		Post:          tp.post,
+	}
+	if err := h.HandleMessage(ctx, "conn", []byte(`{"type":"connection_init","payload":{}}`)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if types := tp.types(); !reflect.DeepEqual(types, []string{messageConnectionError}) {
+		t.Errorf("expected connections without a tenant to be rejected, got %v", types)
+	}
+	if err := h.HandleMessage(ctx, "conn", []byte(`{"type":"connection_init","payload":{"X-Pregel-Tenant":"acme"}}`)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if p, ok, _ := h.Subscriptions.GetConnection(ctx, "conn"); !ok || p.Tenant != "acme" {
+		t.Errorf("expected the connection's tenant to be stored, got %+v", p)
+	}
+}
+
+func TestSubscriptionPublisher(t *testing.T) {
+	ctx := context.Background()
+	store := NewSubscriptionStore(memory.New())
+	for _, sub := range []Subscription{
+		{ConnectionID: "a", OperationID: "1", Query: "a", Principal: Principal{ID: "user a"}},
+		{ConnectionID: "b", OperationID: "1", Query: "b"},
+		{ConnectionID: "c", OperationID: "1", Query: "invalid"},
+	} {
+		if err := store.Put(ctx, sub); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	tp := &testPoster{gone: map[string]bool{"b": true}}
+	var principals []string
+	p := &SubscriptionPublisher{
+		Subscriptions: store,
+		Execute: func(ctx context.Context, sub Subscription, changes ChangeSubscriber) (responses []json.RawMessage, err error) {
+			if sub.Query == "invalid" {
+				return nil, errors.New("invalid query")
+			}
+			p, _ := PrincipalFromContext(ctx)
+			principals = append(principals, p.ID)
+			for e := range changes.Subscribe(ctx, func(e pregel.ChangeEvent) bool { return true }) {
+				responses = append(responses, json.RawMessage(`{"data":{"nodeChanged":{"nodeId":"`+e.NodeID+`"}}}`))
+			}
+			return
+		},
+		Post: tp.post,
+	}
+	if err := p.Publish(ctx, pregel.ChangeEvent{Type: pregel.NodeCreated, NodeID: "x"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(tp.posted) != 2 {
+		t.Fatalf("expected 2 messages, got %+v", tp.posted)
+	}
+	if m := tp.posted[0]; m.ConnectionID != "a" || m.Message.Type != messageData || m.Message.ID != "1" || string(m.Message.Payload) != `{"data":{"nodeChanged":{"nodeId":"x"}}}` {
+		t.Errorf("expected the change to be posted to a, got %+v", m)
+	}
+	if m := tp.posted[1]; m.ConnectionID != "c" || m.Message.Type != messageError {
+		t.Errorf("expected an error to be posted to c, got %+v", m)
+	}
+	if !reflect.DeepEqual(principals, []string{"user a", ""}) {
+		t.Errorf("expected each subscription to be run as its principal, got %v", principals)
+	}
+	subs, err := store.List(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(subs) != 1 || subs[0].ConnectionID != "a" {
+		t.Errorf("expected the gone connection and the invalid subscription to be deleted, got %+v", subs)
+	}
+}
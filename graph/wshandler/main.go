@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"log"
+	"net/http"
+
+	"github.com/a-h/pregel/db"
+	"github.com/a-h/pregel/graph"
+	"github.com/a-h/pregel/graph/config"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/apigatewaymanagementapi"
+)
+
+// The handler of an API Gateway WebSocket API, which stores the subscriptions of its clients so
+// that the changes read from the pregel table's stream by the streamhandler can be posted to them.
+func main() {
+	c, err := config.FromEnvironment(config.Default())
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err = c.Require(config.Region, config.SubscriptionTableName, config.WebSocketEndpoint); err != nil {
+		log.Fatal(err)
+	}
+
+	client, err := db.New(c.Region, c.SubscriptionTableName)
+	if err != nil {
+		log.Fatal(err)
+	}
+	sess, err := session.NewSession(&aws.Config{Region: aws.String(c.Region)})
+	if err != nil {
+		log.Fatal(err)
+	}
+	connections := apigatewaymanagementapi.New(sess, aws.NewConfig().WithEndpoint(c.WebSocketEndpoint))
+
+	// Clients send the same credentials as the handler's callers in the payload of their
+	// connection_init message, e.g. {"Authorization": "Bearer <token>"}.
+	authenticate, err := c.Authenticator()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	h := &graph.WebSocketHandler{
+		Subscriptions: graph.NewSubscriptionStore(client),
+		Authenticate:  authenticate,
+		Post:          graph.NewAPIGatewayConnectionPoster(connections),
+	}
+	// The tenant of each connection is stored with it, so that the streamhandler only publishes the
+	// changes in the tenant's namespace.
+	selectTenant, ok, err := c.TenantSelector()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if ok {
+		h.SelectTenant = selectTenant
+	}
+	lambda.Start(func(ctx context.Context, req events.APIGatewayWebsocketProxyRequest) (resp events.APIGatewayProxyResponse, err error) {
+		resp.StatusCode = http.StatusOK
+		connectionID := req.RequestContext.ConnectionID
+		switch req.RequestContext.RouteKey {
+		case "$connect":
+		case "$disconnect":
+			err = h.Disconnect(ctx, connectionID)
+		default:
+			err = h.HandleMessage(ctx, connectionID, []byte(req.Body))
+		}
+		return
+	})
+}
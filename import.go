@@ -0,0 +1,277 @@
+package pregel
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/a-h/pregel/db"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// Defaults used by Import when the ImportOptions aren't set.
+const (
+	DefaultImportWriters    = 4
+	DefaultImportMaxRetries = 10
+	DefaultImportRetryDelay = 50 * time.Millisecond
+)
+
+// ErrUnprocessedItems is returned by Import when DynamoDB still hasn't processed some records after
+// all of the retries.
+var ErrUnprocessedItems = errors.New("records were not processed after retrying")
+
+// ImportOptions control how Import writes to the database.
+type ImportOptions struct {
+	// Writers is the number of batches written in parallel. If zero, DefaultImportWriters is used.
+	Writers int
+	// MaxRetries is the number of times records that DynamoDB didn't process are retried. If zero,
+	// the Store's ImportRetryPolicy is used, or DefaultImportMaxRetries if the Store doesn't have one.
+	MaxRetries int
+	// RetryDelay is the time to wait before the first retry of a batch, which is doubled for each
+	// subsequent retry. If zero, the Store's ImportRetryPolicy is used, or DefaultImportRetryDelay if
+	// the Store doesn't have one.
+	RetryDelay time.Duration
+	// Progress is called each time a batch is written. It's not called concurrently.
+	Progress func(p ImportProgress)
+	// Snapshot is set if the nodes were written by Export, so that both ends of each edge are
+	// imported. Each node's edges are only written to its own records, so that each edge is written
+	// once, rather than once by each of its nodes.
+	Snapshot bool
+}
+
+// ImportProgress reports how much of an import has been completed.
+type ImportProgress struct {
+	// NodesRead is the number of nodes which have been read from the channel and converted to records.
+	NodesRead int64
+	// RecordsWritten is the number of records which have been written to the database.
+	RecordsWritten int64
+}
+
+// Import writes the nodes received from the channel until it's closed. The nodes' records are
+// written in batches of up to db.MaxBatchWriteItems records by parallel writers, which is much faster
+// than calling Put for each node. Records that DynamoDB doesn't process, e.g. due to throttling, are
+// retried with an exponential backoff. Batches are written in no particular order, so the same node
+// shouldn't be sent more than once. If an error occurs, or the context is cancelled, Import stops
+// reading from the channel, and returns the error once the batches in progress have been written.
+func (s *Store) Import(ctx context.Context, nodes <-chan Node, opts ImportOptions) (p ImportProgress, err error) {
+	if opts.Writers <= 0 {
+		opts.Writers = DefaultImportWriters
+	}
+	policy := s.ImportRetryPolicy
+	if policy == nil || opts.MaxRetries > 0 || opts.RetryDelay > 0 {
+		if opts.MaxRetries <= 0 {
+			opts.MaxRetries = DefaultImportMaxRetries
+		}
+		if opts.RetryDelay <= 0 {
+			opts.RetryDelay = DefaultImportRetryDelay
+		}
+		policy = db.ExponentialBackoff{
+			Attempts:  opts.MaxRetries + 1,
+			BaseDelay: opts.RetryDelay,
+		}
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	imp := &importer{
+		store:  s,
+		opts:   opts,
+		policy: policy,
+		cancel: cancel,
+	}
+	batches := make(chan []map[string]*dynamodb.AttributeValue, opts.Writers)
+	var wg sync.WaitGroup
+	wg.Add(opts.Writers)
+	for i := 0; i < opts.Writers; i++ {
+		go func() {
+			defer wg.Done()
+			for batch := range batches {
+				if imp.failed() {
+					continue
+				}
+				if wErr := imp.write(ctx, batch); wErr != nil {
+					imp.fail(wErr)
+				}
+			}
+		}()
+	}
+	imp.read(ctx, nodes, batches)
+	wg.Wait()
+	return imp.progress, imp.err
+}
+
+// ImportJSONLines imports the nodes written by Export, decoding each node as it's read, so that
+// large snapshots don't need to fit in memory. Data of registered types is converted into the
+// registered type. The nodes are imported as a Snapshot, so the file must contain both nodes of each
+// edge. If a line can't be decoded, the import is stopped and the error is returned.
+func (s *Store) ImportJSONLines(ctx context.Context, r io.Reader, opts ImportOptions) (p ImportProgress, err error) {
+	opts.Snapshot = true
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	nodes := make(chan Node)
+	readErr := make(chan error, 1)
+	go func() {
+		defer close(nodes)
+		readErr <- s.decodeNodes(ctx, r, nodes)
+	}()
+	p, err = s.Import(ctx, nodes, opts)
+	// Import stops reading if it fails, so unblock the reader.
+	cancel()
+	for range nodes {
+	}
+	if rErr := <-readErr; rErr != nil && rErr != context.Canceled {
+		err = rErr
+	}
+	return
+}
+
+// decodeNodes sends each JSON encoded node read from r to the channel.
+func (s *Store) decodeNodes(ctx context.Context, r io.Reader, nodes chan<- Node) (err error) {
+	dec := json.NewDecoder(r)
+	for {
+		var n Node
+		if err = dec.Decode(&n); err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return
+		}
+		if err = s.convertDecodedNode(&n); err != nil {
+			return
+		}
+		select {
+		case nodes <- n:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+type importer struct {
+	store  *Store
+	opts   ImportOptions
+	policy db.RetryPolicy
+	cancel func()
+
+	// m protects the progress and error, which are updated by concurrent writers.
+	m        sync.Mutex
+	progress ImportProgress
+	err      error
+}
+
+// read converts the nodes into batches of records, until the nodes channel is closed, or the
+// context is cancelled. Records with the same key can't be written in the same batch, so a new batch
+// is started if a key is repeated.
+func (imp *importer) read(ctx context.Context, nodes <-chan Node, batches chan<- []map[string]*dynamodb.AttributeValue) {
+	defer close(batches)
+	var batch []map[string]*dynamodb.AttributeValue
+	keys := make(map[string]bool)
+	flush := func() (ok bool) {
+		if len(batch) == 0 {
+			return true
+		}
+		select {
+		case batches <- batch:
+		case <-ctx.Done():
+			imp.fail(ctx.Err())
+			return false
+		}
+		batch = nil
+		keys = make(map[string]bool)
+		return true
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			imp.fail(ctx.Err())
+			return
+		case n, ok := <-nodes:
+			if !ok {
+				flush()
+				return
+			}
+			records, err := imp.convert(n)
+			if err != nil {
+				imp.fail(err)
+				return
+			}
+			for _, r := range records {
+				k := recordKey(r)
+				if keys[k] || len(batch) == db.MaxBatchWriteItems {
+					if !flush() {
+						return
+					}
+				}
+				batch = append(batch, r)
+				keys[k] = true
+			}
+			imp.update(1, 0)
+		}
+	}
+}
+
+// convert returns the records of the node.
+func (imp *importer) convert(n Node) (records []map[string]*dynamodb.AttributeValue, err error) {
+	if imp.opts.Snapshot {
+		return imp.store.convertNodeToRecords(n, imp.store.convertToOwnRecords)
+	}
+	return imp.store.convertNodeToRecords(n, imp.store.convertToRecords)
+}
+
+// write puts the batch into the database, retrying any records which aren't processed.
+func (imp *importer) write(ctx context.Context, batch []map[string]*dynamodb.AttributeValue) (err error) {
+	for attempt := 0; ; attempt++ {
+		unprocessed, cc, pErr := imp.store.Client.TryBatchPut(ctx, batch)
+		imp.store.updateCapacityStats(cc)
+		if pErr != nil {
+			return pErr
+		}
+		imp.update(0, int64(len(batch)-len(unprocessed)))
+		if len(unprocessed) == 0 {
+			return
+		}
+		if attempt+1 >= imp.policy.MaxAttempts() {
+			return ErrUnprocessedItems
+		}
+		select {
+		case <-time.After(imp.policy.Backoff(attempt)):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		batch = unprocessed
+	}
+}
+
+func (imp *importer) update(nodesRead, recordsWritten int64) {
+	imp.m.Lock()
+	defer imp.m.Unlock()
+	imp.progress.NodesRead += nodesRead
+	imp.progress.RecordsWritten += recordsWritten
+	if recordsWritten > 0 && imp.opts.Progress != nil {
+		imp.opts.Progress(imp.progress)
+	}
+}
+
+// fail records the first error, and cancels the import.
+func (imp *importer) fail(err error) {
+	imp.m.Lock()
+	defer imp.m.Unlock()
+	if imp.err == nil {
+		imp.err = err
+	}
+	imp.cancel()
+}
+
+func (imp *importer) failed() bool {
+	imp.m.Lock()
+	defer imp.m.Unlock()
+	return imp.err != nil
+}
+
+// recordKey returns the primary key of the record as a string.
+func recordKey(r map[string]*dynamodb.AttributeValue) string {
+	return aws.StringValue(r[fieldID].S) + "\x00" + aws.StringValue(r[fieldRange].S)
+}
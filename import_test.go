@@ -0,0 +1,201 @@
+package pregel
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/a-h/pregel/db"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+func TestStoreImport(t *testing.T) {
+	nodesToImport := func(count int) (nodes []Node) {
+		for i := 0; i < count; i++ {
+			nodes = append(nodes, NewNode("node"+strconv.Itoa(i)))
+		}
+		return
+	}
+	tests := []struct {
+		name string
+		// nodes are sent to the import.
+		nodes []Node
+		// unprocessed returns the number of records in a batch which aren't processed on each attempt.
+		unprocessed            func(attempt int) int
+		expectedProgress       ImportProgress
+		expectedMaxBatchSize   int
+		expectedRecordsWritten int
+		expectedErr            error
+	}{
+		{
+			name:                   "Records are written in batches",
+			nodes:                  nodesToImport(60),
+			expectedProgress:       ImportProgress{NodesRead: 60, RecordsWritten: 60},
+			expectedMaxBatchSize:   db.MaxBatchWriteItems,
+			expectedRecordsWritten: 60,
+		},
+		{
+			name:                   "Records with the same key are written in separate batches",
+			nodes:                  []Node{NewNode("a"), NewNode("a")},
+			expectedProgress:       ImportProgress{NodesRead: 2, RecordsWritten: 2},
+			expectedMaxBatchSize:   1,
+			expectedRecordsWritten: 2,
+		},
+		{
+			name:  "Unprocessed records are retried",
+			nodes: nodesToImport(10),
+			unprocessed: func(attempt int) int {
+				if attempt == 0 {
+					return 5
+				}
+				return 0
+			},
+			expectedProgress:       ImportProgress{NodesRead: 10, RecordsWritten: 10},
+			expectedMaxBatchSize:   10,
+			expectedRecordsWritten: 15,
+		},
+		{
+			name:  "Records which are never processed result in an error",
+			nodes: nodesToImport(1),
+			unprocessed: func(attempt int) int {
+				return 1
+			},
+			expectedProgress:       ImportProgress{NodesRead: 1},
+			expectedMaxBatchSize:   1,
+			expectedRecordsWritten: 3,
+			expectedErr:            ErrUnprocessedItems,
+		},
+		{
+			name:        "Invalid nodes result in an error",
+			nodes:       []Node{NewNode("")},
+			expectedErr: ErrMissingNodeID,
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			var m sync.Mutex
+			var actualMaxBatchSize, actualRecordsWritten int
+			attempts := make(map[string]int)
+			client := newdynamoDBClient()
+			client.tryBatchPutter = func(items []map[string]*dynamodb.AttributeValue) (unprocessed []map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error) {
+				m.Lock()
+				defer m.Unlock()
+				if len(items) > actualMaxBatchSize {
+					actualMaxBatchSize = len(items)
+				}
+				actualRecordsWritten += len(items)
+				if test.unprocessed != nil {
+					k := recordKey(items[0])
+					unprocessed = items[:test.unprocessed(attempts[k])]
+					attempts[k]++
+				}
+				return
+			}
+			s := NewStoreWithClient(client)
+			nodes := make(chan Node)
+			go func() {
+				defer close(nodes)
+				for _, n := range test.nodes {
+					nodes <- n
+				}
+			}()
+			var lastProgress ImportProgress
+			opts := ImportOptions{
+				Writers:    2,
+				MaxRetries: 2,
+				RetryDelay: time.Millisecond,
+				Progress: func(p ImportProgress) {
+					lastProgress = p
+				},
+			}
+			if test.expectedErr != nil {
+				// Drain the channel, since Import stops reading from it.
+				defer func() {
+					for range nodes {
+					}
+				}()
+			}
+			p, err := s.Import(context.Background(), nodes, opts)
+			if err != test.expectedErr {
+				t.Fatalf("expected err %v, got %v", test.expectedErr, err)
+			}
+			if p != test.expectedProgress {
+				t.Errorf("expected progress %+v, got %+v", test.expectedProgress, p)
+			}
+			if test.expectedProgress.RecordsWritten > 0 && lastProgress != p {
+				t.Errorf("expected the last progress report to be %+v, got %+v", p, lastProgress)
+			}
+			if actualMaxBatchSize != test.expectedMaxBatchSize {
+				t.Errorf("expected a maximum batch size of %d, got %d", test.expectedMaxBatchSize, actualMaxBatchSize)
+			}
+			if actualRecordsWritten != test.expectedRecordsWritten {
+				t.Errorf("expected %d records to be written, got %d", test.expectedRecordsWritten, actualRecordsWritten)
+			}
+		})
+	}
+	t.Run("Cancelling the context stops the import", func(t *testing.T) {
+		client := newdynamoDBClient()
+		s := NewStoreWithClient(client)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err := s.Import(ctx, make(chan Node), ImportOptions{})
+		if err != context.Canceled {
+			t.Errorf("expected err %v, got %v", context.Canceled, err)
+		}
+	})
+	t.Run("The Store's ImportRetryPolicy is used when retries aren't configured", func(t *testing.T) {
+		var attempts int
+		client := newdynamoDBClient()
+		client.tryBatchPutter = func(items []map[string]*dynamodb.AttributeValue) (unprocessed []map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error) {
+			attempts++
+			return items, cc, nil
+		}
+		s := NewStoreWithClient(client)
+		s.ImportRetryPolicy = db.NoRetry
+		nodes := make(chan Node, 1)
+		nodes <- NewNode("a")
+		close(nodes)
+		_, err := s.Import(context.Background(), nodes, ImportOptions{Writers: 1})
+		if err != ErrUnprocessedItems {
+			t.Errorf("expected err %v, got %v", ErrUnprocessedItems, err)
+		}
+		if attempts != 1 {
+			t.Errorf("expected 1 attempt, got %d", attempts)
+		}
+	})
+}
+
+func TestStoreImportJSONLines(t *testing.T) {
+	t.Run("Nodes written by Export are imported", func(t *testing.T) {
+		s := NewMemoryStore()
+		input := `{"id":"a","children":[{"id":"b"}]}` + "\n" + `{"id":"b","parents":[{"id":"a"}]}` + "\n"
+		p, err := s.ImportJSONLines(context.Background(), strings.NewReader(input), ImportOptions{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if p.NodesRead != 2 {
+			t.Errorf("expected 2 nodes to be read, got %d", p.NodesRead)
+		}
+		n, ok, err := s.Get("a")
+		if err != nil || !ok {
+			t.Fatalf("expected node a to be imported, got %v, %v", ok, err)
+		}
+		if len(n.Children) != 1 || n.Children[0].ID != "b" {
+			t.Errorf("expected node a to have the child b, got %+v", n.Children)
+		}
+	})
+	t.Run("Invalid JSON stops the import", func(t *testing.T) {
+		s := NewMemoryStore()
+		input := `{"id":"a"}` + "\n" + `{"id":` + "\n"
+		_, err := s.ImportJSONLines(context.Background(), strings.NewReader(input), ImportOptions{})
+		if err == nil {
+			t.Error("expected an error, got nil")
+		}
+	})
+}
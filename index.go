@@ -0,0 +1,66 @@
+package pregel
+
+import (
+	"fmt"
+
+	"github.com/a-h/pregel/rangefield"
+)
+
+// AttributeIndexKey identifies an attribute of a data type.
+type AttributeIndexKey struct {
+	DataType string
+	Field    string
+}
+
+// RegisterIndex registers a global secondary index on the table whose partition key is the field of
+// a data type, e.g. the "ssid" field of the "router" data type. The field name is the name of the
+// attribute after marshalling, not the name of the Go struct field. The index must already exist
+// on the table, and must project the table's keys.
+func (s *Store) RegisterIndex(dataType, field, indexName string) {
+	s.Indexes[AttributeIndexKey{DataType: dataType, Field: field}] = indexName
+}
+
+// ErrIndexNotRegistered is returned when querying a data type's field that doesn't have a
+// registered index.
+type ErrIndexNotRegistered struct {
+	DataType string
+	Field    string
+}
+
+func (err ErrIndexNotRegistered) Error() string {
+	return fmt.Sprintf("no index registered for field '%s' of data type '%s'", err.Field, err.DataType)
+}
+
+// QueryByAttribute returns the IDs of nodes which have data of the given type where the field is
+// equal to the value. The data type's field must have been registered using RegisterIndex.
+func (s *Store) QueryByAttribute(dataType, field string, value interface{}) (ids []string, err error) {
+	indexName, ok := s.Indexes[AttributeIndexKey{DataType: dataType, Field: field}]
+	if !ok {
+		err = ErrIndexNotRegistered{DataType: dataType, Field: field}
+		return
+	}
+	items, cc, err := s.Client.QueryIndex(s.context(), indexName, field, value)
+	if err != nil {
+		return
+	}
+	s.updateCapacityStats(cc)
+	// The index contains edge data records and other data types which share the field name.
+	seen := make(map[string]bool)
+	for _, itm := range items {
+		rf, ok := decodeRangeField(itm)
+		if !ok || isExpired(itm, s.now()) {
+			continue
+		}
+		nd, isNodeData := rf.(rangefield.NodeData)
+		if !isNodeData || nd.DataType != dataType {
+			continue
+		}
+		id := itm[fieldID]
+		if id == nil || id.S == nil || seen[*id.S] {
+			continue
+		}
+		seen[*id.S] = true
+		ids = append(ids, *id.S)
+	}
+	return
+}
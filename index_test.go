@@ -0,0 +1,73 @@
+package pregel
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/a-h/pregel/db"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+func TestStoreQueryByAttribute(t *testing.T) {
+	records := []map[string]*dynamodb.AttributeValue{
+		{"id": {S: aws.String("nodeA")}, "rng": {S: aws.String("node/data/testNodeData")}, "t": {S: aws.String("testNodeData")}, "extra": {S: aws.String("value")}},
+		{"id": {S: aws.String("nodeB")}, "rng": {S: aws.String("node/data/otherNodeData")}, "t": {S: aws.String("otherNodeData")}, "extra": {S: aws.String("value")}},
+		{"id": {S: aws.String("nodeC")}, "rng": {S: aws.String("child/nodeA/data/testNodeData")}, "t": {S: aws.String("testNodeData")}, "extra": {S: aws.String("value")}},
+		{"id": {S: aws.String("nodeD")}, "rng": {S: aws.String("node/data/testNodeData")}, "t": {S: aws.String("testNodeData")}, "extra": {S: aws.String("value")}},
+	}
+	tests := []struct {
+		name        string
+		dataType    string
+		field       string
+		queryErr    error
+		expectedIDs []string
+		expectedErr error
+	}{
+		{
+			name:        "Unregistered indexes result in an error",
+			dataType:    "testNodeData",
+			field:       "unknown",
+			expectedErr: ErrIndexNotRegistered{DataType: "testNodeData", Field: "unknown"},
+		},
+		{
+			name:        "Database errors are returned",
+			dataType:    "testNodeData",
+			field:       "extra",
+			queryErr:    errTestDatabaseFailure,
+			expectedErr: errTestDatabaseFailure,
+		},
+		{
+			name:        "Only node data records of the data type are returned",
+			dataType:    "testNodeData",
+			field:       "extra",
+			expectedIDs: []string{"nodeA", "nodeD"},
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			client := newdynamoDBClient()
+			client.queryIndexer = func(indexName, field string, value interface{}) ([]map[string]*dynamodb.AttributeValue, db.ConsumedCapacity, error) {
+				if indexName != "extraIndex" {
+					t.Errorf("expected index name 'extraIndex', got '%s'", indexName)
+				}
+				if field != "extra" || value != "value" {
+					t.Errorf("expected extra = value, got %s = %v", field, value)
+				}
+				return records, db.ConsumedCapacity{ConsumedCapacity: 1}, test.queryErr
+			}
+			s := NewStoreWithClient(client)
+			s.RegisterIndex("testNodeData", "extra", "extraIndex")
+			ids, err := s.QueryByAttribute(test.dataType, test.field, "value")
+			if err != test.expectedErr {
+				t.Errorf("expected err %v, got %v", test.expectedErr, err)
+			}
+			if !reflect.DeepEqual(ids, test.expectedIDs) {
+				t.Errorf("expected IDs %v, got %v", test.expectedIDs, ids)
+			}
+		})
+	}
+}
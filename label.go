@@ -0,0 +1,72 @@
+package pregel
+
+import (
+	"errors"
+
+	"github.com/a-h/pregel/rangefield"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// ErrLabelIndexNotConfigured is returned by QueryByLabel when neither the Store's LabelIndex or
+// RangeIndex are set.
+var ErrLabelIndexNotConfigured = errors.New("label index not configured")
+
+// QueryByLabel returns a page of up to limit IDs of nodes which have the label. A limit of zero
+// returns all of the nodes. To get the next page, pass the returned next cursor back in, starting
+// with an empty cursor. When there are no more nodes, next is empty. The label index is eventually
+// consistent, so recently labelled nodes may not be returned immediately, and soft deleted nodes
+// keep their labels. If the Store's LabelIndex isn't set, the RangeIndex is used.
+func (s *Store) QueryByLabel(label string, limit int, cursor string) (ids []string, next string, err error) {
+	if label == "" {
+		err = ErrMissingLabel
+		return
+	}
+	indexName, field, value := s.LabelIndex, LabelAttributeName, label
+	if indexName == "" {
+		indexName, field, value = s.RangeIndex, fieldRange, rangefield.NodeLabel{Label: label}.Encode()
+	}
+	if indexName == "" {
+		err = ErrLabelIndexNotConfigured
+		return
+	}
+	var startKey map[string]*dynamodb.AttributeValue
+	if cursor != "" {
+		// The start key contains the table's key and the index's key.
+		startKey = newLabelRecord(cursor, label)
+		if field != LabelAttributeName {
+			delete(startKey, LabelAttributeName)
+		}
+	}
+	for {
+		items, lastEvaluatedKey, cc, qErr := s.Client.QueryIndexPage(s.context(), indexName, field, value, int64(limit), startKey)
+		if qErr != nil {
+			err = qErr
+			return
+		}
+		s.updateCapacityStats(cc)
+		for _, itm := range items {
+			rf, ok := decodeRangeField(itm)
+			if !ok || isExpired(itm, s.now()) {
+				continue
+			}
+			if nl, isLabel := rf.(rangefield.NodeLabel); !isLabel || nl.Label != label {
+				continue
+			}
+			ids = append(ids, aws.StringValue(itm[fieldID].S))
+		}
+		if limit > 0 && len(ids) >= limit {
+			// As with ListNodes, the cursor is the ID of the last node returned.
+			truncated := len(ids) > limit
+			ids = ids[:limit]
+			if truncated || lastEvaluatedKey != nil {
+				next = ids[limit-1]
+			}
+			return
+		}
+		if lastEvaluatedKey == nil {
+			return
+		}
+		startKey = lastEvaluatedKey
+	}
+}
@@ -0,0 +1,155 @@
+package pregel
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/a-h/pregel/db"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+func TestStorePutLabels(t *testing.T) {
+	client := newdynamoDBClient()
+	var actualItems []map[string]*dynamodb.AttributeValue
+	client.batchPutter = func(items []map[string]*dynamodb.AttributeValue) (db.ConsumedCapacity, error) {
+		actualItems = items
+		return db.ConsumedCapacity{}, nil
+	}
+	client.queryByIDer = func(idField, idValue string) ([]map[string]*dynamodb.AttributeValue, db.ConsumedCapacity, error) {
+		return actualItems, db.ConsumedCapacity{}, nil
+	}
+	s := NewStoreWithClient(client)
+	err := s.Put(NewNode("router").WithLabels("device", "network"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectedItems := []map[string]*dynamodb.AttributeValue{
+		{"id": {S: aws.String("router")}, "rng": {S: aws.String("node")}},
+		{"id": {S: aws.String("router")}, "rng": {S: aws.String("node/label/device")}, "_l": {S: aws.String("device")}},
+		{"id": {S: aws.String("router")}, "rng": {S: aws.String("node/label/network")}, "_l": {S: aws.String("network")}},
+	}
+	if !reflect.DeepEqual(actualItems, expectedItems) {
+		t.Errorf("\nexpected:\n%s\n\ngot:\n%s\n", format(expectedItems), format(actualItems))
+	}
+	n, _, err := s.Get("router")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(n.Labels, []string{"device", "network"}) {
+		t.Errorf("expected labels to be read back, got %v", n.Labels)
+	}
+}
+
+func TestStoreQueryByLabel(t *testing.T) {
+	labelRecord := func(id, label string) map[string]*dynamodb.AttributeValue {
+		return map[string]*dynamodb.AttributeValue{
+			"id":  {S: aws.String(id)},
+			"rng": {S: aws.String("node/label/" + label)},
+			"_l":  {S: aws.String(label)},
+		}
+	}
+	records := []map[string]*dynamodb.AttributeValue{
+		labelRecord("computerA", "device"),
+		labelRecord("computerB", "device"),
+		labelRecord("router", "device"),
+	}
+	tests := []struct {
+		name          string
+		label         string
+		labelIndex    string
+		rangeIndex    string
+		limit         int
+		cursor        string
+		expectedIDs   []string
+		expectedNext  string
+		expectedStart string
+		expectedErr   error
+	}{
+		{
+			name:        "Missing labels result in an error",
+			labelIndex:  "labels",
+			expectedErr: ErrMissingLabel,
+		},
+		{
+			name:        "An index must be configured",
+			label:       "device",
+			expectedErr: ErrLabelIndexNotConfigured,
+		},
+		{
+			name:        "No limit returns all nodes",
+			label:       "device",
+			labelIndex:  "labels",
+			expectedIDs: []string{"computerA", "computerB", "router"},
+		},
+		{
+			name:        "The range index is used if there's no label index",
+			label:       "device",
+			rangeIndex:  "ranges",
+			expectedIDs: []string{"computerA", "computerB", "router"},
+		},
+		{
+			name:         "Limits return a cursor",
+			label:        "device",
+			labelIndex:   "labels",
+			limit:        2,
+			expectedIDs:  []string{"computerA", "computerB"},
+			expectedNext: "computerB",
+		},
+		{
+			name:          "Cursors start after the last node",
+			label:         "device",
+			labelIndex:    "labels",
+			limit:         2,
+			cursor:        "computerB",
+			expectedIDs:   []string{"router"},
+			expectedStart: "computerB",
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			client := newdynamoDBClient()
+			var actualStart string
+			client.queryIndexPager = func(indexName, field string, value interface{}, limit int64, startKey map[string]*dynamodb.AttributeValue) ([]map[string]*dynamodb.AttributeValue, map[string]*dynamodb.AttributeValue, db.ConsumedCapacity, error) {
+				expectedIndex, expectedField, expectedValue := test.labelIndex, "_l", test.label
+				if test.rangeIndex != "" {
+					expectedIndex, expectedField, expectedValue = test.rangeIndex, "rng", "node/label/"+test.label
+				}
+				if indexName != expectedIndex || field != expectedField || value != expectedValue {
+					t.Errorf("unexpected query of %s.%s = %v", indexName, field, value)
+				}
+				var items []map[string]*dynamodb.AttributeValue
+				for _, r := range records {
+					if startKey != nil && *r[fieldID].S <= *startKey[fieldID].S {
+						actualStart = *startKey[fieldID].S
+						continue
+					}
+					if limit > 0 && int64(len(items)) == limit {
+						return items, items[len(items)-1], db.ConsumedCapacity{}, nil
+					}
+					items = append(items, r)
+				}
+				return items, nil, db.ConsumedCapacity{}, nil
+			}
+			s := NewStoreWithClient(client)
+			s.LabelIndex = test.labelIndex
+			s.RangeIndex = test.rangeIndex
+			ids, next, err := s.QueryByLabel(test.label, test.limit, test.cursor)
+			if err != test.expectedErr {
+				t.Errorf("expected err %v, got %v", test.expectedErr, err)
+			}
+			if !reflect.DeepEqual(ids, test.expectedIDs) {
+				t.Errorf("expected IDs %v, got %v", test.expectedIDs, ids)
+			}
+			if next != test.expectedNext {
+				t.Errorf("expected next cursor %q, got %q", test.expectedNext, next)
+			}
+			if actualStart != test.expectedStart {
+				t.Errorf("expected start %q, got %q", test.expectedStart, actualStart)
+			}
+		})
+	}
+}
@@ -0,0 +1,209 @@
+package pregel
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/a-h/pregel/db"
+	"github.com/a-h/pregel/db/memory"
+)
+
+func TestStoreTimestampsWithMemoryDB(t *testing.T) {
+	s := NewMemoryStore()
+	s.Timestamps = true
+	created := time.Date(2019, time.January, 1, 0, 0, 0, 0, time.UTC)
+	now := created
+	s.Now = func() time.Time { return now }
+
+	if err := s.Put(NewNode("a").WithChildren(NewEdge("b"))); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	now = created.Add(time.Hour)
+	if err := s.PutNodeData("a", Data{"testNodeData": testNodeData{}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := s.PutEdges("a", NewEdge("b")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := s.Put(NewNode("b")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Node b's node record was first written by the second Put.
+	for id, expectedCreatedAt := range map[string]time.Time{"a": created, "b": now} {
+		n, ok, err := s.Get(id)
+		if err != nil || !ok {
+			t.Fatalf("expected node %q to be found, got %v, %v", id, ok, err)
+		}
+		if !n.CreatedAt.Equal(expectedCreatedAt) {
+			t.Errorf("expected node %q to be created at %v, got %v", id, expectedCreatedAt, n.CreatedAt)
+		}
+		if !n.UpdatedAt.Equal(now) {
+			t.Errorf("expected node %q to be updated at %v, got %v", id, now, n.UpdatedAt)
+		}
+		edges := append(n.Children, n.Parents...)
+		if len(edges) != 1 || !edges[0].CreatedAt.Equal(created) {
+			t.Errorf("expected the edge of node %q to keep its creation time %v, got %+v", id, created, edges)
+		}
+	}
+}
+
+func TestStoreDeleteAliasedDataWithMemoryDB(t *testing.T) {
+	for _, rename := range []bool{false, true} {
+		client := memory.New()
+		s := NewStoreWithClient(client)
+		// The data is written before the data type is renamed.
+		n := NewNode("a").WithChildren(NewEdge("b").WithNamedData("oldTestEdgeData", testEdgeData{EdgeDataField: 1}))
+		n.Data["oldTestNodeData"] = testNodeData{}
+		if err := s.Put(n); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		s.RegisterNamedDataType("testNodeData", func() interface{} { return &testNodeData{} }, "oldTestNodeData")
+		s.RegisterNamedDataType("testEdgeData", func() interface{} { return &testEdgeData{} }, "oldTestEdgeData")
+
+		var err error
+		if rename {
+			err = s.RenameNode("a", "c")
+		} else {
+			err = s.Delete("a")
+		}
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for _, id := range []string{"a", "b"} {
+			items, _, err := client.QueryByID(context.Background(), fieldID, id)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var ranges []string
+			for _, itm := range items {
+				ranges = append(ranges, *itm[fieldRange].S)
+			}
+			if id == "b" && rename {
+				if expected := []string{"parent/c", "parent/c/data/testEdgeData"}; !reflect.DeepEqual(ranges, expected) {
+					t.Errorf("rename: expected node b to have records %v, got %v", expected, ranges)
+				}
+				continue
+			}
+			if len(ranges) > 0 {
+				t.Errorf("rename %v: expected the records of node %q to be deleted, got %v", rename, id, ranges)
+			}
+		}
+	}
+}
+
+func TestStoreWithMemoryDB(t *testing.T) {
+	s := NewStoreWithClient(memory.New(db.TableIndex{Name: "labels", PartitionKey: LabelAttributeName, SortKey: fieldID}))
+	s.LabelIndex = "labels"
+	s.NodeDataMode = NodeDataMustExist
+
+	for _, id := range []string{"a", "b", "c", "d", "e"} {
+		if err := s.Put(NewNode(id).WithLabels("letter")); err != nil {
+			t.Fatalf("unexpected error putting %q: %v", id, err)
+		}
+	}
+	if err := s.PutEdges("a", NewEdge("b").WithData(testEdgeData{EdgeDataField: 1})); err != nil {
+		t.Fatalf("unexpected error putting edges: %v", err)
+	}
+	if err := s.Create(NewNode("a")); err != ErrAlreadyExists {
+		t.Errorf("expected ErrAlreadyExists, got %v", err)
+	}
+	if err := s.PutNodeData("z", Data{"testNodeData": testNodeData{}}); err != ErrNodeNotFound {
+		t.Errorf("expected ErrNodeNotFound, got %v", err)
+	}
+
+	n, ok, err := s.Get("a")
+	if err != nil || !ok {
+		t.Fatalf("expected node a to be found, got %v, %v", ok, err)
+	}
+	if len(n.Children) != 1 || n.Children[0].ID != "b" {
+		t.Errorf("expected a to have child b, got %+v", n.Children)
+	}
+
+	t.Run("Nodes are listed in pages", func(t *testing.T) {
+		var ids []string
+		var cursor string
+		for {
+			page, next, err := s.ListNodes(2, cursor)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			ids = append(ids, page...)
+			if next == "" {
+				break
+			}
+			cursor = next
+		}
+		sort.Strings(ids)
+		if expected := []string{"a", "b", "c", "d", "e"}; !reflect.DeepEqual(ids, expected) {
+			t.Errorf("expected %v, got %v", expected, ids)
+		}
+	})
+	t.Run("Labels are queried using the index", func(t *testing.T) {
+		ids, next, err := s.QueryByLabel("letter", 3, "")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if expected := []string{"a", "b", "c"}; !reflect.DeepEqual(ids, expected) || next != "c" {
+			t.Errorf("expected %v and cursor c, got %v and %q", expected, ids, next)
+		}
+		ids, _, err = s.QueryByLabel("letter", 3, next)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if expected := []string{"d", "e"}; !reflect.DeepEqual(ids, expected) {
+			t.Errorf("expected %v, got %v", expected, ids)
+		}
+	})
+	t.Run("Deleted nodes aren't found", func(t *testing.T) {
+		if err := s.Delete("b"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, ok, _ := s.Get("b"); ok {
+			t.Errorf("expected b to be deleted")
+		}
+		n, _, _ := s.Get("a")
+		if len(n.Children) != 0 {
+			t.Errorf("expected the edge to b to be deleted, got %+v", n.Children)
+		}
+	})
+}
+
+func TestNewMemoryStore(t *testing.T) {
+	s := NewMemoryStore()
+	s.RegisterDataType(func() interface{} { return &testNodeData{} })
+	if err := s.ValidateSchema(context.Background()); err != nil {
+		t.Fatalf("expected the schema to be valid, got %v", err)
+	}
+	if err := s.Put(NewNode("a").WithLabels("letter").WithData(testNodeData{ExtraAttribute: "a"})); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := s.Put(NewNode("b")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ids, _, err := s.ListNodes(0, "")
+	if err != nil {
+		t.Fatalf("unexpected error listing nodes: %v", err)
+	}
+	if expected := []string{"a", "b"}; !reflect.DeepEqual(ids, expected) {
+		t.Errorf("expected nodes %v, got %v", expected, ids)
+	}
+	ids, _, err = s.QueryByLabel("letter", 0, "")
+	if err != nil {
+		t.Fatalf("unexpected error querying labels: %v", err)
+	}
+	if expected := []string{"a"}; !reflect.DeepEqual(ids, expected) {
+		t.Errorf("expected labelled nodes %v, got %v", expected, ids)
+	}
+	ids, _, err = s.QueryByDataType("testNodeData", 0, "")
+	if err != nil {
+		t.Fatalf("unexpected error querying data types: %v", err)
+	}
+	if expected := []string{"a"}; !reflect.DeepEqual(ids, expected) {
+		t.Errorf("expected nodes with data %v, got %v", expected, ids)
+	}
+}
@@ -0,0 +1,50 @@
+package pregel
+
+import (
+	"github.com/a-h/pregel/db"
+	"github.com/a-h/pregel/db/file"
+	"github.com/a-h/pregel/db/memory"
+)
+
+// Names of the indexes of the tables created by NewMemoryStore and NewFileStore.
+const (
+	memoryLabelIndex    = "labels"
+	memoryRangeIndex    = "ranges"
+	memoryDataTypeIndex = "dataTypes"
+)
+
+var memoryIndexes = []db.TableIndex{
+	{Name: memoryLabelIndex, PartitionKey: LabelAttributeName, SortKey: fieldID},
+	{Name: memoryRangeIndex, PartitionKey: fieldRange, SortKey: fieldID},
+	{Name: memoryDataTypeIndex, PartitionKey: fieldRecordDataType, SortKey: fieldID},
+}
+
+// NewMemoryStore creates a store which keeps the graph in memory, e.g. for local development and
+// tests which shouldn't need AWS. The LabelIndex, RangeIndex and DataTypeIndex are configured, so
+// every Store method can be used, except for backups, which need S3. The graph is lost when the
+// process exits.
+func NewMemoryStore() (store *Store) {
+	client := memory.New(memoryIndexes...)
+	client.TTLAttribute = TTLAttributeName
+	return newLocalStore(client)
+}
+
+// NewFileStore creates a store which keeps the graph in a directory, with a JSON file for each node,
+// e.g. for examples, and golden file tests of applications which use pregel. The graph is read from
+// the directory if it already exists. The indexes are configured in the same way as NewMemoryStore.
+func NewFileStore(dir string) (store *Store, err error) {
+	client, err := file.New(dir, memoryIndexes...)
+	if err != nil {
+		return
+	}
+	client.TTLAttribute = TTLAttributeName
+	return newLocalStore(client), nil
+}
+
+func newLocalStore(client DB) (store *Store) {
+	store = NewStoreWithClient(client)
+	store.LabelIndex = memoryLabelIndex
+	store.RangeIndex = memoryRangeIndex
+	store.DataTypeIndex = memoryDataTypeIndex
+	return
+}
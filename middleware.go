@@ -0,0 +1,225 @@
+package pregel
+
+import (
+	"context"
+
+	"github.com/a-h/pregel/db"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// Operation is a read or write made by the Store to the database.
+type Operation struct {
+	// Name of the database operation, e.g. "BatchPut" or "QueryByID".
+	Name string
+	// IDs of the nodes whose records are read or written. Scans and index queries read records from
+	// many nodes, so don't have IDs.
+	IDs []string
+}
+
+// Handler carries out an operation. The context is passed to the database, so middleware can add
+// values to it, e.g. a request ID or a tracing span, before calling next.
+type Handler func(ctx context.Context) error
+
+// Middleware wraps the Handler of an operation, e.g. to log or authorize it. To continue with the
+// operation, the returned Handler must call next.
+type Middleware func(op Operation, next Handler) Handler
+
+// Use adds middleware which wraps every read and write that the Store makes to the database. The
+// first middleware added is the outermost. Stores which were copied from s, e.g. by WithContext,
+// keep their existing middleware.
+func (s *Store) Use(middleware ...Middleware) {
+	client := s.Client
+	var existing []Middleware
+	if mdb, ok := s.Client.(*middlewareDB); ok {
+		client = mdb.client
+		existing = mdb.middleware
+	}
+	chain := make([]Middleware, 0, len(existing)+len(middleware))
+	chain = append(chain, existing...)
+	s.Client = &middlewareDB{
+		client:     client,
+		middleware: append(chain, middleware...),
+	}
+}
+
+// middlewareDB runs middleware around each call to the underlying DB.
+type middlewareDB struct {
+	client     DB
+	middleware []Middleware
+}
+
+func (mdb *middlewareDB) run(ctx context.Context, op Operation, h Handler) error {
+	for i := len(mdb.middleware) - 1; i >= 0; i-- {
+		h = mdb.middleware[i](op, h)
+	}
+	return h(ctx)
+}
+
+// recordIDs returns the distinct IDs of the records, in order.
+func recordIDs(records []map[string]*dynamodb.AttributeValue) (ids []string) {
+	seen := make(map[string]bool)
+	for _, r := range records {
+		id, ok := r[fieldID]
+		if !ok || id.S == nil || seen[*id.S] {
+			continue
+		}
+		seen[*id.S] = true
+		ids = append(ids, *id.S)
+	}
+	return
+}
+
+func (mdb *middlewareDB) BatchDelete(ctx context.Context, keys []map[string]*dynamodb.AttributeValue) (cc db.ConsumedCapacity, err error) {
+	err = mdb.run(ctx, Operation{Name: "BatchDelete", IDs: recordIDs(keys)}, func(ctx context.Context) (hErr error) {
+		cc, hErr = mdb.client.BatchDelete(ctx, keys)
+		return
+	})
+	return
+}
+
+func (mdb *middlewareDB) BatchPut(ctx context.Context, items []map[string]*dynamodb.AttributeValue) (cc db.ConsumedCapacity, err error) {
+	err = mdb.run(ctx, Operation{Name: "BatchPut", IDs: recordIDs(items)}, func(ctx context.Context) (hErr error) {
+		cc, hErr = mdb.client.BatchPut(ctx, items)
+		return
+	})
+	return
+}
+
+func (mdb *middlewareDB) TryBatchPut(ctx context.Context, items []map[string]*dynamodb.AttributeValue) (unprocessed []map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error) {
+	err = mdb.run(ctx, Operation{Name: "TryBatchPut", IDs: recordIDs(items)}, func(ctx context.Context) (hErr error) {
+		unprocessed, cc, hErr = mdb.client.TryBatchPut(ctx, items)
+		return
+	})
+	return
+}
+
+func (mdb *middlewareDB) PutIfNotExists(ctx context.Context, idField string, item map[string]*dynamodb.AttributeValue) (cc db.ConsumedCapacity, err error) {
+	op := Operation{Name: "PutIfNotExists", IDs: recordIDs([]map[string]*dynamodb.AttributeValue{item})}
+	err = mdb.run(ctx, op, func(ctx context.Context) (hErr error) {
+		cc, hErr = mdb.client.PutIfNotExists(ctx, idField, item)
+		return
+	})
+	return
+}
+
+func (mdb *middlewareDB) TransactPut(ctx context.Context, items []map[string]*dynamodb.AttributeValue) (cc db.ConsumedCapacity, err error) {
+	err = mdb.run(ctx, Operation{Name: "TransactPut", IDs: recordIDs(items)}, func(ctx context.Context) (hErr error) {
+		cc, hErr = mdb.client.TransactPut(ctx, items)
+		return
+	})
+	return
+}
+
+func (mdb *middlewareDB) TransactWrite(ctx context.Context, items []db.TransactWriteItem) (cc db.ConsumedCapacity, err error) {
+	var records []map[string]*dynamodb.AttributeValue
+	for _, item := range items {
+		for _, r := range []map[string]*dynamodb.AttributeValue{item.Put, item.Delete, item.ConditionCheck} {
+			if r != nil {
+				records = append(records, r)
+			}
+		}
+	}
+	err = mdb.run(ctx, Operation{Name: "TransactWrite", IDs: recordIDs(records)}, func(ctx context.Context) (hErr error) {
+		cc, hErr = mdb.client.TransactWrite(ctx, items)
+		return
+	})
+	return
+}
+
+func (mdb *middlewareDB) TransactPutIfExists(ctx context.Context, key map[string]*dynamodb.AttributeValue, idField, excludedField string, items []map[string]*dynamodb.AttributeValue) (cc db.ConsumedCapacity, err error) {
+	err = mdb.run(ctx, Operation{Name: "TransactPutIfExists", IDs: recordIDs(items)}, func(ctx context.Context) (hErr error) {
+		cc, hErr = mdb.client.TransactPutIfExists(ctx, key, idField, excludedField, items)
+		return
+	})
+	return
+}
+
+func (mdb *middlewareDB) UpdateItem(ctx context.Context, key map[string]*dynamodb.AttributeValue, set map[string]*dynamodb.AttributeValue) (cc db.ConsumedCapacity, err error) {
+	op := Operation{Name: "UpdateItem", IDs: recordIDs([]map[string]*dynamodb.AttributeValue{key})}
+	err = mdb.run(ctx, op, func(ctx context.Context) (hErr error) {
+		cc, hErr = mdb.client.UpdateItem(ctx, key, set)
+		return
+	})
+	return
+}
+
+func (mdb *middlewareDB) GetItem(ctx context.Context, key map[string]*dynamodb.AttributeValue, attributes ...string) (item map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error) {
+	op := Operation{Name: "GetItem", IDs: recordIDs([]map[string]*dynamodb.AttributeValue{key})}
+	err = mdb.run(ctx, op, func(ctx context.Context) (hErr error) {
+		item, cc, hErr = mdb.client.GetItem(ctx, key, attributes...)
+		return
+	})
+	return
+}
+
+func (mdb *middlewareDB) BatchGet(ctx context.Context, keys []map[string]*dynamodb.AttributeValue) (items []map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error) {
+	err = mdb.run(ctx, Operation{Name: "BatchGet", IDs: recordIDs(keys)}, func(ctx context.Context) (hErr error) {
+		items, cc, hErr = mdb.client.BatchGet(ctx, keys)
+		return
+	})
+	return
+}
+
+func (mdb *middlewareDB) QueryByID(ctx context.Context, idField, idValue string, attributes ...string) (items []map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error) {
+	err = mdb.run(ctx, Operation{Name: "QueryByID", IDs: []string{idValue}}, func(ctx context.Context) (hErr error) {
+		items, cc, hErr = mdb.client.QueryByID(ctx, idField, idValue, attributes...)
+		return
+	})
+	return
+}
+
+func (mdb *middlewareDB) QueryByIDAndRangePrefix(ctx context.Context, idField, idValue, rangeField, rangePrefix string, attributes ...string) (items []map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error) {
+	err = mdb.run(ctx, Operation{Name: "QueryByIDAndRangePrefix", IDs: []string{idValue}}, func(ctx context.Context) (hErr error) {
+		items, cc, hErr = mdb.client.QueryByIDAndRangePrefix(ctx, idField, idValue, rangeField, rangePrefix, attributes...)
+		return
+	})
+	return
+}
+
+func (mdb *middlewareDB) QueryIndex(ctx context.Context, indexName, field string, value interface{}) (items []map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error) {
+	err = mdb.run(ctx, Operation{Name: "QueryIndex"}, func(ctx context.Context) (hErr error) {
+		items, cc, hErr = mdb.client.QueryIndex(ctx, indexName, field, value)
+		return
+	})
+	return
+}
+
+func (mdb *middlewareDB) QueryIndexPage(ctx context.Context, indexName, field string, value interface{}, limit int64, startKey map[string]*dynamodb.AttributeValue) (items []map[string]*dynamodb.AttributeValue, lastEvaluatedKey map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error) {
+	err = mdb.run(ctx, Operation{Name: "QueryIndexPage"}, func(ctx context.Context) (hErr error) {
+		items, lastEvaluatedKey, cc, hErr = mdb.client.QueryIndexPage(ctx, indexName, field, value, limit, startKey)
+		return
+	})
+	return
+}
+
+func (mdb *middlewareDB) ScanPage(ctx context.Context, field, value string, limit int64, startKey map[string]*dynamodb.AttributeValue) (items []map[string]*dynamodb.AttributeValue, lastEvaluatedKey map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error) {
+	err = mdb.run(ctx, Operation{Name: "ScanPage"}, func(ctx context.Context) (hErr error) {
+		items, lastEvaluatedKey, cc, hErr = mdb.client.ScanPage(ctx, field, value, limit, startKey)
+		return
+	})
+	return
+}
+
+func (mdb *middlewareDB) ScanSegment(ctx context.Context, segment, totalSegments, limit int64, startKey map[string]*dynamodb.AttributeValue) (items []map[string]*dynamodb.AttributeValue, lastEvaluatedKey map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error) {
+	err = mdb.run(ctx, Operation{Name: "ScanSegment"}, func(ctx context.Context) (hErr error) {
+		items, lastEvaluatedKey, cc, hErr = mdb.client.ScanSegment(ctx, segment, totalSegments, limit, startKey)
+		return
+	})
+	return
+}
+
+func (mdb *middlewareDB) QueryPage(ctx context.Context, idField, idValue, rangeField, rangePrefix string, limit int64, startKey map[string]*dynamodb.AttributeValue) (items []map[string]*dynamodb.AttributeValue, lastEvaluatedKey map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error) {
+	err = mdb.run(ctx, Operation{Name: "QueryPage", IDs: []string{idValue}}, func(ctx context.Context) (hErr error) {
+		items, lastEvaluatedKey, cc, hErr = mdb.client.QueryPage(ctx, idField, idValue, rangeField, rangePrefix, limit, startKey)
+		return
+	})
+	return
+}
+
+func (mdb *middlewareDB) DescribeTable(ctx context.Context) (td db.TableDescription, err error) {
+	err = mdb.run(ctx, Operation{Name: "DescribeTable"}, func(ctx context.Context) (hErr error) {
+		td, hErr = mdb.client.DescribeTable(ctx)
+		return
+	})
+	return
+}
@@ -0,0 +1,105 @@
+package pregel
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/a-h/pregel/db"
+	"github.com/a-h/pregel/rangefield"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+func TestStoreUse(t *testing.T) {
+	client := newdynamoDBClient()
+	client.batchPutter = func(items []map[string]*dynamodb.AttributeValue) (db.ConsumedCapacity, error) {
+		return db.ConsumedCapacity{ConsumedCapacity: 1}, nil
+	}
+	client.queryByIDer = func(idField, idValue string) ([]map[string]*dynamodb.AttributeValue, db.ConsumedCapacity, error) {
+		return nil, db.ConsumedCapacity{}, nil
+	}
+	s := NewStoreWithClient(client)
+
+	var calls []string
+	s.Use(func(op Operation, next Handler) Handler {
+		return func(ctx context.Context) error {
+			calls = append(calls, "outer:"+op.Name)
+			return next(ctx)
+		}
+	})
+	errTestUnauthorized := errors.New("unauthorized")
+	s.Use(func(op Operation, next Handler) Handler {
+		return func(ctx context.Context) error {
+			calls = append(calls, "inner:"+op.Name)
+			for _, id := range op.IDs {
+				if id == "secret" {
+					return errTestUnauthorized
+				}
+			}
+			return next(ctx)
+		}
+	})
+
+	if err := s.Put(NewNode("nodeA").WithChildren(NewEdge("nodeB"))); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cc := s.CapacitySnapshot(); cc.ConsumedCapacity != 1 {
+		t.Errorf("expected the operation's capacity to be recorded, got %+v", cc)
+	}
+	if _, _, err := s.Get("secret"); err != errTestUnauthorized {
+		t.Errorf("expected the middleware to be able to stop the operation, got %v", err)
+	}
+	expected := []string{"outer:BatchPut", "inner:BatchPut", "outer:QueryByID", "inner:QueryByID"}
+	if !reflect.DeepEqual(calls, expected) {
+		t.Errorf("expected calls %v, got %v", expected, calls)
+	}
+}
+
+type requestIDKey struct{}
+
+func TestStoreUseContext(t *testing.T) {
+	client := newdynamoDBClient()
+	client.queryByIDer = func(idField, idValue string) ([]map[string]*dynamodb.AttributeValue, db.ConsumedCapacity, error) {
+		return nil, db.ConsumedCapacity{}, nil
+	}
+	s := NewStoreWithClient(client)
+	s.Use(func(op Operation, next Handler) Handler {
+		return func(ctx context.Context) error {
+			return next(context.WithValue(ctx, requestIDKey{}, "request-1"))
+		}
+	})
+
+	// Middleware added to a copy of the Store mustn't change the parent's chain.
+	var requestIDs []interface{}
+	c := s.WithContext(context.Background())
+	c.Use(func(op Operation, next Handler) Handler {
+		return func(ctx context.Context) error {
+			requestIDs = append(requestIDs, ctx.Value(requestIDKey{}))
+			return next(ctx)
+		}
+	})
+	if _, _, err := c.Get("nodeA"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, _, err := s.Get("nodeA"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(requestIDs, []interface{}{"request-1"}) {
+		t.Errorf("expected the inner middleware to run once, with the request ID, got %v", requestIDs)
+	}
+	if mdb := s.Client.(*middlewareDB); len(mdb.middleware) != 1 {
+		t.Errorf("expected the parent to keep 1 middleware, got %d", len(mdb.middleware))
+	}
+}
+
+func TestRecordIDs(t *testing.T) {
+	records := []map[string]*dynamodb.AttributeValue{
+		getID("nodeA", rangefield.Node{}),
+		getID("nodeB", rangefield.Node{}),
+		getID("nodeA", rangefield.Node{}),
+	}
+	if ids := recordIDs(records); !reflect.DeepEqual(ids, []string{"nodeA", "nodeB"}) {
+		t.Errorf("expected distinct IDs, got %v", ids)
+	}
+}
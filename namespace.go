@@ -0,0 +1,268 @@
+package pregel
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/a-h/pregel/db"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// namespaceSeparator separates the namespace from the node ID in the partition key.
+const namespaceSeparator = "/"
+
+// ErrInvalidNamespace is returned by WithNamespace when the namespace is empty, or contains the
+// namespace separator, "/".
+var ErrInvalidNamespace = errors.New("namespaces must not be empty, or contain \"" + namespaceSeparator + "\"")
+
+// WithNamespace returns a copy of the Store which prefixes the partition key of every record it
+// writes with the namespace, and strips the prefix from records it reads. This allows multiple
+// tenants or environments to share a table without their node IDs colliding. Capacity statistics
+// are tracked separately by the returned Store. Namespaces can't contain "/", otherwise the nodes of
+// namespace "a/b" would be the nodes of namespace "a" whose IDs start with "b/".
+func (s *Store) WithNamespace(namespace string) (ns *Store, err error) {
+	if err = ValidateNamespace(namespace); err != nil {
+		return
+	}
+	ns = s.withClient(newNamespacedDB(s.Client, namespace))
+	return
+}
+
+// ValidateNamespace returns ErrInvalidNamespace if the namespace can't be used by WithNamespace.
+func ValidateNamespace(namespace string) error {
+	if namespace == "" || strings.Contains(namespace, namespaceSeparator) {
+		return ErrInvalidNamespace
+	}
+	return nil
+}
+
+func newNamespacedDB(client DB, namespace string) namespacedDB {
+	return namespacedDB{
+		client: client,
+		prefix: namespace + namespaceSeparator,
+	}
+}
+
+// InNamespace returns the event with the namespace removed from its NodeID, or false if the node
+// isn't in the namespace, e.g. to pass the events emitted by a Store to the subscribers of one of its
+// namespaces. The Child of an edge is stored without the namespace, so it isn't changed.
+func (e ChangeEvent) InNamespace(namespace string) (ne ChangeEvent, ok bool) {
+	prefix := namespace + namespaceSeparator
+	if !strings.HasPrefix(e.NodeID, prefix) {
+		return
+	}
+	ne = e
+	ne.NodeID = strings.TrimPrefix(e.NodeID, prefix)
+	return ne, true
+}
+
+// namespacedDB prefixes the ID field of records written to the underlying DB, and strips the prefix
+// from the records that are read. Records outside of the namespace are not returned.
+type namespacedDB struct {
+	client DB
+	prefix string
+}
+
+func (ndb namespacedDB) addPrefix(item map[string]*dynamodb.AttributeValue) map[string]*dynamodb.AttributeValue {
+	if item == nil {
+		return nil
+	}
+	prefixed := make(map[string]*dynamodb.AttributeValue, len(item))
+	for k, v := range item {
+		prefixed[k] = v
+	}
+	if id, ok := item[fieldID]; ok && id.S != nil {
+		prefixed[fieldID] = &dynamodb.AttributeValue{S: aws.String(ndb.prefix + *id.S)}
+	}
+	return prefixed
+}
+
+func (ndb namespacedDB) addPrefixes(items []map[string]*dynamodb.AttributeValue) (prefixed []map[string]*dynamodb.AttributeValue) {
+	for _, itm := range items {
+		prefixed = append(prefixed, ndb.addPrefix(itm))
+	}
+	return
+}
+
+// removePrefix strips the namespace from the item's ID. If the item isn't in the namespace, ok is false.
+func (ndb namespacedDB) removePrefix(item map[string]*dynamodb.AttributeValue) (stripped map[string]*dynamodb.AttributeValue, ok bool) {
+	if item == nil {
+		return
+	}
+	id, hasID := item[fieldID]
+	if !hasID || id.S == nil || !strings.HasPrefix(*id.S, ndb.prefix) {
+		return
+	}
+	stripped = make(map[string]*dynamodb.AttributeValue, len(item))
+	for k, v := range item {
+		stripped[k] = v
+	}
+	stripped[fieldID] = &dynamodb.AttributeValue{S: aws.String(strings.TrimPrefix(*id.S, ndb.prefix))}
+	ok = true
+	return
+}
+
+func (ndb namespacedDB) removePrefixes(items []map[string]*dynamodb.AttributeValue) (stripped []map[string]*dynamodb.AttributeValue) {
+	for _, itm := range items {
+		if s, ok := ndb.removePrefix(itm); ok {
+			stripped = append(stripped, s)
+		}
+	}
+	return
+}
+
+func (ndb namespacedDB) BatchDelete(ctx context.Context, keys []map[string]*dynamodb.AttributeValue) (db.ConsumedCapacity, error) {
+	return ndb.client.BatchDelete(ctx, ndb.addPrefixes(keys))
+}
+
+func (ndb namespacedDB) BatchPut(ctx context.Context, items []map[string]*dynamodb.AttributeValue) (db.ConsumedCapacity, error) {
+	return ndb.client.BatchPut(ctx, ndb.addPrefixes(items))
+}
+
+func (ndb namespacedDB) TryBatchPut(ctx context.Context, items []map[string]*dynamodb.AttributeValue) (unprocessed []map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error) {
+	unprocessed, cc, err = ndb.client.TryBatchPut(ctx, ndb.addPrefixes(items))
+	unprocessed = ndb.removePrefixes(unprocessed)
+	return
+}
+
+func (ndb namespacedDB) PutIfNotExists(ctx context.Context, idField string, item map[string]*dynamodb.AttributeValue) (db.ConsumedCapacity, error) {
+	return ndb.client.PutIfNotExists(ctx, idField, ndb.addPrefix(item))
+}
+
+func (ndb namespacedDB) TransactPut(ctx context.Context, items []map[string]*dynamodb.AttributeValue) (db.ConsumedCapacity, error) {
+	return ndb.client.TransactPut(ctx, ndb.addPrefixes(items))
+}
+
+func (ndb namespacedDB) UpdateItem(ctx context.Context, key map[string]*dynamodb.AttributeValue, set map[string]*dynamodb.AttributeValue) (db.ConsumedCapacity, error) {
+	return ndb.client.UpdateItem(ctx, ndb.addPrefix(key), set)
+}
+
+func (ndb namespacedDB) TransactWrite(ctx context.Context, items []db.TransactWriteItem) (db.ConsumedCapacity, error) {
+	prefixed := make([]db.TransactWriteItem, len(items))
+	for i, item := range items {
+		prefixed[i] = db.TransactWriteItem{
+			Put:            ndb.addPrefix(item.Put),
+			Delete:         ndb.addPrefix(item.Delete),
+			ConditionCheck: ndb.addPrefix(item.ConditionCheck),
+			Condition:      item.Condition,
+		}
+	}
+	return ndb.client.TransactWrite(ctx, prefixed)
+}
+
+func (ndb namespacedDB) TransactPutIfExists(ctx context.Context, key map[string]*dynamodb.AttributeValue, idField, excludedField string, items []map[string]*dynamodb.AttributeValue) (db.ConsumedCapacity, error) {
+	return ndb.client.TransactPutIfExists(ctx, ndb.addPrefix(key), idField, excludedField, ndb.addPrefixes(items))
+}
+
+// GetItem gets the item with the key in the namespace. The ID field is always projected, since it's
+// needed to remove the namespace prefix.
+func (ndb namespacedDB) GetItem(ctx context.Context, key map[string]*dynamodb.AttributeValue, attributes ...string) (item map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error) {
+	if len(attributes) > 0 {
+		attributes = withAttribute(attributes, fieldID)
+	}
+	item, cc, err = ndb.client.GetItem(ctx, ndb.addPrefix(key), attributes...)
+	item, _ = ndb.removePrefix(item)
+	return
+}
+
+func (ndb namespacedDB) BatchGet(ctx context.Context, keys []map[string]*dynamodb.AttributeValue) (items []map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error) {
+	items, cc, err = ndb.client.BatchGet(ctx, ndb.addPrefixes(keys))
+	items = ndb.removePrefixes(items)
+	return
+}
+
+// QueryByID queries the items with the ID in the namespace. The ID field is always projected, since
+// it's needed to remove the namespace prefix.
+func (ndb namespacedDB) QueryByID(ctx context.Context, idField, idValue string, attributes ...string) (items []map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error) {
+	if len(attributes) > 0 {
+		attributes = withAttribute(attributes, fieldID)
+	}
+	items, cc, err = ndb.client.QueryByID(ctx, idField, ndb.prefix+idValue, attributes...)
+	items = ndb.removePrefixes(items)
+	return
+}
+
+// QueryByIDAndRangePrefix queries the items with the ID in the namespace whose range field begins with
+// the prefix.
+func (ndb namespacedDB) QueryByIDAndRangePrefix(ctx context.Context, idField, idValue, rangeField, rangePrefix string, attributes ...string) (items []map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error) {
+	if len(attributes) > 0 {
+		attributes = withAttribute(attributes, fieldID)
+	}
+	items, cc, err = ndb.client.QueryByIDAndRangePrefix(ctx, idField, ndb.prefix+idValue, rangeField, rangePrefix, attributes...)
+	items = ndb.removePrefixes(items)
+	return
+}
+
+// withAttribute returns the attributes, adding the attribute if it's missing.
+func withAttribute(attributes []string, attribute string) []string {
+	for _, a := range attributes {
+		if a == attribute {
+			return attributes
+		}
+	}
+	return append(append([]string{}, attributes...), attribute)
+}
+
+func (ndb namespacedDB) QueryIndex(ctx context.Context, indexName, field string, value interface{}) (items []map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error) {
+	items, cc, err = ndb.client.QueryIndex(ctx, indexName, field, value)
+	items = ndb.removePrefixes(items)
+	return
+}
+
+// ScanPage scans the table, returning the items in the namespace.
+func (ndb namespacedDB) ScanPage(ctx context.Context, field, value string, limit int64, startKey map[string]*dynamodb.AttributeValue) (items []map[string]*dynamodb.AttributeValue, lastEvaluatedKey map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error) {
+	return ndb.pageWithinNamespace(startKey, func(startKey map[string]*dynamodb.AttributeValue) ([]map[string]*dynamodb.AttributeValue, map[string]*dynamodb.AttributeValue, db.ConsumedCapacity, error) {
+		return ndb.client.ScanPage(ctx, field, value, limit, startKey)
+	})
+}
+
+// ScanSegment scans a segment of the table, returning the items in the namespace.
+func (ndb namespacedDB) ScanSegment(ctx context.Context, segment, totalSegments, limit int64, startKey map[string]*dynamodb.AttributeValue) (items []map[string]*dynamodb.AttributeValue, lastEvaluatedKey map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error) {
+	return ndb.pageWithinNamespace(startKey, func(startKey map[string]*dynamodb.AttributeValue) ([]map[string]*dynamodb.AttributeValue, map[string]*dynamodb.AttributeValue, db.ConsumedCapacity, error) {
+		return ndb.client.ScanSegment(ctx, segment, totalSegments, limit, startKey)
+	})
+}
+
+// QueryIndexPage queries the index, returning the items in the namespace.
+func (ndb namespacedDB) QueryIndexPage(ctx context.Context, indexName, field string, value interface{}, limit int64, startKey map[string]*dynamodb.AttributeValue) (items []map[string]*dynamodb.AttributeValue, lastEvaluatedKey map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error) {
+	return ndb.pageWithinNamespace(startKey, func(startKey map[string]*dynamodb.AttributeValue) ([]map[string]*dynamodb.AttributeValue, map[string]*dynamodb.AttributeValue, db.ConsumedCapacity, error) {
+		return ndb.client.QueryIndexPage(ctx, indexName, field, value, limit, startKey)
+	})
+}
+
+// pageWithinNamespace gets a page of items which may span namespaces, and returns the items in the
+// namespace. If a page ends on an item in another namespace, paging continues, so that the returned
+// lastEvaluatedKey is always in the namespace.
+func (ndb namespacedDB) pageWithinNamespace(startKey map[string]*dynamodb.AttributeValue, page func(startKey map[string]*dynamodb.AttributeValue) ([]map[string]*dynamodb.AttributeValue, map[string]*dynamodb.AttributeValue, db.ConsumedCapacity, error)) (items []map[string]*dynamodb.AttributeValue, lastEvaluatedKey map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error) {
+	startKey = ndb.addPrefix(startKey)
+	for {
+		pageItems, lek, pageCC, pageErr := page(startKey)
+		cc = addCapacity(cc, pageCC)
+		if pageErr != nil {
+			err = pageErr
+			return
+		}
+		items = append(items, ndb.removePrefixes(pageItems)...)
+		if lek == nil {
+			return
+		}
+		var inNamespace bool
+		if lastEvaluatedKey, inNamespace = ndb.removePrefix(lek); inNamespace {
+			return
+		}
+		startKey = lek
+	}
+}
+
+func (ndb namespacedDB) QueryPage(ctx context.Context, idField, idValue, rangeField, rangePrefix string, limit int64, startKey map[string]*dynamodb.AttributeValue) (items []map[string]*dynamodb.AttributeValue, lastEvaluatedKey map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error) {
+	items, lastEvaluatedKey, cc, err = ndb.client.QueryPage(ctx, idField, ndb.prefix+idValue, rangeField, rangePrefix, limit, ndb.addPrefix(startKey))
+	items = ndb.removePrefixes(items)
+	lastEvaluatedKey, _ = ndb.removePrefix(lastEvaluatedKey)
+	return
+}
+
+func (ndb namespacedDB) DescribeTable(ctx context.Context) (db.TableDescription, error) {
+	return ndb.client.DescribeTable(ctx)
+}
@@ -0,0 +1,173 @@
+package pregel
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/a-h/pregel/db"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+func TestStoreWithNamespace(t *testing.T) {
+	client := newdynamoDBClient()
+	var actualItems []map[string]*dynamodb.AttributeValue
+	client.batchPutter = func(items []map[string]*dynamodb.AttributeValue) (db.ConsumedCapacity, error) {
+		actualItems = items
+		return db.ConsumedCapacity{}, nil
+	}
+	client.queryByIDer = func(idField, idValue string) ([]map[string]*dynamodb.AttributeValue, db.ConsumedCapacity, error) {
+		var items []map[string]*dynamodb.AttributeValue
+		for _, itm := range actualItems {
+			if *itm[fieldID].S == idValue {
+				items = append(items, itm)
+			}
+		}
+		return items, db.ConsumedCapacity{}, nil
+	}
+	var scanStartKeys []map[string]*dynamodb.AttributeValue
+	client.scanPager = func(field, value string, limit int64, startKey map[string]*dynamodb.AttributeValue) ([]map[string]*dynamodb.AttributeValue, map[string]*dynamodb.AttributeValue, db.ConsumedCapacity, error) {
+		scanStartKeys = append(scanStartKeys, startKey)
+		if startKey == nil {
+			// The first page ends on a node in another namespace.
+			otherTenant := map[string]*dynamodb.AttributeValue{"id": {S: aws.String("tenantB/nodeA")}, "rng": {S: aws.String("node")}}
+			return []map[string]*dynamodb.AttributeValue{otherTenant}, otherTenant, db.ConsumedCapacity{}, nil
+		}
+		return []map[string]*dynamodb.AttributeValue{
+			{"id": {S: aws.String("tenantA/nodeA")}, "rng": {S: aws.String("node")}},
+		}, nil, db.ConsumedCapacity{}, nil
+	}
+
+	s, err := NewStoreWithClient(client).WithNamespace("tenantA")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	err = s.Put(NewNode("nodeA").WithChildren(NewEdge("nodeB")))
+	if err != nil {
+		t.Fatalf("unexpected error putting node: %v", err)
+	}
+	expectedItems := []map[string]*dynamodb.AttributeValue{
+		{"id": {S: aws.String("tenantA/nodeA")}, "rng": {S: aws.String("node")}},
+		{"id": {S: aws.String("tenantA/nodeA")}, "rng": {S: aws.String("child/nodeB")}},
+		{"id": {S: aws.String("tenantA/nodeB")}, "rng": {S: aws.String("parent/nodeA")}},
+	}
+	if !reflect.DeepEqual(actualItems, expectedItems) {
+		t.Errorf("\nexpected:\n%s\n\ngot:\n%s\n", format(expectedItems), format(actualItems))
+	}
+
+	n, ok, err := s.Get("nodeA")
+	if err != nil {
+		t.Fatalf("unexpected error getting node: %v", err)
+	}
+	if !ok {
+		t.Fatalf("expected node to be found")
+	}
+	expectedNode := NewNode("nodeA").WithChildren(NewEdge("nodeB"))
+	if !reflect.DeepEqual(n, expectedNode) {
+		t.Errorf("expected %+v, got %+v", expectedNode, n)
+	}
+
+	ids, _, err := s.ListNodes(0, "")
+	if err != nil {
+		t.Fatalf("unexpected error listing nodes: %v", err)
+	}
+	if !reflect.DeepEqual(ids, []string{"nodeA"}) {
+		t.Errorf("expected only the node in the namespace to be listed, got %v", ids)
+	}
+	if len(scanStartKeys) != 2 || *scanStartKeys[1][fieldID].S != "tenantB/nodeA" {
+		t.Errorf("expected the scan to continue from the other namespace's key, got %v", format(scanStartKeys))
+	}
+}
+
+func TestStoreWithNamespaceBatchGet(t *testing.T) {
+	client := newdynamoDBClient()
+	var actualKeys []map[string]*dynamodb.AttributeValue
+	client.batchGetter = func(keys []map[string]*dynamodb.AttributeValue) ([]map[string]*dynamodb.AttributeValue, db.ConsumedCapacity, error) {
+		actualKeys = keys
+		return []map[string]*dynamodb.AttributeValue{
+			{"id": {S: aws.String("tenantA/nodeA")}, "rng": {S: aws.String("node")}},
+		}, db.ConsumedCapacity{}, nil
+	}
+	s, err := NewStoreWithClient(client).WithNamespace("tenantA")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	items, _, err := s.Client.BatchGet(context.Background(), []map[string]*dynamodb.AttributeValue{
+		{"id": {S: aws.String("nodeA")}, "rng": {S: aws.String("node")}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectedKeys := []map[string]*dynamodb.AttributeValue{
+		{"id": {S: aws.String("tenantA/nodeA")}, "rng": {S: aws.String("node")}},
+	}
+	if !reflect.DeepEqual(actualKeys, expectedKeys) {
+		t.Errorf("\nexpected keys:\n%s\n\ngot:\n%s\n", format(expectedKeys), format(actualKeys))
+	}
+	expectedItems := []map[string]*dynamodb.AttributeValue{
+		{"id": {S: aws.String("nodeA")}, "rng": {S: aws.String("node")}},
+	}
+	if !reflect.DeepEqual(items, expectedItems) {
+		t.Errorf("\nexpected items:\n%s\n\ngot:\n%s\n", format(expectedItems), format(items))
+	}
+}
+
+func TestStoreWithNamespaceTransactWrite(t *testing.T) {
+	client := newdynamoDBClient()
+	var actualItems []db.TransactWriteItem
+	client.transactWriter = func(items []db.TransactWriteItem) (db.ConsumedCapacity, error) {
+		actualItems = items
+		return db.ConsumedCapacity{}, nil
+	}
+	s, err := NewStoreWithClient(client).WithNamespace("tenantA")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, err = s.Client.TransactWrite(context.Background(), []db.TransactWriteItem{
+		{Put: map[string]*dynamodb.AttributeValue{"id": {S: aws.String("nodeA")}, "rng": {S: aws.String("node")}}},
+		{Delete: map[string]*dynamodb.AttributeValue{"id": {S: aws.String("nodeB")}, "rng": {S: aws.String("node")}}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectedItems := []db.TransactWriteItem{
+		{Put: map[string]*dynamodb.AttributeValue{"id": {S: aws.String("tenantA/nodeA")}, "rng": {S: aws.String("node")}}},
+		{Delete: map[string]*dynamodb.AttributeValue{"id": {S: aws.String("tenantA/nodeB")}, "rng": {S: aws.String("node")}}},
+	}
+	if !reflect.DeepEqual(actualItems, expectedItems) {
+		t.Errorf("expected %+v, got %+v", expectedItems, actualItems)
+	}
+}
+
+func TestValidateNamespace(t *testing.T) {
+	tests := []struct {
+		namespace string
+		expected  error
+	}{
+		{namespace: "tenantA"},
+		{namespace: "", expected: ErrInvalidNamespace},
+		{namespace: "tenantA/b", expected: ErrInvalidNamespace},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.namespace, func(t *testing.T) {
+			t.Parallel()
+			if err := ValidateNamespace(test.namespace); err != test.expected {
+				t.Errorf("expected %v, got %v", test.expected, err)
+			}
+		})
+	}
+}
+
+func TestChangeEventInNamespace(t *testing.T) {
+	e := ChangeEvent{Type: EdgeCreated, NodeID: "tenantA/nodeA", Child: "nodeB"}
+	actual, ok := e.InNamespace("tenantA")
+	expected := ChangeEvent{Type: EdgeCreated, NodeID: "nodeA", Child: "nodeB"}
+	if !ok || !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %+v, got %+v, %v", expected, actual, ok)
+	}
+	if _, ok := e.InNamespace("tenant"); ok {
+		t.Errorf("expected the event not to be in a namespace which is a prefix of its namespace")
+	}
+}
@@ -1,5 +1,10 @@
 package pregel
 
+import (
+	"encoding/json"
+	"time"
+)
+
 // Node within the graph.
 type Node struct {
 	ID   string `json:"id"`
@@ -8,6 +13,42 @@ type Node struct {
 	Children []*Edge `json:"children"`
 	// Parents of the node.
 	Parents []*Edge `json:"parents"`
+	// Labels of the node, e.g. "device", which can be queried with QueryByLabel.
+	Labels []string `json:"labels,omitempty"`
+	// CreatedAt is the time that the node was created, if the Store's Timestamps are enabled.
+	CreatedAt time.Time `json:"createdAt"`
+	// UpdatedAt is the time that the node or its data was last written, if the Store's Timestamps
+	// are enabled.
+	UpdatedAt time.Time `json:"updatedAt"`
+	// Deleted is true if the node has been soft deleted.
+	Deleted bool `json:"deleted,omitempty"`
+	// TTL is the time that the node expires, or the zero time if it doesn't expire.
+	TTL time.Time `json:"ttl"`
+}
+
+// MarshalJSON leaves out the timestamps and TTL of the node if they're not set, so that exports don't
+// contain zero times.
+func (n Node) MarshalJSON() ([]byte, error) {
+	type node Node
+	return json.Marshal(struct {
+		node
+		CreatedAt *time.Time `json:"createdAt,omitempty"`
+		UpdatedAt *time.Time `json:"updatedAt,omitempty"`
+		TTL       *time.Time `json:"ttl,omitempty"`
+	}{
+		node:      node(n),
+		CreatedAt: timeOrNil(n.CreatedAt),
+		UpdatedAt: timeOrNil(n.UpdatedAt),
+		TTL:       timeOrNil(n.TTL),
+	})
+}
+
+// timeOrNil returns nil for the zero time.
+func timeOrNil(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
 }
 
 // Data attached to a node or edge.
@@ -38,6 +79,18 @@ func (n Node) WithNamedData(key string, value interface{}) Node {
 	return n
 }
 
+// WithLabels adds labels to the node.
+func (n Node) WithLabels(labels ...string) Node {
+	n.Labels = append(n.Labels, labels...)
+	return n
+}
+
+// WithTTL sets the time that the node and its edges expire.
+func (n Node) WithTTL(t time.Time) Node {
+	n.TTL = t
+	return n
+}
+
 // WithParents adds parents to the node.
 func (n Node) WithParents(parents ...*Edge) Node {
 	n.Parents = append(n.Parents, parents...)
@@ -68,6 +121,11 @@ func (n Node) GetChild(id string) *Edge {
 	return nil
 }
 
+// GetLabelledChild returns the child edge with the given ID and label.
+func (n Node) GetLabelledChild(id, label string) *Edge {
+	return findEdge(n.Children, id, label)
+}
+
 // GetParent edge.
 func (n Node) GetParent(id string) *Edge {
 	for _, ee := range n.Parents {
@@ -78,10 +136,51 @@ func (n Node) GetParent(id string) *Edge {
 	return nil
 }
 
+// GetLabelledParent returns the parent edge with the given ID and label.
+func (n Node) GetLabelledParent(id, label string) *Edge {
+	return findEdge(n.Parents, id, label)
+}
+
+func findEdge(edges []*Edge, id, label string) *Edge {
+	for _, ee := range edges {
+		if ee.ID == id && ee.Label == label {
+			return ee
+		}
+	}
+	return nil
+}
+
 // Edge relationship.
 type Edge struct {
 	ID   string `json:"id"`
 	Data Data   `json:"data"`
+	// Label is the type of the relationship, e.g. "owns", or empty for an unlabelled edge. Edges
+	// between the same nodes with different labels are stored separately, so labels can also be used
+	// as keys to distinguish parallel edges, e.g. "link1" and "link2".
+	Label string `json:"label,omitempty"`
+	// CreatedAt is the time that the edge was created, if the Store's Timestamps are enabled.
+	CreatedAt time.Time `json:"createdAt"`
+	// UpdatedAt is the time that the edge or its data was last written, if the Store's Timestamps
+	// are enabled.
+	UpdatedAt time.Time `json:"updatedAt"`
+	// TTL is the time that the edge expires, or the zero time if it doesn't expire.
+	TTL time.Time `json:"ttl"`
+}
+
+// MarshalJSON leaves out the timestamps and TTL of the edge if they're not set.
+func (e Edge) MarshalJSON() ([]byte, error) {
+	type edge Edge
+	return json.Marshal(struct {
+		edge
+		CreatedAt *time.Time `json:"createdAt,omitempty"`
+		UpdatedAt *time.Time `json:"updatedAt,omitempty"`
+		TTL       *time.Time `json:"ttl,omitempty"`
+	}{
+		edge:      edge(e),
+		CreatedAt: timeOrNil(e.CreatedAt),
+		UpdatedAt: timeOrNil(e.UpdatedAt),
+		TTL:       timeOrNil(e.TTL),
+	})
 }
 
 // NewEdge creates an edge.
@@ -102,3 +201,15 @@ func (e *Edge) WithNamedData(key string, value interface{}) *Edge {
 	e.Data[key] = value
 	return e
 }
+
+// WithLabel sets the type of the relationship.
+func (e *Edge) WithLabel(label string) *Edge {
+	e.Label = label
+	return e
+}
+
+// WithTTL sets the time that the edge expires.
+func (e *Edge) WithTTL(t time.Time) *Edge {
+	e.TTL = t
+	return e
+}
@@ -0,0 +1,338 @@
+package pregel
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/a-h/pregel/db"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// InvalidPartitionKeyError is returned when a node ID can't be stored in a numeric partition key,
+// because it isn't a number.
+type InvalidPartitionKeyError struct {
+	ID string
+}
+
+func (err InvalidPartitionKeyError) Error() string {
+	return fmt.Sprintf("node ID %q can't be stored in a numeric partition key", err.ID)
+}
+
+// WithPartitionKey returns a copy of the Store which stores node IDs in the named partition key
+// attribute, instead of a string attribute named "id", so that the Store can use a table with an
+// existing key schema. The attribute type is "S", "N" or "B". Node IDs are stored as numbers, which
+// must be in the canonical form returned by DynamoDB, e.g. "12" rather than "012", or as the bytes of
+// the ID. Capacity statistics are tracked separately by the returned Store.
+//
+// When the Store uses a db.DB, its PartitionKeyType must be set to the same type, so that queries use
+// it. Global secondary indexes which use the "id" attribute as their sort key, such as the
+// LabelIndex, must use the partition key attribute instead. Conditions passed to TransactWrite
+// aren't changed, so must refer to the partition key attribute by its name.
+func (s *Store) WithPartitionKey(name, attributeType string) (*Store, error) {
+	switch attributeType {
+	case dynamodb.ScalarAttributeTypeS, dynamodb.ScalarAttributeTypeN, dynamodb.ScalarAttributeTypeB:
+	default:
+		return nil, fmt.Errorf("invalid partition key type %q, expected S, N or B", attributeType)
+	}
+	return s.withClient(partitionKeyDB{
+		client:        s.Client,
+		name:          name,
+		attributeType: attributeType,
+	}), nil
+}
+
+// partitionKeyDB converts the string "id" attribute of records written to the underlying DB into the
+// partition key attribute of the table, and converts it back in the records that are read.
+type partitionKeyDB struct {
+	client        DB
+	name          string
+	attributeType string
+}
+
+// field returns the name of the attribute used to store the field.
+func (pdb partitionKeyDB) field(field string) string {
+	if field == fieldID {
+		return pdb.name
+	}
+	return field
+}
+
+// value converts a node ID into a partition key value.
+func (pdb partitionKeyDB) value(id string) (v *dynamodb.AttributeValue, err error) {
+	switch pdb.attributeType {
+	case dynamodb.ScalarAttributeTypeN:
+		if _, ok := new(big.Float).SetString(id); !ok {
+			err = InvalidPartitionKeyError{ID: id}
+			return
+		}
+		return &dynamodb.AttributeValue{N: aws.String(id)}, nil
+	case dynamodb.ScalarAttributeTypeB:
+		return &dynamodb.AttributeValue{B: []byte(id)}, nil
+	}
+	return &dynamodb.AttributeValue{S: aws.String(id)}, nil
+}
+
+func (pdb partitionKeyDB) toTable(item map[string]*dynamodb.AttributeValue) (converted map[string]*dynamodb.AttributeValue, err error) {
+	if item == nil {
+		return
+	}
+	converted = make(map[string]*dynamodb.AttributeValue, len(item))
+	for k, v := range item {
+		converted[k] = v
+	}
+	id, ok := item[fieldID]
+	if !ok || id.S == nil {
+		return
+	}
+	delete(converted, fieldID)
+	converted[pdb.name], err = pdb.value(*id.S)
+	return
+}
+
+func (pdb partitionKeyDB) toTableItems(items []map[string]*dynamodb.AttributeValue) (converted []map[string]*dynamodb.AttributeValue, err error) {
+	converted = make([]map[string]*dynamodb.AttributeValue, len(items))
+	for i, itm := range items {
+		if converted[i], err = pdb.toTable(itm); err != nil {
+			return
+		}
+	}
+	return
+}
+
+func (pdb partitionKeyDB) fromTable(item map[string]*dynamodb.AttributeValue) (converted map[string]*dynamodb.AttributeValue) {
+	if item == nil {
+		return
+	}
+	converted = make(map[string]*dynamodb.AttributeValue, len(item))
+	for k, v := range item {
+		converted[k] = v
+	}
+	pk, ok := item[pdb.name]
+	if !ok {
+		return
+	}
+	delete(converted, pdb.name)
+	switch {
+	case pk.N != nil:
+		converted[fieldID] = &dynamodb.AttributeValue{S: aws.String(*pk.N)}
+	case pk.B != nil:
+		converted[fieldID] = &dynamodb.AttributeValue{S: aws.String(string(pk.B))}
+	default:
+		converted[fieldID] = pk
+	}
+	return
+}
+
+func (pdb partitionKeyDB) fromTableItems(items []map[string]*dynamodb.AttributeValue) (converted []map[string]*dynamodb.AttributeValue) {
+	for _, itm := range items {
+		converted = append(converted, pdb.fromTable(itm))
+	}
+	return
+}
+
+func (pdb partitionKeyDB) BatchDelete(ctx context.Context, keys []map[string]*dynamodb.AttributeValue) (cc db.ConsumedCapacity, err error) {
+	converted, err := pdb.toTableItems(keys)
+	if err != nil {
+		return
+	}
+	return pdb.client.BatchDelete(ctx, converted)
+}
+
+func (pdb partitionKeyDB) BatchPut(ctx context.Context, items []map[string]*dynamodb.AttributeValue) (cc db.ConsumedCapacity, err error) {
+	converted, err := pdb.toTableItems(items)
+	if err != nil {
+		return
+	}
+	return pdb.client.BatchPut(ctx, converted)
+}
+
+func (pdb partitionKeyDB) TryBatchPut(ctx context.Context, items []map[string]*dynamodb.AttributeValue) (unprocessed []map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error) {
+	converted, err := pdb.toTableItems(items)
+	if err != nil {
+		return
+	}
+	unprocessed, cc, err = pdb.client.TryBatchPut(ctx, converted)
+	unprocessed = pdb.fromTableItems(unprocessed)
+	return
+}
+
+func (pdb partitionKeyDB) PutIfNotExists(ctx context.Context, idField string, item map[string]*dynamodb.AttributeValue) (cc db.ConsumedCapacity, err error) {
+	converted, err := pdb.toTable(item)
+	if err != nil {
+		return
+	}
+	return pdb.client.PutIfNotExists(ctx, pdb.field(idField), converted)
+}
+
+func (pdb partitionKeyDB) TransactPut(ctx context.Context, items []map[string]*dynamodb.AttributeValue) (cc db.ConsumedCapacity, err error) {
+	converted, err := pdb.toTableItems(items)
+	if err != nil {
+		return
+	}
+	return pdb.client.TransactPut(ctx, converted)
+}
+
+func (pdb partitionKeyDB) TransactWrite(ctx context.Context, items []db.TransactWriteItem) (cc db.ConsumedCapacity, err error) {
+	converted := make([]db.TransactWriteItem, len(items))
+	for i, item := range items {
+		converted[i].Condition = item.Condition
+		if converted[i].Put, err = pdb.toTable(item.Put); err != nil {
+			return
+		}
+		if converted[i].Delete, err = pdb.toTable(item.Delete); err != nil {
+			return
+		}
+		if converted[i].ConditionCheck, err = pdb.toTable(item.ConditionCheck); err != nil {
+			return
+		}
+	}
+	return pdb.client.TransactWrite(ctx, converted)
+}
+
+func (pdb partitionKeyDB) TransactPutIfExists(ctx context.Context, key map[string]*dynamodb.AttributeValue, idField, excludedField string, items []map[string]*dynamodb.AttributeValue) (cc db.ConsumedCapacity, err error) {
+	convertedKey, err := pdb.toTable(key)
+	if err != nil {
+		return
+	}
+	converted, err := pdb.toTableItems(items)
+	if err != nil {
+		return
+	}
+	return pdb.client.TransactPutIfExists(ctx, convertedKey, pdb.field(idField), excludedField, converted)
+}
+
+func (pdb partitionKeyDB) UpdateItem(ctx context.Context, key map[string]*dynamodb.AttributeValue, set map[string]*dynamodb.AttributeValue) (cc db.ConsumedCapacity, err error) {
+	converted, err := pdb.toTable(key)
+	if err != nil {
+		return
+	}
+	return pdb.client.UpdateItem(ctx, converted, set)
+}
+
+// GetItem gets the item with the key. Projections of the ID use the partition key attribute.
+func (pdb partitionKeyDB) GetItem(ctx context.Context, key map[string]*dynamodb.AttributeValue, attributes ...string) (item map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error) {
+	converted, err := pdb.toTable(key)
+	if err != nil {
+		return
+	}
+	projected := make([]string, len(attributes))
+	for i, a := range attributes {
+		projected[i] = pdb.field(a)
+	}
+	item, cc, err = pdb.client.GetItem(ctx, converted, projected...)
+	item = pdb.fromTable(item)
+	return
+}
+
+func (pdb partitionKeyDB) BatchGet(ctx context.Context, keys []map[string]*dynamodb.AttributeValue) (items []map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error) {
+	converted, err := pdb.toTableItems(keys)
+	if err != nil {
+		return
+	}
+	items, cc, err = pdb.client.BatchGet(ctx, converted)
+	items = pdb.fromTableItems(items)
+	return
+}
+
+// QueryByID queries the items with the ID. Projections of the ID use the partition key attribute.
+func (pdb partitionKeyDB) QueryByID(ctx context.Context, idField, idValue string, attributes ...string) (items []map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error) {
+	if pdb.attributeType == dynamodb.ScalarAttributeTypeN {
+		if _, err = pdb.value(idValue); err != nil {
+			return
+		}
+	}
+	projected := make([]string, len(attributes))
+	for i, a := range attributes {
+		projected[i] = pdb.field(a)
+	}
+	items, cc, err = pdb.client.QueryByID(ctx, pdb.field(idField), idValue, projected...)
+	items = pdb.fromTableItems(items)
+	return
+}
+
+func (pdb partitionKeyDB) QueryByIDAndRangePrefix(ctx context.Context, idField, idValue, rangeField, rangePrefix string, attributes ...string) (items []map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error) {
+	if pdb.attributeType == dynamodb.ScalarAttributeTypeN {
+		if _, err = pdb.value(idValue); err != nil {
+			return
+		}
+	}
+	projected := make([]string, len(attributes))
+	for i, a := range attributes {
+		projected[i] = pdb.field(a)
+	}
+	items, cc, err = pdb.client.QueryByIDAndRangePrefix(ctx, pdb.field(idField), idValue, rangeField, rangePrefix, projected...)
+	items = pdb.fromTableItems(items)
+	return
+}
+
+func (pdb partitionKeyDB) QueryIndex(ctx context.Context, indexName, field string, value interface{}) (items []map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error) {
+	items, cc, err = pdb.client.QueryIndex(ctx, indexName, pdb.field(field), value)
+	items = pdb.fromTableItems(items)
+	return
+}
+
+func (pdb partitionKeyDB) QueryIndexPage(ctx context.Context, indexName, field string, value interface{}, limit int64, startKey map[string]*dynamodb.AttributeValue) (items []map[string]*dynamodb.AttributeValue, lastEvaluatedKey map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error) {
+	converted, err := pdb.toTable(startKey)
+	if err != nil {
+		return
+	}
+	items, lastEvaluatedKey, cc, err = pdb.client.QueryIndexPage(ctx, indexName, pdb.field(field), value, limit, converted)
+	items = pdb.fromTableItems(items)
+	lastEvaluatedKey = pdb.fromTable(lastEvaluatedKey)
+	return
+}
+
+func (pdb partitionKeyDB) ScanPage(ctx context.Context, field, value string, limit int64, startKey map[string]*dynamodb.AttributeValue) (items []map[string]*dynamodb.AttributeValue, lastEvaluatedKey map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error) {
+	converted, err := pdb.toTable(startKey)
+	if err != nil {
+		return
+	}
+	items, lastEvaluatedKey, cc, err = pdb.client.ScanPage(ctx, pdb.field(field), value, limit, converted)
+	items = pdb.fromTableItems(items)
+	lastEvaluatedKey = pdb.fromTable(lastEvaluatedKey)
+	return
+}
+
+func (pdb partitionKeyDB) ScanSegment(ctx context.Context, segment, totalSegments, limit int64, startKey map[string]*dynamodb.AttributeValue) (items []map[string]*dynamodb.AttributeValue, lastEvaluatedKey map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error) {
+	converted, err := pdb.toTable(startKey)
+	if err != nil {
+		return
+	}
+	items, lastEvaluatedKey, cc, err = pdb.client.ScanSegment(ctx, segment, totalSegments, limit, converted)
+	items = pdb.fromTableItems(items)
+	lastEvaluatedKey = pdb.fromTable(lastEvaluatedKey)
+	return
+}
+
+func (pdb partitionKeyDB) QueryPage(ctx context.Context, idField, idValue, rangeField, rangePrefix string, limit int64, startKey map[string]*dynamodb.AttributeValue) (items []map[string]*dynamodb.AttributeValue, lastEvaluatedKey map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error) {
+	converted, err := pdb.toTable(startKey)
+	if err != nil {
+		return
+	}
+	items, lastEvaluatedKey, cc, err = pdb.client.QueryPage(ctx, pdb.field(idField), idValue, rangeField, rangePrefix, limit, converted)
+	items = pdb.fromTableItems(items)
+	lastEvaluatedKey = pdb.fromTable(lastEvaluatedKey)
+	return
+}
+
+// DescribeTable describes the table as if the partition key were the string "id" attribute, so that
+// ValidateSchema checks the table against the partition key.
+func (pdb partitionKeyDB) DescribeTable(ctx context.Context) (td db.TableDescription, err error) {
+	if td, err = pdb.client.DescribeTable(ctx); err != nil {
+		return
+	}
+	if td.PartitionKeyName == pdb.name && td.PartitionKeyType == pdb.attributeType {
+		td.PartitionKeyName, td.PartitionKeyType = fieldID, dynamodb.ScalarAttributeTypeS
+	}
+	indexes := make([]db.TableIndex, len(td.Indexes))
+	for i, index := range td.Indexes {
+		if index.SortKey == pdb.name {
+			index.SortKey = fieldID
+		}
+		indexes[i] = index
+	}
+	td.Indexes = indexes
+	return
+}
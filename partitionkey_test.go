@@ -0,0 +1,55 @@
+package pregel
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/a-h/pregel/db/memory"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+func TestStoreWithPartitionKey(t *testing.T) {
+	table := memory.New()
+	table.PartitionKey = "pk"
+	table.PartitionKeyType = "N"
+	s, err := NewStoreWithClient(table).WithPartitionKey("pk", "N")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err = s.Put(NewNode("1").WithChildren(NewEdge("2")), NewNode("2")); err != nil {
+		t.Fatalf("unexpected error putting nodes: %v", err)
+	}
+	for _, itm := range table.Items() {
+		if pk := itm["pk"]; pk == nil || pk.N == nil {
+			t.Errorf("expected a numeric partition key, got %v", format([]map[string]*dynamodb.AttributeValue{itm}))
+		}
+		if _, ok := itm[fieldID]; ok {
+			t.Errorf("expected the id attribute not to be written")
+		}
+	}
+
+	n, ok, err := s.Get("1")
+	if err != nil || !ok {
+		t.Fatalf("expected node 1 to be found, got %v, %v", ok, err)
+	}
+	if expected := NewNode("1").WithChildren(NewEdge("2")); !reflect.DeepEqual(n, expected) {
+		t.Errorf("expected %+v, got %+v", expected, n)
+	}
+	ids, _, err := s.ListNodes(0, "")
+	if err != nil {
+		t.Fatalf("unexpected error listing nodes: %v", err)
+	}
+	if expected := []string{"1", "2"}; !reflect.DeepEqual(ids, expected) {
+		t.Errorf("expected %v, got %v", expected, ids)
+	}
+	if err = s.ValidateSchema(context.Background()); err != nil {
+		t.Errorf("expected the schema to be valid, got %v", err)
+	}
+	if err = s.Put(NewNode("a")); err != (InvalidPartitionKeyError{ID: "a"}) {
+		t.Errorf("expected an InvalidPartitionKeyError, got %v", err)
+	}
+	if _, err = NewStoreWithClient(table).WithPartitionKey("pk", "BOOL"); err == nil {
+		t.Errorf("expected an error for an invalid partition key type")
+	}
+}
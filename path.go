@@ -0,0 +1,76 @@
+package pregel
+
+// Path is a route through the graph. Edges[i] is the edge followed from Nodes[i] to Nodes[i+1], so
+// there's one less edge than there are nodes.
+type Path struct {
+	Nodes []Node
+	Edges []*Edge
+}
+
+// pathStep records how a node was reached during a search for a path.
+type pathStep struct {
+	from string
+	edge *Edge
+}
+
+// ShortestPath finds the path with the fewest edges from one node to another, following the edges
+// selected by the options, e.g. DirectionBoth to ignore the direction of edges. The graph is searched
+// breadth first, up to maxDepth edges away from the first node, and the nodes at each level are
+// retrieved in parallel with GetMany. If there's no path within maxDepth, ok is false. If the first
+// node doesn't exist, ErrNodeNotFound is returned.
+func (s *Store) ShortestPath(from, to string, maxDepth int, opts TraversalOptions) (p Path, ok bool, err error) {
+	if from == "" || to == "" {
+		err = ErrMissingNodeID
+		return
+	}
+	if maxDepth < 0 {
+		err = ErrInvalidDepth
+		return
+	}
+	nodes := make(map[string]Node)
+	steps := map[string]pathStep{from: {}}
+	frontier := []string{from}
+	for level := 0; len(frontier) > 0; level++ {
+		var levelNodes []Node
+		levelNodes, err = s.GetMany(frontier...)
+		if err != nil {
+			return
+		}
+		if level == 0 && len(levelNodes) == 0 {
+			err = ErrNodeNotFound
+			return
+		}
+		var next []string
+		for _, n := range levelNodes {
+			nodes[n.ID] = n
+			if n.ID == to {
+				return pathTo(to, nodes, steps), true, nil
+			}
+			if level >= maxDepth {
+				continue
+			}
+			for _, e := range opts.edges(n) {
+				if _, seen := steps[e.ID]; seen {
+					continue
+				}
+				steps[e.ID] = pathStep{from: n.ID, edge: e}
+				next = append(next, e.ID)
+			}
+		}
+		frontier = next
+	}
+	return
+}
+
+// pathTo follows the steps back from the node to the start of the search.
+func pathTo(id string, nodes map[string]Node, steps map[string]pathStep) (p Path) {
+	for {
+		p.Nodes = append([]Node{nodes[id]}, p.Nodes...)
+		step := steps[id]
+		if step.edge == nil {
+			return
+		}
+		p.Edges = append([]*Edge{step.edge}, p.Edges...)
+		id = step.from
+	}
+}
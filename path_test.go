@@ -0,0 +1,144 @@
+package pregel
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/a-h/pregel/db"
+	"github.com/a-h/pregel/rangefield"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+func TestStoreShortestPath(t *testing.T) {
+	// root has children a and b. a has a child a1, which has a child c. b is linked to c with the
+	// label "friend". d links to root. missing isn't stored.
+	records := make(map[string][]map[string]*dynamodb.AttributeValue)
+	addEdge := func(parent, label, child string) {
+		records[parent] = append(records[parent], getID(parent, rangefield.Child{Child: child, Label: label}))
+		records[child] = append(records[child], getID(child, rangefield.Parent{Parent: parent, Label: label}))
+	}
+	for _, id := range []string{"root", "a", "b", "a1", "c", "d"} {
+		records[id] = append(records[id], getID(id, rangefield.Node{}))
+	}
+	addEdge("root", "", "a")
+	addEdge("root", "", "b")
+	addEdge("a", "", "a1")
+	addEdge("a1", "", "c")
+	addEdge("b", "friend", "c")
+	addEdge("d", "", "root")
+	addEdge("c", "", "missing")
+
+	tests := []struct {
+		name          string
+		from, to      string
+		maxDepth      int
+		opts          TraversalOptions
+		expectedNodes []string
+		expectedEdges []string
+		expectedOK    bool
+		expectedErr   error
+	}{
+		{
+			name:        "Missing node IDs result in an error",
+			from:        "root",
+			expectedErr: ErrMissingNodeID,
+		},
+		{
+			name:        "Negative depths result in an error",
+			from:        "root",
+			to:          "c",
+			maxDepth:    -1,
+			expectedErr: ErrInvalidDepth,
+		},
+		{
+			name:        "Missing start nodes result in an error",
+			from:        "missing",
+			to:          "c",
+			expectedErr: ErrNodeNotFound,
+		},
+		{
+			name:          "The path from a node to itself is the node",
+			from:          "root",
+			to:            "root",
+			expectedNodes: []string{"root"},
+			expectedOK:    true,
+		},
+		{
+			name:          "The shortest path is returned",
+			from:          "root",
+			to:            "c",
+			maxDepth:      10,
+			expectedNodes: []string{"root", "b", "c"},
+			expectedEdges: []string{"b", "c"},
+			expectedOK:    true,
+		},
+		{
+			name:          "Only edges with the label are followed",
+			from:          "root",
+			to:            "c",
+			maxDepth:      10,
+			opts:          TraversalOptions{Label: "friend"},
+			expectedNodes: nil,
+		},
+		{
+			name:     "Paths longer than the maximum depth aren't found",
+			from:     "root",
+			to:       "c",
+			maxDepth: 1,
+		},
+		{
+			name:     "Edges are followed in their direction",
+			from:     "root",
+			to:       "d",
+			maxDepth: 10,
+		},
+		{
+			name:          "Parents can be followed",
+			from:          "root",
+			to:            "d",
+			maxDepth:      10,
+			opts:          TraversalOptions{Direction: DirectionParents},
+			expectedNodes: []string{"root", "d"},
+			expectedEdges: []string{"d"},
+			expectedOK:    true,
+		},
+		{
+			name:     "Edges to nodes which don't exist aren't paths",
+			from:     "root",
+			to:       "missing",
+			maxDepth: 10,
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			client := newdynamoDBClient()
+			client.queryByIDer = func(idField, idValue string) ([]map[string]*dynamodb.AttributeValue, db.ConsumedCapacity, error) {
+				return records[idValue], db.ConsumedCapacity{}, nil
+			}
+			s := NewStoreWithClient(client)
+			p, ok, err := s.ShortestPath(test.from, test.to, test.maxDepth, test.opts)
+			if err != test.expectedErr {
+				t.Errorf("expected err %v, got %v", test.expectedErr, err)
+			}
+			if ok != test.expectedOK {
+				t.Errorf("expected ok %v, got %v", test.expectedOK, ok)
+			}
+			var actualNodes, actualEdges []string
+			for _, n := range p.Nodes {
+				actualNodes = append(actualNodes, n.ID)
+			}
+			for _, e := range p.Edges {
+				actualEdges = append(actualEdges, e.ID)
+			}
+			if !reflect.DeepEqual(actualNodes, test.expectedNodes) {
+				t.Errorf("expected nodes %v, got %v", test.expectedNodes, actualNodes)
+			}
+			if !reflect.DeepEqual(actualEdges, test.expectedEdges) {
+				t.Errorf("expected edges %v, got %v", test.expectedEdges, actualEdges)
+			}
+		})
+	}
+}
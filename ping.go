@@ -0,0 +1,22 @@
+package pregel
+
+import (
+	"context"
+
+	"github.com/a-h/pregel/rangefield"
+)
+
+// pingID is the ID of the node read by Ping. The node doesn't need to exist.
+const pingID = "_ping"
+
+// Ping checks that the table can be read, e.g. for a readiness probe. It reads a single record by its
+// key, so it costs at most one read capacity unit, and isn't subject to the low rate limits of
+// DescribeTable.
+func (s *Store) Ping(ctx context.Context) (err error) {
+	_, cc, err := s.Client.GetItem(ctx, getID(pingID, rangefield.Node{}))
+	if err != nil {
+		return
+	}
+	s.updateCapacityStats(cc)
+	return
+}
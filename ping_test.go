@@ -0,0 +1,42 @@
+package pregel
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/a-h/pregel/db"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+func TestStorePing(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		err      error
+		expected error
+	}{
+		{name: "healthy"},
+		{name: "unhealthy", err: errors.New("connection refused"), expected: errors.New("connection refused")},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			client := newdynamoDBClient()
+			var actualKey map[string]*dynamodb.AttributeValue
+			client.getItemer = func(key map[string]*dynamodb.AttributeValue) (map[string]*dynamodb.AttributeValue, db.ConsumedCapacity, error) {
+				actualKey = key
+				return nil, db.ConsumedCapacity{}, test.err
+			}
+			s := NewStoreWithClient(client)
+			err := s.Ping(context.Background())
+			if (err == nil) != (test.expected == nil) || (err != nil && err.Error() != test.expected.Error()) {
+				t.Errorf("expected error %v, got %v", test.expected, err)
+			}
+			if actualKey == nil || *actualKey[fieldID].S != pingID {
+				t.Errorf("expected a single key to be read, got %v", actualKey)
+			}
+		})
+	}
+}
@@ -0,0 +1,254 @@
+// Package pregeltest provides tests which check that an implementation of the database used by
+// pregel.Store behaves in the same way as DynamoDB, so that custom backends stay compatible with the
+// Store as it changes.
+//
+//	func TestBackend(t *testing.T) {
+//		pregeltest.RunDB(t, func(t *testing.T) pregel.DB {
+//			return mybackend.New()
+//		})
+//	}
+package pregeltest
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/a-h/pregel"
+)
+
+// NodeData is the data type attached to nodes by the tests.
+type NodeData struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// EdgeData is the data type attached to edges by the tests.
+type EdgeData struct {
+	Weight float64 `json:"weight"`
+}
+
+// RunDB runs the tests against Stores which use the databases returned by newDB. newDB is called
+// once for each test, and must return an empty database.
+func RunDB(t *testing.T, newDB func(t *testing.T) pregel.DB) {
+	Run(t, func(t *testing.T) *pregel.Store {
+		return pregel.NewStoreWithClient(newDB(t))
+	})
+}
+
+// Run runs the tests against the Stores returned by newStore. newStore is called once for each test,
+// and must return a Store with an empty graph. The NodeData and EdgeData types are registered by the
+// tests.
+func Run(t *testing.T, newStore func(t *testing.T) *pregel.Store) {
+	tests := []struct {
+		name string
+		test func(t *testing.T, s *pregel.Store)
+	}{
+		{name: "Nodes round trip", test: testNodesRoundTrip},
+		{name: "Missing nodes aren't found", test: testMissingNodes},
+		{name: "Deleted nodes and their edges aren't found", test: testDelete},
+		{name: "Edge data round trips", test: testEdgeDataRoundTrips},
+		{name: "Edges with similar IDs are kept separate", test: testRangeKeys},
+		{name: "Unicode IDs round trip", test: testUnicodeIDs},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			s := newStore(t)
+			s.RegisterDataType(func() interface{} { return &NodeData{} })
+			s.RegisterDataType(func() interface{} { return &EdgeData{} })
+			test.test(t, s)
+		})
+	}
+}
+
+func testNodesRoundTrip(t *testing.T, s *pregel.Store) {
+	if err := s.Put(pregel.NewNode("a").WithData(NodeData{Name: "A", Count: 1})); err != nil {
+		t.Fatalf("unexpected error putting a: %v", err)
+	}
+	n, ok, err := s.Get("a")
+	if err != nil || !ok {
+		t.Fatalf("expected a to be found, got %v, %v", ok, err)
+	}
+	if n.ID != "a" {
+		t.Errorf("expected ID a, got %q", n.ID)
+	}
+	if d, ok := n.Data["NodeData"].(*NodeData); !ok || *d != (NodeData{Name: "A", Count: 1}) {
+		t.Errorf("expected the node's data to be read, got %+v", n.Data["NodeData"])
+	}
+
+	if err = s.PutNodeData("a", pregel.NewData(NodeData{Name: "B", Count: 2})); err != nil {
+		t.Fatalf("unexpected error putting node data: %v", err)
+	}
+	n, _, err = s.Get("a")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if d, ok := n.Data["NodeData"].(*NodeData); !ok || *d != (NodeData{Name: "B", Count: 2}) {
+		t.Errorf("expected the node's data to be replaced, got %+v", n.Data["NodeData"])
+	}
+
+	if err = s.Create(pregel.NewNode("a")); err != pregel.ErrAlreadyExists {
+		t.Errorf("expected creating a again to return ErrAlreadyExists, got %v", err)
+	}
+	if ok, err = s.Exists("a"); err != nil || !ok {
+		t.Errorf("expected a to exist, got %v, %v", ok, err)
+	}
+}
+
+func testMissingNodes(t *testing.T, s *pregel.Store) {
+	if _, ok, err := s.Get("missing"); err != nil || ok {
+		t.Errorf("expected the node not to be found, got %v, %v", ok, err)
+	}
+	if ok, err := s.Exists("missing"); err != nil || ok {
+		t.Errorf("expected the node not to exist, got %v, %v", ok, err)
+	}
+	if _, ok, err := s.GetEdge("missing", "other"); err != nil || ok {
+		t.Errorf("expected the edge not to be found, got %v, %v", ok, err)
+	}
+}
+
+func testDelete(t *testing.T, s *pregel.Store) {
+	if err := s.Put(pregel.NewNode("a"), pregel.NewNode("b")); err != nil {
+		t.Fatalf("unexpected error putting nodes: %v", err)
+	}
+	if err := s.PutEdges("a", pregel.NewEdge("b")); err != nil {
+		t.Fatalf("unexpected error putting edges: %v", err)
+	}
+	if err := s.Delete("b"); err != nil {
+		t.Fatalf("unexpected error deleting b: %v", err)
+	}
+	if _, ok, err := s.Get("b"); err != nil || ok {
+		t.Errorf("expected b not to be found, got %v, %v", ok, err)
+	}
+	n, ok, err := s.Get("a")
+	if err != nil || !ok {
+		t.Fatalf("expected a to be found, got %v, %v", ok, err)
+	}
+	if len(n.Children) != 0 {
+		t.Errorf("expected the edge to b to be deleted, got %v", edgeIDs(n.Children))
+	}
+}
+
+func testEdgeDataRoundTrips(t *testing.T, s *pregel.Store) {
+	if err := s.Put(pregel.NewNode("parent"), pregel.NewNode("child")); err != nil {
+		t.Fatalf("unexpected error putting nodes: %v", err)
+	}
+	if err := s.PutEdges("parent", pregel.NewEdge("child").WithData(EdgeData{Weight: 0.5})); err != nil {
+		t.Fatalf("unexpected error putting edges: %v", err)
+	}
+	parent, _, err := s.Get("parent")
+	if err != nil {
+		t.Fatalf("unexpected error getting the parent: %v", err)
+	}
+	if e := parent.GetChild("child"); e == nil {
+		t.Errorf("expected the parent to have the child, got %v", edgeIDs(parent.Children))
+	} else if d, ok := e.Data["EdgeData"].(*EdgeData); !ok || d.Weight != 0.5 {
+		t.Errorf("expected the child edge's data to be read, got %+v", e.Data["EdgeData"])
+	}
+	child, _, err := s.Get("child")
+	if err != nil {
+		t.Fatalf("unexpected error getting the child: %v", err)
+	}
+	if e := child.GetParent("parent"); e == nil {
+		t.Errorf("expected the child to have the parent, got %v", edgeIDs(child.Parents))
+	} else if d, ok := e.Data["EdgeData"].(*EdgeData); !ok || d.Weight != 0.5 {
+		t.Errorf("expected the parent edge's data to be read, got %+v", e.Data["EdgeData"])
+	}
+
+	if err = s.PutEdgeData("parent", "child", pregel.NewData(EdgeData{Weight: 2})); err != nil {
+		t.Fatalf("unexpected error putting edge data: %v", err)
+	}
+	e, ok, err := s.GetEdge("parent", "child")
+	if err != nil || !ok {
+		t.Fatalf("expected the edge to be found, got %v, %v", ok, err)
+	}
+	if d, ok := e.Data["EdgeData"].(*EdgeData); !ok || d.Weight != 2 {
+		t.Errorf("expected the edge's data to be replaced, got %+v", e.Data["EdgeData"])
+	}
+}
+
+// testRangeKeys checks that edges are read using the whole of the range key, and not just its prefix.
+func testRangeKeys(t *testing.T, s *pregel.Store) {
+	children := []string{"b", "bc", "b/data/NodeData", "b%2F", "node", "child", "parent", "data"}
+	nodes := []pregel.Node{pregel.NewNode("a")}
+	var edges []*pregel.Edge
+	for _, id := range children {
+		nodes = append(nodes, pregel.NewNode(id))
+		edges = append(edges, pregel.NewEdge(id))
+	}
+	if err := s.Put(nodes...); err != nil {
+		t.Fatalf("unexpected error putting nodes: %v", err)
+	}
+	if err := s.PutEdges("a", edges...); err != nil {
+		t.Fatalf("unexpected error putting edges: %v", err)
+	}
+	n, _, err := s.Get("a")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := append([]string{}, children...)
+	sort.Strings(expected)
+	if actual := edgeIDs(n.Children); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected children %v, got %v", expected, actual)
+	}
+	if len(n.Data) != 0 {
+		t.Errorf("expected the node to have no data, got %+v", n.Data)
+	}
+
+	if err = s.DeleteEdge("a", "b"); err != nil {
+		t.Fatalf("unexpected error deleting the edge: %v", err)
+	}
+	n, _, err = s.Get("a")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected = expected[1:]
+	if actual := edgeIDs(n.Children); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected only b to be deleted, got %v", actual)
+	}
+	if _, ok, _ := s.GetEdge("a", "bc"); !ok {
+		t.Errorf("expected the edge to bc to be found")
+	}
+}
+
+func testUnicodeIDs(t *testing.T, s *pregel.Store) {
+	ids := []string{"中文", "émoji 🎉", "with space", "ß", "ﬁ"}
+	for _, id := range ids {
+		if err := s.Put(pregel.NewNode(id).WithData(NodeData{Name: id})); err != nil {
+			t.Fatalf("unexpected error putting %q: %v", id, err)
+		}
+	}
+	if err := s.PutEdges(ids[0], pregel.NewEdge(ids[1])); err != nil {
+		t.Fatalf("unexpected error putting edges: %v", err)
+	}
+	for _, id := range ids {
+		n, ok, err := s.Get(id)
+		if err != nil || !ok {
+			t.Errorf("expected %q to be found, got %v, %v", id, ok, err)
+			continue
+		}
+		if n.ID != id {
+			t.Errorf("expected ID %q, got %q", id, n.ID)
+		}
+		if d, ok := n.Data["NodeData"].(*NodeData); !ok || d.Name != id {
+			t.Errorf("expected the data of %q to be read, got %+v", id, n.Data["NodeData"])
+		}
+	}
+	n, _, err := s.Get(ids[0])
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if actual := edgeIDs(n.Children); !reflect.DeepEqual(actual, ids[1:2]) {
+		t.Errorf("expected children %v, got %v", ids[1:2], actual)
+	}
+}
+
+func edgeIDs(edges []*pregel.Edge) (ids []string) {
+	ids = []string{}
+	for _, e := range edges {
+		ids = append(ids, e.ID)
+	}
+	sort.Strings(ids)
+	return
+}
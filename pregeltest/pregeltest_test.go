@@ -0,0 +1,31 @@
+package pregeltest
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/a-h/pregel"
+	"github.com/a-h/pregel/db/memory"
+)
+
+func TestMemoryDB(t *testing.T) {
+	RunDB(t, func(t *testing.T) pregel.DB {
+		return memory.New()
+	})
+}
+
+func TestFileStore(t *testing.T) {
+	Run(t, func(t *testing.T) *pregel.Store {
+		dir, err := ioutil.TempDir("", "pregeltest")
+		if err != nil {
+			t.Fatalf("failed to create directory: %v", err)
+		}
+		t.Cleanup(func() { os.RemoveAll(dir) })
+		s, err := pregel.NewFileStore(dir)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return s
+	})
+}
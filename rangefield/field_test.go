@@ -41,6 +41,10 @@ func TestRoundTrip(t *testing.T) {
 			input:   NodeData{DataType: "nodedatatype"},
 			encoded: "node/data/nodedatatype",
 		},
+		{
+			input:   NodeLabel{Label: "device"},
+			encoded: "node/label/device",
+		},
 		{
 			input:   Child{Child: "childid"},
 			encoded: "child/childid",
@@ -53,10 +57,26 @@ func TestRoundTrip(t *testing.T) {
 			input:   ChildData{Child: "childid", DataType: "childdatatype"},
 			encoded: "child/childid/data/childdatatype",
 		},
+		{
+			input:   Child{Child: "childid", Label: "owns"},
+			encoded: "child/owns/childid",
+		},
+		{
+			input:   ChildData{Child: "childid", DataType: "childdatatype", Label: "owns"},
+			encoded: "child/owns/childid/data/childdatatype",
+		},
 		{
 			input:   Parent{Parent: "parentid"},
 			encoded: "parent/parentid",
 		},
+		{
+			input:   Parent{Parent: "parentid", Label: "owns"},
+			encoded: "parent/owns/parentid",
+		},
+		{
+			input:   ParentData{Parent: "parentid", DataType: "parentdatatype", Label: "owns"},
+			encoded: "parent/owns/parentid/data/parentdatatype",
+		},
 		{
 			input:   ParentData{Parent: "parentid", DataType: "parentdatatype"},
 			encoded: "parent/parentid/data/parentdatatype",
@@ -114,6 +134,12 @@ func TestRangeFieldDecode(t *testing.T) {
 			},
 			expectedOK: true,
 		},
+		{
+			name:       "node label becomes a NodeLabel",
+			input:      "node/label/device",
+			expected:   NodeLabel{Label: "device"},
+			expectedOK: true,
+		},
 		{
 			name:       "child becomes a Child",
 			input:      "child/childid",
@@ -126,6 +152,18 @@ func TestRangeFieldDecode(t *testing.T) {
 			expected:   ChildData{Child: "childid", DataType: "childdatatype"},
 			expectedOK: true,
 		},
+		{
+			name:       "labelled child becomes a Child with a label",
+			input:      "child/owns/childid",
+			expected:   Child{Child: "childid", Label: "owns"},
+			expectedOK: true,
+		},
+		{
+			name:       "labelled child with data becomes a ChildData with a label",
+			input:      "child/owns/childid/data/childdatatype",
+			expected:   ChildData{Child: "childid", DataType: "childdatatype", Label: "owns"},
+			expectedOK: true,
+		},
 		{
 			name:       "parent becomes a Parent",
 			input:      "parent/parentid",
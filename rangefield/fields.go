@@ -32,39 +32,95 @@ func decodeNodeField(parts []string) (f RangeField, ok bool) {
 			DataType: parts[1],
 		}, true
 	}
+	if len(parts) == 2 && parts[0] == "label" {
+		return NodeLabel{
+			Label: parts[1],
+		}, true
+	}
 	return
 }
 
+// Labelled edges have an extra segment for the label before the ID, e.g. child/label/id, so they
+// can be distinguished from unlabelled edges by the number of segments.
+
 func decodeChildField(parts []string) (f RangeField, ok bool) {
-	if len(parts) == 1 {
+	switch {
+	case len(parts) == 1:
 		return Child{
 			Child: parts[0],
 		}, true
-	}
-	if len(parts) == 3 && parts[1] == "data" {
+	case len(parts) == 2:
+		return Child{
+			Label: parts[0],
+			Child: parts[1],
+		}, true
+	case len(parts) == 3 && parts[1] == "data":
 		return ChildData{
 			Child:    parts[0],
 			DataType: parts[2],
 		}, true
+	case len(parts) == 4 && parts[2] == "data":
+		return ChildData{
+			Label:    parts[0],
+			Child:    parts[1],
+			DataType: parts[3],
+		}, true
 	}
 	return
 }
 
 func decodeParentField(parts []string) (f RangeField, ok bool) {
-	if len(parts) == 1 {
+	switch {
+	case len(parts) == 1:
 		return Parent{
 			Parent: parts[0],
 		}, true
-	}
-	if len(parts) == 3 && parts[1] == "data" {
+	case len(parts) == 2:
+		return Parent{
+			Label:  parts[0],
+			Parent: parts[1],
+		}, true
+	case len(parts) == 3 && parts[1] == "data":
 		return ParentData{
 			Parent:   parts[0],
 			DataType: parts[2],
 		}, true
+	case len(parts) == 4 && parts[2] == "data":
+		return ParentData{
+			Label:    parts[0],
+			Parent:   parts[1],
+			DataType: parts[3],
+		}, true
 	}
 	return
 }
 
+// Prefixes shared by the encoded range fields of each kind of record, for use in range key conditions.
+const (
+	NodePrefix   = "node"
+	ChildPrefix  = "child/"
+	ParentPrefix = "parent/"
+)
+
+// ChildLabelPrefix returns the prefix shared by the child records (and child data records) of
+// edges with the label. Unlabelled children whose ID is the same as the label also have the prefix.
+func ChildLabelPrefix(label string) string {
+	return encodeField("child", label) + "/"
+}
+
+// ChildIDPrefix returns the prefix shared by the child records (and child data records) of
+// unlabelled edges whose child ID begins with the prefix. Labelled edges whose label begins with the
+// prefix also have the prefix.
+func ChildIDPrefix(prefix string) string {
+	return encodeField("child", prefix)
+}
+
+// ParentLabelPrefix returns the prefix shared by the parent records (and parent data records) of
+// edges with the label. Unlabelled parents whose ID is the same as the label also have the prefix.
+func ParentLabelPrefix(label string) string {
+	return encodeField("parent", label) + "/"
+}
+
 // RangeField for a DynamoDB table.
 type RangeField interface {
 	Encode() string
@@ -88,13 +144,28 @@ func (k NodeData) Encode() string {
 	return encodeField("node", "data", k.DataType)
 }
 
+// NodeLabel is the range field for a Node's label record.
+type NodeLabel struct {
+	Label string
+}
+
+// Encode to the field to string.
+func (k NodeLabel) Encode() string {
+	return encodeField("node", "label", k.Label)
+}
+
 // Child is the range field for a Node's child record.
 type Child struct {
 	Child string
+	// Label of the edge, or empty for an unlabelled edge.
+	Label string
 }
 
 // Encode to the field to string.
 func (k Child) Encode() string {
+	if k.Label != "" {
+		return encodeField("child", k.Label, k.Child)
+	}
 	return encodeField("child", k.Child)
 }
 
@@ -102,20 +173,30 @@ func (k Child) Encode() string {
 type ChildData struct {
 	Child    string
 	DataType string
+	// Label of the edge, or empty for an unlabelled edge.
+	Label string
 }
 
 // Encode to the field to string.
 func (k ChildData) Encode() string {
+	if k.Label != "" {
+		return encodeField("child", k.Label, k.Child, "data", k.DataType)
+	}
 	return encodeField("child", k.Child, "data", k.DataType)
 }
 
 // Parent is the range field for a Node's Parent record.
 type Parent struct {
 	Parent string
+	// Label of the edge, or empty for an unlabelled edge.
+	Label string
 }
 
 // Encode to the field to string.
 func (k Parent) Encode() string {
+	if k.Label != "" {
+		return encodeField("parent", k.Label, k.Parent)
+	}
 	return encodeField("parent", k.Parent)
 }
 
@@ -123,10 +204,15 @@ func (k Parent) Encode() string {
 type ParentData struct {
 	Parent   string
 	DataType string
+	// Label of the edge, or empty for an unlabelled edge.
+	Label string
 }
 
 // Encode to the field to string.
 func (k ParentData) Encode() string {
+	if k.Label != "" {
+		return encodeField("parent", k.Label, k.Parent, "data", k.DataType)
+	}
 	return encodeField("parent", k.Parent, "data", k.DataType)
 }
 
@@ -1,30 +1,78 @@
 package pregel
 
 import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/a-h/pregel/db"
 	"github.com/a-h/pregel/rangefield"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
-	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
 )
 
 const (
 	fieldID             = "id"
 	fieldRange          = "rng"
 	fieldRecordDataType = "t"
+	fieldCreatedAt      = "_c"
+	fieldUpdatedAt      = "_u"
+	fieldDeleted        = "_d"
 )
 
+// LabelAttributeName is the name of the attribute which holds the label of node label records. To
+// query nodes by label, create a global secondary index with this attribute as its partition key
+// and the "id" attribute as its sort key, and set the Store's LabelIndex to the name of the index.
+const LabelAttributeName = "_l"
+
+// TTLAttributeName is the name of the attribute which holds the expiry time of records, as a number
+// of seconds since the Unix epoch. To have DynamoDB delete expired records, enable Time to Live on
+// the table using this attribute name.
+const TTLAttributeName = "_ttl"
+
+// ErrItemTooLarge is returned when a record would exceed DynamoDB's item size limit of db.MaxItemSize
+// bytes. Use errors.Is to check for it, since it's wrapped by an ItemTooLargeError.
+var ErrItemTooLarge = errors.New("record exceeds the DynamoDB item size limit")
+
+// ItemTooLargeError is returned when a record would exceed DynamoDB's item size limit. Nothing is
+// written. Large data can be reduced below the limit by enabling the Store's Compression.
+type ItemTooLargeError struct {
+	// ID and Range are the key of the record.
+	ID    string
+	Range string
+	// Size is the estimated size of the record in bytes.
+	Size int
+}
+
+func (err ItemTooLargeError) Error() string {
+	return fmt.Sprintf("record %s/%s is %d bytes, which exceeds the DynamoDB item size limit of %d bytes",
+		err.ID, err.Range, err.Size, db.MaxItemSize)
+}
+
+// Unwrap returns ErrItemTooLarge.
+func (err ItemTooLargeError) Unwrap() error {
+	return ErrItemTooLarge
+}
+
 func newNodeRecord(id string) (r map[string]*dynamodb.AttributeValue) {
 	return newRecord(id, rangefield.Node{})
 }
 
-type recordCreator func(from, to string, data Data) (r []map[string]*dynamodb.AttributeValue, err error)
+func newLabelRecord(id, label string) (r map[string]*dynamodb.AttributeValue) {
+	r = newRecord(id, rangefield.NodeLabel{Label: label})
+	r[LabelAttributeName] = &dynamodb.AttributeValue{S: aws.String(label)}
+	return
+}
+
+type recordCreator func(from, to, label string, data Data) (r []map[string]*dynamodb.AttributeValue, err error)
 
-func newChildRecord(parent, child string, data Data) (r []map[string]*dynamodb.AttributeValue, err error) {
-	r = append(r, newRecord(parent, rangefield.Child{Child: child}))
+func (s *Store) newChildRecord(parent, child, label string, data Data) (r []map[string]*dynamodb.AttributeValue, err error) {
+	r = append(r, newRecord(parent, rangefield.Child{Child: child, Label: label}))
 	for k, v := range data {
 		k := k
 		v := v
-		dr, dErr := newDataRecord(parent, rangefield.ChildData{Child: child, DataType: k}, k, v)
+		dr, dErr := s.newDataRecord(parent, rangefield.ChildData{Child: child, DataType: k, Label: label}, k, v)
 		if dErr != nil {
 			err = dErr
 			return
@@ -34,12 +82,12 @@ func newChildRecord(parent, child string, data Data) (r []map[string]*dynamodb.A
 	return
 }
 
-func newParentRecord(parent, child string, data Data) (r []map[string]*dynamodb.AttributeValue, err error) {
-	r = append(r, newRecord(child, rangefield.Parent{Parent: parent}))
+func (s *Store) newParentRecord(parent, child, label string, data Data) (r []map[string]*dynamodb.AttributeValue, err error) {
+	r = append(r, newRecord(child, rangefield.Parent{Parent: parent, Label: label}))
 	for k, v := range data {
 		k := k
 		v := v
-		dr, dErr := newDataRecord(child, rangefield.ParentData{Parent: parent, DataType: k}, k, v)
+		dr, dErr := s.newDataRecord(child, rangefield.ParentData{Parent: parent, DataType: k, Label: label}, k, v)
 		if dErr != nil {
 			err = dErr
 			return
@@ -56,13 +104,155 @@ func newRecord(id string, rangeKey rangefield.RangeField) (r map[string]*dynamod
 	return
 }
 
-func newDataRecord(id string, rangeKey rangefield.RangeField, key string, value interface{}) (r map[string]*dynamodb.AttributeValue, err error) {
-	r, err = dynamodbattribute.MarshalMap(value)
+// newDataRecord creates a record which holds the data, using the Encoding of its data type.
+func (s *Store) newDataRecord(id string, rangeKey rangefield.RangeField, key string, value interface{}) (r map[string]*dynamodb.AttributeValue, err error) {
+	r, err = s.encoding(key).Marshal(value)
 	if err != nil {
 		return
 	}
+	if r == nil {
+		r = make(map[string]*dynamodb.AttributeValue)
+	}
 	r[fieldID] = &dynamodb.AttributeValue{S: &id}
 	r[fieldRange] = &dynamodb.AttributeValue{S: aws.String(rangeKey.Encode())}
 	r[fieldRecordDataType] = &dynamodb.AttributeValue{S: aws.String(key)}
 	return
 }
+
+// stampRecords sets the creation and modification times of the records written for a node. The
+// creation time of records which belong to the node or one of its edges is taken from the node or
+// edge if it's set. Records which already exist keep their creation time, see keepCreationTimes.
+func stampRecords(n Node, now time.Time, records []map[string]*dynamodb.AttributeValue) {
+	for _, r := range records {
+		created := recordCreatedAt(n, r)
+		if created.IsZero() {
+			created = now
+		}
+		r[fieldCreatedAt] = newTimeAttribute(created)
+		r[fieldUpdatedAt] = newTimeAttribute(now)
+	}
+}
+
+func recordCreatedAt(n Node, r map[string]*dynamodb.AttributeValue) time.Time {
+	if isNodeRecord(r) {
+		return n.CreatedAt
+	}
+	if e := getRecordEdge(n, r); e != nil {
+		return e.CreatedAt
+	}
+	return time.Time{}
+}
+
+// setRecordTTLs sets the expiry time of the records written for a node. Records which belong to the
+// node expire with the node, while edge records expire with whichever of the node or the edge
+// expires first.
+func setRecordTTLs(n Node, records []map[string]*dynamodb.AttributeValue) {
+	for _, r := range records {
+		ttl := n.TTL
+		if !isNodeRecord(r) {
+			if e := getRecordEdge(n, r); e != nil {
+				ttl = earliest(ttl, e.TTL)
+			}
+		}
+		if !ttl.IsZero() {
+			r[TTLAttributeName] = &dynamodb.AttributeValue{N: aws.String(strconv.FormatInt(ttl.Unix(), 10))}
+		}
+	}
+}
+
+// getRecordTTL returns the expiry time of the record, or the zero time if it doesn't expire.
+func getRecordTTL(r map[string]*dynamodb.AttributeValue) (t time.Time) {
+	v, ok := r[TTLAttributeName]
+	if !ok || v.N == nil {
+		return
+	}
+	secs, err := strconv.ParseInt(*v.N, 10, 64)
+	if err != nil {
+		return
+	}
+	return time.Unix(secs, 0).UTC()
+}
+
+// isExpired returns true if the record has expired. DynamoDB doesn't delete expired records
+// immediately, so they need to be ignored when reading.
+func isExpired(r map[string]*dynamodb.AttributeValue, now time.Time) bool {
+	ttl := getRecordTTL(r)
+	return !ttl.IsZero() && !ttl.After(now)
+}
+
+// hasCreationTime returns true if the creation time of the record is read into a node or edge.
+func hasCreationTime(r map[string]*dynamodb.AttributeValue) bool {
+	switch getRecordRangeField(r).(type) {
+	case rangefield.Node, rangefield.Child, rangefield.Parent:
+		return true
+	}
+	return false
+}
+
+func isNodeRecord(r map[string]*dynamodb.AttributeValue) bool {
+	switch getRecordRangeField(r).(type) {
+	case rangefield.Node, rangefield.NodeData, rangefield.NodeLabel:
+		return true
+	}
+	return false
+}
+
+// getRecordEdge returns the edge of the node that an edge record was written for.
+func getRecordEdge(n Node, r map[string]*dynamodb.AttributeValue) *Edge {
+	id := *r[fieldID].S
+	var parent, child, label string
+	switch rf := getRecordRangeField(r).(type) {
+	case rangefield.Child:
+		parent, child, label = id, rf.Child, rf.Label
+	case rangefield.ChildData:
+		parent, child, label = id, rf.Child, rf.Label
+	case rangefield.Parent:
+		parent, child, label = rf.Parent, id, rf.Label
+	case rangefield.ParentData:
+		parent, child, label = rf.Parent, id, rf.Label
+	default:
+		return nil
+	}
+	if parent == n.ID {
+		return n.GetLabelledChild(child, label)
+	}
+	if child == n.ID {
+		return n.GetLabelledParent(parent, label)
+	}
+	return nil
+}
+
+func getRecordRangeField(r map[string]*dynamodb.AttributeValue) rangefield.RangeField {
+	rf, _ := decodeRangeField(r)
+	return rf
+}
+
+func newTimeAttribute(t time.Time) *dynamodb.AttributeValue {
+	return &dynamodb.AttributeValue{S: aws.String(t.UTC().Format(time.RFC3339Nano))}
+}
+
+// getTimeAttribute returns the time stored in the field of the record, or the zero time if it's
+// not present.
+func getTimeAttribute(r map[string]*dynamodb.AttributeValue, field string) (t time.Time) {
+	v, ok := r[field]
+	if !ok || v.S == nil {
+		return
+	}
+	t, _ = time.Parse(time.RFC3339Nano, *v.S)
+	return
+}
+
+// earliest returns the earliest of two times, where the zero time means never.
+func earliest(a, b time.Time) time.Time {
+	if a.IsZero() || (!b.IsZero() && b.Before(a)) {
+		return b
+	}
+	return a
+}
+
+func latest(a, b time.Time) time.Time {
+	if b.After(a) {
+		return b
+	}
+	return a
+}
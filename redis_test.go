@@ -0,0 +1,285 @@
+package pregel
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/a-h/pregel/db"
+	"github.com/a-h/pregel/db/redis"
+)
+
+// fakeRedis implements the Redis commands used by the redis package, including WATCH, MULTI and
+// EXEC.
+type fakeRedis struct {
+	m        sync.Mutex
+	hashes   map[string]map[string][]byte
+	zsets    map[string]map[string]bool
+	versions map[string]int
+	// beforeExec is called before each transaction is executed, e.g. to simulate a conflicting write.
+	beforeExec func(r *fakeRedis)
+}
+
+func newFakeRedis() *fakeRedis {
+	return &fakeRedis{
+		hashes:   make(map[string]map[string][]byte),
+		zsets:    make(map[string]map[string]bool),
+		versions: make(map[string]int),
+	}
+}
+
+func (r *fakeRedis) dial(ctx context.Context) (redis.Conn, error) {
+	return &fakeRedisConn{r: r}, nil
+}
+
+type fakeRedisConn struct {
+	r       *fakeRedis
+	watched map[string]int
+	multi   bool
+	queue   [][]interface{}
+}
+
+func (c *fakeRedisConn) Close() error { return nil }
+
+func (c *fakeRedisConn) Do(command string, args ...interface{}) (reply interface{}, err error) {
+	switch command {
+	case "WATCH":
+		c.r.m.Lock()
+		defer c.r.m.Unlock()
+		c.watched = make(map[string]int)
+		for _, k := range args {
+			c.watched[text(k)] = c.r.versions[text(k)]
+		}
+		return "OK", nil
+	case "UNWATCH":
+		c.watched = nil
+		return "OK", nil
+	case "MULTI":
+		c.multi = true
+		return "OK", nil
+	case "DISCARD":
+		c.multi, c.queue, c.watched = false, nil, nil
+		return "OK", nil
+	case "EXEC":
+		if c.r.beforeExec != nil {
+			c.r.beforeExec(c.r)
+		}
+		c.r.m.Lock()
+		defer c.r.m.Unlock()
+		queue, watched := c.queue, c.watched
+		c.multi, c.queue, c.watched = false, nil, nil
+		for k, v := range watched {
+			if c.r.versions[k] != v {
+				return nil, nil
+			}
+		}
+		var replies []interface{}
+		for _, q := range queue {
+			reply, err := c.r.exec(q[0].(string), q[1:]...)
+			if err != nil {
+				return nil, err
+			}
+			replies = append(replies, reply)
+		}
+		return replies, nil
+	}
+	if c.multi {
+		c.queue = append(c.queue, append([]interface{}{command}, args...))
+		return "QUEUED", nil
+	}
+	c.r.m.Lock()
+	defer c.r.m.Unlock()
+	return c.r.exec(command, args...)
+}
+
+func text(v interface{}) string {
+	if b, ok := v.([]byte); ok {
+		return string(b)
+	}
+	return fmt.Sprint(v)
+}
+
+func (r *fakeRedis) exec(command string, args ...interface{}) (reply interface{}, err error) {
+	key := text(args[0])
+	switch command {
+	case "HGET":
+		if v, ok := r.hashes[key][text(args[1])]; ok {
+			return v, nil
+		}
+		return nil, nil
+	case "HMGET":
+		var values []interface{}
+		for _, f := range args[1:] {
+			if v, ok := r.hashes[key][text(f)]; ok {
+				values = append(values, v)
+			} else {
+				values = append(values, nil)
+			}
+		}
+		return values, nil
+	case "HSET":
+		if r.hashes[key] == nil {
+			r.hashes[key] = make(map[string][]byte)
+		}
+		r.hashes[key][text(args[1])] = []byte(text(args[2]))
+		r.versions[key]++
+		return int64(1), nil
+	case "HDEL":
+		delete(r.hashes[key], text(args[1]))
+		r.versions[key]++
+		return int64(1), nil
+	case "ZADD":
+		if r.zsets[key] == nil {
+			r.zsets[key] = make(map[string]bool)
+		}
+		r.zsets[key][text(args[2])] = true
+		r.versions[key]++
+		return int64(1), nil
+	case "ZREM":
+		delete(r.zsets[key], text(args[1]))
+		r.versions[key]++
+		return int64(1), nil
+	case "ZCARD":
+		return int64(len(r.zsets[key])), nil
+	case "ZRANGEBYLEX":
+		var members []string
+		for m := range r.zsets[key] {
+			if inLexRange(m, text(args[1]), text(args[2])) {
+				members = append(members, m)
+			}
+		}
+		sort.Strings(members)
+		if len(args) == 6 {
+			offset, _ := strconv.Atoi(text(args[4]))
+			count, _ := strconv.Atoi(text(args[5]))
+			members = members[offset:]
+			if count < len(members) {
+				members = members[:count]
+			}
+		}
+		var values []interface{}
+		for _, m := range members {
+			values = append(values, []byte(m))
+		}
+		return values, nil
+	}
+	return nil, fmt.Errorf("unsupported command %s", command)
+}
+
+func inLexRange(m, min, max string) bool {
+	switch {
+	case min == "-":
+	case strings.HasPrefix(min, "[") && m < min[1:]:
+		return false
+	case strings.HasPrefix(min, "(") && m <= min[1:]:
+		return false
+	}
+	switch {
+	case max == "+":
+	case strings.HasPrefix(max, "[") && m > max[1:]:
+		return false
+	case strings.HasPrefix(max, "(") && m >= max[1:]:
+		return false
+	}
+	return true
+}
+
+func TestStoreWithRedis(t *testing.T) {
+	r := newFakeRedis()
+	s := NewStoreWithClient(redis.New(r.dial, db.TableIndex{Name: "labels", PartitionKey: LabelAttributeName, SortKey: fieldID}))
+	s.LabelIndex = "labels"
+	s.NodeDataMode = NodeDataMustExist
+
+	for _, id := range []string{"a", "b", "c", "d", "e"} {
+		if err := s.Put(NewNode(id).WithLabels("letter")); err != nil {
+			t.Fatalf("unexpected error putting %q: %v", id, err)
+		}
+	}
+	if err := s.PutEdges("a", NewEdge("b").WithData(testEdgeData{EdgeDataField: 1})); err != nil {
+		t.Fatalf("unexpected error putting edges: %v", err)
+	}
+	if err := s.Create(NewNode("a")); err != ErrAlreadyExists {
+		t.Errorf("expected ErrAlreadyExists, got %v", err)
+	}
+	if err := s.PutNodeData("z", Data{"testNodeData": testNodeData{}}); err != ErrNodeNotFound {
+		t.Errorf("expected ErrNodeNotFound, got %v", err)
+	}
+
+	n, ok, err := s.Get("a")
+	if err != nil || !ok {
+		t.Fatalf("expected node a to be found, got %v, %v", ok, err)
+	}
+	if len(n.Children) != 1 || n.Children[0].ID != "b" {
+		t.Errorf("expected a to have child b, got %+v", n.Children)
+	}
+
+	t.Run("Nodes are listed in pages", func(t *testing.T) {
+		var ids []string
+		var cursor string
+		for {
+			page, next, err := s.ListNodes(2, cursor)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			ids = append(ids, page...)
+			if next == "" {
+				break
+			}
+			cursor = next
+		}
+		if expected := []string{"a", "b", "c", "d", "e"}; !reflect.DeepEqual(ids, expected) {
+			t.Errorf("expected %v, got %v", expected, ids)
+		}
+	})
+	t.Run("Labels are queried using the index", func(t *testing.T) {
+		ids, next, err := s.QueryByLabel("letter", 3, "")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if expected := []string{"a", "b", "c"}; !reflect.DeepEqual(ids, expected) || next != "c" {
+			t.Errorf("expected %v and cursor c, got %v and %q", expected, ids, next)
+		}
+		ids, _, err = s.QueryByLabel("letter", 3, next)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if expected := []string{"d", "e"}; !reflect.DeepEqual(ids, expected) {
+			t.Errorf("expected %v, got %v", expected, ids)
+		}
+	})
+	t.Run("Writes are retried when they conflict", func(t *testing.T) {
+		var conflicts int
+		r.beforeExec = func(r *fakeRedis) {
+			if conflicts < 2 {
+				conflicts++
+				r.m.Lock()
+				r.versions["pregel:items:f"]++
+				r.m.Unlock()
+			}
+		}
+		defer func() { r.beforeExec = nil }()
+		if err := s.Put(NewNode("f")); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if ok, _ := s.Exists("f"); !ok || conflicts != 2 {
+			t.Errorf("expected f to be written after %d conflicts, got %v after %d", 2, ok, conflicts)
+		}
+	})
+	t.Run("Deleted nodes aren't found", func(t *testing.T) {
+		if err := s.Delete("b"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, ok, _ := s.Get("b"); ok {
+			t.Errorf("expected b to be deleted")
+		}
+		n, _, _ := s.Get("a")
+		if len(n.Children) != 0 {
+			t.Errorf("expected the edge to b to be deleted, got %+v", n.Children)
+		}
+	})
+}
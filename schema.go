@@ -0,0 +1,92 @@
+package pregel
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/a-h/pregel/db"
+)
+
+// SchemaError is returned by ValidateSchema when the table doesn't match the schema used by the Store.
+type SchemaError struct {
+	// Problems describe each difference between the table and the expected schema.
+	Problems []string
+}
+
+func (err SchemaError) Error() string {
+	return "table schema is invalid: " + strings.Join(err.Problems, "; ")
+}
+
+// ValidateSchema checks that the table's keys, the global secondary indexes used by the Store's
+// LabelIndex and registered indexes, and Time to Live, match what the Store expects, e.g. at startup.
+// Time to Live is optional, since expired records are ignored when they're read, but if it's enabled
+// it must use TTLAttributeName. If the table doesn't match, a SchemaError listing the problems is
+// returned.
+func (s *Store) ValidateSchema(ctx context.Context) (err error) {
+	td, err := s.Client.DescribeTable(ctx)
+	if err != nil {
+		return
+	}
+	var problems []string
+	problemf := func(format string, a ...interface{}) {
+		problems = append(problems, fmt.Sprintf(format, a...))
+	}
+	if td.PartitionKeyName != fieldID || td.PartitionKeyType != "S" {
+		problemf("the partition key must be a string attribute named %q, but is %q of type %q",
+			fieldID, td.PartitionKeyName, td.PartitionKeyType)
+	}
+	if td.SortKeyName != fieldRange || td.SortKeyType != "S" {
+		problemf("the sort key must be a string attribute named %q, but is %q of type %q",
+			fieldRange, td.SortKeyName, td.SortKeyType)
+	}
+	indexes := make(map[string]db.TableIndex, len(td.Indexes))
+	for _, index := range td.Indexes {
+		indexes[index.Name] = index
+	}
+	checkIndex := func(name, purpose, partitionKey, sortKey string) {
+		index, ok := indexes[name]
+		if !ok {
+			problemf("the global secondary index %q used by %s is missing", name, purpose)
+			return
+		}
+		if index.PartitionKey != partitionKey {
+			problemf("the partition key of the global secondary index %q used by %s must be %q, but is %q",
+				name, purpose, partitionKey, index.PartitionKey)
+		}
+		if sortKey != "" && index.SortKey != sortKey {
+			problemf("the sort key of the global secondary index %q used by %s must be %q, but is %q",
+				name, purpose, sortKey, index.SortKey)
+		}
+	}
+	if s.LabelIndex != "" {
+		checkIndex(s.LabelIndex, "LabelIndex", LabelAttributeName, fieldID)
+	}
+	if s.RangeIndex != "" {
+		checkIndex(s.RangeIndex, "RangeIndex", fieldRange, fieldID)
+	}
+	if s.DataTypeIndex != "" {
+		checkIndex(s.DataTypeIndex, "DataTypeIndex", fieldRecordDataType, fieldID)
+	}
+	var keys []AttributeIndexKey
+	for k := range s.Indexes {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].DataType != keys[j].DataType {
+			return keys[i].DataType < keys[j].DataType
+		}
+		return keys[i].Field < keys[j].Field
+	})
+	for _, k := range keys {
+		checkIndex(s.Indexes[k], fmt.Sprintf("the %q field of the %q data type", k.Field, k.DataType), k.Field, "")
+	}
+	if td.TTLAttribute != "" && td.TTLAttribute != TTLAttributeName {
+		problemf("time to live must use the %q attribute, but uses %q", TTLAttributeName, td.TTLAttribute)
+	}
+	if len(problems) > 0 {
+		err = SchemaError{Problems: problems}
+	}
+	return
+}
@@ -0,0 +1,98 @@
+package pregel
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/a-h/pregel/db"
+)
+
+func TestStoreValidateSchema(t *testing.T) {
+	validTable := func() db.TableDescription {
+		return db.TableDescription{
+			Status:           "ACTIVE",
+			PartitionKeyName: "id",
+			PartitionKeyType: "S",
+			SortKeyName:      "rng",
+			SortKeyType:      "S",
+			Indexes: []db.TableIndex{
+				{Name: "labels", PartitionKey: "_l", PartitionKeyType: "S", SortKey: "id"},
+				{Name: "ssids", PartitionKey: "ssid", PartitionKeyType: "S"},
+			},
+			TTLAttribute: "_ttl",
+		}
+	}
+	tests := []struct {
+		name             string
+		table            func() db.TableDescription
+		describeErr      error
+		expectedProblems []string
+		expectedErr      error
+	}{
+		{
+			name:  "Tables which match the schema are valid",
+			table: validTable,
+		},
+		{
+			name: "Time to Live is optional",
+			table: func() (td db.TableDescription) {
+				td = validTable()
+				td.TTLAttribute = ""
+				return
+			},
+		},
+		{
+			name:        "Database errors are returned",
+			table:       validTable,
+			describeErr: errTestDatabaseFailure,
+			expectedErr: errTestDatabaseFailure,
+		},
+		{
+			name: "All of the problems are reported",
+			table: func() (td db.TableDescription) {
+				td = validTable()
+				td.SortKeyName = "sk"
+				td.Indexes = []db.TableIndex{
+					{Name: "labels", PartitionKey: "label", PartitionKeyType: "S", SortKey: "id"},
+				}
+				td.TTLAttribute = "expires"
+				return
+			},
+			expectedProblems: []string{
+				`the sort key must be a string attribute named "rng", but is "sk" of type "S"`,
+				`the partition key of the global secondary index "labels" used by LabelIndex must be "_l", but is "label"`,
+				`the global secondary index "ssids" used by the "ssid" field of the "router" data type is missing`,
+				`time to live must use the "_ttl" attribute, but uses "expires"`,
+			},
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			client := newdynamoDBClient()
+			client.tableDescriber = func(ctx context.Context) (db.TableDescription, error) {
+				return test.table(), test.describeErr
+			}
+			s := NewStoreWithClient(client)
+			s.LabelIndex = "labels"
+			s.RegisterIndex("router", "ssid", "ssids")
+			err := s.ValidateSchema(context.Background())
+			if test.expectedProblems != nil {
+				se, ok := err.(SchemaError)
+				if !ok {
+					t.Fatalf("expected a SchemaError, got %v", err)
+				}
+				if !reflect.DeepEqual(se.Problems, test.expectedProblems) {
+					t.Errorf("expected problems:\n%q\ngot:\n%q", test.expectedProblems, se.Problems)
+				}
+				return
+			}
+			if err != test.expectedErr {
+				t.Errorf("expected err %v, got %v", test.expectedErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,307 @@
+package pregel
+
+import (
+	"context"
+	"errors"
+	"hash/fnv"
+	"strconv"
+
+	"github.com/a-h/pregel/db"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+// ErrCrossShardTransaction is returned when a transaction contains records which belong to nodes in
+// different shards, since a transaction can only be written to a single shard. For example, using
+// TransactionalEdges or PutTransaction with nodes in different shards returns this error.
+var ErrCrossShardTransaction = errors.New("cannot write a transaction across shards")
+
+// fieldShard is added to the last evaluated key of pages which end at the last record of a shard,
+// to continue paging from the start of the next shard.
+const fieldShard = "_shard"
+
+// NewShardedStore creates a store which spreads the graph across multiple DynamoDB tables, to
+// exceed the throughput of a single table. See NewStoreWithShards.
+func NewShardedStore(region string, tableNames ...string) (store *Store, err error) {
+	sess, err := session.NewSession(&aws.Config{
+		Region: aws.String(region),
+	})
+	if err != nil {
+		return nil, err
+	}
+	shards := make([]DB, len(tableNames))
+	for i, tableName := range tableNames {
+		shards[i] = db.NewWithSession(sess, tableName)
+	}
+	store = NewStoreWithShards(shards...)
+	store.S3 = s3.New(sess)
+	return
+}
+
+// NewStoreWithShards creates a store which spreads the graph across multiple shards, e.g. tables.
+// All of the records of a node are written to the shard chosen by a hash of its ID, so the number
+// and order of the shards must not change once nodes have been written. Queries of indexes and scans
+// read every shard. At least one shard is required, and each shard must have the same schema,
+// including indexes.
+func NewStoreWithShards(shards ...DB) *Store {
+	return NewStoreWithClient(newShardedDB(shards))
+}
+
+func newShardedDB(shards []DB) shardedDB {
+	return shardedDB{
+		shards: shards,
+	}
+}
+
+// shardedDB routes each request to the shard which holds the records of the node, or to every shard
+// for requests which aren't limited to a node.
+type shardedDB struct {
+	shards []DB
+}
+
+// shardOf returns the index of the shard that holds the records of a node.
+func (sdb shardedDB) shardOf(id string) int {
+	return shardIndex(id, len(sdb.shards))
+}
+
+// shardIndex returns which of n shards holds the records of a node.
+func shardIndex(id string, n int) int {
+	h := fnv.New32a()
+	h.Write([]byte(id))
+	return int(h.Sum32() % uint32(n))
+}
+
+func (sdb shardedDB) shardOfItem(item map[string]*dynamodb.AttributeValue) int {
+	if id, ok := item[fieldID]; ok && id.S != nil {
+		return sdb.shardOf(*id.S)
+	}
+	return 0
+}
+
+// group splits items by the shard they belong to.
+func (sdb shardedDB) group(items []map[string]*dynamodb.AttributeValue) (groups map[int][]map[string]*dynamodb.AttributeValue) {
+	groups = make(map[int][]map[string]*dynamodb.AttributeValue)
+	for _, itm := range items {
+		shard := sdb.shardOfItem(itm)
+		groups[shard] = append(groups[shard], itm)
+	}
+	return
+}
+
+// transactionShard returns the shard which holds all of the items, or ErrCrossShardTransaction.
+func (sdb shardedDB) transactionShard(items []map[string]*dynamodb.AttributeValue) (shard int, err error) {
+	shard = -1
+	for _, itm := range items {
+		if itm == nil {
+			continue
+		}
+		s := sdb.shardOfItem(itm)
+		if shard >= 0 && s != shard {
+			err = ErrCrossShardTransaction
+			return
+		}
+		shard = s
+	}
+	if shard < 0 {
+		shard = 0
+	}
+	return
+}
+
+func (sdb shardedDB) BatchDelete(ctx context.Context, keys []map[string]*dynamodb.AttributeValue) (cc db.ConsumedCapacity, err error) {
+	for shard, group := range sdb.group(keys) {
+		shardCC, dErr := sdb.shards[shard].BatchDelete(ctx, group)
+		cc = addCapacity(cc, shardCC)
+		if dErr != nil {
+			err = dErr
+			return
+		}
+	}
+	return
+}
+
+func (sdb shardedDB) BatchPut(ctx context.Context, items []map[string]*dynamodb.AttributeValue) (cc db.ConsumedCapacity, err error) {
+	for shard, group := range sdb.group(items) {
+		shardCC, pErr := sdb.shards[shard].BatchPut(ctx, group)
+		cc = addCapacity(cc, shardCC)
+		if pErr != nil {
+			err = pErr
+			return
+		}
+	}
+	return
+}
+
+func (sdb shardedDB) TryBatchPut(ctx context.Context, items []map[string]*dynamodb.AttributeValue) (unprocessed []map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error) {
+	for shard, group := range sdb.group(items) {
+		shardUnprocessed, shardCC, pErr := sdb.shards[shard].TryBatchPut(ctx, group)
+		cc = addCapacity(cc, shardCC)
+		if pErr != nil {
+			err = pErr
+			return
+		}
+		unprocessed = append(unprocessed, shardUnprocessed...)
+	}
+	return
+}
+
+func (sdb shardedDB) PutIfNotExists(ctx context.Context, idField string, item map[string]*dynamodb.AttributeValue) (db.ConsumedCapacity, error) {
+	return sdb.shards[sdb.shardOfItem(item)].PutIfNotExists(ctx, idField, item)
+}
+
+func (sdb shardedDB) TransactPut(ctx context.Context, items []map[string]*dynamodb.AttributeValue) (cc db.ConsumedCapacity, err error) {
+	shard, err := sdb.transactionShard(items)
+	if err != nil {
+		return
+	}
+	return sdb.shards[shard].TransactPut(ctx, items)
+}
+
+func (sdb shardedDB) TransactWrite(ctx context.Context, items []db.TransactWriteItem) (cc db.ConsumedCapacity, err error) {
+	keys := make([]map[string]*dynamodb.AttributeValue, 0, len(items)*3)
+	for _, item := range items {
+		keys = append(keys, item.Put, item.Delete, item.ConditionCheck)
+	}
+	shard, err := sdb.transactionShard(keys)
+	if err != nil {
+		return
+	}
+	return sdb.shards[shard].TransactWrite(ctx, items)
+}
+
+func (sdb shardedDB) TransactPutIfExists(ctx context.Context, key map[string]*dynamodb.AttributeValue, idField, excludedField string, items []map[string]*dynamodb.AttributeValue) (cc db.ConsumedCapacity, err error) {
+	shard, err := sdb.transactionShard(append([]map[string]*dynamodb.AttributeValue{key}, items...))
+	if err != nil {
+		return
+	}
+	return sdb.shards[shard].TransactPutIfExists(ctx, key, idField, excludedField, items)
+}
+
+func (sdb shardedDB) UpdateItem(ctx context.Context, key map[string]*dynamodb.AttributeValue, set map[string]*dynamodb.AttributeValue) (db.ConsumedCapacity, error) {
+	return sdb.shards[sdb.shardOfItem(key)].UpdateItem(ctx, key, set)
+}
+
+func (sdb shardedDB) GetItem(ctx context.Context, key map[string]*dynamodb.AttributeValue, attributes ...string) (item map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error) {
+	return sdb.shards[sdb.shardOfItem(key)].GetItem(ctx, key, attributes...)
+}
+
+func (sdb shardedDB) BatchGet(ctx context.Context, keys []map[string]*dynamodb.AttributeValue) (items []map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error) {
+	for shard, group := range sdb.group(keys) {
+		shardItems, shardCC, gErr := sdb.shards[shard].BatchGet(ctx, group)
+		cc = addCapacity(cc, shardCC)
+		if gErr != nil {
+			err = gErr
+			return
+		}
+		items = append(items, shardItems...)
+	}
+	return
+}
+
+func (sdb shardedDB) QueryByID(ctx context.Context, idField, idValue string, attributes ...string) (items []map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error) {
+	return sdb.shards[sdb.shardOf(idValue)].QueryByID(ctx, idField, idValue, attributes...)
+}
+
+func (sdb shardedDB) QueryByIDAndRangePrefix(ctx context.Context, idField, idValue, rangeField, rangePrefix string, attributes ...string) (items []map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error) {
+	return sdb.shards[sdb.shardOf(idValue)].QueryByIDAndRangePrefix(ctx, idField, idValue, rangeField, rangePrefix, attributes...)
+}
+
+// QueryIndex queries the index of every shard.
+func (sdb shardedDB) QueryIndex(ctx context.Context, indexName, field string, value interface{}) (items []map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error) {
+	for _, shard := range sdb.shards {
+		shardItems, shardCC, qErr := shard.QueryIndex(ctx, indexName, field, value)
+		cc = addCapacity(cc, shardCC)
+		if qErr != nil {
+			err = qErr
+			return
+		}
+		items = append(items, shardItems...)
+	}
+	return
+}
+
+// QueryIndexPage queries the index of each shard in turn.
+func (sdb shardedDB) QueryIndexPage(ctx context.Context, indexName, field string, value interface{}, limit int64, startKey map[string]*dynamodb.AttributeValue) (items []map[string]*dynamodb.AttributeValue, lastEvaluatedKey map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error) {
+	return sdb.pageAcrossShards(limit, startKey, func(shard DB, limit int64, startKey map[string]*dynamodb.AttributeValue) ([]map[string]*dynamodb.AttributeValue, map[string]*dynamodb.AttributeValue, db.ConsumedCapacity, error) {
+		return shard.QueryIndexPage(ctx, indexName, field, value, limit, startKey)
+	})
+}
+
+// ScanPage scans each shard in turn.
+func (sdb shardedDB) ScanPage(ctx context.Context, field, value string, limit int64, startKey map[string]*dynamodb.AttributeValue) (items []map[string]*dynamodb.AttributeValue, lastEvaluatedKey map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error) {
+	return sdb.pageAcrossShards(limit, startKey, func(shard DB, limit int64, startKey map[string]*dynamodb.AttributeValue) ([]map[string]*dynamodb.AttributeValue, map[string]*dynamodb.AttributeValue, db.ConsumedCapacity, error) {
+		return shard.ScanPage(ctx, field, value, limit, startKey)
+	})
+}
+
+// ScanSegment scans the segment of each shard in turn.
+func (sdb shardedDB) ScanSegment(ctx context.Context, segment, totalSegments, limit int64, startKey map[string]*dynamodb.AttributeValue) (items []map[string]*dynamodb.AttributeValue, lastEvaluatedKey map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error) {
+	return sdb.pageAcrossShards(limit, startKey, func(shard DB, limit int64, startKey map[string]*dynamodb.AttributeValue) ([]map[string]*dynamodb.AttributeValue, map[string]*dynamodb.AttributeValue, db.ConsumedCapacity, error) {
+		return shard.ScanSegment(ctx, segment, totalSegments, limit, startKey)
+	})
+}
+
+// pageAcrossShards gets a page of up to limit items, starting from the shard of the start key, and
+// moving on to the following shards until the limit is reached. A start key is either a key returned
+// by a shard, which contains the ID of a node in the shard, or a key which only contains the index of
+// the next shard to read, so that cursors made from node IDs can be used to continue paging.
+func (sdb shardedDB) pageAcrossShards(limit int64, startKey map[string]*dynamodb.AttributeValue, page func(shard DB, limit int64, startKey map[string]*dynamodb.AttributeValue) ([]map[string]*dynamodb.AttributeValue, map[string]*dynamodb.AttributeValue, db.ConsumedCapacity, error)) (items []map[string]*dynamodb.AttributeValue, lastEvaluatedKey map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error) {
+	var shard int
+	if startKey != nil {
+		shard = sdb.shardOfItem(startKey)
+		if s, ok := startKey[fieldShard]; ok && s.N != nil {
+			if shard, err = strconv.Atoi(*s.N); err != nil || shard < 0 || shard >= len(sdb.shards) {
+				err = ErrInvalidCursor
+				return
+			}
+			startKey = nil
+		}
+	}
+	for ; shard < len(sdb.shards); shard++ {
+		remaining := limit
+		if limit > 0 {
+			remaining = limit - int64(len(items))
+		}
+		shardItems, lek, shardCC, pErr := page(sdb.shards[shard], remaining, startKey)
+		cc = addCapacity(cc, shardCC)
+		if pErr != nil {
+			err = pErr
+			return
+		}
+		items = append(items, shardItems...)
+		if lek != nil {
+			lastEvaluatedKey = lek
+			return
+		}
+		startKey = nil
+		if limit > 0 && int64(len(items)) >= limit && shard+1 < len(sdb.shards) {
+			lastEvaluatedKey = map[string]*dynamodb.AttributeValue{
+				fieldShard: {N: aws.String(strconv.Itoa(shard + 1))},
+			}
+			return
+		}
+	}
+	return
+}
+
+func (sdb shardedDB) QueryPage(ctx context.Context, idField, idValue, rangeField, rangePrefix string, limit int64, startKey map[string]*dynamodb.AttributeValue) (items []map[string]*dynamodb.AttributeValue, lastEvaluatedKey map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error) {
+	return sdb.shards[sdb.shardOf(idValue)].QueryPage(ctx, idField, idValue, rangeField, rangePrefix, limit, startKey)
+}
+
+// DescribeTable describes the table of each shard, and returns the description of the first shard.
+// ValidateSchema therefore checks the first shard's schema, while the others are checked to exist.
+func (sdb shardedDB) DescribeTable(ctx context.Context) (td db.TableDescription, err error) {
+	for i, shard := range sdb.shards {
+		std, dErr := shard.DescribeTable(ctx)
+		if dErr != nil {
+			err = dErr
+			return
+		}
+		if i == 0 {
+			td = std
+		}
+	}
+	return
+}
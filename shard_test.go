@@ -0,0 +1,122 @@
+package pregel
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/a-h/pregel/db"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// newShardClient creates a client which stores records in memory, and scans node records in order
+// of their ID.
+func newShardClient() (client *dynamoDBClient, records *[]map[string]*dynamodb.AttributeValue) {
+	records = &[]map[string]*dynamodb.AttributeValue{}
+	client = newdynamoDBClient()
+	client.batchPutter = func(items []map[string]*dynamodb.AttributeValue) (db.ConsumedCapacity, error) {
+		*records = append(*records, items...)
+		return db.ConsumedCapacity{}, nil
+	}
+	client.queryByIDer = func(idField, idValue string) (items []map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error) {
+		for _, r := range *records {
+			if *r[fieldID].S == idValue {
+				// Reading a node modifies the records, so they're copied.
+				itm := make(map[string]*dynamodb.AttributeValue, len(r))
+				for k, v := range r {
+					itm[k] = v
+				}
+				items = append(items, itm)
+			}
+		}
+		return
+	}
+	client.scanPager = func(field, value string, limit int64, startKey map[string]*dynamodb.AttributeValue) (items []map[string]*dynamodb.AttributeValue, lastEvaluatedKey map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error) {
+		var nodes []map[string]*dynamodb.AttributeValue
+		for _, r := range *records {
+			if *r[field].S == value && (startKey == nil || *r[fieldID].S > *startKey[fieldID].S) {
+				nodes = append(nodes, r)
+			}
+		}
+		sort.Slice(nodes, func(i, j int) bool { return *nodes[i][fieldID].S < *nodes[j][fieldID].S })
+		if limit > 0 && int64(len(nodes)) > limit {
+			nodes = nodes[:limit]
+			lastEvaluatedKey = map[string]*dynamodb.AttributeValue{
+				fieldID:    nodes[limit-1][fieldID],
+				fieldRange: nodes[limit-1][fieldRange],
+			}
+		}
+		return nodes, lastEvaluatedKey, cc, nil
+	}
+	return
+}
+
+func TestStoreWithShards(t *testing.T) {
+	shardA, recordsA := newShardClient()
+	shardB, recordsB := newShardClient()
+	s := NewStoreWithShards(shardA, shardB)
+	sdb := s.Client.(shardedDB)
+
+	ids := []string{"a", "b", "c", "d", "e", "f", "g", "h"}
+	for _, id := range ids {
+		if err := s.Put(NewNode(id).WithData(testNodeData{ExtraAttribute: id})); err != nil {
+			t.Fatalf("unexpected error putting node %q: %v", id, err)
+		}
+	}
+	for shard, records := range []*[]map[string]*dynamodb.AttributeValue{recordsA, recordsB} {
+		if len(*records) == 0 {
+			t.Errorf("expected shard %d to contain records", shard)
+		}
+		for _, r := range *records {
+			if actual := sdb.shardOf(*r[fieldID].S); actual != shard {
+				t.Errorf("expected record %v to be written to shard %d, but it was written to shard %d", format([]map[string]*dynamodb.AttributeValue{r}), actual, shard)
+			}
+		}
+	}
+
+	for _, id := range ids {
+		n, ok, err := s.Get(id)
+		if err != nil {
+			t.Fatalf("unexpected error getting node %q: %v", id, err)
+		}
+		if !ok {
+			t.Fatalf("expected node %q to be found", id)
+		}
+		if !reflect.DeepEqual(n.Data["testNodeData"], &map[string]interface{}{"extra": id}) {
+			t.Errorf("expected the data of node %q, got %+v", id, n.Data)
+		}
+	}
+
+	t.Run("Pages are read from each shard in turn", func(t *testing.T) {
+		var listed []string
+		var cursor string
+		for i := 0; i < len(ids); i++ {
+			page, next, err := s.ListNodes(3, cursor)
+			if err != nil {
+				t.Fatalf("unexpected error listing nodes: %v", err)
+			}
+			listed = append(listed, page...)
+			if next == "" {
+				break
+			}
+			cursor = next
+		}
+		sort.Strings(listed)
+		if !reflect.DeepEqual(listed, ids) {
+			t.Errorf("expected every node to be listed once, got %v", listed)
+		}
+	})
+	t.Run("Transactions can't span shards", func(t *testing.T) {
+		var x, y string
+		for _, id := range ids {
+			if sdb.shardOf(id) == 0 {
+				x = id
+			} else {
+				y = id
+			}
+		}
+		if err := s.PutTransaction(NewNode(x), NewNode(y)); err != ErrCrossShardTransaction {
+			t.Errorf("expected ErrCrossShardTransaction, got %v", err)
+		}
+	})
+}
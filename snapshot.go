@@ -0,0 +1,299 @@
+package pregel
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/a-h/pregel/db"
+	"github.com/a-h/pregel/db/memory"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// ErrReadOnly is returned when writing to a store created by NewSnapshotStore.
+var ErrReadOnly = errors.New("cannot write to a read-only snapshot")
+
+// DefaultSnapshotPartitions is the number of partitions written by SnapshotToS3 if the number of
+// partitions isn't set.
+const DefaultSnapshotPartitions = 16
+
+// SnapshotManifest is the index of a snapshot made by SnapshotToS3. It's written after all of the
+// partitions, so a snapshot without a manifest is incomplete.
+type SnapshotManifest struct {
+	CreatedAt time.Time `json:"createdAt"`
+	// Records is the total number of records in the snapshot.
+	Records int `json:"records"`
+	// Partitions are the keys of the gzipped JSON Lines files containing the records, relative to
+	// the prefix. The records of each node are in the partition chosen by a hash of its ID.
+	Partitions []string `json:"partitions"`
+	// Table is the schema of the table, including its indexes.
+	Table db.TableDescription `json:"table"`
+}
+
+const snapshotManifestKey = "snapshot.json"
+
+// SnapshotToS3 copies every record in the table to the S3 bucket, under the prefix, so that it can
+// be read using NewSnapshotStore. The records are split into partitions by a hash of their node's
+// ID, so that reading a node only needs to read a single partition. Each partition is built in
+// memory before it's written, so large tables need more partitions. If partitions is zero,
+// DefaultSnapshotPartitions is used.
+func (s *Store) SnapshotToS3(ctx context.Context, bucket, prefix string, partitions int) (err error) {
+	if s.S3 == nil {
+		return ErrS3NotConfigured
+	}
+	if partitions < 1 {
+		partitions = DefaultSnapshotPartitions
+	}
+	m := SnapshotManifest{
+		CreatedAt: s.now(),
+	}
+	if m.Table, err = s.Client.DescribeTable(ctx); err != nil {
+		return
+	}
+	bufs := make([]*bytes.Buffer, partitions)
+	zws := make([]*gzip.Writer, partitions)
+	encs := make([]*json.Encoder, partitions)
+	for i := range bufs {
+		bufs[i] = new(bytes.Buffer)
+		zws[i] = gzip.NewWriter(bufs[i])
+		encs[i] = json.NewEncoder(zws[i])
+	}
+	var startKey map[string]*dynamodb.AttributeValue
+	for {
+		items, lastEvaluatedKey, cc, sErr := s.Client.ScanSegment(ctx, 0, 1, 0, startKey)
+		if sErr != nil {
+			err = sErr
+			return
+		}
+		s.updateCapacityStats(cc)
+		for _, itm := range items {
+			var p int
+			if id, ok := itm[fieldID]; ok && id.S != nil {
+				p = shardIndex(*id.S, partitions)
+			}
+			if err = encs[p].Encode(itm); err != nil {
+				return
+			}
+			m.Records++
+		}
+		if lastEvaluatedKey == nil {
+			break
+		}
+		startKey = lastEvaluatedKey
+	}
+	for i, zw := range zws {
+		if err = zw.Close(); err != nil {
+			return
+		}
+		file := fmt.Sprintf("records-%05d.jsonl.gz", i)
+		if err = s.putObject(ctx, bucket, path.Join(prefix, file), bufs[i].Bytes()); err != nil {
+			return
+		}
+		m.Partitions = append(m.Partitions, file)
+	}
+	manifest, err := json.Marshal(m)
+	if err != nil {
+		return
+	}
+	return s.putObject(ctx, bucket, path.Join(prefix, snapshotManifestKey), manifest)
+}
+
+// NewSnapshotStore creates a read-only store which reads a snapshot made by SnapshotToS3, e.g. for
+// analytics jobs which shouldn't use the capacity of the table. Each partition is read into memory
+// the first time that one of its nodes is read. Queries of indexes, and scans, read every
+// partition. Writes return ErrReadOnly.
+func NewSnapshotStore(ctx context.Context, client S3API, bucket, prefix string) (store *Store, err error) {
+	mr, err := getObject(ctx, client, bucket, path.Join(prefix, snapshotManifestKey))
+	if err != nil {
+		return
+	}
+	defer mr.Close()
+	var m SnapshotManifest
+	if err = json.NewDecoder(mr).Decode(&m); err != nil {
+		return
+	}
+	if len(m.Partitions) == 0 {
+		err = fmt.Errorf("snapshot s3://%s/%s has no partitions", bucket, prefix)
+		return
+	}
+	partitions := make([]DB, len(m.Partitions))
+	for i, file := range m.Partitions {
+		partitions[i] = &snapshotPartition{
+			s3:     client,
+			bucket: bucket,
+			key:    path.Join(prefix, file),
+			table:  m.Table,
+		}
+	}
+	store = NewStoreWithShards(partitions...)
+	store.S3 = client
+	return
+}
+
+// snapshotPartition serves the records of a partition of a snapshot, which are read from S3 into
+// memory the first time they're needed.
+type snapshotPartition struct {
+	s3     S3API
+	bucket string
+	key    string
+	table  db.TableDescription
+
+	m  sync.Mutex
+	db *memory.DB
+}
+
+// load returns the records of the partition, reading them if they haven't been read. If reading
+// fails, it's attempted again by the next call.
+func (sp *snapshotPartition) load(ctx context.Context) (mdb *memory.DB, err error) {
+	sp.m.Lock()
+	defer sp.m.Unlock()
+	if sp.db != nil {
+		return sp.db, nil
+	}
+	body, err := getObject(ctx, sp.s3, sp.bucket, sp.key)
+	if err != nil {
+		return
+	}
+	defer body.Close()
+	zr, err := gzip.NewReader(body)
+	if err != nil {
+		return
+	}
+	mdb = memory.New(sp.table.Indexes...)
+	mdb.PartitionKeyType = sp.table.PartitionKeyType
+	mdb.TTLAttribute = sp.table.TTLAttribute
+	var items []map[string]*dynamodb.AttributeValue
+	dec := json.NewDecoder(zr)
+	for {
+		var itm map[string]*dynamodb.AttributeValue
+		if err = dec.Decode(&itm); err == io.EOF {
+			break
+		}
+		if err != nil {
+			err = fmt.Errorf("failed to read s3://%s/%s: %v", sp.bucket, sp.key, err)
+			return
+		}
+		items = append(items, itm)
+	}
+	if _, err = mdb.BatchPut(ctx, items); err != nil {
+		return
+	}
+	sp.db = mdb
+	return
+}
+
+func (sp *snapshotPartition) BatchDelete(ctx context.Context, keys []map[string]*dynamodb.AttributeValue) (db.ConsumedCapacity, error) {
+	return db.ConsumedCapacity{}, ErrReadOnly
+}
+
+func (sp *snapshotPartition) BatchPut(ctx context.Context, items []map[string]*dynamodb.AttributeValue) (db.ConsumedCapacity, error) {
+	return db.ConsumedCapacity{}, ErrReadOnly
+}
+
+func (sp *snapshotPartition) TryBatchPut(ctx context.Context, items []map[string]*dynamodb.AttributeValue) (unprocessed []map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error) {
+	return items, db.ConsumedCapacity{}, ErrReadOnly
+}
+
+func (sp *snapshotPartition) PutIfNotExists(ctx context.Context, idField string, item map[string]*dynamodb.AttributeValue) (db.ConsumedCapacity, error) {
+	return db.ConsumedCapacity{}, ErrReadOnly
+}
+
+func (sp *snapshotPartition) TransactPut(ctx context.Context, items []map[string]*dynamodb.AttributeValue) (db.ConsumedCapacity, error) {
+	return db.ConsumedCapacity{}, ErrReadOnly
+}
+
+func (sp *snapshotPartition) TransactWrite(ctx context.Context, items []db.TransactWriteItem) (db.ConsumedCapacity, error) {
+	return db.ConsumedCapacity{}, ErrReadOnly
+}
+
+func (sp *snapshotPartition) TransactPutIfExists(ctx context.Context, key map[string]*dynamodb.AttributeValue, idField, excludedField string, items []map[string]*dynamodb.AttributeValue) (db.ConsumedCapacity, error) {
+	return db.ConsumedCapacity{}, ErrReadOnly
+}
+
+func (sp *snapshotPartition) UpdateItem(ctx context.Context, key map[string]*dynamodb.AttributeValue, set map[string]*dynamodb.AttributeValue) (db.ConsumedCapacity, error) {
+	return db.ConsumedCapacity{}, ErrReadOnly
+}
+
+func (sp *snapshotPartition) GetItem(ctx context.Context, key map[string]*dynamodb.AttributeValue, attributes ...string) (item map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error) {
+	mdb, err := sp.load(ctx)
+	if err != nil {
+		return
+	}
+	return mdb.GetItem(ctx, key, attributes...)
+}
+
+func (sp *snapshotPartition) BatchGet(ctx context.Context, keys []map[string]*dynamodb.AttributeValue) (items []map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error) {
+	mdb, err := sp.load(ctx)
+	if err != nil {
+		return
+	}
+	return mdb.BatchGet(ctx, keys)
+}
+
+func (sp *snapshotPartition) QueryByID(ctx context.Context, idField, idValue string, attributes ...string) (items []map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error) {
+	mdb, err := sp.load(ctx)
+	if err != nil {
+		return
+	}
+	return mdb.QueryByID(ctx, idField, idValue, attributes...)
+}
+
+func (sp *snapshotPartition) QueryByIDAndRangePrefix(ctx context.Context, idField, idValue, rangeField, rangePrefix string, attributes ...string) (items []map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error) {
+	mdb, err := sp.load(ctx)
+	if err != nil {
+		return
+	}
+	return mdb.QueryByIDAndRangePrefix(ctx, idField, idValue, rangeField, rangePrefix, attributes...)
+}
+
+func (sp *snapshotPartition) QueryIndex(ctx context.Context, indexName, field string, value interface{}) (items []map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error) {
+	mdb, err := sp.load(ctx)
+	if err != nil {
+		return
+	}
+	return mdb.QueryIndex(ctx, indexName, field, value)
+}
+
+func (sp *snapshotPartition) QueryIndexPage(ctx context.Context, indexName, field string, value interface{}, limit int64, startKey map[string]*dynamodb.AttributeValue) (items []map[string]*dynamodb.AttributeValue, lastEvaluatedKey map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error) {
+	mdb, err := sp.load(ctx)
+	if err != nil {
+		return
+	}
+	return mdb.QueryIndexPage(ctx, indexName, field, value, limit, startKey)
+}
+
+func (sp *snapshotPartition) ScanPage(ctx context.Context, field, value string, limit int64, startKey map[string]*dynamodb.AttributeValue) (items []map[string]*dynamodb.AttributeValue, lastEvaluatedKey map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error) {
+	mdb, err := sp.load(ctx)
+	if err != nil {
+		return
+	}
+	return mdb.ScanPage(ctx, field, value, limit, startKey)
+}
+
+func (sp *snapshotPartition) ScanSegment(ctx context.Context, segment, totalSegments, limit int64, startKey map[string]*dynamodb.AttributeValue) (items []map[string]*dynamodb.AttributeValue, lastEvaluatedKey map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error) {
+	mdb, err := sp.load(ctx)
+	if err != nil {
+		return
+	}
+	return mdb.ScanSegment(ctx, segment, totalSegments, limit, startKey)
+}
+
+func (sp *snapshotPartition) QueryPage(ctx context.Context, idField, idValue, rangeField, rangePrefix string, limit int64, startKey map[string]*dynamodb.AttributeValue) (items []map[string]*dynamodb.AttributeValue, lastEvaluatedKey map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error) {
+	mdb, err := sp.load(ctx)
+	if err != nil {
+		return
+	}
+	return mdb.QueryPage(ctx, idField, idValue, rangeField, rangePrefix, limit, startKey)
+}
+
+// DescribeTable describes the table which the snapshot was made from.
+func (sp *snapshotPartition) DescribeTable(ctx context.Context) (db.TableDescription, error) {
+	return sp.table, nil
+}
@@ -0,0 +1,73 @@
+package pregel
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestStoreSnapshot(t *testing.T) {
+	ctx := context.Background()
+	objects := newTestS3()
+	source := NewMemoryStore()
+	source.S3 = objects
+	for _, id := range []string{"a", "b", "c", "d"} {
+		if err := source.Put(NewNode(id).WithLabels("letter")); err != nil {
+			t.Fatalf("unexpected error putting %q: %v", id, err)
+		}
+	}
+	if err := source.PutEdges("a", NewEdge("b"), NewEdge("c")); err != nil {
+		t.Fatalf("unexpected error putting edges: %v", err)
+	}
+	if err := source.SnapshotToS3(ctx, "bucket", "snapshots/1", 3); err != nil {
+		t.Fatalf("unexpected error taking snapshot: %v", err)
+	}
+
+	s, err := NewSnapshotStore(ctx, objects, "bucket", "snapshots/1")
+	if err != nil {
+		t.Fatalf("unexpected error opening snapshot: %v", err)
+	}
+	s.LabelIndex = source.LabelIndex
+	if err = s.ValidateSchema(ctx); err != nil {
+		t.Errorf("expected the snapshot to have the schema of the table, got %v", err)
+	}
+
+	t.Run("Nodes can be read", func(t *testing.T) {
+		n, ok, err := s.Get("a")
+		if err != nil || !ok {
+			t.Fatalf("expected a to be found, got %v, %v", ok, err)
+		}
+		var children []string
+		for _, e := range n.Children {
+			children = append(children, e.ID)
+		}
+		if expected := []string{"b", "c"}; !reflect.DeepEqual(children, expected) {
+			t.Errorf("expected children %v, got %v", expected, children)
+		}
+		if _, ok, _ := s.Get("z"); ok {
+			t.Errorf("expected z not to be found")
+		}
+	})
+	t.Run("Indexes are queried across partitions", func(t *testing.T) {
+		ids, _, err := s.QueryByLabel("letter", 0, "")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		sort.Strings(ids)
+		if expected := []string{"a", "b", "c", "d"}; !reflect.DeepEqual(ids, expected) {
+			t.Errorf("expected %v, got %v", expected, ids)
+		}
+	})
+	t.Run("Writes are rejected", func(t *testing.T) {
+		if err := s.Put(NewNode("e")); !errors.Is(err, ErrReadOnly) {
+			t.Errorf("expected ErrReadOnly, got %v", err)
+		}
+	})
+	t.Run("Missing snapshots return an error", func(t *testing.T) {
+		if _, err := NewSnapshotStore(ctx, objects, "bucket", "snapshots/2"); err == nil {
+			t.Errorf("expected an error")
+		}
+	})
+}
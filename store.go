@@ -1,49 +1,140 @@
 package pregel
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/a-h/pregel/db"
 	"github.com/a-h/pregel/rangefield"
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
 )
 
 // NewStore creates a store which is backed by DynamoDB.
 func NewStore(region, tableName string) (store *Store, err error) {
-	client, err := db.New(region, tableName)
+	sess, err := session.NewSession(&aws.Config{
+		Region: aws.String(region),
+	})
 	if err != nil {
 		return nil, err
 	}
-	return NewStoreWithClient(client), nil
+	return NewStoreWithSession(sess, tableName), nil
+}
+
+// NewStoreWithSession creates a store which is backed by DynamoDB, using an existing session for the
+// DynamoDB and S3 clients. This allows the session's credentials, region and HTTP client to be
+// configured, and the session to be shared with other clients.
+func NewStoreWithSession(sess *session.Session, tableName string) (store *Store) {
+	store = NewStoreWithClient(db.NewWithSession(sess, tableName))
+	store.S3 = &lazyS3{sess: sess}
+	return
 }
 
 // NewStoreWithClient creates a store from a DB implementation.
 func NewStoreWithClient(client DB) (store *Store) {
 	store = &Store{
-		Client:    client,
-		DataTypes: make(map[string]func() interface{}),
+		Client:          client,
+		DataTypes:       make(map[string]func() interface{}),
+		DataTypeAliases: make(map[string]string),
+		Validators:      make(map[string]func(v interface{}) error),
+		Indexes:         make(map[AttributeIndexKey]string),
+		Encodings:       make(map[string]Encoding),
+		Now:             time.Now,
 	}
 	return
 }
 
 // DB client to access DynamoDB.
 type DB interface {
-	BatchDelete(keys []map[string]*dynamodb.AttributeValue) (db.ConsumedCapacity, error)
-	BatchPut(items []map[string]*dynamodb.AttributeValue) (db.ConsumedCapacity, error)
-	QueryByID(idField, idValue string) (items []map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error)
+	BatchDelete(ctx context.Context, keys []map[string]*dynamodb.AttributeValue) (db.ConsumedCapacity, error)
+	BatchPut(ctx context.Context, items []map[string]*dynamodb.AttributeValue) (db.ConsumedCapacity, error)
+	TryBatchPut(ctx context.Context, items []map[string]*dynamodb.AttributeValue) (unprocessed []map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error)
+	PutIfNotExists(ctx context.Context, idField string, item map[string]*dynamodb.AttributeValue) (db.ConsumedCapacity, error)
+	TransactPut(ctx context.Context, items []map[string]*dynamodb.AttributeValue) (db.ConsumedCapacity, error)
+	TransactWrite(ctx context.Context, items []db.TransactWriteItem) (db.ConsumedCapacity, error)
+	TransactPutIfExists(ctx context.Context, key map[string]*dynamodb.AttributeValue, idField, excludedField string, items []map[string]*dynamodb.AttributeValue) (db.ConsumedCapacity, error)
+	UpdateItem(ctx context.Context, key map[string]*dynamodb.AttributeValue, set map[string]*dynamodb.AttributeValue) (db.ConsumedCapacity, error)
+	GetItem(ctx context.Context, key map[string]*dynamodb.AttributeValue, attributes ...string) (item map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error)
+	BatchGet(ctx context.Context, keys []map[string]*dynamodb.AttributeValue) (items []map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error)
+	QueryByID(ctx context.Context, idField, idValue string, attributes ...string) (items []map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error)
+	QueryByIDAndRangePrefix(ctx context.Context, idField, idValue, rangeField, rangePrefix string, attributes ...string) (items []map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error)
+	QueryIndex(ctx context.Context, indexName, field string, value interface{}) (items []map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error)
+	QueryIndexPage(ctx context.Context, indexName, field string, value interface{}, limit int64, startKey map[string]*dynamodb.AttributeValue) (items []map[string]*dynamodb.AttributeValue, lastEvaluatedKey map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error)
+	ScanPage(ctx context.Context, field, value string, limit int64, startKey map[string]*dynamodb.AttributeValue) (items []map[string]*dynamodb.AttributeValue, lastEvaluatedKey map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error)
+	ScanSegment(ctx context.Context, segment, totalSegments, limit int64, startKey map[string]*dynamodb.AttributeValue) (items []map[string]*dynamodb.AttributeValue, lastEvaluatedKey map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error)
+	QueryPage(ctx context.Context, idField, idValue, rangeField, rangePrefix string, limit int64, startKey map[string]*dynamodb.AttributeValue) (items []map[string]*dynamodb.AttributeValue, lastEvaluatedKey map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error)
+	DescribeTable(ctx context.Context) (db.TableDescription, error)
 }
 
 // Store handles storage of data in DynamoDB.
 type Store struct {
-	Client                DB
-	ConsumedCapacity      float64
-	ConsumedReadCapacity  float64
-	ConsumedWriteCapacity float64
-	DataTypes             map[string]func() interface{}
+	Client    DB
+	DataTypes map[string]func() interface{}
+	// DataTypeAliases maps alternative names of data types to their registered names.
+	DataTypeAliases map[string]string
+	// Validators check data of each data type before it's written.
+	Validators map[string]func(v interface{}) error
+	// Indexes maps data record attributes to the name of the global secondary index that has the
+	// attribute as its partition key.
+	Indexes map[AttributeIndexKey]string
+	// Encodings convert the data of each data type to and from the attributes of data records. Data
+	// types without an Encoding use DynamoDBEncoding.
+	Encodings map[string]Encoding
+	// SoftDelete makes Delete mark nodes as deleted with a tombstone, instead of removing their
+	// records. Soft deleted nodes are excluded from Get unless the IncludeDeleted option is used,
+	// and can be restored with Undelete.
+	SoftDelete bool
+	// Timestamps enables recording the creation and modification times of nodes and edges.
+	Timestamps bool
+	// Now returns the current time, and is used to create timestamps.
+	Now func() time.Time
+	// LabelIndex is the name of the global secondary index used by QueryByLabel. See LabelAttributeName.
+	LabelIndex string
+	// RangeIndex is the name of an optional global secondary index with the "rng" attribute as its
+	// partition key and the "id" attribute as its sort key, which projects all attributes. If set,
+	// ListNodes queries the index instead of scanning the table, and QueryByLabel uses it when
+	// LabelIndex isn't set.
+	RangeIndex string
+	// DataTypeIndex is the name of the global secondary index used by QueryByDataType. The index has
+	// the "t" attribute, which holds the name of the data type of data records, as its partition key
+	// and the "id" attribute as its sort key.
+	DataTypeIndex string
+	// TransactionalEdges makes PutEdges write both sides of each edge, and the edge data, in a single
+	// transaction, so that a failure can't leave an edge that only exists in one direction.
+	// Transactions are limited to db.MaxTransactionItems records.
+	TransactionalEdges bool
+	// EnforceAcyclic makes PutEdges return a CycleError instead of adding an edge that would create a
+	// cycle. The check walks up to AcyclicCheckDepth levels of the parent's ancestors.
+	EnforceAcyclic bool
+	// AcyclicCheckDepth is the number of levels of ancestors checked when EnforceAcyclic is set. If
+	// zero, DefaultAcyclicCheckDepth is used.
+	AcyclicCheckDepth int
+	// NodeDataMode controls whether PutNodeData creates nodes which don't exist.
+	NodeDataMode NodeDataMode
+	// ImportRetryPolicy controls how Import retries records which DynamoDB didn't process. If nil, the
+	// ImportOptions are used. It's only used by Import: every request, including those made by
+	// Import, is retried by the db package according to the db.DB's own RetryPolicy.
+	ImportRetryPolicy db.RetryPolicy
+	// Compression compresses large data records. If nil, data records aren't compressed.
+	Compression *Compression
+	// S3 is the client used by BackupToS3, RestoreFromS3 and SnapshotToS3. NewStore sets a client for
+	// the Store's region, which is only created when it's first used.
+	S3 S3API
+
+	// capacityMutex protects the capacity, which is updated by concurrent calls.
+	capacityMutex sync.Mutex
+	capacity      db.ConsumedCapacity
+
+	// ctx is passed to the database by Store methods which don't take a context.
+	ctx context.Context
 }
 
 // RegisterDataType registers a data type.
@@ -52,7 +143,27 @@ func (s *Store) RegisterDataType(f func() interface{}) {
 	s.DataTypes[getTypeName(v)] = f
 }
 
+// RegisterNamedDataType registers a data type using an explicit name, instead of the name of the Go
+// type, so that the Go type can be renamed without affecting stored records. Data stored under any
+// of the aliases, e.g. previous names, is also read into the data type, and returned using the name.
+// Use WithNamedData to write data using the name.
+func (s *Store) RegisterNamedDataType(name string, f func() interface{}, aliases ...string) {
+	s.DataTypes[name] = f
+	for _, alias := range aliases {
+		s.DataTypeAliases[alias] = name
+	}
+}
+
+// TypeNamer can be implemented by data types to set the name that their data is stored under,
+// instead of using the name of the Go type. The name is used by RegisterDataType and WithData.
+type TypeNamer interface {
+	TypeName() string
+}
+
 func getTypeName(of interface{}) string {
+	if tn, ok := of.(TypeNamer); ok {
+		return tn.TypeName()
+	}
 	t := reflect.TypeOf(of)
 	if t.Kind() == reflect.Ptr {
 		return t.Elem().Name()
@@ -60,14 +171,12 @@ func getTypeName(of interface{}) string {
 	return t.Name()
 }
 
-func convertToRecords(n Node) (records []map[string]*dynamodb.AttributeValue, err error) {
-	records = append(records, newNodeRecord(n.ID))
-	nodeDataRecords, err := convertNodeDataToRecords(n.ID, n.Data)
+func (s *Store) convertToRecords(n Node) (records []map[string]*dynamodb.AttributeValue, err error) {
+	records, err = s.convertNodeRecords(n)
 	if err != nil {
 		return
 	}
-	records = append(records, nodeDataRecords...)
-	edgeRecords, err := convertNodeEdgesToRecords(n.ID, n.Children, n.Parents)
+	edgeRecords, err := s.convertNodeEdgesToRecords(n.ID, n.Children, n.Parents)
 	if err != nil {
 		return
 	}
@@ -75,11 +184,53 @@ func convertToRecords(n Node) (records []map[string]*dynamodb.AttributeValue, er
 	return
 }
 
-func convertNodeDataToRecords(id string, d Data) (nodeDataRecords []map[string]*dynamodb.AttributeValue, err error) {
+// convertToOwnRecords converts a node to the records stored under its own ID. The records of the
+// other end of each edge are left out, so that edges aren't written twice when both of their nodes
+// are written, e.g. when importing a snapshot written by Export.
+func (s *Store) convertToOwnRecords(n Node) (records []map[string]*dynamodb.AttributeValue, err error) {
+	records, err = s.convertNodeRecords(n)
+	if err != nil {
+		return
+	}
+	for _, e := range n.Children {
+		er, eErr := s.newChildRecord(n.ID, e.ID, e.Label, e.Data)
+		if eErr != nil {
+			err = eErr
+			return
+		}
+		records = append(records, er...)
+	}
+	for _, e := range n.Parents {
+		er, eErr := s.newParentRecord(e.ID, n.ID, e.Label, e.Data)
+		if eErr != nil {
+			err = eErr
+			return
+		}
+		records = append(records, er...)
+	}
+	return
+}
+
+// convertNodeRecords converts the node record, data and labels of a node to records. The node
+// record is first.
+func (s *Store) convertNodeRecords(n Node) (records []map[string]*dynamodb.AttributeValue, err error) {
+	records = append(records, newNodeRecord(n.ID))
+	nodeDataRecords, err := s.convertNodeDataToRecords(n.ID, n.Data)
+	if err != nil {
+		return
+	}
+	records = append(records, nodeDataRecords...)
+	for _, l := range n.Labels {
+		records = append(records, newLabelRecord(n.ID, l))
+	}
+	return
+}
+
+func (s *Store) convertNodeDataToRecords(id string, d Data) (nodeDataRecords []map[string]*dynamodb.AttributeValue, err error) {
 	for k, v := range d {
 		k := k
 		v := v
-		dr, dErr := newDataRecord(id, rangefield.NodeData{DataType: k}, k, v)
+		dr, dErr := s.newDataRecord(id, rangefield.NodeData{DataType: k}, k, v)
 		if dErr != nil {
 			err = dErr
 			return
@@ -89,9 +240,9 @@ func convertNodeDataToRecords(id string, d Data) (nodeDataRecords []map[string]*
 	return
 }
 
-func convertNodeEdgesToRecords(id string, children []*Edge, parents []*Edge) (edgeRecords []map[string]*dynamodb.AttributeValue, err error) {
+func (s *Store) convertNodeEdgesToRecords(id string, children []*Edge, parents []*Edge) (edgeRecords []map[string]*dynamodb.AttributeValue, err error) {
 	// Add parent to child relationship.
-	childRecords, err := convertEdgesToRecords(id, children, newChildRecord, newParentRecord)
+	childRecords, err := convertEdgesToRecords(id, children, s.newChildRecord, s.newParentRecord)
 	if err != nil {
 		return
 	}
@@ -102,7 +253,8 @@ func convertNodeEdgesToRecords(id string, children []*Edge, parents []*Edge) (ed
 		parent := parent
 		e := NewEdge(id)
 		e.Data = parent.Data
-		parentRecords, pErr := convertEdgesToRecords(parent.ID, []*Edge{e}, newParentRecord, newChildRecord)
+		e.Label = parent.Label
+		parentRecords, pErr := convertEdgesToRecords(parent.ID, []*Edge{e}, s.newParentRecord, s.newChildRecord)
 		if pErr != nil {
 			err = pErr
 			return
@@ -116,14 +268,14 @@ func convertEdgesToRecords(principal string, edges []*Edge, fromPrincipal record
 	for _, e := range edges {
 		e := e
 
-		er, nErr := fromPrincipal(principal, e.ID, e.Data)
+		er, nErr := fromPrincipal(principal, e.ID, e.Label, e.Data)
 		if nErr != nil {
 			err = nErr
 			return
 		}
 		edgeRecords = append(edgeRecords, er...)
 
-		er, nErr = toPrincipal(principal, e.ID, e.Data)
+		er, nErr = toPrincipal(principal, e.ID, e.Label, e.Data)
 		if nErr != nil {
 			err = nErr
 			return
@@ -133,28 +285,308 @@ func convertEdgesToRecords(principal string, edges []*Edge, fromPrincipal record
 	return
 }
 
+func (s *Store) now() time.Time {
+	if s.Now == nil {
+		return time.Now()
+	}
+	return s.Now()
+}
+
+// stampRecords sets the expiry time of the records, and their timestamps if they're enabled.
+func (s *Store) stampRecords(n Node, records []map[string]*dynamodb.AttributeValue) {
+	setRecordTTLs(n, records)
+	if !s.Timestamps {
+		return
+	}
+	stampRecords(n, s.now(), records)
+}
+
+// keepCreationTimes sets the creation time of node and edge records which already exist to the
+// time that they were first written, so that rewriting a node or edge, e.g. by PutNodeData, doesn't
+// reset it. It has the same effect as SET _c = if_not_exists(_c, :now), which can't be used with
+// batch writes.
+func (s *Store) keepCreationTimes(records []map[string]*dynamodb.AttributeValue) (err error) {
+	if !s.Timestamps {
+		return
+	}
+	written := make(map[string][]map[string]*dynamodb.AttributeValue)
+	var keys []map[string]*dynamodb.AttributeValue
+	for _, r := range records {
+		if !hasCreationTime(r) {
+			continue
+		}
+		k := recordKey(r)
+		if _, ok := written[k]; !ok {
+			keys = append(keys, map[string]*dynamodb.AttributeValue{fieldID: r[fieldID], fieldRange: r[fieldRange]})
+		}
+		written[k] = append(written[k], r)
+	}
+	if len(keys) == 0 {
+		return
+	}
+	existing, cc, err := s.Client.BatchGet(s.context(), keys)
+	if err != nil {
+		return
+	}
+	s.updateCapacityStats(cc)
+	for _, itm := range existing {
+		created, ok := itm[fieldCreatedAt]
+		if !ok {
+			continue
+		}
+		for _, r := range written[recordKey(itm)] {
+			r[fieldCreatedAt] = created
+		}
+	}
+	return
+}
+
 func (s *Store) updateCapacityStats(c db.ConsumedCapacity) {
-	s.ConsumedCapacity += c.ConsumedCapacity
-	s.ConsumedReadCapacity += c.ConsumedReadCapacity
-	s.ConsumedWriteCapacity += c.ConsumedWriteCapacity
+	s.capacityMutex.Lock()
+	defer s.capacityMutex.Unlock()
+	s.capacity.ConsumedCapacity += c.ConsumedCapacity
+	s.capacity.ConsumedReadCapacity += c.ConsumedReadCapacity
+	s.capacity.ConsumedWriteCapacity += c.ConsumedWriteCapacity
+}
+
+// withClient returns a copy of the Store's configuration which uses the client, and tracks its
+// consumed capacity separately.
+func (s *Store) withClient(client DB) *Store {
+	c := NewStoreWithClient(client)
+	c.DataTypes = s.DataTypes
+	c.DataTypeAliases = s.DataTypeAliases
+	c.Validators = s.Validators
+	c.Encodings = s.Encodings
+	c.Indexes = s.Indexes
+	c.LabelIndex = s.LabelIndex
+	c.RangeIndex = s.RangeIndex
+	c.DataTypeIndex = s.DataTypeIndex
+	c.SoftDelete = s.SoftDelete
+	c.Timestamps = s.Timestamps
+	c.Now = s.Now
+	c.TransactionalEdges = s.TransactionalEdges
+	c.EnforceAcyclic = s.EnforceAcyclic
+	c.AcyclicCheckDepth = s.AcyclicCheckDepth
+	c.NodeDataMode = s.NodeDataMode
+	c.ImportRetryPolicy = s.ImportRetryPolicy
+	c.Compression = s.Compression
+	c.S3 = s.S3
+	c.ctx = s.ctx
+	return c
+}
+
+// WithContext returns a copy of the Store which passes the context to each database request made by
+// methods which don't take a context, so that cancellation, deadlines and tracing apply to them.
+// Capacity statistics are tracked separately by the returned Store.
+func (s *Store) WithContext(ctx context.Context) *Store {
+	c := s.withClient(s.Client)
+	c.ctx = ctx
+	return c
+}
+
+// context returns the context passed to WithContext, or the background context.
+func (s *Store) context() context.Context {
+	if s.ctx == nil {
+		return context.Background()
+	}
+	return s.ctx
+}
+
+// measure runs the operation against a copy of the Store, to find the capacity consumed by just
+// that operation. The capacity is also added to the Store's total.
+func (s *Store) measure(op func(s *Store) error) (cc db.ConsumedCapacity, err error) {
+	m := s.withClient(s.Client)
+	err = op(m)
+	cc = m.CapacitySnapshot()
+	s.updateCapacityStats(cc)
+	return
+}
+
+// PutWithCapacity is the same as Put, but also returns the capacity consumed by the operation.
+func (s *Store) PutWithCapacity(nodes ...Node) (cc db.ConsumedCapacity, err error) {
+	return s.measure(func(m *Store) error {
+		return m.Put(nodes...)
+	})
+}
+
+// GetWithCapacity is the same as Get, but also returns the capacity consumed by the operation.
+func (s *Store) GetWithCapacity(id string) (n Node, ok bool, cc db.ConsumedCapacity, err error) {
+	cc, err = s.measure(func(m *Store) (mErr error) {
+		n, ok, mErr = m.Get(id)
+		return
+	})
+	return
+}
+
+// GetManyWithCapacity is the same as GetMany, but also returns the capacity consumed by the
+// operation.
+func (s *Store) GetManyWithCapacity(ids ...string) (nodes []Node, cc db.ConsumedCapacity, err error) {
+	cc, err = s.measure(func(m *Store) (mErr error) {
+		nodes, mErr = m.GetMany(ids...)
+		return
+	})
+	return
+}
+
+// DeleteWithCapacity is the same as Delete, but also returns the capacity consumed by the operation.
+func (s *Store) DeleteWithCapacity(id string) (cc db.ConsumedCapacity, err error) {
+	return s.measure(func(m *Store) error {
+		return m.Delete(id)
+	})
+}
+
+// CapacitySnapshot returns the capacity consumed by the Store since it was created, or since the
+// last call to ResetCapacity. It's safe to call while the Store is in use.
+func (s *Store) CapacitySnapshot() db.ConsumedCapacity {
+	s.capacityMutex.Lock()
+	defer s.capacityMutex.Unlock()
+	return s.capacity
+}
+
+// ConsumedCapacity returns the total capacity consumed by the Store.
+//
+// Deprecated: Use CapacitySnapshot, which returns the read and write capacity at the same time.
+func (s *Store) ConsumedCapacity() float64 {
+	return s.CapacitySnapshot().ConsumedCapacity
+}
+
+// ConsumedReadCapacity returns the read capacity consumed by the Store.
+//
+// Deprecated: Use CapacitySnapshot, which returns the total and write capacity at the same time.
+func (s *Store) ConsumedReadCapacity() float64 {
+	return s.CapacitySnapshot().ConsumedReadCapacity
+}
+
+// ConsumedWriteCapacity returns the write capacity consumed by the Store.
+//
+// Deprecated: Use CapacitySnapshot, which returns the total and read capacity at the same time.
+func (s *Store) ConsumedWriteCapacity() float64 {
+	return s.CapacitySnapshot().ConsumedWriteCapacity
+}
+
+// ResetCapacity sets the consumed capacity back to zero, returning the capacity consumed before the
+// reset, e.g. to record the capacity used by each request.
+func (s *Store) ResetCapacity() (previous db.ConsumedCapacity) {
+	s.capacityMutex.Lock()
+	defer s.capacityMutex.Unlock()
+	previous = s.capacity
+	s.capacity = db.ConsumedCapacity{}
+	return
 }
 
 // Put upserts Nodes and Edges into DynamoDB.
 func (s *Store) Put(nodes ...Node) (err error) {
 	// Map from nodes into the Write Requests.
-	var records []map[string]*dynamodb.AttributeValue
+	records, err := s.convertNodesToRecords(nodes)
+	if err != nil {
+		return
+	}
+	if err = s.keepCreationTimes(records); err != nil {
+		return
+	}
+	cc, err := s.Client.BatchPut(s.context(), records)
+	if err != nil {
+		return
+	}
+	s.updateCapacityStats(cc)
+	return
+}
+
+// convertNodesToRecords converts nodes to stamped records, ready to be written.
+func (s *Store) convertNodesToRecords(nodes []Node) (records []map[string]*dynamodb.AttributeValue, err error) {
 	for _, n := range nodes {
-		if n.ID == "" {
-			return ErrMissingNodeID
-		}
-		r, cErr := convertToRecords(n)
+		r, cErr := s.convertNodeToRecords(n, s.convertToRecords)
 		if cErr != nil {
 			err = cErr
 			return
 		}
 		records = append(records, r...)
 	}
-	cc, err := s.Client.BatchPut(records)
+	return
+}
+
+// convertNodeToRecords validates the node, and converts it to stamped records using convert. Soft
+// deleted nodes keep their tombstone.
+func (s *Store) convertNodeToRecords(n Node, convert func(n Node) ([]map[string]*dynamodb.AttributeValue, error)) (records []map[string]*dynamodb.AttributeValue, err error) {
+	if n.ID == "" {
+		err = ErrMissingNodeID
+		return
+	}
+	if err = s.validateNode(n); err != nil {
+		return
+	}
+	records, err = convert(n)
+	if err != nil {
+		return
+	}
+	if n.Deleted {
+		// The first record is the node record.
+		records[0][fieldDeleted] = newTimeAttribute(s.now())
+	}
+	s.stampRecords(n, records)
+	err = s.encodeRecords(records)
+	return
+}
+
+// PutTransaction upserts Nodes and Edges into DynamoDB using transactions. If the nodes fit into a
+// single transaction (db.MaxTransactionItems records), they're written atomically. Larger sets of
+// records are split into multiple transactions which are written in order, so a failure can leave
+// earlier transactions written.
+func (s *Store) PutTransaction(nodes ...Node) (err error) {
+	records, err := s.convertNodesToRecords(nodes)
+	if err != nil {
+		return
+	}
+	if err = s.keepCreationTimes(records); err != nil {
+		return
+	}
+	for len(records) > 0 {
+		chunk := records
+		if len(chunk) > db.MaxTransactionItems {
+			chunk = chunk[:db.MaxTransactionItems]
+		}
+		records = records[len(chunk):]
+		var cc db.ConsumedCapacity
+		cc, err = s.Client.TransactPut(s.context(), chunk)
+		if err != nil {
+			return
+		}
+		s.updateCapacityStats(cc)
+	}
+	return
+}
+
+// Create inserts a new Node and its Edges into DynamoDB. If the node already exists, ErrAlreadyExists
+// is returned and nothing is written. The node record is written first, so if writing the node's
+// data or edges fails, the node will exist without them.
+func (s *Store) Create(n Node) (err error) {
+	if n.ID == "" {
+		return ErrMissingNodeID
+	}
+	if err = s.validateNode(n); err != nil {
+		return
+	}
+	records, err := s.convertToRecords(n)
+	if err != nil {
+		return
+	}
+	s.stampRecords(n, records)
+	if err = s.encodeRecords(records); err != nil {
+		return
+	}
+	// The first record is the node record.
+	cc, err := s.Client.PutIfNotExists(s.context(), fieldID, records[0])
+	if err == db.ErrConditionalCheckFailed {
+		return ErrAlreadyExists
+	}
+	if err != nil {
+		return
+	}
+	s.updateCapacityStats(cc)
+	if len(records) == 1 {
+		return
+	}
+	cc, err = s.Client.BatchPut(s.context(), records[1:])
 	if err != nil {
 		return
 	}
@@ -162,14 +594,138 @@ func (s *Store) Put(nodes ...Node) (err error) {
 	return
 }
 
-// PutNodeData into the store.
+// NodeDataMode controls whether PutNodeData creates nodes which don't exist.
+type NodeDataMode int
+
+const (
+	// NodeDataUpsert writes the node record along with the data, so PutNodeData creates the node if
+	// it doesn't exist, and restores it if it was soft deleted. This is the default.
+	NodeDataUpsert NodeDataMode = iota
+	// NodeDataMustExist only writes the data if the node exists, and hasn't been soft deleted.
+	// Otherwise, PutNodeData returns ErrNodeNotFound. The data is written in a transaction, so is
+	// limited to db.MaxTransactionItems - 1 data types.
+	NodeDataMustExist
+)
+
+// PutNodeData into the store. See the Store's NodeDataMode for how nodes which don't exist are handled.
 func (s *Store) PutNodeData(id string, data Data) (err error) {
 	if id == "" {
 		return ErrMissingNodeID
 	}
 	n := NewNode(id)
 	n.Data = data
-	return s.Put(n)
+	if s.NodeDataMode != NodeDataMustExist {
+		return s.Put(n)
+	}
+	if err = s.validateNode(n); err != nil {
+		return
+	}
+	records, err := s.convertNodeDataToRecords(id, data)
+	if err != nil {
+		return
+	}
+	s.stampRecords(n, records)
+	if err = s.encodeRecords(records); err != nil {
+		return
+	}
+	cc, err := s.Client.TransactPutIfExists(s.context(), getID(id, rangefield.Node{}), fieldID, fieldDeleted, records)
+	if err == db.ErrConditionalCheckFailed {
+		err = ErrNodeNotFound
+	}
+	if err != nil {
+		return
+	}
+	s.updateCapacityStats(cc)
+	return
+}
+
+// DeleteNodeData removes a single data type from a node. If the data type is registered with
+// aliases, data stored under the aliases is removed too, since it's read as the same data type.
+func (s *Store) DeleteNodeData(id, dataType string) (err error) {
+	if id == "" {
+		return ErrMissingNodeID
+	}
+	if dataType == "" {
+		return ErrMissingDataType
+	}
+	if name, isAlias := s.DataTypeAliases[dataType]; isAlias {
+		dataType = name
+	}
+	dataTypes := []string{dataType}
+	for alias, name := range s.DataTypeAliases {
+		if name == dataType {
+			dataTypes = append(dataTypes, alias)
+		}
+	}
+	sort.Strings(dataTypes[1:])
+	keys := make([]map[string]*dynamodb.AttributeValue, len(dataTypes))
+	for i, dt := range dataTypes {
+		keys[i] = getID(id, rangefield.NodeData{DataType: dt})
+	}
+	cc, err := s.Client.BatchDelete(s.context(), keys)
+	if err != nil {
+		return
+	}
+	s.updateCapacityStats(cc)
+	return
+}
+
+// ErrReservedAttributeName is returned when updating an attribute which the Store uses to manage
+// its records, such as the ID.
+var ErrReservedAttributeName = errors.New("cannot update reserved attribute")
+
+// reservedAttributes are the attributes used by the Store, which can't be changed by UpdateNodeData.
+var reservedAttributes = map[string]bool{
+	fieldID:             true,
+	fieldRange:          true,
+	fieldRecordDataType: true,
+	fieldCreatedAt:      true,
+	fieldUpdatedAt:      true,
+	fieldDeleted:        true,
+	LabelAttributeName:  true,
+	TTLAttributeName:    true,
+	fieldCompressed:     true,
+	fieldCodec:          true,
+}
+
+// UpdateNodeData sets individual fields of a node's data record, leaving its other fields
+// unchanged. Unlike PutNodeData, which replaces the whole record, concurrent updates to different
+// fields don't overwrite each other. The updates are keyed by the attribute name of each field, i.e.
+// the name used when the data is marshalled. If the record doesn't exist, it's created with just
+// the updated fields. Validators aren't run, since the rest of the data isn't read. Data types with
+// a registered Encoding can't be updated, and return ErrEncodedDataUpdate.
+func (s *Store) UpdateNodeData(id, dataType string, updates map[string]interface{}) (err error) {
+	if id == "" {
+		return ErrMissingNodeID
+	}
+	if dataType == "" {
+		return ErrMissingDataType
+	}
+	if name, isAlias := s.DataTypeAliases[dataType]; isAlias {
+		dataType = name
+	}
+	if _, ok := s.Encodings[dataType]; ok {
+		return ErrEncodedDataUpdate
+	}
+	set := make(map[string]*dynamodb.AttributeValue, len(updates)+2)
+	for k, v := range updates {
+		if reservedAttributes[k] {
+			return ErrReservedAttributeName
+		}
+		if set[k], err = dynamodbattribute.Marshal(v); err != nil {
+			return
+		}
+	}
+	set[fieldRecordDataType] = &dynamodb.AttributeValue{S: aws.String(dataType)}
+	if s.Timestamps {
+		set[fieldUpdatedAt] = newTimeAttribute(s.now())
+	}
+	cc, err := s.Client.UpdateItem(s.context(), getID(id, rangefield.NodeData{DataType: dataType}), set)
+	if err != nil {
+		return
+	}
+	s.updateCapacityStats(cc)
+	return
 }
 
 // PutEdges into the store.
@@ -177,11 +733,30 @@ func (s *Store) PutEdges(parent string, edges ...*Edge) (err error) {
 	if parent == "" {
 		return ErrMissingNodeID
 	}
-	records, err := convertNodeEdgesToRecords(parent, edges, nil)
+	n := NewNode(parent).WithChildren(edges...)
+	if err = s.validateNode(n); err != nil {
+		return
+	}
+	if err = s.checkAcyclic(parent, edges); err != nil {
+		return
+	}
+	records, err := s.convertNodeEdgesToRecords(parent, edges, nil)
 	if err != nil {
 		return
 	}
-	cc, err := s.Client.BatchPut(records)
+	s.stampRecords(n, records)
+	if err = s.keepCreationTimes(records); err != nil {
+		return
+	}
+	if err = s.encodeRecords(records); err != nil {
+		return
+	}
+	var cc db.ConsumedCapacity
+	if s.TransactionalEdges {
+		cc, err = s.Client.TransactPut(s.context(), records)
+	} else {
+		cc, err = s.Client.BatchPut(s.context(), records)
+	}
 	if err != nil {
 		return
 	}
@@ -189,6 +764,23 @@ func (s *Store) PutEdges(parent string, edges ...*Edge) (err error) {
 	return
 }
 
+// PutLabelledEdges adds edges with the label to the store, e.g. to record that the parent "owns" the
+// children. Labelled edges are stored separately to unlabelled edges, and to edges with other labels,
+// so a parent can have multiple relationships with the same child. The edges passed in aren't
+// changed.
+func (s *Store) PutLabelledEdges(parent, label string, edges ...*Edge) (err error) {
+	if label == "" {
+		return ErrMissingLabel
+	}
+	labelled := make([]*Edge, len(edges))
+	for i, e := range edges {
+		c := *e
+		c.Label = label
+		labelled[i] = &c
+	}
+	return s.PutEdges(parent, labelled...)
+}
+
 // PutEdgeData into the store.
 func (s *Store) PutEdgeData(parent, child string, data Data) (err error) {
 	if parent == "" || child == "" {
@@ -199,6 +791,33 @@ func (s *Store) PutEdgeData(parent, child string, data Data) (err error) {
 	return s.PutEdges(parent, e)
 }
 
+// DeleteEdgeData removes a single data type from an edge, deleting both the parent and child side
+// data records.
+func (s *Store) DeleteEdgeData(parent, child, dataType string) (err error) {
+	return s.DeleteLabelledEdgeData(parent, child, "", dataType)
+}
+
+// DeleteLabelledEdgeData removes a single data type from the edge with the label between a parent
+// and child, deleting both the parent and child side data records. An empty label removes the data
+// of the unlabelled edge.
+func (s *Store) DeleteLabelledEdgeData(parent, child, label, dataType string) (err error) {
+	if parent == "" || child == "" {
+		return ErrMissingNodeID
+	}
+	if dataType == "" {
+		return ErrMissingDataType
+	}
+	cc, err := s.Client.BatchDelete(s.context(), []map[string]*dynamodb.AttributeValue{
+		getID(parent, rangefield.ChildData{Child: child, DataType: dataType, Label: label}),
+		getID(child, rangefield.ParentData{Parent: parent, DataType: dataType, Label: label}),
+	})
+	if err != nil {
+		return
+	}
+	s.updateCapacityStats(cc)
+	return
+}
+
 func getID(id string, rangeKey rangefield.RangeField) map[string]*dynamodb.AttributeValue {
 	return map[string]*dynamodb.AttributeValue{
 		fieldID: {
@@ -213,6 +832,34 @@ func getID(id string, rangeKey rangefield.RangeField) map[string]*dynamodb.Attri
 // ErrMissingNodeID is returned when a node's ID is empty.
 var ErrMissingNodeID = errors.New("invalid node ID, IDs cannot be empty")
 
+// ErrMissingDataType is returned when a data type name is empty.
+var ErrMissingDataType = errors.New("invalid data type, data types cannot be empty")
+
+// ErrMissingLabel is returned when an edge label is required, but is empty.
+var ErrMissingLabel = errors.New("invalid label, labels cannot be empty")
+
+// ErrNodeNotFound is returned when a node which is required to exist can't be found.
+var ErrNodeNotFound = errors.New("node not found")
+
+// ErrAlreadyExists is returned when creating a node which already exists.
+var ErrAlreadyExists = errors.New("node already exists")
+
+// ErrInvalidCursor is returned when a pagination cursor can't be decoded.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Errors returned when DynamoDB rejects a request can be matched using errors.Is, without inspecting
+// AWS error codes.
+var (
+	// ErrThrottled matches requests which were throttled, and weren't successful after retrying.
+	ErrThrottled = db.ErrThrottled
+	// ErrResourceNotFound matches requests to a table or index which doesn't exist.
+	ErrResourceNotFound = db.ErrResourceNotFound
+	// ErrValidation matches requests which DynamoDB rejected as invalid.
+	ErrValidation = db.ErrValidation
+	// ErrConditionalCheckFailed matches conditional writes whose condition wasn't met.
+	ErrConditionalCheckFailed = db.ErrConditionalCheckFailed
+)
+
 var errRecordIsMissingARangeField = errors.New("record is missing a range field")
 var errRecordTypeFieldIsNil = errors.New("the record's range field is nil")
 
@@ -224,7 +871,7 @@ func errRecordTypeFieldUnhandled(rt rangefield.RangeField) error {
 	return fmt.Errorf("record type of '%T' is not handled", rt)
 }
 
-func (s Store) populateNodeFromRecord(itm map[string]*dynamodb.AttributeValue, n *Node) error {
+func (s *Store) populateNodeFromRecord(itm map[string]*dynamodb.AttributeValue, n *Node) error {
 	tf, hasType := itm[fieldRange]
 	if !hasType {
 		return errRecordIsMissingARangeField
@@ -236,60 +883,67 @@ func (s Store) populateNodeFromRecord(itm map[string]*dynamodb.AttributeValue, n
 	if !ok {
 		return errRecordTypeFieldUnknown(f)
 	}
+	if isExpired(itm, s.now()) {
+		return nil
+	}
+	ttl := getRecordTTL(itm)
+	created, updated := getTimeAttribute(itm, fieldCreatedAt), getTimeAttribute(itm, fieldUpdatedAt)
 	switch rf := f.(type) {
 	case rangefield.Node:
 		n.ID = *itm[fieldID].S
+		_, n.Deleted = itm[fieldDeleted]
+		n.TTL = ttl
+		n.CreatedAt = created
+		n.UpdatedAt = latest(n.UpdatedAt, updated)
 		return nil
 	case rangefield.NodeData:
-		typeName := *itm[fieldRecordDataType].S
-		f, ok := s.DataTypes[typeName]
-		if !ok {
-			f = func() interface{} { return &map[string]interface{}{} }
-		}
-		v := f()
-		err := s.putData(itm, v)
+		n.UpdatedAt = latest(n.UpdatedAt, updated)
+		typeName, v, err := s.getData(itm)
 		n.Data[typeName] = v
 		return err
+	case rangefield.NodeLabel:
+		n.Labels = append(n.Labels, rf.Label)
+		return nil
 	case rangefield.Child:
-		if e := n.GetChild(rf.Child); e == nil {
-			n.Children = append(n.Children, NewEdge(rf.Child))
+		e := n.GetLabelledChild(rf.Child, rf.Label)
+		if e == nil {
+			e = NewEdge(rf.Child).WithLabel(rf.Label)
+			n.Children = append(n.Children, e)
 		}
+		e.CreatedAt = created
+		e.UpdatedAt = latest(e.UpdatedAt, updated)
+		e.TTL = ttl
 		return nil
 	case rangefield.ChildData:
-		e := n.GetChild(rf.Child)
+		e := n.GetLabelledChild(rf.Child, rf.Label)
 		if e == nil {
-			e = NewEdge(rf.Child)
+			e = NewEdge(rf.Child).WithLabel(rf.Label)
 			n.Children = append(n.Children, e)
 		}
+		e.UpdatedAt = latest(e.UpdatedAt, updated)
 
-		typeName := *itm[fieldRecordDataType].S
-		f, ok := s.DataTypes[typeName]
-		if !ok {
-			f = func() interface{} { return &map[string]interface{}{} }
-		}
-		v := f()
-		err := s.putData(itm, v)
+		typeName, v, err := s.getData(itm)
 		e.Data[typeName] = v
 		return err
 	case rangefield.Parent:
-		if e := n.GetParent(rf.Parent); e == nil {
-			n.Parents = append(n.Parents, NewEdge(rf.Parent))
+		e := n.GetLabelledParent(rf.Parent, rf.Label)
+		if e == nil {
+			e = NewEdge(rf.Parent).WithLabel(rf.Label)
+			n.Parents = append(n.Parents, e)
 		}
+		e.CreatedAt = created
+		e.UpdatedAt = latest(e.UpdatedAt, updated)
+		e.TTL = ttl
 		return nil
 	case rangefield.ParentData:
-		e := n.GetParent(rf.Parent)
+		e := n.GetLabelledParent(rf.Parent, rf.Label)
 		if e == nil {
-			e = NewEdge(rf.Parent)
+			e = NewEdge(rf.Parent).WithLabel(rf.Label)
 			n.Parents = append(n.Parents, e)
 		}
+		e.UpdatedAt = latest(e.UpdatedAt, updated)
 
-		typeName := *itm[fieldRecordDataType].S
-		f, ok := s.DataTypes[typeName]
-		if !ok {
-			f = func() interface{} { return &map[string]interface{}{} }
-		}
-		v := f()
-		err := s.putData(itm, v)
+		typeName, v, err := s.getData(itm)
 		e.Data[typeName] = v
 		return err
 	default:
@@ -297,81 +951,563 @@ func (s Store) populateNodeFromRecord(itm map[string]*dynamodb.AttributeValue, n
 	}
 }
 
-func (s Store) putData(itm map[string]*dynamodb.AttributeValue, into interface{}) (err error) {
-	delete(itm, fieldID)
-	delete(itm, fieldRange)
+// getData unmarshals a data record into a new value of its registered data type, or into a map if
+// the data type isn't registered. Data stored under an alias of a data type is returned using the
+// data type's name.
+func (s *Store) getData(itm map[string]*dynamodb.AttributeValue) (typeName string, v interface{}, err error) {
+	typeName = *itm[fieldRecordDataType].S
+	if name, isAlias := s.DataTypeAliases[typeName]; isAlias {
+		typeName = name
+	}
+	f, ok := s.DataTypes[typeName]
+	if !ok {
+		f = func() interface{} { return &map[string]interface{}{} }
+	}
+	v = f()
+	err = s.putData(itm, typeName, v)
+	return
+}
+
+func (s *Store) putData(itm map[string]*dynamodb.AttributeValue, dataType string, into interface{}) (err error) {
+	if err = DecompressRecord(itm); err != nil {
+		return
+	}
+	delete(itm, fieldID)
+	delete(itm, fieldRange)
 	delete(itm, fieldRecordDataType)
-	err = dynamodbattribute.UnmarshalMap(itm, into)
+	delete(itm, fieldCreatedAt)
+	delete(itm, fieldUpdatedAt)
+	delete(itm, TTLAttributeName)
+	err = s.encoding(dataType).Unmarshal(itm, into)
 	return
 }
 
-// Get retrieves data from DynamoDB.
+// GetOptions control which nodes and records are returned by GetWithOptions.
+type GetOptions struct {
+	// IncludeDeleted returns nodes which have been soft deleted.
+	IncludeDeleted bool
+	// Data loads the node's data records and labels.
+	Data bool
+	// Children loads the node's child edges and their data.
+	Children bool
+	// Parents loads the node's parent edges and their data.
+	Parents bool
+
+	// keysOnly reads just enough of each record to find its key, when all of the records are read.
+	keysOnly bool
+}
+
+// keyAttributes are the attributes read when only the keys of a node's records are needed. The data
+// type is needed to add data records to the node.
+var keyAttributes = []string{fieldID, fieldRange, fieldRecordDataType}
+
+// allRecords are the options used by Get, which loads every record of a node.
+var allRecords = GetOptions{Data: true, Children: true, Parents: true}
+
+// includingDeleted returns a copy of the options which also returns soft deleted nodes.
+func (opts GetOptions) includingDeleted() GetOptions {
+	opts.IncludeDeleted = true
+	return opts
+}
+
+// keys returns a copy of the options which only reads the keys of the records, e.g. to delete them.
+// The data of the returned node is empty.
+func (opts GetOptions) keys() GetOptions {
+	opts.keysOnly = true
+	return opts
+}
+
+// Get retrieves a node, along with its data and edges, from DynamoDB.
 func (s *Store) Get(id string) (n Node, ok bool, err error) {
+	return s.GetWithOptions(id, allRecords)
+}
+
+// GetWithOptions retrieves a node from DynamoDB, loading only the records selected by the options.
+// The node record is always read. Each other kind of record is read using a begins_with condition
+// on the range key, so callers only pay for the records they need.
+func (s *Store) GetWithOptions(id string, opts GetOptions) (n Node, ok bool, err error) {
+	n, ok, cc, err := s.get(id, opts)
+	s.updateCapacityStats(cc)
+	return
+}
+
+func (s *Store) get(id string, opts GetOptions) (n Node, ok bool, cc db.ConsumedCapacity, err error) {
+	n, _, ok, cc, err = s.getWithRanges(id, opts)
+	return
+}
+
+// getWithRanges reads a node, and returns the range keys of the records that it was read from, as
+// they're stored, i.e. without resolving the aliases of data types.
+func (s *Store) getWithRanges(id string, opts GetOptions) (n Node, ranges []rangefield.RangeField, ok bool, cc db.ConsumedCapacity, err error) {
 	if id == "" {
 		return
 	}
-	items, cc, qErr := s.Client.QueryByID(fieldID, id)
-	if qErr != nil {
-		err = qErr
+	items, cc, err := s.getRecords(id, opts)
+	if err != nil {
 		return
 	}
-	s.updateCapacityStats(cc)
 	n = NewNode("")
 	for _, itm := range items {
+		// Reading data removes the key from the record.
+		if rf, rfOK := decodeRangeField(itm); rfOK {
+			ranges = append(ranges, rf)
+		}
 		err = s.populateNodeFromRecord(itm, &n)
 		if err != nil {
 			return
 		}
 	}
+	if n.Deleted && !opts.IncludeDeleted {
+		n = NewNode("")
+	}
 	ok = len(n.ID) > 0
 	return
 }
 
-// Delete a node.
-func (s *Store) Delete(id string) (err error) {
-	// Get the IDs.
-	n, ok, err := s.Get(id)
+// getRecords reads the records of a node selected by the options. If all records are required,
+// they're read with a single query.
+func (s *Store) getRecords(id string, opts GetOptions) (items []map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error) {
+	if opts.Data && opts.Children && opts.Parents {
+		if opts.keysOnly {
+			return s.Client.QueryByID(s.context(), fieldID, id, keyAttributes...)
+		}
+		return s.Client.QueryByID(s.context(), fieldID, id)
+	}
+	var prefixes []string
+	if opts.Data {
+		// The node prefix matches the node record, its data and its labels.
+		prefixes = append(prefixes, rangefield.NodePrefix)
+	} else {
+		itm, itmCC, itmErr := s.Client.GetItem(s.context(), getID(id, rangefield.Node{}))
+		cc = addCapacity(cc, itmCC)
+		if itmErr != nil {
+			err = itmErr
+			return
+		}
+		if itm == nil {
+			return
+		}
+		items = append(items, itm)
+	}
+	if opts.Children {
+		prefixes = append(prefixes, rangefield.ChildPrefix)
+	}
+	if opts.Parents {
+		prefixes = append(prefixes, rangefield.ParentPrefix)
+	}
+	for _, prefix := range prefixes {
+		prefixItems, prefixCC, prefixErr := s.queryByPrefix(id, prefix)
+		cc = addCapacity(cc, prefixCC)
+		if prefixErr != nil {
+			err = prefixErr
+			return
+		}
+		items = append(items, prefixItems...)
+	}
+	return
+}
+
+func addCapacity(a, b db.ConsumedCapacity) db.ConsumedCapacity {
+	a.ConsumedCapacity += b.ConsumedCapacity
+	a.ConsumedReadCapacity += b.ConsumedReadCapacity
+	a.ConsumedWriteCapacity += b.ConsumedWriteCapacity
+	return a
+}
+
+// GetNodeOnly retrieves just the node record from DynamoDB, skipping its data and edges.
+// This is much cheaper than Get for nodes with many edges or data records.
+func (s *Store) GetNodeOnly(id string) (n Node, ok bool, err error) {
+	n, ok, err = s.getNodeRecord(id)
+	if n.Deleted {
+		n, ok = NewNode(""), false
+	}
+	return
+}
+
+func (s *Store) getNodeRecord(id string) (n Node, ok bool, err error) {
+	if id == "" {
+		return
+	}
+	itm, cc, err := s.Client.GetItem(s.context(), getID(id, rangefield.Node{}))
+	if err != nil {
+		return
+	}
+	s.updateCapacityStats(cc)
+	if itm == nil {
+		return
+	}
+	n = NewNode("")
+	err = s.populateNodeFromRecord(itm, &n)
 	if err != nil {
 		return
 	}
+	ok = len(n.ID) > 0
+	return
+}
+
+// GetChildren retrieves a page of up to limit child edges of a node, without loading the rest of
+// the node. A limit of zero returns all of the children. To get the next page, pass the returned
+// next cursor back in, starting with an empty cursor. When there are no more children, next is empty.
+// The edges only contain the IDs and labels of the children, use GetEdge to retrieve edge data.
+func (s *Store) GetChildren(id string, limit int, cursor string) (children []*Edge, next string, err error) {
+	return s.getChildren(id, rangefield.ChildPrefix, func(rangefield.Child) bool { return true }, limit, cursor)
+}
+
+// GetChildrenByLabel retrieves a page of up to limit child edges of a node which have the label.
+// Paging works in the same way as GetChildren.
+func (s *Store) GetChildrenByLabel(id, label string, limit int, cursor string) (children []*Edge, next string, err error) {
+	if label == "" {
+		err = ErrMissingLabel
+		return
+	}
+	matchesLabel := func(c rangefield.Child) bool { return c.Label == label }
+	return s.getChildren(id, rangefield.ChildLabelPrefix(label), matchesLabel, limit, cursor)
+}
+
+// GetChildrenWithPrefix retrieves the unlabelled child edges of a node whose child IDs begin with the
+// prefix, e.g. "2024/01/" to get the children of a node which are named by date. Only the matching
+// records are read.
+func (s *Store) GetChildrenWithPrefix(id, prefix string) (children []*Edge, err error) {
+	hasPrefix := func(c rangefield.Child) bool { return c.Label == "" && strings.HasPrefix(c.Child, prefix) }
+	children, _, err = s.getChildren(id, rangefield.ChildIDPrefix(prefix), hasPrefix, 0, "")
+	return
+}
+
+func (s *Store) getChildren(id, prefix string, include func(rangefield.Child) bool, limit int, cursor string) (children []*Edge, next string, err error) {
+	if id == "" {
+		err = ErrMissingNodeID
+		return
+	}
+	startKey, err := decodeCursor(id, cursor)
+	if err != nil {
+		return
+	}
+	for {
+		var pageLimit int64
+		if limit > 0 {
+			pageLimit = int64(limit - len(children))
+		}
+		items, lastEvaluatedKey, cc, qErr := s.Client.QueryPage(s.context(), fieldID, id, fieldRange, prefix, pageLimit, startKey)
+		if qErr != nil {
+			err = qErr
+			return
+		}
+		s.updateCapacityStats(cc)
+		for _, itm := range items {
+			rf, ok := decodeRangeField(itm)
+			if !ok || isExpired(itm, s.now()) {
+				continue
+			}
+			if c, isChild := rf.(rangefield.Child); isChild && include(c) {
+				children = append(children, NewEdge(c.Child).WithLabel(c.Label))
+			}
+		}
+		startKey = lastEvaluatedKey
+		if startKey == nil || (limit > 0 && len(children) >= limit) {
+			break
+		}
+	}
+	next = encodeCursor(startKey)
+	return
+}
+
+// GetEdge retrieves a single unlabelled edge from a parent to a child, including the edge's data, without
+// loading the rest of the parent node.
+func (s *Store) GetEdge(parent, child string) (e *Edge, ok bool, err error) {
+	if parent == "" || child == "" {
+		err = ErrMissingNodeID
+		return
+	}
+	// The prefix also matches children whose IDs start with the child's ID, and labelled edges whose
+	// label is the child's ID, so filter them out.
+	items, cc, err := s.queryByPrefix(parent, rangefield.Child{Child: child}.Encode())
+	s.updateCapacityStats(cc)
+	if err != nil {
+		return
+	}
+	n := NewNode(parent)
+	for _, itm := range items {
+		rf, rfOK := decodeRangeField(itm)
+		if !rfOK {
+			continue
+		}
+		switch rf := rf.(type) {
+		case rangefield.Child:
+			if rf.Child != child || rf.Label != "" {
+				continue
+			}
+		case rangefield.ChildData:
+			if rf.Child != child || rf.Label != "" {
+				continue
+			}
+		default:
+			continue
+		}
+		err = s.populateNodeFromRecord(itm, &n)
+		if err != nil {
+			return
+		}
+	}
+	e = n.GetLabelledChild(child, "")
+	ok = e != nil
+	return
+}
+
+// queryByPrefix returns all of the records of a node whose range field begins with the prefix.
+func (s *Store) queryByPrefix(id, rangePrefix string) (items []map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error) {
+	return s.Client.QueryByIDAndRangePrefix(s.context(), fieldID, id, fieldRange, rangePrefix)
+}
+
+func decodeRangeField(itm map[string]*dynamodb.AttributeValue) (f rangefield.RangeField, ok bool) {
+	tf, hasType := itm[fieldRange]
+	if !hasType || tf.S == nil {
+		return
+	}
+	return rangefield.Decode(*tf.S)
+}
+
+// encodeCursor converts the last evaluated key of a query into a cursor.
+func encodeCursor(lastEvaluatedKey map[string]*dynamodb.AttributeValue) string {
+	if lastEvaluatedKey == nil {
+		return ""
+	}
+	rng, ok := lastEvaluatedKey[fieldRange]
+	if !ok || rng.S == nil {
+		return ""
+	}
+	return *rng.S
+}
+
+// decodeCursor converts a cursor into the start key of a query.
+func decodeCursor(id, cursor string) (startKey map[string]*dynamodb.AttributeValue, err error) {
+	if cursor == "" {
+		return
+	}
+	rf, ok := rangefield.Decode(cursor)
 	if !ok {
+		err = ErrInvalidCursor
 		return
 	}
+	startKey = getID(id, rf)
+	return
+}
 
-	keysToDelete := []map[string]*dynamodb.AttributeValue{
-		getID(n.ID, rangefield.Node{}),
+// Exists returns true if the node exists. Only the key of the node record, and the attributes which
+// mark it as deleted or expired, are read, so it's cheap to call regardless of the size of the node.
+func (s *Store) Exists(id string) (ok bool, err error) {
+	if id == "" {
+		return
 	}
-	for dt := range n.Data {
-		keysToDelete = append(keysToDelete,
-			getID(n.ID, rangefield.NodeData{DataType: dt}))
+	itm, cc, err := s.Client.GetItem(s.context(), getID(id, rangefield.Node{}), fieldID, fieldRange, fieldDeleted, TTLAttributeName)
+	if err != nil {
+		return
 	}
-	for _, e := range n.Children {
-		// Delete child and parent records.
-		keysToDelete = append(keysToDelete,
-			getID(n.ID, rangefield.Child{Child: e.ID}),
-			getID(e.ID, rangefield.Parent{Parent: n.ID}))
+	s.updateCapacityStats(cc)
+	if itm == nil {
+		return
+	}
+	if _, deleted := itm[fieldDeleted]; deleted || isExpired(itm, s.now()) {
+		return
+	}
+	ok = true
+	return
+}
 
-		// Delete data records.
-		for dataKey := range e.Data {
-			keysToDelete = append(keysToDelete,
-				getID(n.ID, rangefield.ChildData{Child: e.ID, DataType: dataKey}),
-				getID(e.ID, rangefield.ParentData{Parent: n.ID, DataType: dataKey}))
+// ListNodes returns a page of up to limit node IDs from the store, in no particular order. A limit
+// of zero returns all of the node IDs, which requires reading the whole table. To get the next page,
+// pass the returned next cursor back in, starting with an empty cursor. When there are no more
+// nodes, next is empty.
+func (s *Store) ListNodes(limit int, cursor string) (ids []string, next string, err error) {
+	var startKey map[string]*dynamodb.AttributeValue
+	if cursor != "" {
+		startKey = getID(cursor, rangefield.Node{})
+	}
+	for {
+		items, lastEvaluatedKey, cc, sErr := s.nodeRecordsPage(int64(limit), startKey)
+		if sErr != nil {
+			err = sErr
+			return
+		}
+		s.updateCapacityStats(cc)
+		for _, itm := range items {
+			if _, deleted := itm[fieldDeleted]; deleted || isExpired(itm, s.now()) {
+				continue
+			}
+			if id, ok := itm[fieldID]; ok && id.S != nil {
+				ids = append(ids, *id.S)
+			}
+		}
+		if limit > 0 && len(ids) >= limit {
+			// The cursor is always the ID of the last node returned, so that pages don't overlap.
+			truncated := len(ids) > limit
+			ids = ids[:limit]
+			if truncated || lastEvaluatedKey != nil {
+				next = ids[limit-1]
+			}
+			return
+		}
+		if lastEvaluatedKey == nil {
+			return
 		}
+		startKey = lastEvaluatedKey
 	}
-	for _, e := range n.Parents {
-		keysToDelete = append(keysToDelete,
-			getID(n.ID, rangefield.Parent{Parent: e.ID}),
-			getID(e.ID, rangefield.Child{Child: n.ID}))
+}
 
-		// Delete data records.
-		for dataKey := range e.Data {
+// nodeRecordsPage returns a page of node records, using the RangeIndex if it's configured, or a scan
+// of the table if not.
+func (s *Store) nodeRecordsPage(limit int64, startKey map[string]*dynamodb.AttributeValue) (items []map[string]*dynamodb.AttributeValue, lastEvaluatedKey map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error) {
+	if s.RangeIndex != "" {
+		return s.Client.QueryIndexPage(s.context(), s.RangeIndex, fieldRange, rangefield.Node{}.Encode(), limit, startKey)
+	}
+	return s.Client.ScanPage(s.context(), fieldRange, rangefield.Node{}.Encode(), limit, startKey)
+}
+
+// getManyConcurrency is the maximum number of queries that GetMany runs at once.
+const getManyConcurrency = 10
+
+// GetMany retrieves multiple nodes from DynamoDB. The node records are read in batches first, so
+// that the rest of the records are only queried for the nodes which exist, with the queries run in
+// parallel. The nodes which exist are returned in the order of the requested IDs.
+func (s *Store) GetMany(ids ...string) (nodes []Node, err error) {
+	found, err := s.existingNodeIDs(ids)
+	if err != nil {
+		return
+	}
+	type result struct {
+		n   Node
+		ok  bool
+		cc  db.ConsumedCapacity
+		err error
+	}
+	results := make([]result, len(found))
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, getManyConcurrency)
+	for i, id := range found {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(r *result, nodeID string) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			r.n, r.ok, r.cc, r.err = s.get(nodeID, allRecords)
+		}(&results[i], id)
+	}
+	wg.Wait()
+
+	byID := make(map[string]Node, len(found))
+	for _, r := range results {
+		s.updateCapacityStats(r.cc)
+		if r.err != nil && err == nil {
+			err = r.err
+		}
+		if r.ok {
+			byID[r.n.ID] = r.n
+		}
+	}
+	if err != nil {
+		return
+	}
+	for _, id := range ids {
+		if n, ok := byID[id]; ok {
+			nodes = append(nodes, n)
+		}
+	}
+	return
+}
+
+// existingNodeIDs reads the node records of the IDs in batches, and returns the IDs of the nodes
+// which exist, and aren't deleted or expired, without duplicates.
+func (s *Store) existingNodeIDs(ids []string) (found []string, err error) {
+	requested := make(map[string]bool, len(ids))
+	var keys []map[string]*dynamodb.AttributeValue
+	for _, id := range ids {
+		if id == "" || requested[id] {
+			continue
+		}
+		requested[id] = true
+		keys = append(keys, getID(id, rangefield.Node{}))
+	}
+	if len(keys) == 0 {
+		return
+	}
+	items, cc, err := s.Client.BatchGet(s.context(), keys)
+	s.updateCapacityStats(cc)
+	if err != nil {
+		return
+	}
+	exists := make(map[string]bool, len(items))
+	for _, itm := range items {
+		if _, deleted := itm[fieldDeleted]; deleted || isExpired(itm, s.now()) {
+			continue
+		}
+		if id, ok := itm[fieldID]; ok && id.S != nil {
+			exists[*id.S] = true
+		}
+	}
+	for _, id := range ids {
+		if exists[id] {
+			found = append(found, id)
+			delete(exists, id)
+		}
+	}
+	return
+}
+
+// Delete a node. If the Store's SoftDelete is enabled, the node is marked as deleted instead of
+// being removed.
+func (s *Store) Delete(id string) (err error) {
+	if s.SoftDelete {
+		return s.setDeleted(id, true)
+	}
+	// Get the keys.
+	_, ranges, ok, cc, err := s.getWithRanges(id, allRecords.includingDeleted().keys())
+	s.updateCapacityStats(cc)
+	if err != nil {
+		return
+	}
+	if !ok {
+		return
+	}
+	return s.deleteNodeRecords(id, ranges)
+}
+
+// deleteNodeRecords deletes the records of a node with the range keys read by getWithRanges,
+// including the other side of its edges. The keys are built from the stored range keys, rather than
+// from the node, so that data stored under an alias of its data type is deleted too.
+func (s *Store) deleteNodeRecords(id string, ranges []rangefield.RangeField) (err error) {
+	keysToDelete := []map[string]*dynamodb.AttributeValue{
+		getID(id, rangefield.Node{}),
+	}
+	for _, rf := range ranges {
+		switch rf := rf.(type) {
+		case rangefield.Node:
+			// The node record is deleted first.
+		case rangefield.Child:
+			// Delete child and parent records.
+			keysToDelete = append(keysToDelete,
+				getID(id, rf),
+				getID(rf.Child, rangefield.Parent{Parent: id, Label: rf.Label}))
+		case rangefield.ChildData:
+			keysToDelete = append(keysToDelete,
+				getID(id, rf),
+				getID(rf.Child, rangefield.ParentData{Parent: id, DataType: rf.DataType, Label: rf.Label}))
+		case rangefield.Parent:
 			keysToDelete = append(keysToDelete,
-				getID(n.ID, rangefield.ParentData{Parent: e.ID, DataType: dataKey}),
-				getID(e.ID, rangefield.ChildData{Child: n.ID, DataType: dataKey}))
+				getID(id, rf),
+				getID(rf.Parent, rangefield.Child{Child: id, Label: rf.Label}))
+		case rangefield.ParentData:
+			keysToDelete = append(keysToDelete,
+				getID(id, rf),
+				getID(rf.Parent, rangefield.ChildData{Child: id, DataType: rf.DataType, Label: rf.Label}))
+		default:
+			// Data and label records.
+			keysToDelete = append(keysToDelete, getID(id, rf))
 		}
 	}
 	var cc db.ConsumedCapacity
-	cc, err = s.Client.BatchDelete(keysToDelete)
+	cc, err = s.Client.BatchDelete(s.context(), keysToDelete)
 	if err != nil {
 		return
 	}
@@ -379,12 +1515,167 @@ func (s *Store) Delete(id string) (err error) {
 	return
 }
 
-// DeleteEdge deletes an edge.
+// RenameNode changes the ID of a node, rewriting its data records and both sides of all of its
+// edges. ErrNodeNotFound is returned if the node doesn't exist, and ErrAlreadyExists if a node
+// with the new ID already exists. The node is written with its new ID before the old records are
+// deleted, so a failure part way through can leave both nodes in place, but never loses data.
+func (s *Store) RenameNode(oldID, newID string) (err error) {
+	if oldID == "" || newID == "" {
+		return ErrMissingNodeID
+	}
+	if oldID == newID {
+		return
+	}
+	n, ranges, ok, cc, err := s.getWithRanges(oldID, allRecords.includingDeleted())
+	s.updateCapacityStats(cc)
+	if err != nil {
+		return
+	}
+	if !ok {
+		return ErrNodeNotFound
+	}
+	exists, err := s.Exists(newID)
+	if err != nil {
+		return
+	}
+	if exists {
+		return ErrAlreadyExists
+	}
+
+	renamed := n
+	renamed.ID = newID
+	renamed.Children = renameEdges(n.Children, oldID, newID)
+	renamed.Parents = renameEdges(n.Parents, oldID, newID)
+	err = s.Put(renamed)
+	if err != nil {
+		return
+	}
+	if n.Deleted {
+		err = s.setDeleted(newID, true)
+		if err != nil {
+			return
+		}
+	}
+	return s.deleteNodeRecords(oldID, ranges)
+}
+
+// CloneNode copies the node with sourceID, including all of its data and labels, to a new node with
+// destID. If includeEdges is set, the source node's parent and child edges (and their data) are
+// copied too. The clone is written using Create, so ErrAlreadyExists is returned if destID is
+// already in use.
+func (s *Store) CloneNode(sourceID, destID string, includeEdges bool) (err error) {
+	if sourceID == "" || destID == "" {
+		return ErrMissingNodeID
+	}
+	n, ok, err := s.Get(sourceID)
+	if err != nil {
+		return
+	}
+	if !ok {
+		return ErrNodeNotFound
+	}
+	clone := NewNode(destID)
+	clone.Data = n.Data
+	clone.Labels = n.Labels
+	clone.TTL = n.TTL
+	if includeEdges {
+		clone.Children = resetEdgeTimestamps(renameEdges(n.Children, sourceID, destID))
+		clone.Parents = resetEdgeTimestamps(renameEdges(n.Parents, sourceID, destID))
+	}
+	return s.Create(clone)
+}
+
+// resetEdgeTimestamps clears the timestamps of copied edges, so that they're stamped as new.
+func resetEdgeTimestamps(edges []*Edge) []*Edge {
+	for _, e := range edges {
+		e.CreatedAt = time.Time{}
+		e.UpdatedAt = time.Time{}
+	}
+	return edges
+}
+
+// renameEdges copies the edges, updating any edges which point back at the node being renamed.
+func renameEdges(edges []*Edge, oldID, newID string) (renamed []*Edge) {
+	for _, e := range edges {
+		ee := *e
+		if ee.ID == oldID {
+			ee.ID = newID
+		}
+		renamed = append(renamed, &ee)
+	}
+	return
+}
+
+// Undelete restores a node which was soft deleted.
+func (s *Store) Undelete(id string) (err error) {
+	return s.setDeleted(id, false)
+}
+
+// setDeleted rewrites the node record with or without a tombstone, leaving the rest of the node's
+// records in place.
+func (s *Store) setDeleted(id string, deleted bool) (err error) {
+	n, ok, err := s.getNodeRecord(id)
+	if err != nil {
+		return
+	}
+	if !ok || n.Deleted == deleted {
+		return
+	}
+	r := newNodeRecord(id)
+	if deleted {
+		r[fieldDeleted] = newTimeAttribute(s.now())
+	}
+	records := []map[string]*dynamodb.AttributeValue{r}
+	s.stampRecords(n, records)
+	cc, err := s.Client.BatchPut(s.context(), records)
+	if err != nil {
+		return
+	}
+	s.updateCapacityStats(cc)
+	return
+}
+
+// DeleteEdge deletes an edge. If there are multiple edges between the parent and child with
+// different labels, all of them are deleted.
 func (s *Store) DeleteEdge(parent string, child string) (err error) {
+	return s.DeleteEdges(parent, child)
+}
+
+// DeleteLabelledEdge deletes the edge with the label between a parent and child, leaving any other
+// edges between them in place. An empty label deletes the unlabelled edge.
+func (s *Store) DeleteLabelledEdge(parent, child, label string) (err error) {
 	if parent == "" || child == "" {
 		return ErrMissingNodeID
 	}
-	n, ok, err := s.Get(parent)
+	return s.deleteEdges(parent, func(e *Edge) bool {
+		return e.ID == child && e.Label == label
+	})
+}
+
+// DeleteEdges deletes the edges between a parent and each of the children. The parent is only
+// read once, and all of the records are deleted in a single batch.
+func (s *Store) DeleteEdges(parent string, children ...string) (err error) {
+	if parent == "" {
+		return ErrMissingNodeID
+	}
+	toDelete := make(map[string]bool, len(children))
+	for _, child := range children {
+		if child == "" {
+			return ErrMissingNodeID
+		}
+		toDelete[child] = true
+	}
+	if len(toDelete) == 0 {
+		return
+	}
+	return s.deleteEdges(parent, func(e *Edge) bool {
+		return toDelete[e.ID]
+	})
+}
+
+// deleteEdges deletes the child edges of the parent which match.
+func (s *Store) deleteEdges(parent string, match func(e *Edge) bool) (err error) {
+	n, ok, err := s.GetWithOptions(parent, allRecords.includingDeleted().keys())
 	if err != nil {
 		return
 	}
@@ -397,23 +1688,26 @@ func (s *Store) DeleteEdge(parent string, child string) (err error) {
 
 	var keysToDelete []map[string]*dynamodb.AttributeValue
 	for _, e := range n.Children {
-		if e.ID != child {
+		if !match(e) {
 			continue
 		}
 		// Delete child and parent records.
 		keysToDelete = append(keysToDelete,
-			getID(n.ID, rangefield.Child{Child: e.ID}),
-			getID(e.ID, rangefield.Parent{Parent: n.ID}))
+			getID(n.ID, rangefield.Child{Child: e.ID, Label: e.Label}),
+			getID(e.ID, rangefield.Parent{Parent: n.ID, Label: e.Label}))
 
 		// Delete data records.
 		for dataKey := range e.Data {
 			keysToDelete = append(keysToDelete,
-				getID(n.ID, rangefield.ChildData{Child: e.ID, DataType: dataKey}),
-				getID(e.ID, rangefield.ParentData{Parent: n.ID, DataType: dataKey}))
+				getID(n.ID, rangefield.ChildData{Child: e.ID, DataType: dataKey, Label: e.Label}),
+				getID(e.ID, rangefield.ParentData{Parent: n.ID, DataType: dataKey, Label: e.Label}))
 		}
 	}
+	if len(keysToDelete) == 0 {
+		return
+	}
 	var cc db.ConsumedCapacity
-	cc, err = s.Client.BatchDelete(keysToDelete)
+	cc, err = s.Client.BatchDelete(s.context(), keysToDelete)
 	if err != nil {
 		return
 	}
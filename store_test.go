@@ -2,14 +2,22 @@ package pregel
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"reflect"
+	"sort"
 	"strconv"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/a-h/pregel/db"
+	"github.com/a-h/pregel/rangefield"
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
 )
 
@@ -18,22 +26,174 @@ func newdynamoDBClient() *dynamoDBClient {
 }
 
 type dynamoDBClient struct {
-	errorToReturn error
-	batchDeleter  func(keys []map[string]*dynamodb.AttributeValue) (db.ConsumedCapacity, error)
-	batchPutter   func(items []map[string]*dynamodb.AttributeValue) (db.ConsumedCapacity, error)
-	queryByIDer   func(idField, idValue string) (items []map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error)
+	errorToReturn        error
+	batchDeleter         func(keys []map[string]*dynamodb.AttributeValue) (db.ConsumedCapacity, error)
+	batchPutter          func(items []map[string]*dynamodb.AttributeValue) (db.ConsumedCapacity, error)
+	tryBatchPutter       func(items []map[string]*dynamodb.AttributeValue) (unprocessed []map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error)
+	putIfNotExister      func(idField string, item map[string]*dynamodb.AttributeValue) (db.ConsumedCapacity, error)
+	transactPutter       func(items []map[string]*dynamodb.AttributeValue) (db.ConsumedCapacity, error)
+	transactWriter       func(items []db.TransactWriteItem) (db.ConsumedCapacity, error)
+	transactPutIfExister func(key map[string]*dynamodb.AttributeValue, idField, excludedField string, items []map[string]*dynamodb.AttributeValue) (db.ConsumedCapacity, error)
+	updateItemer         func(key map[string]*dynamodb.AttributeValue, set map[string]*dynamodb.AttributeValue) (db.ConsumedCapacity, error)
+	batchGetter          func(keys []map[string]*dynamodb.AttributeValue) ([]map[string]*dynamodb.AttributeValue, db.ConsumedCapacity, error)
+	getItemer            func(key map[string]*dynamodb.AttributeValue) (item map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error)
+	queryByIDer          func(idField, idValue string) (items []map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error)
+	queryIndexer         func(indexName, field string, value interface{}) (items []map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error)
+	queryIndexPager      func(indexName, field string, value interface{}, limit int64, startKey map[string]*dynamodb.AttributeValue) (items []map[string]*dynamodb.AttributeValue, lastEvaluatedKey map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error)
+	scanPager            func(field, value string, limit int64, startKey map[string]*dynamodb.AttributeValue) (items []map[string]*dynamodb.AttributeValue, lastEvaluatedKey map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error)
+	scanSegmenter        func(segment, totalSegments, limit int64, startKey map[string]*dynamodb.AttributeValue) (items []map[string]*dynamodb.AttributeValue, lastEvaluatedKey map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error)
+	tableDescriber       func(ctx context.Context) (db.TableDescription, error)
+	queryPager           func(idField, idValue, rangeField, rangePrefix string, limit int64, startKey map[string]*dynamodb.AttributeValue) (items []map[string]*dynamodb.AttributeValue, lastEvaluatedKey map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error)
 }
 
-func (mdc *dynamoDBClient) BatchDelete(keys []map[string]*dynamodb.AttributeValue) (db.ConsumedCapacity, error) {
+func (mdc *dynamoDBClient) BatchDelete(ctx context.Context, keys []map[string]*dynamodb.AttributeValue) (db.ConsumedCapacity, error) {
 	return mdc.batchDeleter(keys)
 }
 
-func (mdc *dynamoDBClient) BatchPut(items []map[string]*dynamodb.AttributeValue) (db.ConsumedCapacity, error) {
+func (mdc *dynamoDBClient) BatchPut(ctx context.Context, items []map[string]*dynamodb.AttributeValue) (db.ConsumedCapacity, error) {
 	return mdc.batchPutter(items)
 }
 
-func (mdc *dynamoDBClient) QueryByID(idField, idValue string) (items []map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error) {
-	return mdc.queryByIDer(idField, idValue)
+func (mdc *dynamoDBClient) TryBatchPut(ctx context.Context, items []map[string]*dynamodb.AttributeValue) (unprocessed []map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error) {
+	return mdc.tryBatchPutter(items)
+}
+
+func (mdc *dynamoDBClient) PutIfNotExists(ctx context.Context, idField string, item map[string]*dynamodb.AttributeValue) (db.ConsumedCapacity, error) {
+	return mdc.putIfNotExister(idField, item)
+}
+
+func (mdc *dynamoDBClient) TransactPut(ctx context.Context, items []map[string]*dynamodb.AttributeValue) (db.ConsumedCapacity, error) {
+	return mdc.transactPutter(items)
+}
+
+func (mdc *dynamoDBClient) TransactWrite(ctx context.Context, items []db.TransactWriteItem) (db.ConsumedCapacity, error) {
+	return mdc.transactWriter(items)
+}
+
+func (mdc *dynamoDBClient) TransactPutIfExists(ctx context.Context, key map[string]*dynamodb.AttributeValue, idField, excludedField string, items []map[string]*dynamodb.AttributeValue) (db.ConsumedCapacity, error) {
+	return mdc.transactPutIfExister(key, idField, excludedField, items)
+}
+
+func (mdc *dynamoDBClient) UpdateItem(ctx context.Context, key map[string]*dynamodb.AttributeValue, set map[string]*dynamodb.AttributeValue) (db.ConsumedCapacity, error) {
+	return mdc.updateItemer(key, set)
+}
+
+// BatchGet uses the batchGetter, or if it isn't set, finds each key in the records returned by the
+// queryByIDer, so that tests which only mock queries can read node records in batches. Capacity
+// consumed by the queryByIDer isn't included, so that it's only counted once per query.
+func (mdc *dynamoDBClient) BatchGet(ctx context.Context, keys []map[string]*dynamodb.AttributeValue) (items []map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error) {
+	if mdc.batchGetter != nil {
+		return mdc.batchGetter(keys)
+	}
+	for _, k := range keys {
+		records, _, qErr := mdc.queryByIDer(fieldID, *k[fieldID].S)
+		if qErr != nil {
+			return nil, cc, qErr
+		}
+		for _, r := range records {
+			if r[fieldRange] != nil && reflect.DeepEqual(r[fieldRange], k[fieldRange]) {
+				items = append(items, r)
+			}
+		}
+	}
+	return
+}
+
+func (mdc *dynamoDBClient) GetItem(ctx context.Context, key map[string]*dynamodb.AttributeValue, attributes ...string) (item map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error) {
+	item, cc, err = mdc.getItemer(key)
+	if item != nil && len(attributes) > 0 {
+		item = project([]map[string]*dynamodb.AttributeValue{item}, attributes)[0]
+	}
+	return
+}
+
+func (mdc *dynamoDBClient) QueryByID(ctx context.Context, idField, idValue string, attributes ...string) (items []map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error) {
+	items, cc, err = mdc.queryByIDer(idField, idValue)
+	if len(attributes) > 0 {
+		items = project(items, attributes)
+	}
+	return
+}
+
+// QueryByIDAndRangePrefix reads every page returned by the queryPager, in the same way as DynamoDB.
+func (mdc *dynamoDBClient) QueryByIDAndRangePrefix(ctx context.Context, idField, idValue, rangeField, rangePrefix string, attributes ...string) (items []map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error) {
+	var startKey map[string]*dynamodb.AttributeValue
+	for {
+		page, lastEvaluatedKey, pageCC, qErr := mdc.queryPager(idField, idValue, rangeField, rangePrefix, 0, startKey)
+		cc = addCapacity(cc, pageCC)
+		if qErr != nil {
+			return nil, cc, qErr
+		}
+		items = append(items, page...)
+		if lastEvaluatedKey == nil {
+			break
+		}
+		startKey = lastEvaluatedKey
+	}
+	if len(attributes) > 0 {
+		items = project(items, attributes)
+	}
+	return
+}
+
+// project simulates a DynamoDB projection expression, returning only the attributes of the items.
+func project(items []map[string]*dynamodb.AttributeValue, attributes []string) (projected []map[string]*dynamodb.AttributeValue) {
+	for _, itm := range items {
+		p := make(map[string]*dynamodb.AttributeValue)
+		for _, a := range attributes {
+			if v, ok := itm[a]; ok {
+				p[a] = v
+			}
+		}
+		projected = append(projected, p)
+	}
+	return
+}
+
+func (mdc *dynamoDBClient) QueryIndex(ctx context.Context, indexName, field string, value interface{}) (items []map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error) {
+	return mdc.queryIndexer(indexName, field, value)
+}
+
+func (mdc *dynamoDBClient) QueryIndexPage(ctx context.Context, indexName, field string, value interface{}, limit int64, startKey map[string]*dynamodb.AttributeValue) (items []map[string]*dynamodb.AttributeValue, lastEvaluatedKey map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error) {
+	return mdc.queryIndexPager(indexName, field, value, limit, startKey)
+}
+
+func (mdc *dynamoDBClient) ScanPage(ctx context.Context, field, value string, limit int64, startKey map[string]*dynamodb.AttributeValue) (items []map[string]*dynamodb.AttributeValue, lastEvaluatedKey map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error) {
+	return mdc.scanPager(field, value, limit, startKey)
+}
+
+func (mdc *dynamoDBClient) ScanSegment(ctx context.Context, segment, totalSegments, limit int64, startKey map[string]*dynamodb.AttributeValue) (items []map[string]*dynamodb.AttributeValue, lastEvaluatedKey map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error) {
+	return mdc.scanSegmenter(segment, totalSegments, limit, startKey)
+}
+
+func (mdc *dynamoDBClient) QueryPage(ctx context.Context, idField, idValue, rangeField, rangePrefix string, limit int64, startKey map[string]*dynamodb.AttributeValue) (items []map[string]*dynamodb.AttributeValue, lastEvaluatedKey map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error) {
+	return mdc.queryPager(idField, idValue, rangeField, rangePrefix, limit, startKey)
+}
+
+func (mdc *dynamoDBClient) DescribeTable(ctx context.Context) (db.TableDescription, error) {
+	return mdc.tableDescriber(ctx)
+}
+
+// pageRecords simulates DynamoDB paging over records which are sorted by their range field.
+func pageRecords(records []map[string]*dynamodb.AttributeValue, rangePrefix string, limit int64, startKey map[string]*dynamodb.AttributeValue) (items []map[string]*dynamodb.AttributeValue, lastEvaluatedKey map[string]*dynamodb.AttributeValue) {
+	for _, r := range records {
+		rng := *r[fieldRange].S
+		if !strings.HasPrefix(rng, rangePrefix) {
+			continue
+		}
+		if startKey != nil && rng <= *startKey[fieldRange].S {
+			continue
+		}
+		if limit > 0 && int64(len(items)) == limit {
+			lastEvaluatedKey = map[string]*dynamodb.AttributeValue{
+				fieldID:    items[len(items)-1][fieldID],
+				fieldRange: items[len(items)-1][fieldRange],
+			}
+			return
+		}
+		items = append(items, r)
+	}
+	return
 }
 
 type testNodeData struct {
@@ -276,6 +436,122 @@ func TestStorePut(t *testing.T) {
 	}
 }
 
+func TestStoreCreate(t *testing.T) {
+	tests := []struct {
+		name              string
+		node              Node
+		putIfNotExistsErr error
+		batchPutErr       error
+		expectedNodeItem  map[string]*dynamodb.AttributeValue
+		expectedItems     []map[string]*dynamodb.AttributeValue
+		expectedErr       error
+	}{
+		{
+			name:        "Missing node ID results in an error",
+			node:        NewNode(""),
+			expectedErr: ErrMissingNodeID,
+		},
+		{
+			name:              "Existing nodes result in an error",
+			node:              NewNode("id").WithChildren(NewEdge("child")),
+			putIfNotExistsErr: db.ErrConditionalCheckFailed,
+			expectedNodeItem: map[string]*dynamodb.AttributeValue{
+				"id":  {S: aws.String("id")},
+				"rng": {S: aws.String("node")},
+			},
+			expectedErr: ErrAlreadyExists,
+		},
+		{
+			name:              "Database errors are returned",
+			node:              NewNode("id"),
+			putIfNotExistsErr: errTestDatabaseFailure,
+			expectedNodeItem: map[string]*dynamodb.AttributeValue{
+				"id":  {S: aws.String("id")},
+				"rng": {S: aws.String("node")},
+			},
+			expectedErr: errTestDatabaseFailure,
+		},
+		{
+			name: "A node without edges or data only writes the node record",
+			node: NewNode("id"),
+			expectedNodeItem: map[string]*dynamodb.AttributeValue{
+				"id":  {S: aws.String("id")},
+				"rng": {S: aws.String("node")},
+			},
+		},
+		{
+			name: "The edges are written after the node record",
+			node: NewNode("id").WithChildren(NewEdge("child")),
+			expectedNodeItem: map[string]*dynamodb.AttributeValue{
+				"id":  {S: aws.String("id")},
+				"rng": {S: aws.String("node")},
+			},
+			expectedItems: []map[string]*dynamodb.AttributeValue{
+				{
+					"id":  {S: aws.String("id")},
+					"rng": {S: aws.String("child/child")},
+				},
+				{
+					"id":  {S: aws.String("child")},
+					"rng": {S: aws.String("parent/id")},
+				},
+			},
+		},
+		{
+			name:        "Batch put errors are returned",
+			node:        NewNode("id").WithChildren(NewEdge("child")),
+			batchPutErr: errTestDatabaseFailure,
+			expectedNodeItem: map[string]*dynamodb.AttributeValue{
+				"id":  {S: aws.String("id")},
+				"rng": {S: aws.String("node")},
+			},
+			expectedItems: []map[string]*dynamodb.AttributeValue{
+				{
+					"id":  {S: aws.String("id")},
+					"rng": {S: aws.String("child/child")},
+				},
+				{
+					"id":  {S: aws.String("child")},
+					"rng": {S: aws.String("parent/id")},
+				},
+			},
+			expectedErr: errTestDatabaseFailure,
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			client := newdynamoDBClient()
+			var actualNodeItem map[string]*dynamodb.AttributeValue
+			client.putIfNotExister = func(idField string, item map[string]*dynamodb.AttributeValue) (db.ConsumedCapacity, error) {
+				if idField != "id" {
+					t.Errorf("unexpected idField value of '%s'", idField)
+				}
+				actualNodeItem = item
+				return db.ConsumedCapacity{ConsumedCapacity: 1}, test.putIfNotExistsErr
+			}
+			var actualItems []map[string]*dynamodb.AttributeValue
+			client.batchPutter = func(items []map[string]*dynamodb.AttributeValue) (db.ConsumedCapacity, error) {
+				actualItems = items
+				return db.ConsumedCapacity{ConsumedCapacity: 1}, test.batchPutErr
+			}
+			s := NewStoreWithClient(client)
+			err := s.Create(test.node)
+			if err != test.expectedErr {
+				t.Errorf("expected err %v, got %v", test.expectedErr, err)
+			}
+			if !reflect.DeepEqual(actualNodeItem, test.expectedNodeItem) {
+				t.Errorf("expected node item %+v, got %+v", test.expectedNodeItem, actualNodeItem)
+			}
+			if !reflect.DeepEqual(actualItems, test.expectedItems) {
+				t.Errorf("\nexpected:\n%s\n\ngot:\n%s\n", format(test.expectedItems), format(actualItems))
+			}
+		})
+	}
+}
+
 func TestStorePutNodeData(t *testing.T) {
 	tests := []struct {
 		name string
@@ -381,11 +657,122 @@ func TestStorePutNodeData(t *testing.T) {
 	}
 }
 
+func TestStorePutNodeDataMustExist(t *testing.T) {
+	tests := []struct {
+		name                  string
+		transactPutIfExistErr error
+		expectedErr           error
+	}{
+		{
+			name: "Data is written if the node exists",
+		},
+		{
+			name:                  "Missing or deleted nodes result in ErrNodeNotFound",
+			transactPutIfExistErr: db.ErrConditionalCheckFailed,
+			expectedErr:           ErrNodeNotFound,
+		},
+		{
+			name:                  "Database errors are returned",
+			transactPutIfExistErr: errTestDatabaseFailure,
+			expectedErr:           errTestDatabaseFailure,
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			client := newdynamoDBClient()
+			var actualKey map[string]*dynamodb.AttributeValue
+			var actualIDField, actualExcludedField string
+			var actualItems []map[string]*dynamodb.AttributeValue
+			client.transactPutIfExister = func(key map[string]*dynamodb.AttributeValue, idField, excludedField string, items []map[string]*dynamodb.AttributeValue) (db.ConsumedCapacity, error) {
+				actualKey, actualIDField, actualExcludedField, actualItems = key, idField, excludedField, items
+				return db.ConsumedCapacity{}, test.transactPutIfExistErr
+			}
+			s := NewStoreWithClient(client)
+			s.NodeDataMode = NodeDataMustExist
+			err := s.PutNodeData("nodeA", NewData(testNodeData{ExtraAttribute: "ExtraValue"}))
+			if err != test.expectedErr {
+				t.Errorf("expected err %v, got %v", test.expectedErr, err)
+			}
+			if expectedKey := getID("nodeA", rangefield.Node{}); !reflect.DeepEqual(actualKey, expectedKey) {
+				t.Errorf("\nexpected key:\n%s\n\ngot:\n%s\n", format([]map[string]*dynamodb.AttributeValue{expectedKey}), format([]map[string]*dynamodb.AttributeValue{actualKey}))
+			}
+			if actualIDField != "id" || actualExcludedField != "_d" {
+				t.Errorf("expected the condition on fields id and _d, got %q and %q", actualIDField, actualExcludedField)
+			}
+			if len(actualItems) != 1 || aws.StringValue(actualItems[0]["rng"].S) != "node/data/testNodeData" {
+				t.Errorf("expected only the data record to be written, got:\n%s", format(actualItems))
+			}
+		})
+	}
+}
+
+func TestStorePutTransaction(t *testing.T) {
+	manyEdges := NewNode("order")
+	for i := 0; i < 60; i++ {
+		manyEdges = manyEdges.WithChildren(NewEdge("item" + strconv.Itoa(i)))
+	}
+	tests := []struct {
+		name               string
+		nodes              []Node
+		transactPutErr     error
+		expectedChunkSizes []int
+		expectedErr        error
+	}{
+		{
+			name:        "Missing node IDs result in an error",
+			nodes:       []Node{NewNode("order"), NewNode("")},
+			expectedErr: ErrMissingNodeID,
+		},
+		{
+			name:               "Small sets of nodes are written in a single transaction",
+			nodes:              []Node{NewNode("order").WithChildren(NewEdge("item")), NewNode("item")},
+			expectedChunkSizes: []int{4},
+		},
+		{
+			name:               "Large sets of records are split into multiple transactions",
+			nodes:              []Node{manyEdges},
+			expectedChunkSizes: []int{100, 21},
+		},
+		{
+			name:               "Database errors are returned",
+			nodes:              []Node{manyEdges},
+			transactPutErr:     errTestDatabaseFailure,
+			expectedChunkSizes: []int{100},
+			expectedErr:        errTestDatabaseFailure,
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			client := newdynamoDBClient()
+			var actualChunkSizes []int
+			client.transactPutter = func(items []map[string]*dynamodb.AttributeValue) (db.ConsumedCapacity, error) {
+				actualChunkSizes = append(actualChunkSizes, len(items))
+				return db.ConsumedCapacity{ConsumedCapacity: 1}, test.transactPutErr
+			}
+			s := NewStoreWithClient(client)
+			err := s.PutTransaction(test.nodes...)
+			if err != test.expectedErr {
+				t.Errorf("expected err %v, got %v", test.expectedErr, err)
+			}
+			if !reflect.DeepEqual(actualChunkSizes, test.expectedChunkSizes) {
+				t.Errorf("expected transactions of %v records, got %v", test.expectedChunkSizes, actualChunkSizes)
+			}
+		})
+	}
+}
+
 func TestStorePutEdge(t *testing.T) {
 	tests := []struct {
 		name                 string
 		parent               string
 		edge                 *Edge
+		transactional        bool
 		expectedItems        []map[string]*dynamodb.AttributeValue
 		batchPutterOutputErr error
 		expectedErr          error
@@ -418,6 +805,22 @@ func TestStorePutEdge(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:          "Transactional edges are written in a single transaction",
+			parent:        "parentNode",
+			edge:          NewEdge("childNode"),
+			transactional: true,
+			expectedItems: []map[string]*dynamodb.AttributeValue{
+				{
+					"id":  {S: aws.String("parentNode")},
+					"rng": {S: aws.String("child/childNode")},
+				},
+				{
+					"id":  {S: aws.String("childNode")},
+					"rng": {S: aws.String("parent/parentNode")},
+				},
+			},
+		},
 		{
 			name:   "Database errors are returned",
 			parent: "parentNode",
@@ -513,7 +916,15 @@ func TestStorePutEdge(t *testing.T) {
 				actualItems = items
 				return db.ConsumedCapacity{ConsumedCapacity: 1, ConsumedReadCapacity: 3, ConsumedWriteCapacity: 5}, test.batchPutterOutputErr
 			}
+			client.transactPutter = func(items []map[string]*dynamodb.AttributeValue) (db.ConsumedCapacity, error) {
+				if !test.transactional {
+					t.Errorf("unexpected transaction")
+				}
+				actualItems = items
+				return db.ConsumedCapacity{ConsumedCapacity: 1, ConsumedReadCapacity: 3, ConsumedWriteCapacity: 5}, nil
+			}
 			s := NewStoreWithClient(client)
+			s.TransactionalEdges = test.transactional
 			err := s.PutEdges(test.parent, test.edge)
 			if err != test.expectedErr {
 				t.Errorf("expected err %v, got %v", test.expectedErr, err)
@@ -948,31 +1359,1100 @@ func TestStoreGet(t *testing.T) {
 	}
 }
 
-func TestStoreDelete(t *testing.T) {
+func TestStoreTimestamps(t *testing.T) {
+	created := time.Date(2018, time.January, 1, 0, 0, 0, 0, time.UTC)
+	now := time.Date(2019, time.February, 2, 12, 30, 0, 0, time.UTC)
+	createdValue := aws.String("2018-01-01T00:00:00Z")
+	nowValue := aws.String("2019-02-02T12:30:00Z")
+
+	client := newdynamoDBClient()
+	var actualItems []map[string]*dynamodb.AttributeValue
+	client.batchPutter = func(items []map[string]*dynamodb.AttributeValue) (db.ConsumedCapacity, error) {
+		actualItems = items
+		return db.ConsumedCapacity{}, nil
+	}
+	// The child record of childB already exists, so keeps its creation time.
+	client.batchGetter = func(keys []map[string]*dynamodb.AttributeValue) ([]map[string]*dynamodb.AttributeValue, db.ConsumedCapacity, error) {
+		return []map[string]*dynamodb.AttributeValue{
+			{"id": {S: aws.String("nodeA")}, "rng": {S: aws.String("child/childB")}, "_c": {S: createdValue}, "_u": {S: createdValue}},
+		}, db.ConsumedCapacity{}, nil
+	}
+	s := NewStoreWithClient(client)
+	s.Timestamps = true
+	s.Now = func() time.Time { return now }
+
+	existingEdge := NewEdge("childA")
+	existingEdge.CreatedAt = created
+	n := NewNode("nodeA").WithChildren(existingEdge, NewEdge("childB"))
+	n.CreatedAt = created
+	err := s.Put(n)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectedItems := []map[string]*dynamodb.AttributeValue{
+		{"id": {S: aws.String("nodeA")}, "rng": {S: aws.String("node")}, "_c": {S: createdValue}, "_u": {S: nowValue}},
+		{"id": {S: aws.String("nodeA")}, "rng": {S: aws.String("child/childA")}, "_c": {S: createdValue}, "_u": {S: nowValue}},
+		{"id": {S: aws.String("childA")}, "rng": {S: aws.String("parent/nodeA")}, "_c": {S: createdValue}, "_u": {S: nowValue}},
+		{"id": {S: aws.String("nodeA")}, "rng": {S: aws.String("child/childB")}, "_c": {S: createdValue}, "_u": {S: nowValue}},
+		{"id": {S: aws.String("childB")}, "rng": {S: aws.String("parent/nodeA")}, "_c": {S: nowValue}, "_u": {S: nowValue}},
+	}
+	if !reflect.DeepEqual(actualItems, expectedItems) {
+		t.Errorf("\nexpected:\n%s\n\ngot:\n%s\n", format(expectedItems), format(actualItems))
+	}
+
+	client.queryByIDer = func(idField, idValue string) ([]map[string]*dynamodb.AttributeValue, db.ConsumedCapacity, error) {
+		return []map[string]*dynamodb.AttributeValue{
+			{"id": {S: aws.String("nodeA")}, "rng": {S: aws.String("node")}, "_c": {S: createdValue}, "_u": {S: createdValue}},
+			{"id": {S: aws.String("nodeA")}, "rng": {S: aws.String("node/data/testNodeData")}, "t": {S: aws.String("testNodeData")}, "_c": {S: createdValue}, "_u": {S: nowValue}},
+			{"id": {S: aws.String("nodeA")}, "rng": {S: aws.String("child/childA")}, "_c": {S: createdValue}, "_u": {S: nowValue}},
+		}, db.ConsumedCapacity{}, nil
+	}
+	s.RegisterDataType(func() interface{} {
+		return &testNodeData{}
+	})
+	actual, _, err := s.Get("nodeA")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !actual.CreatedAt.Equal(created) {
+		t.Errorf("expected node to be created at %v, got %v", created, actual.CreatedAt)
+	}
+	if !actual.UpdatedAt.Equal(now) {
+		t.Errorf("expected node data update to update the node at %v, got %v", now, actual.UpdatedAt)
+	}
+	if len(actual.Children) != 1 || !actual.Children[0].CreatedAt.Equal(created) || !actual.Children[0].UpdatedAt.Equal(now) {
+		t.Errorf("expected child edge to be created at %v and updated at %v, got %+v", created, now, actual.Children)
+	}
+	if d, ok := actual.Data["testNodeData"].(*testNodeData); !ok || *d != (testNodeData{}) {
+		t.Errorf("expected timestamps to be excluded from node data, got %+v", actual.Data["testNodeData"])
+	}
+}
+
+func TestStoreTTL(t *testing.T) {
+	now := time.Date(2019, time.February, 2, 12, 30, 0, 0, time.UTC)
+	nodeExpiry := now.Add(time.Hour)
+	edgeExpiry := now.Add(time.Minute)
+	nodeExpiryValue := aws.String(strconv.FormatInt(nodeExpiry.Unix(), 10))
+	edgeExpiryValue := aws.String(strconv.FormatInt(edgeExpiry.Unix(), 10))
+
+	client := newdynamoDBClient()
+	var actualItems []map[string]*dynamodb.AttributeValue
+	client.batchPutter = func(items []map[string]*dynamodb.AttributeValue) (db.ConsumedCapacity, error) {
+		actualItems = items
+		return db.ConsumedCapacity{}, nil
+	}
+	s := NewStoreWithClient(client)
+	s.Now = func() time.Time { return now }
+
+	err := s.Put(NewNode("nodeA").
+		WithTTL(nodeExpiry).
+		WithChildren(NewEdge("childA").WithTTL(edgeExpiry), NewEdge("childB")))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectedItems := []map[string]*dynamodb.AttributeValue{
+		{"id": {S: aws.String("nodeA")}, "rng": {S: aws.String("node")}, "_ttl": {N: nodeExpiryValue}},
+		{"id": {S: aws.String("nodeA")}, "rng": {S: aws.String("child/childA")}, "_ttl": {N: edgeExpiryValue}},
+		{"id": {S: aws.String("childA")}, "rng": {S: aws.String("parent/nodeA")}, "_ttl": {N: edgeExpiryValue}},
+		{"id": {S: aws.String("nodeA")}, "rng": {S: aws.String("child/childB")}, "_ttl": {N: nodeExpiryValue}},
+		{"id": {S: aws.String("childB")}, "rng": {S: aws.String("parent/nodeA")}, "_ttl": {N: nodeExpiryValue}},
+	}
+	if !reflect.DeepEqual(actualItems, expectedItems) {
+		t.Errorf("\nexpected:\n%s\n\ngot:\n%s\n", format(expectedItems), format(actualItems))
+	}
+
+	client.queryByIDer = func(idField, idValue string) ([]map[string]*dynamodb.AttributeValue, db.ConsumedCapacity, error) {
+		return []map[string]*dynamodb.AttributeValue{
+			{"id": {S: aws.String("nodeA")}, "rng": {S: aws.String("node")}, "_ttl": {N: nodeExpiryValue}},
+			{"id": {S: aws.String("nodeA")}, "rng": {S: aws.String("child/childA")}, "_ttl": {N: edgeExpiryValue}},
+			{"id": {S: aws.String("nodeA")}, "rng": {S: aws.String("child/childB")}, "_ttl": {N: nodeExpiryValue}},
+		}, db.ConsumedCapacity{}, nil
+	}
+	s.Now = func() time.Time { return edgeExpiry }
+	n, ok, err := s.Get("nodeA")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !ok {
+		t.Fatalf("expected the node to be found before it expires")
+	}
+	if !n.TTL.Equal(nodeExpiry) {
+		t.Errorf("expected node TTL of %v, got %v", nodeExpiry, n.TTL)
+	}
+	if len(n.Children) != 1 || n.Children[0].ID != "childB" {
+		t.Errorf("expected the expired edge to be skipped, got %+v", n.Children)
+	}
+
+	s.Now = func() time.Time { return nodeExpiry }
+	_, ok, err = s.Get("nodeA")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ok {
+		t.Errorf("expected the expired node to not be found")
+	}
+}
+
+func TestStoreErrorsCanBeClassified(t *testing.T) {
+	t.Parallel()
 	tests := []struct {
-		name               string
-		id                 string
-		recordsToReturn    []map[string]*dynamodb.AttributeValue
-		keysToDelete       []map[string]*dynamodb.AttributeValue
-		queryByIDOutputErr error
-		deleteOutputErr    error
-		expectedErr        error
+		name     string
+		code     string
+		expected error
+	}{
+		{name: "throttling", code: dynamodb.ErrCodeProvisionedThroughputExceededException, expected: ErrThrottled},
+		{name: "request limit", code: dynamodb.ErrCodeRequestLimitExceeded, expected: ErrThrottled},
+		{name: "missing table", code: dynamodb.ErrCodeResourceNotFoundException, expected: ErrResourceNotFound},
+		{name: "validation", code: "ValidationException", expected: ErrValidation},
+		{name: "conditional check", code: dynamodb.ErrCodeConditionalCheckFailedException, expected: ErrConditionalCheckFailed},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			aerr := awserr.New(test.code, "message", nil)
+			client := newdynamoDBClient()
+			client.queryByIDer = func(idField, idValue string) ([]map[string]*dynamodb.AttributeValue, db.ConsumedCapacity, error) {
+				return nil, db.ConsumedCapacity{}, db.RequestError{Op: "DB.QueryByID", Message: "failed to query pages", Err: aerr}
+			}
+			s := NewStoreWithClient(client)
+			_, _, err := s.Get("nodeA")
+			if !errors.Is(err, test.expected) {
+				t.Errorf("expected error to match %v, got %v", test.expected, err)
+			}
+			var actual awserr.Error
+			if !errors.As(err, &actual) || actual.Code() != test.code {
+				t.Errorf("expected the AWS error to be unwrapped, got %v", err)
+			}
+		})
+	}
+	if db.Classify(errors.New("other")) != nil {
+		t.Errorf("expected errors which aren't from AWS to be unclassified")
+	}
+}
+
+func TestStoreGetNodeOnly(t *testing.T) {
+	tests := []struct {
+		name           string
+		id             string
+		itemToReturn   map[string]*dynamodb.AttributeValue
+		getItemErr     error
+		expected       Node
+		expectedOK     bool
+		expectedErr    error
+		expectedLookup bool
 	}{
 		{
-			name:            "Missing node ID results in no error, and no results",
-			id:              "",
-			deleteOutputErr: fmt.Errorf("unexpected 2nd database call"),
+			name: "Missing node ID results in no error, and no results",
+			id:   "",
 		},
 		{
-			name: "A node record which doesn't have data or children can be deleted",
+			name:           "A missing node is not OK",
+			id:             "nodeA",
+			expectedLookup: true,
+		},
+		{
+			name:           "Database errors are returned",
+			id:             "nodeA",
+			getItemErr:     errTestDatabaseFailure,
+			expectedErr:    errTestDatabaseFailure,
+			expectedLookup: true,
+		},
+		{
+			name: "The node record is returned",
 			id:   "nodeA",
-			recordsToReturn: []map[string]*dynamodb.AttributeValue{
-				map[string]*dynamodb.AttributeValue{
-					"id": {
-						S: aws.String("nodeA"),
-					},
-					"rng": {
-						S: aws.String("node"),
+			itemToReturn: map[string]*dynamodb.AttributeValue{
+				"id": {
+					S: aws.String("nodeA"),
+				},
+				"rng": {
+					S: aws.String("node"),
+				},
+			},
+			expected:       NewNode("nodeA"),
+			expectedOK:     true,
+			expectedLookup: true,
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			client := newdynamoDBClient()
+			var lookedUp bool
+			client.getItemer = func(key map[string]*dynamodb.AttributeValue) (map[string]*dynamodb.AttributeValue, db.ConsumedCapacity, error) {
+				lookedUp = true
+				expectedKey := getID(test.id, rangefield.Node{})
+				if !reflect.DeepEqual(key, expectedKey) {
+					t.Errorf("expected key %+v, got %+v", expectedKey, key)
+				}
+				return test.itemToReturn, db.ConsumedCapacity{ConsumedCapacity: 1}, test.getItemErr
+			}
+			s := NewStoreWithClient(client)
+			n, ok, err := s.GetNodeOnly(test.id)
+			if err != test.expectedErr {
+				t.Errorf("expected err %v, got %v", test.expectedErr, err)
+			}
+			if ok != test.expectedOK {
+				t.Errorf("expected OK %v, got %v", test.expectedOK, ok)
+			}
+			if lookedUp != test.expectedLookup {
+				t.Errorf("expected lookup %v, got %v", test.expectedLookup, lookedUp)
+			}
+			if test.expectedOK && !reflect.DeepEqual(n, test.expected) {
+				t.Errorf("\nexpected:\n%+v\n\ngot:\n%+v\n", test.expected, n)
+			}
+		})
+	}
+}
+
+func TestStoreGetWithOptions(t *testing.T) {
+	records := []map[string]*dynamodb.AttributeValue{
+		getID("nodeA", rangefield.Node{}),
+		getID("nodeA", rangefield.NodeLabel{Label: "person"}),
+		getID("nodeA", rangefield.Child{Child: "nodeB"}),
+		getID("nodeA", rangefield.Parent{Parent: "nodeC"}),
+	}
+	tests := []struct {
+		name             string
+		opts             GetOptions
+		expectedPrefixes []string
+		expectedGetItem  bool
+		expected         Node
+	}{
+		{
+			name:            "Only the node record is read if nothing else is requested",
+			expectedGetItem: true,
+			expected:        NewNode("nodeA"),
+		},
+		{
+			name:             "Data is read using the node prefix",
+			opts:             GetOptions{Data: true},
+			expectedPrefixes: []string{rangefield.NodePrefix},
+			expected:         NewNode("nodeA").WithLabels("person"),
+		},
+		{
+			name:             "Parents are read using the parent prefix",
+			opts:             GetOptions{Data: true, Parents: true},
+			expectedPrefixes: []string{rangefield.NodePrefix, rangefield.ParentPrefix},
+			expected:         NewNode("nodeA").WithLabels("person").WithParents(NewEdge("nodeC")),
+		},
+		{
+			name:             "Children are read using the child prefix",
+			opts:             GetOptions{Children: true},
+			expectedPrefixes: []string{rangefield.ChildPrefix},
+			expectedGetItem:  true,
+			expected:         NewNode("nodeA").WithChildren(NewEdge("nodeB")),
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			client := newdynamoDBClient()
+			var actualGetItem bool
+			client.getItemer = func(key map[string]*dynamodb.AttributeValue) (map[string]*dynamodb.AttributeValue, db.ConsumedCapacity, error) {
+				actualGetItem = true
+				return records[0], db.ConsumedCapacity{}, nil
+			}
+			var actualPrefixes []string
+			client.queryPager = func(idField, idValue, rangeField, rangePrefix string, limit int64, startKey map[string]*dynamodb.AttributeValue) (items []map[string]*dynamodb.AttributeValue, lastEvaluatedKey map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error) {
+				actualPrefixes = append(actualPrefixes, rangePrefix)
+				for _, r := range records {
+					if strings.HasPrefix(*r[fieldRange].S, rangePrefix) {
+						items = append(items, r)
+					}
+				}
+				return
+			}
+			s := NewStoreWithClient(client)
+			n, ok, err := s.GetWithOptions("nodeA", test.opts)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !ok {
+				t.Fatalf("expected the node to be found")
+			}
+			if actualGetItem != test.expectedGetItem {
+				t.Errorf("expected GetItem %v, got %v", test.expectedGetItem, actualGetItem)
+			}
+			if !reflect.DeepEqual(actualPrefixes, test.expectedPrefixes) {
+				t.Errorf("expected prefixes %v, got %v", test.expectedPrefixes, actualPrefixes)
+			}
+			if !reflect.DeepEqual(n, test.expected) {
+				t.Errorf("\nexpected:\n%+v\n\ngot:\n%+v\n", test.expected, n)
+			}
+		})
+	}
+}
+
+func TestStoreGetChildren(t *testing.T) {
+	records := []map[string]*dynamodb.AttributeValue{
+		{"id": {S: aws.String("nodeA")}, "rng": {S: aws.String("child/childA")}},
+		{"id": {S: aws.String("nodeA")}, "rng": {S: aws.String("child/childA/data/testEdgeData")}, "t": {S: aws.String("testEdgeData")}},
+		{"id": {S: aws.String("nodeA")}, "rng": {S: aws.String("child/childB")}},
+		{"id": {S: aws.String("nodeA")}, "rng": {S: aws.String("child/childC")}},
+		{"id": {S: aws.String("nodeA")}, "rng": {S: aws.String("node")}},
+	}
+	tests := []struct {
+		name          string
+		id            string
+		limit         int
+		cursor        string
+		queryErr      error
+		expectedIDs   []string
+		expectedNext  string
+		expectedErr   error
+		expectedCalls int
+	}{
+		{
+			name:        "Missing node ID results in an error",
+			expectedErr: ErrMissingNodeID,
+		},
+		{
+			name:        "Invalid cursors result in an error",
+			id:          "nodeA",
+			cursor:      "not a cursor",
+			expectedErr: ErrInvalidCursor,
+		},
+		{
+			name:          "Database errors are returned",
+			id:            "nodeA",
+			queryErr:      errTestDatabaseFailure,
+			expectedErr:   errTestDatabaseFailure,
+			expectedCalls: 1,
+		},
+		{
+			name:          "No limit returns all children",
+			id:            "nodeA",
+			expectedIDs:   []string{"childA", "childB", "childC"},
+			expectedCalls: 1,
+		},
+		{
+			name:          "Data records don't count towards the limit",
+			id:            "nodeA",
+			limit:         2,
+			expectedIDs:   []string{"childA", "childB"},
+			expectedNext:  "child/childB",
+			expectedCalls: 2,
+		},
+		{
+			name:          "The cursor continues from the previous page",
+			id:            "nodeA",
+			limit:         2,
+			cursor:        "child/childB",
+			expectedIDs:   []string{"childC"},
+			expectedCalls: 1,
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			client := newdynamoDBClient()
+			calls := 0
+			client.queryPager = func(idField, idValue, rangeField, rangePrefix string, limit int64, startKey map[string]*dynamodb.AttributeValue) ([]map[string]*dynamodb.AttributeValue, map[string]*dynamodb.AttributeValue, db.ConsumedCapacity, error) {
+				calls++
+				if idValue != test.id {
+					t.Errorf("expected id of '%s', got '%s'", test.id, idValue)
+				}
+				if rangePrefix != "child/" {
+					t.Errorf("expected range prefix of 'child/', got '%s'", rangePrefix)
+				}
+				if test.queryErr != nil {
+					return nil, nil, db.ConsumedCapacity{}, test.queryErr
+				}
+				items, lastEvaluatedKey := pageRecords(records, rangePrefix, limit, startKey)
+				return items, lastEvaluatedKey, db.ConsumedCapacity{ConsumedCapacity: 1}, nil
+			}
+			s := NewStoreWithClient(client)
+			children, next, err := s.GetChildren(test.id, test.limit, test.cursor)
+			if err != test.expectedErr {
+				t.Errorf("expected err %v, got %v", test.expectedErr, err)
+			}
+			var actualIDs []string
+			for _, c := range children {
+				actualIDs = append(actualIDs, c.ID)
+			}
+			if !reflect.DeepEqual(actualIDs, test.expectedIDs) {
+				t.Errorf("expected IDs %v, got %v", test.expectedIDs, actualIDs)
+			}
+			if next != test.expectedNext {
+				t.Errorf("expected next cursor '%s', got '%s'", test.expectedNext, next)
+			}
+			if calls != test.expectedCalls {
+				t.Errorf("expected %d queries, got %d", test.expectedCalls, calls)
+			}
+		})
+	}
+}
+
+func TestStoreGetChildrenWithPrefix(t *testing.T) {
+	records := []map[string]*dynamodb.AttributeValue{
+		{"id": {S: aws.String("nodeA")}, "rng": {S: aws.String("child/2024%2F01%2F01")}},
+		{"id": {S: aws.String("nodeA")}, "rng": {S: aws.String("child/2024%2F01%2F01/data/testEdgeData")}, "t": {S: aws.String("testEdgeData")}},
+		{"id": {S: aws.String("nodeA")}, "rng": {S: aws.String("child/2024%2F01%2F02")}},
+		{"id": {S: aws.String("nodeA")}, "rng": {S: aws.String("child/2024%2F01%2Flabel/childA")}},
+		{"id": {S: aws.String("nodeA")}, "rng": {S: aws.String("child/2024%2F02%2F01")}},
+		{"id": {S: aws.String("nodeA")}, "rng": {S: aws.String("node")}},
+	}
+	tests := []struct {
+		name                string
+		prefix              string
+		expectedRangePrefix string
+		expectedIDs         []string
+	}{
+		{
+			name:                "Children whose IDs begin with the prefix are returned",
+			prefix:              "2024/01/",
+			expectedRangePrefix: "child/2024%2F01%2F",
+			expectedIDs:         []string{"2024/01/01", "2024/01/02"},
+		},
+		{
+			name:                "An empty prefix returns all unlabelled children",
+			expectedRangePrefix: "child/",
+			expectedIDs:         []string{"2024/01/01", "2024/01/02", "2024/02/01"},
+		},
+		{
+			name:                "No matches returns no children",
+			prefix:              "2023/",
+			expectedRangePrefix: "child/2023%2F",
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			client := newdynamoDBClient()
+			client.queryPager = func(idField, idValue, rangeField, rangePrefix string, limit int64, startKey map[string]*dynamodb.AttributeValue) ([]map[string]*dynamodb.AttributeValue, map[string]*dynamodb.AttributeValue, db.ConsumedCapacity, error) {
+				if rangePrefix != test.expectedRangePrefix {
+					t.Errorf("expected range prefix of '%s', got '%s'", test.expectedRangePrefix, rangePrefix)
+				}
+				items, lastEvaluatedKey := pageRecords(records, rangePrefix, limit, startKey)
+				return items, lastEvaluatedKey, db.ConsumedCapacity{}, nil
+			}
+			s := NewStoreWithClient(client)
+			children, err := s.GetChildrenWithPrefix("nodeA", test.prefix)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var actualIDs []string
+			for _, c := range children {
+				actualIDs = append(actualIDs, c.ID)
+			}
+			if !reflect.DeepEqual(actualIDs, test.expectedIDs) {
+				t.Errorf("expected IDs %v, got %v", test.expectedIDs, actualIDs)
+			}
+		})
+	}
+}
+
+func TestStorePutLabelledEdges(t *testing.T) {
+	client := newdynamoDBClient()
+	var actualItems []map[string]*dynamodb.AttributeValue
+	client.batchPutter = func(items []map[string]*dynamodb.AttributeValue) (db.ConsumedCapacity, error) {
+		actualItems = items
+		return db.ConsumedCapacity{}, nil
+	}
+	s := NewStoreWithClient(client)
+	if err := s.PutLabelledEdges("nodeA", "", NewEdge("childA")); err != ErrMissingLabel {
+		t.Errorf("expected missing labels to result in ErrMissingLabel, got %v", err)
+	}
+	edge := NewEdge("childA").WithData(testEdgeData{EdgeDataField: 1})
+	err := s.PutLabelledEdges("nodeA", "owns", edge)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if edge.Label != "" {
+		t.Errorf("expected the caller's edge not to be changed, got label %q", edge.Label)
+	}
+	expectedItems := []map[string]*dynamodb.AttributeValue{
+		{"id": {S: aws.String("nodeA")}, "rng": {S: aws.String("child/owns/childA")}},
+		{"id": {S: aws.String("nodeA")}, "rng": {S: aws.String("child/owns/childA/data/testEdgeData")}, "t": {S: aws.String("testEdgeData")}, "edgeDataField": {N: aws.String("1")}},
+		{"id": {S: aws.String("childA")}, "rng": {S: aws.String("parent/owns/nodeA")}},
+		{"id": {S: aws.String("childA")}, "rng": {S: aws.String("parent/owns/nodeA/data/testEdgeData")}, "t": {S: aws.String("testEdgeData")}, "edgeDataField": {N: aws.String("1")}},
+	}
+	if !reflect.DeepEqual(actualItems, expectedItems) {
+		t.Errorf("\nexpected:\n%s\n\ngot:\n%s\n", format(expectedItems), format(actualItems))
+	}
+}
+
+func TestStoreLabelledEdges(t *testing.T) {
+	records := []map[string]*dynamodb.AttributeValue{
+		{"id": {S: aws.String("nodeA")}, "rng": {S: aws.String("child/childA")}},
+		{"id": {S: aws.String("nodeA")}, "rng": {S: aws.String("child/connects_to/childA")}},
+		{"id": {S: aws.String("nodeA")}, "rng": {S: aws.String("child/owns/childA")}},
+		{"id": {S: aws.String("nodeA")}, "rng": {S: aws.String("child/owns/childB")}},
+		{"id": {S: aws.String("nodeA")}, "rng": {S: aws.String("node")}},
+	}
+	client := newdynamoDBClient()
+	client.queryByIDer = func(idField, idValue string) ([]map[string]*dynamodb.AttributeValue, db.ConsumedCapacity, error) {
+		return records, db.ConsumedCapacity{}, nil
+	}
+	client.queryPager = func(idField, idValue, rangeField, rangePrefix string, limit int64, startKey map[string]*dynamodb.AttributeValue) ([]map[string]*dynamodb.AttributeValue, map[string]*dynamodb.AttributeValue, db.ConsumedCapacity, error) {
+		items, lastEvaluatedKey := pageRecords(records, rangePrefix, limit, startKey)
+		return items, lastEvaluatedKey, db.ConsumedCapacity{}, nil
+	}
+	s := NewStoreWithClient(client)
+
+	n, ok, err := s.Get("nodeA")
+	if err != nil || !ok {
+		t.Fatalf("expected node to be found, got ok=%v, err=%v", ok, err)
+	}
+	expectedChildren := []*Edge{
+		NewEdge("childA"),
+		NewEdge("childA").WithLabel("connects_to"),
+		NewEdge("childA").WithLabel("owns"),
+		NewEdge("childB").WithLabel("owns"),
+	}
+	if !reflect.DeepEqual(n.Children, expectedChildren) {
+		t.Errorf("expected children %v, got %v", expectedChildren, n.Children)
+	}
+
+	children, _, err := s.GetChildrenByLabel("nodeA", "owns", 0, "")
+	if err != nil {
+		t.Fatalf("unexpected error getting children by label: %v", err)
+	}
+	expectedChildren = []*Edge{
+		NewEdge("childA").WithLabel("owns"),
+		NewEdge("childB").WithLabel("owns"),
+	}
+	if !reflect.DeepEqual(children, expectedChildren) {
+		t.Errorf("expected children %v, got %v", expectedChildren, children)
+	}
+}
+
+func TestStoreParallelEdges(t *testing.T) {
+	records := []map[string]*dynamodb.AttributeValue{
+		{"id": {S: aws.String("switchA")}, "rng": {S: aws.String("node")}},
+		{"id": {S: aws.String("switchA")}, "rng": {S: aws.String("child/link1/switchB")}},
+		{"id": {S: aws.String("switchA")}, "rng": {S: aws.String("child/link1/switchB/data/testEdgeData")}, "t": {S: aws.String("testEdgeData")}, "edgeDataField": {N: aws.String("1")}},
+		{"id": {S: aws.String("switchA")}, "rng": {S: aws.String("child/link2/switchB")}},
+		{"id": {S: aws.String("switchA")}, "rng": {S: aws.String("child/link2/switchB/data/testEdgeData")}, "t": {S: aws.String("testEdgeData")}, "edgeDataField": {N: aws.String("2")}},
+	}
+	client := newdynamoDBClient()
+	client.queryByIDer = func(idField, idValue string) ([]map[string]*dynamodb.AttributeValue, db.ConsumedCapacity, error) {
+		var items []map[string]*dynamodb.AttributeValue
+		for _, r := range records {
+			itm := make(map[string]*dynamodb.AttributeValue)
+			for k, v := range r {
+				itm[k] = v
+			}
+			items = append(items, itm)
+		}
+		return items, db.ConsumedCapacity{}, nil
+	}
+	var actualKeysToDelete []map[string]*dynamodb.AttributeValue
+	client.batchDeleter = func(keys []map[string]*dynamodb.AttributeValue) (db.ConsumedCapacity, error) {
+		actualKeysToDelete = keys
+		return db.ConsumedCapacity{}, nil
+	}
+	s := NewStoreWithClient(client)
+	s.RegisterDataType(func() interface{} {
+		return &testEdgeData{}
+	})
+
+	n, ok, err := s.Get("switchA")
+	if err != nil || !ok {
+		t.Fatalf("expected node to be found, got ok=%v, err=%v", ok, err)
+	}
+	link1, link2 := n.GetLabelledChild("switchB", "link1"), n.GetLabelledChild("switchB", "link2")
+	if link1 == nil || link2 == nil {
+		t.Fatalf("expected both edges to be returned, got %v", n.Children)
+	}
+	if link1.Data["testEdgeData"].(*testEdgeData).EdgeDataField != 1 || link2.Data["testEdgeData"].(*testEdgeData).EdgeDataField != 2 {
+		t.Errorf("expected each edge to have its own data, got %v and %v", link1.Data, link2.Data)
+	}
+
+	err = s.DeleteLabelledEdge("switchA", "switchB", "link2")
+	if err != nil {
+		t.Fatalf("unexpected error deleting edge: %v", err)
+	}
+	expectedKeysToDelete := []map[string]*dynamodb.AttributeValue{
+		{"id": {S: aws.String("switchA")}, "rng": {S: aws.String("child/link2/switchB")}},
+		{"id": {S: aws.String("switchB")}, "rng": {S: aws.String("parent/link2/switchA")}},
+		{"id": {S: aws.String("switchA")}, "rng": {S: aws.String("child/link2/switchB/data/testEdgeData")}},
+		{"id": {S: aws.String("switchB")}, "rng": {S: aws.String("parent/link2/switchA/data/testEdgeData")}},
+	}
+	if !reflect.DeepEqual(actualKeysToDelete, expectedKeysToDelete) {
+		t.Errorf("\nexpected:\n%s\n\ngot:\n%s\n", format(expectedKeysToDelete), format(actualKeysToDelete))
+	}
+}
+
+func TestStoreGetEdge(t *testing.T) {
+	records := []map[string]*dynamodb.AttributeValue{
+		{"id": {S: aws.String("nodeA")}, "rng": {S: aws.String("child/childA")}},
+		{"id": {S: aws.String("nodeA")}, "rng": {S: aws.String("child/childA/data/testEdgeData")}, "t": {S: aws.String("testEdgeData")}, "edgeDataField": {N: aws.String("123")}},
+		{"id": {S: aws.String("nodeA")}, "rng": {S: aws.String("child/childAB")}},
+		{"id": {S: aws.String("nodeA")}, "rng": {S: aws.String("child/childAB/data/testEdgeData")}, "t": {S: aws.String("testEdgeData")}, "edgeDataField": {N: aws.String("456")}},
+	}
+	tests := []struct {
+		name         string
+		parent       string
+		child        string
+		queryErr     error
+		expected     *Edge
+		expectedOK   bool
+		expectedErr  error
+		expectedCall bool
+	}{
+		{
+			name:        "Missing parent ID results in an error",
+			child:       "childA",
+			expectedErr: ErrMissingNodeID,
+		},
+		{
+			name:        "Missing child ID results in an error",
+			parent:      "nodeA",
+			expectedErr: ErrMissingNodeID,
+		},
+		{
+			name:         "Database errors are returned",
+			parent:       "nodeA",
+			child:        "childA",
+			queryErr:     errTestDatabaseFailure,
+			expectedErr:  errTestDatabaseFailure,
+			expectedCall: true,
+		},
+		{
+			name:         "Missing edges are not OK",
+			parent:       "nodeA",
+			child:        "childB",
+			expectedCall: true,
+		},
+		{
+			name:         "The edge is returned with its data, ignoring children with similar IDs",
+			parent:       "nodeA",
+			child:        "childA",
+			expected:     NewEdge("childA").WithData(&testEdgeData{EdgeDataField: 123}),
+			expectedOK:   true,
+			expectedCall: true,
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			client := newdynamoDBClient()
+			var called bool
+			client.queryPager = func(idField, idValue, rangeField, rangePrefix string, limit int64, startKey map[string]*dynamodb.AttributeValue) ([]map[string]*dynamodb.AttributeValue, map[string]*dynamodb.AttributeValue, db.ConsumedCapacity, error) {
+				called = true
+				if idValue != test.parent {
+					t.Errorf("expected id of '%s', got '%s'", test.parent, idValue)
+				}
+				if test.queryErr != nil {
+					return nil, nil, db.ConsumedCapacity{}, test.queryErr
+				}
+				var items []map[string]*dynamodb.AttributeValue
+				for _, r := range records {
+					// Copy the records, since they're modified when read.
+					itm := make(map[string]*dynamodb.AttributeValue)
+					for k, v := range r {
+						itm[k] = v
+					}
+					items = append(items, itm)
+				}
+				items, lastEvaluatedKey := pageRecords(items, rangePrefix, limit, startKey)
+				return items, lastEvaluatedKey, db.ConsumedCapacity{ConsumedCapacity: 1}, nil
+			}
+			s := NewStoreWithClient(client)
+			s.RegisterDataType(func() interface{} {
+				return &testEdgeData{}
+			})
+			e, ok, err := s.GetEdge(test.parent, test.child)
+			if err != test.expectedErr {
+				t.Errorf("expected err %v, got %v", test.expectedErr, err)
+			}
+			if ok != test.expectedOK {
+				t.Errorf("expected OK %v, got %v", test.expectedOK, ok)
+			}
+			if called != test.expectedCall {
+				t.Errorf("expected query call %v, got %v", test.expectedCall, called)
+			}
+			if !reflect.DeepEqual(e, test.expected) {
+				t.Errorf("\nexpected:\n%+v\n\ngot:\n%+v\n", test.expected, e)
+			}
+		})
+	}
+}
+
+func TestStoreExists(t *testing.T) {
+	tests := []struct {
+		name         string
+		id           string
+		itemToReturn map[string]*dynamodb.AttributeValue
+		getItemErr   error
+		expected     bool
+		expectedErr  error
+	}{
+		{
+			name:     "Missing node ID doesn't exist",
+			id:       "",
+			expected: false,
+		},
+		{
+			name:     "A missing node doesn't exist",
+			id:       "nodeA",
+			expected: false,
+		},
+		{
+			name:        "Database errors are returned",
+			id:          "nodeA",
+			getItemErr:  errTestDatabaseFailure,
+			expectedErr: errTestDatabaseFailure,
+		},
+		{
+			name: "A node with a node record exists",
+			id:   "nodeA",
+			itemToReturn: map[string]*dynamodb.AttributeValue{
+				"id": {
+					S: aws.String("nodeA"),
+				},
+				"rng": {
+					S: aws.String("node"),
+				},
+			},
+			expected: true,
+		},
+		{
+			name: "A deleted node doesn't exist",
+			id:   "nodeA",
+			itemToReturn: map[string]*dynamodb.AttributeValue{
+				"id":  {S: aws.String("nodeA")},
+				"rng": {S: aws.String("node")},
+				"_d":  {S: aws.String("2019-01-01T00:00:00Z")},
+			},
+			expected: false,
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			client := newdynamoDBClient()
+			var actualAttributes []string
+			client.getItemer = func(key map[string]*dynamodb.AttributeValue) (map[string]*dynamodb.AttributeValue, db.ConsumedCapacity, error) {
+				return test.itemToReturn, db.ConsumedCapacity{ConsumedCapacity: 1}, test.getItemErr
+			}
+			s := NewStoreWithClient(client)
+			s.Client = &attributeRecordingDB{DB: client, attributes: &actualAttributes}
+			actual, err := s.Exists(test.id)
+			if test.id != "" && !reflect.DeepEqual(actualAttributes, []string{"id", "rng", "_d", TTLAttributeName}) {
+				t.Errorf("expected only the key and the deleted and TTL attributes to be read, got %v", actualAttributes)
+			}
+			if err != test.expectedErr {
+				t.Errorf("expected err %v, got %v", test.expectedErr, err)
+			}
+			if actual != test.expected {
+				t.Errorf("expected %v, got %v", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestStoreListNodes(t *testing.T) {
+	// Scans return records in the order of the table, which isn't sorted by ID.
+	records := []map[string]*dynamodb.AttributeValue{
+		{"id": {S: aws.String("nodeC")}, "rng": {S: aws.String("node")}},
+		{"id": {S: aws.String("nodeC")}, "rng": {S: aws.String("child/nodeA")}},
+		{"id": {S: aws.String("nodeA")}, "rng": {S: aws.String("node")}},
+		{"id": {S: aws.String("nodeA")}, "rng": {S: aws.String("parent/nodeC")}},
+		{"id": {S: aws.String("nodeB")}, "rng": {S: aws.String("node")}},
+	}
+	scan := func(limit int64, startKey map[string]*dynamodb.AttributeValue) (items []map[string]*dynamodb.AttributeValue, lastEvaluatedKey map[string]*dynamodb.AttributeValue) {
+		var start int
+		if startKey != nil {
+			for i, r := range records {
+				if reflect.DeepEqual(r, startKey) {
+					start = i + 1
+				}
+			}
+		}
+		end := len(records)
+		if limit > 0 && start+int(limit) < end {
+			end = start + int(limit)
+			lastEvaluatedKey = records[end-1]
+		}
+		for _, r := range records[start:end] {
+			if *r["rng"].S == "node" {
+				items = append(items, r)
+			}
+		}
+		return
+	}
+	tests := []struct {
+		name         string
+		limit        int
+		cursor       string
+		scanErr      error
+		expectedIDs  []string
+		expectedNext string
+		expectedErr  error
+	}{
+		{
+			name:        "Database errors are returned",
+			scanErr:     errTestDatabaseFailure,
+			expectedErr: errTestDatabaseFailure,
+		},
+		{
+			name:        "No limit returns all of the nodes",
+			expectedIDs: []string{"nodeC", "nodeA", "nodeB"},
+		},
+		{
+			name:         "Non-node records are skipped until the limit is reached",
+			limit:        2,
+			expectedIDs:  []string{"nodeC", "nodeA"},
+			expectedNext: "nodeA",
+		},
+		{
+			name:        "The cursor continues from the previous page",
+			limit:       2,
+			cursor:      "nodeA",
+			expectedIDs: []string{"nodeB"},
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			client := newdynamoDBClient()
+			client.scanPager = func(field, value string, limit int64, startKey map[string]*dynamodb.AttributeValue) ([]map[string]*dynamodb.AttributeValue, map[string]*dynamodb.AttributeValue, db.ConsumedCapacity, error) {
+				if field != "rng" || value != "node" {
+					t.Errorf("expected to filter on rng = node, got %s = %s", field, value)
+				}
+				if test.scanErr != nil {
+					return nil, nil, db.ConsumedCapacity{}, test.scanErr
+				}
+				items, lastEvaluatedKey := scan(limit, startKey)
+				return items, lastEvaluatedKey, db.ConsumedCapacity{ConsumedCapacity: 1}, nil
+			}
+			s := NewStoreWithClient(client)
+			ids, next, err := s.ListNodes(test.limit, test.cursor)
+			if err != test.expectedErr {
+				t.Errorf("expected err %v, got %v", test.expectedErr, err)
+			}
+			if !reflect.DeepEqual(ids, test.expectedIDs) {
+				t.Errorf("expected IDs %v, got %v", test.expectedIDs, ids)
+			}
+			if next != test.expectedNext {
+				t.Errorf("expected next cursor '%s', got '%s'", test.expectedNext, next)
+			}
+		})
+	}
+}
+
+func TestStoreListNodesRangeIndex(t *testing.T) {
+	client := newdynamoDBClient()
+	var actualStartKey map[string]*dynamodb.AttributeValue
+	client.queryIndexPager = func(indexName, field string, value interface{}, limit int64, startKey map[string]*dynamodb.AttributeValue) ([]map[string]*dynamodb.AttributeValue, map[string]*dynamodb.AttributeValue, db.ConsumedCapacity, error) {
+		if indexName != "ranges" || field != "rng" || value != "node" {
+			t.Errorf("expected to query ranges.rng = node, got %s.%s = %v", indexName, field, value)
+		}
+		actualStartKey = startKey
+		return []map[string]*dynamodb.AttributeValue{
+			{"id": {S: aws.String("nodeB")}, "rng": {S: aws.String("node")}},
+			{"id": {S: aws.String("nodeC")}, "rng": {S: aws.String("node")}, "_d": {BOOL: aws.Bool(true)}},
+		}, nil, db.ConsumedCapacity{}, nil
+	}
+	s := NewStoreWithClient(client)
+	s.RangeIndex = "ranges"
+	ids, next, err := s.ListNodes(10, "nodeA")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(ids, []string{"nodeB"}) {
+		t.Errorf("expected IDs [nodeB], got %v", ids)
+	}
+	if next != "" {
+		t.Errorf("expected no next cursor, got '%s'", next)
+	}
+	if expectedStartKey := getID("nodeA", rangefield.Node{}); !reflect.DeepEqual(actualStartKey, expectedStartKey) {
+		t.Errorf("expected start key %s, got %s", format([]map[string]*dynamodb.AttributeValue{expectedStartKey}), format([]map[string]*dynamodb.AttributeValue{actualStartKey}))
+	}
+}
+
+func TestStoreGetMany(t *testing.T) {
+	nodeRecords := map[string][]map[string]*dynamodb.AttributeValue{
+		"nodeA": {
+			{
+				"id":  {S: aws.String("nodeA")},
+				"rng": {S: aws.String("node")},
+			},
+		},
+		"nodeB": {
+			{
+				"id":  {S: aws.String("nodeB")},
+				"rng": {S: aws.String("node")},
+			},
+			{
+				"id":  {S: aws.String("nodeB")},
+				"rng": {S: aws.String("child/nodeA")},
+			},
+		},
+		"deleted": {
+			{
+				"id":         {S: aws.String("deleted")},
+				"rng":        {S: aws.String("node")},
+				fieldDeleted: {BOOL: aws.Bool(true)},
+			},
+		},
+		"error": {
+			{
+				"id":  {S: aws.String("error")},
+				"rng": {S: aws.String("node")},
+			},
+		},
+	}
+	manyIDs := []string{"nodeA"}
+	for i := 0; i < 250; i++ {
+		manyIDs = append(manyIDs, fmt.Sprintf("missing%d", i))
+	}
+	tests := []struct {
+		name              string
+		ids               []string
+		expected          []Node
+		expectedErr       error
+		expectedBatchGets int
+		expectedKeys      int
+		expectedQueries   []string
+	}{
+		{
+			name: "No IDs results in no nodes",
+		},
+		{
+			name:              "Nodes are returned in the order requested",
+			ids:               []string{"nodeB", "nodeA"},
+			expected:          []Node{NewNode("nodeB").WithChildren(NewEdge("nodeA")), NewNode("nodeA")},
+			expectedBatchGets: 1,
+			expectedKeys:      2,
+			expectedQueries:   []string{"nodeA", "nodeB"},
+		},
+		{
+			name:              "Missing nodes are skipped without querying them",
+			ids:               []string{"nodeA", "missing", "nodeB"},
+			expected:          []Node{NewNode("nodeA"), NewNode("nodeB").WithChildren(NewEdge("nodeA"))},
+			expectedBatchGets: 1,
+			expectedKeys:      3,
+			expectedQueries:   []string{"nodeA", "nodeB"},
+		},
+		{
+			name:              "Deleted nodes are skipped without querying them",
+			ids:               []string{"deleted", "nodeA"},
+			expected:          []Node{NewNode("nodeA")},
+			expectedBatchGets: 1,
+			expectedKeys:      2,
+			expectedQueries:   []string{"nodeA"},
+		},
+		{
+			name:              "Nodes requested more than once are read once",
+			ids:               []string{"nodeA", "nodeA"},
+			expected:          []Node{NewNode("nodeA"), NewNode("nodeA")},
+			expectedBatchGets: 1,
+			expectedKeys:      1,
+			expectedQueries:   []string{"nodeA"},
+		},
+		{
+			name:              "Node records are read in a single batch, and only the nodes which exist are queried",
+			ids:               manyIDs,
+			expected:          []Node{NewNode("nodeA")},
+			expectedBatchGets: 1,
+			expectedKeys:      len(manyIDs),
+			expectedQueries:   []string{"nodeA"},
+		},
+		{
+			name:              "Database errors are returned",
+			ids:               []string{"nodeA", "error"},
+			expectedErr:       errTestDatabaseFailure,
+			expectedBatchGets: 1,
+			expectedKeys:      2,
+			expectedQueries:   []string{"error", "nodeA"},
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			var m sync.Mutex
+			var batchGets, keyCount int
+			var queries []string
+			client := newdynamoDBClient()
+			client.batchGetter = func(keys []map[string]*dynamodb.AttributeValue) (items []map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error) {
+				m.Lock()
+				defer m.Unlock()
+				batchGets++
+				keyCount += len(keys)
+				for _, k := range keys {
+					if records, ok := nodeRecords[*k["id"].S]; ok {
+						items = append(items, records[0])
+					}
+				}
+				return items, db.ConsumedCapacity{ConsumedCapacity: 1, ConsumedReadCapacity: 1}, nil
+			}
+			client.queryByIDer = func(idField, idValue string) ([]map[string]*dynamodb.AttributeValue, db.ConsumedCapacity, error) {
+				m.Lock()
+				defer m.Unlock()
+				queries = append(queries, idValue)
+				cc := db.ConsumedCapacity{ConsumedCapacity: 1, ConsumedReadCapacity: 1}
+				if idValue == "error" {
+					return nil, cc, errTestDatabaseFailure
+				}
+				return nodeRecords[idValue], cc, nil
+			}
+			s := NewStoreWithClient(client)
+			nodes, err := s.GetMany(test.ids...)
+			if err != test.expectedErr {
+				t.Errorf("expected err %v, got %v", test.expectedErr, err)
+			}
+			if !reflect.DeepEqual(nodes, test.expected) {
+				t.Errorf("\nexpected:\n%+v\n\ngot:\n%+v\n", test.expected, nodes)
+			}
+			if batchGets != test.expectedBatchGets || keyCount != test.expectedKeys {
+				t.Errorf("expected %d batch gets of %d keys, got %d batch gets of %d keys", test.expectedBatchGets, test.expectedKeys, batchGets, keyCount)
+			}
+			sort.Strings(queries)
+			if !reflect.DeepEqual(queries, test.expectedQueries) {
+				t.Errorf("expected queries of %v, got %v", test.expectedQueries, queries)
+			}
+			if expected, cc := float64(test.expectedBatchGets+len(test.expectedQueries)), s.CapacitySnapshot(); cc.ConsumedCapacity != expected {
+				t.Errorf("expected consumed capacity of %v, got %v", expected, cc.ConsumedCapacity)
+			}
+		})
+	}
+}
+
+func TestStoreDelete(t *testing.T) {
+	tests := []struct {
+		name               string
+		id                 string
+		recordsToReturn    []map[string]*dynamodb.AttributeValue
+		keysToDelete       []map[string]*dynamodb.AttributeValue
+		queryByIDOutputErr error
+		deleteOutputErr    error
+		expectedErr        error
+	}{
+		{
+			name:            "Missing node ID results in no error, and no results",
+			id:              "",
+			deleteOutputErr: fmt.Errorf("unexpected 2nd database call"),
+		},
+		{
+			name: "A node record which doesn't have data or children can be deleted",
+			id:   "nodeA",
+			recordsToReturn: []map[string]*dynamodb.AttributeValue{
+				map[string]*dynamodb.AttributeValue{
+					"id": {
+						S: aws.String("nodeA"),
+					},
+					"rng": {
+						S: aws.String("node"),
 					},
 				},
 			},
@@ -1108,18 +2588,100 @@ func TestStoreDelete(t *testing.T) {
 						S: aws.String("childNode"),
 					},
 					"rng": {
-						S: aws.String("parent/nodeA"),
+						S: aws.String("parent/nodeA"),
+					},
+				},
+			},
+		},
+		{
+			name: "A node record with children is fully deleted, including deleting the edge data",
+			id:   "nodeA",
+			recordsToReturn: []map[string]*dynamodb.AttributeValue{
+				map[string]*dynamodb.AttributeValue{
+					"id": {
+						S: aws.String("nodeA"),
+					},
+					"rng": {
+						S: aws.String("node"),
+					},
+				},
+				map[string]*dynamodb.AttributeValue{
+					"id": {
+						S: aws.String("nodeA"),
+					},
+					"rng": {
+						S: aws.String("child/childNode"),
+					},
+				},
+				map[string]*dynamodb.AttributeValue{
+					"id": {
+						S: aws.String("nodeA"),
+					},
+					"rng": {
+						S: aws.String("child/childNode/data/testEdgeData"),
+					},
+					"t": {
+						S: aws.String("testEdgeData"),
+					},
+					"edgeDataField": {
+						N: aws.String(strconv.Itoa(123)),
+					},
+				},
+			},
+			keysToDelete: []map[string]*dynamodb.AttributeValue{
+				map[string]*dynamodb.AttributeValue{
+					"id": {
+						S: aws.String("nodeA"),
+					},
+					"rng": {
+						S: aws.String("node"),
+					},
+				},
+				// The relationship from the parent to the child.
+				map[string]*dynamodb.AttributeValue{
+					"id": {
+						S: aws.String("nodeA"),
+					},
+					"rng": {
+						S: aws.String("child/childNode"),
+					},
+				},
+				// The relationship from the child to the parent.
+				map[string]*dynamodb.AttributeValue{
+					"id": {
+						S: aws.String("childNode"),
+					},
+					"rng": {
+						S: aws.String("parent/nodeA"),
+					},
+				},
+				// The edge data stored on the parent.
+				map[string]*dynamodb.AttributeValue{
+					"id": {
+						S: aws.String("nodeA"),
+					},
+					"rng": {
+						S: aws.String("child/childNode/data/testEdgeData"),
+					},
+				},
+				// The edge data stored on the child.
+				map[string]*dynamodb.AttributeValue{
+					"id": {
+						S: aws.String("childNode"),
+					},
+					"rng": {
+						S: aws.String("parent/nodeA/data/testEdgeData"),
 					},
 				},
 			},
 		},
 		{
-			name: "A node record with children is fully deleted, including deleting the edge data",
-			id:   "nodeA",
+			name: "A node record with parents is fully deleted, including deleting the edge data",
+			id:   "child",
 			recordsToReturn: []map[string]*dynamodb.AttributeValue{
 				map[string]*dynamodb.AttributeValue{
 					"id": {
-						S: aws.String("nodeA"),
+						S: aws.String("child"),
 					},
 					"rng": {
 						S: aws.String("node"),
@@ -1127,18 +2689,18 @@ func TestStoreDelete(t *testing.T) {
 				},
 				map[string]*dynamodb.AttributeValue{
 					"id": {
-						S: aws.String("nodeA"),
+						S: aws.String("child"),
 					},
 					"rng": {
-						S: aws.String("child/childNode"),
+						S: aws.String("parent/parentNode"),
 					},
 				},
 				map[string]*dynamodb.AttributeValue{
 					"id": {
-						S: aws.String("nodeA"),
+						S: aws.String("child"),
 					},
 					"rng": {
-						S: aws.String("child/childNode/data/testEdgeData"),
+						S: aws.String("parent/parentNode/data/testEdgeData"),
 					},
 					"t": {
 						S: aws.String("testEdgeData"),
@@ -1151,132 +2713,627 @@ func TestStoreDelete(t *testing.T) {
 			keysToDelete: []map[string]*dynamodb.AttributeValue{
 				map[string]*dynamodb.AttributeValue{
 					"id": {
-						S: aws.String("nodeA"),
+						S: aws.String("child"),
 					},
 					"rng": {
 						S: aws.String("node"),
 					},
 				},
-				// The relationship from the parent to the child.
+				// The relationship from the child to the parent.
 				map[string]*dynamodb.AttributeValue{
 					"id": {
-						S: aws.String("nodeA"),
+						S: aws.String("child"),
 					},
 					"rng": {
-						S: aws.String("child/childNode"),
+						S: aws.String("parent/parentNode"),
 					},
 				},
-				// The relationship from the child to the parent.
+				// The relationship from the parent to the child.
 				map[string]*dynamodb.AttributeValue{
 					"id": {
-						S: aws.String("childNode"),
+						S: aws.String("parentNode"),
 					},
 					"rng": {
-						S: aws.String("parent/nodeA"),
+						S: aws.String("child/child"),
 					},
 				},
-				// The edge data stored on the parent.
+				// The edge data stored on the child.
 				map[string]*dynamodb.AttributeValue{
 					"id": {
-						S: aws.String("nodeA"),
+						S: aws.String("child"),
 					},
 					"rng": {
-						S: aws.String("child/childNode/data/testEdgeData"),
+						S: aws.String("parent/parentNode/data/testEdgeData"),
 					},
 				},
-				// The edge data stored on the child.
+				// The edge data stored on the parent.
 				map[string]*dynamodb.AttributeValue{
 					"id": {
-						S: aws.String("childNode"),
+						S: aws.String("parentNode"),
 					},
 					"rng": {
-						S: aws.String("parent/nodeA/data/testEdgeData"),
+						S: aws.String("child/child/data/testEdgeData"),
 					},
 				},
 			},
 		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			client := newdynamoDBClient()
+			callCount := 0
+			client.queryByIDer = func(idField, idValue string) ([]map[string]*dynamodb.AttributeValue, db.ConsumedCapacity, error) {
+				if idField != "id" {
+					t.Errorf("unexpected idField value of '%s'", idField)
+				}
+				if idValue != test.id {
+					t.Errorf("expected id of '%s', got '%s'", test.id, idValue)
+				}
+				defer func() { callCount++ }()
+				if callCount > 0 {
+					t.Errorf("expected QueryByID to be called once, but was called %d times", callCount+1)
+				}
+				cc := db.ConsumedCapacity{ConsumedCapacity: 1, ConsumedReadCapacity: 3, ConsumedWriteCapacity: 5}
+				err := test.queryByIDOutputErr
+				return test.recordsToReturn, cc, err
+			}
+			client.batchDeleter = func(keys []map[string]*dynamodb.AttributeValue) (db.ConsumedCapacity, error) {
+				if !reflect.DeepEqual(keys, test.keysToDelete) {
+					t.Errorf("\nexpected:\n%+v\n\ngot:\n%+v\n", format(test.keysToDelete), format(keys))
+				}
+				return db.ConsumedCapacity{ConsumedCapacity: 1, ConsumedReadCapacity: 3, ConsumedWriteCapacity: 5}, test.deleteOutputErr
+			}
+			s := NewStoreWithClient(client)
+			s.RegisterDataType(func() interface{} {
+				return &testNodeData{}
+			})
+			s.RegisterDataType(func() interface{} {
+				return &testEdgeData{}
+			})
+			err := s.Delete(test.id)
+			if err != test.expectedErr {
+				t.Errorf("expected err %v, got %v", test.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestStoreSoftDelete(t *testing.T) {
+	now := time.Date(2019, time.February, 2, 12, 30, 0, 0, time.UTC)
+	nodeRecord := map[string]*dynamodb.AttributeValue{
+		"id":  {S: aws.String("nodeA")},
+		"rng": {S: aws.String("node")},
+	}
+	deletedNodeRecord := map[string]*dynamodb.AttributeValue{
+		"id":  {S: aws.String("nodeA")},
+		"rng": {S: aws.String("node")},
+		"_d":  {S: aws.String("2019-02-02T12:30:00Z")},
+	}
+	childRecord := map[string]*dynamodb.AttributeValue{
+		"id":  {S: aws.String("nodeA")},
+		"rng": {S: aws.String("child/childA")},
+	}
+
+	t.Run("Delete writes a tombstone instead of deleting records", func(t *testing.T) {
+		client := newdynamoDBClient()
+		client.getItemer = func(key map[string]*dynamodb.AttributeValue) (map[string]*dynamodb.AttributeValue, db.ConsumedCapacity, error) {
+			return nodeRecord, db.ConsumedCapacity{}, nil
+		}
+		client.batchDeleter = func(keys []map[string]*dynamodb.AttributeValue) (db.ConsumedCapacity, error) {
+			t.Errorf("unexpected delete of %s", format(keys))
+			return db.ConsumedCapacity{}, nil
+		}
+		var actualItems []map[string]*dynamodb.AttributeValue
+		client.batchPutter = func(items []map[string]*dynamodb.AttributeValue) (db.ConsumedCapacity, error) {
+			actualItems = items
+			return db.ConsumedCapacity{}, nil
+		}
+		s := NewStoreWithClient(client)
+		s.SoftDelete = true
+		s.Now = func() time.Time { return now }
+		err := s.Delete("nodeA")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		expectedItems := []map[string]*dynamodb.AttributeValue{deletedNodeRecord}
+		if !reflect.DeepEqual(actualItems, expectedItems) {
+			t.Errorf("\nexpected:\n%s\n\ngot:\n%s\n", format(expectedItems), format(actualItems))
+		}
+	})
+	t.Run("Undelete removes the tombstone", func(t *testing.T) {
+		client := newdynamoDBClient()
+		client.getItemer = func(key map[string]*dynamodb.AttributeValue) (map[string]*dynamodb.AttributeValue, db.ConsumedCapacity, error) {
+			return deletedNodeRecord, db.ConsumedCapacity{}, nil
+		}
+		var actualItems []map[string]*dynamodb.AttributeValue
+		client.batchPutter = func(items []map[string]*dynamodb.AttributeValue) (db.ConsumedCapacity, error) {
+			actualItems = items
+			return db.ConsumedCapacity{}, nil
+		}
+		s := NewStoreWithClient(client)
+		err := s.Undelete("nodeA")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		expectedItems := []map[string]*dynamodb.AttributeValue{nodeRecord}
+		if !reflect.DeepEqual(actualItems, expectedItems) {
+			t.Errorf("\nexpected:\n%s\n\ngot:\n%s\n", format(expectedItems), format(actualItems))
+		}
+	})
+	t.Run("Get excludes deleted nodes unless they're requested", func(t *testing.T) {
+		client := newdynamoDBClient()
+		client.queryByIDer = func(idField, idValue string) ([]map[string]*dynamodb.AttributeValue, db.ConsumedCapacity, error) {
+			return []map[string]*dynamodb.AttributeValue{deletedNodeRecord, childRecord}, db.ConsumedCapacity{}, nil
+		}
+		s := NewStoreWithClient(client)
+		_, ok, err := s.Get("nodeA")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if ok {
+			t.Errorf("expected deleted node to not be found")
+		}
+		n, ok, err := s.GetWithOptions("nodeA", allRecords.includingDeleted())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !ok || !n.Deleted {
+			t.Errorf("expected deleted node to be found and marked as deleted, got %v, %+v", ok, n)
+		}
+		if len(n.Children) != 1 {
+			t.Errorf("expected deleted node to retain its edges, got %+v", n.Children)
+		}
+	})
+}
+
+func TestStoreRenameNode(t *testing.T) {
+	records := []map[string]*dynamodb.AttributeValue{
+		{"id": {S: aws.String("old")}, "rng": {S: aws.String("node")}},
+		{"id": {S: aws.String("old")}, "rng": {S: aws.String("node/data/testNodeData")}, "t": {S: aws.String("testNodeData")}, "extra": {S: aws.String("ABC")}},
+		{"id": {S: aws.String("old")}, "rng": {S: aws.String("child/childA")}},
+		{"id": {S: aws.String("old")}, "rng": {S: aws.String("parent/parentA")}},
+	}
+	tests := []struct {
+		name                  string
+		oldID, newID          string
+		newExists             bool
+		expectedPutItems      []map[string]*dynamodb.AttributeValue
+		expectedKeysToDelete  []map[string]*dynamodb.AttributeValue
+		expectedErr           error
+		expectedQueryByIDCall bool
+	}{
+		{
+			name:        "Missing IDs result in an error",
+			oldID:       "old",
+			expectedErr: ErrMissingNodeID,
+		},
+		{
+			name:                  "Missing nodes result in an error",
+			oldID:                 "missing",
+			newID:                 "new",
+			expectedErr:           ErrNodeNotFound,
+			expectedQueryByIDCall: true,
+		},
+		{
+			name:                  "Renaming to an existing node results in an error",
+			oldID:                 "old",
+			newID:                 "new",
+			newExists:             true,
+			expectedErr:           ErrAlreadyExists,
+			expectedQueryByIDCall: true,
+		},
+		{
+			name:  "The node, data and both sides of the edges are rewritten",
+			oldID: "old",
+			newID: "new",
+			expectedPutItems: []map[string]*dynamodb.AttributeValue{
+				{"id": {S: aws.String("new")}, "rng": {S: aws.String("node")}},
+				{"id": {S: aws.String("new")}, "rng": {S: aws.String("node/data/testNodeData")}, "t": {S: aws.String("testNodeData")}, "extra": {S: aws.String("ABC")}},
+				{"id": {S: aws.String("new")}, "rng": {S: aws.String("child/childA")}},
+				{"id": {S: aws.String("childA")}, "rng": {S: aws.String("parent/new")}},
+				{"id": {S: aws.String("new")}, "rng": {S: aws.String("parent/parentA")}},
+				{"id": {S: aws.String("parentA")}, "rng": {S: aws.String("child/new")}},
+			},
+			expectedKeysToDelete: []map[string]*dynamodb.AttributeValue{
+				{"id": {S: aws.String("old")}, "rng": {S: aws.String("node")}},
+				{"id": {S: aws.String("old")}, "rng": {S: aws.String("node/data/testNodeData")}},
+				{"id": {S: aws.String("old")}, "rng": {S: aws.String("child/childA")}},
+				{"id": {S: aws.String("childA")}, "rng": {S: aws.String("parent/old")}},
+				{"id": {S: aws.String("old")}, "rng": {S: aws.String("parent/parentA")}},
+				{"id": {S: aws.String("parentA")}, "rng": {S: aws.String("child/old")}},
+			},
+			expectedQueryByIDCall: true,
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			client := newdynamoDBClient()
+			var queryByIDCalled bool
+			client.queryByIDer = func(idField, idValue string) ([]map[string]*dynamodb.AttributeValue, db.ConsumedCapacity, error) {
+				queryByIDCalled = true
+				if idValue != "old" {
+					return nil, db.ConsumedCapacity{}, nil
+				}
+				var items []map[string]*dynamodb.AttributeValue
+				for _, r := range records {
+					itm := make(map[string]*dynamodb.AttributeValue)
+					for k, v := range r {
+						itm[k] = v
+					}
+					items = append(items, itm)
+				}
+				return items, db.ConsumedCapacity{}, nil
+			}
+			client.getItemer = func(key map[string]*dynamodb.AttributeValue) (map[string]*dynamodb.AttributeValue, db.ConsumedCapacity, error) {
+				if test.newExists {
+					return key, db.ConsumedCapacity{}, nil
+				}
+				return nil, db.ConsumedCapacity{}, nil
+			}
+			var actualPutItems []map[string]*dynamodb.AttributeValue
+			client.batchPutter = func(items []map[string]*dynamodb.AttributeValue) (db.ConsumedCapacity, error) {
+				actualPutItems = items
+				return db.ConsumedCapacity{}, nil
+			}
+			var actualKeysToDelete []map[string]*dynamodb.AttributeValue
+			client.batchDeleter = func(keys []map[string]*dynamodb.AttributeValue) (db.ConsumedCapacity, error) {
+				actualKeysToDelete = keys
+				return db.ConsumedCapacity{}, nil
+			}
+			s := NewStoreWithClient(client)
+			s.RegisterDataType(func() interface{} {
+				return &testNodeData{}
+			})
+			err := s.RenameNode(test.oldID, test.newID)
+			if err != test.expectedErr {
+				t.Errorf("expected err %v, got %v", test.expectedErr, err)
+			}
+			if queryByIDCalled != test.expectedQueryByIDCall {
+				t.Errorf("expected query call %v, got %v", test.expectedQueryByIDCall, queryByIDCalled)
+			}
+			if !reflect.DeepEqual(actualPutItems, test.expectedPutItems) {
+				t.Errorf("put:\nexpected:\n%s\n\ngot:\n%s\n", format(test.expectedPutItems), format(actualPutItems))
+			}
+			if !reflect.DeepEqual(actualKeysToDelete, test.expectedKeysToDelete) {
+				t.Errorf("delete:\nexpected:\n%s\n\ngot:\n%s\n", format(test.expectedKeysToDelete), format(actualKeysToDelete))
+			}
+		})
+	}
+}
+
+func TestStoreCloneNode(t *testing.T) {
+	records := []map[string]*dynamodb.AttributeValue{
+		{"id": {S: aws.String("source")}, "rng": {S: aws.String("node")}},
+		{"id": {S: aws.String("source")}, "rng": {S: aws.String("node/data/testNodeData")}, "t": {S: aws.String("testNodeData")}, "extra": {S: aws.String("ABC")}},
+		{"id": {S: aws.String("source")}, "rng": {S: aws.String("child/childA")}},
+	}
+	tests := []struct {
+		name             string
+		sourceID, destID string
+		includeEdges     bool
+		expectedNodeItem map[string]*dynamodb.AttributeValue
+		expectedItems    []map[string]*dynamodb.AttributeValue
+		expectedErr      error
+	}{
+		{
+			name:        "Missing IDs result in an error",
+			sourceID:    "source",
+			expectedErr: ErrMissingNodeID,
+		},
+		{
+			name:        "Missing source nodes result in an error",
+			sourceID:    "missing",
+			destID:      "dest",
+			expectedErr: ErrNodeNotFound,
+		},
+		{
+			name:     "Data is copied without edges",
+			sourceID: "source",
+			destID:   "dest",
+			expectedNodeItem: map[string]*dynamodb.AttributeValue{
+				"id":  {S: aws.String("dest")},
+				"rng": {S: aws.String("node")},
+			},
+			expectedItems: []map[string]*dynamodb.AttributeValue{
+				{"id": {S: aws.String("dest")}, "rng": {S: aws.String("node/data/testNodeData")}, "t": {S: aws.String("testNodeData")}, "extra": {S: aws.String("ABC")}},
+			},
+		},
+		{
+			name:         "Edges can be copied",
+			sourceID:     "source",
+			destID:       "dest",
+			includeEdges: true,
+			expectedNodeItem: map[string]*dynamodb.AttributeValue{
+				"id":  {S: aws.String("dest")},
+				"rng": {S: aws.String("node")},
+			},
+			expectedItems: []map[string]*dynamodb.AttributeValue{
+				{"id": {S: aws.String("dest")}, "rng": {S: aws.String("node/data/testNodeData")}, "t": {S: aws.String("testNodeData")}, "extra": {S: aws.String("ABC")}},
+				{"id": {S: aws.String("dest")}, "rng": {S: aws.String("child/childA")}},
+				{"id": {S: aws.String("childA")}, "rng": {S: aws.String("parent/dest")}},
+			},
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			client := newdynamoDBClient()
+			client.queryByIDer = func(idField, idValue string) ([]map[string]*dynamodb.AttributeValue, db.ConsumedCapacity, error) {
+				if idValue != "source" {
+					return nil, db.ConsumedCapacity{}, nil
+				}
+				var items []map[string]*dynamodb.AttributeValue
+				for _, r := range records {
+					itm := make(map[string]*dynamodb.AttributeValue)
+					for k, v := range r {
+						itm[k] = v
+					}
+					items = append(items, itm)
+				}
+				return items, db.ConsumedCapacity{}, nil
+			}
+			var actualNodeItem map[string]*dynamodb.AttributeValue
+			client.putIfNotExister = func(idField string, item map[string]*dynamodb.AttributeValue) (db.ConsumedCapacity, error) {
+				actualNodeItem = item
+				return db.ConsumedCapacity{}, nil
+			}
+			var actualItems []map[string]*dynamodb.AttributeValue
+			client.batchPutter = func(items []map[string]*dynamodb.AttributeValue) (db.ConsumedCapacity, error) {
+				actualItems = items
+				return db.ConsumedCapacity{}, nil
+			}
+			s := NewStoreWithClient(client)
+			s.RegisterDataType(func() interface{} {
+				return &testNodeData{}
+			})
+			err := s.CloneNode(test.sourceID, test.destID, test.includeEdges)
+			if err != test.expectedErr {
+				t.Errorf("expected err %v, got %v", test.expectedErr, err)
+			}
+			if !reflect.DeepEqual(actualNodeItem, test.expectedNodeItem) {
+				t.Errorf("expected node item %+v, got %+v", test.expectedNodeItem, actualNodeItem)
+			}
+			if !reflect.DeepEqual(actualItems, test.expectedItems) {
+				t.Errorf("\nexpected:\n%s\n\ngot:\n%s\n", format(test.expectedItems), format(actualItems))
+			}
+		})
+	}
+}
+
+func TestStoreDeleteNodeData(t *testing.T) {
+	tests := []struct {
+		name                 string
+		id                   string
+		dataType             string
+		aliases              []string
+		expectedKeysToDelete []map[string]*dynamodb.AttributeValue
+		expectedErr          error
+	}{
+		{
+			name:        "Missing node IDs result in an error",
+			dataType:    "testNodeData",
+			expectedErr: ErrMissingNodeID,
+		},
+		{
+			name:        "Missing data types result in an error",
+			id:          "nodeA",
+			expectedErr: ErrMissingDataType,
+		},
+		{
+			name:     "Only the data record is deleted",
+			id:       "nodeA",
+			dataType: "testNodeData",
+			expectedKeysToDelete: []map[string]*dynamodb.AttributeValue{
+				{
+					"id":  {S: aws.String("nodeA")},
+					"rng": {S: aws.String("node/data/testNodeData")},
+				},
+			},
+		},
+		{
+			name:     "Aliases are resolved, and data stored under each alias is deleted",
+			id:       "nodeA",
+			dataType: "oldTestNodeData",
+			aliases:  []string{"oldTestNodeData", "olderTestNodeData"},
+			expectedKeysToDelete: []map[string]*dynamodb.AttributeValue{
+				{
+					"id":  {S: aws.String("nodeA")},
+					"rng": {S: aws.String("node/data/testNodeData")},
+				},
+				{
+					"id":  {S: aws.String("nodeA")},
+					"rng": {S: aws.String("node/data/oldTestNodeData")},
+				},
+				{
+					"id":  {S: aws.String("nodeA")},
+					"rng": {S: aws.String("node/data/olderTestNodeData")},
+				},
+			},
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			client := newdynamoDBClient()
+			var actualKeysToDelete []map[string]*dynamodb.AttributeValue
+			client.batchDeleter = func(keys []map[string]*dynamodb.AttributeValue) (db.ConsumedCapacity, error) {
+				actualKeysToDelete = keys
+				return db.ConsumedCapacity{ConsumedCapacity: 1}, nil
+			}
+			s := NewStoreWithClient(client)
+			if len(test.aliases) > 0 {
+				s.RegisterNamedDataType("testNodeData", func() interface{} { return &testNodeData{} }, test.aliases...)
+			}
+			err := s.DeleteNodeData(test.id, test.dataType)
+			if err != test.expectedErr {
+				t.Errorf("expected err %v, got %v", test.expectedErr, err)
+			}
+			if !reflect.DeepEqual(actualKeysToDelete, test.expectedKeysToDelete) {
+				t.Errorf("\nexpected:\n%s\n\ngot:\n%s\n", format(test.expectedKeysToDelete), format(actualKeysToDelete))
+			}
+		})
+	}
+}
+
+func TestStoreUpdateNodeData(t *testing.T) {
+	tests := []struct {
+		name        string
+		id          string
+		dataType    string
+		updates     map[string]interface{}
+		timestamps  bool
+		expectedKey map[string]*dynamodb.AttributeValue
+		expectedSet map[string]*dynamodb.AttributeValue
+		expectedErr error
+	}{
+		{
+			name:        "Missing node IDs result in an error",
+			dataType:    "testNodeData",
+			expectedErr: ErrMissingNodeID,
+		},
+		{
+			name:        "Missing data types result in an error",
+			id:          "nodeA",
+			expectedErr: ErrMissingDataType,
+		},
+		{
+			name:        "Reserved attributes can't be updated",
+			id:          "nodeA",
+			dataType:    "testNodeData",
+			updates:     map[string]interface{}{"rng": "child/nodeB"},
+			expectedErr: ErrReservedAttributeName,
+		},
+		{
+			name:     "Only the updated fields are set",
+			id:       "nodeA",
+			dataType: "testNodeData",
+			updates:  map[string]interface{}{"name": "A", "count": 2},
+			expectedKey: map[string]*dynamodb.AttributeValue{
+				"id":  {S: aws.String("nodeA")},
+				"rng": {S: aws.String("node/data/testNodeData")},
+			},
+			expectedSet: map[string]*dynamodb.AttributeValue{
+				"name":  {S: aws.String("A")},
+				"count": {N: aws.String("2")},
+				"t":     {S: aws.String("testNodeData")},
+			},
+		},
+		{
+			name:       "The updated time is set if timestamps are enabled",
+			id:         "nodeA",
+			dataType:   "testNodeData",
+			updates:    map[string]interface{}{"name": "A"},
+			timestamps: true,
+			expectedKey: map[string]*dynamodb.AttributeValue{
+				"id":  {S: aws.String("nodeA")},
+				"rng": {S: aws.String("node/data/testNodeData")},
+			},
+			expectedSet: map[string]*dynamodb.AttributeValue{
+				"name": {S: aws.String("A")},
+				"t":    {S: aws.String("testNodeData")},
+				"_u":   {S: aws.String("2000-01-01T00:00:00Z")},
+			},
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			client := newdynamoDBClient()
+			var actualKey, actualSet map[string]*dynamodb.AttributeValue
+			client.updateItemer = func(key, set map[string]*dynamodb.AttributeValue) (db.ConsumedCapacity, error) {
+				actualKey, actualSet = key, set
+				return db.ConsumedCapacity{}, nil
+			}
+			s := NewStoreWithClient(client)
+			s.Timestamps = test.timestamps
+			s.Now = func() time.Time { return time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC) }
+			err := s.UpdateNodeData(test.id, test.dataType, test.updates)
+			if err != test.expectedErr {
+				t.Errorf("expected err %v, got %v", test.expectedErr, err)
+			}
+			if !reflect.DeepEqual(actualKey, test.expectedKey) {
+				t.Errorf("expected key %v, got %v", test.expectedKey, actualKey)
+			}
+			if !reflect.DeepEqual(actualSet, test.expectedSet) {
+				t.Errorf("expected set %v, got %v", test.expectedSet, actualSet)
+			}
+		})
+	}
+}
+
+func TestStoreDeleteEdgeData(t *testing.T) {
+	tests := []struct {
+		name                 string
+		parent, child        string
+		label                string
+		dataType             string
+		expectedKeysToDelete []map[string]*dynamodb.AttributeValue
+		expectedErr          error
+	}{
+		{
+			name:        "Missing node IDs result in an error",
+			parent:      "nodeA",
+			dataType:    "testEdgeData",
+			expectedErr: ErrMissingNodeID,
+		},
 		{
-			name: "A node record with parents is fully deleted, including deleting the edge data",
-			id:   "child",
-			recordsToReturn: []map[string]*dynamodb.AttributeValue{
-				map[string]*dynamodb.AttributeValue{
-					"id": {
-						S: aws.String("child"),
-					},
-					"rng": {
-						S: aws.String("node"),
-					},
-				},
-				map[string]*dynamodb.AttributeValue{
-					"id": {
-						S: aws.String("child"),
-					},
-					"rng": {
-						S: aws.String("parent/parentNode"),
-					},
-				},
-				map[string]*dynamodb.AttributeValue{
-					"id": {
-						S: aws.String("child"),
-					},
-					"rng": {
-						S: aws.String("parent/parentNode/data/testEdgeData"),
-					},
-					"t": {
-						S: aws.String("testEdgeData"),
-					},
-					"edgeDataField": {
-						N: aws.String(strconv.Itoa(123)),
-					},
+			name:        "Missing data types result in an error",
+			parent:      "nodeA",
+			child:       "nodeB",
+			expectedErr: ErrMissingDataType,
+		},
+		{
+			name:     "Both sides of the edge data are deleted",
+			parent:   "nodeA",
+			child:    "nodeB",
+			dataType: "testEdgeData",
+			expectedKeysToDelete: []map[string]*dynamodb.AttributeValue{
+				{
+					"id":  {S: aws.String("nodeA")},
+					"rng": {S: aws.String("child/nodeB/data/testEdgeData")},
+				},
+				{
+					"id":  {S: aws.String("nodeB")},
+					"rng": {S: aws.String("parent/nodeA/data/testEdgeData")},
 				},
 			},
-			keysToDelete: []map[string]*dynamodb.AttributeValue{
-				map[string]*dynamodb.AttributeValue{
-					"id": {
-						S: aws.String("child"),
-					},
-					"rng": {
-						S: aws.String("node"),
-					},
-				},
-				// The relationship from the child to the parent.
-				map[string]*dynamodb.AttributeValue{
-					"id": {
-						S: aws.String("child"),
-					},
-					"rng": {
-						S: aws.String("parent/parentNode"),
-					},
-				},
-				// The relationship from the parent to the child.
-				map[string]*dynamodb.AttributeValue{
-					"id": {
-						S: aws.String("parentNode"),
-					},
-					"rng": {
-						S: aws.String("child/child"),
-					},
-				},
-				// The edge data stored on the child.
-				map[string]*dynamodb.AttributeValue{
-					"id": {
-						S: aws.String("child"),
-					},
-					"rng": {
-						S: aws.String("parent/parentNode/data/testEdgeData"),
-					},
-				},
-				// The edge data stored on the parent.
-				map[string]*dynamodb.AttributeValue{
-					"id": {
-						S: aws.String("parentNode"),
-					},
-					"rng": {
-						S: aws.String("child/child/data/testEdgeData"),
-					},
+		},
+		{
+			name:     "Both sides of the data of a labelled edge are deleted",
+			parent:   "nodeA",
+			child:    "nodeB",
+			label:    "link",
+			dataType: "testEdgeData",
+			expectedKeysToDelete: []map[string]*dynamodb.AttributeValue{
+				{
+					"id":  {S: aws.String("nodeA")},
+					"rng": {S: aws.String("child/link/nodeB/data/testEdgeData")},
+				},
+				{
+					"id":  {S: aws.String("nodeB")},
+					"rng": {S: aws.String("parent/link/nodeA/data/testEdgeData")},
 				},
 			},
 		},
+		{
+			name:        "Missing node IDs of labelled edges result in an error",
+			child:       "nodeB",
+			label:       "link",
+			dataType:    "testEdgeData",
+			expectedErr: ErrMissingNodeID,
+		},
 	}
 	for _, test := range tests {
 		test := test
@@ -1284,39 +3341,24 @@ func TestStoreDelete(t *testing.T) {
 			t.Parallel()
 
 			client := newdynamoDBClient()
-			callCount := 0
-			client.queryByIDer = func(idField, idValue string) ([]map[string]*dynamodb.AttributeValue, db.ConsumedCapacity, error) {
-				if idField != "id" {
-					t.Errorf("unexpected idField value of '%s'", idField)
-				}
-				if idValue != test.id {
-					t.Errorf("expected id of '%s', got '%s'", test.id, idValue)
-				}
-				defer func() { callCount++ }()
-				if callCount > 0 {
-					t.Errorf("expected QueryByID to be called once, but was called %d times", callCount+1)
-				}
-				cc := db.ConsumedCapacity{ConsumedCapacity: 1, ConsumedReadCapacity: 3, ConsumedWriteCapacity: 5}
-				err := test.queryByIDOutputErr
-				return test.recordsToReturn, cc, err
-			}
+			var actualKeysToDelete []map[string]*dynamodb.AttributeValue
 			client.batchDeleter = func(keys []map[string]*dynamodb.AttributeValue) (db.ConsumedCapacity, error) {
-				if !reflect.DeepEqual(keys, test.keysToDelete) {
-					t.Errorf("\nexpected:\n%+v\n\ngot:\n%+v\n", format(test.keysToDelete), format(keys))
-				}
-				return db.ConsumedCapacity{ConsumedCapacity: 1, ConsumedReadCapacity: 3, ConsumedWriteCapacity: 5}, test.deleteOutputErr
+				actualKeysToDelete = keys
+				return db.ConsumedCapacity{ConsumedCapacity: 1}, nil
 			}
 			s := NewStoreWithClient(client)
-			s.RegisterDataType(func() interface{} {
-				return &testNodeData{}
-			})
-			s.RegisterDataType(func() interface{} {
-				return &testEdgeData{}
-			})
-			err := s.Delete(test.id)
+			var err error
+			if test.label == "" {
+				err = s.DeleteEdgeData(test.parent, test.child, test.dataType)
+			} else {
+				err = s.DeleteLabelledEdgeData(test.parent, test.child, test.label, test.dataType)
+			}
 			if err != test.expectedErr {
 				t.Errorf("expected err %v, got %v", test.expectedErr, err)
 			}
+			if !reflect.DeepEqual(actualKeysToDelete, test.expectedKeysToDelete) {
+				t.Errorf("\nexpected:\n%s\n\ngot:\n%s\n", format(test.expectedKeysToDelete), format(actualKeysToDelete))
+			}
 		})
 	}
 }
@@ -1593,6 +3635,88 @@ func TestStoreDeleteEdge(t *testing.T) {
 	}
 }
 
+func TestStoreDeleteEdges(t *testing.T) {
+	records := []map[string]*dynamodb.AttributeValue{
+		{"id": {S: aws.String("nodeA")}, "rng": {S: aws.String("node")}},
+		{"id": {S: aws.String("nodeA")}, "rng": {S: aws.String("child/childA")}},
+		{"id": {S: aws.String("nodeA")}, "rng": {S: aws.String("child/childB")}},
+		{"id": {S: aws.String("nodeA")}, "rng": {S: aws.String("child/childC")}},
+	}
+	tests := []struct {
+		name                 string
+		parent               string
+		children             []string
+		expectedKeysToDelete []map[string]*dynamodb.AttributeValue
+		expectedErr          error
+	}{
+		{
+			name:        "Missing parent IDs result in an error",
+			children:    []string{"childA"},
+			expectedErr: ErrMissingNodeID,
+		},
+		{
+			name:        "Missing child IDs result in an error",
+			parent:      "nodeA",
+			children:    []string{"childA", ""},
+			expectedErr: ErrMissingNodeID,
+		},
+		{
+			name:   "No children doesn't delete anything",
+			parent: "nodeA",
+		},
+		{
+			name:     "Children which aren't edges of the parent are ignored",
+			parent:   "nodeA",
+			children: []string{"childD"},
+		},
+		{
+			name:     "All matching edges are deleted in a single batch",
+			parent:   "nodeA",
+			children: []string{"childA", "childC", "childD"},
+			expectedKeysToDelete: []map[string]*dynamodb.AttributeValue{
+				{"id": {S: aws.String("nodeA")}, "rng": {S: aws.String("child/childA")}},
+				{"id": {S: aws.String("childA")}, "rng": {S: aws.String("parent/nodeA")}},
+				{"id": {S: aws.String("nodeA")}, "rng": {S: aws.String("child/childC")}},
+				{"id": {S: aws.String("childC")}, "rng": {S: aws.String("parent/nodeA")}},
+			},
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			client := newdynamoDBClient()
+			var queryCount int
+			client.queryByIDer = func(idField, idValue string) ([]map[string]*dynamodb.AttributeValue, db.ConsumedCapacity, error) {
+				queryCount++
+				return records, db.ConsumedCapacity{}, nil
+			}
+			var deleteCount int
+			var actualKeysToDelete []map[string]*dynamodb.AttributeValue
+			client.batchDeleter = func(keys []map[string]*dynamodb.AttributeValue) (db.ConsumedCapacity, error) {
+				deleteCount++
+				actualKeysToDelete = keys
+				return db.ConsumedCapacity{}, nil
+			}
+			s := NewStoreWithClient(client)
+			err := s.DeleteEdges(test.parent, test.children...)
+			if err != test.expectedErr {
+				t.Errorf("expected err %v, got %v", test.expectedErr, err)
+			}
+			if queryCount > 1 {
+				t.Errorf("expected the parent to be read at most once, but was read %d times", queryCount)
+			}
+			if deleteCount > 1 {
+				t.Errorf("expected a single delete batch, got %d", deleteCount)
+			}
+			if !reflect.DeepEqual(actualKeysToDelete, test.expectedKeysToDelete) {
+				t.Errorf("\nexpected:\n%s\n\ngot:\n%s\n", format(test.expectedKeysToDelete), format(actualKeysToDelete))
+			}
+		})
+	}
+}
+
 func format(v []map[string]*dynamodb.AttributeValue) string {
 	var b bytes.Buffer
 	for _, vv := range v {
@@ -1601,6 +3725,159 @@ func format(v []map[string]*dynamodb.AttributeValue) string {
 	return b.String()
 }
 
+func TestStoreCapacity(t *testing.T) {
+	client := newdynamoDBClient()
+	client.getItemer = func(key map[string]*dynamodb.AttributeValue) (map[string]*dynamodb.AttributeValue, db.ConsumedCapacity, error) {
+		return nil, db.ConsumedCapacity{ConsumedCapacity: 1, ConsumedReadCapacity: 2}, nil
+	}
+	s := NewStoreWithClient(client)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.Exists("id")
+		}()
+	}
+	wg.Wait()
+
+	expected := db.ConsumedCapacity{ConsumedCapacity: 100, ConsumedReadCapacity: 200}
+	if actual := s.CapacitySnapshot(); actual != expected {
+		t.Errorf("expected capacity %+v, got %+v", expected, actual)
+	}
+	if s.ConsumedCapacity() != 100 || s.ConsumedReadCapacity() != 200 || s.ConsumedWriteCapacity() != 0 {
+		t.Errorf("expected the deprecated accessors to return the snapshot, got %v, %v, %v", s.ConsumedCapacity(), s.ConsumedReadCapacity(), s.ConsumedWriteCapacity())
+	}
+	if previous := s.ResetCapacity(); previous != expected {
+		t.Errorf("expected reset to return the previous capacity %+v, got %+v", expected, previous)
+	}
+	if actual := s.CapacitySnapshot(); actual != (db.ConsumedCapacity{}) {
+		t.Errorf("expected capacity to be reset, got %+v", actual)
+	}
+}
+
+func TestStoreOperationCapacity(t *testing.T) {
+	client := newdynamoDBClient()
+	client.batchGetter = func(keys []map[string]*dynamodb.AttributeValue) ([]map[string]*dynamodb.AttributeValue, db.ConsumedCapacity, error) {
+		return keys, db.ConsumedCapacity{ConsumedCapacity: 1, ConsumedReadCapacity: 1}, nil
+	}
+	client.queryByIDer = func(idField, idValue string) ([]map[string]*dynamodb.AttributeValue, db.ConsumedCapacity, error) {
+		return []map[string]*dynamodb.AttributeValue{
+			{"id": {S: aws.String(idValue)}, "rng": {S: aws.String("node")}},
+		}, db.ConsumedCapacity{ConsumedCapacity: 1, ConsumedReadCapacity: 1}, nil
+	}
+	client.batchPutter = func(items []map[string]*dynamodb.AttributeValue) (db.ConsumedCapacity, error) {
+		return db.ConsumedCapacity{ConsumedCapacity: 2, ConsumedWriteCapacity: 2}, nil
+	}
+	client.batchDeleter = func(keys []map[string]*dynamodb.AttributeValue) (db.ConsumedCapacity, error) {
+		return db.ConsumedCapacity{ConsumedCapacity: 3, ConsumedWriteCapacity: 3}, nil
+	}
+	s := NewStoreWithClient(client)
+
+	cc, err := s.PutWithCapacity(NewNode("nodeA"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := (db.ConsumedCapacity{ConsumedCapacity: 2, ConsumedWriteCapacity: 2}); cc != expected {
+		t.Errorf("put: expected %+v, got %+v", expected, cc)
+	}
+	_, ok, cc, err := s.GetWithCapacity("nodeA")
+	if err != nil || !ok {
+		t.Fatalf("expected node to be found, got ok=%v, err=%v", ok, err)
+	}
+	if expected := (db.ConsumedCapacity{ConsumedCapacity: 1, ConsumedReadCapacity: 1}); cc != expected {
+		t.Errorf("get: expected %+v, got %+v", expected, cc)
+	}
+	nodes, cc, err := s.GetManyWithCapacity("nodeA", "nodeA")
+	if err != nil || len(nodes) != 2 {
+		t.Fatalf("expected 2 nodes, got %d, err=%v", len(nodes), err)
+	}
+	if expected := (db.ConsumedCapacity{ConsumedCapacity: 2, ConsumedReadCapacity: 2}); cc != expected {
+		t.Errorf("get many: expected %+v, got %+v", expected, cc)
+	}
+	cc, err = s.DeleteWithCapacity("nodeA")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := (db.ConsumedCapacity{ConsumedCapacity: 4, ConsumedReadCapacity: 1, ConsumedWriteCapacity: 3}); cc != expected {
+		t.Errorf("delete: expected %+v, got %+v", expected, cc)
+	}
+	if expected := (db.ConsumedCapacity{ConsumedCapacity: 9, ConsumedReadCapacity: 4, ConsumedWriteCapacity: 5}); s.CapacitySnapshot() != expected {
+		t.Errorf("total: expected %+v, got %+v", expected, s.CapacitySnapshot())
+	}
+}
+
+func TestStoreRegisterNamedDataType(t *testing.T) {
+	client := newdynamoDBClient()
+	client.queryByIDer = func(idField, idValue string) ([]map[string]*dynamodb.AttributeValue, db.ConsumedCapacity, error) {
+		return []map[string]*dynamodb.AttributeValue{
+			{"id": {S: aws.String("nodeA")}, "rng": {S: aws.String("node")}},
+			{"id": {S: aws.String("nodeA")}, "rng": {S: aws.String("node/data/oldName")}, "t": {S: aws.String("oldName")}, "extra": {S: aws.String("old")}},
+			{"id": {S: aws.String("nodeA")}, "rng": {S: aws.String("child/nodeB")}},
+			{"id": {S: aws.String("nodeA")}, "rng": {S: aws.String("child/nodeB/data/newName")}, "t": {S: aws.String("newName")}, "extra": {S: aws.String("new")}},
+		}, db.ConsumedCapacity{}, nil
+	}
+	s := NewStoreWithClient(client)
+	s.RegisterNamedDataType("newName", func() interface{} {
+		return &testNodeData{}
+	}, "oldName")
+
+	n, ok, err := s.Get("nodeA")
+	if err != nil || !ok {
+		t.Fatalf("expected node to be found, got ok=%v, err=%v", ok, err)
+	}
+	expected := NewNode("nodeA").
+		WithNamedData("newName", &testNodeData{ExtraAttribute: "old"}).
+		WithChildren(NewEdge("nodeB").WithNamedData("newName", &testNodeData{ExtraAttribute: "new"}))
+	if !reflect.DeepEqual(n, expected) {
+		t.Errorf("expected %+v, got %+v", expected, n)
+	}
+}
+
+type namedTestData struct {
+	Value string `json:"value"`
+}
+
+func (namedTestData) TypeName() string {
+	return "customName"
+}
+
+func TestStoreTypeNamer(t *testing.T) {
+	client := newdynamoDBClient()
+	var actualItems []map[string]*dynamodb.AttributeValue
+	client.batchPutter = func(items []map[string]*dynamodb.AttributeValue) (db.ConsumedCapacity, error) {
+		actualItems = items
+		return db.ConsumedCapacity{}, nil
+	}
+	client.queryByIDer = func(idField, idValue string) ([]map[string]*dynamodb.AttributeValue, db.ConsumedCapacity, error) {
+		return actualItems, db.ConsumedCapacity{}, nil
+	}
+	s := NewStoreWithClient(client)
+	s.RegisterDataType(func() interface{} {
+		return &namedTestData{}
+	})
+
+	err := s.Put(NewNode("nodeA").WithData(namedTestData{Value: "a"}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectedItems := []map[string]*dynamodb.AttributeValue{
+		{"id": {S: aws.String("nodeA")}, "rng": {S: aws.String("node")}},
+		{"id": {S: aws.String("nodeA")}, "rng": {S: aws.String("node/data/customName")}, "t": {S: aws.String("customName")}, "value": {S: aws.String("a")}},
+	}
+	if !reflect.DeepEqual(actualItems, expectedItems) {
+		t.Errorf("\nexpected:\n%s\n\ngot:\n%s\n", format(expectedItems), format(actualItems))
+	}
+	n, _, err := s.Get("nodeA")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if d, ok := n.Data["customName"].(*namedTestData); !ok || d.Value != "a" {
+		t.Errorf("expected data to be read using the type name, got %+v", n.Data)
+	}
+}
+
 func TestNewStore(t *testing.T) {
 	s, err := NewStore("eu-west-2", "exampleTableName")
 	if err != nil {
@@ -1610,3 +3887,102 @@ func TestNewStore(t *testing.T) {
 		t.Errorf("underlying default database has changed to %T, please check", s.Client)
 	}
 }
+
+func TestNewStoreWithSession(t *testing.T) {
+	sess, err := session.NewSession(&aws.Config{
+		Region: aws.String("eu-west-2"),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error creating session: %v", err)
+	}
+	s := NewStoreWithSession(sess, "exampleTableName")
+	client, ok := s.Client.(*db.DB)
+	if !ok {
+		t.Fatalf("underlying default database has changed to %T, please check", s.Client)
+	}
+	if client.TableName != "exampleTableName" {
+		t.Errorf("expected table name 'exampleTableName', got '%s'", client.TableName)
+	}
+	if s.S3 == nil {
+		t.Errorf("expected the S3 client to be set")
+	}
+}
+
+// attributeRecordingDB records the attributes passed to GetItem.
+type attributeRecordingDB struct {
+	DB
+	attributes *[]string
+}
+
+func (ardb *attributeRecordingDB) GetItem(ctx context.Context, key map[string]*dynamodb.AttributeValue, attributes ...string) (map[string]*dynamodb.AttributeValue, db.ConsumedCapacity, error) {
+	*ardb.attributes = attributes
+	return ardb.DB.GetItem(ctx, key, attributes...)
+}
+
+// contextRecordingDB records the context passed to GetItem.
+type contextRecordingDB struct {
+	DB
+	ctx context.Context
+}
+
+func (crdb *contextRecordingDB) GetItem(ctx context.Context, key map[string]*dynamodb.AttributeValue, attributes ...string) (map[string]*dynamodb.AttributeValue, db.ConsumedCapacity, error) {
+	crdb.ctx = ctx
+	return crdb.DB.GetItem(ctx, key, attributes...)
+}
+
+func TestStoreWithContext(t *testing.T) {
+	type contextKey struct{}
+	client := newdynamoDBClient()
+	client.getItemer = func(key map[string]*dynamodb.AttributeValue) (map[string]*dynamodb.AttributeValue, db.ConsumedCapacity, error) {
+		return nil, db.ConsumedCapacity{}, nil
+	}
+	crdb := &contextRecordingDB{DB: client}
+	s := NewStoreWithClient(crdb)
+
+	if _, _, err := s.GetNodeOnly("a"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if crdb.ctx != context.Background() {
+		t.Errorf("expected the background context to be used, got %v", crdb.ctx)
+	}
+
+	ctx := context.WithValue(context.Background(), contextKey{}, "value")
+	if _, _, err := s.WithContext(ctx).GetNodeOnly("a"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if crdb.ctx != ctx {
+		t.Errorf("expected the Store's context to be passed to the database, got %v", crdb.ctx)
+	}
+}
+
+// projectionRecordingDB records the attributes projected by QueryByID.
+type projectionRecordingDB struct {
+	DB
+	attributes []string
+}
+
+func (prdb *projectionRecordingDB) QueryByID(ctx context.Context, idField, idValue string, attributes ...string) ([]map[string]*dynamodb.AttributeValue, db.ConsumedCapacity, error) {
+	prdb.attributes = attributes
+	return prdb.DB.QueryByID(ctx, idField, idValue, attributes...)
+}
+
+func TestStoreDeleteReadsOnlyKeys(t *testing.T) {
+	client := newdynamoDBClient()
+	client.queryByIDer = func(idField, idValue string) ([]map[string]*dynamodb.AttributeValue, db.ConsumedCapacity, error) {
+		return []map[string]*dynamodb.AttributeValue{
+			{"id": {S: aws.String("a")}, "rng": {S: aws.String("node")}},
+		}, db.ConsumedCapacity{}, nil
+	}
+	client.batchDeleter = func(keys []map[string]*dynamodb.AttributeValue) (db.ConsumedCapacity, error) {
+		return db.ConsumedCapacity{}, nil
+	}
+	prdb := &projectionRecordingDB{DB: client}
+	s := NewStoreWithClient(prdb)
+	if err := s.Delete("a"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []string{"id", "rng", "t"}
+	if !reflect.DeepEqual(prdb.attributes, expected) {
+		t.Errorf("expected the attributes %v to be projected, got %v", expected, prdb.attributes)
+	}
+}
@@ -0,0 +1,44 @@
+package pregel
+
+// Storer is the set of graph operations provided by a Store. Depend on it instead of *Store to wrap
+// a Store with decorators, e.g. caching or metrics, or to replace it with a mock in tests.
+type Storer interface {
+	Put(nodes ...Node) error
+	PutTransaction(nodes ...Node) error
+	Create(n Node) error
+	PutNodeData(id string, data Data) error
+	UpdateNodeData(id, dataType string, updates map[string]interface{}) error
+	DeleteNodeData(id, dataType string) error
+	PutEdges(parent string, edges ...*Edge) error
+	PutLabelledEdges(parent, label string, edges ...*Edge) error
+	PutEdgeData(parent, child string, data Data) error
+	DeleteEdgeData(parent, child, dataType string) error
+	DeleteLabelledEdgeData(parent, child, label, dataType string) error
+
+	Get(id string) (n Node, ok bool, err error)
+	GetWithOptions(id string, opts GetOptions) (n Node, ok bool, err error)
+	GetNodeOnly(id string) (n Node, ok bool, err error)
+	GetMany(ids ...string) (nodes []Node, err error)
+	GetChildren(id string, limit int, cursor string) (children []*Edge, next string, err error)
+	GetChildrenByLabel(id, label string, limit int, cursor string) (children []*Edge, next string, err error)
+	GetChildrenWithPrefix(id, prefix string) (children []*Edge, err error)
+	GetEdge(parent, child string) (e *Edge, ok bool, err error)
+	Exists(id string) (ok bool, err error)
+	ListNodes(limit int, cursor string) (ids []string, next string, err error)
+	QueryByLabel(label string, limit int, cursor string) (ids []string, next string, err error)
+	QueryByDataType(dataType string, limit int, cursor string) (ids []string, next string, err error)
+	QueryByAttribute(dataType, field string, value interface{}) (ids []string, err error)
+	GetSubgraph(id string, depth int, opts TraversalOptions) (g Graph, err error)
+	ShortestPath(from, to string, maxDepth int, opts TraversalOptions) (p Path, ok bool, err error)
+
+	Delete(id string) error
+	DeleteCascade(id string, opts CascadeOptions) error
+	Undelete(id string) error
+	RenameNode(oldID, newID string) error
+	CloneNode(sourceID, destID string, includeEdges bool) error
+	DeleteEdge(parent, child string) error
+	DeleteLabelledEdge(parent, child, label string) error
+	DeleteEdges(parent string, children ...string) error
+}
+
+var _ Storer = &Store{}
@@ -0,0 +1,273 @@
+// Package stream decodes the records of a DynamoDB Stream of a pregel table into changes to nodes,
+// edges and data, so that other systems can react to changes to the graph, e.g. by updating a search
+// index or invalidating a cache.
+//
+// Each edge is stored as a child record on the parent node, and a parent record on the child node.
+// To avoid reporting each change twice, only the child records are decoded into EdgeEvents and
+// DataEvents. The table's stream must include new and old images for data to be reported.
+package stream
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/a-h/pregel"
+	"github.com/a-h/pregel/rangefield"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/aws/aws-sdk-go/service/dynamodbstreams"
+)
+
+// Attributes of pregel records, see record.go in the pregel package.
+const (
+	fieldID             = "id"
+	fieldRange          = "rng"
+	fieldRecordDataType = "t"
+	fieldCreatedAt      = "_c"
+	fieldUpdatedAt      = "_u"
+	fieldDeleted        = "_d"
+)
+
+// Change is the kind of change made to a record.
+type Change string
+
+// Changes reported by the stream.
+const (
+	Inserted Change = "INSERT"
+	Modified Change = "MODIFY"
+	Removed  Change = "REMOVE"
+)
+
+// NodeEvent reports a change to a node record, or to one of the node's labels.
+type NodeEvent struct {
+	Change Change
+	ID     string
+	// Label is set when one of the node's labels was added or removed, rather than the node itself.
+	Label string
+	// Deleted is true if the node has been soft deleted.
+	Deleted bool
+	Time    time.Time
+}
+
+// EdgeEvent reports a change to an edge between a parent and a child node.
+type EdgeEvent struct {
+	Change Change
+	Parent string
+	Child  string
+	Label  string
+	Time   time.Time
+}
+
+// DataEvent reports a change to data of a node, or of an edge if Child is set.
+type DataEvent struct {
+	Change Change
+	// NodeID is the node the data belongs to, or the parent node of the edge.
+	NodeID string
+	// Child and Label identify the edge the data belongs to. If Child is empty, the data belongs to
+	// the node.
+	Child    string
+	Label    string
+	DataType string
+	// Old and New are the data before and after the change. Old is nil for inserted data, and New is
+	// nil for removed data.
+	Old  map[string]interface{}
+	New  map[string]interface{}
+	Time time.Time
+}
+
+// Handler receives the changes decoded from a stream.
+type Handler interface {
+	HandleNode(ctx context.Context, e NodeEvent) error
+	HandleEdge(ctx context.Context, e EdgeEvent) error
+	HandleData(ctx context.Context, e DataEvent) error
+}
+
+// ErrMissingStreamRecord is returned when a record doesn't contain the change to the item.
+var ErrMissingStreamRecord = errors.New("stream: the record doesn't contain a stream record")
+
+// Process decodes the records and passes the changes to the handler, in order. Records which aren't
+// pregel records, and the parent records of edges, are skipped. Processing stops at the first error.
+func Process(ctx context.Context, records []*dynamodbstreams.Record, h Handler) (err error) {
+	for _, r := range records {
+		var event interface{}
+		var ok bool
+		event, ok, err = Decode(r)
+		if err != nil {
+			return
+		}
+		if !ok {
+			continue
+		}
+		switch e := event.(type) {
+		case NodeEvent:
+			err = h.HandleNode(ctx, e)
+		case EdgeEvent:
+			err = h.HandleEdge(ctx, e)
+		case DataEvent:
+			err = h.HandleData(ctx, e)
+		}
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+// Dispatch decodes the records into pregel ChangeEvents, the same events emitted by a Store's
+// EmitChanges hook, and dispatches them in order. Records which aren't pregel records, and the parent
+// records of edges, are skipped. Dispatching stops at the first error.
+func Dispatch(ctx context.Context, records []*dynamodbstreams.Record, d *pregel.Dispatcher) (err error) {
+	for _, r := range records {
+		if r == nil || r.Dynamodb == nil {
+			return ErrMissingStreamRecord
+		}
+		op := pregel.RecordModified
+		if r.EventName != nil {
+			switch *r.EventName {
+			case dynamodbstreams.OperationTypeInsert:
+				op = pregel.RecordInserted
+			case dynamodbstreams.OperationTypeRemove:
+				op = pregel.RecordRemoved
+			}
+		}
+		var t time.Time
+		if r.Dynamodb.ApproximateCreationDateTime != nil {
+			t = *r.Dynamodb.ApproximateCreationDateTime
+		}
+		e, ok, eErr := pregel.NewChangeEvent(op, convertItem(r.Dynamodb.Keys), convertItem(r.Dynamodb.OldImage), convertItem(r.Dynamodb.NewImage), t)
+		if eErr != nil {
+			return fmt.Errorf("stream: failed to decode change: %v", eErr)
+		}
+		if !ok {
+			continue
+		}
+		if err = d.Dispatch(ctx, e); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// Decode a stream record into a NodeEvent, EdgeEvent or DataEvent. If the record isn't a pregel
+// record, or is the parent record of an edge, ok is false.
+func Decode(r *dynamodbstreams.Record) (event interface{}, ok bool, err error) {
+	if r == nil || r.Dynamodb == nil {
+		err = ErrMissingStreamRecord
+		return
+	}
+	sr := r.Dynamodb
+	id, rng := stringValue(sr.Keys[fieldID]), stringValue(sr.Keys[fieldRange])
+	f, isPregelRecord := rangefield.Decode(rng)
+	if id == "" || !isPregelRecord {
+		return
+	}
+	var change Change
+	if r.EventName != nil {
+		change = Change(*r.EventName)
+	}
+	var t time.Time
+	if sr.ApproximateCreationDateTime != nil {
+		t = *sr.ApproximateCreationDateTime
+	}
+	switch rf := f.(type) {
+	case rangefield.Node:
+		_, deleted := sr.NewImage[fieldDeleted]
+		return NodeEvent{Change: change, ID: id, Deleted: deleted, Time: t}, true, nil
+	case rangefield.NodeLabel:
+		return NodeEvent{Change: change, ID: id, Label: rf.Label, Time: t}, true, nil
+	case rangefield.Child:
+		return EdgeEvent{Change: change, Parent: id, Child: rf.Child, Label: rf.Label, Time: t}, true, nil
+	case rangefield.NodeData:
+		e := DataEvent{Change: change, NodeID: id, DataType: rf.DataType, Time: t}
+		e.Old, e.New, err = decodeImages(sr)
+		return e, err == nil, err
+	case rangefield.ChildData:
+		e := DataEvent{Change: change, NodeID: id, Child: rf.Child, Label: rf.Label, DataType: rf.DataType, Time: t}
+		e.Old, e.New, err = decodeImages(sr)
+		return e, err == nil, err
+	}
+	return
+}
+
+func decodeImages(sr *dynamodbstreams.StreamRecord) (before, after map[string]interface{}, err error) {
+	if before, err = decodeData(sr.OldImage); err != nil {
+		return
+	}
+	after, err = decodeData(sr.NewImage)
+	return
+}
+
+// decodeData unmarshals the data attributes of a data record's image.
+func decodeData(image map[string]*dynamodbstreams.AttributeValue) (data map[string]interface{}, err error) {
+	if image == nil {
+		return
+	}
+	itm := make(map[string]*dynamodb.AttributeValue, len(image))
+	for k, v := range image {
+		switch k {
+		case fieldID, fieldRange, fieldRecordDataType, fieldCreatedAt, fieldUpdatedAt, pregel.TTLAttributeName:
+			continue
+		}
+		itm[k] = convert(v)
+	}
+	if err = pregel.DecompressRecord(itm); err != nil {
+		err = fmt.Errorf("stream: %v", err)
+		return
+	}
+	data = make(map[string]interface{})
+	if err = dynamodbattribute.UnmarshalMap(itm, &data); err != nil {
+		err = fmt.Errorf("stream: failed to unmarshal data: %v", err)
+	}
+	return
+}
+
+// convertItem converts the attribute values of a stream image into DynamoDB attribute values.
+func convertItem(image map[string]*dynamodbstreams.AttributeValue) (itm map[string]*dynamodb.AttributeValue) {
+	if image == nil {
+		return
+	}
+	itm = make(map[string]*dynamodb.AttributeValue, len(image))
+	for k, v := range image {
+		itm[k] = convert(v)
+	}
+	return
+}
+
+// convert a stream attribute value into the equivalent DynamoDB attribute value.
+func convert(v *dynamodbstreams.AttributeValue) *dynamodb.AttributeValue {
+	if v == nil {
+		return nil
+	}
+	av := &dynamodb.AttributeValue{
+		B:    v.B,
+		BOOL: v.BOOL,
+		BS:   v.BS,
+		N:    v.N,
+		NS:   v.NS,
+		NULL: v.NULL,
+		S:    v.S,
+		SS:   v.SS,
+	}
+	if v.L != nil {
+		av.L = make([]*dynamodb.AttributeValue, len(v.L))
+		for i, lv := range v.L {
+			av.L[i] = convert(lv)
+		}
+	}
+	if v.M != nil {
+		av.M = make(map[string]*dynamodb.AttributeValue, len(v.M))
+		for k, mv := range v.M {
+			av.M[k] = convert(mv)
+		}
+	}
+	return av
+}
+
+func stringValue(v *dynamodbstreams.AttributeValue) string {
+	if v == nil || v.S == nil {
+		return ""
+	}
+	return *v.S
+}
@@ -0,0 +1,194 @@
+package stream
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/a-h/pregel"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodbstreams"
+)
+
+type recordingHandler struct {
+	events []interface{}
+	err    error
+}
+
+func (h *recordingHandler) HandleNode(ctx context.Context, e NodeEvent) error {
+	h.events = append(h.events, e)
+	return h.err
+}
+
+func (h *recordingHandler) HandleEdge(ctx context.Context, e EdgeEvent) error {
+	h.events = append(h.events, e)
+	return h.err
+}
+
+func (h *recordingHandler) HandleData(ctx context.Context, e DataEvent) error {
+	h.events = append(h.events, e)
+	return h.err
+}
+
+func newRecord(eventName, id, rng string, oldImage, newImage map[string]*dynamodbstreams.AttributeValue) *dynamodbstreams.Record {
+	return &dynamodbstreams.Record{
+		EventName: aws.String(eventName),
+		Dynamodb: &dynamodbstreams.StreamRecord{
+			ApproximateCreationDateTime: aws.Time(time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)),
+			Keys: map[string]*dynamodbstreams.AttributeValue{
+				"id":  {S: aws.String(id)},
+				"rng": {S: aws.String(rng)},
+			},
+			OldImage: oldImage,
+			NewImage: newImage,
+		},
+	}
+}
+
+func TestProcess(t *testing.T) {
+	created := time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		records  []*dynamodbstreams.Record
+		expected []interface{}
+	}{
+		{
+			name: "Node and label records are decoded into node events",
+			records: []*dynamodbstreams.Record{
+				newRecord("INSERT", "a", "node", nil, nil),
+				newRecord("REMOVE", "a", "node/label/user", nil, nil),
+			},
+			expected: []interface{}{
+				NodeEvent{Change: Inserted, ID: "a", Time: created},
+				NodeEvent{Change: Removed, ID: "a", Label: "user", Time: created},
+			},
+		},
+		{
+			name: "Soft deleted nodes are reported",
+			records: []*dynamodbstreams.Record{
+				newRecord("MODIFY", "a", "node", nil, map[string]*dynamodbstreams.AttributeValue{
+					"_d": {BOOL: aws.Bool(true)},
+				}),
+			},
+			expected: []interface{}{
+				NodeEvent{Change: Modified, ID: "a", Deleted: true, Time: created},
+			},
+		},
+		{
+			name: "Only the child side of edges is reported",
+			records: []*dynamodbstreams.Record{
+				newRecord("INSERT", "a", "child/owns/b", nil, nil),
+				newRecord("INSERT", "b", "parent/owns/a", nil, nil),
+			},
+			expected: []interface{}{
+				EdgeEvent{Change: Inserted, Parent: "a", Child: "b", Label: "owns", Time: created},
+			},
+		},
+		{
+			name: "Data records include the old and new data, without the record's attributes",
+			records: []*dynamodbstreams.Record{
+				newRecord("MODIFY", "a", "node/data/location",
+					map[string]*dynamodbstreams.AttributeValue{
+						"id":  {S: aws.String("a")},
+						"rng": {S: aws.String("node/data/location")},
+						"t":   {S: aws.String("location")},
+						"lat": {N: aws.String("1")},
+					},
+					map[string]*dynamodbstreams.AttributeValue{
+						"id":  {S: aws.String("a")},
+						"rng": {S: aws.String("node/data/location")},
+						"t":   {S: aws.String("location")},
+						"_u":  {N: aws.String("1546300800")},
+						"lat": {N: aws.String("2")},
+					}),
+				newRecord("REMOVE", "a", "child/b/data/weight", map[string]*dynamodbstreams.AttributeValue{
+					"value": {S: aws.String("heavy")},
+				}, nil),
+			},
+			expected: []interface{}{
+				DataEvent{Change: Modified, NodeID: "a", DataType: "location",
+					Old:  map[string]interface{}{"lat": 1.0},
+					New:  map[string]interface{}{"lat": 2.0},
+					Time: created,
+				},
+				DataEvent{Change: Removed, NodeID: "a", Child: "b", DataType: "weight",
+					Old:  map[string]interface{}{"value": "heavy"},
+					Time: created,
+				},
+			},
+		},
+		{
+			name: "Records which aren't pregel records are skipped",
+			records: []*dynamodbstreams.Record{
+				newRecord("INSERT", "a", "unknown", nil, nil),
+			},
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			h := &recordingHandler{}
+			if err := Process(context.Background(), test.records, h); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(h.events, test.expected) {
+				t.Errorf("expected:\n%#v\ngot:\n%#v", test.expected, h.events)
+			}
+		})
+	}
+}
+
+func TestProcessStopsAtTheFirstError(t *testing.T) {
+	handlerErr := errors.New("handler failed")
+	h := &recordingHandler{err: handlerErr}
+	err := Process(context.Background(), []*dynamodbstreams.Record{
+		newRecord("INSERT", "a", "node", nil, nil),
+		newRecord("INSERT", "b", "node", nil, nil),
+	}, h)
+	if err != handlerErr {
+		t.Errorf("expected err %v, got %v", handlerErr, err)
+	}
+	if len(h.events) != 1 {
+		t.Errorf("expected 1 event to be handled, got %d", len(h.events))
+	}
+	if err = Process(context.Background(), []*dynamodbstreams.Record{{}}, h); err != ErrMissingStreamRecord {
+		t.Errorf("expected err %v, got %v", ErrMissingStreamRecord, err)
+	}
+}
+
+func TestDispatch(t *testing.T) {
+	created := time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)
+	var actual []pregel.ChangeEvent
+	d := pregel.NewDispatcher()
+	d.Subscribe(pregel.ChangeHandlerFunc(func(ctx context.Context, e pregel.ChangeEvent) error {
+		actual = append(actual, e)
+		return nil
+	}))
+	err := Dispatch(context.Background(), []*dynamodbstreams.Record{
+		newRecord("INSERT", "a", "node", nil, nil),
+		newRecord("INSERT", "b", "parent/a", nil, nil),
+		newRecord("MODIFY", "a", "node/data/location",
+			map[string]*dynamodbstreams.AttributeValue{"lat": {N: aws.String("1")}},
+			map[string]*dynamodbstreams.AttributeValue{"lat": {N: aws.String("2")}}),
+		newRecord("REMOVE", "a", "child/b", nil, nil),
+	}, d)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []pregel.ChangeEvent{
+		{Type: pregel.NodeCreated, NodeID: "a", Time: created},
+		{Type: pregel.DataUpdated, NodeID: "a", DataType: "location",
+			Old:  map[string]interface{}{"lat": 1.0},
+			New:  map[string]interface{}{"lat": 2.0},
+			Time: created,
+		},
+		{Type: pregel.EdgeDeleted, NodeID: "a", Child: "b", Time: created},
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected:\n%+v\ngot:\n%+v", expected, actual)
+	}
+}
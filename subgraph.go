@@ -0,0 +1,135 @@
+package pregel
+
+import "errors"
+
+// ErrInvalidDepth is returned when a traversal depth is negative.
+var ErrInvalidDepth = errors.New("invalid depth, depth cannot be negative")
+
+// Direction is the direction of the edges followed when traversing the graph.
+type Direction int
+
+const (
+	// DirectionChildren follows edges from parents to their children.
+	DirectionChildren Direction = iota
+	// DirectionParents follows edges from children to their parents.
+	DirectionParents
+	// DirectionBoth follows edges to both children and parents.
+	DirectionBoth
+)
+
+// TraversalOptions control which edges are followed when traversing the graph.
+type TraversalOptions struct {
+	// Direction of the edges to follow. Defaults to following child edges.
+	Direction Direction
+	// Label restricts the traversal to edges with the label. If empty, all edges are followed.
+	Label string
+	// MaxNodes is the maximum number of nodes to return. If zero, there's no limit.
+	MaxNodes int
+}
+
+// edges returns the edges of the node to follow.
+func (opts TraversalOptions) edges(n Node) (edges []*Edge) {
+	var candidates []*Edge
+	if opts.Direction == DirectionChildren || opts.Direction == DirectionBoth {
+		candidates = append(candidates, n.Children...)
+	}
+	if opts.Direction == DirectionParents || opts.Direction == DirectionBoth {
+		candidates = append(candidates, n.Parents...)
+	}
+	for _, e := range candidates {
+		if opts.Label != "" && e.Label != opts.Label {
+			continue
+		}
+		edges = append(edges, e)
+	}
+	return
+}
+
+// Graph is an in-memory subgraph, made up of the nodes reached from the root node.
+type Graph struct {
+	// Root is the ID of the node that the graph was expanded from.
+	Root string
+	// Nodes in the graph, keyed by ID. Nodes at the edge of the graph keep all of their edges, so
+	// edges may refer to nodes which aren't in the graph.
+	Nodes map[string]Node
+}
+
+// Get returns the node with the ID, if it's in the graph.
+func (g Graph) Get(id string) (n Node, ok bool) {
+	n, ok = g.Nodes[id]
+	return
+}
+
+// Children returns the children of the node which are in the graph.
+func (g Graph) Children(id string) (children []Node) {
+	for _, e := range g.Nodes[id].Children {
+		if c, ok := g.Nodes[e.ID]; ok {
+			children = append(children, c)
+		}
+	}
+	return
+}
+
+// Parents returns the parents of the node which are in the graph.
+func (g Graph) Parents(id string) (parents []Node) {
+	for _, e := range g.Nodes[id].Parents {
+		if p, ok := g.Nodes[e.ID]; ok {
+			parents = append(parents, p)
+		}
+	}
+	return
+}
+
+// GetSubgraph retrieves the node, and the nodes up to depth edges away from it, by expanding the
+// graph breadth first. The nodes at each level are retrieved in parallel with GetMany. A depth of
+// zero returns just the node. If the node doesn't exist, ErrNodeNotFound is returned.
+func (s *Store) GetSubgraph(id string, depth int, opts TraversalOptions) (g Graph, err error) {
+	if id == "" {
+		err = ErrMissingNodeID
+		return
+	}
+	if depth < 0 {
+		err = ErrInvalidDepth
+		return
+	}
+	nodes := make(map[string]Node)
+	visited := map[string]bool{id: true}
+	frontier := []string{id}
+	for level := 0; len(frontier) > 0; level++ {
+		var levelNodes []Node
+		levelNodes, err = s.GetMany(frontier...)
+		if err != nil {
+			return
+		}
+		if level == 0 && len(levelNodes) == 0 {
+			err = ErrNodeNotFound
+			return
+		}
+		var next []string
+		for _, n := range levelNodes {
+			if opts.MaxNodes > 0 && len(nodes) >= opts.MaxNodes {
+				break
+			}
+			nodes[n.ID] = n
+			if level >= depth {
+				continue
+			}
+			for _, e := range opts.edges(n) {
+				if visited[e.ID] {
+					continue
+				}
+				visited[e.ID] = true
+				next = append(next, e.ID)
+			}
+		}
+		if opts.MaxNodes > 0 && len(nodes) >= opts.MaxNodes {
+			break
+		}
+		frontier = next
+	}
+	g = Graph{
+		Root:  id,
+		Nodes: nodes,
+	}
+	return
+}
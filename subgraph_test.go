@@ -0,0 +1,144 @@
+package pregel
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/a-h/pregel/db"
+	"github.com/a-h/pregel/rangefield"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+func TestStoreGetSubgraph(t *testing.T) {
+	// root has children a and b. a has a child a1, which links back to root. b is linked to c with
+	// the label "friend".
+	records := make(map[string][]map[string]*dynamodb.AttributeValue)
+	addEdge := func(parent, label, child string) {
+		records[parent] = append(records[parent], getID(parent, rangefield.Child{Child: child, Label: label}))
+		records[child] = append(records[child], getID(child, rangefield.Parent{Parent: parent, Label: label}))
+	}
+	for _, id := range []string{"root", "a", "b", "a1", "c"} {
+		records[id] = append(records[id], getID(id, rangefield.Node{}))
+	}
+	addEdge("root", "", "a")
+	addEdge("root", "", "b")
+	addEdge("a", "", "a1")
+	addEdge("a1", "", "root")
+	addEdge("b", "friend", "c")
+
+	tests := []struct {
+		name          string
+		id            string
+		depth         int
+		opts          TraversalOptions
+		expectedNodes []string
+		expectedErr   error
+	}{
+		{
+			name:        "Missing node IDs result in an error",
+			expectedErr: ErrMissingNodeID,
+		},
+		{
+			name:        "Negative depths result in an error",
+			id:          "root",
+			depth:       -1,
+			expectedErr: ErrInvalidDepth,
+		},
+		{
+			name:        "Missing nodes result in an error",
+			id:          "missing",
+			expectedErr: ErrNodeNotFound,
+		},
+		{
+			name:          "A depth of zero returns just the node",
+			id:            "root",
+			expectedNodes: []string{"root"},
+		},
+		{
+			name:          "Children are expanded to the depth",
+			id:            "root",
+			depth:         1,
+			expectedNodes: []string{"a", "b", "root"},
+		},
+		{
+			name:          "Cycles are only visited once",
+			id:            "root",
+			depth:         10,
+			expectedNodes: []string{"a", "a1", "b", "c", "root"},
+		},
+		{
+			name:          "Parents can be followed",
+			id:            "a1",
+			depth:         1,
+			opts:          TraversalOptions{Direction: DirectionParents},
+			expectedNodes: []string{"a", "a1"},
+		},
+		{
+			name:          "Children and parents can be followed",
+			id:            "a1",
+			depth:         1,
+			opts:          TraversalOptions{Direction: DirectionBoth},
+			expectedNodes: []string{"a", "a1", "root"},
+		},
+		{
+			name:          "Only edges with the label are followed",
+			id:            "b",
+			depth:         1,
+			opts:          TraversalOptions{Direction: DirectionBoth, Label: "friend"},
+			expectedNodes: []string{"b", "c"},
+		},
+		{
+			name:          "The number of nodes can be limited",
+			id:            "root",
+			depth:         10,
+			opts:          TraversalOptions{MaxNodes: 2},
+			expectedNodes: []string{"a", "root"},
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			client := newdynamoDBClient()
+			client.queryByIDer = func(idField, idValue string) ([]map[string]*dynamodb.AttributeValue, db.ConsumedCapacity, error) {
+				return records[idValue], db.ConsumedCapacity{}, nil
+			}
+			s := NewStoreWithClient(client)
+			g, err := s.GetSubgraph(test.id, test.depth, test.opts)
+			if err != test.expectedErr {
+				t.Errorf("expected err %v, got %v", test.expectedErr, err)
+			}
+			var actualNodes []string
+			for id := range g.Nodes {
+				actualNodes = append(actualNodes, id)
+			}
+			sort.Strings(actualNodes)
+			if !reflect.DeepEqual(actualNodes, test.expectedNodes) {
+				t.Errorf("expected nodes %v, got %v", test.expectedNodes, actualNodes)
+			}
+		})
+	}
+	t.Run("The graph can be navigated", func(t *testing.T) {
+		client := newdynamoDBClient()
+		client.queryByIDer = func(idField, idValue string) ([]map[string]*dynamodb.AttributeValue, db.ConsumedCapacity, error) {
+			return records[idValue], db.ConsumedCapacity{}, nil
+		}
+		s := NewStoreWithClient(client)
+		g, err := s.GetSubgraph("root", 1, TraversalOptions{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var children []string
+		for _, c := range g.Children(g.Root) {
+			children = append(children, c.ID)
+		}
+		if !reflect.DeepEqual(children, []string{"a", "b"}) {
+			t.Errorf("expected children [a b], got %v", children)
+		}
+		if parents := g.Parents("a"); len(parents) != 1 || parents[0].ID != "root" {
+			t.Errorf("expected a's parent to be root, got %+v", parents)
+		}
+	})
+}
@@ -0,0 +1,162 @@
+package pregel
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CycleError is returned by TopologicalSort when the graph contains a cycle.
+type CycleError struct {
+	// Cycle is the path of node IDs which make up the cycle. The first and last IDs are the same.
+	Cycle []string
+}
+
+func (err CycleError) Error() string {
+	return fmt.Sprintf("graph contains a cycle: %s", strings.Join(err.Cycle, " -> "))
+}
+
+// TopologicalSort returns the IDs of the root nodes and all of their descendants, ordered so that
+// every node comes before its children. Where the graph models dependencies as edges from a node to
+// the nodes it depends on, reverse the result to get the order in which to process them. If the
+// graph contains a cycle, a CycleError is returned.
+func (s *Store) TopologicalSort(rootIDs ...string) (ids []string, err error) {
+	ts := topologicalSort{
+		store: s,
+		state: make(map[string]visitState),
+	}
+	for _, id := range rootIDs {
+		if id == "" {
+			err = ErrMissingNodeID
+			return
+		}
+		if err = ts.visit(id); err != nil {
+			return
+		}
+	}
+	// Nodes are added after their children, so reverse them.
+	ids = make([]string, len(ts.order))
+	for i, id := range ts.order {
+		ids[len(ts.order)-1-i] = id
+	}
+	return
+}
+
+type visitState int
+
+const (
+	sortUnvisited visitState = iota
+	sortVisiting
+	sortVisited
+)
+
+type topologicalSort struct {
+	store *Store
+	state map[string]visitState
+	// path is the list of nodes currently being visited, used to report cycles.
+	path  []string
+	order []string
+}
+
+func (ts *topologicalSort) visit(id string) (err error) {
+	switch ts.state[id] {
+	case sortVisited:
+		return
+	case sortVisiting:
+		return ts.cycle(id)
+	}
+	ts.state[id] = sortVisiting
+	ts.path = append(ts.path, id)
+	children, _, err := ts.store.GetChildren(id, 0, "")
+	if err != nil {
+		return
+	}
+	for _, c := range children {
+		if err = ts.visit(c.ID); err != nil {
+			return
+		}
+	}
+	ts.path = ts.path[:len(ts.path)-1]
+	ts.state[id] = sortVisited
+	ts.order = append(ts.order, id)
+	return
+}
+
+// cycle returns a CycleError for the path from the first visit of the node back to itself.
+func (ts *topologicalSort) cycle(id string) error {
+	for i := len(ts.path) - 1; i >= 0; i-- {
+		if ts.path[i] == id {
+			cycle := append([]string{}, ts.path[i:]...)
+			return CycleError{Cycle: append(cycle, id)}
+		}
+	}
+	return CycleError{Cycle: []string{id, id}}
+}
+
+// HasCycle returns true if a cycle can be reached by following child edges from the start node.
+func (s *Store) HasCycle(start string) (hasCycle bool, err error) {
+	if start == "" {
+		err = ErrMissingNodeID
+		return
+	}
+	ts := topologicalSort{
+		store: s,
+		state: make(map[string]visitState),
+	}
+	err = ts.visit(start)
+	if _, isCycle := err.(CycleError); isCycle {
+		return true, nil
+	}
+	return
+}
+
+// DefaultAcyclicCheckDepth is the number of levels of ancestors checked when the Store's
+// EnforceAcyclic is set, and AcyclicCheckDepth isn't.
+const DefaultAcyclicCheckDepth = 10
+
+// checkAcyclic returns a CycleError if adding the edges to the parent would create a cycle, i.e. if
+// any of the children is the parent, or one of its ancestors.
+func (s *Store) checkAcyclic(parent string, edges []*Edge) (err error) {
+	if !s.EnforceAcyclic {
+		return
+	}
+	maxDepth := s.AcyclicCheckDepth
+	if maxDepth <= 0 {
+		maxDepth = DefaultAcyclicCheckDepth
+	}
+	children := make(map[string]bool, len(edges))
+	for _, e := range edges {
+		if e.ID == parent {
+			return CycleError{Cycle: []string{parent, parent}}
+		}
+		children[e.ID] = true
+	}
+	// descendant maps each ancestor to the node it was reached from, so that the cycle can be reported.
+	descendant := map[string]string{parent: ""}
+	frontier := []string{parent}
+	for depth := 0; depth < maxDepth && len(frontier) > 0; depth++ {
+		var next []string
+		for _, id := range frontier {
+			var n Node
+			n, _, err = s.GetWithOptions(id, GetOptions{Parents: true})
+			if err != nil {
+				return
+			}
+			for _, p := range n.Parents {
+				if _, seen := descendant[p.ID]; seen {
+					continue
+				}
+				descendant[p.ID] = id
+				if children[p.ID] {
+					cycle := []string{parent}
+					for current := p.ID; current != ""; current = descendant[current] {
+						cycle = append(cycle, current)
+					}
+					return CycleError{Cycle: cycle}
+				}
+				next = append(next, p.ID)
+			}
+		}
+		frontier = next
+	}
+	return
+}
@@ -0,0 +1,213 @@
+package pregel
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/a-h/pregel/db"
+	"github.com/a-h/pregel/rangefield"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+func TestStoreTopologicalSort(t *testing.T) {
+	tests := []struct {
+		name        string
+		graph       map[string][]string
+		roots       []string
+		expected    []string
+		expectedErr error
+	}{
+		{
+			name:        "Missing node IDs result in an error",
+			roots:       []string{""},
+			expectedErr: ErrMissingNodeID,
+		},
+		{
+			name:     "Nodes without children are returned on their own",
+			roots:    []string{"a"},
+			expected: []string{"a"},
+		},
+		{
+			name: "Nodes come before their children",
+			graph: map[string][]string{
+				"app":    {"lib", "log"},
+				"lib":    {"log"},
+				"log":    {"stdlib"},
+				"unused": {"stdlib"},
+			},
+			roots:    []string{"app"},
+			expected: []string{"app", "lib", "log", "stdlib"},
+		},
+		{
+			name: "Multiple roots are combined",
+			graph: map[string][]string{
+				"a": {"c"},
+				"b": {"c"},
+			},
+			roots:    []string{"a", "b"},
+			expected: []string{"b", "a", "c"},
+		},
+		{
+			name: "Cycles result in an error",
+			graph: map[string][]string{
+				"a": {"b"},
+				"b": {"c"},
+				"c": {"d", "b"},
+			},
+			roots:       []string{"a"},
+			expectedErr: CycleError{Cycle: []string{"b", "c", "b"}},
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			client := newdynamoDBClient()
+			client.queryPager = func(idField, idValue, rangeField, rangePrefix string, limit int64, startKey map[string]*dynamodb.AttributeValue) (items []map[string]*dynamodb.AttributeValue, lastEvaluatedKey map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error) {
+				for _, child := range test.graph[idValue] {
+					itm := getID(idValue, rangefield.Child{Child: child})
+					if strings.HasPrefix(*itm[fieldRange].S, rangePrefix) {
+						items = append(items, itm)
+					}
+				}
+				return
+			}
+			s := NewStoreWithClient(client)
+			actual, err := s.TopologicalSort(test.roots...)
+			if !reflect.DeepEqual(err, test.expectedErr) {
+				t.Fatalf("expected err %v, got %v", test.expectedErr, err)
+			}
+			if !reflect.DeepEqual(actual, test.expected) {
+				t.Errorf("expected %v, got %v", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestStoreHasCycle(t *testing.T) {
+	graph := map[string][]string{
+		"a": {"b", "c"},
+		"b": {"c"},
+		"x": {"y"},
+		"y": {"x"},
+	}
+	tests := []struct {
+		name        string
+		start       string
+		expected    bool
+		expectedErr error
+	}{
+		{
+			name:        "Missing node IDs result in an error",
+			expectedErr: ErrMissingNodeID,
+		},
+		{
+			name:  "Acyclic graphs don't have a cycle",
+			start: "a",
+		},
+		{
+			name:     "Cycles are detected",
+			start:    "x",
+			expected: true,
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			client := newdynamoDBClient()
+			client.queryPager = func(idField, idValue, rangeField, rangePrefix string, limit int64, startKey map[string]*dynamodb.AttributeValue) (items []map[string]*dynamodb.AttributeValue, lastEvaluatedKey map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error) {
+				for _, child := range graph[idValue] {
+					items = append(items, getID(idValue, rangefield.Child{Child: child}))
+				}
+				return
+			}
+			s := NewStoreWithClient(client)
+			actual, err := s.HasCycle(test.start)
+			if err != test.expectedErr {
+				t.Fatalf("expected err %v, got %v", test.expectedErr, err)
+			}
+			if actual != test.expected {
+				t.Errorf("expected %v, got %v", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestStoreEnforceAcyclic(t *testing.T) {
+	// a -> b -> c -> d
+	parents := map[string][]string{
+		"b": {"a"},
+		"c": {"b"},
+		"d": {"c"},
+	}
+	tests := []struct {
+		name        string
+		depth       int
+		parent      string
+		child       string
+		expectedErr error
+	}{
+		{
+			name:   "Edges which don't create a cycle are added",
+			parent: "d",
+			child:  "e",
+		},
+		{
+			name:        "Edges to the parent itself are refused",
+			parent:      "d",
+			child:       "d",
+			expectedErr: CycleError{Cycle: []string{"d", "d"}},
+		},
+		{
+			name:        "Edges to an ancestor are refused",
+			parent:      "d",
+			child:       "a",
+			expectedErr: CycleError{Cycle: []string{"d", "a", "b", "c", "d"}},
+		},
+		{
+			name:   "Ancestors beyond the check depth aren't checked",
+			depth:  2,
+			parent: "d",
+			child:  "a",
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			client := newdynamoDBClient()
+			client.getItemer = func(key map[string]*dynamodb.AttributeValue) (map[string]*dynamodb.AttributeValue, db.ConsumedCapacity, error) {
+				return key, db.ConsumedCapacity{}, nil
+			}
+			client.queryPager = func(idField, idValue, rangeField, rangePrefix string, limit int64, startKey map[string]*dynamodb.AttributeValue) (items []map[string]*dynamodb.AttributeValue, lastEvaluatedKey map[string]*dynamodb.AttributeValue, cc db.ConsumedCapacity, err error) {
+				if rangePrefix != rangefield.ParentPrefix {
+					t.Errorf("expected only parents to be queried, got prefix %q", rangePrefix)
+				}
+				for _, parent := range parents[idValue] {
+					items = append(items, getID(idValue, rangefield.Parent{Parent: parent}))
+				}
+				return
+			}
+			var putCalled bool
+			client.batchPutter = func(items []map[string]*dynamodb.AttributeValue) (db.ConsumedCapacity, error) {
+				putCalled = true
+				return db.ConsumedCapacity{}, nil
+			}
+			s := NewStoreWithClient(client)
+			s.EnforceAcyclic = true
+			s.AcyclicCheckDepth = test.depth
+			err := s.PutEdges(test.parent, NewEdge(test.child))
+			if !reflect.DeepEqual(err, test.expectedErr) {
+				t.Fatalf("expected err %v, got %v", test.expectedErr, err)
+			}
+			if putCalled != (test.expectedErr == nil) {
+				t.Errorf("expected put %v, got %v", test.expectedErr == nil, putCalled)
+			}
+		})
+	}
+}
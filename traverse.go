@@ -0,0 +1,109 @@
+package pregel
+
+// TraversalOrder is the order in which Traverse visits nodes.
+type TraversalOrder int
+
+const (
+	// BreadthFirst visits all of a node's children before visiting their children.
+	BreadthFirst TraversalOrder = iota
+	// DepthFirst visits all of a child's descendants before visiting its siblings.
+	DepthFirst
+)
+
+// Iterator walks through nodes one at a time.
+type Iterator interface {
+	// Next moves to the next node, returning false when there are no more nodes, or an error occurred.
+	Next() bool
+	// Node returns the current node.
+	Node() Node
+	// Err returns the error which stopped the iteration, if any.
+	Err() error
+}
+
+// Traverse returns an Iterator which walks the graph by following child edges from the start node.
+// Nodes are retrieved one at a time as the iterator moves, so that large graphs can be walked
+// incrementally, and the walk can be stopped at any point. Each node is visited once, even if it's
+// reachable by multiple paths. Edges to nodes which don't exist are skipped.
+func (s *Store) Traverse(start string, order TraversalOrder) Iterator {
+	it := &traversal{
+		store:   s,
+		order:   order,
+		pending: []string{start},
+		visited: make(map[string]bool),
+	}
+	if start == "" {
+		it.err = ErrMissingNodeID
+	}
+	return it
+}
+
+type traversal struct {
+	store *Store
+	order TraversalOrder
+	// pending is a queue of node IDs for breadth first traversals, and a stack for depth first.
+	pending []string
+	visited map[string]bool
+	current Node
+	err     error
+}
+
+func (it *traversal) Next() bool {
+	if it.err != nil {
+		return false
+	}
+	for len(it.pending) > 0 {
+		id := it.pop()
+		if it.visited[id] {
+			continue
+		}
+		n, ok, err := it.store.Get(id)
+		if err != nil {
+			it.err = err
+			return false
+		}
+		it.visited[id] = true
+		if !ok {
+			continue
+		}
+		it.push(n.Children)
+		it.current = n
+		return true
+	}
+	return false
+}
+
+func (it *traversal) pop() (id string) {
+	if it.order == DepthFirst {
+		id = it.pending[len(it.pending)-1]
+		it.pending = it.pending[:len(it.pending)-1]
+		return
+	}
+	id = it.pending[0]
+	it.pending = it.pending[1:]
+	return
+}
+
+func (it *traversal) push(edges []*Edge) {
+	if it.order == DepthFirst {
+		// Push in reverse, so that the first child is visited first.
+		for i := len(edges) - 1; i >= 0; i-- {
+			if !it.visited[edges[i].ID] {
+				it.pending = append(it.pending, edges[i].ID)
+			}
+		}
+		return
+	}
+	for _, e := range edges {
+		if !it.visited[e.ID] {
+			it.pending = append(it.pending, e.ID)
+		}
+	}
+}
+
+func (it *traversal) Node() Node {
+	return it.current
+}
+
+func (it *traversal) Err() error {
+	return it.err
+}
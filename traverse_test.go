@@ -0,0 +1,100 @@
+package pregel
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/a-h/pregel/db"
+	"github.com/a-h/pregel/rangefield"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+func TestStoreTraverse(t *testing.T) {
+	// root has children a and b. a has children a1 and a2, and b has a child b1. a2 links back to
+	// root, and b1 links to a missing node.
+	graph := map[string][]string{
+		"root": {"a", "b"},
+		"a":    {"a1", "a2"},
+		"a2":   {"root"},
+		"b":    {"b1"},
+		"b1":   {"missing"},
+	}
+	records := make(map[string][]map[string]*dynamodb.AttributeValue)
+	for _, id := range []string{"root", "a", "b", "a1", "a2", "b1"} {
+		records[id] = append(records[id], getID(id, rangefield.Node{}))
+	}
+	for parent, children := range graph {
+		for _, child := range children {
+			records[parent] = append(records[parent], getID(parent, rangefield.Child{Child: child}))
+		}
+	}
+
+	tests := []struct {
+		name          string
+		start         string
+		order         TraversalOrder
+		stopAfter     int
+		queryErr      error
+		expectedNodes []string
+		expectedErr   error
+	}{
+		{
+			name:        "Missing node IDs result in an error",
+			expectedErr: ErrMissingNodeID,
+		},
+		{
+			name:  "Missing start nodes return no nodes",
+			start: "missing",
+		},
+		{
+			name:          "Breadth first traversals visit each level in turn",
+			start:         "root",
+			order:         BreadthFirst,
+			expectedNodes: []string{"root", "a", "b", "a1", "a2", "b1"},
+		},
+		{
+			name:          "Depth first traversals visit descendants before siblings",
+			start:         "root",
+			order:         DepthFirst,
+			expectedNodes: []string{"root", "a", "a1", "a2", "b", "b1"},
+		},
+		{
+			name:          "Traversals can be stopped early",
+			start:         "root",
+			stopAfter:     2,
+			expectedNodes: []string{"root", "a"},
+		},
+		{
+			name:        "Database errors are returned",
+			start:       "root",
+			queryErr:    errTestDatabaseFailure,
+			expectedErr: errTestDatabaseFailure,
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			client := newdynamoDBClient()
+			client.queryByIDer = func(idField, idValue string) ([]map[string]*dynamodb.AttributeValue, db.ConsumedCapacity, error) {
+				return records[idValue], db.ConsumedCapacity{}, test.queryErr
+			}
+			s := NewStoreWithClient(client)
+			it := s.Traverse(test.start, test.order)
+			var actualNodes []string
+			for it.Next() {
+				actualNodes = append(actualNodes, it.Node().ID)
+				if len(actualNodes) == test.stopAfter {
+					break
+				}
+			}
+			if err := it.Err(); err != test.expectedErr {
+				t.Errorf("expected err %v, got %v", test.expectedErr, err)
+			}
+			if !reflect.DeepEqual(actualNodes, test.expectedNodes) {
+				t.Errorf("expected nodes %v, got %v", test.expectedNodes, actualNodes)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package pregel
+
+import "fmt"
+
+// Validator can be implemented by data types to check their values before they're written.
+type Validator interface {
+	Validate() error
+}
+
+// RegisterValidator registers a function which checks data of the data type before it's written.
+// Data types which implement Validator are checked without being registered.
+func (s *Store) RegisterValidator(dataType string, validate func(v interface{}) error) {
+	s.Validators[dataType] = validate
+}
+
+// ValidationError is returned when data fails validation, and nothing is written.
+type ValidationError struct {
+	// ID of the node, or of the parent if the data belongs to an edge.
+	ID string
+	// EdgeID is the ID of the child if the data belongs to an edge, or empty if the data belongs to
+	// the node.
+	EdgeID   string
+	DataType string
+	Err      error
+}
+
+func (err ValidationError) Error() string {
+	if err.EdgeID != "" {
+		return fmt.Sprintf("invalid '%s' data on edge from '%s' to '%s': %v", err.DataType, err.ID, err.EdgeID, err.Err)
+	}
+	return fmt.Sprintf("invalid '%s' data on node '%s': %v", err.DataType, err.ID, err.Err)
+}
+
+// Unwrap returns the error returned by the validator.
+func (err ValidationError) Unwrap() error {
+	return err.Err
+}
+
+// validateNode validates the data of the node and its edges.
+func (s *Store) validateNode(n Node) (err error) {
+	if err = s.validateData(n.ID, "", n.Data); err != nil {
+		return
+	}
+	for _, e := range n.Children {
+		if err = s.validateData(n.ID, e.ID, e.Data); err != nil {
+			return
+		}
+	}
+	for _, e := range n.Parents {
+		if err = s.validateData(e.ID, n.ID, e.Data); err != nil {
+			return
+		}
+	}
+	return
+}
+
+func (s *Store) validateData(id, edgeID string, d Data) error {
+	for dataType, v := range d {
+		var err error
+		if validate, ok := s.Validators[dataType]; ok {
+			err = validate(v)
+		} else if vv, ok := v.(Validator); ok {
+			err = vv.Validate()
+		}
+		if err != nil {
+			return ValidationError{ID: id, EdgeID: edgeID, DataType: dataType, Err: err}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package pregel
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/a-h/pregel/db"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+type validatedTestData struct {
+	Name string `json:"name"`
+}
+
+var errTestMissingName = errors.New("name is required")
+
+func (d validatedTestData) Validate() error {
+	if d.Name == "" {
+		return errTestMissingName
+	}
+	return nil
+}
+
+func TestStoreValidation(t *testing.T) {
+	errTestNegative := errors.New("must not be negative")
+	tests := []struct {
+		name        string
+		put         func(s *Store) error
+		expectedErr error
+	}{
+		{
+			name: "Valid data is written",
+			put: func(s *Store) error {
+				return s.Put(NewNode("nodeA").WithData(validatedTestData{Name: "a"}))
+			},
+		},
+		{
+			name: "Data which implements Validator is validated by Put",
+			put: func(s *Store) error {
+				return s.Put(NewNode("nodeA").WithData(validatedTestData{}))
+			},
+			expectedErr: ValidationError{ID: "nodeA", DataType: "validatedTestData", Err: errTestMissingName},
+		},
+		{
+			name: "Node data is validated by PutNodeData",
+			put: func(s *Store) error {
+				return s.PutNodeData("nodeA", NewData(validatedTestData{}))
+			},
+			expectedErr: ValidationError{ID: "nodeA", DataType: "validatedTestData", Err: errTestMissingName},
+		},
+		{
+			name: "Edge data is validated by registered validators in PutEdgeData",
+			put: func(s *Store) error {
+				return s.PutEdgeData("nodeA", "nodeB", NewData(testEdgeData{EdgeDataField: -1}))
+			},
+			expectedErr: ValidationError{ID: "nodeA", EdgeID: "nodeB", DataType: "testEdgeData", Err: errTestNegative},
+		},
+		{
+			name: "Parent edge data is validated",
+			put: func(s *Store) error {
+				return s.Put(NewNode("nodeB").WithParents(NewEdge("nodeA").WithData(testEdgeData{EdgeDataField: -1})))
+			},
+			expectedErr: ValidationError{ID: "nodeA", EdgeID: "nodeB", DataType: "testEdgeData", Err: errTestNegative},
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			client := newdynamoDBClient()
+			var written bool
+			client.batchPutter = func(items []map[string]*dynamodb.AttributeValue) (db.ConsumedCapacity, error) {
+				written = true
+				return db.ConsumedCapacity{}, nil
+			}
+			s := NewStoreWithClient(client)
+			s.RegisterValidator("testEdgeData", func(v interface{}) error {
+				if v.(testEdgeData).EdgeDataField < 0 {
+					return errTestNegative
+				}
+				return nil
+			})
+			err := test.put(s)
+			if err != test.expectedErr {
+				t.Errorf("expected err %v, got %v", test.expectedErr, err)
+			}
+			if written != (test.expectedErr == nil) {
+				t.Errorf("expected data to be written only if it's valid, but written was %v", written)
+			}
+		})
+	}
+}